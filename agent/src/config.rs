@@ -14,6 +14,17 @@ pub struct AgentConfig {
     pub logrotate_dir: PathBuf,
     pub ssl_storage_dir: PathBuf,
     pub proxy_conf_dir: PathBuf,
+    pub php_fpm_root: PathBuf,
+    pub runtimes_dir: PathBuf,
+    pub integrity_dir: PathBuf, // Root-only file hash baselines per app
+    pub change_ledger_path: PathBuf, // Outbox change IDs already applied
+    pub system_defaults_path: PathBuf, // SystemProfile ceilings last pushed by the Brain
+    pub artifact_dir: PathBuf, // Root-only release tarballs for rebuild-free redeploys
+
+    // 🔄 Brain Lifecycle (self-update)
+    pub update_staging_dir: PathBuf,
+    pub brain_binary_path: PathBuf,
+    pub brain_health_addr: String,
 }
 
 impl AgentConfig {
@@ -60,6 +71,40 @@ impl AgentConfig {
             proxy_conf_dir: PathBuf::from(
                 env::var("KARI_PROXY_CONF_DIR").unwrap_or_else(|_| "/etc/nginx/sites-available".to_string())
             ),
+
+            php_fpm_root: PathBuf::from(
+                env::var("KARI_PHP_FPM_ROOT").unwrap_or_else(|_| "/etc/php".to_string())
+            ),
+
+            runtimes_dir: PathBuf::from(
+                env::var("KARI_RUNTIMES_DIR").unwrap_or_else(|_| "/opt/kari/runtimes".to_string())
+            ),
+
+            integrity_dir: PathBuf::from(
+                env::var("KARI_INTEGRITY_DIR").unwrap_or_else(|_| "/var/lib/kari/integrity".to_string())
+            ),
+
+            change_ledger_path: PathBuf::from(
+                env::var("KARI_CHANGE_LEDGER").unwrap_or_else(|_| "/var/lib/kari/applied_changes".to_string())
+            ),
+
+            system_defaults_path: PathBuf::from(
+                env::var("KARI_SYSTEM_DEFAULTS").unwrap_or_else(|_| "/var/lib/kari/system_defaults.json".to_string())
+            ),
+
+            artifact_dir: PathBuf::from(
+                env::var("KARI_ARTIFACT_DIR").unwrap_or_else(|_| "/var/lib/kari/artifacts".to_string())
+            ),
+
+            update_staging_dir: PathBuf::from(
+                env::var("KARI_UPDATE_STAGING_DIR").unwrap_or_else(|_| "/var/lib/kari/updates".to_string())
+            ),
+
+            brain_binary_path: PathBuf::from(
+                env::var("KARI_API_BINARY").unwrap_or_else(|_| "/opt/kari/bin/kari-api".to_string())
+            ),
+
+            brain_health_addr: env::var("KARI_API_HEALTH_ADDR").unwrap_or_else(|_| "127.0.0.1:8080".to_string()),
         }
     }
 }
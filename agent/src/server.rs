@@ -6,12 +6,21 @@ use tokio::sync::mpsc;
 use tokio_stream::wrappers::ReceiverStream;
 use tonic::{Request, Response, Status};
 use tracing::{info, warn, error};
-use zeroize::Zeroizing;
+use zeroize::{Zeroize, Zeroizing};
 
 use crate::config::AgentConfig;
+use crate::sys::artifact::{ArtifactStore, TarArtifactStore};
 use crate::sys::build::{BuildManager, SystemBuildManager};
+use crate::sys::defaults::{DefaultsStore, SystemDefaults};
 use crate::sys::git::{GitManager, SystemGitManager};
+use crate::sys::image::{ImageManager, PodmanImageManager, RegistryLogin};
 use crate::sys::jail::{JailManager, LinuxJailManager};
+use crate::sys::ledger::{self, ChangeLedger};
+use crate::sys::sbom::{SbomGenerator, SyftSbomGenerator};
+use crate::sys::scanner::{FileScanner, IntegrityScanner};
+use crate::sys::sealing;
+use crate::sys::access_log;
+use crate::sys::php_fpm::{LinuxPhpFpmManager, PhpFpmManager, PhpPoolConfig};
 use crate::sys::systemd::{LinuxSystemdManager, ServiceManager, ServiceConfig};
 use crate::sys::traits::{
     ProxyManager, FirewallManager, SslEngine, JobScheduler,
@@ -29,10 +38,55 @@ use kari_agent::system_agent_server::SystemAgent;
 use kari_agent::{
     AgentResponse, DeployRequest, DeleteRequest, TeardownRequest, PackageRequest, Empty, SystemStatus,
     ServiceRequest, LogChunk, ProvisionJailRequest, FileWriteRequest,
-    SslPayload, FirewallPolicy, JobIntent,
+    SslPayload, FirewallPolicy, JobIntent, BrainUpdateRequest, ImagePullRequest,
+    ProcessSpec, ProcessStatusRequest, ProcessStatusResponse, ProcessState, RestartRequest,
+    FileScanRequest, FileScanResponse, FileFinding, SecurityHeadersRequest,
+    AccessLogRequest, AccessLogResponse, AccessLogBucket, BandwidthLimitRequest,
+    ResourceInventory, RemoveResourceRequest, ResourceKind, ArtifactInfo, DeleteArtifactsRequest,
+    SbomReport, SbomComponent, HostCapabilities, SystemDefaults as ProtoSystemDefaults,
+    SecretBundle, SealedSecrets,
 };
 
 const ALLOWED_PKG_COMMANDS: &[&str] = &["apt-get", "apt", "dnf", "yum", "zypper"];
+const BRAIN_SERVICE_NAME: &str = "kari-api";
+
+// 🤝 Brain<->Muscle API revisions this build speaks (reported via GetSystemStatus).
+// Bump PROTOCOL_VERSION when adding RPCs; raise MIN only when dropping old behavior.
+//   1: baseline RPC surface
+//   2: ApplyBrainUpdate
+//   3: PHP-FPM runtime (DeployRequest.runtime / php_max_children)
+//   4: Runtime version pinning (DeployRequest.runtime_version toolchains)
+//   5: PullImage with Brain-injected registry credentials
+//   6: Multi-process apps (DeployRequest.processes, GetProcessStatus)
+//   7: Horizontal web instances (DeployRequest.instances, per-instance health)
+//   8: RestartApp (hard or graceful, connection-draining restarts)
+//   9: ScanAppFiles (integrity baselines, ClamAV)
+//  10: ApplySecurityHeaders (per-domain response headers)
+//  11: CollectAccessLogs (per-domain access analytics)
+//  12: SetBandwidthLimit, AccessLogBucket.bytes_received (transfer quotas)
+//  13: ProcessState.cpu_usage_nsec (usage metering)
+//  14: ListManagedResources, RemoveManagedResource (drift reconciliation)
+//  15: x-kari-change-id metadata (outbox redeliveries acknowledged, not re-applied)
+//  16: DeployRequest.build_env (build-only environment, never in the units)
+//  17: Build artifacts (DeployRequest.artifact_id / reuse_artifact, DeleteArtifacts)
+//  18: LogChunk.sbom (dependency catalog of each built release)
+//  19: GetCapabilities (container runtime, PHP versions, quotas, firewall)
+//  20: ApplySystemDefaults (SystemProfile resource ceilings, default firewall policy)
+//  21: SealSecrets, UnsealSecrets (machine-bound env file secrets)
+const PROTOCOL_VERSION: u32 = 21;
+const MIN_PROTOCOL_VERSION: u32 = 1;
+const DEFAULT_BRAIN_HEALTH_TIMEOUT_SECS: u32 = 60;
+
+// Runtimes whose builds get a pinned toolchain from `runtimes_dir`.
+// (php is served by its FPM pool; static has no toolchain.)
+const TOOLCHAIN_RUNTIMES: &[&str] = &["nodejs", "python", "go", "ruby"];
+
+// Mirrors the Brain's process-type validator.
+const MAX_PROCESSES: usize = 10;
+const WEB_PROCESS: &str = "web";
+const MAX_INSTANCES: u32 = 16;
+// How long a restarted web instance may take to listen again.
+const INSTANCE_HEALTH_TIMEOUT_SECS: u64 = 30;
 
 // ==============================================================================
 // 🛡️ SOLID: KariAgentService is the single gRPC boundary.
@@ -46,9 +100,16 @@ pub struct KariAgentService {
     git_mgr: Arc<dyn GitManager>,
     build_mgr: Arc<dyn BuildManager>,
     proxy_mgr: Arc<dyn ProxyManager>,
+    php_mgr: Arc<dyn PhpFpmManager>,
+    image_mgr: Arc<dyn ImageManager>,
+    scanner: Arc<dyn FileScanner>,
+    artifacts: Arc<dyn ArtifactStore>,
+    sbom: Arc<dyn SbomGenerator>,
     firewall_mgr: Arc<dyn FirewallManager>,
     ssl_engine: Arc<dyn SslEngine>,
     job_scheduler: Arc<dyn JobScheduler>,
+    ledger: ChangeLedger,
+    defaults: DefaultsStore,
 }
 
 impl KariAgentService {
@@ -65,13 +126,44 @@ impl KariAgentService {
             git_mgr: Arc::new(SystemGitManager),
             build_mgr: Arc::new(SystemBuildManager),
             proxy_mgr,
+            php_mgr: Arc::new(LinuxPhpFpmManager::new(config.php_fpm_root.clone())),
+            image_mgr: Arc::new(PodmanImageManager),
+            scanner: Arc::new(IntegrityScanner::new(config.integrity_dir.clone())),
+            artifacts: Arc::new(TarArtifactStore::new(config.artifact_dir.clone())),
+            sbom: Arc::new(SyftSbomGenerator),
             firewall_mgr,
             ssl_engine,
             job_scheduler,
+            ledger: ChangeLedger::open(config.change_ledger_path.clone()),
+            defaults: DefaultsStore::open(config.system_defaults_path.clone()),
             config,
         }
     }
 
+    /// 📬 Outbox redelivery: a change the ledger already holds is acknowledged, not re-applied
+    async fn replayed_change(&self, change_id: Option<&str>) -> Option<Response<AgentResponse>> {
+        let id = change_id?;
+        if !self.ledger.contains(id).await {
+            return None;
+        }
+        info!("📬 Change {} already applied; acknowledging redelivery", id);
+        Some(Response::new(AgentResponse {
+            success: true,
+            exit_code: 0,
+            stdout: "Change already applied".to_string(),
+            stderr: String::new(),
+            error_message: String::new(),
+        }))
+    }
+
+    async fn record_change(&self, change_id: Option<String>) {
+        if let Some(id) = change_id {
+            if let Err(e) = self.ledger.record(&id).await {
+                warn!("⚠️ Failed to record applied change {}: {}", id, e);
+            }
+        }
+    }
+
     /// 🛡️ Zero-Trust: Strictly prevents directory traversal
     fn secure_join(&self, base: &Path, unsafe_suffix: &str) -> Result<std::path::PathBuf, Status> {
         if unsafe_suffix.contains("..") || unsafe_suffix.contains('/') || unsafe_suffix.contains('\\') {
@@ -80,6 +172,54 @@ impl KariAgentService {
         Ok(base.join(unsafe_suffix))
     }
 
+    /// Resolves `{runtimes_dir}/{runtime}/{version}/bin` for a pinned build.
+    /// None means the build uses the host toolchain (no runtime or no version).
+    fn resolve_toolchain(&self, runtime: &str, version: &str) -> Result<Option<std::path::PathBuf>, Status> {
+        if version.is_empty() || !TOOLCHAIN_RUNTIMES.contains(&runtime) {
+            return Ok(None);
+        }
+        // 🛡️ Zero-Trust: The version becomes a path segment; digits and dots only
+        if !version.chars().all(|c| c.is_ascii_digit() || c == '.') || version.starts_with('.') {
+            return Err(Status::invalid_argument(format!("Zero-Trust: Invalid runtime version: '{}'", version)));
+        }
+
+        let bin_dir = self.config.runtimes_dir.join(runtime).join(version).join("bin");
+        if !bin_dir.is_dir() {
+            return Err(Status::failed_precondition(format!(
+                "[SLA ERROR] Runtime {} {} is not installed on this host", runtime, version
+            )));
+        }
+        Ok(Some(bin_dir))
+    }
+
+    /// 🛡️ Zero-Trust: Process specs become unit names and ExecStart lines
+    fn validate_processes(processes: &[ProcessSpec], runtime: &str) -> Result<(), Status> {
+        if processes.len() > MAX_PROCESSES {
+            return Err(Status::invalid_argument(format!("At most {} processes per app", MAX_PROCESSES)));
+        }
+        let mut seen = std::collections::HashSet::new();
+        for p in processes {
+            let valid_name = !p.name.is_empty() && p.name.len() <= 30
+                && p.name.starts_with(|c: char| c.is_ascii_lowercase())
+                && p.name.chars().all(|c| c.is_ascii_lowercase() || c.is_ascii_digit());
+            if !valid_name || !seen.insert(p.name.as_str()) {
+                return Err(Status::invalid_argument(format!("Zero-Trust: Invalid process name: '{}'", p.name)));
+            }
+            // A newline in ExecStart would inject arbitrary unit directives
+            if p.command.trim().is_empty() || p.command.contains(['\n', '\r']) {
+                return Err(Status::invalid_argument(format!("Zero-Trust: Invalid command for process '{}'", p.name)));
+            }
+            if p.cpu_percent == 0 || p.cpu_percent > 400 || p.memory_mb < 64 || p.memory_mb > 16384 {
+                return Err(Status::invalid_argument(format!("Resource share out of range for process '{}'", p.name)));
+            }
+        }
+        // PHP's web tier is the FPM pool; only background processes may be added
+        if runtime == "php" && seen.contains(WEB_PROCESS) {
+            return Err(Status::invalid_argument("PHP apps are served by PHP-FPM and cannot define a web process"));
+        }
+        Ok(())
+    }
+
     /// 🛡️ Zero-Trust: Validates that a string is a safe alphanumeric-dash identifier
     fn validate_identifier(value: &str, field_name: &str) -> Result<(), Status> {
         if value.is_empty() || !value.chars().all(|c| c.is_ascii_alphanumeric() || c == '-' || c == '_' || c == '.') {
@@ -133,6 +273,21 @@ impl SystemAgent for KariAgentService {
             memory_usage_mb,
             agent_version: env!("CARGO_PKG_VERSION").to_string(),
             uptime_seconds: uptime,
+            protocol_version: PROTOCOL_VERSION,
+            min_protocol_version: MIN_PROTOCOL_VERSION,
+        }))
+    }
+
+    async fn get_capabilities(
+        &self,
+        _request: Request<Empty>,
+    ) -> Result<Response<HostCapabilities>, Status> {
+        let profile = crate::sys::host::probe(&self.config.php_fpm_root).await;
+        Ok(Response::new(HostCapabilities {
+            container_runtime: profile.container_runtime,
+            php_versions: profile.php_versions,
+            disk_quotas: profile.disk_quotas,
+            firewall: profile.firewall,
         }))
     }
 
@@ -179,13 +334,20 @@ impl SystemAgent for KariAgentService {
         Self::validate_identifier(&req.app_id, "app_id")?;
         Self::validate_identifier(&req.domain_name, "domain_name")?;
 
+        // 🛡️ Zero-Trust: Never create a jail user in the system/root UID space
+        if req.app_uid < 1000 {
+            return Err(Status::invalid_argument(format!(
+                "Zero-Trust: app_uid {} is outside the unprivileged range", req.app_uid
+            )));
+        }
+
         let app_user = format!("kari-app-{}", req.app_id);
         let app_dir = self.secure_join(&self.config.web_root, &req.domain_name)?;
         let service_name = format!("kari-{}", req.domain_name);
 
-        // Step 1: Provision the unprivileged OS user
+        // Step 1: Provision the unprivileged OS user with the ledger-assigned UID
         self.jail_mgr
-            .provision_app_user(&app_user, 0) // UID auto-assigned by useradd
+            .provision_app_user(&app_user, req.app_uid)
             .await
             .map_err(|e| Status::internal(format!("[SLA ERROR] User provisioning failed: {}", e)))?;
 
@@ -195,15 +357,17 @@ impl SystemAgent for KariAgentService {
             .await
             .map_err(|e| Status::internal(format!("[SLA ERROR] Directory jailing failed: {}", e)))?;
 
-        // Step 3: Write systemd unit file with cgroup v2 resource limits
+        // Step 3: Write systemd unit file with cgroup v2 resource limits,
+        // capped by the SystemProfile ceilings
+        let ceilings = self.defaults.get().await;
         let svc_config = ServiceConfig {
             service_name: service_name.clone(),
             username: app_user.clone(),
             working_directory: app_dir.clone(),
             start_command: req.start_command.clone(),
             env_vars: req.env_vars.clone(),
-            memory_limit_mb: req.memory_limit_mb as i32,
-            cpu_limit_percent: 100, // Default: full single core
+            memory_limit_mb: ceilings.clamp_memory(req.memory_limit_mb) as i32,
+            cpu_limit_percent: ceilings.clamp_cpu(100) as i32, // Default: full single core
         };
 
         self.svc_mgr
@@ -248,6 +412,10 @@ impl SystemAgent for KariAgentService {
     ) -> Result<Response<AgentResponse>, Status> {
         use kari_agent::ServiceAction;
 
+        let change_id = ledger::change_id(&request);
+        if let Some(ack) = self.replayed_change(change_id.as_deref()).await {
+            return Ok(ack);
+        }
         let req = request.into_inner();
         Self::validate_identifier(&req.service_name, "service_name")?;
 
@@ -273,6 +441,7 @@ impl SystemAgent for KariAgentService {
         match result {
             Ok(()) => {
                 info!("⚙️ Service {} action {:?} succeeded", req.service_name, action);
+                self.record_change(change_id).await;
                 Ok(Response::new(AgentResponse {
                     success: true,
                     exit_code: 0,
@@ -291,6 +460,161 @@ impl SystemAgent for KariAgentService {
         }
     }
 
+    // =========================================================================
+    // 4b. 📊 Process Status (Multi-Process Apps)
+    // =========================================================================
+    async fn get_process_status(
+        &self,
+        request: Request<ProcessStatusRequest>,
+    ) -> Result<Response<ProcessStatusResponse>, Status> {
+        let req = request.into_inner();
+        Self::validate_identifier(&req.domain_name, "domain_name")?;
+
+        if req.web_instances > MAX_INSTANCES || req.base_port.saturating_add(req.web_instances.max(1) - 1) > u16::MAX as u32 {
+            return Err(Status::invalid_argument(format!("At most {} web instances on valid ports", MAX_INSTANCES)));
+        }
+
+        let mut processes = Vec::with_capacity(req.names.len() + req.web_instances as usize);
+        for name in req.names {
+            if name.is_empty() || !name.chars().all(|c| c.is_ascii_lowercase() || c.is_ascii_digit()) {
+                return Err(Status::invalid_argument(format!("Zero-Trust: Invalid process name: '{}'", name)));
+            }
+
+            // Non-web processes (and an unscaled web) report a single state
+            let instances = if name == WEB_PROCESS { req.web_instances.max(1) } else { 1 };
+            for i in 0..instances {
+                let unit_process = if i == 0 { name.clone() } else { format!("{}-{}", WEB_PROCESS, i + 1) };
+                let unit = process_unit_name(&req.domain_name, &unit_process);
+                // A unit that was never deployed reports as inactive rather than failing the batch
+                let st = self.svc_mgr.status(&unit).await.unwrap_or_default();
+
+                let mut state = ProcessState {
+                    name: name.clone(),
+                    active_state: st.active_state,
+                    sub_state: st.sub_state,
+                    memory_bytes: st.memory_bytes,
+                    cpu_usage_nsec: st.cpu_usage_nsec,
+                    active_since_unix: st.active_since_unix,
+                    ..Default::default()
+                };
+                if name == WEB_PROCESS && req.base_port > 0 {
+                    let port = (req.base_port + i) as u16;
+                    state.instance = i + 1;
+                    state.port = port as u32;
+                    state.healthy = state.active_state == "active" && probe_port(port).await;
+                }
+                processes.push(state);
+            }
+        }
+        Ok(Response::new(ProcessStatusResponse { processes }))
+    }
+
+    // =========================================================================
+    // 4c. 🔄 App Restart (Hard or Graceful)
+    // =========================================================================
+    async fn restart_app(
+        &self,
+        request: Request<RestartRequest>,
+    ) -> Result<Response<AgentResponse>, Status> {
+        let req = request.into_inner();
+        Self::validate_identifier(&req.domain_name, "domain_name")?;
+
+        // The surge copy takes the port after the last instance
+        let instances = req.web_instances.max(1);
+        if instances > MAX_INSTANCES || req.base_port.saturating_add(instances) > u16::MAX as u32 {
+            return Err(Status::invalid_argument(format!("At most {} web instances on valid ports", MAX_INSTANCES)));
+        }
+
+        let mut units: Vec<(String, Option<u16>)> = Vec::new();
+        for name in &req.names {
+            if name.is_empty() || !name.chars().all(|c| c.is_ascii_lowercase() || c.is_ascii_digit()) {
+                return Err(Status::invalid_argument(format!("Zero-Trust: Invalid process name: '{}'", name)));
+            }
+            if name != WEB_PROCESS {
+                units.push((process_unit_name(&req.domain_name, name), None));
+                continue;
+            }
+            for i in 0..instances {
+                let unit_process = if i == 0 { name.clone() } else { format!("{}-{}", WEB_PROCESS, i + 1) };
+                let port = (req.base_port > 0).then(|| (req.base_port + i) as u16);
+                units.push((process_unit_name(&req.domain_name, &unit_process), port));
+            }
+        }
+        if units.is_empty() {
+            return Err(Status::invalid_argument("No processes to restart"));
+        }
+
+        // ⚖️ Scaled down (3 → 1): instances above the count leave the pool
+        // once the kept ones are back, then are stopped and disabled
+        let surplus = if req.names.iter().any(|n| n == WEB_PROCESS) {
+            surplus_web_units(self.svc_mgr.as_ref(), &req.domain_name, instances).await
+                .map_err(Status::internal)?
+        } else {
+            Vec::new()
+        };
+
+        let graceful = req.graceful && req.names.iter().any(|n| n == WEB_PROCESS);
+        if graceful && req.base_port == 0 {
+            return Err(Status::invalid_argument("Graceful restart needs the web port"));
+        }
+
+        let result = if graceful {
+            graceful_restart(
+                self.svc_mgr.as_ref(), self.proxy_mgr.as_ref(), &req.domain_name,
+                &units, req.base_port as u16, instances,
+            ).await
+        } else {
+            let mut res = Ok(());
+            for (unit, _) in &units {
+                if let Err(e) = self.svc_mgr.restart(unit).await {
+                    res = Err(e);
+                    break;
+                }
+            }
+            res
+        };
+
+        if result.is_ok() && !surplus.is_empty() {
+            // A graceful restart already handed traffic back to the kept ports
+            if !graceful && req.base_port > 0 {
+                let base = req.base_port as u16;
+                let vhost = if instances > 1 {
+                    let ports: Vec<u16> = (0..instances).map(|i| base + i as u16).collect();
+                    self.proxy_mgr.create_balanced_vhost(&req.domain_name, &ports).await
+                } else {
+                    self.proxy_mgr.create_vhost(&req.domain_name, base).await
+                };
+                if let Err(e) = vhost {
+                    warn!("Vhost of {} not narrowed before scale-down: {}", req.domain_name, e);
+                }
+            }
+            match retire_units(self.svc_mgr.as_ref(), &surplus).await {
+                Ok(()) => info!("⚖️ Retired {} surplus web instance(s) of {}", surplus.len(), req.domain_name),
+                Err(e) => warn!("Surplus web instances of {} not retired: {}", req.domain_name, e),
+            }
+        }
+
+        match result {
+            Ok(()) => {
+                info!("🔄 Restarted {} unit(s) of {} (trace: {}, graceful: {})",
+                    units.len(), req.domain_name, req.trace_id, graceful);
+                Ok(Response::new(AgentResponse {
+                    success: true,
+                    stdout: format!("Restarted {} unit(s)", units.len()),
+                    ..Default::default()
+                }))
+            }
+            Err(e) => {
+                error!("🔄 Restart of {} failed: {}", req.domain_name, e);
+                Ok(Response::new(AgentResponse {
+                    success: false,
+                    error_message: e,
+                    ..Default::default()
+                }))
+            }
+        }
+    }
+
     // =========================================================================
     // 5. 📡 Streaming Deployment (Hardened Blue-Green)
     // =========================================================================
@@ -304,6 +628,29 @@ impl SystemAgent for KariAgentService {
         let base_dir = self.secure_join(&self.config.web_root, &req.domain_name)?;
         let release_dir = base_dir.join("releases").join(&timestamp);
         let app_user = format!("kari-app-{}", req.app_id);
+        // Fail the RPC up front rather than building with the wrong toolchain
+        let toolchain = self.resolve_toolchain(&req.runtime, &req.runtime_version)?;
+        Self::validate_processes(&req.processes, &req.runtime)?;
+        let port = req.port.unwrap_or(3000) as u16;
+        if req.instances > 1 {
+            if req.instances > MAX_INSTANCES || !req.processes.iter().any(|p| p.name == WEB_PROCESS) {
+                return Err(Status::invalid_argument(format!(
+                    "Instances must be 1-{} and require a web process", MAX_INSTANCES
+                )));
+            }
+            if port as u32 + req.instances - 1 > u16::MAX as u32 {
+                return Err(Status::invalid_argument("Instance ports exceed 65535"));
+            }
+        }
+        let ceilings = self.defaults.get().await;
+        let units: Vec<(ProcessSpec, Option<u16>)> = expand_web_instances(&req.processes, req.instances, port)
+            .into_iter()
+            .map(|(mut spec, port)| {
+                spec.memory_mb = ceilings.clamp_memory(spec.memory_mb);
+                spec.cpu_percent = ceilings.clamp_cpu(spec.cpu_percent);
+                (spec, port)
+            })
+            .collect();
 
         let (tx, rx) = mpsc::channel(512);
 
@@ -313,17 +660,29 @@ impl SystemAgent for KariAgentService {
         let build = Arc::clone(&self.build_mgr);
         let svc = Arc::clone(&self.svc_mgr);
         let proxy = Arc::clone(&self.proxy_mgr);
+        let php = Arc::clone(&self.php_mgr);
+        let scanner = Arc::clone(&self.scanner);
+        let artifacts = Arc::clone(&self.artifacts);
+        let sbom = Arc::clone(&self.sbom);
 
         tokio::spawn(async move {
             let t = req.trace_id.clone();
-            let log = |m: &str| LogChunk { content: m.to_string(), trace_id: t.clone() };
+            let log = |m: &str| LogChunk { content: m.to_string(), trace_id: t.clone(), ..Default::default() };
 
-            // -- Step 1: Secure Git Clone --
+            // -- Step 1: Secure Git Clone (or a stored release for redeploys) --
             let ssh_cred = req.ssh_key.map(ProviderCredential::from_string);
-            let _ = tx.send(Ok(log("📦 Pulling source...\n"))).await;
-            if let Err(e) = git.clone_repo(&req.repo_url, &req.branch, &release_dir, ssh_cred).await {
-                let _ = tx.send(Ok(log(&format!("❌ Git Error: {}\n", e)))).await;
-                return;
+            if let Some(src) = &req.reuse_artifact {
+                let _ = tx.send(Ok(log(&format!("📦 Unpacking artifact {} (no rebuild)...\n", src.id)))).await;
+                if let Err(e) = artifacts.restore(&req.app_id, &src.id, &src.sha256, &release_dir).await {
+                    let _ = tx.send(Ok(log(&format!("❌ Artifact Error: {}\n", e)))).await;
+                    return;
+                }
+            } else {
+                let _ = tx.send(Ok(log("📦 Pulling source...\n"))).await;
+                if let Err(e) = git.clone_repo(&req.repo_url, &req.branch, &release_dir, ssh_cred).await {
+                    let _ = tx.send(Ok(log(&format!("❌ Git Error: {}\n", e)))).await;
+                    return;
+                }
             }
 
             // -- Step 2: Permissions Jailing --
@@ -335,9 +694,23 @@ impl SystemAgent for KariAgentService {
             }
 
             // -- Step 3: Isolated Build --
-            let _ = tx.send(Ok(log("🏗️ Executing build...\n"))).await;
             let mut envs: HashMap<String, String> = req.env_vars.into_iter().collect();
-            let build_res = build.execute_build(&req.build_command, &release_dir, &app_user, &envs, tx.clone(), t.clone()).await;
+            if let Some(bin_dir) = &toolchain {
+                let _ = tx.send(Ok(log(&format!("🧰 Using {} {}\n", req.runtime, req.runtime_version)))).await;
+                let host_path = std::env::var("PATH").unwrap_or_else(|_| "/usr/local/bin:/usr/bin:/bin".to_string());
+                envs.insert("PATH".to_string(), format!("{}:{}", bin_dir.display(), host_path));
+            }
+            // Process units run with the same environment as the build
+            let mut unit_env = if req.processes.is_empty() { HashMap::new() } else { envs.clone() };
+            // Build-only vars (build args, one-off overrides) win over runtime
+            // ones but never reach the units
+            envs.extend(req.build_env);
+            let build_res = if req.reuse_artifact.is_some() {
+                Ok(()) // Built when the artifact was stored
+            } else {
+                let _ = tx.send(Ok(log("🏗️ Executing build...\n"))).await;
+                build.execute_build(&req.build_command, &release_dir, &app_user, &envs, tx.clone(), t.clone()).await
+            };
 
             // 🛡️ Privacy: Clear the build environment variables from RAM
             for (_, mut val) in envs.drain() {
@@ -345,23 +718,132 @@ impl SystemAgent for KariAgentService {
             }
 
             if let Err(e) = build_res {
+                for (_, mut val) in unit_env.drain() {
+                    val.zeroize();
+                }
                 let _ = tx.send(Ok(log(&format!("❌ Build Error: {}\n", e)))).await;
                 return;
             }
 
+            // -- Step 3b: Artifact for later redeploys (best effort) --
+            if req.reuse_artifact.is_none() && !req.artifact_id.is_empty() {
+                match artifacts.store(&req.app_id, &req.artifact_id, &release_dir).await {
+                    Ok(meta) => {
+                        let _ = tx.send(Ok(LogChunk {
+                            content: format!("📦 Stored artifact ({} bytes)\n", meta.size_bytes),
+                            trace_id: t.clone(),
+                            artifact: Some(ArtifactInfo {
+                                id: req.artifact_id.clone(),
+                                sha256: meta.sha256,
+                                size_bytes: meta.size_bytes,
+                            }),
+                            ..Default::default()
+                        })).await;
+                    }
+                    Err(e) => {
+                        let _ = tx.send(Ok(log(&format!("⚠️ Artifact not stored (redeploys will rebuild): {}\n", e)))).await;
+                    }
+                }
+            }
+
+            // -- Step 3c: SBOM (best effort; a redeploy reuses its source's) --
+            if req.reuse_artifact.is_none() {
+                match sbom.generate(&release_dir).await {
+                    Ok(Some(report)) => {
+                        let _ = tx.send(Ok(LogChunk {
+                            content: format!("🧾 Cataloged {} dependencies\n", report.components.len()),
+                            trace_id: t.clone(),
+                            sbom: Some(SbomReport {
+                                generator: report.generator,
+                                components: report.components.into_iter().map(|c| SbomComponent {
+                                    name: c.name,
+                                    version: c.version,
+                                    purl: c.purl,
+                                    licenses: c.licenses,
+                                }).collect(),
+                                truncated: report.truncated,
+                            }),
+                            ..Default::default()
+                        })).await;
+                    }
+                    Ok(None) => {} // No generator installed on this host
+                    Err(e) => {
+                        let _ = tx.send(Ok(log(&format!("⚠️ SBOM not generated: {}\n", e)))).await;
+                    }
+                }
+            }
+
             // -- Step 4: Proxy & Service Activation --
             let service_name = format!("kari-{}", req.domain_name);
             let _ = tx.send(Ok(log("🌐 Updating Proxy & Restarting...\n"))).await;
-            
-            let port = req.port.unwrap_or(3000) as u16;
-            if let Err(e) = proxy.create_vhost(&req.domain_name, port).await {
-                let _ = tx.send(Ok(log(&format!("❌ Proxy Error: {}\n", e)))).await;
-                return;
+
+            // PHP apps have no web process of their own: the per-app FPM pool
+            // serves the release directly (background processes still apply).
+            if req.runtime == "php" {
+                let pool = PhpPoolConfig {
+                    app_id: req.app_id.clone(),
+                    version: req.runtime_version.clone(),
+                    app_user: app_user.clone(),
+                    doc_root: release_dir.clone(),
+                    max_children: req.php_max_children,
+                };
+                let socket = match php.write_pool(&pool).await {
+                    Ok(socket) => socket,
+                    Err(e) => {
+                        let _ = tx.send(Ok(log(&format!("❌ PHP-FPM Error: {}\n", e)))).await;
+                        return;
+                    }
+                };
+                if let Err(e) = proxy.create_php_vhost(&req.domain_name, &socket, &release_dir).await {
+                    let _ = tx.send(Ok(log(&format!("❌ Proxy Error: {}\n", e)))).await;
+                    return;
+                }
+            } else if req.instances > 1 {
+                // ⚖️ Load-balance across every web instance
+                let ports: Vec<u16> = units.iter().filter_map(|(_, p)| *p).collect();
+                if let Err(e) = proxy.create_balanced_vhost(&req.domain_name, &ports).await {
+                    let _ = tx.send(Ok(log(&format!("❌ Proxy Error: {}\n", e)))).await;
+                    return;
+                }
+            } else if req.processes.is_empty() || req.processes.iter().any(|p| p.name == WEB_PROCESS) {
+                if let Err(e) = proxy.create_vhost(&req.domain_name, port).await {
+                    let _ = tx.send(Ok(log(&format!("❌ Proxy Error: {}\n", e)))).await;
+                    return;
+                }
             }
 
-            if let Err(e) = svc.restart(&service_name).await {
-                let _ = tx.send(Ok(log(&format!("❌ Service Error: {}\n", e)))).await;
-                return;
+            if !req.processes.is_empty() {
+                // ⚙️ Multi-process apps: every unit switches to the new release, or none does
+                let names: Vec<&str> = units.iter().map(|(p, _)| p.name.as_str()).collect();
+                let _ = tx.send(Ok(log(&format!("⚙️ Starting processes: {}\n", names.join(", "))))).await;
+                let res = apply_process_set(svc.as_ref(), &req.domain_name, &app_user, &release_dir, &units, &unit_env).await;
+                for (_, mut val) in unit_env.drain() {
+                    val.zeroize();
+                }
+                if let Err(e) = res {
+                    let _ = tx.send(Ok(log(&format!("❌ Process Error (rolled back): {}\n", e)))).await;
+                    return;
+                }
+            } else {
+                if req.runtime != "php" {
+                    if let Err(e) = svc.restart(&service_name).await {
+                        let _ = tx.send(Ok(log(&format!("❌ Service Error: {}\n", e)))).await;
+                        return;
+                    }
+                }
+                // Back to a single process: units left from an earlier process set go
+                match retire_stale_units(svc.as_ref(), &req.domain_name, &[]).await {
+                    Ok(0) => {}
+                    Ok(n) => {
+                        let _ = tx.send(Ok(log(&format!("🧹 Retired {} process unit(s) no longer declared\n", n)))).await;
+                    }
+                    Err(e) => warn!("Stale units of {} not retired: {}", req.domain_name, e),
+                }
+            }
+
+            // 🧬 The new release is the known-good state for integrity scans
+            if let Err(e) = scanner.rebaseline(&req.app_id, &base_dir, &[]).await {
+                warn!("Integrity baseline for {} not refreshed: {}", req.domain_name, e);
             }
 
             let _ = tx.send(Ok(log("✅ Deployment successful.\n"))).await;
@@ -370,6 +852,102 @@ impl SystemAgent for KariAgentService {
         Ok(Response::new(ReceiverStream::new(rx)))
     }
 
+    // =========================================================================
+    // 5b. 🐳 Container Image Pull (Image-Based Apps)
+    // =========================================================================
+    async fn pull_image(
+        &self,
+        request: Request<ImagePullRequest>,
+    ) -> Result<Response<AgentResponse>, Status> {
+        let req = request.into_inner();
+        Self::validate_identifier(&req.app_id, "app_id")?;
+
+        // 🛡️ Privacy: Move the password straight into a zeroizing wrapper
+        let login = req.auth.map(|a| RegistryLogin {
+            server: a.server,
+            username: a.username,
+            password: ProviderCredential::from_string(a.password),
+        });
+        let authenticated = login.is_some();
+
+        if let Err(e) = self.image_mgr.pull(&req.image, login).await {
+            error!("🐳 Image pull failed for app {}: {}", req.app_id, e);
+            return Ok(Response::new(AgentResponse {
+                success: false,
+                error_message: e,
+                ..Default::default()
+            }));
+        }
+
+        info!("🐳 Pulled {} for app {} (trace: {}, authenticated: {})",
+            req.image, req.app_id, req.trace_id, authenticated);
+        Ok(Response::new(AgentResponse { success: true, ..Default::default() }))
+    }
+
+    // =========================================================================
+    // 5c. 🧬 File Integrity & Malware Scan
+    // =========================================================================
+    async fn scan_app_files(
+        &self,
+        request: Request<FileScanRequest>,
+    ) -> Result<Response<FileScanResponse>, Status> {
+        let req = request.into_inner();
+        Self::validate_identifier(&req.app_id, "app_id")?;
+        Self::validate_identifier(&req.domain_name, "domain_name")?;
+        if req.exclude.iter().any(|p| p.is_empty() || p.starts_with('/') || p.contains("..")) {
+            return Err(Status::invalid_argument("Zero-Trust: Exclusions must be relative paths"));
+        }
+
+        let app_dir = self.secure_join(&self.config.web_root, &req.domain_name)?;
+        let report = self.scanner
+            .scan(&req.app_id, &app_dir, req.rebaseline, req.clamav, &req.exclude)
+            .await
+            .map_err(|e| Status::internal(format!("[SLA ERROR] File scan failed: {}", e)))?;
+
+        info!("🧬 Scanned {} files for {} (trace: {}, findings: {})",
+            report.files_scanned, req.domain_name, req.trace_id, report.findings.len());
+        Ok(Response::new(FileScanResponse {
+            files_scanned: report.files_scanned,
+            baseline_created: report.baseline_created,
+            findings: report.findings.into_iter()
+                .map(|f| FileFinding { path: f.path, kind: f.kind.to_string(), detail: f.detail })
+                .collect(),
+        }))
+    }
+
+    async fn collect_access_logs(
+        &self,
+        request: Request<AccessLogRequest>,
+    ) -> Result<Response<AccessLogResponse>, Status> {
+        let req = request.into_inner();
+        Self::validate_identifier(&req.domain_name, "domain_name")?;
+
+        let path = self.proxy_mgr.access_log_path(&req.domain_name);
+        let report = access_log::collect(path, req.offset, req.max_bytes)
+            .await
+            .map_err(|e| Status::internal(format!("[SLA ERROR] Access log collection failed: {}", e)))?;
+
+        if report.rotated {
+            info!("📊 Access log for {} rotated; reading from the start (trace: {})", req.domain_name, req.trace_id);
+        }
+        Ok(Response::new(AccessLogResponse {
+            next_offset: report.next_offset,
+            rotated: report.rotated,
+            skipped_lines: report.skipped_lines,
+            buckets: report.buckets.into_iter()
+                .map(|(hour, b)| AccessLogBucket {
+                    hour_unix: hour,
+                    requests: b.requests,
+                    bytes_sent: b.bytes_sent,
+                    bytes_received: b.bytes_received,
+                    status_classes: b.status_classes,
+                    paths: b.paths,
+                    clients: b.clients,
+                })
+                .collect(),
+        }))
+    }
+
     // =========================================================================
     // 6. 🔥 Resource Teardown (Clean Hygiene)
     // =========================================================================
@@ -391,7 +969,9 @@ impl SystemAgent for KariAgentService {
         let _ = self.svc_mgr.stop(&service_name).await;
         let _ = self.svc_mgr.remove_unit_file(&service_name).await;
         let _ = self.proxy_mgr.remove_vhost(&req.domain_name).await;
+        let _ = self.php_mgr.remove_pool(&req.app_id).await;
         let _ = self.jail_mgr.deprovision_app_user(&app_user).await;
+        let _ = self.artifacts.purge(&req.app_id).await;
 
         if app_dir.exists() {
             tokio::fs::remove_dir_all(&app_dir)
@@ -445,6 +1025,91 @@ impl SystemAgent for KariAgentService {
         }))
     }
 
+    // =========================================================================
+    // 6c. 🧭 Drift Reconciliation (Inventory & Single-Resource Removal)
+    // =========================================================================
+    async fn list_managed_resources(
+        &self,
+        _request: Request<Empty>,
+    ) -> Result<Response<ResourceInventory>, Status> {
+        let sla = |what: &str, e: String| Status::internal(format!("[SLA ERROR] Listing {} failed: {}", what, e));
+
+        let units = self.svc_mgr.list_units("kari-").await.map_err(|e| sla("units", e))?
+            .into_iter()
+            .filter(|u| u != BRAIN_SERVICE_NAME)
+            .collect();
+        let vhosts = self.proxy_mgr.list_vhosts().await.map_err(|e| sla("vhosts", e))?;
+        let jail_users = self.jail_mgr.list_app_users().await.map_err(|e| sla("jail users", e))?;
+        let certificates = self.ssl_engine.list_certificates().await.map_err(|e| sla("certificates", e))?;
+
+        Ok(Response::new(ResourceInventory { units, vhosts, jail_users, certificates }))
+    }
+
+    async fn remove_managed_resource(
+        &self,
+        request: Request<RemoveResourceRequest>,
+    ) -> Result<Response<AgentResponse>, Status> {
+        let req = request.into_inner();
+        Self::validate_identifier(&req.name, "name")?;
+
+        let kind = ResourceKind::try_from(req.kind)
+            .map_err(|_| Status::invalid_argument("Invalid resource kind"))?;
+
+        let result = match kind {
+            ResourceKind::Unit => {
+                // 🛡️ Zero-Trust: Only Kari units, and never the Brain itself
+                if !req.name.starts_with("kari-") || req.name == BRAIN_SERVICE_NAME {
+                    return Err(Status::permission_denied("Zero-Trust: Refusing to remove non-app unit"));
+                }
+                let _ = self.svc_mgr.stop(&req.name).await;
+                match self.svc_mgr.remove_unit_file(&req.name).await {
+                    Ok(()) => self.svc_mgr.reload_daemon().await,
+                    Err(e) => Err(e),
+                }
+            }
+            ResourceKind::Vhost => self.proxy_mgr.remove_vhost(&req.name).await,
+            ResourceKind::JailUser => {
+                if !req.name.starts_with("kari-app-") {
+                    return Err(Status::permission_denied("Zero-Trust: Refusing to remove non-jail user"));
+                }
+                self.jail_mgr.deprovision_app_user(&req.name).await
+            }
+            ResourceKind::Certificate => self.ssl_engine.remove_certificate(&req.name).await,
+        };
+
+        result.map_err(|e| Status::internal(format!("[SLA ERROR] Removing {} failed: {}", req.name, e)))?;
+
+        info!("🧭 Removed orphaned {:?} {} (trace: {})", kind, req.name, req.trace_id);
+        Ok(Response::new(AgentResponse {
+            success: true,
+            exit_code: 0,
+            stdout: format!("Removed {}", req.name),
+            stderr: String::new(),
+            error_message: String::new(),
+        }))
+    }
+
+    // =========================================================================
+    // 6d. 📦 Artifact Retention (the Brain picks what to drop)
+    // =========================================================================
+    async fn delete_artifacts(
+        &self,
+        request: Request<DeleteArtifactsRequest>,
+    ) -> Result<Response<AgentResponse>, Status> {
+        let req = request.into_inner();
+        Self::validate_identifier(&req.app_id, "app_id")?;
+
+        let deleted = self.artifacts.delete(&req.app_id, &req.artifact_ids).await
+            .map_err(|e| Status::internal(format!("[SLA ERROR] Artifact pruning failed: {}", e)))?;
+
+        info!("📦 Pruned {} artifact(s) of app {}", deleted, req.app_id);
+        Ok(Response::new(AgentResponse {
+            success: true,
+            stdout: format!("Deleted {} artifact(s)", deleted),
+            ..Default::default()
+        }))
+    }
+
     // =========================================================================
     // 7. 📝 Filesystem Operations (Zero-Trust Path Validation)
     // =========================================================================
@@ -452,6 +1117,10 @@ impl SystemAgent for KariAgentService {
         &self,
         request: Request<FileWriteRequest>,
     ) -> Result<Response<AgentResponse>, Status> {
+        let change_id = ledger::change_id(&request);
+        if let Some(ack) = self.replayed_change(change_id.as_deref()).await {
+            return Ok(ack);
+        }
         let req = request.into_inner();
 
         // 🛡️ Zero-Trust: Validate path is within allowed directories
@@ -523,6 +1192,7 @@ impl SystemAgent for KariAgentService {
         }
 
         info!("📝 File written: {} (trace: {})", req.absolute_path, req.trace_id);
+        self.record_change(change_id).await;
 
         Ok(Response::new(AgentResponse {
             success: true,
@@ -540,6 +1210,10 @@ impl SystemAgent for KariAgentService {
         &self,
         request: Request<SslPayload>,
     ) -> Result<Response<AgentResponse>, Status> {
+        let change_id = ledger::change_id(&request);
+        if let Some(ack) = self.replayed_change(change_id.as_deref()).await {
+            return Ok(ack);
+        }
         let req = request.into_inner();
 
         // 🛡️ Zero-Trust: Validate domain
@@ -566,6 +1240,7 @@ impl SystemAgent for KariAgentService {
             .map_err(|e| Status::internal(format!("[SLA ERROR] Certificate installation failed: {}", e)))?;
 
         info!("🔐 Certificate installed for domain: {}", req.domain_name);
+        self.record_change(change_id).await;
 
         Ok(Response::new(AgentResponse {
             success: true,
@@ -576,6 +1251,64 @@ impl SystemAgent for KariAgentService {
         }))
     }
 
+    /// Renders the Brain's per-domain header policy into the vhost include.
+    /// A config the proxy rejects is rolled back and reported, not raised.
+    async fn apply_security_headers(
+        &self,
+        request: Request<SecurityHeadersRequest>,
+    ) -> Result<Response<AgentResponse>, Status> {
+        let req = request.into_inner();
+        Self::validate_identifier(&req.domain_name, "domain_name")?;
+
+        let headers: Vec<(String, String)> = req.headers.into_iter().map(|h| (h.name, h.value)).collect();
+        if let Err(e) = self.proxy_mgr.apply_headers(&req.domain_name, &headers).await {
+            warn!("🛡️ Security headers rejected for {} (trace: {}): {}", req.domain_name, req.trace_id, e);
+            return Ok(Response::new(AgentResponse {
+                success: false,
+                exit_code: 1,
+                stdout: String::new(),
+                stderr: String::new(),
+                error_message: e,
+            }));
+        }
+
+        info!("🛡️ Applied {} security header(s) for {}", headers.len(), req.domain_name);
+        Ok(Response::new(AgentResponse {
+            success: true,
+            exit_code: 0,
+            stdout: format!("Security headers applied for {}", req.domain_name),
+            stderr: String::new(),
+            error_message: String::new(),
+        }))
+    }
+
+    async fn set_bandwidth_limit(
+        &self,
+        request: Request<BandwidthLimitRequest>,
+    ) -> Result<Response<AgentResponse>, Status> {
+        let req = request.into_inner();
+        Self::validate_identifier(&req.domain_name, "domain_name")?;
+
+        self.proxy_mgr
+            .set_rate_limit(&req.domain_name, req.rate_kbps)
+            .await
+            .map_err(|e| Status::internal(format!("[SLA ERROR] Bandwidth limit failed: {}", e)))?;
+
+        let summary = if req.rate_kbps == 0 {
+            format!("Bandwidth limit lifted for {}", req.domain_name)
+        } else {
+            format!("{} throttled to {} KiB/s", req.domain_name, req.rate_kbps)
+        };
+        info!("🚦 {} (trace: {})", summary, req.trace_id);
+        Ok(Response::new(AgentResponse {
+            success: true,
+            exit_code: 0,
+            stdout: summary,
+            stderr: String::new(),
+            error_message: String::new(),
+        }))
+    }
+
     // =========================================================================
     // 8. 🛡️ Firewall Policy Enforcement
     // =========================================================================
@@ -636,6 +1369,119 @@ impl SystemAgent for KariAgentService {
         }))
     }
 
+    /// SystemProfile defaults arrive from the Brain's reconciliation worker,
+    /// which resends them until acknowledged, possibly out of order.
+    async fn apply_system_defaults(
+        &self,
+        request: Request<ProtoSystemDefaults>,
+    ) -> Result<Response<AgentResponse>, Status> {
+        let req = request.into_inner();
+
+        // 🛡️ Zero-Trust: Same bounds the Brain validates the profile with
+        if req.max_memory_per_app_mb < 128 {
+            return Err(Status::invalid_argument("max_memory_per_app_mb must be at least 128"));
+        }
+        if !(10..=100).contains(&req.max_cpu_percent_per_app) {
+            return Err(Status::invalid_argument("max_cpu_percent_per_app must be between 10 and 100"));
+        }
+        let firewall = match req.default_firewall_policy.as_str() {
+            "" => None,
+            "allow" => Some(FirewallAction::Allow),
+            "deny" => Some(FirewallAction::Deny),
+            other => return Err(Status::invalid_argument(format!("Unknown firewall policy '{}'", other))),
+        };
+
+        let current = self.defaults.get().await;
+        if req.profile_version < current.profile_version {
+            return Ok(Response::new(AgentResponse {
+                success: true,
+                stdout: format!("Profile v{} already superseded by v{}", req.profile_version, current.profile_version),
+                ..Default::default()
+            }));
+        }
+
+        if let Some(action) = firewall {
+            self.firewall_mgr
+                .set_default_inbound(action)
+                .await
+                .map_err(|e| Status::internal(format!("[SLA ERROR] Firewall default failed: {}", e)))?;
+        }
+        self.defaults
+            .set(SystemDefaults {
+                max_memory_mb: req.max_memory_per_app_mb,
+                max_cpu_percent: req.max_cpu_percent_per_app,
+                profile_version: req.profile_version,
+            })
+            .await
+            .map_err(|e| Status::internal(format!("[SLA ERROR] Defaults persist failed: {}", e)))?;
+
+        info!(
+            "📐 System defaults v{}: {}MB / {}% per app, firewall '{}'",
+            req.profile_version, req.max_memory_per_app_mb, req.max_cpu_percent_per_app, req.default_firewall_policy
+        );
+        Ok(Response::new(AgentResponse {
+            success: true,
+            stdout: format!("Applied system profile v{}", req.profile_version),
+            ..Default::default()
+        }))
+    }
+
+    /// Seals the Brain's secrets during setup. Each value is bound to its
+    /// name and to this machine; the plaintext is wiped once encrypted.
+    async fn seal_secrets(
+        &self,
+        request: Request<SecretBundle>,
+    ) -> Result<Response<SealedSecrets>, Status> {
+        let req = request.into_inner();
+        if req.values.is_empty() || req.values.len() > sealing::MAX_SEALED_VALUES {
+            return Err(Status::invalid_argument(format!(
+                "Between 1 and {} secrets may be sealed at once", sealing::MAX_SEALED_VALUES
+            )));
+        }
+
+        let key = sealing::binding().await;
+        let mut values = HashMap::with_capacity(req.values.len());
+        for (name, value) in req.values {
+            let value = Zeroizing::new(value);
+            let sealed = sealing::seal(&name, &value, key)
+                .await
+                .map_err(|e| Status::failed_precondition(format!("[SLA ERROR] Sealing failed: {}", e)))?;
+            values.insert(name, sealed);
+        }
+
+        info!("🔐 Sealed {} secret(s) under the {} key", values.len(), key.as_str());
+        Ok(Response::new(SealedSecrets {
+            values,
+            key_binding: key.as_str().to_string(),
+        }))
+    }
+
+    /// Unseals the Brain's secrets at boot. Only the Brain's own UID gets
+    /// this far (peer credential guard); nothing is logged but the names.
+    async fn unseal_secrets(
+        &self,
+        request: Request<SealedSecrets>,
+    ) -> Result<Response<SecretBundle>, Status> {
+        let req = request.into_inner();
+        if req.values.len() > sealing::MAX_SEALED_VALUES {
+            return Err(Status::invalid_argument(format!(
+                "At most {} secrets may be unsealed at once", sealing::MAX_SEALED_VALUES
+            )));
+        }
+
+        let mut values = HashMap::with_capacity(req.values.len());
+        for (name, sealed) in req.values {
+            let plain = sealing::unseal(&name, &sealed).await.map_err(|e| {
+                warn!("🔐 Unsealing {} failed", name);
+                Status::failed_precondition(format!("[SLA ERROR] Unsealing failed: {}", e))
+            })?;
+            values.insert(name, plain.to_string());
+        }
+
+        info!("🔓 Unsealed {} secret(s) for the Brain", values.len());
+        Ok(Response::new(SecretBundle { values }))
+    }
+
     // =========================================================================
     // 9. ⏰ Job Scheduling (Zero-Trust Cron via systemd timers)
     // =========================================================================
@@ -683,4 +1529,352 @@ impl SystemAgent for KariAgentService {
             error_message: String::new(),
         }))
     }
+
+    // =========================================================================
+    // 10. 🔄 Brain Self-Update (Verified swap + supervised rollback)
+    // =========================================================================
+    async fn apply_brain_update(
+        &self,
+        request: Request<BrainUpdateRequest>,
+    ) -> Result<Response<AgentResponse>, Status> {
+        use sha2::{Digest, Sha256};
+
+        let req = request.into_inner();
+        Self::validate_identifier(&req.version, "version")?;
+
+        // 🛡️ Zero-Trust: The Brain may only point at files inside the staging dir
+        let staged = std::fs::canonicalize(&req.staged_path)
+            .map_err(|_| Status::not_found("Staged binary not found"))?;
+        let staging_root = std::fs::canonicalize(&self.config.update_staging_dir)
+            .map_err(|e| Status::failed_precondition(format!("Update staging dir unavailable: {}", e)))?;
+        if !staged.starts_with(&staging_root) {
+            return Err(Status::permission_denied(
+                "Zero-Trust: Staged binary is outside the update staging directory"
+            ));
+        }
+
+        // 🛡️ Zero-Trust: Re-verify the digest; never trust the Brain's own check
+        let bytes = tokio::fs::read(&staged)
+            .await
+            .map_err(|e| Status::internal(format!("Failed to read staged binary: {}", e)))?;
+        let digest: String = Sha256::digest(&bytes).iter().map(|b| format!("{:02x}", b)).collect();
+        if !digest.eq_ignore_ascii_case(&req.sha256) {
+            return Err(Status::permission_denied("Zero-Trust: Staged binary checksum mismatch"));
+        }
+
+        // Keep the running binary as `.previous`, then atomically swap in the new one
+        let target = self.config.brain_binary_path.clone();
+        let previous = target.with_extension("previous");
+        let incoming = target.with_extension("new");
+
+        tokio::fs::copy(&target, &previous)
+            .await
+            .map_err(|e| Status::internal(format!("Failed to back up current binary: {}", e)))?;
+        tokio::fs::write(&incoming, &bytes)
+            .await
+            .map_err(|e| Status::internal(format!("Failed to write new binary: {}", e)))?;
+        tokio::fs::set_permissions(&incoming, std::fs::Permissions::from_mode(0o755))
+            .await
+            .map_err(|e| Status::internal(format!("Failed to set binary permissions: {}", e)))?;
+        tokio::fs::rename(&incoming, &target)
+            .await
+            .map_err(|e| Status::internal(format!("Failed to install new binary: {}", e)))?;
+        let _ = tokio::fs::remove_file(&staged).await;
+
+        info!("🔄 Brain binary {} installed; restarting {}", req.version, BRAIN_SERVICE_NAME);
+
+        // The restart kills the caller, so it runs detached AFTER this response is sent
+        let svc_mgr = Arc::clone(&self.svc_mgr);
+        let health_addr = self.config.brain_health_addr.clone();
+        let timeout_secs = if req.health_timeout_seconds == 0 {
+            DEFAULT_BRAIN_HEALTH_TIMEOUT_SECS
+        } else {
+            req.health_timeout_seconds
+        };
+        let version = req.version.clone();
+
+        tokio::spawn(async move {
+            tokio::time::sleep(std::time::Duration::from_secs(2)).await;
+
+            if let Err(e) = svc_mgr.restart(BRAIN_SERVICE_NAME).await {
+                error!("Brain restart failed after update: {}", e);
+            } else if probe_brain_health(&health_addr, timeout_secs).await {
+                info!("✅ Brain {} passed health checks", version);
+                return;
+            }
+
+            // 🛡️ SLA: Roll back to the last known-good binary
+            warn!("⏪ Brain {} failed health checks; rolling back", version);
+            if let Err(e) = tokio::fs::rename(&previous, &target).await {
+                error!("CRITICAL: Brain rollback failed: {}", e);
+                return;
+            }
+            if let Err(e) = svc_mgr.restart(BRAIN_SERVICE_NAME).await {
+                error!("CRITICAL: Brain restart after rollback failed: {}", e);
+            }
+        });
+
+        Ok(Response::new(AgentResponse {
+            success: true,
+            exit_code: 0,
+            stdout: format!("Brain {} staged; restart scheduled", req.version),
+            stderr: String::new(),
+            error_message: String::new(),
+        }))
+    }
+}
+
+/// "web" keeps the app's original unit so the proxy and legacy tooling still find it.
+fn process_unit_name(domain: &str, process: &str) -> String {
+    if process == WEB_PROCESS {
+        format!("kari-{}", domain)
+    } else {
+        // '_' never appears in a hostname, so this cannot collide with another app's unit
+        format!("kari-{}_{}", domain, process)
+    }
+}
+
+/// Expands "web" into `instances` copies on sequential ports; instance 1 keeps
+/// the "web" name (and the kari-{domain} unit), the rest become "web-2", ...
+/// ('-' never appears in a user process name). Ports are only pinned via
+/// PORT when scaled, so single-instance apps keep their own configuration.
+fn expand_web_instances(processes: &[ProcessSpec], instances: u32, base_port: u16) -> Vec<(ProcessSpec, Option<u16>)> {
+    let mut units = Vec::with_capacity(processes.len() + instances as usize);
+    for p in processes {
+        if p.name != WEB_PROCESS || instances <= 1 {
+            units.push((p.clone(), None));
+            continue;
+        }
+        for i in 0..instances {
+            let mut copy = p.clone();
+            if i > 0 {
+                copy.name = format!("{}-{}", WEB_PROCESS, i + 1);
+            }
+            units.push((copy, Some(base_port + i as u16)));
+        }
+    }
+    units
+}
+
+/// Switches every process unit of an app to `workdir` as one unit of work.
+/// On any failure the previous unit files are restored and restarted; process
+/// types dropped from the app are only retired after the new set is up.
+async fn apply_process_set(
+    svc: &dyn ServiceManager,
+    domain: &str,
+    app_user: &str,
+    workdir: &Path,
+    processes: &[(ProcessSpec, Option<u16>)],
+    env: &HashMap<String, String>,
+) -> Result<(), String> {
+    let wanted: Vec<(String, &(ProcessSpec, Option<u16>))> = processes.iter()
+        .map(|p| (process_unit_name(domain, &p.0.name), p))
+        .collect();
+    // 1. Snapshot everything we are about to touch
+    let mut snapshots = Vec::with_capacity(wanted.len());
+    for (unit, _) in &wanted {
+        snapshots.push((unit.clone(), svc.read_unit_file(unit).await?));
+    }
+
+    // 2. Write all units, reload once, then (re)start each
+    let result: Result<(), String> = async {
+        for (unit, (spec, port)) in &wanted {
+            let mut env_vars = env.clone();
+            if let Some(port) = port {
+                env_vars.insert("PORT".to_string(), port.to_string());
+            }
+            svc.write_unit_file(&ServiceConfig {
+                service_name: unit.clone(),
+                username: app_user.to_string(),
+                working_directory: workdir.to_path_buf(),
+                start_command: spec.command.clone(),
+                env_vars,
+                memory_limit_mb: spec.memory_mb as i32,
+                cpu_limit_percent: spec.cpu_percent as i32,
+            }).await?;
+        }
+        svc.reload_daemon().await?;
+        for ((unit, _), (_, previous)) in wanted.iter().zip(&snapshots) {
+            if previous.is_some() {
+                svc.restart(unit).await?;
+            } else {
+                svc.enable_and_start(unit).await?;
+            }
+        }
+        Ok(())
+    }.await;
+
+    // 3. 🛡️ Stability: Roll the whole set back on any failure
+    if let Err(e) = result {
+        warn!("Process set for {} failed, rolling back: {}", domain, e);
+        let mut restored = Vec::new();
+        for (unit, snapshot) in snapshots {
+            let existed = snapshot.is_some();
+            if svc.restore_unit_file(&unit, snapshot).await.is_ok() && existed {
+                restored.push(unit);
+            }
+        }
+        let _ = svc.reload_daemon().await;
+        for unit in restored {
+            let _ = svc.restart(&unit).await;
+        }
+        return Err(e);
+    }
+
+    // 4. Retire processes removed from the app
+    let keep: Vec<String> = wanted.into_iter().map(|(unit, _)| unit).collect();
+    if let Err(e) = retire_stale_units(svc, domain, &keep).await {
+        warn!("Stale units of {} not retired: {}", domain, e);
+    }
+    Ok(())
+}
+
+/// Stops and removes every `kari-{domain}_*` unit not in `keep`: process
+/// types dropped from the app and web instances scaled away. Runs on every
+/// deploy, so the units on disk always match what was last declared.
+/// Returns how many were retired.
+async fn retire_stale_units(svc: &dyn ServiceManager, domain: &str, keep: &[String]) -> Result<usize, String> {
+    let stale: Vec<String> = svc.list_units(&format!("kari-{}_", domain)).await?
+        .into_iter()
+        .filter(|u| !keep.contains(u))
+        .collect();
+    if !stale.is_empty() {
+        retire_units(svc, &stale).await?;
+    }
+    Ok(stale.len())
+}
+
+/// Stops, disables and removes units. Disabled as well as stopped: a removed
+/// unit left enabled leaves a dangling wants/ link behind.
+async fn retire_units(svc: &dyn ServiceManager, units: &[String]) -> Result<(), String> {
+    for unit in units {
+        if svc.disable(unit).await.is_err() {
+            let _ = svc.stop(unit).await;
+        }
+        let _ = svc.remove_unit_file(unit).await;
+    }
+    svc.reload_daemon().await
+}
+
+/// Web instance units above `instances` (`kari-{domain}_web-N`), i.e. the
+/// copies left running after the app was scaled down.
+async fn surplus_web_units(svc: &dyn ServiceManager, domain: &str, instances: u32) -> Result<Vec<String>, String> {
+    let prefix = format!("kari-{}_{}-", domain, WEB_PROCESS);
+    Ok(svc.list_units(&prefix).await?
+        .into_iter()
+        .filter(|u| u[prefix.len()..].parse::<u32>().map_or(false, |n| n > instances))
+        .collect())
+}
+
+/// Restarts an app without cutting live connections. A surge copy of web
+/// instance 1 takes over the vhost (the proxy reload keeps existing
+/// connections on the old upstreams), then each unit is restarted: systemd
+/// sends SIGTERM and waits out TimeoutStopSec so WebSocket/SSE clients can
+/// drain. Traffic returns to the instances before the surge copy drains too.
+async fn graceful_restart(
+    svc: &dyn ServiceManager,
+    proxy: &dyn ProxyManager,
+    domain: &str,
+    units: &[(String, Option<u16>)],
+    base_port: u16,
+    instances: u32,
+) -> Result<(), String> {
+    let surge_unit = format!("kari-{}_{}-surge", domain, WEB_PROCESS);
+    let surge_port = base_port + instances as u16;
+    if probe_port(surge_port).await {
+        return Err(format!("Surge port {} is already in use", surge_port));
+    }
+
+    // 1. Clone web instance 1 onto the surge port
+    let template = svc.read_unit_file(&process_unit_name(domain, WEB_PROCESS)).await?
+        .ok_or_else(|| "App has no web unit; deploy it first".to_string())?;
+    svc.restore_unit_file(&surge_unit, Some(pin_unit_port(&template, surge_port))).await?;
+    svc.reload_daemon().await?;
+
+    let result: Result<(), String> = async {
+        svc.start(&surge_unit).await?;
+        if !wait_for_port(surge_port, INSTANCE_HEALTH_TIMEOUT_SECS).await {
+            return Err(format!("Surge instance never listened on port {} (does the app honor $PORT?)", surge_port));
+        }
+
+        // 2. New connections go to the surge copy from here on
+        proxy.create_balanced_vhost(domain, &[surge_port]).await?;
+
+        // 3. Drain and restart each unit, waiting for web instances to listen again
+        for (unit, port) in units {
+            svc.restart(unit).await?;
+            if let Some(port) = port {
+                if !wait_for_port(*port, INSTANCE_HEALTH_TIMEOUT_SECS).await {
+                    return Err(format!("{} did not come back on port {}", unit, port));
+                }
+            }
+        }
+        Ok(())
+    }.await;
+
+    // 4. 🛡️ Stability: Always hand traffic back and retire the surge copy
+    let restored = if instances > 1 {
+        let ports: Vec<u16> = (0..instances).map(|i| base_port + i as u16).collect();
+        proxy.create_balanced_vhost(domain, &ports).await
+    } else {
+        proxy.create_vhost(domain, base_port).await
+    };
+    let _ = svc.stop(&surge_unit).await;
+    let _ = svc.remove_unit_file(&surge_unit).await;
+    let _ = svc.reload_daemon().await;
+
+    result.and(restored)
+}
+
+/// Pins PORT in a rendered unit; a later Environment= wins over the app's own.
+fn pin_unit_port(unit: &str, port: u16) -> String {
+    let line = format!("Environment=\"PORT={}\"\n", port);
+    match unit.find("\n[Install]") {
+        Some(idx) => format!("{}\n{}{}", &unit[..idx], line, &unit[idx..]),
+        None => format!("{}\n{}", unit, line),
+    }
+}
+
+/// Polls `probe_port` until the port accepts a connection or the window closes.
+async fn wait_for_port(port: u16, timeout_secs: u64) -> bool {
+    let deadline = tokio::time::Instant::now() + std::time::Duration::from_secs(timeout_secs);
+    while tokio::time::Instant::now() < deadline {
+        if probe_port(port).await {
+            return true;
+        }
+        tokio::time::sleep(std::time::Duration::from_millis(500)).await;
+    }
+    false
+}
+
+/// An instance is healthy when it accepts a TCP connection within a second.
+async fn probe_port(port: u16) -> bool {
+    let connect = tokio::net::TcpStream::connect(("127.0.0.1", port));
+    matches!(tokio::time::timeout(std::time::Duration::from_secs(1), connect).await, Ok(Ok(_)))
+}
+
+/// Polls the Brain's /ping endpoint until it answers 200 or the window closes.
+async fn probe_brain_health(addr: &str, timeout_secs: u32) -> bool {
+    use tokio::io::{AsyncReadExt, AsyncWriteExt};
+
+    let deadline = tokio::time::Instant::now() + std::time::Duration::from_secs(timeout_secs as u64);
+    while tokio::time::Instant::now() < deadline {
+        tokio::time::sleep(std::time::Duration::from_secs(2)).await;
+
+        let Ok(mut stream) = tokio::net::TcpStream::connect(addr).await else {
+            continue;
+        };
+        let probe = format!("GET /ping HTTP/1.1\r\nHost: {}\r\nConnection: close\r\n\r\n", addr);
+        if stream.write_all(probe.as_bytes()).await.is_err() {
+            continue;
+        }
+        let mut buf = [0u8; 64];
+        if let Ok(n) = stream.read(&mut buf).await {
+            let head = String::from_utf8_lossy(&buf[..n]);
+            if head.starts_with("HTTP/1.1 200") || head.starts_with("HTTP/1.0 200") {
+                return true;
+            }
+        }
+    }
+    false
 }
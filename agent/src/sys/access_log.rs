@@ -0,0 +1,163 @@
+// agent/src/sys/access_log.rs
+
+use chrono::{DateTime, Timelike};
+use std::collections::{BTreeMap, HashMap};
+use std::io::{Read, Seek, SeekFrom};
+use std::net::IpAddr;
+use std::path::{Path, PathBuf};
+
+// 🛡️ SLA: One collection pass never reads more than this, whatever the Brain asks
+const MAX_READ_BYTES: u64 = 64 * 1024 * 1024;
+// Top-N per hour bucket; the long tail is folded into the totals only
+const TOP_ENTRIES: usize = 50;
+
+/// One hour of traffic for a domain.
+#[derive(Debug, Default)]
+pub struct HourBucket {
+    pub requests: u64,
+    pub bytes_sent: u64,
+    pub bytes_received: u64,                  // 0 for logs without kari_combined's request size
+    pub status_classes: HashMap<String, u64>, // "2xx", "3xx", ...
+    pub paths: HashMap<String, u64>,          // Query strings stripped
+    pub clients: HashMap<String, u64>,        // Anonymized (IPv4 /24, IPv6 /48)
+}
+
+#[derive(Debug, Default)]
+pub struct AccessLogReport {
+    pub next_offset: u64,
+    pub rotated: bool,                         // File shrank: read restarted at 0
+    pub buckets: BTreeMap<i64, HourBucket>,    // Keyed by hour start (unix seconds)
+    pub skipped_lines: u64,
+}
+
+/// Aggregates the combined-format log at `path` from byte `offset`. Only whole
+/// lines are consumed, so `next_offset` always lands on a line boundary.
+pub async fn collect(path: PathBuf, offset: u64, max_bytes: u64) -> Result<AccessLogReport, String> {
+    tokio::task::spawn_blocking(move || collect_blocking(&path, offset, max_bytes))
+        .await
+        .map_err(|e| format!("Access log task failed: {}", e))?
+}
+
+fn collect_blocking(path: &Path, offset: u64, max_bytes: u64) -> Result<AccessLogReport, String> {
+    let mut report = AccessLogReport::default();
+    let mut file = match std::fs::File::open(path) {
+        Ok(f) => f,
+        // No traffic yet (or the vhost predates access logging)
+        Err(e) if e.kind() == std::io::ErrorKind::NotFound => {
+            report.next_offset = offset;
+            return Ok(report);
+        }
+        Err(e) => return Err(format!("Failed to open {}: {}", path.display(), e)),
+    };
+
+    let len = file.metadata().map_err(|e| e.to_string())?.len();
+    let mut start = offset;
+    if len < offset {
+        report.rotated = true;
+        start = 0;
+    }
+
+    let budget = if max_bytes == 0 { MAX_READ_BYTES } else { max_bytes.min(MAX_READ_BYTES) };
+    file.seek(SeekFrom::Start(start)).map_err(|e| e.to_string())?;
+    let mut raw = Vec::new();
+    file.take(budget).read_to_end(&mut raw).map_err(|e| format!("Failed to read access log: {}", e))?;
+
+    // Leave a trailing partial line for the next pass
+    let consumed = match raw.iter().rposition(|&b| b == b'\n') {
+        Some(i) => i + 1,
+        None => 0,
+    };
+    report.next_offset = start + consumed as u64;
+    // A single line larger than the budget would otherwise stall the cursor forever
+    if consumed == 0 && raw.len() as u64 == budget {
+        report.next_offset = start + budget;
+        report.skipped_lines += 1;
+        return Ok(report);
+    }
+
+    for line in String::from_utf8_lossy(&raw[..consumed]).lines() {
+        match parse_line(line) {
+            Some(entry) => {
+                let bucket = report.buckets.entry(entry.hour).or_default();
+                bucket.requests += 1;
+                bucket.bytes_sent += entry.bytes;
+                bucket.bytes_received += entry.bytes_in;
+                *bucket.status_classes.entry(format!("{}xx", entry.status / 100)).or_default() += 1;
+                *bucket.paths.entry(entry.path).or_default() += 1;
+                *bucket.clients.entry(entry.client).or_default() += 1;
+            }
+            None => report.skipped_lines += 1,
+        }
+    }
+
+    for bucket in report.buckets.values_mut() {
+        truncate_top(&mut bucket.paths);
+        truncate_top(&mut bucket.clients);
+    }
+    Ok(report)
+}
+
+struct LogEntry {
+    hour: i64,
+    client: String,
+    path: String,
+    status: u16,
+    bytes: u64,
+    bytes_in: u64,
+}
+
+/// `1.2.3.4 - user [10/Oct/2024:13:55:36 +0000] "GET /p?q HTTP/1.1" 200 2326 "ref" "ua" 512`
+/// The trailing request size is kari_combined only; plain combined lines parse too.
+fn parse_line(line: &str) -> Option<LogEntry> {
+    let (ip, rest) = line.split_once(' ')?;
+    let client = anonymize_ip(ip.parse().ok()?);
+
+    let ts_start = rest.find('[')? + 1;
+    let ts_end = ts_start + rest[ts_start..].find(']')?;
+    let ts = DateTime::parse_from_str(&rest[ts_start..ts_end], "%d/%b/%Y:%H:%M:%S %z").ok()?;
+    let hour = ts.timestamp() - (ts.minute() as i64 * 60 + ts.second() as i64);
+
+    let rest = &rest[ts_end..];
+    let req_start = rest.find('"')? + 1;
+    let req_end = req_start + rest[req_start..].find('"')?;
+    let request = &rest[req_start..req_end];
+    // Malformed requests are logged as "-" or garbage; count them under "-"
+    let target = request.split(' ').nth(1).unwrap_or("-");
+    let path = target.split(['?', '#']).next().unwrap_or("-");
+    let path: String = path.chars().take(256).collect();
+
+    let tail = &rest[req_end + 1..];
+    let mut fields = tail.split_whitespace();
+    let status: u16 = fields.next()?.parse().ok()?;
+    let bytes = fields.next().and_then(|b| b.parse().ok()).unwrap_or(0); // "-" for empty bodies
+    // Only trust a number that follows the quoted user agent
+    let bytes_in = tail.rfind('"')
+        .and_then(|i| tail[i + 1..].trim().parse().ok())
+        .unwrap_or(0);
+
+    Some(LogEntry { hour, client, path, status, bytes, bytes_in })
+}
+
+/// 🛡️ Privacy: Full client addresses never leave the host.
+fn anonymize_ip(ip: IpAddr) -> String {
+    match ip {
+        IpAddr::V4(v4) => {
+            let [a, b, c, _] = v4.octets();
+            format!("{}.{}.{}.0", a, b, c)
+        }
+        IpAddr::V6(v6) => {
+            let s = v6.segments();
+            format!("{:x}:{:x}:{:x}::", s[0], s[1], s[2])
+        }
+    }
+}
+
+fn truncate_top(counts: &mut HashMap<String, u64>) {
+    if counts.len() <= TOP_ENTRIES {
+        return;
+    }
+    let mut entries: Vec<(String, u64)> = counts.drain().collect();
+    entries.sort_unstable_by(|a, b| b.1.cmp(&a.1).then_with(|| a.0.cmp(&b.0)));
+    entries.truncate(TOP_ENTRIES);
+    counts.extend(entries);
+}
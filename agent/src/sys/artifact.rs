@@ -0,0 +1,155 @@
+use async_trait::async_trait;
+use sha2::{Digest, Sha256};
+use std::io::Read;
+use std::os::unix::fs::PermissionsExt;
+use std::path::{Path, PathBuf};
+use tokio::process::Command;
+
+/// Checksum and size of a stored release archive, reported to the Brain.
+#[derive(Debug, Clone)]
+pub struct ArtifactMeta {
+    pub sha256: String,
+    pub size_bytes: u64,
+}
+
+#[async_trait]
+pub trait ArtifactStore: Send + Sync {
+    /// Archives a built release directory (without .git) under the artifact ID.
+    async fn store(&self, app_id: &str, artifact_id: &str, release_dir: &Path) -> Result<ArtifactMeta, String>;
+    /// Verifies the archive against `sha256` and unpacks it into `release_dir`.
+    async fn restore(&self, app_id: &str, artifact_id: &str, sha256: &str, release_dir: &Path) -> Result<(), String>;
+    /// Removes archives; already-missing ones are not an error. Returns how many were deleted.
+    async fn delete(&self, app_id: &str, artifact_ids: &[String]) -> Result<usize, String>;
+    /// Removes every archive of an app (teardown).
+    async fn purge(&self, app_id: &str) -> Result<(), String>;
+}
+
+/// gzip'd tarballs in a root-only directory outside every app jail:
+/// `{root}/{app_id}/{artifact_id}.tar.gz`.
+pub struct TarArtifactStore {
+    root: PathBuf,
+}
+
+impl TarArtifactStore {
+    pub fn new(root: PathBuf) -> Self {
+        Self { root }
+    }
+
+    /// 🛡️ Zero-Trust: Both IDs become path components
+    fn archive_path(&self, app_id: &str, artifact_id: &str) -> Result<PathBuf, String> {
+        for (id, what) in [(app_id, "app ID"), (artifact_id, "artifact ID")] {
+            if id.is_empty() || id.len() > 64 || !id.chars().all(|c| c.is_ascii_alphanumeric() || c == '-') {
+                return Err(format!("SECURITY VIOLATION: Invalid {} for artifact", what));
+            }
+        }
+        Ok(self.root.join(app_id).join(format!("{}.tar.gz", artifact_id)))
+    }
+
+    async fn run_tar(cmd: &mut Command) -> Result<(), String> {
+        let output = cmd.output().await.map_err(|e| format!("Failed to run tar: {}", e))?;
+        if !output.status.success() {
+            return Err(format!("tar failed: {}", String::from_utf8_lossy(&output.stderr).trim()));
+        }
+        Ok(())
+    }
+}
+
+fn checksum(path: &Path) -> Result<ArtifactMeta, String> {
+    let mut file = std::fs::File::open(path).map_err(|e| format!("Failed to open {}: {}", path.display(), e))?;
+    let mut hasher = Sha256::new();
+    let mut buf = [0u8; 64 * 1024];
+    let mut size_bytes = 0u64;
+    loop {
+        let n = file.read(&mut buf).map_err(|e| format!("Failed to read {}: {}", path.display(), e))?;
+        if n == 0 {
+            break;
+        }
+        hasher.update(&buf[..n]);
+        size_bytes += n as u64;
+    }
+    Ok(ArtifactMeta { sha256: format!("{:x}", hasher.finalize()), size_bytes })
+}
+
+#[async_trait]
+impl ArtifactStore for TarArtifactStore {
+    async fn store(&self, app_id: &str, artifact_id: &str, release_dir: &Path) -> Result<ArtifactMeta, String> {
+        let path = self.archive_path(app_id, artifact_id)?;
+        let dir = path.parent().ok_or("Invalid artifact path")?;
+        tokio::fs::create_dir_all(dir).await
+            .map_err(|e| format!("Failed to create artifact dir: {}", e))?;
+        tokio::fs::set_permissions(dir, std::fs::Permissions::from_mode(0o700)).await
+            .map_err(|e| e.to_string())?;
+
+        // Written aside and renamed, so a crash never leaves a truncated archive under the real name
+        let partial = path.with_extension("gz.partial");
+        let res = Self::run_tar(
+            Command::new("tar")
+                .arg("--create").arg("--gzip")
+                .arg("--file").arg(&partial)
+                .arg("--exclude=./.git")
+                .arg("--directory").arg(release_dir)
+                .arg("."),
+        ).await;
+        if let Err(e) = res {
+            let _ = tokio::fs::remove_file(&partial).await;
+            return Err(e);
+        }
+
+        let hashed = partial.clone();
+        let meta = tokio::task::spawn_blocking(move || checksum(&hashed)).await
+            .map_err(|e| format!("Checksum task failed: {}", e))??;
+        tokio::fs::rename(&partial, &path).await
+            .map_err(|e| format!("Failed to finalize artifact: {}", e))?;
+        Ok(meta)
+    }
+
+    async fn restore(&self, app_id: &str, artifact_id: &str, sha256: &str, release_dir: &Path) -> Result<(), String> {
+        let path = self.archive_path(app_id, artifact_id)?;
+        if !path.exists() {
+            return Err(format!("Artifact {} is no longer stored", artifact_id));
+        }
+
+        // 🛡️ Integrity: Never unpack something other than what the Brain recorded
+        let hashed = path.clone();
+        let meta = tokio::task::spawn_blocking(move || checksum(&hashed)).await
+            .map_err(|e| format!("Checksum task failed: {}", e))??;
+        if !meta.sha256.eq_ignore_ascii_case(sha256) {
+            return Err(format!("Artifact {} failed checksum verification", artifact_id));
+        }
+
+        tokio::fs::create_dir_all(release_dir).await
+            .map_err(|e| format!("Failed to create release dir: {}", e))?;
+        // Ownership is re-applied by the jail afterwards
+        Self::run_tar(
+            Command::new("tar")
+                .arg("--extract").arg("--gzip")
+                .arg("--no-same-owner")
+                .arg("--file").arg(&path)
+                .arg("--directory").arg(release_dir),
+        ).await
+    }
+
+    async fn delete(&self, app_id: &str, artifact_ids: &[String]) -> Result<usize, String> {
+        let mut deleted = 0;
+        for id in artifact_ids {
+            let path = self.archive_path(app_id, id)?;
+            match tokio::fs::remove_file(&path).await {
+                Ok(()) => deleted += 1,
+                Err(e) if e.kind() == std::io::ErrorKind::NotFound => {}
+                Err(e) => return Err(format!("Failed to delete artifact {}: {}", id, e)),
+            }
+        }
+        Ok(deleted)
+    }
+
+    async fn purge(&self, app_id: &str) -> Result<(), String> {
+        // Validates app_id the same way as every other path
+        let path = self.archive_path(app_id, "purge")?;
+        let dir = path.parent().ok_or("Invalid artifact path")?;
+        match tokio::fs::remove_dir_all(&dir).await {
+            Ok(()) => Ok(()),
+            Err(e) if e.kind() == std::io::ErrorKind::NotFound => Ok(()),
+            Err(e) => Err(format!("Failed to purge artifacts of {}: {}", app_id, e)),
+        }
+    }
+}
@@ -63,7 +63,8 @@ impl BuildManager for SystemBuildManager {
             while let Ok(Some(line)) = reader.next_line().await {
                 let chunk = LogChunk { 
                     content: format!("[OUT] {}\n", line), 
-                    trace_id: t_out.clone() 
+                    trace_id: t_out.clone(),
+                    ..Default::default()
                 };
                 // 🛡️ SLA: Send with backpressure. If receiver is gone, stop the task.
                 if tx_out.send(Ok(chunk)).await.is_err() { break; } 
@@ -77,7 +78,8 @@ impl BuildManager for SystemBuildManager {
             while let Ok(Some(line)) = reader.next_line().await {
                 let chunk = LogChunk { 
                     content: format!("[ERR] {}\n", line), 
-                    trace_id: t_err.clone() 
+                    trace_id: t_err.clone(),
+                    ..Default::default()
                 };
                 if tx_err.send(Ok(chunk)).await.is_err() { break; }
             }
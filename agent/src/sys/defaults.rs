@@ -0,0 +1,103 @@
+use serde::{Deserialize, Serialize};
+use std::os::unix::fs::PermissionsExt;
+use std::path::PathBuf;
+use tokio::sync::RwLock;
+
+/// Platform-wide ceilings from the Brain's SystemProfile (ApplySystemDefaults).
+/// Zero means the Brain has not pushed a ceiling yet.
+#[derive(Debug, Clone, Copy, Default, Serialize, Deserialize)]
+pub struct SystemDefaults {
+    pub max_memory_mb: u32,
+    pub max_cpu_percent: u32,
+    pub profile_version: u32,
+}
+
+impl SystemDefaults {
+    pub fn clamp_memory(&self, mb: u32) -> u32 {
+        if self.max_memory_mb == 0 {
+            mb
+        } else {
+            mb.min(self.max_memory_mb)
+        }
+    }
+
+    pub fn clamp_cpu(&self, percent: u32) -> u32 {
+        if self.max_cpu_percent == 0 {
+            percent
+        } else {
+            percent.min(self.max_cpu_percent)
+        }
+    }
+}
+
+/// The ceilings currently in force, persisted so they survive a Muscle
+/// restart without waiting for the Brain's next reconciliation pass.
+pub struct DefaultsStore {
+    path: PathBuf,
+    current: RwLock<SystemDefaults>,
+}
+
+impl DefaultsStore {
+    /// Loads the stored ceilings; a missing or unreadable file means none.
+    pub fn open(path: PathBuf) -> Self {
+        let current = std::fs::read_to_string(&path)
+            .ok()
+            .and_then(|raw| serde_json::from_str(&raw).ok())
+            .unwrap_or_default();
+        Self {
+            path,
+            current: RwLock::new(current),
+        }
+    }
+
+    pub async fn get(&self) -> SystemDefaults {
+        *self.current.read().await
+    }
+
+    /// Persists first, so the ceilings in memory never run ahead of the file.
+    pub async fn set(&self, defaults: SystemDefaults) -> Result<(), String> {
+        let mut current = self.current.write().await;
+        let raw = serde_json::to_vec(&defaults).map_err(|e| e.to_string())?;
+        if let Some(parent) = self.path.parent() {
+            tokio::fs::create_dir_all(parent)
+                .await
+                .map_err(|e| e.to_string())?;
+        }
+        let tmp = self.path.with_extension("tmp");
+        tokio::fs::write(&tmp, &raw)
+            .await
+            .map_err(|e| e.to_string())?;
+        tokio::fs::set_permissions(&tmp, std::fs::Permissions::from_mode(0o600))
+            .await
+            .map_err(|e| e.to_string())?;
+        tokio::fs::rename(&tmp, &self.path)
+            .await
+            .map_err(|e| e.to_string())?;
+        *current = defaults;
+        Ok(())
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn zero_ceiling_leaves_limits_alone() {
+        let d = SystemDefaults::default();
+        assert_eq!(d.clamp_memory(4096), 4096);
+        assert_eq!(d.clamp_cpu(250), 250);
+    }
+
+    #[test]
+    fn ceilings_cap_limits() {
+        let d = SystemDefaults {
+            max_memory_mb: 1024,
+            max_cpu_percent: 100,
+            profile_version: 3,
+        };
+        assert_eq!(d.clamp_memory(4096), 1024);
+        assert_eq!(d.clamp_memory(512), 512);
+        assert_eq!(d.clamp_cpu(250), 100);
+    }
+}
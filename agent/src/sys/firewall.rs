@@ -13,6 +13,24 @@ use crate::sys::traits::{FirewallAction, FirewallManager, FirewallPolicy, Protoc
 /// Falls back to `iptables` if nftables is unavailable.
 pub struct LinuxFirewallManager;
 
+/// Inbound rules kept open under a deny-by-default policy.
+const BASELINE_RULES: &[&[&str]] = &[
+    &["-i", "lo", "-j", "ACCEPT"],
+    &["-m", "conntrack", "--ctstate", "ESTABLISHED,RELATED", "-j", "ACCEPT"],
+    &["-p", "tcp", "--dport", "22", "-j", "ACCEPT"],
+    &["-p", "tcp", "--dport", "80", "-j", "ACCEPT"],
+    &["-p", "tcp", "--dport", "443", "-j", "ACCEPT"],
+];
+
+async fn iptables(args: &[&str]) -> Result<bool, String> {
+    let output = Command::new("iptables")
+        .args(args)
+        .output()
+        .await
+        .map_err(|e| format!("[SLA ERROR] iptables spawn failed: {}", e))?;
+    Ok(output.status.success())
+}
+
 impl LinuxFirewallManager {
     pub fn new() -> Self {
         Self
@@ -80,6 +98,30 @@ impl FirewallManager for LinuxFirewallManager {
 
         Ok(())
     }
+
+    async fn set_default_inbound(&self, action: FirewallAction) -> Result<(), String> {
+        if action != FirewallAction::Allow {
+            for rule in BASELINE_RULES {
+                // Idempotent: -C finds the rule from an earlier pass
+                let check: Vec<&str> = ["-C", "INPUT"].iter().chain(rule.iter()).copied().collect();
+                if iptables(&check).await? {
+                    continue;
+                }
+                let insert: Vec<&str> = ["-I", "INPUT", "1"].iter().chain(rule.iter()).copied().collect();
+                if !iptables(&insert).await? {
+                    return Err(format!("[SLA ERROR] iptables baseline rule failed: {}", rule.join(" ")));
+                }
+            }
+        }
+
+        // A chain policy cannot be REJECT; deny drops
+        let target = if action == FirewallAction::Allow { "ACCEPT" } else { "DROP" };
+        if !iptables(&["-P", "INPUT", target]).await? {
+            return Err(format!("[SLA ERROR] iptables could not set the INPUT policy to {}", target));
+        }
+        info!("🛡️ Firewall: default inbound policy is now {}", target);
+        Ok(())
+    }
 }
 
 // ==============================================================================
@@ -0,0 +1,77 @@
+use std::path::Path;
+use tokio::process::Command;
+
+/// What this host can run, reported to the Brain via GetCapabilities.
+#[derive(Debug, Default)]
+pub struct HostProfile {
+    pub container_runtime: String,
+    pub php_versions: Vec<String>,
+    pub disk_quotas: bool,
+    pub firewall: String,
+}
+
+/// Probes the host. Every check is read-only and a failed check reads as
+/// "absent", so the Brain never sends work the host cannot do.
+pub async fn probe(php_root: &Path) -> HostProfile {
+    HostProfile {
+        container_runtime: first_installed(&["podman", "docker"])
+            .await
+            .unwrap_or_default(),
+        php_versions: php_versions(php_root).await,
+        disk_quotas: quotas_enabled().await,
+        firewall: match first_installed(&["nft", "iptables"]).await.as_deref() {
+            Some("nft") => "nftables".to_string(),
+            Some(other) => other.to_string(),
+            None => String::new(),
+        },
+    }
+}
+
+async fn first_installed(binaries: &[&str]) -> Option<String> {
+    for bin in binaries {
+        let ok = Command::new(bin)
+            .arg("--version")
+            .output()
+            .await
+            .map(|o| o.status.success())
+            .unwrap_or(false);
+        if ok {
+            return Some(bin.to_string());
+        }
+    }
+    None
+}
+
+/// Versions with an FPM install under php_root ("/etc/php/8.3/fpm"), sorted.
+async fn php_versions(php_root: &Path) -> Vec<String> {
+    let mut versions = Vec::new();
+    let Ok(mut entries) = tokio::fs::read_dir(php_root).await else {
+        return versions;
+    };
+    while let Ok(Some(entry)) = entries.next_entry().await {
+        let name = entry.file_name().to_string_lossy().to_string();
+        let numeric = !name.is_empty() && name.chars().all(|c| c.is_ascii_digit() || c == '.');
+        if numeric && entry.path().join("fpm").is_dir() {
+            versions.push(name);
+        }
+    }
+    versions.sort();
+    versions
+}
+
+async fn quotas_enabled() -> bool {
+    tokio::fs::read_to_string("/proc/mounts")
+        .await
+        .map(|mounts| {
+            mounts.lines().any(|line| {
+                line.split_whitespace()
+                    .nth(3)
+                    .map(|opts| {
+                        opts.split(',')
+                            .any(|o| matches!(o, "quota" | "usrquota" | "grpquota" | "prjquota"))
+                    })
+                    .unwrap_or(false)
+            })
+        })
+        .unwrap_or(false)
+}
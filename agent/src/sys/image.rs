@@ -0,0 +1,102 @@
+use async_trait::async_trait;
+use base64::Engine;
+use base64::engine::general_purpose::STANDARD as BASE64;
+use std::io::Write;
+use std::os::unix::fs::PermissionsExt;
+use tempfile::NamedTempFile;
+use tokio::process::Command;
+use zeroize::Zeroizing;
+
+use crate::sys::secrets::ProviderCredential;
+
+/// Brain-injected registry login for a single pull.
+/// 🛡️ Privacy: The password is only ever held inside a ProviderCredential.
+pub struct RegistryLogin {
+    pub server: String,
+    pub username: String,
+    pub password: ProviderCredential,
+}
+
+#[async_trait]
+pub trait ImageManager: Send + Sync {
+    /// Pulls `image` into the host image store, authenticating when `login` is set.
+    async fn pull(&self, image: &str, login: Option<RegistryLogin>) -> Result<(), String>;
+}
+
+pub struct PodmanImageManager;
+
+impl PodmanImageManager {
+    /// 🛡️ Zero-Trust: repo[:tag][@digest]; no flags, whitespace or shell metacharacters
+    fn validate_image(image: &str) -> Result<(), String> {
+        let valid = !image.is_empty()
+            && image.len() <= 512
+            && !image.starts_with('-')
+            && image.chars().all(|c| c.is_ascii_alphanumeric() || "._-/:@".contains(c));
+        if !valid {
+            return Err(format!("SECURITY VIOLATION: Invalid image reference '{}'", image));
+        }
+        Ok(())
+    }
+
+    /// Writes a containers-auth.json with exactly one entry to a 0600 temp file.
+    /// The file is unlinked when the returned guard drops.
+    fn write_authfile(login: RegistryLogin) -> Result<NamedTempFile, String> {
+        let valid_server = !login.server.is_empty()
+            && login.server.chars().all(|c| c.is_ascii_alphanumeric() || ".-:".contains(c));
+        if !valid_server {
+            return Err(format!("SECURITY VIOLATION: Invalid registry host '{}'", login.server));
+        }
+
+        let mut temp = NamedTempFile::new().map_err(|e| format!("Temp file error: {}", e))?;
+        let mut perms = std::fs::metadata(temp.path()).map_err(|e| e.to_string())?.permissions();
+        perms.set_mode(0o600);
+        std::fs::set_permissions(temp.path(), perms).map_err(|e| e.to_string())?;
+
+        // Lexical confinement: every intermediate copy of the secret is wiped on drop
+        let content = login.password.use_secret(|password| {
+            let pair = Zeroizing::new(format!("{}:{}", login.username, password));
+            let auth = Zeroizing::new(BASE64.encode(pair.as_bytes()));
+            Zeroizing::new(serde_json::json!({
+                "auths": { login.server.as_str(): { "auth": auth.as_str() } }
+            }).to_string())
+        });
+        login.password.destroy();
+
+        temp.write_all(content.as_bytes()).map_err(|e| format!("Failed to write authfile: {}", e))?;
+        temp.as_file().sync_all().map_err(|e| e.to_string())?;
+        Ok(temp)
+    }
+}
+
+#[async_trait]
+impl ImageManager for PodmanImageManager {
+    async fn pull(&self, image: &str, login: Option<RegistryLogin>) -> Result<(), String> {
+        Self::validate_image(image)?;
+
+        // Held until the pull exits; NamedTempFile unlinks on drop
+        let authfile = login.map(Self::write_authfile).transpose()?;
+
+        let mut cmd = Command::new("podman");
+        cmd.arg("pull").arg("--quiet");
+        match &authfile {
+            Some(f) => { cmd.arg("--authfile").arg(f.path()); }
+            // 🛡️ Never fall back to whatever root happens to be logged in to
+            None => { cmd.arg("--authfile").arg("/dev/null"); }
+        }
+        let output = cmd
+            .arg("--")
+            .arg(image)
+            .env("REGISTRY_AUTH_FILE", "/dev/null")
+            .kill_on_drop(true)
+            .output()
+            .await
+            .map_err(|e| format!("Podman spawn error: {}", e))?;
+
+        drop(authfile);
+
+        if !output.status.success() {
+            return Err(format!("Image pull failed: {}", String::from_utf8_lossy(&output.stderr).trim()));
+        }
+        Ok(())
+    }
+}
@@ -13,6 +13,9 @@ pub trait JailManager: Send + Sync {
     
     /// Locks down a directory safely, avoiding TOCTOU symlink races
     async fn secure_directory(&self, path: &Path, username: &str) -> Result<(), String>;
+
+    /// Every app jail user (kari-app-*) in the system's user database
+    async fn list_app_users(&self) -> Result<Vec<String>, String>;
 }
 
 pub struct LinuxJailManager;
@@ -123,4 +126,23 @@ impl JailManager for LinuxJailManager {
 
         Ok(())
     }
+    async fn list_app_users(&self) -> Result<Vec<String>, String> {
+        // getent also covers users from NSS sources other than /etc/passwd
+        let output = Command::new("getent")
+            .arg("passwd")
+            .output()
+            .await
+            .map_err(|e| format!("Failed to spawn getent: {}", e))?;
+
+        if !output.status.success() {
+            return Err(format!("Failed to list users: {}", String::from_utf8_lossy(&output.stderr)));
+        }
+
+        Ok(String::from_utf8_lossy(&output.stdout)
+            .lines()
+            .filter_map(|line| line.split(':').next())
+            .filter(|name| name.starts_with("kari-app-"))
+            .map(str::to_string)
+            .collect())
+    }
 }
@@ -0,0 +1,101 @@
+use std::collections::{HashSet, VecDeque};
+use std::path::PathBuf;
+use tokio::io::AsyncWriteExt;
+use tokio::sync::Mutex;
+use tonic::Request;
+
+/// gRPC metadata key carrying the Brain's outbox entry ID.
+pub const CHANGE_ID_KEY: &str = "x-kari-change-id";
+
+// 🛡️ SLA: The Brain retries one change at a time, in order, so only recent
+// IDs can ever be redelivered; the file is compacted past this many.
+const MAX_REMEMBERED: usize = 10_000;
+
+/// Applied-change ledger. The Brain's outbox keeps resending a change until
+/// it records the ack, so a change can arrive again after a Brain crash; the
+/// ledger lets the Muscle acknowledge it without applying it twice. IDs are
+/// appended to a root-only file so the guarantee survives a Muscle restart.
+pub struct ChangeLedger {
+    path: PathBuf,
+    state: Mutex<LedgerState>,
+}
+
+#[derive(Default)]
+struct LedgerState {
+    order: VecDeque<String>,
+    seen: HashSet<String>,
+}
+
+impl LedgerState {
+    fn remember(&mut self, id: String) {
+        if self.seen.insert(id.clone()) {
+            self.order.push_back(id);
+        }
+        while self.order.len() > MAX_REMEMBERED {
+            if let Some(old) = self.order.pop_front() {
+                self.seen.remove(&old);
+            }
+        }
+    }
+}
+
+impl ChangeLedger {
+    /// Loads the ledger; a missing or unreadable file starts it empty.
+    pub fn open(path: PathBuf) -> Self {
+        let mut state = LedgerState::default();
+        if let Ok(raw) = std::fs::read_to_string(&path) {
+            for line in raw.lines().filter(|l| valid_change_id(l)) {
+                state.remember(line.to_string());
+            }
+        }
+        Self { path, state: Mutex::new(state) }
+    }
+
+    pub async fn contains(&self, id: &str) -> bool {
+        self.state.lock().await.seen.contains(id)
+    }
+
+    /// Records an applied change. The file is rewritten from memory once it
+    /// holds twice the remembered window.
+    pub async fn record(&self, id: &str) -> Result<(), String> {
+        let mut state = self.state.lock().await;
+        state.remember(id.to_string());
+
+        if let Some(parent) = self.path.parent() {
+            tokio::fs::create_dir_all(parent).await.map_err(|e| e.to_string())?;
+        }
+        let on_disk = tokio::fs::metadata(&self.path).await.map(|m| m.len()).unwrap_or(0);
+        // A UUID line is 37 bytes
+        if on_disk > (2 * MAX_REMEMBERED * 37) as u64 {
+            let mut body = state.order.iter().cloned().collect::<Vec<_>>().join("\n");
+            body.push('\n');
+            let tmp = self.path.with_extension("tmp");
+            tokio::fs::write(&tmp, body).await.map_err(|e| e.to_string())?;
+            return tokio::fs::rename(&tmp, &self.path).await.map_err(|e| e.to_string());
+        }
+
+        let mut file = tokio::fs::OpenOptions::new()
+            .create(true)
+            .append(true)
+            .mode(0o600)
+            .open(&self.path)
+            .await
+            .map_err(|e| e.to_string())?;
+        file.write_all(format!("{}\n", id).as_bytes()).await.map_err(|e| e.to_string())
+    }
+}
+
+/// Extracts the outbox change ID from a request, if the Brain sent one.
+pub fn change_id<T>(request: &Request<T>) -> Option<String> {
+    request
+        .metadata()
+        .get(CHANGE_ID_KEY)
+        .and_then(|v| v.to_str().ok())
+        .filter(|id| valid_change_id(id))
+        .map(str::to_string)
+}
+
+// 🛡️ Zero-Trust: IDs are written to disk verbatim, so only UUID shapes pass
+fn valid_change_id(id: &str) -> bool {
+    id.len() == 36 && id.chars().all(|c| c.is_ascii_hexdigit() || c == '-')
+}
@@ -12,6 +12,16 @@ pub mod ssl;        // Certificate management
 pub mod scheduler;  // Cron/Timer scheduling
 pub mod logs;       // Log management
 pub mod firewall;   // Network policy enforcement
+pub mod php_fpm;    // Per-app PHP-FPM pools
+pub mod image;      // Container image pulls (podman)
+pub mod scanner;    // File integrity baselines & malware scanning
+pub mod access_log; // Per-domain access log aggregation
+pub mod ledger;     // Applied outbox changes (exactly-once redelivery)
+pub mod artifact;   // Release tarballs for rebuild-free redeploys
+pub mod sbom;       // Dependency catalogs (CycloneDX via syft)
+pub mod host;       // Host capability discovery
+pub mod defaults;   // SystemProfile ceilings pushed by the Brain
+pub mod sealing;    // Machine-bound secrets (systemd-creds)
 
 // 🏗️ SLA Re-exports
 // We re-export common types so server.rs doesn't have deep nested imports.
@@ -0,0 +1,148 @@
+use async_trait::async_trait;
+use tokio::fs;
+use tokio::process::Command;
+use std::path::PathBuf;
+
+/// Upper bound for pm.max_children, mirrored by the Brain's validator.
+pub const MAX_PHP_CHILDREN: u32 = 200;
+
+/// 🛡️ Zero-Trust: Discrete fields; nothing here is interpolated unchecked.
+pub struct PhpPoolConfig {
+    pub app_id: String,
+    pub version: String,        // Stack registry version, e.g. "8.3"
+    pub app_user: String,       // Pool runs as the app's jail identity
+    pub doc_root: PathBuf,
+    pub max_children: u32,
+}
+
+#[async_trait]
+pub trait PhpFpmManager: Send + Sync {
+    /// Writes (or replaces) the app's dedicated pool and reloads that PHP version's FPM.
+    /// Returns the pool's listen socket for the vhost.
+    async fn write_pool(&self, pool: &PhpPoolConfig) -> Result<PathBuf, String>;
+
+    /// Removes the app's pool from every installed PHP version.
+    async fn remove_pool(&self, app_id: &str) -> Result<(), String>;
+}
+
+pub struct LinuxPhpFpmManager {
+    php_root: PathBuf,
+}
+
+impl LinuxPhpFpmManager {
+    pub fn new(php_root: PathBuf) -> Self {
+        Self { php_root }
+    }
+
+    pub fn socket_path(app_id: &str) -> PathBuf {
+        PathBuf::from(format!("/run/php/kari-{}.sock", app_id))
+    }
+
+    fn pool_path(&self, version: &str, app_id: &str) -> PathBuf {
+        self.php_root.join(version).join("fpm").join("pool.d").join(format!("kari-{}.conf", app_id))
+    }
+
+    /// 🛡️ Zero-Trust: Only "major.minor" reaches paths and unit names
+    fn validate_version(version: &str) -> Result<(), String> {
+        let mut parts = version.split('.');
+        let valid = matches!((parts.next(), parts.next(), parts.next()), (Some(major), Some(minor), None)
+            if !major.is_empty() && !minor.is_empty()
+                && major.chars().all(|c| c.is_ascii_digit())
+                && minor.chars().all(|c| c.is_ascii_digit()));
+        if !valid {
+            return Err(format!("SECURITY VIOLATION: Invalid PHP version '{}'", version));
+        }
+        Ok(())
+    }
+
+    async fn test_and_reload(&self, version: &str) -> Result<(), String> {
+        let check = Command::new(format!("php-fpm{}", version)).arg("-t").output().await
+            .map_err(|e| format!("PHP-FPM check failed: {}", e))?;
+
+        if !check.status.success() {
+            return Err(format!("PHP-FPM config error: {}", String::from_utf8_lossy(&check.stderr)));
+        }
+
+        Command::new("systemctl").args(["reload", &format!("php{}-fpm", version)]).output().await
+            .map_err(|e| format!("Systemd reload failed: {}", e))?;
+        Ok(())
+    }
+}
+
+#[async_trait]
+impl PhpFpmManager for LinuxPhpFpmManager {
+    async fn write_pool(&self, pool: &PhpPoolConfig) -> Result<PathBuf, String> {
+        // 1. 🛡️ Zero-Trust Input Validation
+        Self::validate_version(&pool.version)?;
+        if pool.app_id.is_empty() || !pool.app_id.chars().all(|c| c.is_ascii_alphanumeric() || c == '-') {
+            return Err(format!("SECURITY VIOLATION: Invalid app_id '{}'", pool.app_id));
+        }
+        if !pool.app_user.starts_with("kari-") {
+            return Err("SECURITY VIOLATION: Refusing to run a pool as a non-Kari user".into());
+        }
+        if pool.max_children == 0 || pool.max_children > MAX_PHP_CHILDREN {
+            return Err(format!("pm.max_children must be between 1 and {}", MAX_PHP_CHILDREN));
+        }
+
+        // 2. 🛡️ Tenant Isolation: One pool per app, jailed to its own tree.
+        // ondemand keeps idle sites at zero workers.
+        let socket = Self::socket_path(&pool.app_id);
+        let doc_root = pool.doc_root.display();
+        let content = format!(
+            r#"[kari-{app_id}]
+user = {user}
+group = {user}
+listen = {socket}
+listen.owner = www-data
+listen.group = www-data
+listen.mode = 0660
+
+pm = ondemand
+pm.max_children = {max_children}
+pm.process_idle_timeout = 10s
+pm.max_requests = 500
+
+chdir = {doc_root}
+php_admin_value[open_basedir] = {doc_root}:/tmp
+php_admin_value[disable_functions] = exec,passthru,shell_exec,system,proc_open,popen
+"#,
+            app_id = pool.app_id,
+            user = pool.app_user,
+            socket = socket.display(),
+            max_children = pool.max_children,
+            doc_root = doc_root,
+        );
+
+        let path = self.pool_path(&pool.version, &pool.app_id);
+        let previous = fs::read(&path).await.ok();
+        fs::write(&path, content).await.map_err(|e| format!("Failed to write pool: {}", e))?;
+
+        // 3. 🛡️ Stability: Never leave a broken pool behind to take down every site on this version
+        if let Err(e) = self.test_and_reload(&pool.version).await {
+            match previous {
+                Some(old) => { let _ = fs::write(&path, old).await; }
+                None => { let _ = fs::remove_file(&path).await; }
+            }
+            return Err(e);
+        }
+        Ok(socket)
+    }
+
+    async fn remove_pool(&self, app_id: &str) -> Result<(), String> {
+        let mut versions = fs::read_dir(&self.php_root).await
+            .map_err(|e| format!("Failed to scan PHP versions: {}", e))?;
+
+        while let Ok(Some(entry)) = versions.next_entry().await {
+            let version = entry.file_name().to_string_lossy().to_string();
+            if Self::validate_version(&version).is_err() {
+                continue;
+            }
+            let path = self.pool_path(&version, app_id);
+            if path.exists() {
+                fs::remove_file(&path).await.map_err(|e| e.to_string())?;
+                self.test_and_reload(&version).await?;
+            }
+        }
+        Ok(())
+    }
+}
@@ -4,6 +4,95 @@ use tokio::process::Command;
 use std::path::{Path, PathBuf};
 use crate::sys::traits::ProxyManager;
 
+// Per-domain access logs, read back by CollectAccessLogs. "kari_combined" is the
+// stock combined format plus the request size, for bandwidth-in accounting.
+const APACHE_LOG_DIR: &str = "/var/log/apache2";
+const NGINX_LOG_DIR: &str = "/var/log/nginx";
+const LOG_FORMAT_FILE: &str = "kari-log-format.conf";
+// %I/%O need mod_logio, which the stock Debian "combined" format already relies on
+const APACHE_LOG_FORMAT: &str = r#"LogFormat "%h %l %u %t \"%r\" %>s %O \"%{Referer}i\" \"%{User-Agent}i\" %I" kari_combined
+"#;
+const NGINX_LOG_FORMAT: &str = r#"log_format kari_combined '$remote_addr - $remote_user [$time_local] "$request" '
+                         '$status $body_bytes_sent "$http_referer" "$http_user_agent" $request_length';
+"#;
+
+// ==============================================================================
+// 0. Per-Domain Include Snippets (headers, throttling; shared by both proxies)
+// ==============================================================================
+
+// 🛡️ Zero-Trust: The Brain validates too, but only these names ever reach a vhost
+const ALLOWED_HEADERS: &[&str] = &[
+    "Content-Security-Policy",
+    "X-Frame-Options",
+    "Referrer-Policy",
+    "Permissions-Policy",
+];
+const MAX_HEADER_VALUE_LEN: usize = 4096;
+
+/// Rejects anything that could break out of a quoted directive argument.
+fn validate_headers(headers: &[(String, String)]) -> Result<(), String> {
+    for (name, value) in headers {
+        if !ALLOWED_HEADERS.contains(&name.as_str()) {
+            return Err(format!("SECURITY VIOLATION: Header {} is not managed by Kari", name));
+        }
+        if value.len() > MAX_HEADER_VALUE_LEN
+            || !value.chars().all(|c| (' '..='~').contains(&c) && !"\"\\$%".contains(c))
+        {
+            return Err(format!("SECURITY VIOLATION: Invalid value for {}", name));
+        }
+    }
+    Ok(())
+}
+
+/// Writes (or, when `lines` is empty, removes) one of the domain's include
+/// snippets, which every vhost template pulls in. Returns the previous content
+/// for rollback.
+async fn write_snippet(dir: &Path, file: &str, lines: String) -> Result<Option<String>, String> {
+    let path = dir.join(file);
+    let previous = fs::read_to_string(&path).await.ok();
+    if lines.is_empty() {
+        let _ = fs::remove_file(&path).await;
+    } else {
+        fs::create_dir_all(dir).await.map_err(|e| format!("Failed to create include dir: {}", e))?;
+        fs::write(&path, lines).await.map_err(|e| format!("Failed to write {}: {}", file, e))?;
+    }
+    Ok(previous)
+}
+
+async fn restore_snippet(dir: &Path, file: &str, previous: Option<String>) {
+    let path = dir.join(file);
+    match previous {
+        Some(content) => { let _ = fs::write(&path, content).await; }
+        None => { let _ = fs::remove_file(&path).await; }
+    }
+}
+
+/// 🛡️ Zero-Trust: The domain becomes a directory name
+fn checked_headers_dir(base: &Path, domain: &str) -> Result<PathBuf, String> {
+    if domain.is_empty() || domain.contains("..") || !domain.chars().all(|c| c.is_ascii_alphanumeric() || c == '.' || c == '-') {
+        return Err("SECURITY VIOLATION: Invalid domain for header snippet".into());
+    }
+    Ok(base.join("kari-headers").join(domain))
+}
+
+/// Domains of the vhosts in `sites-enabled` that Kari rendered. Every Kari
+/// template logs in "kari_combined", which is what tells them apart from
+/// vhosts the operator wrote by hand.
+async fn list_kari_vhosts(base: &Path, suffix: &str) -> Result<Vec<String>, String> {
+    let mut entries = fs::read_dir(base.join("sites-enabled")).await
+        .map_err(|e| format!("Failed to scan vhosts: {}", e))?;
+    let mut domains = Vec::new();
+    while let Ok(Some(entry)) = entries.next_entry().await {
+        let file = entry.file_name().to_string_lossy().to_string();
+        let Some(domain) = file.strip_suffix(suffix) else { continue };
+        let content = fs::read_to_string(entry.path()).await.unwrap_or_default();
+        if content.contains("kari_combined") {
+            domains.push(domain.to_string());
+        }
+    }
+    Ok(domains)
+}
+
 // ==============================================================================
 // 1. Apache Implementation
 // ==============================================================================
@@ -16,6 +105,19 @@ impl ApacheManager {
         Self { base_path }
     }
 
+    fn headers_dir(&self, domain: &str) -> PathBuf {
+        self.base_path.join("kari-headers").join(domain)
+    }
+
+    /// "kari_combined" must be defined before any vhost references it.
+    async fn ensure_log_format(&self) -> Result<(), String> {
+        let path = self.base_path.join("conf-enabled").join(LOG_FORMAT_FILE);
+        if fs::read_to_string(&path).await.ok().as_deref() == Some(APACHE_LOG_FORMAT) {
+            return Ok(());
+        }
+        fs::write(&path, APACHE_LOG_FORMAT).await.map_err(|e| format!("Failed to write log format: {}", e))
+    }
+
     async fn test_and_reload(&self) -> Result<(), String> {
         let check = Command::new("apache2ctl").arg("configtest").output().await
             .map_err(|e| format!("Apache check failed: {}", e))?;
@@ -32,6 +134,10 @@ impl ApacheManager {
 
 #[async_trait]
 impl ProxyManager for ApacheManager {
+    fn access_log_path(&self, domain: &str) -> PathBuf {
+        Path::new(APACHE_LOG_DIR).join(format!("{}.access.log", domain))
+    }
+
     async fn create_vhost(&self, domain: &str, target_port: u16) -> Result<(), String> {
         let config_path = self.base_path.join("sites-available").join(format!("{}.conf", domain));
         let enabled_link = self.base_path.join("sites-enabled").join(format!("{}.conf", domain));
@@ -43,10 +149,85 @@ impl ProxyManager for ApacheManager {
     ProxyPass / http://127.0.0.1:{target_port}/
     ProxyPassReverse / http://127.0.0.1:{target_port}/
     Header always set X-Content-Type-Options "nosniff"
+    CustomLog {access_log} kari_combined
+    IncludeOptional {headers}/*.conf
+</VirtualHost>"#,
+            domain = domain, target_port = target_port,
+            headers = self.headers_dir(domain).display(),
+            access_log = self.access_log_path(domain).display()
+        );
+
+        self.ensure_log_format().await?;
+        fs::write(&config_path, content).await.map_err(|e| e.to_string())?;
+        if !enabled_link.exists() {
+            fs::symlink(&config_path, &enabled_link).await.map_err(|e| e.to_string())?;
+        }
+        self.test_and_reload().await
+    }
+
+    async fn create_balanced_vhost(&self, domain: &str, ports: &[u16]) -> Result<(), String> {
+        if ports.is_empty() {
+            return Err("Balanced vhost needs at least one backend".into());
+        }
+        let config_path = self.base_path.join("sites-available").join(format!("{}.conf", domain));
+        let enabled_link = self.base_path.join("sites-enabled").join(format!("{}.conf", domain));
+
+        // Requires mod_proxy_balancer + mod_lbmethod_byrequests
+        let members: String = ports.iter()
+            .map(|p| format!("        BalancerMember http://127.0.0.1:{} retry=10\n", p))
+            .collect();
+        let content = format!(
+            r#"<VirtualHost *:80>
+    ServerName {domain}
+    ProxyPreserveHost On
+    <Proxy "balancer://kari-{domain}">
+{members}        ProxySet lbmethod=byrequests
+    </Proxy>
+    ProxyPass / balancer://kari-{domain}/
+    ProxyPassReverse / balancer://kari-{domain}/
+    Header always set X-Content-Type-Options "nosniff"
+    CustomLog {access_log} kari_combined
+    IncludeOptional {headers}/*.conf
+</VirtualHost>"#,
+            domain = domain, members = members,
+            headers = self.headers_dir(domain).display(),
+            access_log = self.access_log_path(domain).display()
+        );
+
+        self.ensure_log_format().await?;
+        fs::write(&config_path, content).await.map_err(|e| e.to_string())?;
+        if !enabled_link.exists() {
+            fs::symlink(&config_path, &enabled_link).await.map_err(|e| e.to_string())?;
+        }
+        self.test_and_reload().await
+    }
+
+    async fn create_php_vhost(&self, domain: &str, fpm_socket: &Path, doc_root: &Path) -> Result<(), String> {
+        let config_path = self.base_path.join("sites-available").join(format!("{}.conf", domain));
+        let enabled_link = self.base_path.join("sites-enabled").join(format!("{}.conf", domain));
+
+        let content = format!(
+            r#"<VirtualHost *:80>
+    ServerName {domain}
+    DocumentRoot {doc_root}
+    DirectoryIndex index.php index.html
+    <Directory {doc_root}>
+        AllowOverride All
+        Require all granted
+    </Directory>
+    <FilesMatch "\.php$">
+        SetHandler "proxy:unix:{socket}|fcgi://localhost"
+    </FilesMatch>
+    Header always set X-Content-Type-Options "nosniff"
+    CustomLog {access_log} kari_combined
+    IncludeOptional {headers}/*.conf
 </VirtualHost>"#,
-            domain = domain, target_port = target_port
+            domain = domain, doc_root = doc_root.display(), socket = fpm_socket.display(),
+            headers = self.headers_dir(domain).display(),
+            access_log = self.access_log_path(domain).display()
         );
 
+        self.ensure_log_format().await?;
         fs::write(&config_path, content).await.map_err(|e| e.to_string())?;
         if !enabled_link.exists() {
             fs::symlink(&config_path, &enabled_link).await.map_err(|e| e.to_string())?;
@@ -61,6 +242,42 @@ impl ProxyManager for ApacheManager {
         let _ = fs::remove_file(config_path).await;
         self.test_and_reload().await
     }
+
+    async fn list_vhosts(&self) -> Result<Vec<String>, String> {
+        list_kari_vhosts(&self.base_path, ".conf").await
+    }
+
+    async fn apply_headers(&self, domain: &str, headers: &[(String, String)]) -> Result<(), String> {
+        validate_headers(headers)?;
+        let dir = checked_headers_dir(&self.base_path, domain)?;
+        let lines: String = headers.iter()
+            .map(|(name, value)| format!("Header always set {} \"{}\"\n", name, value))
+            .collect();
+
+        let previous = write_snippet(&dir, "headers.conf", lines).await?;
+        if let Err(e) = self.test_and_reload().await {
+            restore_snippet(&dir, "headers.conf", previous).await;
+            return Err(e);
+        }
+        Ok(())
+    }
+
+    async fn set_rate_limit(&self, domain: &str, kbps: u32) -> Result<(), String> {
+        let dir = checked_headers_dir(&self.base_path, domain)?;
+        // mod_ratelimit; "rate-limit" is in KiB/s
+        let lines = if kbps == 0 {
+            String::new()
+        } else {
+            format!("SetOutputFilter RATE_LIMIT\nSetEnv rate-limit {}\n", kbps)
+        };
+
+        let previous = write_snippet(&dir, "throttle.conf", lines).await?;
+        if let Err(e) = self.test_and_reload().await {
+            restore_snippet(&dir, "throttle.conf", previous).await;
+            return Err(e);
+        }
+        Ok(())
+    }
 }
 
 // ==============================================================================
@@ -75,6 +292,19 @@ impl NginxManager {
         Self { base_path }
     }
 
+    fn headers_dir(&self, domain: &str) -> PathBuf {
+        self.base_path.join("kari-headers").join(domain)
+    }
+
+    /// "kari_combined" must be defined before any vhost references it.
+    async fn ensure_log_format(&self) -> Result<(), String> {
+        let path = self.base_path.join("conf.d").join(LOG_FORMAT_FILE);
+        if fs::read_to_string(&path).await.ok().as_deref() == Some(NGINX_LOG_FORMAT) {
+            return Ok(());
+        }
+        fs::write(&path, NGINX_LOG_FORMAT).await.map_err(|e| format!("Failed to write log format: {}", e))
+    }
+
     async fn test_and_reload(&self) -> Result<(), String> {
         let check = Command::new("nginx").arg("-t").output().await
             .map_err(|e| format!("Nginx check failed: {}", e))?;
@@ -91,6 +321,10 @@ impl NginxManager {
 
 #[async_trait]
 impl ProxyManager for NginxManager {
+    fn access_log_path(&self, domain: &str) -> PathBuf {
+        Path::new(NGINX_LOG_DIR).join(format!("{}.access.log", domain))
+    }
+
     async fn create_vhost(&self, domain: &str, target_port: u16) -> Result<(), String> {
         let config_path = self.base_path.join("sites-available").join(domain);
         let enabled_link = self.base_path.join("sites-enabled").join(domain);
@@ -99,17 +333,105 @@ impl ProxyManager for NginxManager {
             r#"server {{
     listen 80;
     server_name {domain};
+    access_log {access_log} kari_combined;
 
     location / {{
         proxy_pass http://127.0.0.1:{target_port};
         proxy_set_header Host $host;
         proxy_set_header X-Real-IP $remote_addr;
         add_header X-Content-Type-Options "nosniff" always;
+        include {headers}/*.conf;
+    }}
+}}"#,
+            domain = domain, target_port = target_port,
+            headers = self.headers_dir(domain).display(),
+            access_log = self.access_log_path(domain).display()
+        );
+
+        self.ensure_log_format().await?;
+        fs::write(&config_path, content).await.map_err(|e| e.to_string())?;
+        if !enabled_link.exists() {
+            fs::symlink(&config_path, &enabled_link).await.map_err(|e| e.to_string())?;
+        }
+        self.test_and_reload().await
+    }
+
+    async fn create_balanced_vhost(&self, domain: &str, ports: &[u16]) -> Result<(), String> {
+        if ports.is_empty() {
+            return Err("Balanced vhost needs at least one backend".into());
+        }
+        let config_path = self.base_path.join("sites-available").join(domain);
+        let enabled_link = self.base_path.join("sites-enabled").join(domain);
+
+        // Upstream names are nginx identifiers; dots and dashes are not allowed
+        let upstream = format!("kari_{}", domain.replace(['.', '-'], "_"));
+        let servers: String = ports.iter()
+            .map(|p| format!("    server 127.0.0.1:{} max_fails=3 fail_timeout=10s;\n", p))
+            .collect();
+        let content = format!(
+            r#"upstream {upstream} {{
+    least_conn;
+{servers}}}
+
+server {{
+    listen 80;
+    server_name {domain};
+    access_log {access_log} kari_combined;
+
+    location / {{
+        proxy_pass http://{upstream};
+        proxy_next_upstream error timeout http_502 http_503;
+        proxy_set_header Host $host;
+        proxy_set_header X-Real-IP $remote_addr;
+        add_header X-Content-Type-Options "nosniff" always;
+        include {headers}/*.conf;
+    }}
+}}"#,
+            upstream = upstream, servers = servers, domain = domain,
+            headers = self.headers_dir(domain).display(),
+            access_log = self.access_log_path(domain).display()
+        );
+
+        self.ensure_log_format().await?;
+        fs::write(&config_path, content).await.map_err(|e| e.to_string())?;
+        if !enabled_link.exists() {
+            fs::symlink(&config_path, &enabled_link).await.map_err(|e| e.to_string())?;
+        }
+        self.test_and_reload().await
+    }
+
+    async fn create_php_vhost(&self, domain: &str, fpm_socket: &Path, doc_root: &Path) -> Result<(), String> {
+        let config_path = self.base_path.join("sites-available").join(domain);
+        let enabled_link = self.base_path.join("sites-enabled").join(domain);
+
+        let content = format!(
+            r#"server {{
+    listen 80;
+    server_name {domain};
+    access_log {access_log} kari_combined;
+    root {doc_root};
+    index index.php index.html;
+
+    location / {{
+        try_files $uri $uri/ /index.php?$args;
+        add_header X-Content-Type-Options "nosniff" always;
+        include {headers}/*.conf;
+    }}
+
+    location ~ \.php$ {{
+        try_files $uri =404;
+        include fastcgi_params;
+        fastcgi_param SCRIPT_FILENAME $document_root$fastcgi_script_name;
+        fastcgi_pass unix:{socket};
+        include {headers}/*.conf;
     }}
 }}"#,
-            domain = domain, target_port = target_port
+            domain = domain, doc_root = doc_root.display(), socket = fpm_socket.display(),
+            headers = self.headers_dir(domain).display(),
+            access_log = self.access_log_path(domain).display()
         );
 
+        self.ensure_log_format().await?;
         fs::write(&config_path, content).await.map_err(|e| e.to_string())?;
         if !enabled_link.exists() {
             fs::symlink(&config_path, &enabled_link).await.map_err(|e| e.to_string())?;
@@ -124,4 +446,36 @@ impl ProxyManager for NginxManager {
         let _ = fs::remove_file(config_path).await;
         self.test_and_reload().await
     }
+
+    async fn list_vhosts(&self) -> Result<Vec<String>, String> {
+        list_kari_vhosts(&self.base_path, "").await
+    }
+
+    async fn apply_headers(&self, domain: &str, headers: &[(String, String)]) -> Result<(), String> {
+        validate_headers(headers)?;
+        let dir = checked_headers_dir(&self.base_path, domain)?;
+        let lines: String = headers.iter()
+            .map(|(name, value)| format!("add_header {} \"{}\" always;\n", name, value))
+            .collect();
+
+        let previous = write_snippet(&dir, "headers.conf", lines).await?;
+        if let Err(e) = self.test_and_reload().await {
+            restore_snippet(&dir, "headers.conf", previous).await;
+            return Err(e);
+        }
+        Ok(())
+    }
+
+    async fn set_rate_limit(&self, domain: &str, kbps: u32) -> Result<(), String> {
+        let dir = checked_headers_dir(&self.base_path, domain)?;
+        // Per-connection cap, applied in every location that includes the snippet
+        let lines = if kbps == 0 { String::new() } else { format!("limit_rate {}k;\n", kbps) };
+
+        let previous = write_snippet(&dir, "throttle.conf", lines).await?;
+        if let Err(e) = self.test_and_reload().await {
+            restore_snippet(&dir, "throttle.conf", previous).await;
+            return Err(e);
+        }
+        Ok(())
+    }
 }
@@ -0,0 +1,94 @@
+use async_trait::async_trait;
+use std::path::Path;
+use std::time::Duration;
+use tokio::process::Command;
+
+// 🛡️ SLA: One pathological dependency tree must not stall the deploy or
+// overflow the gRPC message it travels back in
+const MAX_COMPONENTS: usize = 10_000;
+const SYFT_TIMEOUT: Duration = Duration::from_secs(300);
+
+/// One dependency cataloged from a built release.
+#[derive(Debug, Clone, Default)]
+pub struct SbomComponent {
+    pub name: String,
+    pub version: String,
+    pub purl: String,          // Package URL, the key vulnerability feeds match on
+    pub licenses: Vec<String>, // SPDX IDs or expressions, else free-form names
+}
+
+#[derive(Debug, Default)]
+pub struct Sbom {
+    pub generator: String,
+    pub components: Vec<SbomComponent>,
+    pub truncated: bool,
+}
+
+#[async_trait]
+pub trait SbomGenerator: Send + Sync {
+    /// Catalogs the dependencies of a built release. Ok(None) when no
+    /// generator is installed on this host.
+    async fn generate(&self, release_dir: &Path) -> Result<Option<Sbom>, String>;
+}
+
+/// Runs `syft` and reduces its CycloneDX output to the fields the Brain keeps.
+pub struct SyftSbomGenerator;
+
+impl SyftSbomGenerator {
+    fn parse(raw: &[u8]) -> Result<Sbom, String> {
+        let doc: serde_json::Value = serde_json::from_slice(raw)
+            .map_err(|e| format!("Invalid CycloneDX output: {}", e))?;
+
+        let generator = doc["metadata"]["tools"]["components"][0]["name"].as_str()
+            .or_else(|| doc["metadata"]["tools"][0]["name"].as_str())
+            .unwrap_or("syft")
+            .to_string();
+
+        let mut sbom = Sbom { generator, ..Default::default() };
+        for c in doc["components"].as_array().map(|a| a.as_slice()).unwrap_or(&[]) {
+            if sbom.components.len() == MAX_COMPONENTS {
+                sbom.truncated = true;
+                break;
+            }
+            let licenses = c["licenses"].as_array().map(|a| a.as_slice()).unwrap_or(&[])
+                .iter()
+                .filter_map(|l| {
+                    l["expression"].as_str()
+                        .or_else(|| l["license"]["id"].as_str())
+                        .or_else(|| l["license"]["name"].as_str())
+                        .map(str::to_string)
+                })
+                .collect();
+            sbom.components.push(SbomComponent {
+                name: c["name"].as_str().unwrap_or_default().to_string(),
+                version: c["version"].as_str().unwrap_or_default().to_string(),
+                purl: c["purl"].as_str().unwrap_or_default().to_string(),
+                licenses,
+            });
+        }
+        Ok(sbom)
+    }
+}
+
+#[async_trait]
+impl SbomGenerator for SyftSbomGenerator {
+    async fn generate(&self, release_dir: &Path) -> Result<Option<Sbom>, String> {
+        let mut cmd = Command::new("syft");
+        cmd.arg("scan")
+            .arg(format!("dir:{}", release_dir.display()))
+            .arg("--output").arg("cyclonedx-json")
+            .arg("--quiet")
+            .kill_on_drop(true);
+
+        let output = match tokio::time::timeout(SYFT_TIMEOUT, cmd.output()).await {
+            Err(_) => return Err("syft timed out".into()),
+            Ok(Err(e)) if e.kind() == std::io::ErrorKind::NotFound => return Ok(None),
+            Ok(Err(e)) => return Err(format!("Failed to run syft: {}", e)),
+            Ok(Ok(output)) => output,
+        };
+        if !output.status.success() {
+            return Err(format!("syft failed: {}", String::from_utf8_lossy(&output.stderr).trim()));
+        }
+        Self::parse(&output.stdout).map(Some)
+    }
+}
@@ -0,0 +1,195 @@
+use async_trait::async_trait;
+use sha2::{Digest, Sha256};
+use std::collections::BTreeMap;
+use std::io::Read;
+use std::os::unix::fs::PermissionsExt;
+use std::path::{Path, PathBuf};
+use tokio::process::Command;
+
+// 🛡️ SLA: Bounds keep one pathological tree from stalling the Muscle
+const MAX_SCAN_FILES: usize = 200_000;
+
+/// One deviation from the app's known-good state.
+#[derive(Debug, Clone)]
+pub struct ScanFinding {
+    pub path: String,       // Relative to the scanned root
+    pub kind: &'static str, // modified, added, removed, infected
+    pub detail: String,     // Signature name for infected files
+}
+
+#[derive(Debug, Default)]
+pub struct ScanReport {
+    pub files_scanned: u64,
+    pub baseline_created: bool,
+    pub findings: Vec<ScanFinding>,
+}
+
+#[async_trait]
+pub trait FileScanner: Send + Sync {
+    /// Diffs `root` against the app's stored hash baseline (creating it on the
+    /// first run, or replacing it when `rebaseline` is set) and optionally runs
+    /// ClamAV over the whole tree. Paths under `exclude` skip the hash diff only.
+    async fn scan(&self, app_id: &str, root: &Path, rebaseline: bool, clamav: bool, exclude: &[String]) -> Result<ScanReport, String>;
+    /// Accepts the current tree as known-good (after a deploy).
+    async fn rebaseline(&self, app_id: &str, root: &Path, exclude: &[String]) -> Result<(), String>;
+}
+
+/// SHA-256 integrity baselines stored outside every app jail, plus clamscan.
+pub struct IntegrityScanner {
+    baseline_dir: PathBuf,
+}
+
+impl IntegrityScanner {
+    pub fn new(baseline_dir: PathBuf) -> Self {
+        Self { baseline_dir }
+    }
+
+    /// 🛡️ Zero-Trust: The app ID becomes a file name
+    fn baseline_path(&self, app_id: &str) -> Result<PathBuf, String> {
+        if app_id.is_empty() || !app_id.chars().all(|c| c.is_ascii_alphanumeric() || c == '-') {
+            return Err("SECURITY VIOLATION: Invalid app ID for baseline".into());
+        }
+        Ok(self.baseline_dir.join(format!("{}.json", app_id)))
+    }
+
+    async fn load_baseline(&self, app_id: &str) -> Result<Option<BTreeMap<String, String>>, String> {
+        let path = self.baseline_path(app_id)?;
+        match tokio::fs::read(&path).await {
+            Ok(raw) => serde_json::from_slice(&raw)
+                .map(Some)
+                .map_err(|e| format!("Corrupt baseline for {}: {}", app_id, e)),
+            Err(e) if e.kind() == std::io::ErrorKind::NotFound => Ok(None),
+            Err(e) => Err(format!("Failed to read baseline: {}", e)),
+        }
+    }
+
+    /// Written root-only (0600) so a compromised app cannot launder its own changes.
+    async fn store_baseline(&self, app_id: &str, hashes: &BTreeMap<String, String>) -> Result<(), String> {
+        let path = self.baseline_path(app_id)?;
+        tokio::fs::create_dir_all(&self.baseline_dir).await
+            .map_err(|e| format!("Failed to create baseline dir: {}", e))?;
+        let raw = serde_json::to_vec(hashes).map_err(|e| e.to_string())?;
+
+        let tmp = path.with_extension("json.tmp");
+        tokio::fs::write(&tmp, raw).await.map_err(|e| format!("Failed to write baseline: {}", e))?;
+        tokio::fs::set_permissions(&tmp, std::fs::Permissions::from_mode(0o600)).await
+            .map_err(|e| e.to_string())?;
+        tokio::fs::rename(&tmp, &path).await.map_err(|e| format!("Failed to commit baseline: {}", e))
+    }
+
+    /// Hashes every regular file under `root` (symlinks are not followed).
+    async fn hash_tree(root: &Path, exclude: &[String]) -> Result<(BTreeMap<String, String>, Vec<ScanFinding>), String> {
+        let root = root.to_path_buf();
+        let exclude = exclude.to_vec();
+        tokio::task::spawn_blocking(move || {
+            let mut hashes = BTreeMap::new();
+            let mut odd = Vec::new();
+            let mut stack = vec![root.clone()];
+            while let Some(dir) = stack.pop() {
+                let entries = std::fs::read_dir(&dir).map_err(|e| format!("Failed to read {}: {}", dir.display(), e))?;
+                for entry in entries.flatten() {
+                    let path = entry.path();
+                    let Ok(rel) = path.strip_prefix(&root) else { continue };
+                    let rel = rel.to_string_lossy().to_string();
+                    if exclude.iter().any(|prefix| rel.starts_with(prefix.as_str())) {
+                        continue;
+                    }
+                    let Ok(file_type) = entry.file_type() else { continue };
+                    if file_type.is_dir() {
+                        stack.push(path);
+                    } else if file_type.is_file() {
+                        // A newline in a file name is a classic log/report evasion trick
+                        if rel.contains('\n') {
+                            odd.push(ScanFinding { path: rel.replace('\n', "\\n"), kind: "added", detail: "file name contains a newline".into() });
+                            continue;
+                        }
+                        if hashes.len() >= MAX_SCAN_FILES {
+                            return Err(format!("Tree exceeds {} files", MAX_SCAN_FILES));
+                        }
+                        hashes.insert(rel, hash_file(&path)?);
+                    }
+                }
+            }
+            Ok((hashes, odd))
+        })
+        .await
+        .map_err(|e| format!("Scan task failed: {}", e))?
+    }
+
+    /// `clamscan --infected` prints "path: Signature FOUND" per hit; exit 1 means hits.
+    async fn clamscan(root: &Path) -> Result<Vec<ScanFinding>, String> {
+        let output = Command::new("clamscan")
+            .args(["--recursive", "--infected", "--no-summary", "--stdout"])
+            .arg(root)
+            .output()
+            .await
+            .map_err(|e| format!("ClamAV is not available: {}", e))?;
+        if !matches!(output.status.code(), Some(0) | Some(1)) {
+            return Err(format!("clamscan failed: {}", String::from_utf8_lossy(&output.stderr)));
+        }
+
+        let mut findings = Vec::new();
+        for line in String::from_utf8_lossy(&output.stdout).lines() {
+            let Some(hit) = line.strip_suffix(" FOUND") else { continue };
+            let Some((path, signature)) = hit.rsplit_once(": ") else { continue };
+            let rel = Path::new(path).strip_prefix(root).map(|p| p.to_string_lossy().to_string()).unwrap_or_else(|_| path.to_string());
+            findings.push(ScanFinding { path: rel, kind: "infected", detail: signature.to_string() });
+        }
+        Ok(findings)
+    }
+}
+
+fn hash_file(path: &Path) -> Result<String, String> {
+    let mut file = std::fs::File::open(path).map_err(|e| format!("Failed to open {}: {}", path.display(), e))?;
+    let mut hasher = Sha256::new();
+    let mut buf = [0u8; 64 * 1024];
+    loop {
+        let n = file.read(&mut buf).map_err(|e| format!("Failed to read {}: {}", path.display(), e))?;
+        if n == 0 {
+            break;
+        }
+        hasher.update(&buf[..n]);
+    }
+    Ok(format!("{:x}", hasher.finalize()))
+}
+
+#[async_trait]
+impl FileScanner for IntegrityScanner {
+    async fn scan(&self, app_id: &str, root: &Path, rebaseline: bool, clamav: bool, exclude: &[String]) -> Result<ScanReport, String> {
+        let (current, mut findings) = Self::hash_tree(root, exclude).await?;
+        let mut report = ScanReport { files_scanned: current.len() as u64, ..Default::default() };
+
+        match self.load_baseline(app_id).await? {
+            Some(baseline) if !rebaseline => {
+                for (path, hash) in &current {
+                    match baseline.get(path) {
+                        None => findings.push(ScanFinding { path: path.clone(), kind: "added", detail: String::new() }),
+                        Some(known) if known != hash => findings.push(ScanFinding { path: path.clone(), kind: "modified", detail: String::new() }),
+                        _ => {}
+                    }
+                }
+                // The baseline may predate the exclusions (post-deploy baselines cover everything)
+                let excluded = |p: &str| exclude.iter().any(|prefix| p.starts_with(prefix.as_str()));
+                for path in baseline.keys().filter(|p| !current.contains_key(*p) && !excluded(p)) {
+                    findings.push(ScanFinding { path: path.clone(), kind: "removed", detail: String::new() });
+                }
+            }
+            _ => {
+                self.store_baseline(app_id, &current).await?;
+                report.baseline_created = true;
+            }
+        }
+
+        // Signatures cover excluded paths too: uploads are exactly where payloads land
+        if clamav {
+            findings.extend(Self::clamscan(root).await?);
+        }
+        report.findings = findings;
+        Ok(report)
+    }
+
+    async fn rebaseline(&self, app_id: &str, root: &Path, exclude: &[String]) -> Result<(), String> {
+        let (current, _) = Self::hash_tree(root, exclude).await?;
+        self.store_baseline(app_id, &current).await
+    }
+}
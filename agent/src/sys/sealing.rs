@@ -0,0 +1,161 @@
+use std::process::Stdio;
+use tokio::io::AsyncWriteExt;
+use tokio::process::Command;
+use zeroize::Zeroizing;
+
+/// Most secrets the Brain seals at once (DATABASE_URL, JWT_SECRET, ENCRYPTION_KEY, ...).
+pub const MAX_SEALED_VALUES: usize = 16;
+/// An env file value, not a blob store.
+pub const MAX_SECRET_BYTES: usize = 4096;
+
+/// What a sealed value can only be decrypted with.
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub enum KeyBinding {
+    /// The TPM2 chip: the ciphertext is useless on any other machine.
+    Tpm2,
+    /// /var/lib/systemd/credential.secret: root-only, never leaves this host.
+    Host,
+}
+
+impl KeyBinding {
+    pub fn as_str(&self) -> &'static str {
+        match self {
+            KeyBinding::Tpm2 => "tpm2",
+            KeyBinding::Host => "host",
+        }
+    }
+}
+
+/// Picks the strongest key this host offers.
+pub async fn binding() -> KeyBinding {
+    let tpm = Command::new("systemd-creds")
+        .arg("has-tpm2")
+        .arg("--quiet")
+        .output()
+        .await
+        .map(|o| o.status.success())
+        .unwrap_or(false);
+    if tpm {
+        KeyBinding::Tpm2
+    } else {
+        KeyBinding::Host
+    }
+}
+
+/// Environment variable names only: the name is bound into the ciphertext,
+/// so a sealed JWT_SECRET cannot be replayed as ENCRYPTION_KEY.
+pub fn validate_name(name: &str) -> Result<(), String> {
+    let valid = !name.is_empty()
+        && name.len() <= 64
+        && name
+            .chars()
+            .all(|c| c.is_ascii_uppercase() || c.is_ascii_digit() || c == '_')
+        && !name.starts_with(|c: char| c.is_ascii_digit());
+    if valid {
+        Ok(())
+    } else {
+        Err(format!("'{}' is not an environment variable name", name))
+    }
+}
+
+/// Encrypts one value; returns the Base64 ciphertext on a single line.
+pub async fn seal(
+    name: &str,
+    value: &Zeroizing<String>,
+    key: KeyBinding,
+) -> Result<String, String> {
+    validate_name(name)?;
+    if value.len() > MAX_SECRET_BYTES {
+        return Err(format!("{} exceeds {} bytes", name, MAX_SECRET_BYTES));
+    }
+    let out = run(
+        &[
+            "encrypt",
+            &format!("--name={}", name),
+            &format!("--with-key={}", key.as_str()),
+            "-",
+            "-",
+        ],
+        value.as_bytes(),
+    )
+    .await?;
+    Ok(single_line(&String::from_utf8_lossy(&out)))
+}
+
+/// Decrypts one value. The plaintext only ever exists in this process's memory
+/// and the gRPC response to the Brain.
+pub async fn unseal(name: &str, sealed: &str) -> Result<Zeroizing<String>, String> {
+    validate_name(name)?;
+    let out = Zeroizing::new(
+        run(
+            &["decrypt", &format!("--name={}", name), "-", "-"],
+            sealed.as_bytes(),
+        )
+        .await?,
+    );
+    String::from_utf8(out.to_vec())
+        .map(Zeroizing::new)
+        .map_err(|_| format!("{} did not decrypt to text", name))
+}
+
+/// systemd-creds wraps its Base64 output; env files need one line per value.
+fn single_line(encoded: &str) -> String {
+    encoded.chars().filter(|c| !c.is_whitespace()).collect()
+}
+
+/// Feeds the input over stdin: secrets never appear in argv or on disk.
+async fn run(args: &[&str], input: &[u8]) -> Result<Vec<u8>, String> {
+    let mut child = Command::new("systemd-creds")
+        .args(args)
+        .stdin(Stdio::piped())
+        .stdout(Stdio::piped())
+        .stderr(Stdio::piped())
+        .spawn()
+        .map_err(|e| format!("systemd-creds unavailable: {}", e))?;
+
+    let mut stdin = child
+        .stdin
+        .take()
+        .ok_or("systemd-creds stdin unavailable")?;
+    stdin.write_all(input).await.map_err(|e| e.to_string())?;
+    drop(stdin);
+
+    let output = child.wait_with_output().await.map_err(|e| e.to_string())?;
+    if !output.status.success() {
+        return Err(format!(
+            "systemd-creds {} failed: {}",
+            args[0],
+            String::from_utf8_lossy(&output.stderr).trim()
+        ));
+    }
+    Ok(output.stdout)
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn accepts_env_names() {
+        assert!(validate_name("JWT_SECRET").is_ok());
+        assert!(validate_name("ENCRYPTION_KEY").is_ok());
+        assert!(validate_name("S3_SECRET_KEY").is_ok());
+    }
+
+    #[test]
+    fn rejects_non_env_names() {
+        assert!(validate_name("").is_err());
+        assert!(validate_name("jwt_secret").is_err());
+        assert!(validate_name("1KEY").is_err());
+        assert!(validate_name("KEY=VALUE").is_err());
+        assert!(validate_name("--name=X").is_err());
+    }
+
+    #[test]
+    fn joins_wrapped_base64() {
+        assert_eq!(
+            single_line("k6iUCUh0RJCQ\nyvtsNLxt1w==\n"),
+            "k6iUCUh0RJCQyvtsNLxt1w=="
+        );
+    }
+}
@@ -20,6 +20,15 @@ impl LinuxSslEngine {
     pub fn new(ssl_storage_dir: PathBuf) -> Self {
         Self { ssl_storage_dir }
     }
+
+    /// 🛡️ Zero-Trust: The domain becomes a directory name under the store
+    fn domain_dir(&self, domain_name: &str) -> Result<PathBuf, String> {
+        if domain_name.is_empty() || domain_name.contains("..")
+            || !domain_name.chars().all(|c| c.is_ascii_alphanumeric() || c == '-' || c == '.') {
+            return Err("SECURITY VIOLATION: Invalid domain name format".into());
+        }
+        Ok(self.ssl_storage_dir.join(domain_name))
+    }
 }
 
 #[async_trait]
@@ -109,4 +118,27 @@ impl SslEngine for LinuxSslEngine {
 
         Ok(())
     }
+    async fn list_certificates(&self) -> Result<Vec<String>, String> {
+        let mut entries = match tokio_fs::read_dir(&self.ssl_storage_dir).await {
+            Ok(entries) => entries,
+            Err(e) if e.kind() == std::io::ErrorKind::NotFound => return Ok(Vec::new()),
+            Err(e) => return Err(format!("Failed to scan SSL store: {}", e)),
+        };
+        let mut domains = Vec::new();
+        while let Ok(Some(entry)) = entries.next_entry().await {
+            if tokio_fs::metadata(entry.path().join("fullchain.pem")).await.is_ok() {
+                domains.push(entry.file_name().to_string_lossy().to_string());
+            }
+        }
+        Ok(domains)
+    }
+
+    async fn remove_certificate(&self, domain_name: &str) -> Result<(), String> {
+        let domain_path = self.domain_dir(domain_name)?;
+        match tokio_fs::remove_dir_all(&domain_path).await {
+            Ok(()) => Ok(()),
+            Err(e) if e.kind() == std::io::ErrorKind::NotFound => Ok(()),
+            Err(e) => Err(format!("Failed to remove certificate for {}: {}", domain_name, e)),
+        }
+    }
 }
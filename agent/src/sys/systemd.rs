@@ -24,7 +24,27 @@ pub trait ServiceManager: Send + Sync {
     async fn enable_and_start(&self, service_name: &str) -> Result<(), String>;
     async fn start(&self, service_name: &str) -> Result<(), String>;
     async fn stop(&self, service_name: &str) -> Result<(), String>;
+    /// Stops the unit and removes it from boot (`disable --now`).
+    async fn disable(&self, service_name: &str) -> Result<(), String>;
     async fn restart(&self, service_name: &str) -> Result<(), String>;
+
+    /// Current unit file contents, if any (used to roll back multi-unit deploys).
+    async fn read_unit_file(&self, service_name: &str) -> Result<Option<String>, String>;
+    /// Restores a snapshot from `read_unit_file`; `None` removes the unit.
+    async fn restore_unit_file(&self, service_name: &str, snapshot: Option<String>) -> Result<(), String>;
+    /// Names (without `.service`) of managed units starting with `prefix`.
+    async fn list_units(&self, prefix: &str) -> Result<Vec<String>, String>;
+    async fn status(&self, service_name: &str) -> Result<UnitStatus, String>;
+}
+
+/// Live state of a unit as reported by `systemctl show`.
+#[derive(Debug, Default, Clone)]
+pub struct UnitStatus {
+    pub active_state: String,
+    pub sub_state: String,
+    pub memory_bytes: u64,
+    pub cpu_usage_nsec: u64, // Cumulative since the unit last started
+    pub active_since_unix: i64,
 }
 
 pub struct LinuxSystemdManager {
@@ -95,6 +115,9 @@ ExecStart={exec_start}
 {env_block}
 Restart=always
 RestartSec=5
+# Drain window: SIGTERM first, so WebSocket/SSE clients can finish
+KillSignal=SIGTERM
+TimeoutStopSec=30
 
 # --- ⚖️ Dynamic Resource Jailing ---
 CPUAccounting=true
@@ -165,7 +188,81 @@ WantedBy=multi-user.target
         self.execute_systemctl(&["stop", service_name]).await
     }
 
+    async fn disable(&self, service_name: &str) -> Result<(), String> {
+        self.execute_systemctl(&["disable", "--now", service_name]).await
+    }
+
     async fn restart(&self, service_name: &str) -> Result<(), String> {
         self.execute_systemctl(&["restart", service_name]).await
     }
+
+    async fn read_unit_file(&self, service_name: &str) -> Result<Option<String>, String> {
+        let path = self.get_unit_path(service_name)?;
+        match fs::read_to_string(&path).await {
+            Ok(content) => Ok(Some(content)),
+            Err(e) if e.kind() == std::io::ErrorKind::NotFound => Ok(None),
+            Err(e) => Err(format!("Failed to read unit {}: {}", service_name, e)),
+        }
+    }
+
+    async fn restore_unit_file(&self, service_name: &str, snapshot: Option<String>) -> Result<(), String> {
+        match snapshot {
+            Some(content) => {
+                let path = self.get_unit_path(service_name)?;
+                fs::write(&path, content).await.map_err(|e| format!("Restore failed: {}", e))
+            }
+            None => {
+                let _ = self.stop(service_name).await;
+                self.remove_unit_file(service_name).await
+            }
+        }
+    }
+
+    async fn list_units(&self, prefix: &str) -> Result<Vec<String>, String> {
+        let mut entries = fs::read_dir(&self.systemd_dir).await
+            .map_err(|e| format!("Failed to scan units: {}", e))?;
+        let mut units = Vec::new();
+        while let Ok(Some(entry)) = entries.next_entry().await {
+            let file = entry.file_name().to_string_lossy().to_string();
+            if let Some(name) = file.strip_suffix(".service") {
+                if name.starts_with(prefix) {
+                    units.push(name.to_string());
+                }
+            }
+        }
+        Ok(units)
+    }
+
+    async fn status(&self, service_name: &str) -> Result<UnitStatus, String> {
+        self.get_unit_path(service_name)?;
+        let output = Command::new("systemctl")
+            .args(["show", service_name, "--timestamp=unix",
+                "-p", "ActiveState,SubState,MemoryCurrent,CPUUsageNSec,ActiveEnterTimestamp"])
+            .output()
+            .await
+            .map_err(|e| format!("SLA Failure: systemctl execution error: {}", e))?;
+        if !output.status.success() {
+            return Err(format!("systemctl show failed: {}", String::from_utf8_lossy(&output.stderr)));
+        }
+
+        let mut status = UnitStatus::default();
+        for line in String::from_utf8_lossy(&output.stdout).lines() {
+            let Some((key, value)) = line.split_once('=') else { continue };
+            match key {
+                "ActiveState" => status.active_state = value.to_string(),
+                "SubState" => status.sub_state = value.to_string(),
+                // "[not set]" when memory accounting is off
+                "MemoryCurrent" => status.memory_bytes = value.parse().unwrap_or(0),
+                "CPUUsageNSec" => status.cpu_usage_nsec = value.parse().unwrap_or(0),
+                "ActiveEnterTimestamp" => {
+                    status.active_since_unix = value.trim_start_matches('@').parse().unwrap_or(0)
+                }
+                _ => {}
+            }
+        }
+        if status.active_state != "active" {
+            status.active_since_unix = 0;
+        }
+        Ok(status)
+    }
 }
@@ -1,7 +1,7 @@
 use async_trait::async_trait;
 use std::net::IpAddr;
 use std::collections::HashMap;
-use std::path::Path;
+use std::path::{Path, PathBuf};
 use tokio::sync::mpsc;
 use tonic::Status;
 
@@ -66,6 +66,11 @@ pub struct FirewallPolicy {
 #[async_trait]
 pub trait FirewallManager: Send + Sync {
     async fn apply_policy(&self, policy: &FirewallPolicy) -> Result<(), String>;
+
+    /// Sets what happens to inbound traffic no rule matches. Before denying,
+    /// loopback, established flows, SSH and HTTP(S) are let through, so the
+    /// switch cannot lock the host out.
+    async fn set_default_inbound(&self, action: FirewallAction) -> Result<(), String>;
 }
 
 // ==============================================================================
@@ -85,6 +90,12 @@ pub struct SslPayload {
 #[async_trait]
 pub trait SslEngine: Send + Sync {
     async fn install_certificate(&self, payload: SslPayload) -> Result<(), String>;
+
+    /// Domains with a stored certificate.
+    async fn list_certificates(&self) -> Result<Vec<String>, String>;
+
+    /// Deletes the domain's certificate and key; a missing one is not an error.
+    async fn remove_certificate(&self, domain_name: &str) -> Result<(), String>;
 }
 
 // ==============================================================================
@@ -93,12 +104,34 @@ pub trait SslEngine: Send + Sync {
 
 #[async_trait]
 pub trait ProxyManager: Send + Sync {
+    /// Where every vhost for `domain` writes its combined-format access log.
+    fn access_log_path(&self, domain: &str) -> PathBuf;
+
     /// Creates a virtual host configuration for the given domain,
     /// proxying traffic to the specified internal port.
     async fn create_vhost(&self, domain: &str, target_port: u16) -> Result<(), String>;
 
+    /// Creates a virtual host load-balancing across several local instances
+    /// of the same app (one port each). Unhealthy backends are skipped.
+    async fn create_balanced_vhost(&self, domain: &str, ports: &[u16]) -> Result<(), String>;
+
+    /// Creates a virtual host serving `doc_root` and handing PHP requests
+    /// to the app's PHP-FPM pool over its Unix socket.
+    async fn create_php_vhost(&self, domain: &str, fpm_socket: &Path, doc_root: &Path) -> Result<(), String>;
+
     /// Removes the virtual host configuration for the given domain.
     async fn remove_vhost(&self, domain: &str) -> Result<(), String>;
+
+    /// Domains whose enabled vhost was rendered by Kari (drift reconciliation).
+    async fn list_vhosts(&self) -> Result<Vec<String>, String>;
+
+    /// Replaces the domain's managed response headers (CSP, X-Frame-Options, ...)
+    /// included by every vhost template. An empty list removes them. The previous
+    /// set is restored if the proxy rejects the new config.
+    async fn apply_headers(&self, domain: &str, headers: &[(String, String)]) -> Result<(), String>;
+
+    /// Caps the domain's response bandwidth (KiB/s per connection); 0 lifts it.
+    async fn set_rate_limit(&self, domain: &str, kbps: u32) -> Result<(), String>;
 }
 
 // ==============================================================================
@@ -1,39 +1,298 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// componentNames maps --component values to names in the /health/ready report.
+var componentNames = map[string]string{
+	"agent": "agent",
+	"db":    "database",
+}
+
+// expectations collects repeatable --expect path=value assertions.
+type expectations []string
+
+func (e *expectations) String() string { return strings.Join(*e, ",") }
+
+func (e *expectations) Set(v string) error {
+	if !strings.Contains(v, "=") {
+		return fmt.Errorf("expected path=value, got %q", v)
+	}
+	*e = append(*e, v)
+	return nil
+}
+
+type options struct {
+	target    string
+	socket    string
+	certFile  string
+	keyFile   string
+	caFile    string
+	insecure  bool
+	token     string
+	expect    expectations
+	retries   int
+	backoff   time.Duration
+	timeout   time.Duration
+	component string
+}
+
 func main() {
-	// 🛡️ Zero-Trust: Allow override but default to internal port
-	target := os.Getenv("HEALTHCHECK_TARGET")
-	if target == "" {
-		target = "http://localhost:8080/health"
+	opts := parseOptions()
+
+	client, err := newClient(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Healthcheck misconfigured: %v\n", err)
+		os.Exit(2)
+	}
+
+	// 🛡️ SLA: Exponential backoff between attempts, capped at 10s
+	delay := opts.backoff
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		err = probe(client, opts)
+		if err == nil {
+			// Success remains silent to keep Docker logs clean
+			os.Exit(0)
+		}
+		if attempt >= opts.retries {
+			fmt.Fprintf(os.Stderr, "%v (Duration: %v, Attempts: %d)\n", err, time.Since(start), attempt+1)
+			os.Exit(1)
+		}
+		time.Sleep(delay)
+		delay = min(delay*2, 10*time.Second)
+	}
+}
+
+// parseOptions reads flags, falling back to HEALTHCHECK_* env vars so the
+// Dockerfile HEALTHCHECK can stay a bare `/app/healthcheck`.
+func parseOptions() options {
+	var opts options
+
+	flag.StringVar(&opts.target, "target", envOr("HEALTHCHECK_TARGET", "http://localhost:8080/health"), "URL to probe")
+	flag.StringVar(&opts.socket, "socket", os.Getenv("HEALTHCHECK_SOCKET"), "Unix socket to connect through (LISTEN_MODE=unix); the target host is ignored")
+	flag.StringVar(&opts.certFile, "cert", os.Getenv("HEALTHCHECK_CLIENT_CERT"), "client certificate (PEM) for mTLS")
+	flag.StringVar(&opts.keyFile, "key", os.Getenv("HEALTHCHECK_CLIENT_KEY"), "client private key (PEM) for mTLS")
+	flag.StringVar(&opts.caFile, "ca", os.Getenv("HEALTHCHECK_CA"), "CA bundle (PEM) to verify the server")
+	flag.BoolVar(&opts.insecure, "insecure", envBool("HEALTHCHECK_INSECURE"), "skip server certificate verification")
+	flag.StringVar(&opts.token, "token", os.Getenv("HEALTHCHECK_TOKEN"), "bearer token sent in the Authorization header")
+	flag.Var(&opts.expect, "expect", "JSON body assertion path=value, e.g. status=ok (repeatable)")
+	flag.IntVar(&opts.retries, "retries", envInt("HEALTHCHECK_RETRIES", 0), "extra attempts after a failure")
+	flag.DurationVar(&opts.backoff, "backoff", envDuration("HEALTHCHECK_BACKOFF", 500*time.Millisecond), "delay before the first retry, doubled each attempt")
+	flag.DurationVar(&opts.timeout, "timeout", envDuration("HEALTHCHECK_TIMEOUT", 2*time.Second), "per-attempt timeout")
+	flag.StringVar(&opts.component, "component", os.Getenv("HEALTHCHECK_COMPONENT"), "only check one subsystem: agent|db")
+	flag.Parse()
+
+	if opts.expect == nil {
+		for _, e := range strings.Split(os.Getenv("HEALTHCHECK_EXPECT"), ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				_ = opts.expect.Set(e)
+			}
+		}
+	}
+
+	if opts.component != "" {
+		if _, ok := componentNames[opts.component]; !ok {
+			fmt.Fprintf(os.Stderr, "❌ Unknown component %q (want agent or db)\n", opts.component)
+			os.Exit(2)
+		}
+		// Components are only reported by the readiness endpoint
+		if u, err := url.Parse(opts.target); err == nil && strings.TrimSuffix(u.Path, "/") == "/health" {
+			u.Path = "/health/ready"
+			opts.target = u.String()
+		}
+	}
+	return opts
+}
+
+func newClient(opts options) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.insecure,
+	}
+
+	if opts.certFile != "" || opts.keyFile != "" {
+		if opts.certFile == "" || opts.keyFile == "" {
+			return nil, errors.New("client certificate and key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(opts.certFile, opts.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if opts.caFile != "" {
+		pem, err := os.ReadFile(opts.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("CA bundle contains no certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := &http.Transport{TLSClientConfig: tlsConfig}
+	if opts.socket != "" {
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", opts.socket)
+		}
 	}
 
 	// 🛡️ SLA: Tight timeout for orchestration responsiveness
-	client := http.Client{
-		Timeout: 2 * time.Second,
+	return &http.Client{
+		Timeout:   opts.timeout,
+		Transport: transport,
+	}, nil
+}
+
+// probe performs one attempt and returns a printable reason on failure.
+func probe(client *http.Client, opts options) error {
+	req, err := http.NewRequest(http.MethodGet, opts.target, nil)
+	if err != nil {
+		return fmt.Errorf("❌ Invalid target: %v", err)
+	}
+	if opts.token != "" {
+		req.Header.Set("Authorization", "Bearer "+opts.token)
 	}
 
-	start := time.Now()
-	resp, err := client.Get(target)
-	
+	resp, err := client.Do(req)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Kari Brain Unreachable: %v (Duration: %v)\n", err, time.Since(start))
-		os.Exit(1)
+		return fmt.Errorf("❌ Kari Brain Unreachable: %v", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("❌ Failed to read response: %v", err)
+	}
+
+	// With a component filter the aggregate status code is irrelevant; a 503
+	// caused by another subsystem must not fail this probe.
+	if opts.component != "" {
+		if err := checkComponent(body, componentNames[opts.component]); err != nil {
+			return err
+		}
+	} else if resp.StatusCode != http.StatusOK {
 		// This captures scenarios where the Brain is alive but the Muscle link is dead
-		fmt.Fprintf(os.Stderr, "⚠️ Kari Brain Paralyzed: Received HTTP %d (Duration: %v)\n", resp.StatusCode, time.Since(start))
-		os.Exit(1)
+		return fmt.Errorf("⚠️ Kari Brain Paralyzed: Received HTTP %d", resp.StatusCode)
 	}
 
-	// Success remains silent to keep Docker logs clean
-	os.Exit(0)
+	if len(opts.expect) > 0 {
+		var doc any
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return fmt.Errorf("⚠️ Expected a JSON body: %v", err)
+		}
+		for _, e := range opts.expect {
+			path, want, _ := strings.Cut(e, "=")
+			got, ok := lookup(doc, path)
+			if !ok {
+				return fmt.Errorf("⚠️ Assertion failed: %s is missing", path)
+			}
+			if got != want {
+				return fmt.Errorf("⚠️ Assertion failed: %s is %q, want %q", path, got, want)
+			}
+		}
+	}
+	return nil
+}
+
+func checkComponent(body []byte, name string) error {
+	var report struct {
+		Components []struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+			Detail string `json:"detail"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(body, &report); err != nil {
+		return fmt.Errorf("⚠️ Readiness report is not JSON: %v", err)
+	}
+	for _, c := range report.Components {
+		if c.Name != name {
+			continue
+		}
+		if c.Status != "ok" {
+			return fmt.Errorf("⚠️ Component %s is %s: %s", name, c.Status, c.Detail)
+		}
+		return nil
+	}
+	return fmt.Errorf("⚠️ Component %s not found in readiness report", name)
+}
+
+// lookup walks a dotted path ("components.0.status") through decoded JSON
+// and renders the leaf the way it would appear on the command line.
+func lookup(doc any, path string) (string, bool) {
+	cur := doc
+	for _, key := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[key]
+			if !ok {
+				return "", false
+			}
+			cur = v
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return "", false
+			}
+			cur = node[i]
+		default:
+			return "", false
+		}
+	}
+
+	switch v := cur.(type) {
+	case string:
+		return v, true
+	case nil:
+		return "null", true
+	default:
+		raw, _ := json.Marshal(v)
+		return string(raw), true
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envBool(key string) bool {
+	v, _ := strconv.ParseBool(os.Getenv(key))
+	return v
+}
+
+func envInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if v, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
 }
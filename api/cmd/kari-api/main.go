@@ -2,26 +2,55 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"encoding/hex"
 	"errors"
 	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // IANA zones for minimal images without /usr/share/zoneinfo
 
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
 
+	"kari/api/internal/api/gql"
 	"kari/api/internal/api/handlers"
 	"kari/api/internal/api/middleware"
 	"kari/api/internal/api/router"
 	"kari/api/internal/config"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/saga"
 	"kari/api/internal/core/services"
+	"kari/api/internal/db"
 	"kari/api/internal/db/postgres"
+	kari_http "kari/api/internal/delivery/http"
+	"kari/api/internal/infrastructure/agentlink"
+	"kari/api/internal/infrastructure/agentpki"
+	"kari/api/internal/infrastructure/archive"
+	"kari/api/internal/infrastructure/breach"
+	"kari/api/internal/infrastructure/chaos"
+	"kari/api/internal/infrastructure/chatops"
 	"kari/api/internal/infrastructure/crypto"
+	"kari/api/internal/infrastructure/fakeagent"
+	"kari/api/internal/infrastructure/gitprovider"
+	"kari/api/internal/infrastructure/mailer"
+	"kari/api/internal/infrastructure/objectstore"
+	"kari/api/internal/infrastructure/osv"
+	"kari/api/internal/infrastructure/paneltls"
+	"kari/api/internal/infrastructure/registry"
+	"kari/api/internal/infrastructure/spool"
+	"kari/api/internal/infrastructure/sshexec"
+	"kari/api/internal/infrastructure/webhook"
+	"kari/api/internal/setup"
 	"kari/api/internal/telemetry"
 	"kari/api/internal/worker"
 	"kari/api/internal/workers"
@@ -29,31 +58,77 @@ import (
 )
 
 func main() {
+	// 🤖 Headless setup runs instead of the server
+	if len(os.Args) > 1 && os.Args[1] == "setup" {
+		os.Exit(runSetup(os.Args[2:]))
+	}
+	// 🚨 Break-glass: Super Admin recovery from the host console
+	if len(os.Args) > 1 && os.Args[1] == "recover-admin" {
+		os.Exit(runRecoverAdmin(os.Args[2:]))
+	}
+
 	// --- 1. Core Telemetry & Configuration ---
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	cfg := config.Load()
+
+	// 💥 The log ring keeps recent lines in memory for crash reports
+	logRing := telemetry.NewLogRing(cfg.CrashLogRingSize)
+	logger := slog.New(logRing.Wrap(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})))
 	slog.SetDefault(logger)
 	logger.Info("🚀 Booting Karı Panel Brain...")
-	cfg := config.Load()
+	logger.Info("⚙️ Configuration loaded", "fingerprint", cfg.Fingerprint()[:12], "env_file", cfg.EnvFile)
 
 	// --- 2. Outbound Infrastructure ---
-	dbPool, err := postgres.NewPool(context.Background(), cfg.DatabaseURL)
-	if err != nil {
-		logger.Error("FATAL: DB failed", "error", err)
-		os.Exit(1)
-	}
-	defer dbPool.Close()
-
 	// 🛡️ gRPC Link to Rust Muscle over Unix Socket
 	// Keepalive ensures the Brain detects a dead Muscle and triggers transport reconnection
-	// when the Agent restarts and recreates the UDS.
+	// when the Agent restarts and recreates the UDS. The dial does not block, so
+	// a Muscle that is not up yet only shows as down in readiness.
 	grpcDialer := func(ctx context.Context, addr string) (net.Conn, error) {
 		return (&net.Dialer{}).DialContext(ctx, "unix", addr)
 	}
 
+	// 🎭 Demo Mode: The same client and interceptors, answered from memory.
+	// Never outside development: its "sealed" secrets are only encoded
+	if cfg.DemoAgent {
+		if cfg.Environment != "development" {
+			logger.Error("FATAL: DEMO_AGENT is only allowed with KARI_ENV=development")
+			os.Exit(1)
+		}
+		demoLink := fakeagent.Serve(fakeagent.New())
+		defer demoLink.Close()
+		grpcDialer = demoLink.Dialer()
+		logger.Warn("🎭 Demo mode: An in-memory agent stands in for the Muscle; nothing is applied to this host and sealed secrets are only encoded")
+	}
+
+	// 🧪 Fault Injection: Fake outages to rehearse alerts, retries and the
+	// breaker. Never in production, where it would be a real outage.
+	chaosFaults := chaos.Faults{
+		AgentTimeoutPercent: cfg.ChaosAgentTimeoutPercent,
+		DBDropPercent:       cfg.ChaosDBDropPercent,
+		ACMEDelay:           time.Duration(cfg.ChaosACMEDelaySeconds) * time.Second,
+	}
+	faults := chaos.New(chaosFaults, logger)
+	if chaosFaults.Enabled() {
+		if cfg.Environment != "development" {
+			logger.Error("FATAL: CHAOS_* fault injection is only allowed with KARI_ENV=development")
+			os.Exit(1)
+		}
+		logger.Warn("🧪 Chaos: Injecting dependency faults",
+			"agent_timeout_percent", chaosFaults.AgentTimeoutPercent,
+			"db_drop_percent", chaosFaults.DBDropPercent,
+			"acme_delay", chaosFaults.ACMEDelay)
+	}
+
+	// 🔌 Agent Link: Per-method deadlines and idempotent retries (outer), then
+	// reconnect backoff + circuit breaker (inner), shared by every agent caller
+	linkOpts := agentlink.DefaultOptions()
+	agentLink := agentlink.New(linkOpts, logger)
+
 	grpcConn, err := grpc.Dial(
 		cfg.AgentSocketPath,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithContextDialer(grpcDialer),
+		grpc.WithChainUnaryInterceptor(agentlink.PolicyUnaryInterceptor(linkOpts), agentLink.UnaryInterceptor(), faults.UnaryInterceptor()),
+		grpc.WithChainStreamInterceptor(agentlink.PolicyStreamInterceptor(), agentLink.StreamInterceptor(), faults.StreamInterceptor()),
 		grpc.WithKeepaliveParams(keepalive.ClientParameters{
 			Time:                30 * time.Second, // Send keepalive ping every 30s
 			Timeout:             10 * time.Second, // Wait 10s for pong before marking dead
@@ -65,12 +140,52 @@ func main() {
 		os.Exit(1)
 	}
 	defer grpcConn.Close()
+	agentLink.Attach(grpcConn)
 	agentClient := agent.NewSystemAgentClient(grpcConn)
 
+	// 🔐 Sealed Secrets: Only the Muscle holds the machine-bound key, so the
+	// secrets the env file keeps sealed are unsealed before anything uses them
+	if sealed := cfg.Sealed(); len(sealed) > 0 {
+		if err := unsealSecrets(agentClient, cfg, sealed, time.Duration(cfg.StartupRetrySeconds)*time.Second); err != nil {
+			logger.Error("FATAL: Cannot unseal secrets", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("🔓 Sealed secrets unsealed", "count", len(sealed))
+	}
+
+	dbPool, err := postgres.NewPool(context.Background(), cfg.DatabaseURL, postgres.PoolOptions{
+		MaxConns:           int32(cfg.DBMaxConns),
+		MinConns:           int32(cfg.DBMinConns),
+		HealthCheckPeriod:  time.Duration(cfg.DBHealthCheckSeconds) * time.Second,
+		StatementCacheMode: cfg.DBStatementCacheMode,
+		QueryTimeout:       time.Duration(cfg.DBQueryTimeoutSeconds) * time.Second,
+		Configure:          faults.ConfigurePool,
+	})
+	if err != nil {
+		logger.Error("FATAL: DB failed", "error", err)
+		os.Exit(1)
+	}
+	defer dbPool.Close()
+
+	// ⏳ Boot Ordering: Postgres may still be starting under systemd/compose.
+	// In degraded mode the API serves anyway and /health/ready reports the
+	// database down until it answers.
+	bootCtx, cancelBoot := context.WithTimeout(context.Background(), time.Duration(cfg.StartupRetrySeconds)*time.Second)
+	err = postgres.WaitForDatabase(bootCtx, dbPool, logger)
+	cancelBoot()
+	dbReady := err == nil
+	if !dbReady {
+		if !cfg.StartupDegraded {
+			logger.Error("FATAL: DB failed", "error", err)
+			os.Exit(1)
+		}
+		logger.Warn("⚠️ Starting degraded: database unreachable", "error", err)
+	}
+
 	// --- 3. Setup Mode Detection ---
 	// 🛡️ The Setup Guard determines whether the system is configured.
 	// In setup mode, crypto and DB are not yet available.
-	lockPath := "/opt/kari/setup.lock"
+	lockPath := setup.DefaultLockPath
 
 	// Signal channel for shutdown (used by setup lockdown)
 	stop := make(chan os.Signal, 1)
@@ -89,7 +204,7 @@ func main() {
 			os.Exit(1)
 		}
 		logger.Info("🔧 SETUP MODE: System is unconfigured. Access the wizard at:")
-		logger.Info("   http://localhost:"+cfg.Port+"/setup?token="+setupToken)
+		logger.Info("   http://localhost:" + cfg.Port + "/setup?token=" + setupToken)
 	}
 
 	// --- 4. Hardened Dependency Injection ---
@@ -106,47 +221,612 @@ func main() {
 	// Repositories
 	appRepo := postgres.NewApplicationRepository(dbPool)
 	deployRepo := postgres.NewPostgresDeploymentRepository(dbPool)
-	userRepo := postgres.NewUserRepository(dbPool)
+	var userRepo domain.UserRepository = postgres.NewUserRepository(dbPool)
+	userAdminRepo := postgres.NewUserAdminRepo(dbPool)
+	// ⚡ Identity and RBAC reads run on every request; mutations invalidate in-process
+	var userCache *postgres.UserCache
+	if cfg.UserCacheTTLSeconds > 0 {
+		userCache = postgres.NewUserCache(time.Duration(cfg.UserCacheTTLSeconds) * time.Second)
+		userRepo = postgres.NewCachedUserRepo(userRepo, userCache)
+		userAdminRepo = postgres.NewCachedUserAdminRepo(userAdminRepo, userCache)
+	}
+	idempotencyRepo := postgres.NewIdempotencyRepo(dbPool)
+	auditSinkRepo := postgres.NewAuditSinkConfigRepo(dbPool)
+
+	// 📡 SIEM Forwarding: Every tenant log is persisted first, then forwarded
+	auditForwarder := workers.NewAuditForwarder(logger)
+	// 🕶️ Redaction runs first so neither Postgres, the hash chain nor the SIEM see secrets.
+	// 🧾 Request stamping (IP, trace ID, actor rank) sits outside it, so redaction covers it too.
+	redactionService := services.NewRedactionService(postgres.NewRedactionRuleRepo(dbPool), appRepo, cfg.RedactIPAddresses, logger)
+	auditRepo := services.NewForwardingAuditRepository(
+		services.NewRequestAuditRepository(
+			services.NewRedactingAuditRepository(postgres.NewAuditRepository(dbPool), redactionService),
+		),
+		auditForwarder,
+	)
+
+	// 💥 Crash Reporter: Panics from handlers and workers land in crash_reports
+	crashService, err := services.NewCrashReportService(postgres.NewCrashRepo(dbPool), auditRepo, logRing, cfg.CrashReportDSN, logger)
+	if err != nil {
+		logger.Error("FATAL: Crash reporting misconfigured", "error", err)
+		os.Exit(1)
+	}
+	crashService.WithRedactor(redactionService)
+	searchRepo := postgres.NewSearchRepo(dbPool)
+	retentionRepo := postgres.NewRetentionRepo(dbPool)
+	retentionPolicies := []domain.RetentionPolicy{
+		{Table: "tenant_logs", RetentionDays: cfg.TenantLogRetentionDays},
+		{Table: "deployment_logs", RetentionDays: cfg.DeploymentLogRetentionDays},
+	}
 
 	// 🛡️ Global Telemetry Hub (Memory Bus)
 	telemetryHub := telemetry.NewHub()
+	switch cfg.HubRelay {
+	case "":
+	case "postgres":
+		// 📡 Replicas behind a load balancer: a viewer may not be on the building replica
+		telemetryHub.WithRelay(postgres.NewHubRelay(dbPool))
+	default:
+		logger.Error("FATAL: HUB_RELAY must be empty or \"postgres\"", "value", cfg.HubRelay)
+		os.Exit(1)
+	}
 
 	// Services
 	authService := services.NewAuthService(userRepo, logger, cfg)
 
+	// 🚦 Deploy Approvals: Gated apps hold new deployments until someone approves
+	// them. Bots are only wired when tokens are set (no typed-nil notifiers).
+	var slackApprovals, discordApprovals domain.ApprovalNotifier
+	if cfg.SlackBotToken != "" {
+		slackApprovals = chatops.NewSlackBot(cfg.SlackBotToken)
+	}
+	if cfg.DiscordBotToken != "" {
+		discordApprovals = chatops.NewDiscordBot(cfg.DiscordBotToken)
+	}
+	approvalService := services.NewApprovalService(postgres.NewApprovalRepo(dbPool), appRepo, userRepo, slackApprovals, discordApprovals, logger)
+
+	gatedDeployRepo := approvalService.Gate(deployRepo)
+
+	// ⏰ Scheduled Deployments: Saved as SCHEDULED, promoted to PENDING when due
+	deploySchedules := services.NewDeployScheduleService(gatedDeployRepo, postgres.NewDeployScheduleRepo(dbPool), appRepo, userRepo, logger)
+
 	// Handlers
 	authHandler := handlers.NewAuthHandler(authService)
-	deployHandler := handlers.NewDeploymentHandler(deployRepo, cryptoService, telemetryHub)
+	deployHandler := handlers.NewDeploymentHandler(gatedDeployRepo, cryptoService, telemetryHub)
+	auditHandler := handlers.NewAuditHandler(auditRepo, services.NewAuditIntegrityService(auditRepo))
+	searchHandler := handlers.NewSearchHandler(searchRepo)
+	storageHandler := handlers.NewStorageHandler(retentionRepo, retentionPolicies)
+	// Avoid a typed-nil interface while the system is still in setup mode
+	var domainCrypto domain.CryptoService
+	if cryptoService != nil {
+		domainCrypto = cryptoService
+	}
+	auditSinkService := services.NewAuditSinkService(auditSinkRepo, domainCrypto, auditForwarder, logger)
+	auditSinkHandler := handlers.NewAuditSinkHandler(auditSinkService)
+
+	// 🔁 Sagas: Multi-step provisioning persists its progress, so a restart
+	// rolls back (or finishes) a half-done teardown instead of orphaning it.
+	// Services register their saga kinds in their constructors, before Start.
+	sagas := saga.NewOrchestrator(postgres.NewSagaRepo(dbPool), logger)
+
+	// 📬 Outbox: Every intended state change is recorded first, then applied
+	// on the Muscle exactly once, surviving agent downtime and Brain restarts
+	outbox := services.NewOutbox(grpcConn, postgres.NewOutboxRepo(dbPool), domainCrypto, auditRepo, logger)
+	// 🤝 Protocol Handshake: Negotiate the Brain<->Muscle revision before serving
+	agentCompat := services.NewAgentCompatService(agentClient, auditRepo, logger)
+	if err := agentCompat.Handshake(context.Background()); err != nil {
+		logger.Warn("Muscle handshake deferred to health prober", "error", err)
+	}
+	updateService, err := services.NewUpdateService(cfg.UpdateFeedURL, cfg.UpdatePublicKeyHex, cfg.UpdateStagingDir, agentClient, agentCompat, auditRepo, logger)
+	if err != nil {
+		logger.Error("FATAL: Self-update misconfigured", "error", err)
+		os.Exit(1)
+	}
+	updateHandler := handlers.NewSystemUpdateHandler(updateService)
+	// 📦 Build artifacts: redeploys unpack a stored release instead of rebuilding
+	artifactRepo := postgres.NewArtifactRepo(dbPool)
+	artifactService := services.NewArtifactService(artifactRepo, gatedDeployRepo, appRepo, agentClient, agentCompat, domain.ArtifactRetention{
+		KeepPerApp: cfg.ArtifactKeepPerApp,
+		MaxAge:     time.Duration(cfg.ArtifactRetentionDays) * 24 * time.Hour,
+	}, logger)
+	// 🧾 SBOMs: recorded per build, re-checked against OSV by the vuln scanner
+	sbomRepo := postgres.NewSBOMRepo(dbPool)
+	sbomService := services.NewSBOMService(sbomRepo, postgres.NewVulnerabilityRepo(dbPool), osv.NewClient(cfg.OSVAPIURL),
+		appRepo, auditRepo, agentCompat, logger).
+		WithLicensePolicy(domain.NewLicensePolicy(strings.Split(cfg.DisallowedLicenses, ",")))
+	agentHandler := handlers.NewAgentHandler(agentLink, agentCompat)
+	deployKeyService := services.NewDeployKeyService(appRepo, postgres.NewDeployKeyRepo(dbPool), domainCrypto, logger)
+	deployKeyHandler := handlers.NewDeployKeyHandler(deployKeyService)
+	var gitClients []domain.GitProviderClient
+	if cfg.GitHubClientID != "" {
+		gitClients = append(gitClients, gitprovider.NewGitHub(cfg.GitHubClientID, cfg.GitHubClientSecret))
+	}
+	if cfg.GitLabClientID != "" {
+		gitClients = append(gitClients, gitprovider.NewGitLab(cfg.GitLabBaseURL, cfg.GitLabClientID, cfg.GitLabClientSecret))
+	}
+	gitService := services.NewGitIntegrationService(gitClients, postgres.NewGitConnectionRepo(dbPool), appRepo, deployKeyService, domainCrypto, cfg.PublicURL, logger)
+	gitHandler := handlers.NewGitHandler(gitService)
+	serverRepo := postgres.NewServerRepo(dbPool)
+	registryService := services.NewRegistryService(postgres.NewRegistryCredentialRepo(dbPool), appRepo, registry.NewVerifier(cfg.RegistryAllowPrivate), domainCrypto, agentClient, agentCompat, logger).
+		WithServers(serverRepo)
+	registryHandler := handlers.NewRegistryHandler(registryService)
+
+	// 🪣 Object storage is optional; without MinIO the bucket endpoints answer 503
+	var objectStore domain.ObjectStorageProvider
+	var blobStore domain.BlobStore
+	if cfg.MinIOEndpoint != "" {
+		store, err := objectstore.NewMinIO(cfg.MinIOEndpoint, cfg.MinIOAccessKey, cfg.MinIOSecretKey, cfg.MinIOUseTLS, cfg.MinIOPublicEndpoint, cfg.MinIORegion)
+		if err != nil {
+			logger.Error("FATAL: Object storage misconfigured", "error", err)
+			os.Exit(1)
+		}
+		objectStore = store
+		blobStore = store
+	}
+	bucketService := services.NewBucketService(appRepo, postgres.NewAppBucketRepo(dbPool), objectStore, domainCrypto, int64(cfg.BucketQuotaMB)<<20, logger)
+	bucketHandler := handlers.NewBucketHandler(bucketService)
+	fileScanService := services.NewFileScanService(appRepo, postgres.NewFileScanRepo(dbPool), auditRepo, agentClient, agentCompat, cfg.FileScanClamAV, logger)
+	fileScanHandler := handlers.NewFileScanHandler(fileScanService)
+	// No domain service is constructed here yet, so adoption answers 503 until one is passed
+	webhookService := services.NewWebhookService(postgres.NewWebhookRepo(dbPool), appRepo, domainCrypto, webhook.NewSender(cfg.WebhookAllowPrivate), logger)
+	driftService := services.NewDriftService(postgres.NewDriftRepo(dbPool), nil, auditRepo, agentClient, agentCompat, logger)
+	headersService := services.NewSecurityHeadersService(postgres.NewSecurityHeadersRepo(dbPool), agentClient, agentCompat, logger)
+	headersHandler := handlers.NewSecurityHeadersHandler(headersService)
+	analyticsRepo := postgres.NewAccessAnalyticsRepo(dbPool)
+	analyticsService := services.NewAccessAnalyticsService(analyticsRepo, agentClient, agentCompat, logger)
+	analyticsHandler := handlers.NewAccessAnalyticsHandler(analyticsService)
+	overviewRepo := postgres.NewAppOverviewRepo(dbPool)
+	overviewService := services.NewAppOverviewService(appRepo, overviewRepo, agentClient, agentCompat, logger)
+	// 🧩 GraphQL: Reads the same repositories; resolvers enforce ownership and RBAC
+	var graphQLHandler http.Handler
+	if cfg.GraphQLEnabled {
+		if graphQLHandler, err = gql.NewHandler(appRepo, overviewRepo, auditRepo, logger); err != nil {
+			logger.Error("FATAL: GraphQL schema invalid", "error", err)
+			os.Exit(1)
+		}
+	}
+	bandwidthService := services.NewBandwidthService(appRepo, postgres.NewBandwidthRepo(dbPool), auditRepo, agentClient, agentCompat, logger)
+	bandwidthHandler := handlers.NewBandwidthHandler(bandwidthService)
+	deployLogService := services.NewDeploymentLogService(
+		postgres.NewDeploymentLogRepo(dbPool), appRepo, blobStore,
+		cfg.DeploymentLogArchiveBucket, cfg.DeploymentLogArchiveDays, logger,
+	)
+	deployLogHandler := handlers.NewDeploymentLogHandler(deployLogService)
+	usageSampleInterval := time.Duration(cfg.UsageSampleMinutes) * time.Minute
+	meteringService := services.NewMeteringService(appRepo, postgres.NewMeteringRepo(dbPool), agentClient, postgres.NewAppBucketRepo(dbPool), objectStore, agentCompat, usageSampleInterval, cfg.UsageExportWebhookURL, cfg.UsageExportWebhookSecret, logger)
+	usageHandler := handlers.NewUsageHandler(meteringService)
+	quotaService := services.NewResourceQuotaService(postgres.NewResourceQuotaRepo(dbPool), auditRepo, logger)
+	quotaHandler := handlers.NewResourceQuotaHandler(quotaService)
+	settingsService := services.NewSettingsService(postgres.NewSettingsRepo(dbPool), auditRepo, logger)
+	if err := settingsService.Load(context.Background()); err != nil {
+		logger.Error("System settings unavailable; starting without maintenance mode", "error", err)
+	}
+	settingsHandler := handlers.NewSettingsHandler(settingsService)
+
+	// 🕒 Time zones: per-user, falling back to the install's (system settings)
+	timezoneService := services.NewTimezoneService(postgres.NewTimezoneRepo(dbPool), settingsService, logger)
+	timezoneHandler := handlers.NewTimezoneHandler(timezoneService)
+
+	// 📐 System Profile: Platform-wide app ceilings; the profile reconciler pushes them to the agents
+	// 🧰 Stack Registry: Toolchain versions apps may pin, and bulk moves between them
+	stackRepo := postgres.NewStackRegistryRepo(dbPool)
+	stackService := services.NewStackRegistryService(stackRepo, gatedDeployRepo, auditRepo, logger)
+	var profileRepo domain.SystemProfileRepository = db.NewPostgresProfileRepository(dbPool)
+	if cfg.ProfileCacheTTLSeconds > 0 {
+		profileRepo = db.NewCachedProfileRepository(profileRepo, time.Duration(cfg.ProfileCacheTTLSeconds)*time.Second)
+	}
+	profileService := services.NewSystemProfileService(profileRepo, auditRepo, agentClient, agentCompat, logger).
+		WithStacks(stackRepo)
+
+	// 🚧 Planned Maintenance: Quiets the AppMonitor and optionally pauses the deploy queue
+	maintenanceWindows := services.NewMaintenanceWindowService(postgres.NewMaintenanceWindowRepo(dbPool), auditRepo, logger)
+	if err := maintenanceWindows.Sync(context.Background()); err != nil {
+		logger.Error("Maintenance windows unavailable; starting without them", "error", err)
+	}
+
+	// 📅 iCal / RSS: Certificate expiries, maintenance and incidents for calendars
+	feedService := services.NewFeedService(postgres.NewFeedRepo(dbPool), userRepo, settingsService, cfg.PublicURL, logger).
+		WithMaintenanceWindows(maintenanceWindows)
+
+	// 🖥️ Remote Servers: Agents join with one-time tokens and get a certificate
+	// from the Brain's private agent CA
+	var serverHandler *handlers.ServerHandler
+	agentCA, err := agentpki.LoadOrCreate(cfg.AgentCADir)
+	if err != nil {
+		logger.Warn("Agent CA unavailable; remote servers disabled", "error", err)
+	} else {
+		serverHandler = handlers.NewServerHandler(services.NewServerEnrollmentService(
+			serverRepo, agentCA, domainCrypto, auditRepo, cfg.PublicURL, logger))
+	}
+
+	// 🔑 Passkeys: WebAuthn alongside passwords; the policy gates password logins
+	var passkeyHandler *handlers.PasskeyHandler
+	if cfg.PasskeyRPID != "" {
+		passkeyService, err := services.NewPasskeyService(
+			postgres.NewPasskeyRepo(dbPool), userRepo, authService, settingsService, auditRepo,
+			cfg.PasskeyRPID, strings.Split(cfg.PasskeyRPOrigins, ","), logger,
+		)
+		if err != nil {
+			logger.Error("Passkeys disabled", "error", err)
+		} else {
+			authService.WithPasswordGate(passkeyService)
+			passkeyHandler = handlers.NewPasskeyHandler(passkeyService)
+		}
+	} else {
+		logger.Warn("Passkeys disabled: set KARI_PUBLIC_URL or PASSKEY_RP_ID")
+	}
+
+	// 🔒 Password policy: Checked on every password write; expired passwords
+	// are refused at login until changed
+	passwordService := services.NewPasswordService(
+		userRepo, authService, settingsService, breach.NewPwnedChecker(cfg.PasswordBreachAPI), auditRepo, logger,
+	)
+	authService.WithPasswordGate(passwordService)
+	passwordHandler := handlers.NewPasswordHandler(passwordService)
+
+	// 📬 Transactional email: nil (and skipped by every caller) without SMTP
+	var mailSender domain.Mailer
+	if cfg.SMTPHost != "" {
+		mailSender = mailer.NewSMTP(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+	}
+
+	// 👥 User Management: RoleService owns rank and last-admin rules, and
+	// records every privilege change with a notice to the affected user
+	roleService := services.NewRoleService(userRepo, logger).
+		WithChangeRecords(auditRepo, postgres.NewUserRepo(dbPool), mailSender, webhookService)
+	userAdminService := services.NewUserAdminService(userAdminRepo, roleService, passwordService, auditRepo, logger)
+	userHandler := handlers.NewUserHandler(userAdminService)
+
+	// 📬 Action Center digests: preferences always work; sending needs SMTP
+	digestService := services.NewDigestService(postgres.NewDigestRepo(dbPool), mailSender, cfg.PublicURL, cfg.DigestSendHour, logger).
+		WithTimezone(settingsService)
+	digestHandler := handlers.NewDigestHandler(digestService)
 
 	authMiddleware := middleware.NewAuthMiddleware(authService, logger)
+	rateLimiter := middleware.NewRateLimiter(middleware.RateLimitConfig{
+		RequestsPerSecond: float64(cfg.RateLimitRPS),
+		Burst:             cfg.RateLimitBurst,
+		VisitorTTL:        time.Duration(cfg.RateLimitVisitorTTLMin) * time.Minute,
+		MaxVisitors:       cfg.RateLimitMaxVisitors,
+	})
+	// 🔑 Sign-in: password and passkey logins share a much tighter bucket
+	authRateLimiter := middleware.NewRateLimiter(middleware.RateLimitConfig{
+		Name:              "auth",
+		RequestsPerSecond: float64(cfg.AuthRateLimitPerMin) / 60,
+		Burst:             cfg.AuthRateLimitBurst,
+		VisitorTTL:        time.Duration(cfg.RateLimitVisitorTTLMin) * time.Minute,
+		MaxVisitors:       cfg.RateLimitMaxVisitors,
+	})
+	prometheus.MustRegister(rateLimiter, authRateLimiter, postgres.NewPoolCollector(dbPool))
+	if chaosFaults.Enabled() {
+		prometheus.MustRegister(faults)
+	}
+	var metricsHandler http.Handler
+	if cfg.MetricsToken != "" {
+		metricsHandler = telemetry.MetricsHandler(cfg.MetricsToken)
+	}
 
 	// --- 5. Background Workers ---
 	workerCtx, cancelWorkers := context.WithCancel(context.Background())
 	defer cancelWorkers()
 
+	// ⏳ Degraded start: Boot-time loads that found no database are retried
+	// once it answers; workers retry on their own intervals
+	if !dbReady {
+		go func() {
+			if postgres.WaitForDatabase(workerCtx, dbPool, logger) != nil {
+				return
+			}
+			if err := settingsService.Load(workerCtx); err != nil {
+				logger.Error("System settings unavailable after recovery", "error", err)
+			}
+			if err := maintenanceWindows.Sync(workerCtx); err != nil {
+				logger.Error("Maintenance windows unavailable after recovery", "error", err)
+			}
+		}()
+	}
+
+	// 💓 Worker heartbeats feed the workers component of /health/ready
+	heartbeats := workers.NewHeartbeats()
+
+	// 👑 Leader Election: Singleton workers below run on one replica at a time;
+	// per-replica loops (caches, the deployment worker's row claims) run everywhere
+	var election domain.LeaderElection
+	if cfg.LeaderElection {
+		elector := postgres.NewLeaderElector(dbPool, logger, time.Duration(cfg.LeaderElectionSeconds)*time.Second)
+		go workers.Supervise(workerCtx, "leader_election", crashService, logger, elector.Run)
+		election = elector
+	}
+	singleton := func(name string, run func(context.Context)) func(context.Context) {
+		return workers.Singleton(election, name, heartbeats, logger, run)
+	}
+
 	// 🛡️ Deployment Worker: Claims tasks and orchestrates gRPC -> SSE
-	deployWorker := worker.NewDeploymentWorker(deployRepo, cryptoService, agentClient, telemetryHub, logger)
-	go deployWorker.Start(workerCtx)
+	deployWorker := worker.NewDeploymentWorker(deployRepo, cryptoService, agentClient, telemetryHub, logger).
+		WithHeartbeats(heartbeats).
+		WithScrubber(redactionService).
+		WithWebhooks(webhookService).
+		WithMaintenance(maintenanceWindows).
+		WithArtifacts(artifactRepo).
+		WithSBOMs(sbomRepo).
+		WithWakeups(postgres.NewDeploymentQueueListener(dbPool))
+
+	// 🚦 Rate limiter sweeper: Forgets idle client IPs so churn cannot grow memory
+	go workers.Supervise(workerCtx, "rate_limit_sweeper", crashService, logger, rateLimiter.Start)
+	go workers.Supervise(workerCtx, "auth_rate_limit_sweeper", crashService, logger, authRateLimiter.Start)
+	if userCache != nil {
+		go workers.Supervise(workerCtx, "user_cache_sweeper", crashService, logger, userCache.Start)
+		go workers.Supervise(workerCtx, "user_cache_listener", crashService, logger, func(ctx context.Context) {
+			userCache.Follow(ctx, dbPool, logger)
+		})
+	}
+	go workers.Supervise(workerCtx, "saga_recovery", crashService, logger, sagas.Start)
+
+	// 💾 Log Spool: Build log writes Postgres rejects wait on disk, then replay
+	if cfg.LogSpoolDir != "" {
+		logSpool, err := spool.NewLogSpool(cfg.LogSpoolDir)
+		if err != nil {
+			logger.Error("Build log spooling disabled", "error", err)
+		} else {
+			deployWorker.WithSpool(logSpool)
+			spoolReplayer := workers.NewLogSpoolReplayer(logSpool, deployRepo.AppendLog, logger).WithHeartbeats(heartbeats)
+			go workers.Supervise(workerCtx, "log_spool", crashService, logger, spoolReplayer.Start)
+		}
+	}
+	go workers.Supervise(workerCtx, "deployment_worker", crashService, logger, deployWorker.Start)
+	if cfg.HubRelay != "" {
+		go workers.Supervise(workerCtx, "log_relay", crashService, logger, func(ctx context.Context) {
+			telemetryHub.RunRelay(ctx, logger)
+		})
+	}
 
 	// 🩺 Health Prober: Background Muscle heartbeat (every 15s)
-	healthProber := workers.NewHealthProber(agentClient, logger)
-	go healthProber.Start(workerCtx)
+	healthProber := workers.NewHealthProber(agentClient, logger).
+		WithObserver(agentCompat).
+		WithObserver(outbox). // Drains pending changes once heartbeats resume
+		WithHeartbeats(heartbeats)
+	go workers.Supervise(workerCtx, "health_prober", crashService, logger, healthProber.Start)
+	go workers.Supervise(workerCtx, "agent_link", crashService, logger, agentLink.Watch)
+	outboxDispatcher := workers.NewOutboxDispatcher(outbox, logger, time.Duration(cfg.OutboxDispatchSeconds)*time.Second).WithHeartbeats(heartbeats)
+	go workers.Supervise(workerCtx, "outbox_dispatcher", crashService, logger, outboxDispatcher.Start)
+
+	// 🪝 Webhook Dispatcher: Signed event deliveries to user endpoints, with retries
+	webhookDispatcher := workers.NewWebhookDispatcher(webhookService, logger, time.Duration(cfg.WebhookDispatchSeconds)*time.Second).WithHeartbeats(heartbeats)
+	go workers.Supervise(workerCtx, "webhook_dispatcher", crashService, logger, webhookDispatcher.Start)
+
+	// 🚧 Maintenance Scheduler: Picks up windows from other Brains and announces start/end
+	maintenanceScheduler := workers.NewMaintenanceScheduler(maintenanceWindows, logger, 30*time.Second).WithHeartbeats(heartbeats)
+	go workers.Supervise(workerCtx, "maintenance_scheduler", crashService, logger, maintenanceScheduler.Start)
+
+	deployScheduler := workers.NewDeployScheduler(deploySchedules, logger, 30*time.Second).WithHeartbeats(heartbeats)
+	go workers.Supervise(workerCtx, "deploy_scheduler", crashService, logger, singleton("deploy_scheduler", deployScheduler.Start))
+
+	artifactPruner := workers.NewArtifactPruner(artifactService, logger, 1*time.Hour).WithHeartbeats(heartbeats)
+	go workers.Supervise(workerCtx, "artifact_pruner", crashService, logger, singleton("artifact_pruner", artifactPruner.Start))
+
+	if cfg.VulnScanHours > 0 {
+		vulnScanner := workers.NewVulnScanner(sbomService, logger, time.Duration(cfg.VulnScanHours)*time.Hour).WithHeartbeats(heartbeats)
+		go workers.Supervise(workerCtx, "vuln_scanner", crashService, logger, singleton("vuln_scanner", vulnScanner.Start))
+	}
+
+	// 🖥️ Server Monitor: Probes enrolled remote agents over mTLS (SSH hosts
+	// over SSH); offline servers raise one alert and mute the app alerts beneath them
+	var serverMonitor *workers.ServerMonitor
+	if agentCA != nil {
+		remoteAgents := agentlink.NewRemotePool(agentCA, cfg.AgentRemotePort, logger)
+		if domainCrypto != nil {
+			remoteAgents.WithSSH(sshexec.NewCredentials(serverRepo, domainCrypto))
+		}
+		defer remoteAgents.Close()
+		profileService.WithServers(serverRepo, remoteAgents)
+		serverMonitor = workers.NewServerMonitor(serverRepo, remoteAgents, auditRepo, logger,
+			time.Duration(cfg.ServerOfflineSeconds)*time.Second).WithHeartbeats(heartbeats)
+		go workers.Supervise(workerCtx, "server_monitor", crashService, logger, singleton("server_monitor", serverMonitor.Start))
+	}
+
+	profileReconciler := workers.NewProfileReconciler(profileService, logger, 1*time.Minute).WithHeartbeats(heartbeats)
+	go workers.Supervise(workerCtx, "profile_reconciler", crashService, logger, singleton("profile_reconciler", profileReconciler.Start))
+
+	// ⚙️ Each replica watches its own env file against what it booted with
+	configWatcher := workers.NewConfigDriftWatcher(cfg, auditRepo, logger, 1*time.Minute).WithHeartbeats(heartbeats)
+	go workers.Supervise(workerCtx, "config_drift_watcher", crashService, logger, configWatcher.Start)
 
 	// App Availability Monitor
-	appMonitor := workers.NewAppMonitor(appRepo, logger, 1*time.Minute)
-	go appMonitor.Start(workerCtx)
+	appMonitor := workers.NewAppMonitor(appRepo, logger, 1*time.Minute).WithHeartbeats(heartbeats).WithWebhooks(webhookService).
+		WithMaintenance(maintenanceWindows)
+	if serverMonitor != nil {
+		appMonitor.WithServers(serverMonitor)
+	}
+	go workers.Supervise(workerCtx, "app_monitor", crashService, logger, singleton("app_monitor", appMonitor.Start))
+
+	// 💬 ChatOps: Slash commands run as the linked Kari user. No ApplicationService
+	// is constructed here yet, so "/kari deploy" reports itself unavailable.
+	chatOpsService := services.NewChatOpsService(postgres.NewChatOpsRepo(dbPool), appRepo, userRepo, nil, appMonitor, chatops.NewSlackResponder(), logger).
+		WithApprovals(approvalService)
+	var discordPublicKey ed25519.PublicKey
+	if cfg.DiscordPublicKey != "" {
+		key, err := hex.DecodeString(cfg.DiscordPublicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			logger.Error("FATAL: DISCORD_PUBLIC_KEY must be a hex Ed25519 public key")
+			os.Exit(1)
+		}
+		discordPublicKey = key
+	}
+
+	go workers.Supervise(workerCtx, "audit_forwarder", crashService, logger, auditForwarder.Start)
+	if err := auditSinkService.Activate(workerCtx); err != nil {
+		logger.Error("Audit forwarding could not be activated", "error", err)
+	}
+
+	// 🧬 File Scanner: Integrity/malware sweep over app directories
+	if cfg.FileScanIntervalHours > 0 {
+		fileScanner := workers.NewFileScanner(appRepo, fileScanService, logger, time.Duration(cfg.FileScanIntervalHours)*time.Hour).WithHeartbeats(heartbeats)
+		go workers.Supervise(workerCtx, "file_scanner", crashService, logger, singleton("file_scanner", fileScanner.Start))
+	}
+
+	// 🧭 Drift Reconciler: Orphaned and missing host resources
+	if cfg.DriftScanMinutes > 0 {
+		driftReconciler := workers.NewDriftReconciler(driftService, logger, time.Duration(cfg.DriftScanMinutes)*time.Minute).WithHeartbeats(heartbeats)
+		go workers.Supervise(workerCtx, "drift_reconciler", crashService, logger, singleton("drift_reconciler", driftReconciler.Start))
+	}
+
+	// 📊 Access Log Collector: Per-domain traffic rollups
+	if cfg.AccessLogIntervalMinutes > 0 {
+		accessCollector := workers.NewAccessLogCollector(analyticsRepo, analyticsService, bandwidthService, logger, time.Duration(cfg.AccessLogIntervalMinutes)*time.Minute).
+			WithHeartbeats(heartbeats)
+		go workers.Supervise(workerCtx, "access_log_collector", crashService, logger, singleton("access_log_collector", accessCollector.Start))
+	}
+
+	// 🧾 Usage Meter: CPU/RAM/storage accrual and monthly billing export
+	if cfg.UsageSampleMinutes > 0 {
+		usageMeter := workers.NewUsageMeter(meteringService, logger, usageSampleInterval).WithHeartbeats(heartbeats)
+		go workers.Supervise(workerCtx, "usage_meter", crashService, logger, singleton("usage_meter", usageMeter.Start))
+	}
+
+	// 🗄️ Retention Pruner: Archives expired log rows, then deletes them, and
+	// purges expired idempotency keys. Without an archive the logs are kept.
+	var archiveSink domain.ArchiveSink
+	if sink, err := archive.NewFileArchiver(cfg.ArchiveDir); err != nil {
+		logger.Error("Retention archiving disabled", "error", err)
+	} else {
+		archiveSink = sink
+	}
+	retentionPruner := workers.NewRetentionPruner(retentionRepo, archiveSink, retentionPolicies, logger).
+		WithExpiry("idempotency_keys", idempotencyRepo).
+		WithHeartbeats(heartbeats)
+	go workers.Supervise(workerCtx, "retention_pruner", crashService, logger, singleton("retention_pruner", retentionPruner.Start))
+
+	// 🗄️ Log Archiver: Old build logs move to MinIO ahead of the retention cutoff
+	if blobStore != nil && cfg.DeploymentLogArchiveDays > 0 {
+		if cfg.DeploymentLogRetentionDays > 0 && cfg.DeploymentLogArchiveDays >= cfg.DeploymentLogRetentionDays {
+			logger.Warn("Deployment logs are pruned before they are archived; lower DEPLOYMENT_LOG_ARCHIVE_DAYS",
+				"archive_days", cfg.DeploymentLogArchiveDays, "retention_days", cfg.DeploymentLogRetentionDays)
+		}
+		logArchiver := workers.NewLogArchiver(deployLogService, logger).WithHeartbeats(heartbeats)
+		go workers.Supervise(workerCtx, "log_archiver", crashService, logger, singleton("log_archiver", logArchiver.Start))
+	}
+
+	// 📬 Digest Mailer: Daily/weekly Action Center summaries per admin
+	if cfg.SMTPHost != "" {
+		digestMailer := workers.NewDigestMailer(digestService, logger).WithHeartbeats(heartbeats)
+		go workers.Supervise(workerCtx, "digest_mailer", crashService, logger, singleton("digest_mailer", digestMailer.Start))
+	}
+
+	// 🩺 Health endpoints: /health/ready aggregates DB, schema, Muscle and workers
+	readinessService := services.NewReadinessService(dbPool, healthProber, postgres.NewSchemaCheck(dbPool), heartbeats, settingsService)
+	healthHandler := kari_http.NewHealthHandler(agentClient, settingsService, readinessService)
 
 	// --- 6. HTTP Gateway ---
 	mux := router.NewRouter(router.RouterConfig{
-		AuthHandler:     authHandler,
-		DeployHandler:   deployHandler,
-		SetupHandler:    setupHandler,
-		AuthMiddleware:  authMiddleware,
-		Logger:          logger,
+		AuthHandler:      authHandler,
+		DeployHandler:    deployHandler,
+		DeployLogHandler: deployLogHandler,
+		AuditHandler:     auditHandler,
+		SearchHandler:    searchHandler,
+		StorageHandler:   storageHandler,
+		AuditSinkHandler: auditSinkHandler,
+		UpdateHandler:    updateHandler,
+		AgentHandler:     agentHandler,
+		DeployKeyHandler: deployKeyHandler,
+		GitHandler:       gitHandler,
+		RegistryHandler:  registryHandler,
+		BucketHandler:    bucketHandler,
+		FileScanHandler:  fileScanHandler,
+		HeadersHandler:   headersHandler,
+		AnalyticsHandler: analyticsHandler,
+		BandwidthHandler: bandwidthHandler,
+		UsageHandler:     usageHandler,
+		QuotaHandler:     quotaHandler,
+		SettingsHandler:  settingsHandler,
+		ProfileHandler:   handlers.NewSystemProfileHandler(profileService),
+		StackHandler:     handlers.NewStackRegistryHandler(stackService),
+		ConfigHandler:    handlers.NewConfigHandler(cfg),
+		HealthHandler:    healthHandler,
+		CrashHandler:     handlers.NewCrashHandler(crashService),
+		DriftHandler:     handlers.NewDriftHandler(driftService),
+		HistoryHandler:   handlers.NewStateHistoryHandler(outbox),
+		WebhookHandler:   handlers.NewWebhookHandler(webhookService),
+		ChatOpsHandler:   handlers.NewChatOpsHandler(chatOpsService, cfg.SlackSigningSecret, discordPublicKey),
+		ApprovalHandler:  handlers.NewApprovalHandler(approvalService),
+		FeedHandler:      handlers.NewFeedHandler(feedService),
+		WindowHandler:    handlers.NewMaintenanceWindowHandler(maintenanceWindows, timezoneService),
+		ScheduleHandler:  handlers.NewDeployScheduleHandler(deploySchedules),
+		ArtifactHandler:  handlers.NewArtifactHandler(artifactService),
+		SBOMHandler:      handlers.NewSBOMHandler(sbomService),
+		AgentRPCHandler:  handlers.NewAgentRPCHandler(agentClient),
+		GraphQLHandler:   graphQLHandler,
+		ServerHandler:    serverHandler,
+		RedactionHandler: handlers.NewRedactionHandler(redactionService),
+		OverviewHandler:  handlers.NewAppOverviewHandler(overviewService),
+		PasskeyHandler:   passkeyHandler,
+		PasswordHandler:  passwordHandler,
+		UserHandler:      userHandler,
+		DigestHandler:    digestHandler,
+		TimezoneHandler:  timezoneHandler,
+		CrashReporter:    crashService,
+		SetupHandler:     setupHandler,
+		AuthMiddleware:   authMiddleware,
+		RateLimiter:      rateLimiter,
+		AuthRateLimiter:  authRateLimiter,
+		MetricsHandler:   metricsHandler,
+		Logger:           logger,
+		IdempotencyRepo:  idempotencyRepo,
+		AuditRepo:        auditRepo,
+		AuditSnapshots: middleware.AuditSnapshots{
+			"applications": func(ctx context.Context, id uuid.UUID) (any, error) {
+				rc, _ := domain.RequestContextFrom(ctx)
+				return appRepo.GetByID(ctx, id, rc.UserID)
+			},
+			"users": func(ctx context.Context, id uuid.UUID) (any, error) {
+				return userAdminRepo.Get(ctx, id)
+			},
+		},
 	})
 
+	// 🔌 Listener: Bound before serving so a bad socket path fails boot loudly
+	listener, err := kari_http.Listen(cfg.ListenMode, cfg.Port, cfg.ListenSocket, cfg.ListenSocketMode)
+	if err != nil {
+		logger.Error("FATAL: Cannot listen", "mode", cfg.ListenMode, "error", err)
+		os.Exit(1)
+	}
+
+	// 🔐 Panel TLS: Without a front proxy the Brain terminates HTTPS itself,
+	// with a certificate for APP_DOMAIN it obtains and renews over ACME.
+	// Every replica keeps its own copy in TLS_STATE_DIR.
+	var redirectServer *http.Server
+	switch cfg.TLSMode {
+	case "":
+	case "acme":
+		if cfg.ListenMode == "unix" {
+			logger.Error("FATAL: TLS_MODE=acme needs LISTEN_MODE=tcp; a socket is fronted by a proxy")
+			os.Exit(1)
+		}
+		panelTLS, err := paneltls.NewManager(cfg.AppDomain, cfg.AdminEmail, cfg.ACMEDirectoryURL, cfg.TLSStateDir, logger)
+		if err != nil {
+			logger.Error("FATAL: Panel TLS misconfigured", "error", err)
+			os.Exit(1)
+		}
+		panelTLS.WithTransport(faults.WrapACMETransport)
+		listener = tls.NewListener(listener, panelTLS.TLSConfig())
+		redirectServer = &http.Server{
+			Addr:         ":" + cfg.HTTPRedirectPort,
+			Handler:      panelTLS.HTTPHandler(cfg.Port),
+			ReadTimeout:  5 * time.Second,
+			WriteTimeout: 5 * time.Second,
+		}
+		go func() {
+			if err := redirectServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("CRITICAL: HTTP redirect server crashed", "error", err)
+				os.Exit(1)
+			}
+		}()
+		go workers.Supervise(workerCtx, "panel_tls", crashService, logger, panelTLS.Start)
+	default:
+		logger.Error("FATAL: TLS_MODE must be empty or \"acme\"", "value", cfg.TLSMode)
+		os.Exit(1)
+	}
+
 	server := &http.Server{
-		Addr:         ":" + cfg.Port,
 		Handler:      mux,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
@@ -156,8 +836,8 @@ func main() {
 	// (stop channel already created above for Setup lockdown)
 
 	go func() {
-		logger.Info("🌐 Kari Panel API active", "port", cfg.Port)
-		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Info("🌐 Kari Panel API active", "addr", listener.Addr().String())
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			logger.Error("CRITICAL: Server crashed", "error", err)
 			os.Exit(1)
 		}
@@ -165,6 +845,14 @@ func main() {
 
 	<-stop
 	logger.Info("🛑 Shutting down...")
+
+	// 🚰 Drain: No new deployments are claimed; the one streaming may finish
+	// (the API keeps serving meanwhile, so its log can still be watched)
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownDrainSeconds)*time.Second)
+	if err := deployWorker.Drain(drainCtx); err != nil {
+		logger.Warn("Deployment drain timed out", "error", err)
+	}
+	cancelDrain()
 	cancelWorkers() // Stop workers first to prevent new gRPC calls
 
 	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
@@ -172,5 +860,22 @@ func main() {
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		logger.Error("ERROR: Forced shutdown", "error", err)
 	}
+	if redirectServer != nil {
+		_ = redirectServer.Shutdown(shutdownCtx)
+	}
 	logger.Info("✅ Kari Panel Brain shutdown. Muscle Agent remains in jail.")
 }
+
+// unsealSecrets has the Muscle decrypt the sealed settings. kari-api starts
+// after kari-agent, but the socket may not be bound yet, so the call waits
+// for the link up to timeout.
+func unsealSecrets(client agent.SystemAgentClient, cfg *config.Config, sealed map[string]string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	resp, err := client.UnsealSecrets(ctx, &agent.SealedSecrets{Values: sealed}, grpc.WaitForReady(true))
+	if err != nil {
+		return err
+	}
+	return cfg.ApplyUnsealed(resp.Values)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"kari/api/internal/config"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/db/postgres"
+	"kari/api/internal/setup"
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+// Password bounds match the setup wizard (72 is bcrypt's limit).
+const (
+	minRecoveryPassword = 12
+	maxRecoveryPassword = 72
+)
+
+// runRecoverAdmin is "kari-api recover-admin": break-glass access for a
+// panel whose every Super Admin is locked out. It works only on the host:
+// setup.lock must exist, the env file must be readable, and the operator must
+// present the master key issued at setup.
+//
+//	kari-api recover-admin --email admin@example.com --master-key-file /root/kari-master.key
+//	kari-api recover-admin --email new@example.com --create --master-key-file ... --password-stdin
+//
+// Without --password-stdin a random password is generated and printed once.
+// Either way it must be changed at the next login, the account's passkeys
+// are removed for re-enrolment, and a critical system alert records the
+// recovery in the Action Center.
+func runRecoverAdmin(args []string) int {
+	fs := flag.NewFlagSet("recover-admin", flag.ContinueOnError)
+	email := fs.String("email", "", "Rank-0 account to reset (or create with --create)")
+	create := fs.Bool("create", false, "Create a new Rank-0 account instead of resetting an existing one")
+	keyFile := fs.String("master-key-file", "", "File holding the master key (ENCRYPTION_KEY) issued at setup")
+	lockPath := fs.String("lock", setup.DefaultLockPath, "setup.lock written when setup finished")
+	passwordStdin := fs.Bool("password-stdin", false, "Read the new password from stdin instead of generating one")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	fail := func(format string, a ...any) int {
+		fmt.Fprintf(os.Stderr, "kari-api recover-admin: "+format+"\n", a...)
+		return 2
+	}
+
+	*email = strings.ToLower(strings.TrimSpace(*email))
+	if *email == "" || *keyFile == "" {
+		return fail("--email and --master-key-file are required")
+	}
+
+	// 1. 🛡️ Host proof: a finished setup, and the key it produced
+	if !setup.Locked(*lockPath) {
+		return fail("%s does not exist: finish setup instead of recovering", *lockPath)
+	}
+
+	password, generated, err := recoveryPassword(*passwordStdin)
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	cfg := config.Load()
+	if sealed := cfg.Sealed(); len(sealed) > 0 {
+		// Sealed secrets mean the Muscle must be up; it is on a working host
+		conn, err := grpc.Dial(
+			cfg.AgentSocketPath,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", addr)
+			}),
+		)
+		if err != nil {
+			logger.Error("Recover: gRPC link failed", "error", err)
+			return 1
+		}
+		defer conn.Close()
+		if err := unsealSecrets(agent.NewSystemAgentClient(conn), cfg, sealed, 30*time.Second); err != nil {
+			logger.Error("Recover: Cannot unseal secrets", "error", err)
+			return 1
+		}
+	}
+
+	if err := verifyMasterKey(*keyFile, cfg.MasterKeyHex); err != nil {
+		logger.Error("Recover: Master key check failed", "error", err)
+		return 1
+	}
+
+	// 2. The account
+	pool, err := postgres.NewPool(ctx, cfg.DatabaseURL, postgres.PoolOptions{MaxConns: 2, MinConns: 1})
+	if err != nil {
+		logger.Error("Recover: DB failed", "error", err)
+		return 1
+	}
+	defer pool.Close()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		logger.Error("Recover: Failed to hash password", "error", err)
+		return 1
+	}
+	userID, passkeysRemoved, err := postgres.RecoverAdmin(ctx, pool, *email, string(hash), *create)
+	if err != nil {
+		logger.Error("Recover: Failed", "error", err)
+		return 1
+	}
+
+	// 3. 🚨 Compliance: Break-glass use is never quiet
+	action := "reset"
+	if *create {
+		action = "created"
+	}
+	host, _ := os.Hostname()
+	resourceID := userID.String()
+	metadata := map[string]any{
+		"email":            *email,
+		"action":           action,
+		"host":             host,
+		"os_user":          os.Getenv("USER"),
+		"uid":              os.Getuid(),
+		"lock_file":        *lockPath,
+		"key_file":         *keyFile,
+		"passkeys_removed": passkeysRemoved,
+		"via":              "kari-api recover-admin",
+		"recovered_at":     time.Now().UTC().Format(time.RFC3339),
+	}
+	auditRepo := postgres.NewAuditRepository(pool)
+	if err := auditRepo.CreateAlert(ctx, &domain.SystemAlert{
+		Severity:   "critical",
+		Category:   "security",
+		ResourceID: &resourceID,
+		Message:    fmt.Sprintf("🚨 Break-glass recovery: Super Admin %s was %s from the host console", *email, action),
+		Metadata:   metadata,
+	}); err != nil {
+		// The account changed; say so loudly rather than pretend it did not
+		logger.Error("Recover: Failed to record the system alert", "error", err)
+	}
+	if err := auditRepo.CreateTenantLog(ctx, &domain.TenantLog{
+		TenantID:     userID,
+		Action:       "user.break_glass_recovery",
+		ResourceType: "user",
+		ResourceID:   resourceID,
+		Metadata:     metadata,
+	}); err != nil {
+		logger.Error("Recover: Failed to record the audit log", "error", err)
+	}
+	logger.Warn("🚨 Recover: Super Admin access restored; the password must be changed at next login and a new passkey enrolled",
+		"email", *email, "user_id", userID, "action", action, "passkeys_removed", passkeysRemoved)
+
+	if generated {
+		fmt.Printf("Temporary password for %s: %s\n", *email, password)
+	}
+	return 0
+}
+
+// verifyMasterKey compares the operator's copy of the master key with the
+// one this panel runs on, in constant time.
+func verifyMasterKey(path, configured string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	given := strings.ToLower(strings.TrimSpace(string(raw)))
+	if configured == "" {
+		return fmt.Errorf("ENCRYPTION_KEY is not configured in this environment")
+	}
+	if subtle.ConstantTimeCompare([]byte(given), []byte(strings.ToLower(configured))) != 1 {
+		return fmt.Errorf("%s does not hold this panel's master key", path)
+	}
+	return nil
+}
+
+// recoveryPassword reads the first line of stdin, or generates a password
+// the operator hands over out of band.
+func recoveryPassword(fromStdin bool) (password string, generated bool, err error) {
+	if !fromStdin {
+		b := make([]byte, 12)
+		if _, err := rand.Read(b); err != nil {
+			return "", false, err
+		}
+		return hex.EncodeToString(b), true, nil
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", false, fmt.Errorf("no password on stdin: %w", err)
+	}
+	password = strings.TrimRight(line, "\r\n")
+	if n := len(password); n < minRecoveryPassword || n > maxRecoveryPassword {
+		return "", false, fmt.Errorf("password must be %d-%d characters", minRecoveryPassword, maxRecoveryPassword)
+	}
+	return password, false, nil
+}
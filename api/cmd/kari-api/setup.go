@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"kari/api/internal/setup"
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+// runSetup is "kari-api setup": the wizard's Finalize without a browser, for
+// Ansible and cloud-init. Everything comes from --config and KARI_SETUP_*.
+//
+//	kari-api setup --config /root/setup.yaml
+func runSetup(args []string) int {
+	fs := flag.NewFlagSet("setup", flag.ContinueOnError)
+	configPath := fs.String("config", "", "YAML setup spec (optional when KARI_SETUP_* is set)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	spec, err := setup.LoadSpec(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "kari-api setup: %v\n", err)
+		return 2
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// The Muscle is needed only to seal; plaintext setups never call it
+	conn, err := grpc.Dial(
+		spec.AgentSocket,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", addr)
+		}),
+	)
+	if err != nil {
+		logger.Error("Setup: gRPC link failed", "error", err)
+		return 1
+	}
+	defer conn.Close()
+
+	if err := setup.Run(ctx, spec, agent.NewSystemAgentClient(conn), logger); err != nil {
+		logger.Error("Setup: Failed", "error", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// apiClient speaks to the Brain the way the panel UI does.
+type apiClient struct {
+	base   *url.URL
+	token  string
+	http   *http.Client
+	dialer *websocket.Dialer
+}
+
+func newAPIClient(target, token string, insecure bool) (*apiClient, error) {
+	base, err := url.Parse(strings.TrimSuffix(target, "/"))
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") {
+		return nil, fmt.Errorf("invalid --target %q", target)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: insecure}
+
+	return &apiClient{
+		base:  base,
+		token: token,
+		http: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, MaxIdleConnsPerHost: 64},
+		},
+		dialer: &websocket.Dialer{
+			HandshakeTimeout: 10 * time.Second,
+			TLSClientConfig:  tlsConfig,
+		},
+	}, nil
+}
+
+// triggerDeploy queues one deployment and returns its ID.
+func (c *apiClient) triggerDeploy(ctx context.Context, appID string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.base.String()+"/api/v1/applications/"+url.PathEscape(appID)+"/deploy", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var queued struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&queued); err != nil || queued.ID == "" {
+		return "", fmt.Errorf("deploy response has no id: %v", err)
+	}
+	return queued.ID, nil
+}
+
+// dialLogs opens a deployment log stream; plain output keeps the frames
+// small so the figures reflect the Hub rather than ANSI rendering.
+func (c *apiClient) dialLogs(ctx context.Context, deploymentID string) (*websocket.Conn, error) {
+	u := *c.base
+	u.Scheme = map[string]string{"http": "ws", "https": "wss"}[c.base.Scheme]
+	u.Path += "/api/v1/ws/deployments/" + url.PathEscape(deploymentID)
+	u.RawQuery = "format=plain"
+
+	header := http.Header{"Authorization": {"Bearer " + c.token}}
+	conn, resp, err := c.dialer.DialContext(ctx, u.String(), header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("HTTP %d: %w", resp.StatusCode, err)
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+// fanoutStats counts what the Hub delivered to every subscriber.
+type fanoutStats struct {
+	connected atomic.Int64
+	failed    atomic.Int64
+	messages  atomic.Int64
+	bytes     atomic.Int64
+
+	mu        sync.Mutex
+	firstByte []time.Duration
+}
+
+// subscribe holds one stream open until the Brain closes it (the build
+// ended) or ctx is done.
+func (f *fanoutStats) subscribe(ctx context.Context, client *apiClient, deploymentID string) {
+	opened := time.Now()
+	conn, err := client.dialLogs(ctx, deploymentID)
+	if err != nil {
+		f.failed.Add(1)
+		return
+	}
+	f.connected.Add(1)
+
+	// ReadMessage does not take a context; closing the conn unblocks it
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	defer conn.Close()
+
+	first := true
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if first {
+			first = false
+			f.mu.Lock()
+			f.firstByte = append(f.firstByte, time.Since(opened))
+			f.mu.Unlock()
+		}
+		f.messages.Add(1)
+		f.bytes.Add(int64(len(msg)))
+	}
+}
+
+func (f *fanoutStats) result(elapsed time.Duration) fanoutResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	msgs := f.messages.Load()
+	return fanoutResult{
+		Connected:      f.connected.Load(),
+		Failed:         f.failed.Load(),
+		Messages:       msgs,
+		Bytes:          f.bytes.Load(),
+		MessagesPerSec: float64(msgs) / elapsed.Seconds(),
+		FirstMessage:   summarize(f.firstByte),
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// dbProbe reads the Brain's database directly. It only ever SELECTs, so it
+// is safe to point at a production replica of the schema.
+type dbProbe struct {
+	conn *pgx.Conn
+}
+
+func openDBProbe(ctx context.Context, dsn string) (*dbProbe, error) {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+	return &dbProbe{conn: conn}, nil
+}
+
+func (p *dbProbe) Close() { p.conn.Close(context.Background()) }
+
+// dbCounters are the cumulative pg_stat_database figures for the current
+// database. They cover every client, not just the Brain.
+type dbCounters struct {
+	Commits  int64
+	Inserted int64
+	Updated  int64
+	Deleted  int64
+}
+
+func (p *dbProbe) counters(ctx context.Context) (dbCounters, error) {
+	// Stats are snapshotted per transaction; outside one each query sees fresh numbers
+	var c dbCounters
+	err := p.conn.QueryRow(ctx, `
+		SELECT xact_commit, tup_inserted, tup_updated, tup_deleted
+		FROM pg_stat_database WHERE datname = current_database()`,
+	).Scan(&c.Commits, &c.Inserted, &c.Updated, &c.Deleted)
+	if err != nil {
+		return c, fmt.Errorf("database: read pg_stat_database: %w", err)
+	}
+	return c, nil
+}
+
+func (c dbCounters) sub(o dbCounters) dbCounters {
+	return dbCounters{
+		Commits:  c.Commits - o.Commits,
+		Inserted: c.Inserted - o.Inserted,
+		Updated:  c.Updated - o.Updated,
+		Deleted:  c.Deleted - o.Deleted,
+	}
+}
+
+func (c dbCounters) perSecond(elapsed time.Duration) writeRates {
+	s := elapsed.Seconds()
+	return writeRates{
+		CommitsPerSec: float64(c.Commits) / s,
+		InsertsPerSec: float64(c.Inserted) / s,
+		UpdatesPerSec: float64(c.Updated) / s,
+		DeletesPerSec: float64(c.Deleted) / s,
+	}
+}
+
+// claimTracker watches queued deployments until a worker claims them.
+// Latency is created_at to the claim's updated_at, both stamped by Postgres,
+// so client clock skew and HTTP time are excluded. A deployment that moves
+// on again before a poll sees it is measured to its later update, which
+// --poll keeps small.
+type claimTracker struct {
+	mu        sync.Mutex
+	pending   map[string]struct{}
+	latencies []time.Duration
+}
+
+func newClaimTracker() *claimTracker {
+	return &claimTracker{pending: make(map[string]struct{})}
+}
+
+func (t *claimTracker) add(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[id] = struct{}{}
+}
+
+func (t *claimTracker) poll(ctx context.Context, p *dbProbe, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		t.mu.Lock()
+		ids := make([]string, 0, len(t.pending))
+		for id := range t.pending {
+			ids = append(ids, id)
+		}
+		t.mu.Unlock()
+		if len(ids) == 0 {
+			continue
+		}
+
+		rows, err := p.conn.Query(ctx, `
+			SELECT id::text, EXTRACT(EPOCH FROM updated_at - created_at)::float8
+			FROM deployments
+			WHERE id::text = ANY($1) AND status <> 'PENDING'`, ids)
+		if err != nil {
+			continue
+		}
+		claimed, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (claim, error) {
+			var (
+				c    claim
+				secs float64
+			)
+			err := row.Scan(&c.id, &secs)
+			c.latency = time.Duration(secs * float64(time.Second))
+			return c, err
+		})
+		if err != nil {
+			continue
+		}
+
+		t.mu.Lock()
+		for _, c := range claimed {
+			delete(t.pending, c.id)
+			t.latencies = append(t.latencies, c.latency)
+		}
+		t.mu.Unlock()
+	}
+}
+
+type claim struct {
+	id      string
+	latency time.Duration
+}
+
+// waitSettled returns once nothing is pending, or ctx is done.
+func (t *claimTracker) waitSettled(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		t.mu.Lock()
+		left := len(t.pending)
+		t.mu.Unlock()
+		if left == 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *claimTracker) result(measured bool) claimResult {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !measured {
+		return claimResult{}
+	}
+	return claimResult{
+		Measured:  true,
+		Claimed:   len(t.latencies),
+		Unclaimed: len(t.pending),
+		Latency:   summarize(t.latencies),
+	}
+}
@@ -0,0 +1,249 @@
+// kari-loadtest drives synthetic deployments and log subscribers against a
+// running Brain to validate the deployment queue and the log Hub under load.
+//
+//	kari-loadtest --target https://panel.example.com --token $JWT \
+//	    --app 3f0c... --app 9a41... --deployments 200 --rate 20 \
+//	    --subscribers 5 --database-url postgres://kari@db/kari
+//
+// Deploys go through POST /api/v1/applications/{id}/deploy exactly as the UI
+// sends them, and every queued deployment gets --subscribers WebSocket
+// clients on /api/v1/ws/deployments/{id}. Claim latency and DB write rates
+// need --database-url; without it only trigger and fan-out figures are shown.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// appIDs collects repeatable --app flags.
+type appIDs []string
+
+func (a *appIDs) String() string { return strings.Join(*a, ",") }
+
+func (a *appIDs) Set(v string) error {
+	for _, id := range strings.Split(v, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			*a = append(*a, id)
+		}
+	}
+	return nil
+}
+
+type options struct {
+	target      string
+	token       string
+	apps        appIDs
+	deployments int
+	rate        float64
+	subscribers int
+	databaseURL string
+	poll        time.Duration
+	timeout     time.Duration
+	insecure    bool
+	jsonOut     bool
+}
+
+func main() {
+	opts := parseOptions()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	rep, err := run(ctx, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Load test failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	if opts.jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(rep)
+	} else {
+		rep.print(os.Stdout)
+	}
+	if rep.Deploys.Failed > 0 || rep.Claims.Unclaimed > 0 {
+		os.Exit(3)
+	}
+}
+
+// parseOptions reads flags, falling back to KARI_LOADTEST_* env vars so the
+// bearer token and DSN stay out of shell history.
+func parseOptions() options {
+	var opts options
+
+	flag.StringVar(&opts.target, "target", envOr("KARI_LOADTEST_TARGET", "http://localhost:8080"), "Brain base URL")
+	flag.StringVar(&opts.token, "token", os.Getenv("KARI_LOADTEST_TOKEN"), "bearer token with applications:deploy")
+	flag.Var(&opts.apps, "app", "application ID to deploy, round-robin (repeatable or comma separated)")
+	flag.IntVar(&opts.deployments, "deployments", 50, "deployments to queue")
+	flag.Float64Var(&opts.rate, "rate", 10, "deployments queued per second")
+	flag.IntVar(&opts.subscribers, "subscribers", 3, "WebSocket log subscribers per deployment")
+	flag.StringVar(&opts.databaseURL, "database-url", os.Getenv("KARI_LOADTEST_DATABASE_URL"), "Postgres DSN for claim latency and write rates (optional)")
+	flag.DurationVar(&opts.poll, "poll", 100*time.Millisecond, "claim polling interval; bounds claim latency resolution")
+	flag.DurationVar(&opts.timeout, "timeout", 5*time.Minute, "give up on claims and streams after this long")
+	flag.BoolVar(&opts.insecure, "insecure", false, "skip server certificate verification")
+	flag.BoolVar(&opts.jsonOut, "json", false, "print the report as JSON")
+	flag.Parse()
+
+	if err := opts.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(2)
+	}
+	return opts
+}
+
+func (o options) validate() error {
+	switch {
+	case o.token == "":
+		return errors.New("--token (or KARI_LOADTEST_TOKEN) is required")
+	case len(o.apps) == 0:
+		return errors.New("at least one --app is required")
+	case o.deployments < 1:
+		return errors.New("--deployments must be at least 1")
+	case o.rate <= 0:
+		return errors.New("--rate must be positive")
+	case o.subscribers < 0:
+		return errors.New("--subscribers cannot be negative")
+	}
+	return nil
+}
+
+// run queues the deployments at the requested rate, attaches subscribers to
+// each, and waits until every deployment is claimed and every stream closed
+// (or the timeout passes).
+func run(ctx context.Context, opts options) (*report, error) {
+	client, err := newAPIClient(opts.target, opts.token, opts.insecure)
+	if err != nil {
+		return nil, err
+	}
+
+	var db *dbProbe
+	if opts.databaseURL != "" {
+		if db, err = openDBProbe(ctx, opts.databaseURL); err != nil {
+			return nil, err
+		}
+		defer db.Close()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+
+	var before dbCounters
+	if db != nil {
+		if before, err = db.counters(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	rep := &report{Target: opts.target, Apps: len(opts.apps), Subscribers: opts.subscribers}
+	claims := newClaimTracker()
+	fanout := &fanoutStats{}
+
+	var pollWG sync.WaitGroup
+	pollCtx, stopPoll := context.WithCancel(ctx)
+	defer stopPoll()
+	if db != nil {
+		pollWG.Add(1)
+		go func() {
+			defer pollWG.Done()
+			claims.poll(pollCtx, db, opts.poll)
+		}()
+	}
+
+	start := time.Now()
+	var (
+		triggerWG sync.WaitGroup
+		streamWG  sync.WaitGroup
+		mu        sync.Mutex
+		triggers  []time.Duration
+	)
+	interval := time.Duration(float64(time.Second) / opts.rate)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+queue:
+	for i := 0; i < opts.deployments; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				break queue
+			case <-ticker.C:
+			}
+		}
+
+		appID := opts.apps[i%len(opts.apps)]
+		triggerWG.Add(1)
+		go func() {
+			defer triggerWG.Done()
+			sent := time.Now()
+			id, err := client.triggerDeploy(ctx, appID)
+			took := time.Since(sent)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				rep.Deploys.Failed++
+				rep.Deploys.noteError(err)
+				return
+			}
+			rep.Deploys.Queued++
+			triggers = append(triggers, took)
+			claims.add(id)
+
+			for range opts.subscribers {
+				streamWG.Add(1)
+				go func() {
+					defer streamWG.Done()
+					fanout.subscribe(ctx, client, id)
+				}()
+			}
+		}()
+	}
+	triggerWG.Wait()
+	rep.Deploys.Elapsed = seconds(time.Since(start))
+	rep.Deploys.Latency = summarize(triggers)
+
+	// Claims are settled once nothing is pending; streams end on their own
+	// when the build finishes, so both are bounded only by the timeout
+	if db != nil {
+		claims.waitSettled(ctx, opts.poll)
+		stopPoll()
+		pollWG.Wait()
+	}
+	streamWG.Wait()
+	elapsed := time.Since(start)
+
+	rep.Elapsed = seconds(elapsed)
+	rep.Claims = claims.result(db != nil)
+	rep.Fanout = fanout.result(elapsed)
+
+	if db != nil {
+		// The run context may have timed out; the closing sample still matters
+		sampleCtx, done := context.WithTimeout(context.Background(), 10*time.Second)
+		defer done()
+		after, err := db.counters(sampleCtx)
+		if err != nil {
+			return nil, err
+		}
+		rates := after.sub(before).perSecond(elapsed)
+		rep.Writes = &rates
+	}
+	return rep, nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// maxErrors keeps the report readable when every request fails the same way.
+const maxErrors = 5
+
+type report struct {
+	Target      string       `json:"target"`
+	Apps        int          `json:"apps"`
+	Subscribers int          `json:"subscribers_per_deployment"`
+	Elapsed     float64      `json:"elapsed_seconds"`
+	Deploys     deployResult `json:"deploys"`
+	Claims      claimResult  `json:"claims"`
+	Fanout      fanoutResult `json:"fanout"`
+	Writes      *writeRates  `json:"db_writes,omitempty"`
+}
+
+type deployResult struct {
+	Queued  int            `json:"queued"`
+	Failed  int            `json:"failed"`
+	Elapsed float64        `json:"elapsed_seconds"`
+	Latency latencySummary `json:"trigger_latency"`
+	Errors  []string       `json:"errors,omitempty"`
+}
+
+func (d *deployResult) noteError(err error) {
+	if len(d.Errors) < maxErrors {
+		d.Errors = append(d.Errors, err.Error())
+	}
+}
+
+type claimResult struct {
+	Measured  bool           `json:"measured"`
+	Claimed   int            `json:"claimed"`
+	Unclaimed int            `json:"unclaimed"`
+	Latency   latencySummary `json:"claim_latency"`
+}
+
+type fanoutResult struct {
+	Connected      int64          `json:"connected"`
+	Failed         int64          `json:"failed"`
+	Messages       int64          `json:"messages"`
+	Bytes          int64          `json:"bytes"`
+	MessagesPerSec float64        `json:"messages_per_second"`
+	FirstMessage   latencySummary `json:"first_message_latency"`
+}
+
+type writeRates struct {
+	CommitsPerSec float64 `json:"commits_per_second"`
+	InsertsPerSec float64 `json:"inserts_per_second"`
+	UpdatesPerSec float64 `json:"updates_per_second"`
+	DeletesPerSec float64 `json:"deletes_per_second"`
+}
+
+// latencySummary is in milliseconds so the JSON reads without conversion.
+type latencySummary struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50_ms"`
+	P95   float64 `json:"p95_ms"`
+	P99   float64 `json:"p99_ms"`
+	Max   float64 `json:"max_ms"`
+}
+
+func summarize(samples []time.Duration) latencySummary {
+	if len(samples) == 0 {
+		return latencySummary{}
+	}
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	return latencySummary{
+		Count: len(sorted),
+		P50:   millis(percentile(sorted, 0.50)),
+		P95:   millis(percentile(sorted, 0.95)),
+		P99:   millis(percentile(sorted, 0.99)),
+		Max:   millis(sorted[len(sorted)-1]),
+	}
+}
+
+// percentile uses nearest-rank on an already sorted slice.
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	i := int(q*float64(len(sorted))+0.999999) - 1
+	return sorted[max(0, min(i, len(sorted)-1))]
+}
+
+func millis(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+
+func seconds(d time.Duration) float64 { return d.Seconds() }
+
+func (l latencySummary) String() string {
+	if l.Count == 0 {
+		return "no samples"
+	}
+	return fmt.Sprintf("p50 %.1fms  p95 %.1fms  p99 %.1fms  max %.1fms  (n=%d)", l.P50, l.P95, l.P99, l.Max, l.Count)
+}
+
+func (r *report) print(w io.Writer) {
+	fmt.Fprintf(w, "🏋️ Kari load test against %s (%.1fs)\n\n", r.Target, r.Elapsed)
+
+	fmt.Fprintln(w, "Deployments")
+	fmt.Fprintf(w, "  queued     %d of %d over %.1fs across %d app(s)\n", r.Deploys.Queued, r.Deploys.Queued+r.Deploys.Failed, r.Deploys.Elapsed, r.Apps)
+	fmt.Fprintf(w, "  trigger    %s\n", r.Deploys.Latency)
+	for _, e := range r.Deploys.Errors {
+		fmt.Fprintf(w, "  ❌ %s\n", e)
+	}
+
+	fmt.Fprintln(w, "\nQueue claims")
+	if r.Claims.Measured {
+		fmt.Fprintf(w, "  claimed    %d (%d still pending)\n", r.Claims.Claimed, r.Claims.Unclaimed)
+		fmt.Fprintf(w, "  latency    %s\n", r.Claims.Latency)
+	} else {
+		fmt.Fprintln(w, "  not measured (no --database-url)")
+	}
+
+	fmt.Fprintln(w, "\nLog fan-out")
+	fmt.Fprintf(w, "  streams    %d connected, %d failed (%d per deployment)\n", r.Fanout.Connected, r.Fanout.Failed, r.Subscribers)
+	fmt.Fprintf(w, "  delivered  %d messages, %d bytes, %.1f msg/s\n", r.Fanout.Messages, r.Fanout.Bytes, r.Fanout.MessagesPerSec)
+	fmt.Fprintf(w, "  first msg  %s\n", r.Fanout.FirstMessage)
+
+	if r.Writes != nil {
+		fmt.Fprintln(w, "\nDatabase writes (whole database, all clients)")
+		fmt.Fprintf(w, "  commits    %.1f/s\n", r.Writes.CommitsPerSec)
+		fmt.Fprintf(w, "  rows       %.1f inserted/s, %.1f updated/s, %.1f deleted/s\n", r.Writes.InsertsPerSec, r.Writes.UpdatesPerSec, r.Writes.DeletesPerSec)
+	}
+}
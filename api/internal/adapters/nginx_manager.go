@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"text/template"
 
+	"google.golang.org/protobuf/proto"
 	"kari/api/internal/config"
 	"kari/api/internal/core/domain"
 	pb "kari/api/proto/kari/agent/v1" // Aliased for clarity
@@ -21,18 +22,26 @@ import (
 type NginxManager struct {
 	Config      *config.Config
 	AgentClient pb.SystemAgentClient
+	Outbox      outboxSubmitter
 	Logger      *slog.Logger
 	Template    *template.Template
 }
 
+// outboxSubmitter is satisfied by services.Outbox: vhost writes and the
+// follow-up reload are recorded, and queued in order while the Muscle is offline.
+type outboxSubmitter interface {
+	Submit(ctx context.Context, change *domain.StateChange, req proto.Message) (bool, error)
+}
+
 // Strictly enforce valid domain names (e.g., sub.example.com)
 var domainRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9.-]{1,253}[a-zA-Z0-9]$`)
 
-func NewNginxManager(cfg *config.Config, agentClient pb.SystemAgentClient, logger *slog.Logger) *NginxManager {
+func NewNginxManager(cfg *config.Config, agentClient pb.SystemAgentClient, outbox outboxSubmitter, logger *slog.Logger) *NginxManager {
 	tmpl := template.Must(template.New("nginx_vhost").Parse(nginxTemplate))
 	return &NginxManager{
 		Config:      cfg,
 		AgentClient: agentClient,
+		Outbox:      outbox,
 		Logger:      logger,
 		Template:    tmpl,
 	}
@@ -81,20 +90,37 @@ func (m *NginxManager) ApplyConfig(ctx context.Context, appConfig domain.WebServ
 		FileMode:     "0644",
 	}
 
-	if _, err := m.AgentClient.WriteSystemFile(ctx, writeReq); err != nil {
+	queued, err := m.Outbox.Submit(ctx, &domain.StateChange{
+		Event:        domain.EventVhostWritten,
+		ResourceType: "domain",
+		ResourceID:   appConfig.DomainName,
+		Method:       pb.SystemAgent_WriteSystemFile_FullMethodName,
+		Description:  "Write Nginx vhost for " + appConfig.DomainName,
+	}, writeReq)
+	if err != nil {
 		return fmt.Errorf("agent failed to write Nginx config: %w", err)
 	}
 
-	// Command the Rust Agent to reload the Nginx Daemon
+	// Command the Rust Agent to reload the Nginx Daemon (queued behind the write if offline)
 	reloadReq := &pb.ServiceRequest{
 		ServiceName: "nginx",
 		Action:      pb.ServiceAction_RELOAD,
 	}
 
-	if _, err := m.AgentClient.ManageService(ctx, reloadReq); err != nil {
+	if _, err := m.Outbox.Submit(ctx, &domain.StateChange{
+		Event:        domain.EventServiceReloaded,
+		ResourceType: "service",
+		ResourceID:   "nginx",
+		Method:       pb.SystemAgent_ManageService_FullMethodName,
+		Description:  "Reload Nginx for " + appConfig.DomainName,
+	}, reloadReq); err != nil {
 		return fmt.Errorf("agent failed to reload Nginx: %w", err)
 	}
 
+	if queued {
+		m.Logger.Warn("⏳ Nginx configuration queued until the Muscle returns", slog.String("domain", appConfig.DomainName))
+		return nil
+	}
 	m.Logger.Info("✅ Nginx configuration successfully applied", slog.String("domain", appConfig.DomainName))
 	return nil
 }
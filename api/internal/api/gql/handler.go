@@ -0,0 +1,43 @@
+// api/internal/api/gql/handler.go
+package gql
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"kari/api/internal/core/domain"
+)
+
+// 🛡️ SLA: One request may fan out into many repository calls; bound how
+// deep a query nests and how many resolvers run at once.
+const (
+	maxDepth       = 6
+	maxParallelism = 8
+)
+
+// NewHandler serves POST /api/graphql. It expects RequireAuthentication in
+// front of it; every resolver checks the permission its data needs.
+func NewHandler(
+	apps domain.ApplicationRepository,
+	overview domain.AppOverviewRepository,
+	alerts domain.AuditRepository,
+	logger *slog.Logger,
+) (http.Handler, error) {
+	schema, err := graphql.ParseSchema(schemaSDL, &Resolver{
+		apps:     apps,
+		overview: overview,
+		alerts:   alerts,
+		logger:   logger,
+	},
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxParallelism(maxParallelism),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GraphQL schema: %w", err)
+	}
+	return &relay.Handler{Schema: schema}, nil
+}
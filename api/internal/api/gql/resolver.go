@@ -0,0 +1,321 @@
+// api/internal/api/gql/resolver.go
+package gql
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"kari/api/internal/api/middleware"
+	"kari/api/internal/core/domain"
+)
+
+// alertReader is satisfied by domain.AuditRepository.
+type alertReader interface {
+	GetFilteredAlerts(ctx context.Context, filter domain.AlertFilter) (domain.Page[domain.SystemAlert], error)
+}
+
+// ==============================================================================
+// 1. Root Resolver
+// ==============================================================================
+
+type Resolver struct {
+	apps     domain.ApplicationRepository
+	overview domain.AppOverviewRepository
+	alerts   alertReader
+	logger   *slog.Logger
+}
+
+// gqlError carries a stable code next to the message, under "extensions".
+type gqlError struct {
+	code    domain.ErrorCode
+	message string
+}
+
+func (e *gqlError) Error() string { return e.message }
+
+func (e *gqlError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+var errForbidden = &gqlError{code: domain.CodeForbidden, message: "Forbidden: insufficient scope"}
+
+// require returns the caller's claims if they hold the permission.
+func require(ctx context.Context, permission string) (*domain.UserClaims, error) {
+	claims, ok := ctx.Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		return nil, &gqlError{code: domain.CodeUnauthorized, message: "Unauthorized"}
+	}
+	if !middleware.HasPermission(claims.Permissions, permission) {
+		return nil, errForbidden
+	}
+	return claims, nil
+}
+
+// public keeps repository detail out of responses; the cause is logged.
+func (r *Resolver) public(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		return &gqlError{code: domain.CodeBadRequest, message: err.Error()}
+	case errors.Is(err, domain.ErrUnavailable):
+		return &gqlError{code: domain.CodeServiceUnavailable, message: "Temporarily unavailable"}
+	}
+	r.logger.Error("GraphQL resolver failed", slog.Any("error", err))
+	return &gqlError{code: domain.CodeInternal, message: "Internal error"}
+}
+
+func pageRequest(first *int32, after *string) (domain.PageRequest, error) {
+	page := domain.PageRequest{Desc: true}
+	if first != nil {
+		page.Limit = int(*first)
+	}
+	if after != nil && *after != "" {
+		cursor, err := domain.DecodeCursor(*after)
+		if err != nil {
+			return page, &gqlError{code: domain.CodeBadRequest, message: "Invalid cursor"}
+		}
+		page.Cursor = cursor
+	}
+	return page.Normalize(), nil
+}
+
+func nextCursor(token string) *string {
+	if token == "" {
+		return nil
+	}
+	return &token
+}
+
+// ==============================================================================
+// 2. Query
+// ==============================================================================
+
+func (r *Resolver) Applications(ctx context.Context, args struct {
+	First  *int32
+	After  *string
+	Status *string
+}) (*applicationConnection, error) {
+	claims, err := require(ctx, "applications:read")
+	if err != nil {
+		return nil, err
+	}
+	page, err := pageRequest(args.First, args.After)
+	if err != nil {
+		return nil, err
+	}
+	filter := domain.ApplicationFilter{OwnerID: claims.Subject}
+	if args.Status != nil {
+		filter.Status = *args.Status
+	}
+
+	result, err := r.apps.List(ctx, filter, page)
+	if err != nil {
+		return nil, r.public(err)
+	}
+	conn := &applicationConnection{total: result.Total, next: result.NextCursor}
+	for i := range result.Items {
+		conn.nodes = append(conn.nodes, &applicationResolver{root: r, app: &result.Items[i]})
+	}
+	return conn, nil
+}
+
+func (r *Resolver) Application(ctx context.Context, args struct{ ID graphql.ID }) (*applicationResolver, error) {
+	claims, err := require(ctx, "applications:read")
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, &gqlError{code: domain.CodeBadRequest, message: "Invalid application ID"}
+	}
+
+	// 🛡️ Zero-Trust: Someone else's app is indistinguishable from a missing one
+	app, err := r.apps.GetByID(ctx, id, claims.Subject)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, r.public(err)
+	}
+	return &applicationResolver{root: r, app: app}, nil
+}
+
+func (r *Resolver) Alerts(ctx context.Context, args struct {
+	First      *int32
+	Severity   *string
+	Unresolved *bool
+}) (*alertConnection, error) {
+	if _, err := require(ctx, "server:manage"); err != nil {
+		return nil, err
+	}
+	page, err := pageRequest(args.First, nil)
+	if err != nil {
+		return nil, err
+	}
+	filter := domain.AlertFilter{Page: page}
+	if args.Severity != nil {
+		filter.Severity = *args.Severity
+	}
+	if args.Unresolved != nil && *args.Unresolved {
+		resolved := false
+		filter.IsResolved = &resolved
+	}
+
+	result, err := r.alerts.GetFilteredAlerts(ctx, filter)
+	if err != nil {
+		return nil, r.public(err)
+	}
+	conn := &alertConnection{total: result.Total, next: result.NextCursor}
+	for i := range result.Items {
+		conn.nodes = append(conn.nodes, &alertResolver{alert: &result.Items[i]})
+	}
+	return conn, nil
+}
+
+// ==============================================================================
+// 3. Object Resolvers
+// ==============================================================================
+
+type applicationConnection struct {
+	nodes []*applicationResolver
+	total int
+	next  string
+}
+
+func (c *applicationConnection) Nodes() []*applicationResolver { return c.nodes }
+func (c *applicationConnection) TotalCount() int32             { return int32(c.total) }
+func (c *applicationConnection) NextCursor() *string           { return nextCursor(c.next) }
+
+// applicationResolver wraps an app the caller was already allowed to read,
+// so its nested fields need no further ownership check.
+type applicationResolver struct {
+	root *Resolver
+	app  *domain.Application
+}
+
+func (a *applicationResolver) ID() graphql.ID         { return graphql.ID(a.app.ID.String()) }
+func (a *applicationResolver) AppType() string        { return a.app.AppType }
+func (a *applicationResolver) RuntimeVersion() string { return a.app.RuntimeVersion }
+func (a *applicationResolver) Branch() string         { return a.app.Branch }
+func (a *applicationResolver) Status() string         { return a.app.Status }
+func (a *applicationResolver) Instances() int32       { return int32(a.app.Instances) }
+func (a *applicationResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: a.app.CreatedAt}
+}
+func (a *applicationResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: a.app.UpdatedAt}
+}
+
+func (a *applicationResolver) Domain() *domainResolver {
+	return &domainResolver{root: a.root, id: a.app.DomainID, name: a.app.DomainName}
+}
+
+func (a *applicationResolver) LastDeployment(ctx context.Context) (*deploymentResolver, error) {
+	d, err := a.root.overview.LastDeployment(ctx, a.app.ID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, a.root.public(err)
+	}
+	return &deploymentResolver{d: d}, nil
+}
+
+func (a *applicationResolver) Alerts(ctx context.Context, args struct {
+	First      *int32
+	Unresolved *bool
+}) ([]*alertResolver, error) {
+	if _, err := require(ctx, "server:manage"); err != nil {
+		return nil, err
+	}
+	page, err := pageRequest(args.First, nil)
+	if err != nil {
+		return nil, err
+	}
+	filter := domain.AlertFilter{ResourceID: a.app.ID, Page: page}
+	if args.Unresolved != nil && *args.Unresolved {
+		resolved := false
+		filter.IsResolved = &resolved
+	}
+
+	result, err := a.root.alerts.GetFilteredAlerts(ctx, filter)
+	if err != nil {
+		return nil, a.root.public(err)
+	}
+	out := make([]*alertResolver, len(result.Items))
+	for i := range result.Items {
+		out[i] = &alertResolver{alert: &result.Items[i]}
+	}
+	return out, nil
+}
+
+type domainResolver struct {
+	root *Resolver
+	id   uuid.UUID
+	name string
+}
+
+func (d *domainResolver) ID() graphql.ID { return graphql.ID(d.id.String()) }
+func (d *domainResolver) Name() string   { return d.name }
+
+func (d *domainResolver) Certificate(ctx context.Context) (*certificateResolver, error) {
+	c, err := d.root.overview.Certificate(ctx, d.id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, d.root.public(err)
+	}
+	return &certificateResolver{c: c}, nil
+}
+
+type certificateResolver struct{ c *domain.CertificateSummary }
+
+func (c *certificateResolver) Issuer() string { return c.c.Issuer }
+func (c *certificateResolver) Status() string { return c.c.Status }
+func (c *certificateResolver) ExpiresAt() graphql.Time {
+	return graphql.Time{Time: c.c.ExpiresAt}
+}
+func (c *certificateResolver) LastError() *string {
+	if c.c.LastError == "" {
+		return nil
+	}
+	return &c.c.LastError
+}
+
+type deploymentResolver struct{ d *domain.DeploymentSummary }
+
+func (d *deploymentResolver) ID() graphql.ID { return graphql.ID(d.d.ID.String()) }
+func (d *deploymentResolver) Status() string { return d.d.Status }
+func (d *deploymentResolver) Branch() string { return d.d.Branch }
+func (d *deploymentResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: d.d.CreatedAt}
+}
+func (d *deploymentResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: d.d.UpdatedAt}
+}
+
+type alertConnection struct {
+	nodes []*alertResolver
+	total int
+	next  string
+}
+
+func (c *alertConnection) Nodes() []*alertResolver { return c.nodes }
+func (c *alertConnection) TotalCount() int32       { return int32(c.total) }
+func (c *alertConnection) NextCursor() *string     { return nextCursor(c.next) }
+
+type alertResolver struct{ alert *domain.SystemAlert }
+
+func (a *alertResolver) ID() graphql.ID      { return graphql.ID(a.alert.ID.String()) }
+func (a *alertResolver) Severity() string    { return a.alert.Severity }
+func (a *alertResolver) Category() string    { return a.alert.Category }
+func (a *alertResolver) ResourceID() *string { return a.alert.ResourceID }
+func (a *alertResolver) Message() string     { return a.alert.Message }
+func (a *alertResolver) IsResolved() bool    { return a.alert.IsResolved }
+func (a *alertResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: a.alert.CreatedAt}
+}
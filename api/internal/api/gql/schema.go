@@ -0,0 +1,79 @@
+// api/internal/api/gql/schema.go
+package gql
+
+// schemaSDL is what the dashboard queries in one round trip instead of a
+// request per card. Fields behind a permission the caller lacks resolve to
+// an error on that field only; the rest of the query still answers.
+const schemaSDL = `
+schema {
+	query: Query
+}
+
+scalar Time
+
+type Query {
+	# applications:read. Only the caller's own applications.
+	applications(first: Int = 20, after: String, status: String): ApplicationConnection!
+	application(id: ID!): Application
+	# server:manage. The Action Center feed.
+	alerts(first: Int = 20, severity: String, unresolved: Boolean): AlertConnection!
+}
+
+type ApplicationConnection {
+	nodes: [Application!]!
+	totalCount: Int!
+	nextCursor: String
+}
+
+type Application {
+	id: ID!
+	domain: Domain!
+	appType: String!
+	runtimeVersion: String!
+	branch: String!
+	status: String!
+	instances: Int!
+	createdAt: Time!
+	updatedAt: Time!
+	lastDeployment: Deployment
+	# server:manage
+	alerts(first: Int = 5, unresolved: Boolean = true): [Alert!]!
+}
+
+type Domain {
+	id: ID!
+	name: String!
+	certificate: Certificate
+}
+
+type Certificate {
+	issuer: String!
+	status: String!
+	expiresAt: Time!
+	lastError: String
+}
+
+type Deployment {
+	id: ID!
+	status: String!
+	branch: String!
+	createdAt: Time!
+	updatedAt: Time!
+}
+
+type AlertConnection {
+	nodes: [Alert!]!
+	totalCount: Int!
+	nextCursor: String
+}
+
+type Alert {
+	id: ID!
+	severity: String!
+	category: String!
+	resourceId: String
+	message: String!
+	isResolved: Boolean!
+	createdAt: Time!
+}
+`
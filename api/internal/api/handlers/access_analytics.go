@@ -0,0 +1,43 @@
+// api/internal/api/handlers/access_analytics.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type AccessAnalyticsHandler struct {
+	Service domain.AccessAnalyticsManager
+}
+
+func NewAccessAnalyticsHandler(service domain.AccessAnalyticsManager) *AccessAnalyticsHandler {
+	return &AccessAnalyticsHandler{Service: service}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// Get handles GET /api/v1/domains/{id}/analytics?range=7d
+// Client addresses are anonymized by the Muscle before they are stored.
+func (h *AccessAnalyticsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userClaims, domainID, ok := parseOwnedID(w, r, "Invalid domain ID format")
+	if !ok {
+		return
+	}
+
+	analytics, err := h.Service.GetAnalytics(r.Context(), domainID, userClaims.Subject, r.URL.Query().Get("range"))
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(analytics)
+}
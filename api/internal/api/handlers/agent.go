@@ -0,0 +1,41 @@
+// api/internal/api/handlers/agent.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// agentCompatReader is satisfied by services.AgentCompatService.
+type agentCompatReader interface {
+	Current() *domain.AgentCompatibility
+}
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type AgentHandler struct {
+	Link   domain.AgentLinkMonitor
+	Compat agentCompatReader
+}
+
+func NewAgentHandler(link domain.AgentLinkMonitor, compat agentCompatReader) *AgentHandler {
+	return &AgentHandler{Link: link, Compat: compat}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// HandleGetStatus handles GET /api/v1/admin/agent
+// Reports the Muscle link (breaker + connectivity) and the negotiated protocol.
+func (h *AgentHandler) HandleGetStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"link":          h.Link.Stats(),
+		"compatibility": h.Compat.Current(),
+	})
+}
@@ -0,0 +1,270 @@
+// api/internal/api/handlers/agent_rpc.go
+package handlers
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+// agentProcedure is one allowlisted SystemAgent RPC.
+type agentProcedure struct {
+	newRequest func() proto.Message
+	invoke     func(ctx context.Context, c agent.SystemAgentClient, req proto.Message) (proto.Message, error)
+}
+
+// agentProcedures is all the browser may reach. The Muscle runs as root,
+// so anything that writes to the host stays behind a REST endpoint that
+// validates ownership and audits the change. All of them are unary: the
+// only streaming RPC, StreamDeployment, deploys, and build output already
+// streams through the deployment log WebSocket.
+var agentProcedures = map[string]agentProcedure{
+	"GetSystemStatus": {
+		newRequest: func() proto.Message { return &agent.Empty{} },
+		invoke: func(ctx context.Context, c agent.SystemAgentClient, req proto.Message) (proto.Message, error) {
+			return c.GetSystemStatus(ctx, req.(*agent.Empty))
+		},
+	},
+	"GetCapabilities": {
+		newRequest: func() proto.Message { return &agent.Empty{} },
+		invoke: func(ctx context.Context, c agent.SystemAgentClient, req proto.Message) (proto.Message, error) {
+			return c.GetCapabilities(ctx, req.(*agent.Empty))
+		},
+	},
+	"GetProcessStatus": {
+		newRequest: func() proto.Message { return &agent.ProcessStatusRequest{} },
+		invoke: func(ctx context.Context, c agent.SystemAgentClient, req proto.Message) (proto.Message, error) {
+			return c.GetProcessStatus(ctx, req.(*agent.ProcessStatusRequest))
+		},
+	},
+	"ListManagedResources": {
+		newRequest: func() proto.Message { return &agent.Empty{} },
+		invoke: func(ctx context.Context, c agent.SystemAgentClient, req proto.Message) (proto.Message, error) {
+			return c.ListManagedResources(ctx, req.(*agent.Empty))
+		},
+	},
+	"ScanAppFiles": {
+		newRequest: func() proto.Message { return &agent.FileScanRequest{} },
+		invoke: func(ctx context.Context, c agent.SystemAgentClient, req proto.Message) (proto.Message, error) {
+			scan := req.(*agent.FileScanRequest)
+			if scan.Rebaseline {
+				return nil, status.Error(codes.InvalidArgument, "accept a new baseline through POST /applications/{id}/scans/baseline")
+			}
+			return c.ScanAppFiles(ctx, scan)
+		},
+	},
+}
+
+// rpcProtocol is the wire format a call arrived in.
+type rpcProtocol struct {
+	grpcWeb bool // Length-prefixed frames with trailers in the body; else Connect unary
+	json    bool // protojson instead of binary protobuf
+}
+
+var rpcContentTypes = map[string]rpcProtocol{
+	"application/json":           {json: true},
+	"application/proto":          {},
+	"application/grpc-web":       {grpcWeb: true},
+	"application/grpc-web+proto": {grpcWeb: true},
+	"application/grpc-web+json":  {grpcWeb: true, json: true},
+}
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+// AgentRPCHandler is a gRPC-Web and Connect gateway to selected SystemAgent
+// RPCs, so the frontend can use generated clients instead of a REST wrapper
+// per RPC. Calls go through the same agent link (deadlines, breaker) as the
+// Brain's own. Unary only; Connect streaming content types get a 415.
+type AgentRPCHandler struct {
+	client agent.SystemAgentClient
+}
+
+func NewAgentRPCHandler(client agent.SystemAgentClient) *AgentRPCHandler {
+	return &AgentRPCHandler{client: client}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// Call handles POST /api/v1/rpc/kari.agent.v1.SystemAgent/{method}
+func (h *AgentRPCHandler) Call(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	protocol, ok := rpcContentTypes[mediaType]
+	if !ok {
+		w.Header().Set("Accept-Post", "application/json, application/proto, application/grpc-web+proto, application/grpc-web+json")
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	proc, ok := agentProcedures[chi.URLParam(r, "method")]
+	if !ok {
+		writeRPCError(w, protocol, mediaType, status.Error(codes.Unimplemented, "procedure is not exposed"))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeRPCError(w, protocol, mediaType, status.Error(codes.ResourceExhausted, "request body is too large"))
+		return
+	}
+	if protocol.grpcWeb {
+		if body, err = unframeGRPCWeb(body); err != nil {
+			writeRPCError(w, protocol, mediaType, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+	}
+
+	req := proc.newRequest()
+	if protocol.json {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, req)
+	} else {
+		err = proto.Unmarshal(body, req)
+	}
+	if err != nil {
+		writeRPCError(w, protocol, mediaType, status.Error(codes.InvalidArgument, "malformed request message"))
+		return
+	}
+
+	resp, err := proc.invoke(r.Context(), h.client, req)
+	if err != nil {
+		writeRPCError(w, protocol, mediaType, err)
+		return
+	}
+
+	var out []byte
+	if protocol.json {
+		out, err = protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(resp)
+	} else {
+		out, err = proto.Marshal(resp)
+	}
+	if err != nil {
+		writeRPCError(w, protocol, mediaType, status.Error(codes.Internal, "failed to encode response"))
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	if !protocol.grpcWeb {
+		w.Write(out)
+		return
+	}
+	w.Write(frameGRPCWeb(0x00, out))
+	w.Write(frameGRPCWeb(0x80, []byte("grpc-status: 0\r\n")))
+}
+
+// ==============================================================================
+// 3. Wire Helpers
+// ==============================================================================
+
+// unframeGRPCWeb extracts the single message of a unary gRPC-Web request.
+func unframeGRPCWeb(body []byte) ([]byte, error) {
+	if len(body) < 5 {
+		return nil, errors.New("truncated gRPC-Web frame")
+	}
+	if body[0] != 0x00 {
+		return nil, errors.New("compressed gRPC-Web frames are not supported")
+	}
+	size := binary.BigEndian.Uint32(body[1:5])
+	if uint64(size) != uint64(len(body)-5) {
+		return nil, errors.New("gRPC-Web frame length does not match the body")
+	}
+	return body[5:], nil
+}
+
+func frameGRPCWeb(flag byte, payload []byte) []byte {
+	frame := make([]byte, 5, 5+len(payload))
+	frame[0] = flag
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(payload)))
+	return append(frame, payload...)
+}
+
+// writeRPCError reports a failure the way each protocol expects: gRPC-Web
+// as a trailers-only response, Connect as an HTTP status with a JSON body.
+// Internal Muscle detail never reaches the browser.
+func writeRPCError(w http.ResponseWriter, protocol rpcProtocol, mediaType string, err error) {
+	st := status.Convert(err)
+	msg := st.Message()
+	switch st.Code() {
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		msg = "the agent failed to complete the call"
+	}
+
+	if protocol.grpcWeb {
+		w.Header().Set("Content-Type", mediaType)
+		w.Header().Set("Grpc-Status", fmt.Sprint(uint32(st.Code())))
+		w.Header().Set("Grpc-Message", percentEncode(msg))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	code, httpStatus := connectCode(st.Code())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	json.NewEncoder(w).Encode(map[string]string{"code": code, "message": msg})
+}
+
+// percentEncode escapes a grpc-message value as the gRPC spec requires.
+func percentEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c > 0x7e || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// connectCode maps a gRPC code to the Connect protocol's name and HTTP status.
+func connectCode(c codes.Code) (string, int) {
+	switch c {
+	case codes.Canceled:
+		return "canceled", 499
+	case codes.InvalidArgument:
+		return "invalid_argument", http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return "deadline_exceeded", http.StatusGatewayTimeout
+	case codes.NotFound:
+		return "not_found", http.StatusNotFound
+	case codes.AlreadyExists:
+		return "already_exists", http.StatusConflict
+	case codes.PermissionDenied:
+		return "permission_denied", http.StatusForbidden
+	case codes.ResourceExhausted:
+		return "resource_exhausted", http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return "failed_precondition", http.StatusBadRequest
+	case codes.Aborted:
+		return "aborted", http.StatusConflict
+	case codes.OutOfRange:
+		return "out_of_range", http.StatusBadRequest
+	case codes.Unimplemented:
+		return "unimplemented", http.StatusNotImplemented
+	case codes.Unavailable:
+		return "unavailable", http.StatusServiceUnavailable
+	case codes.Unauthenticated:
+		return "unauthenticated", http.StatusUnauthorized
+	case codes.Internal:
+		return "internal", http.StatusInternalServerError
+	case codes.DataLoss:
+		return "data_loss", http.StatusInternalServerError
+	}
+	return "unknown", http.StatusInternalServerError
+}
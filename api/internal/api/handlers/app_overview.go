@@ -0,0 +1,45 @@
+// api/internal/api/handlers/app_overview.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type AppOverviewHandler struct {
+	Service domain.AppOverviewReader
+}
+
+func NewAppOverviewHandler(service domain.AppOverviewReader) *AppOverviewHandler {
+	return &AppOverviewHandler{Service: service}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// Get handles GET /api/v1/applications/{id}/overview
+// Always 200 once the app is found; sections the agent or database could not
+// deliver in time are null and named in "unavailable".
+func (h *AppOverviewHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	overview, err := h.Service.GetOverview(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(overview)
+}
@@ -6,36 +6,79 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 
 	"kari/api/internal/core/domain"
 	"kari/api/internal/core/utils"
 )
 
-// Use a single instance of Validate, it caches struct info
-var validate = validator.New()
-
 // ==============================================================================
 // 1. Request Payloads (Input Validation)
 // ==============================================================================
 
 type CreateAppRequest struct {
-	DomainID     uuid.UUID         `json:"domain_id" validate:"required"`
-	AppType      string            `json:"app_type" validate:"required,oneof=nodejs python php ruby static"`
-	RepoURL      string            `json:"repo_url" validate:"required,url"`
-	Branch       string            `json:"branch" validate:"required,max=100"`
-	BuildCommand string            `json:"build_command" validate:"required,max=255"`
-	StartCommand string            `json:"start_command" validate:"required,max=255"`
-	EnvVars      map[string]string `json:"env_vars" validate:"dive,keys,max=100,endkeys,max=5000"`
+	DomainID       uuid.UUID         `json:"domain_id" validate:"required"`
+	AppType        string            `json:"app_type" validate:"required,oneof=nodejs python go php ruby static image"`
+	RuntimeVersion string            `json:"runtime_version" validate:"omitempty,max=20"` // Empty follows the stack registry
+	ImageRef       string            `json:"image_ref" validate:"required_if=AppType image,max=512"`
+	RepoURL        string            `json:"repo_url" validate:"required_unless=AppType image,omitempty,url"`
+	Branch         string            `json:"branch" validate:"required_unless=AppType image,max=100"`
+	BuildCommand   string            `json:"build_command" validate:"required_unless=AppType image,max=255"`
+	StartCommand   string            `json:"start_command" validate:"required_unless=AppType image,max=255"`
+	EnvVars        map[string]string `json:"env_vars" validate:"max=50,dive,keys,envkey,endkeys,max=8192"`
+	// Optional: repo picked from a connected account; installs deploy key + webhook
+	GitProvider string `json:"git_provider" validate:"omitempty,oneof=github gitlab"`
+	GitRepo     string `json:"git_repo" validate:"required_with=GitProvider,max=255"`
+	// Optional: web + worker + scheduler instead of a single start_command
+	Processes map[string]domain.ProcessSpec `json:"processes" validate:"max=10"`
+	// Optional: web copies behind a load-balanced vhost (0 = 1)
+	Instances int `json:"instances" validate:"min=0,max=16"`
+}
+
+type LinkRepoRequest struct {
+	GitProvider string `json:"git_provider" validate:"required,oneof=github gitlab"`
+	GitRepo     string `json:"git_repo" validate:"required,max=255"`
 }
 
 type UpdateEnvRequest struct {
-	EnvVars map[string]string `json:"env_vars" validate:"required,dive,keys,max=100,endkeys,max=5000"`
+	EnvVars map[string]string `json:"env_vars" validate:"required,max=50,dive,keys,envkey,endkeys,max=8192"`
+}
+
+// Build-only variables (build args); running units never see them.
+type UpdateBuildEnvRequest struct {
+	BuildEnvVars map[string]string `json:"build_env_vars" validate:"required,max=50,dive,keys,envkey,endkeys,max=8192"`
+}
+
+// DeployOnceRequest overrides the app's config for one deployment only.
+type DeployOnceRequest struct {
+	Branch   string            `json:"branch" validate:"omitempty,max=100"`
+	BuildEnv map[string]string `json:"build_env" validate:"max=50,dive,keys,envkey,endkeys,max=8192"`
+}
+
+type UpdateRuntimeRequest struct {
+	// Empty unpins the app back to the stack registry default
+	RuntimeVersion string `json:"runtime_version" validate:"omitempty,max=20"`
+}
+
+// Bounds are enforced by domain.ValidateProcesses; the DTO only caps size.
+type UpdateProcessesRequest struct {
+	// Empty map returns the app to its single start_command process
+	Processes map[string]domain.ProcessSpec `json:"processes" validate:"max=10"`
+}
+
+type ScaleRequest struct {
+	Instances int `json:"instances" validate:"required,min=1,max=16"`
+}
+
+type UpdateSettingsRequest struct {
+	// 0 resets to the platform default
+	PHPMaxChildren int `json:"php_max_children" validate:"min=0,max=200"`
 }
 
 // ==============================================================================
@@ -44,11 +87,14 @@ type UpdateEnvRequest struct {
 
 type AppHandler struct {
 	Service domain.AppService
+	Git     domain.GitIntegration
+	Health  domain.AppHealthSource // Optional: enables ?include=status on List
 }
 
-func NewAppHandler(service domain.AppService) *AppHandler {
+func NewAppHandler(service domain.AppService, git domain.GitIntegration) *AppHandler {
 	return &AppHandler{
 		Service: service,
+		Git:     git,
 	}
 }
 
@@ -60,29 +106,27 @@ func NewAppHandler(service domain.AppService) *AppHandler {
 func (h *AppHandler) Create(w http.ResponseWriter, r *http.Request) {
 	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
 	if !ok {
-		http.Error(w, `{"message": "Unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	var req CreateAppRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"message": "Invalid JSON payload"}`, http.StatusBadRequest)
-		return
-	}
-
-	if err := validate.Struct(req); err != nil {
-		HandleError(w, r, err)
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	app := &domain.Application{
-		DomainID:     req.DomainID,
-		AppType:      req.AppType,
-		RepoURL:      req.RepoURL,
-		Branch:       req.Branch,
-		BuildCommand: req.BuildCommand,
-		StartCommand: req.StartCommand,
-		EnvVars:      req.EnvVars,
+		DomainID:       req.DomainID,
+		AppType:        req.AppType,
+		RuntimeVersion: req.RuntimeVersion,
+		ImageRef:       req.ImageRef,
+		RepoURL:        req.RepoURL,
+		Branch:         req.Branch,
+		BuildCommand:   req.BuildCommand,
+		StartCommand:   req.StartCommand,
+		EnvVars:        req.EnvVars,
+		Processes:      req.Processes,
+		Instances:      req.Instances,
 	}
 
 	createdApp, err := h.Service.CreateApplication(r.Context(), userClaims.Subject, app)
@@ -91,41 +135,102 @@ func (h *AppHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The app exists either way, so a failed link is a warning on the 201,
+	// not an error; the client retries it via /git-link
+	resp := createAppResponse{Application: createdApp}
+	if req.GitProvider != "" {
+		err := h.Git.LinkApplication(r.Context(), userClaims.Subject, createdApp, domain.GitProvider(req.GitProvider), req.GitRepo)
+		if err != nil {
+			resp.Warning = "Application created, but linking " + req.GitRepo + " failed: " + err.Error() + ". Retry from the app's Git settings."
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(createdApp)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// createAppResponse is the created app, plus a warning when the requested
+// repository link could not be installed.
+type createAppResponse struct {
+	*domain.Application
+	Warning string `json:"warning,omitempty"`
 }
 
-// List handles GET /api/v1/applications
+// appWithHealth is a List item when ?include=status is requested.
+type appWithHealth struct {
+	domain.Application
+	Health *domain.AppHealth `json:"health"` // null until the monitor has probed the app
+}
+
+// List handles GET /api/v1/applications?status=&domain_id=&include=status&sort=&limit=&cursor=
+// include=status adds the availability monitor's last probe to each app from
+// memory; it never triggers a check of its own.
 func (h *AppHandler) List(w http.ResponseWriter, r *http.Request) {
 	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
 	if !ok {
-		http.Error(w, `{"message": "Unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
-	apps, err := h.Service.ListApplications(r.Context(), userClaims.Subject)
+	page, ok := parsePageRequest(r)
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid limit or cursor")
+		return
+	}
+	domainID, ok := parseUUIDParam(r, "domain_id")
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid domain_id filter")
+		return
+	}
+	filter := domain.ApplicationFilter{
+		Status:   r.URL.Query().Get("status"),
+		DomainID: domainID,
+	}
+	withHealth := false
+	if include := r.URL.Query().Get("include"); include != "" {
+		if include != "status" {
+			writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid include; supported: status")
+			return
+		}
+		withHealth = true
+	}
+
+	result, err := h.Service.ListApplications(r.Context(), userClaims.Subject, filter, page)
 	if err != nil {
 		HandleError(w, r, err)
 		return
 	}
 
+	writePageHeaders(w, r, result.Total, result.NextCursor)
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(apps)
+	if !withHealth {
+		json.NewEncoder(w).Encode(result.Items)
+		return
+	}
+
+	items := make([]appWithHealth, len(result.Items))
+	for i, app := range result.Items {
+		items[i].Application = app
+		if h.Health != nil {
+			items[i].Health = h.Health.LastHealth(app.ID)
+		}
+	}
+	json.NewEncoder(w).Encode(items)
 }
 
 // GetByID handles GET /api/v1/applications/{id}
 func (h *AppHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
 	if !ok {
-		http.Error(w, `{"message": "Unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	appIDStr := chi.URLParam(r, "id")
 	appID, err := uuid.Parse(appIDStr)
 	if err != nil {
-		http.Error(w, `{"message": "Invalid application ID format"}`, http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid application ID format")
 		return
 	}
 
@@ -143,29 +248,163 @@ func (h *AppHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 func (h *AppHandler) UpdateEnv(w http.ResponseWriter, r *http.Request) {
 	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
 	if !ok {
-		http.Error(w, `{"message": "Unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	appIDStr := chi.URLParam(r, "id")
 	appID, err := uuid.Parse(appIDStr)
 	if err != nil {
-		http.Error(w, `{"message": "Invalid application ID format"}`, http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid application ID format")
 		return
 	}
 
 	var req UpdateEnvRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"message": "Invalid JSON payload"}`, http.StatusBadRequest)
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	if err := validate.Struct(req); err != nil {
+	updatedApp, err := h.Service.UpdateEnvironmentVariables(r.Context(), appID, userClaims.Subject, req.EnvVars)
+	if err != nil {
 		HandleError(w, r, err)
 		return
 	}
 
-	updatedApp, err := h.Service.UpdateEnvironmentVariables(r.Context(), appID, userClaims.Subject, req.EnvVars)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedApp)
+}
+
+// UpdateBuildEnv handles PUT /api/v1/applications/{id}/build-env
+func (h *AppHandler) UpdateBuildEnv(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	var req UpdateBuildEnvRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	updatedApp, err := h.Service.UpdateBuildEnvVars(r.Context(), appID, userClaims.Subject, req.BuildEnvVars)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedApp)
+}
+
+// UpdateSettings handles PUT /api/v1/applications/{id}/settings
+func (h *AppHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid application ID format")
+		return
+	}
+
+	var req UpdateSettingsRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	updatedApp, err := h.Service.UpdateApplicationSettings(r.Context(), appID, userClaims.Subject, domain.AppSettings{
+		PHPMaxChildren: req.PHPMaxChildren,
+	})
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedApp)
+}
+
+// UpdateRuntime handles PUT /api/v1/applications/{id}/runtime
+func (h *AppHandler) UpdateRuntime(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid application ID format")
+		return
+	}
+
+	var req UpdateRuntimeRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	updatedApp, err := h.Service.UpdateRuntimeVersion(r.Context(), appID, userClaims.Subject, req.RuntimeVersion)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedApp)
+}
+
+// UpdateProcesses handles PUT /api/v1/applications/{id}/processes
+func (h *AppHandler) UpdateProcesses(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid application ID format")
+		return
+	}
+
+	var req UpdateProcessesRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	updatedApp, err := h.Service.UpdateProcesses(r.Context(), appID, userClaims.Subject, req.Processes)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedApp)
+}
+
+// Scale handles PUT /api/v1/applications/{id}/instances
+func (h *AppHandler) Scale(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid application ID format")
+		return
+	}
+
+	var req ScaleRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	updatedApp, err := h.Service.ScaleApplication(r.Context(), appID, userClaims.Subject, req.Instances)
 	if err != nil {
 		HandleError(w, r, err)
 		return
@@ -175,18 +414,113 @@ func (h *AppHandler) UpdateEnv(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(updatedApp)
 }
 
+// Restart handles POST /api/v1/applications/{id}/restart?mode=graceful|hard
+// Hard (the default) restarts units in place; graceful drains live connections first.
+func (h *AppHandler) Restart(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	var graceful bool
+	switch r.URL.Query().Get("mode") {
+	case "", "hard":
+	case "graceful":
+		graceful = true
+	default:
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "mode must be 'graceful' or 'hard'")
+		return
+	}
+
+	if err := h.Service.RestartApplication(r.Context(), appID, userClaims.Subject, graceful); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetProcesses handles GET /api/v1/applications/{id}/processes
+// Returns each process type with its live systemd state; a scaled web process
+// reports one row per instance with its port and health.
+func (h *AppHandler) GetProcesses(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid application ID format")
+		return
+	}
+
+	statuses, err := h.Service.GetProcessStatus(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statuses)
+}
+
+// ListRuntimes handles GET /api/v1/applications/runtimes
+func (h *AppHandler) ListRuntimes(w http.ResponseWriter, r *http.Request) {
+	runtimes, err := h.Service.ListRuntimes(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runtimes)
+}
+
+// LinkRepo handles POST /api/v1/applications/{id}/git-link
+func (h *AppHandler) LinkRepo(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid application ID format")
+		return
+	}
+
+	var req LinkRepoRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	// 🛡️ Zero-Trust: Ownership check before touching the provider
+	app, err := h.Service.GetApplication(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	if err := h.Git.LinkApplication(r.Context(), userClaims.Subject, app, domain.GitProvider(req.GitProvider), req.GitRepo); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // TriggerDeploy handles POST /api/v1/applications/{id}/deploy
 func (h *AppHandler) TriggerDeploy(w http.ResponseWriter, r *http.Request) {
 	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
 	if !ok {
-		http.Error(w, `{"message": "Unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	appIDStr := chi.URLParam(r, "id")
 	appID, err := uuid.Parse(appIDStr)
 	if err != nil {
-		http.Error(w, `{"message": "Invalid application ID format"}`, http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid application ID format")
 		return
 	}
 
@@ -201,27 +535,73 @@ func (h *AppHandler) TriggerDeploy(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(deployment)
 }
 
+// DeployOnce handles POST /api/v1/applications/{id}/deploy/once
+// Builds right away with a one-off branch and/or build env, streaming the
+// build log as SSE "log" events. The overrides are never stored, so unlike
+// TriggerDeploy nothing is queued and the stream is the only record.
+func (h *AppHandler) DeployOnce(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	var req DeployOnceRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	// A dropped connection must not abort a half-finished release
+	logs, err := h.Service.DeployWithOverrides(context.WithoutCancel(r.Context()), appID, userClaims.Subject, domain.DeployOverrides{
+		Branch:   req.Branch,
+		BuildEnv: req.BuildEnv,
+	})
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	rc := http.NewResponseController(w)
+	// Builds routinely outlast the server's write timeout
+	rc.SetWriteDeadline(time.Time{})
+
+	for line := range logs {
+		fmt.Fprint(w, "event: log\n")
+		writeSSEData(w, line)
+		if err := rc.Flush(); err != nil {
+			// Client gone; let the build finish unobserved
+			go func() {
+				for range logs {
+				}
+			}()
+			return
+		}
+	}
+}
+
 // HandleGitHubWebhook handles POST /api/v1/webhooks/github/{id}
 func (h *AppHandler) HandleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
 	// 1. Parse the Application ID from the URL
 	appIDStr := chi.URLParam(r, "id")
 	appID, err := uuid.Parse(appIDStr)
 	if err != nil {
-		http.Error(w, `{"message": "Invalid application ID"}`, http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid application ID")
 		return
 	}
 
 	// 2. Fetch the Application (and its decrypted webhook secret)
 	app, err := h.Service.GetApplicationSystem(r.Context(), appID)
 	if err != nil {
-		http.Error(w, `{"message": "Not found"}`, http.StatusNotFound)
+		writeError(w, r, http.StatusNotFound, domain.CodeNotFound, "Not found")
 		return
 	}
 
 	// 3. Read the RAW bytes for cryptographic HMAC validation (Safe due to MaxBytes middleware)
 	rawBody, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, `{"message": "Failed to read body"}`, http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, domain.CodeInternal, "Failed to read body")
 		return
 	}
 
@@ -232,8 +612,8 @@ func (h *AppHandler) HandleGitHubWebhook(w http.ResponseWriter, r *http.Request)
 	signature := r.Header.Get("X-Hub-Signature-256")
 	if err := utils.VerifyGitHubSignature(rawBody, signature, app.WebhookSecret); err != nil {
 		// Log the attack attempt, but return a generic 401
-		// h.Service.Logger.Warn("Forged Webhook", ...) 
-		http.Error(w, `{"message": "Unauthorized: Invalid signature"}`, http.StatusUnauthorized)
+		// h.Service.Logger.Warn("Forged Webhook", ...)
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized: Invalid signature")
 		return
 	}
 
@@ -251,7 +631,7 @@ func (h *AppHandler) HandleGitHubWebhook(w http.ResponseWriter, r *http.Request)
 	// 6. Safely decode the JSON payload
 	var payload map[string]interface{}
 	if err := json.Unmarshal(rawBody, &payload); err != nil {
-		http.Error(w, `{"message": "Invalid JSON payload"}`, http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, domain.CodeInvalidJSON, "Invalid JSON payload")
 		return
 	}
 
@@ -273,3 +653,49 @@ func (h *AppHandler) HandleGitHubWebhook(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusAccepted)
 	w.Write([]byte(`{"message": "Deployment triggered successfully"}`))
 }
+
+// HandleGitLabWebhook handles POST /api/v1/webhooks/gitlab/{id}
+func (h *AppHandler) HandleGitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid application ID")
+		return
+	}
+
+	app, err := h.Service.GetApplicationSystem(r.Context(), appID)
+	if err != nil {
+		writeError(w, r, http.StatusNotFound, domain.CodeNotFound, "Not found")
+		return
+	}
+
+	// GitLab echoes the hook's secret token instead of signing the body
+	if err := utils.VerifyGitLabToken(r.Header.Get("X-Gitlab-Token"), app.WebhookSecret); err != nil {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized: Invalid token")
+		return
+	}
+
+	if r.Header.Get("X-Gitlab-Event") != "Push Hook" {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var payload struct {
+		Ref string `json:"ref"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeInvalidJSON, "Invalid JSON payload")
+		return
+	}
+	if payload.Ref != "refs/heads/"+app.Branch {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"message": "Ignored: push to untracked branch"}`))
+		return
+	}
+
+	go func() {
+		_ = h.Service.TriggerSystemDeployment(context.Background(), appID)
+	}()
+
+	w.WriteHeader(http.StatusAccepted)
+	w.Write([]byte(`{"message": "Deployment triggered successfully"}`))
+}
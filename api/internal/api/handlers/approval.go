@@ -0,0 +1,133 @@
+// api/internal/api/handlers/approval.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type ApprovalHandler struct {
+	Service domain.ApprovalManager
+}
+
+func NewApprovalHandler(service domain.ApprovalManager) *ApprovalHandler {
+	return &ApprovalHandler{Service: service}
+}
+
+// Both channels are optional; a gate with neither is decided via the API only.
+type setApprovalGateRequest struct {
+	SlackChannelID   *string `json:"slack_channel_id" validate:"omitempty,min=1,max=64"`
+	DiscordChannelID *string `json:"discord_channel_id" validate:"omitempty,numeric,max=64"`
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// GetGate handles GET /api/v1/applications/{id}/approval-gate
+func (h *ApprovalHandler) GetGate(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	gate, err := h.Service.GetGate(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(gate)
+}
+
+// SetGate handles PUT /api/v1/applications/{id}/approval-gate
+// From now on every deployment of the app waits for approval.
+func (h *ApprovalHandler) SetGate(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	var req setApprovalGateRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	gate, err := h.Service.SetGate(r.Context(), appID, userClaims.Subject, &domain.ApprovalGate{
+		SlackChannelID:   req.SlackChannelID,
+		DiscordChannelID: req.DiscordChannelID,
+	})
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(gate)
+}
+
+// DeleteGate handles DELETE /api/v1/applications/{id}/approval-gate
+func (h *ApprovalHandler) DeleteGate(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteGate(r.Context(), appID, userClaims.Subject); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List handles GET /api/v1/applications/{id}/approvals
+// Most recent first, decided and pending alike.
+func (h *ApprovalHandler) List(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	approvals, err := h.Service.ListApprovals(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(approvals)
+}
+
+// Approve handles POST /api/v1/deployment-approvals/{id}/approve
+func (h *ApprovalHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, true)
+}
+
+// Reject handles POST /api/v1/deployment-approvals/{id}/reject
+func (h *ApprovalHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, false)
+}
+
+// decide answers 409 when the approval was already settled, e.g. from chat.
+func (h *ApprovalHandler) decide(w http.ResponseWriter, r *http.Request, approve bool) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid approval ID format")
+	if !ok {
+		return
+	}
+
+	approval, err := h.Service.Decide(r.Context(), id, userClaims.Subject, approve, "api")
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(approval)
+}
@@ -0,0 +1,63 @@
+// api/internal/api/handlers/artifact.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type ArtifactHandler struct {
+	Service domain.Redeployer
+}
+
+func NewArtifactHandler(service domain.Redeployer) *ArtifactHandler {
+	return &ArtifactHandler{Service: service}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// Get handles GET /api/v1/deployments/{id}/artifact
+// The stored release a redeploy of this deployment would unpack.
+func (h *ArtifactHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid deployment ID format")
+	if !ok {
+		return
+	}
+
+	artifact, err := h.Service.GetArtifact(r.Context(), id.String(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(artifact)
+}
+
+// Redeploy handles POST /api/v1/deployments/{id}/redeploy
+// Queues a new deployment of the same build without rebuilding it. 409 once
+// the artifact has been pruned.
+func (h *ArtifactHandler) Redeploy(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid deployment ID format")
+	if !ok {
+		return
+	}
+
+	deployment, err := h.Service.Redeploy(r.Context(), id.String(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(deployment)
+}
@@ -0,0 +1,141 @@
+// api/internal/api/handlers/audit.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kari/api/internal/api/middleware"
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type AuditHandler struct {
+	Repo     domain.AuditRepository
+	Verifier domain.AuditVerifier
+}
+
+func NewAuditHandler(repo domain.AuditRepository, verifier domain.AuditVerifier) *AuditHandler {
+	return &AuditHandler{Repo: repo, Verifier: verifier}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// HandleGetTenantLogs handles GET /api/v1/audit?owner=&actor=&action=&resource_type=&trace_id=&sort=&limit=&cursor=
+func (h *AuditHandler) HandleGetTenantLogs(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	page, ok := parsePageRequest(r)
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid limit or cursor")
+		return
+	}
+	ownerID, ok := parseUUIDParam(r, "owner")
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid owner filter")
+		return
+	}
+	actorID, ok := parseUUIDParam(r, "actor")
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid actor filter")
+		return
+	}
+
+	// 🛡️ Tenant Isolation: Only platform admins may read other tenants' trails.
+	// Everyone else is pinned to their own tenant regardless of ?owner=.
+	if !middleware.HasPermission(userClaims.Permissions, "server:manage") {
+		ownerID = userClaims.Subject
+	}
+
+	q := r.URL.Query()
+	result, err := h.Repo.GetTenantLogs(r.Context(), domain.TenantLogFilter{
+		TenantID:     ownerID,
+		ActorID:      actorID,
+		Action:       q.Get("action"),
+		ResourceType: q.Get("resource_type"),
+		TraceID:      q.Get("trace_id"),
+		Page:         page,
+	})
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	writePageHeaders(w, r, result.Total, result.NextCursor)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"logs":        result.Items,
+		"total_count": result.Total,
+		"next_cursor": result.NextCursor,
+	})
+}
+
+// HandleGetAdminAlerts handles GET /api/v1/admin/alerts?severity=&category=&is_resolved=&trace_id=&resource_id=&sort=&limit=&cursor=
+func (h *AuditHandler) HandleGetAdminAlerts(w http.ResponseWriter, r *http.Request) {
+	page, ok := parsePageRequest(r)
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid limit or cursor")
+		return
+	}
+	resourceID, ok := parseUUIDParam(r, "resource_id")
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid resource_id filter")
+		return
+	}
+
+	q := r.URL.Query()
+	filter := domain.AlertFilter{
+		ResourceID: resourceID,
+		Severity:   q.Get("severity"),
+		Category:   q.Get("category"),
+		TraceID:    q.Get("trace_id"),
+		Page:       page,
+	}
+	if raw := q.Get("is_resolved"); raw != "" {
+		resolved, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid is_resolved filter")
+			return
+		}
+		filter.IsResolved = &resolved
+	}
+
+	result, err := h.Repo.GetFilteredAlerts(r.Context(), filter)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	// Body shape matches the Action Center loader (alerts + total_count)
+	writePageHeaders(w, r, result.Total, result.NextCursor)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"alerts":      result.Items,
+		"total_count": result.Total,
+		"next_cursor": result.NextCursor,
+	})
+}
+
+// HandleVerifyChain handles GET /api/v1/admin/audit/verify
+// A broken chain is still a 200: the report itself is the answer. Only an
+// inability to read the chain is surfaced as an error.
+func (h *AuditHandler) HandleVerifyChain(w http.ResponseWriter, r *http.Request) {
+	report, err := h.Verifier.VerifyTenantLogChain(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,96 @@
+// api/internal/api/handlers/audit_sink.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request Payloads (Input Validation)
+// ==============================================================================
+
+type UpdateAuditSinkRequest struct {
+	Enabled  bool   `json:"enabled"`
+	Kind     string `json:"kind" validate:"required,oneof=syslog splunk_hec https"`
+	Endpoint string `json:"endpoint" validate:"required,max=1024"`
+	Network  string `json:"network" validate:"omitempty,oneof=udp tcp tls"`
+	// Token is write-only. Omit it to keep the currently stored secret.
+	Token string `json:"token" validate:"omitempty,max=4096"`
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type AuditSinkHandler struct {
+	Service domain.AuditSinkManager
+}
+
+func NewAuditSinkHandler(service domain.AuditSinkManager) *AuditSinkHandler {
+	return &AuditSinkHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// Get handles GET /api/v1/admin/audit/sink
+func (h *AuditSinkHandler) Get(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.Service.GetConfig(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cfg)
+}
+
+// Update handles PUT /api/v1/admin/audit/sink
+func (h *AuditSinkHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req UpdateAuditSinkRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	cfg := &domain.AuditSinkConfig{
+		Enabled:  req.Enabled,
+		Kind:     domain.AuditSinkKind(req.Kind),
+		Endpoint: req.Endpoint,
+		Network:  req.Network,
+		Token:    req.Token,
+	}
+	if err := h.Service.UpdateConfig(r.Context(), cfg); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cfg)
+}
+
+// Test handles POST /api/v1/admin/audit/sink/test?tenant=<uuid>
+// Without ?tenant= the event is filed under platform scope, not the admin's.
+func (h *AuditSinkHandler) Test(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+	tenantID, ok := parseUUIDParam(r, "tenant")
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid tenant")
+		return
+	}
+
+	if err := h.Service.SendTestEvent(r.Context(), tenantID, userClaims.Subject); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"message": "Test event delivered."}`))
+}
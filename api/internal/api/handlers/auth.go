@@ -38,20 +38,14 @@ func NewAuthHandler(service domain.AuthService) *AuthHandler {
 
 // Login handles POST /api/v1/auth/login
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	// 1. Decode JSON payload
+	// 1. Decode JSON payload and validate format
+	// (e.g., prevent massive strings that could cause bcrypt to consume too much CPU)
 	var req LoginRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"message": "Invalid JSON payload"}`, http.StatusBadRequest)
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	// 2. Validate format (e.g., prevent massive strings that could cause bcrypt to consume too much CPU)
-	if err := validate.Struct(req); err != nil {
-		HandleError(w, r, err)
-		return
-	}
-
-	// 3. Delegate to the Core Service
+	// 2. Delegate to the Core Service
 	// The service handles fetching the user, verifying the bcrypt password hash, 
 	// and generating the cryptographic JWT strings.
 	tokenPair, user, err := h.Service.Login(r.Context(), req.Email, req.Password)
@@ -62,11 +56,11 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 4. Secure by Design: Set HttpOnly Cookies
+	// 3. Secure by Design: Set HttpOnly Cookies
 	// We DO NOT return the tokens in the JSON body. We attach them as strict cookies.
-	h.setAuthCookies(w, tokenPair)
+	setAuthCookies(w, tokenPair)
 
-	// 5. Return safe user data to the frontend (no passwords, no tokens)
+	// 4. Return safe user data to the frontend (no passwords, no tokens)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	
@@ -88,7 +82,7 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 	// 1. Extract the Refresh Token strictly from the cookie, ignoring the request body
 	refreshCookie, err := r.Cookie("kari_refresh_token")
 	if err != nil {
-		http.Error(w, `{"message": "Missing refresh token"}`, http.StatusUnauthorized)
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Missing refresh token")
 		return
 	}
 
@@ -97,12 +91,12 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		// If the refresh token is expired, revoked, or manipulated, we wipe the cookies.
 		h.clearAuthCookies(w)
-		http.Error(w, `{"message": "Session expired. Please log in again."}`, http.StatusUnauthorized)
+		writeError(w, r, http.StatusUnauthorized, domain.CodeSessionExpired, "Session expired. Please log in again.")
 		return
 	}
 
 	// 3. Set the newly minted cookies
-	h.setAuthCookies(w, tokenPair)
+	setAuthCookies(w, tokenPair)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -126,7 +120,8 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 // ==============================================================================
 
 // setAuthCookies abstracts the strict security flags required for session cookies in 2026.
-func (h *AuthHandler) setAuthCookies(w http.ResponseWriter, tokens *domain.TokenPair) {
+// Shared by password and passkey logins.
+func setAuthCookies(w http.ResponseWriter, tokens *domain.TokenPair) {
 	// Access Token: Short-lived (e.g., 15 minutes)
 	http.SetCookie(w, &http.Cookie{
 		Name:     "kari_access_token",
@@ -0,0 +1,94 @@
+// api/internal/api/handlers/bandwidth.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+type SetTransferQuotaRequest struct {
+	QuotaGB      int    `json:"quota_gb" validate:"required,min=1,max=1000000"`
+	Action       string `json:"action" validate:"omitempty,oneof=alert throttle"`
+	ThrottleKBps int    `json:"throttle_kbps" validate:"omitempty,min=8,max=1048576"`
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type BandwidthHandler struct {
+	Service domain.BandwidthManager
+}
+
+func NewBandwidthHandler(service domain.BandwidthManager) *BandwidthHandler {
+	return &BandwidthHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// Get handles GET /api/v1/applications/{id}/bandwidth
+// Returns the last 12 months of transfer plus the quota, if any.
+func (h *BandwidthHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	usage, err := h.Service.GetBandwidth(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(usage)
+}
+
+// SetQuota handles PUT /api/v1/applications/{id}/bandwidth/quota
+// Takes effect immediately: raising the quota lifts an active throttle.
+func (h *BandwidthHandler) SetQuota(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	var req SetTransferQuotaRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	quota, err := h.Service.SetQuota(r.Context(), appID, userClaims.Subject, domain.TransferQuota{
+		QuotaGB:      req.QuotaGB,
+		Action:       req.Action,
+		ThrottleKBps: req.ThrottleKBps,
+	})
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quota)
+}
+
+// RemoveQuota handles DELETE /api/v1/applications/{id}/bandwidth/quota
+func (h *BandwidthHandler) RemoveQuota(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.Service.RemoveQuota(r.Context(), appID, userClaims.Subject); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,123 @@
+// api/internal/api/handlers/bucket.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/api/middleware"
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+type ProvisionBucketRequest struct {
+	QuotaBytes int64 `json:"quota_bytes" validate:"min=0"` // 0 = platform default
+}
+
+type UpdateBucketQuotaRequest struct {
+	QuotaBytes int64 `json:"quota_bytes" validate:"required,min=1"`
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type BucketHandler struct {
+	Service domain.BucketManager
+}
+
+func NewBucketHandler(service domain.BucketManager) *BucketHandler {
+	return &BucketHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// Get handles GET /api/v1/applications/{id}/bucket
+// Returns the bucket with quota and usage; the secret key is never exposed.
+func (h *BucketHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	usage, err := h.Service.GetBucket(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(usage)
+}
+
+// Provision handles POST /api/v1/applications/{id}/bucket
+// Credentials reach the app as S3_* env vars on its next deploy.
+func (h *BucketHandler) Provision(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	var req ProvisionBucketRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	bucket, err := h.Service.ProvisionBucket(r.Context(), appID, userClaims.Subject, req.QuotaBytes, quotaAdmin(userClaims))
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(bucket)
+}
+
+// UpdateQuota handles PUT /api/v1/applications/{id}/bucket/quota
+// Above the platform default it needs the quotas:manage scope.
+func (h *BucketHandler) UpdateQuota(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	var req UpdateBucketQuotaRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	bucket, err := h.Service.SetBucketQuota(r.Context(), appID, userClaims.Subject, req.QuotaBytes, quotaAdmin(userClaims))
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bucket)
+}
+
+// Delete handles DELETE /api/v1/applications/{id}/bucket
+// 🔥 Destroys every object in the bucket.
+func (h *BucketHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteBucket(r.Context(), appID, userClaims.Subject); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// quotaAdmin reports whether the caller may set quotas above the plan default.
+func quotaAdmin(claims *domain.UserClaims) bool {
+	return middleware.HasPermission(claims.Permissions, "quotas:manage")
+}
@@ -0,0 +1,329 @@
+// api/internal/api/handlers/chatops.go
+package handlers
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/utils"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type ChatOpsHandler struct {
+	Service            domain.ChatOps
+	SlackSigningSecret []byte            // Empty disables the Slack endpoints
+	DiscordPublicKey   ed25519.PublicKey // Nil disables the Discord endpoint
+}
+
+func NewChatOpsHandler(service domain.ChatOps, slackSigningSecret string, discordPublicKey ed25519.PublicKey) *ChatOpsHandler {
+	return &ChatOpsHandler{
+		Service:            service,
+		SlackSigningSecret: []byte(slackSigningSecret),
+		DiscordPublicKey:   discordPublicKey,
+	}
+}
+
+// Slack caps slash command payloads well below this
+const maxChatCommandBody = 64 << 10
+
+type slackReply struct {
+	ResponseType string `json:"response_type"` // ephemeral | in_channel
+	Text         string `json:"text"`
+}
+
+// slackInteraction is the subset of a block_actions payload Kari reads.
+type slackInteraction struct {
+	Type        string `json:"type"`
+	ResponseURL string `json:"response_url"`
+	Team        struct {
+		ID string `json:"id"`
+	} `json:"team"`
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+// Discord interaction and response types
+const (
+	discordPing               = 1
+	discordApplicationCommand = 2
+	discordMessageComponent   = 3
+
+	discordPong           = 1
+	discordChannelMessage = 4
+	discordUpdateMessage  = 7
+	discordEphemeralFlag  = 64
+)
+
+type discordUser struct {
+	ID string `json:"id"`
+}
+
+// discordInteraction is the subset of an interaction payload Kari reads. The
+// "/kari" command is registered with a single string option.
+type discordInteraction struct {
+	Type    int    `json:"type"`
+	GuildID string `json:"guild_id"`
+	Member  *struct {
+		User discordUser `json:"user"`
+	} `json:"member"`
+	User *discordUser `json:"user"` // Set instead of Member in DMs
+	Data struct {
+		Name     string `json:"name"`
+		CustomID string `json:"custom_id"`
+		Options  []struct {
+			Value any `json:"value"`
+		} `json:"options"`
+	} `json:"data"`
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// SlackCommand handles POST /api/v1/integrations/slack/commands
+// Public route: the Slack request signature is the only credential.
+func (h *ChatOpsHandler) SlackCommand(w http.ResponseWriter, r *http.Request) {
+	rawBody, ok := h.verifiedSlackBody(w, r)
+	if !ok {
+		return
+	}
+
+	form, err := url.ParseQuery(string(rawBody))
+	if err != nil || form.Get("team_id") == "" || form.Get("user_id") == "" {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Malformed slash command")
+		return
+	}
+
+	// Slack shows the body to the user; errors are replies, not HTTP failures
+	reply := h.Service.HandleCommand(r.Context(), domain.ChatCommand{
+		Provider:       domain.ChatProviderSlack,
+		TeamID:         form.Get("team_id"),
+		ExternalUserID: form.Get("user_id"),
+		Text:           form.Get("text"),
+		ResponseURL:    form.Get("response_url"),
+	})
+
+	out := slackReply{ResponseType: "in_channel", Text: reply.Text}
+	if reply.Ephemeral {
+		out.ResponseType = "ephemeral"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
+
+// SlackInteraction handles POST /api/v1/integrations/slack/interactions
+// Approve/reject clicks on approval messages. The outcome replaces the
+// original message via response_url, so the HTTP answer is empty.
+func (h *ChatOpsHandler) SlackInteraction(w http.ResponseWriter, r *http.Request) {
+	rawBody, ok := h.verifiedSlackBody(w, r)
+	if !ok {
+		return
+	}
+
+	form, err := url.ParseQuery(string(rawBody))
+	var payload slackInteraction
+	if err == nil {
+		err = json.Unmarshal([]byte(form.Get("payload")), &payload)
+	}
+	if err != nil || payload.Type != "block_actions" || len(payload.Actions) == 0 {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Unsupported interaction")
+		return
+	}
+
+	clicked := payload.Actions[0]
+	approvalID, err := uuid.Parse(clicked.Value)
+	if err != nil || (clicked.ActionID != domain.ChatApproveAction && clicked.ActionID != domain.ChatRejectAction) {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Unsupported interaction")
+		return
+	}
+
+	reply := h.Service.HandleAction(r.Context(), domain.ChatAction{
+		Provider:       domain.ChatProviderSlack,
+		TeamID:         payload.Team.ID,
+		ExternalUserID: payload.User.ID,
+		ApprovalID:     approvalID,
+		Approve:        clicked.ActionID == domain.ChatApproveAction,
+		ResponseURL:    payload.ResponseURL,
+	})
+	if reply.Replace {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(slackReply{ResponseType: "ephemeral", Text: reply.Text})
+}
+
+// DiscordInteraction handles POST /api/v1/integrations/discord/interactions
+// Discord's single interactions endpoint: PINGs, the /kari command, and
+// approval buttons. Verified with the application's Ed25519 public key.
+func (h *ChatOpsHandler) DiscordInteraction(w http.ResponseWriter, r *http.Request) {
+	if h.DiscordPublicKey == nil {
+		writeError(w, r, http.StatusNotFound, domain.CodeNotFound, "Discord integration is not configured")
+		return
+	}
+
+	rawBody, err := io.ReadAll(io.LimitReader(r.Body, maxChatCommandBody))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Failed to read body")
+		return
+	}
+	// 🛡️ Zero-Trust: Discord itself probes this with forged signatures and
+	// disables the endpoint unless they are refused
+	err = utils.VerifyDiscordSignature(rawBody,
+		r.Header.Get("X-Signature-Timestamp"),
+		r.Header.Get("X-Signature-Ed25519"),
+		h.DiscordPublicKey, time.Now())
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized: Invalid signature")
+		return
+	}
+
+	var in discordInteraction
+	if err := json.Unmarshal(rawBody, &in); err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeInvalidJSON, "Invalid JSON payload")
+		return
+	}
+
+	userID := ""
+	if in.Member != nil {
+		userID = in.Member.User.ID
+	} else if in.User != nil {
+		userID = in.User.ID
+	}
+
+	var out map[string]any
+	switch in.Type {
+	case discordPing:
+		out = map[string]any{"type": discordPong}
+	case discordApplicationCommand:
+		text := ""
+		if len(in.Data.Options) > 0 {
+			text, _ = in.Data.Options[0].Value.(string)
+		}
+		reply := h.Service.HandleCommand(r.Context(), domain.ChatCommand{
+			Provider:       domain.ChatProviderDiscord,
+			TeamID:         in.GuildID,
+			ExternalUserID: userID,
+			Text:           text,
+		})
+		out = map[string]any{"type": discordChannelMessage, "data": discordMessage(reply)}
+	case discordMessageComponent:
+		action, rawID, _ := strings.Cut(in.Data.CustomID, ":")
+		approvalID, err := uuid.Parse(rawID)
+		if err != nil || (action != domain.ChatApproveAction && action != domain.ChatRejectAction) {
+			writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Unsupported interaction")
+			return
+		}
+		reply := h.Service.HandleAction(r.Context(), domain.ChatAction{
+			Provider:       domain.ChatProviderDiscord,
+			TeamID:         in.GuildID,
+			ExternalUserID: userID,
+			ApprovalID:     approvalID,
+			Approve:        action == domain.ChatApproveAction,
+		})
+		if reply.Replace {
+			data := discordMessage(reply)
+			data["components"] = []any{} // Drop the buttons once decided
+			out = map[string]any{"type": discordUpdateMessage, "data": data}
+		} else {
+			out = map[string]any{"type": discordChannelMessage, "data": discordMessage(reply)}
+		}
+	default:
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Unsupported interaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
+
+// verifiedSlackBody reads the raw body and checks Slack's request signature,
+// which covers it byte for byte, before anything is parsed.
+func (h *ChatOpsHandler) verifiedSlackBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if len(h.SlackSigningSecret) == 0 {
+		writeError(w, r, http.StatusNotFound, domain.CodeNotFound, "Slack integration is not configured")
+		return nil, false
+	}
+
+	rawBody, err := io.ReadAll(io.LimitReader(r.Body, maxChatCommandBody))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Failed to read body")
+		return nil, false
+	}
+
+	// 🛡️ Zero-Trust: Verify before parsing anything
+	err = utils.VerifySlackSignature(rawBody,
+		r.Header.Get("X-Slack-Request-Timestamp"),
+		r.Header.Get("X-Slack-Signature"),
+		h.SlackSigningSecret, time.Now())
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized: Invalid signature")
+		return nil, false
+	}
+	return rawBody, true
+}
+
+func discordMessage(reply domain.ChatReply) map[string]any {
+	data := map[string]any{"content": reply.Text}
+	if reply.Ephemeral {
+		data["flags"] = discordEphemeralFlag
+	}
+	return data
+}
+
+// CreateLinkCode handles POST /api/v1/integrations/chat/link-code
+// Returns a one-time code to redeem in chat with "/kari link CODE".
+func (h *ChatOpsHandler) CreateLinkCode(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	code, expiresAt, err := h.Service.IssueLinkCode(r.Context(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"code":       code,
+		"expires_at": expiresAt,
+	})
+}
+
+// ListIdentities handles GET /api/v1/integrations/chat/identities
+func (h *ChatOpsHandler) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	identities, err := h.Service.ListIdentities(r.Context(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(identities)
+}
@@ -0,0 +1,70 @@
+// api/internal/api/handlers/crash.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type CrashHandler struct {
+	Service domain.CrashReportReader
+}
+
+func NewCrashHandler(service domain.CrashReportReader) *CrashHandler {
+	return &CrashHandler{Service: service}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// List handles GET /api/v1/admin/crashes?limit=
+// Summaries only; stacks and log rings come from the detail endpoint.
+func (h *CrashHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit := domain.DefaultPageLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = min(n, domain.MaxPageLimit)
+	}
+
+	reports, err := h.Service.List(r.Context(), limit)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+// Get handles GET /api/v1/admin/crashes/{id}
+func (h *CrashHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid crash report ID format")
+		return
+	}
+
+	report, err := h.Service.Get(r.Context(), id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,92 @@
+// api/internal/api/handlers/deploy_key.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type DeployKeyHandler struct {
+	Service domain.DeployKeyManager
+}
+
+func NewDeployKeyHandler(service domain.DeployKeyManager) *DeployKeyHandler {
+	return &DeployKeyHandler{Service: service}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// Get handles GET /api/v1/applications/{id}/deploy-key
+func (h *DeployKeyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	key, err := h.Service.GetDeployKey(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(key)
+}
+
+// Generate handles POST /api/v1/applications/{id}/deploy-key
+// Calling it again rotates the key.
+func (h *DeployKeyHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	key, err := h.Service.GenerateDeployKey(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(key)
+}
+
+// Revoke handles DELETE /api/v1/applications/{id}/deploy-key
+func (h *DeployKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.RevokeDeployKey(r.Context(), appID, userClaims.Subject); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *DeployKeyHandler) parseRequest(w http.ResponseWriter, r *http.Request) (*domain.UserClaims, uuid.UUID, bool) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return nil, uuid.Nil, false
+	}
+	appID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid application ID format")
+		return nil, uuid.Nil, false
+	}
+	return userClaims, appID, true
+}
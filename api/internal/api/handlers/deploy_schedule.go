@@ -0,0 +1,88 @@
+// api/internal/api/handlers/deploy_schedule.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type DeployScheduleHandler struct {
+	Service domain.DeploymentScheduler
+}
+
+func NewDeployScheduleHandler(service domain.DeploymentScheduler) *DeployScheduleHandler {
+	return &DeployScheduleHandler{Service: service}
+}
+
+type scheduleDeploymentRequest struct {
+	ScheduledAt time.Time `json:"scheduled_at" validate:"required"` // RFC 3339, e.g. 2025-06-01T03:00:00+02:00
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// Schedule handles POST /api/v1/applications/{id}/scheduled-deployments
+// Queues a build of the app's tracked branch for scheduled_at.
+func (h *DeployScheduleHandler) Schedule(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	var req scheduleDeploymentRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	scheduled, err := h.Service.ScheduleAppDeployment(r.Context(), appID, userClaims.Subject, req.ScheduledAt)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(scheduled)
+}
+
+// List handles GET /api/v1/scheduled-deployments
+// Deployments still waiting for their time, soonest first.
+func (h *DeployScheduleHandler) List(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	scheduled, err := h.Service.ListScheduled(r.Context(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scheduled)
+}
+
+// Cancel handles POST /api/v1/deployments/{id}/cancel
+// 409 once the scheduler has already queued the build.
+func (h *DeployScheduleHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid deployment ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Cancel(r.Context(), id.String(), userClaims.Subject); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,9 +1,11 @@
-package http
+package handlers
 
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -12,6 +14,16 @@ import (
 	"kari/api/internal/api/middleware"
 )
 
+// CreateDeploymentRequest is the wizard payload for a new GitOps deployment.
+type CreateDeploymentRequest struct {
+	Name         string `json:"name" validate:"required,fqdn,max=255"`
+	RepoURL      string `json:"repo_url" validate:"required,max=1024"`
+	Branch       string `json:"branch" validate:"required,max=100"`
+	BuildCommand string `json:"build_command" validate:"required,max=500"`
+	TargetPort   int    `json:"target_port" validate:"required,gt=1024,lt=65536"`
+	SSHKey       string `json:"ssh_key" validate:"omitempty,max=16384"`
+}
+
 type DeploymentHandler struct {
 	repo   domain.DeploymentRepository
 	crypto domain.CryptoService
@@ -28,24 +40,15 @@ func NewDeploymentHandler(repo domain.DeploymentRepository, crypto domain.Crypto
 
 // CreateDeployment handles the initial POST request from the SvelteKit Wizard
 func (h *DeploymentHandler) CreateDeployment(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Name         string `json:"name"`
-		RepoURL      string `json:"repo_url"`
-		Branch       string `json:"branch"`
-		BuildCommand string `json:"build_command"`
-		TargetPort   int    `json:"target_port"`
-		SSHKey       string `json:"ssh_key"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Malformed request body", http.StatusBadRequest)
+	var req CreateDeploymentRequest
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
 	// 🛡️ Zero-Trust: Identify the requesting user
 	userID, ok := r.Context().Value(middleware.UserKey).(uuid.UUID)
 	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -56,7 +59,7 @@ func (h *DeploymentHandler) CreateDeployment(w http.ResponseWriter, r *http.Requ
 	if req.SSHKey != "" {
 		enc, err := h.crypto.Encrypt(r.Context(), []byte(req.SSHKey), []byte(appID))
 		if err != nil {
-			http.Error(w, "Internal security error", http.StatusInternalServerError)
+			writeError(w, r, http.StatusInternalServerError, domain.CodeInternal, "Internal security error")
 			return
 		}
 		encryptedKey = enc
@@ -76,7 +79,7 @@ func (h *DeploymentHandler) CreateDeployment(w http.ResponseWriter, r *http.Requ
 	}
 
 	if err := h.repo.Save(r.Context(), deployment); err != nil {
-		http.Error(w, "Failed to queue deployment", http.StatusInternalServerError)
+		HandleError(w, r, err)
 		return
 	}
 
@@ -88,11 +91,17 @@ func (h *DeploymentHandler) CreateDeployment(w http.ResponseWriter, r *http.Requ
 	})
 }
 
-// StreamLogs replaces the WebSocket implementation with SSE
+// StreamLogs replaces the WebSocket implementation with SSE.
+// ?format=html|plain|ansi converts or strips terminal escapes (default ansi).
 func (h *DeploymentHandler) StreamLogs(w http.ResponseWriter, r *http.Request) {
 	deploymentID := chi.URLParam(r, "id")
 	if _, err := uuid.Parse(deploymentID); err != nil {
-		http.Error(w, "Invalid deployment ID", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid deployment ID")
+		return
+	}
+	renderer, ok := logRendererFor(r)
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "format must be html, plain or ansi")
 		return
 	}
 
@@ -114,11 +123,34 @@ func (h *DeploymentHandler) StreamLogs(w http.ResponseWriter, r *http.Request) {
 		case <-r.Context().Done():
 			return
 		case msg := <-logChan:
-			// 🛡️ Logic: Format message as data chunk
-			fmt.Fprintf(w, "data: %s\n\n", msg)
+			// 🛡️ Logic: One JSON frame per message; level and stage ride along
+			msg.Content = renderer.Render(msg.Content)
+			frame, err := json.Marshal(msg)
+			if err != nil {
+				continue
+			}
+			writeSSEData(w, string(frame))
 			if err := rc.Flush(); err != nil {
 				return
 			}
 		}
 	}
 }
+
+// logRendererFor builds the per-stream renderer for the ?format= query.
+func logRendererFor(r *http.Request) (*telemetry.LogRenderer, bool) {
+	format, ok := telemetry.ParseLogFormat(r.URL.Query().Get("format"))
+	if !ok {
+		return nil, false
+	}
+	return telemetry.NewLogRenderer(format), true
+}
+
+// writeSSEData frames msg as one SSE event. Each line gets its own "data:"
+// field; the client rejoins them with newlines.
+func writeSSEData(w io.Writer, msg string) {
+	for _, line := range strings.Split(msg, "\n") {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	io.WriteString(w, "\n")
+}
@@ -0,0 +1,96 @@
+// api/internal/api/handlers/deployment_logs.go
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// logDownloadTimeout replaces the server's 15s write timeout for downloads;
+// a long build log on a slow link needs far longer than a JSON response.
+const logDownloadTimeout = 10 * time.Minute
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type DeploymentLogHandler struct {
+	Service domain.DeploymentLogService
+}
+
+func NewDeploymentLogHandler(service domain.DeploymentLogService) *DeploymentLogHandler {
+	return &DeploymentLogHandler{Service: service}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// List handles GET /api/v1/deployments/{id}/logs?after_seq=&limit=
+// Returns log lines in write order, one page at a time; pass next_after_seq
+// back as after_seq to scroll forward or to poll for new output.
+func (h *DeploymentLogHandler) List(w http.ResponseWriter, r *http.Request) {
+	userClaims, deploymentID, ok := parseOwnedID(w, r, "Invalid deployment ID format")
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	var afterSeq int64
+	if raw := q.Get("after_seq"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 0 {
+			writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid after_seq")
+			return
+		}
+		afterSeq = n
+	}
+	var limit int
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	page, err := h.Service.ReadLines(r.Context(), userClaims.Subject, deploymentID, afterSeq, limit)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(page)
+}
+
+// Download handles GET /api/v1/deployments/{id}/logs/download
+// Streams the complete build log as gzip, whether it still lives in Postgres
+// or has been archived to object storage.
+func (h *DeploymentLogHandler) Download(w http.ResponseWriter, r *http.Request) {
+	userClaims, deploymentID, ok := parseOwnedID(w, r, "Invalid deployment ID format")
+	if !ok {
+		return
+	}
+
+	download, err := h.Service.OpenDownload(r.Context(), userClaims.Subject, deploymentID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	http.NewResponseController(w).SetWriteDeadline(time.Now().Add(logDownloadTimeout))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, download.Filename))
+	w.Header().Set("Cache-Control", "no-store")
+
+	// Headers are already sent; a failure here can only cut the stream short
+	download.WriteTo(w)
+}
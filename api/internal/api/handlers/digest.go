@@ -0,0 +1,103 @@
+// api/internal/api/handlers/digest.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+type DigestPreferenceRequest struct {
+	Frequency domain.DigestFrequency `json:"frequency" validate:"required,oneof=off daily weekly"`
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type DigestHandler struct {
+	Service domain.DigestManager
+}
+
+func NewDigestHandler(service domain.DigestManager) *DigestHandler {
+	return &DigestHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// GetPreference handles GET /api/v1/digest/preferences
+func (h *DigestHandler) GetPreference(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	frequency, err := h.Service.GetPreference(r.Context(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(DigestPreferenceRequest{Frequency: frequency})
+}
+
+// SetPreference handles PUT /api/v1/digest/preferences
+func (h *DigestHandler) SetPreference(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req DigestPreferenceRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := h.Service.SetPreference(r.Context(), userClaims.Subject, req.Frequency); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(req)
+}
+
+// Unsubscribe handles GET and POST /api/v1/digest/unsubscribe?token=
+// GET serves the link in the email body; POST is the RFC 8058 one-click
+// request mail clients send on behalf of the List-Unsubscribe header.
+func (h *DigestHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Missing unsubscribe token")
+		return
+	}
+
+	if err := h.Service.Unsubscribe(r.Context(), token); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, domain.CodeNotFound, "Unknown or expired unsubscribe link")
+			return
+		}
+		HandleError(w, r, err)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(`<!doctype html><html><body style="font-family:sans-serif">` +
+		`<p>You will no longer receive Kari Action Center digests.</p>` +
+		`<p>You can turn them back on under Settings &rarr; Notifications.</p></body></html>`))
+}
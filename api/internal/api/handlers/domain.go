@@ -40,44 +40,47 @@ func NewDomainHandler(service domain.DomainService) *DomainHandler {
 // 3. HTTP Methods
 // ==============================================================================
 
-// List handles GET /api/v1/domains
+// List handles GET /api/v1/domains?status=&sort=&limit=&cursor=
 func (h *DomainHandler) List(w http.ResponseWriter, r *http.Request) {
 	// 1. Extract the cryptographically verified user from the JWT Context
 	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
 	if !ok {
-		http.Error(w, `{"message": "Unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
-	// 2. Fetch the domains scoped exclusively to this user ID
-	domains, err := h.Service.ListDomains(r.Context(), userClaims.Subject)
+	// 2. Parse pagination, sort, and filter params
+	page, ok := parsePageRequest(r)
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid limit or cursor")
+		return
+	}
+	filter := domain.DomainFilter{Status: r.URL.Query().Get("status")}
+
+	// 3. Fetch the domains scoped exclusively to this user ID
+	result, err := h.Service.ListDomains(r.Context(), userClaims.Subject, filter, page)
 	if err != nil {
 		HandleError(w, r, err)
 		return
 	}
 
-	// 3. Return JSON array
+	// 4. Return JSON array (pagination metadata travels in headers)
+	writePageHeaders(w, r, result.Total, result.NextCursor)
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(domains)
+	json.NewEncoder(w).Encode(result.Items)
 }
 
 // Create handles POST /api/v1/domains
 func (h *DomainHandler) Create(w http.ResponseWriter, r *http.Request) {
 	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
 	if !ok {
-		http.Error(w, `{"message": "Unauthorized"}`, http.StatusUnauthorized)
-		return
-	}
-
-	var req CreateDomainRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"message": "Invalid JSON payload"}`, http.StatusBadRequest)
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Strict payload validation (fqdn check)
-	if err := validate.Struct(req); err != nil {
-		HandleError(w, r, err)
+	var req CreateDomainRequest
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -91,6 +94,8 @@ func (h *DomainHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	// The Service layer will insert this into Postgres AND instruct the Rust Agent
 	// to generate and activate the Nginx reverse proxy configuration.
+	// 📏 Quotas: The INSERT itself refuses a domain past the owner's limit
+	// (ErrQuotaExceeded), before the Muscle writes any vhost.
 	createdDomain, err := h.Service.CreateDomain(r.Context(), newDomain)
 	if err != nil {
 		HandleError(w, r, err)
@@ -106,14 +111,14 @@ func (h *DomainHandler) Create(w http.ResponseWriter, r *http.Request) {
 func (h *DomainHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
 	if !ok {
-		http.Error(w, `{"message": "Unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	domainIDStr := chi.URLParam(r, "id")
 	domainID, err := uuid.Parse(domainIDStr)
 	if err != nil {
-		http.Error(w, `{"message": "Invalid domain ID format"}`, http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid domain ID format")
 		return
 	}
 
@@ -134,14 +139,14 @@ func (h *DomainHandler) Delete(w http.ResponseWriter, r *http.Request) {
 func (h *DomainHandler) ProvisionSSL(w http.ResponseWriter, r *http.Request) {
 	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
 	if !ok {
-		http.Error(w, `{"message": "Unauthorized"}`, http.StatusUnauthorized)
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	domainIDStr := chi.URLParam(r, "id")
 	domainID, err := uuid.Parse(domainIDStr)
 	if err != nil {
-		http.Error(w, `{"message": "Invalid domain ID format"}`, http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid domain ID format")
 		return
 	}
 
@@ -0,0 +1,93 @@
+// api/internal/api/handlers/drift.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type DriftHandler struct {
+	Service domain.DriftReconciler
+}
+
+func NewDriftHandler(service domain.DriftReconciler) *DriftHandler {
+	return &DriftHandler{Service: service}
+}
+
+type adoptDriftRequest struct {
+	OwnerID uuid.UUID `json:"owner_id" validate:"required"`
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// List handles GET /api/v1/admin/drift?status=open
+// Without a status, settled findings are listed too, most recently seen first.
+func (h *DriftHandler) List(w http.ResponseWriter, r *http.Request) {
+	status := domain.DriftStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", domain.DriftOpen, domain.DriftResolved, domain.DriftAdopted, domain.DriftCleaned:
+	default:
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid status; supported: open, resolved, adopted, cleaned")
+		return
+	}
+
+	findings, err := h.Service.ListDrift(r.Context(), status)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(findings)
+}
+
+// Adopt handles POST /api/v1/admin/drift/{id}/adopt
+// Registers the orphan's domain to owner_id instead of removing it.
+func (h *DriftHandler) Adopt(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid drift finding ID format")
+	if !ok {
+		return
+	}
+
+	var req adoptDriftRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	finding, err := h.Service.Adopt(r.Context(), id, userClaims.Subject, req.OwnerID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(finding)
+}
+
+// Cleanup handles POST /api/v1/admin/drift/{id}/cleanup
+// Removes the orphaned resource from the host.
+func (h *DriftHandler) Cleanup(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid drift finding ID format")
+	if !ok {
+		return
+	}
+
+	finding, err := h.Service.Cleanup(r.Context(), id, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(finding)
+}
@@ -0,0 +1,138 @@
+// api/internal/api/handlers/errors.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+
+	"kari/api/internal/api/middleware"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/db"
+	"kari/api/internal/i18n"
+)
+
+// ==============================================================================
+// 1. Central Error Mapping
+// ==============================================================================
+
+// HandleError translates any service/repository error into the standard envelope.
+// 🛡️ Zero-Trust: Unknown errors are logged with full detail but surface to the
+// browser only as a generic INTERNAL_ERROR — no SQL, paths, or gRPC internals leak.
+func HandleError(w http.ResponseWriter, r *http.Request, err error) {
+	// 1. Validation failures carry per-field detail for inline form errors
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		middleware.WriteErrorResponse(w, r, http.StatusBadRequest, domain.ErrorResponse{
+			Code:    domain.CodeValidationFailed,
+			Message: "One or more fields are invalid",
+			Fields:  fieldErrors(i18n.FromContext(r.Context()), verrs),
+		})
+		return
+	}
+
+	// 2. Malformed or oversized JSON bodies
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		middleware.WriteError(w, r, http.StatusBadRequest, domain.CodeInvalidJSON, "Invalid JSON payload")
+		return
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		middleware.WriteError(w, r, http.StatusRequestEntityTooLarge, domain.CodePayloadTooLarge, "Request body is too large")
+		return
+	}
+
+	// 3. Classified Muscle failures keep their own UI-safe code and copy
+	var agentErr domain.AgentError
+	if errors.As(err, &agentErr) {
+		status := http.StatusBadGateway
+		if agentErr.Code == domain.ErrAgentUnreachable {
+			status = http.StatusServiceUnavailable // Transient: the UI may retry
+		}
+		middleware.WriteErrorResponse(w, r, status, domain.ErrorResponse{
+			Code:    domain.ErrorCode(agentErr.Code),
+			Title:   agentErr.Title,
+			Message: agentErr.Message,
+		})
+		return
+	}
+
+	// 4. Domain sentinels
+	switch {
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, db.ErrProfileNotFound):
+		middleware.WriteError(w, r, http.StatusNotFound, domain.CodeNotFound, "The requested resource was not found")
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		middleware.WriteError(w, r, http.StatusUnauthorized, domain.CodeInvalidCredentials, "Invalid email or password")
+	case errors.Is(err, domain.ErrPasskeyRequired):
+		middleware.WriteError(w, r, http.StatusForbidden, domain.CodePasskeyRequired, "This account must sign in with a passkey")
+	case errors.Is(err, domain.ErrPasswordExpired):
+		middleware.WriteError(w, r, http.StatusForbidden, domain.CodePasswordExpired, "Your password has expired and must be changed")
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		middleware.WriteError(w, r, http.StatusForbidden, domain.CodeQuotaExceeded, "This would exceed your account's resource quota")
+	case errors.Is(err, domain.ErrForbidden):
+		middleware.WriteError(w, r, http.StatusForbidden, domain.CodeForbidden, "You do not have permission to perform this action")
+	case errors.Is(err, domain.ErrConflict), errors.Is(err, db.ErrConcurrencyConflict):
+		middleware.WriteError(w, r, http.StatusConflict, domain.CodeConflict, "The resource was modified or already exists")
+	case errors.Is(err, domain.ErrValidation):
+		middleware.WriteError(w, r, http.StatusBadRequest, domain.CodeValidationFailed, "The request failed validation")
+	case errors.Is(err, domain.ErrUnavailable):
+		middleware.WriteError(w, r, http.StatusServiceUnavailable, domain.CodeServiceUnavailable, "A required system component is unavailable. Try again shortly.")
+	default:
+		slog.Default().Error("Unhandled API error",
+			slog.String("path", r.URL.Path),
+			slog.String("method", r.Method),
+			slog.Any("error", err))
+		middleware.WriteError(w, r, http.StatusInternalServerError, domain.CodeInternal, "An unexpected error occurred")
+	}
+}
+
+// ==============================================================================
+// 2. Helpers
+// ==============================================================================
+
+// writeError is the handler-local shorthand for simple, non-domain failures
+// (bad path params, missing identity, undecodable bodies).
+func writeError(w http.ResponseWriter, r *http.Request, status int, code domain.ErrorCode, message string) {
+	middleware.WriteError(w, r, status, code, message)
+}
+
+// fieldErrors flattens validator output into UI-friendly field errors.
+func fieldErrors(loc i18n.Locale, verrs validator.ValidationErrors) []domain.FieldError {
+	out := make([]domain.FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		out = append(out, domain.FieldError{
+			Field:   fe.Field(),
+			Code:    fe.Tag(),
+			Message: fieldMessage(loc, fe),
+		})
+	}
+	return out
+}
+
+// fieldMessage renders a message in the request's locale; the UI may still
+// localize by Code for fields it labels itself.
+func fieldMessage(loc i18n.Locale, fe validator.FieldError) string {
+	template := "{field} is invalid"
+	switch fe.Tag() {
+	case "required":
+		template = "{field} is required"
+	case "max":
+		template = "{field} must be at most {param} characters"
+	case "min":
+		template = "{field} must be at least {param} characters"
+	case "oneof":
+		template = "{field} must be one of: {param}"
+	case "email":
+		template = "{field} must be a valid email address"
+	case "fqdn":
+		template = "{field} must be a fully qualified domain name"
+	case "url":
+		template = "{field} must be a valid URL"
+	}
+	return i18n.Format(loc, template, "field", fe.Field(), "param", fe.Param())
+}
@@ -0,0 +1,199 @@
+// api/internal/api/handlers/feed.go
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type FeedHandler struct {
+	Service domain.FeedManager
+}
+
+func NewFeedHandler(service domain.FeedManager) *FeedHandler {
+	return &FeedHandler{Service: service}
+}
+
+const icalTimeFormat = "20060102T150405Z"
+
+// RSS 2.0 document shape; only the elements feed readers rely on.
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Description string  `xml:"description"`
+	Category    string  `xml:"category"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// IssueToken handles POST /api/v1/feeds/token
+// Returns the subscription URLs once; calling it again revokes the old ones.
+func (h *FeedHandler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	token, err := h.Service.IssueToken(r.Context(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(token)
+}
+
+// RevokeToken handles DELETE /api/v1/feeds/token
+func (h *FeedHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.Service.RevokeToken(r.Context(), userClaims.Subject); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Calendar handles GET /api/v1/feeds/calendar.ics?token=
+// Public route: the token in the URL is the credential (RFC 5545 output).
+func (h *FeedHandler) Calendar(w http.ResponseWriter, r *http.Request) {
+	feed, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	var b strings.Builder
+	line := func(s string) { b.WriteString(foldICalLine(s)) }
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Kari//Feeds//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:Kari")
+	stamp := feed.GeneratedAt.UTC().Format(icalTimeFormat)
+	for _, item := range feed.Items {
+		line("BEGIN:VEVENT")
+		line("UID:" + escapeICalText(item.UID) + "@kari")
+		line("DTSTAMP:" + stamp)
+		line("DTSTART:" + item.Start.UTC().Format(icalTimeFormat))
+		if item.End != nil {
+			line("DTEND:" + item.End.UTC().Format(icalTimeFormat))
+		}
+		line("LAST-MODIFIED:" + item.Updated.UTC().Format(icalTimeFormat))
+		line("SUMMARY:" + escapeICalText(item.Title))
+		line("DESCRIPTION:" + escapeICalText(item.Description))
+		line("CATEGORIES:" + escapeICalText(string(item.Kind)))
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.Write([]byte(b.String()))
+}
+
+// RSS handles GET /api/v1/feeds/rss?token=
+// Same items as the calendar, for feed readers and chat RSS bots.
+func (h *FeedHandler) RSS(w http.ResponseWriter, r *http.Request) {
+	feed, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	doc := rssDocument{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         "Kari",
+			Link:          feed.Link,
+			Description:   "Certificate expiries, maintenance and incidents",
+			LastBuildDate: feed.GeneratedAt.UTC().Format(time.RFC1123Z),
+			Items:         make([]rssItem, 0, len(feed.Items)),
+		},
+	}
+	for _, item := range feed.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Description: item.Description,
+			Category:    string(item.Kind),
+			GUID:        rssGUID{Value: item.UID},
+			PubDate:     item.Updated.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(doc)
+}
+
+// load resolves the token; unknown tokens are a plain 404 so the endpoint
+// does not reveal which tokens once existed.
+func (h *FeedHandler) load(w http.ResponseWriter, r *http.Request) (*domain.Feed, bool) {
+	feed, err := h.Service.FeedForToken(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		HandleError(w, r, err)
+		return nil, false
+	}
+	return feed, true
+}
+
+// escapeICalText escapes TEXT values per RFC 5545 section 3.3.11.
+func escapeICalText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// foldICalLine splits content lines longer than 75 octets and terminates
+// them with CRLF, never cutting a UTF-8 sequence in half.
+func foldICalLine(s string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
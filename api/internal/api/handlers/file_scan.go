@@ -0,0 +1,81 @@
+// api/internal/api/handlers/file_scan.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type FileScanHandler struct {
+	Service domain.FileScanManager
+}
+
+func NewFileScanHandler(service domain.FileScanManager) *FileScanHandler {
+	return &FileScanHandler{Service: service}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// List handles GET /api/v1/applications/{id}/scans
+// Returns the most recent scans, newest first.
+func (h *FileScanHandler) List(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	scans, err := h.Service.ListScans(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scans)
+}
+
+// Scan handles POST /api/v1/applications/{id}/scans
+// Runs synchronously; findings also land in the Action Center.
+func (h *FileScanHandler) Scan(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	scan, err := h.Service.ScanApp(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(scan)
+}
+
+// AcceptChanges handles POST /api/v1/applications/{id}/scans/baseline
+// Marks the current files as known-good after the owner reviewed the drift.
+func (h *FileScanHandler) AcceptChanges(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	scan, err := h.Service.AcceptChanges(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(scan)
+}
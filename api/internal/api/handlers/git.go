@@ -0,0 +1,169 @@
+// api/internal/api/handlers/git.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type GitHandler struct {
+	Service domain.GitIntegration
+}
+
+func NewGitHandler(service domain.GitIntegration) *GitHandler {
+	return &GitHandler{Service: service}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// Connect handles GET /api/v1/git/connect/{provider}
+// Returns the consent URL; the SPA performs the redirect so the JWT never
+// has to ride along in a top-level navigation.
+func (h *GitHandler) Connect(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+	provider, ok := parseGitProvider(w, r, chi.URLParam(r, "provider"))
+	if !ok {
+		return
+	}
+
+	authorizeURL, err := h.Service.AuthorizeURL(r.Context(), userClaims.Subject, provider)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"authorize_url": authorizeURL})
+}
+
+// Callback handles GET /api/v1/git/callback/{provider}?code=&state=
+// Public route: the sealed state identifies the user.
+func (h *GitHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	provider, ok := parseGitProvider(w, r, chi.URLParam(r, "provider"))
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	if q.Get("code") == "" || q.Get("state") == "" {
+		// The user declined consent, or the provider reported an error
+		http.Redirect(w, r, "/settings/git?error="+url.QueryEscape(q.Get("error")), http.StatusFound)
+		return
+	}
+
+	if _, err := h.Service.CompleteOAuth(r.Context(), provider, q.Get("code"), q.Get("state")); err != nil {
+		http.Redirect(w, r, "/settings/git?error=connect_failed", http.StatusFound)
+		return
+	}
+	http.Redirect(w, r, "/settings/git?connected="+string(provider), http.StatusFound)
+}
+
+// ListConnections handles GET /api/v1/git/connections
+func (h *GitHandler) ListConnections(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	conns, err := h.Service.ListConnections(r.Context(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(conns)
+}
+
+// Disconnect handles DELETE /api/v1/git/connections/{provider}
+func (h *GitHandler) Disconnect(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+	provider, ok := parseGitProvider(w, r, chi.URLParam(r, "provider"))
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Disconnect(r.Context(), userClaims.Subject, provider); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListRepos handles GET /api/v1/git/repos?provider=
+func (h *GitHandler) ListRepos(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+	provider, ok := parseGitProvider(w, r, r.URL.Query().Get("provider"))
+	if !ok {
+		return
+	}
+
+	repos, err := h.Service.ListRepos(r.Context(), userClaims.Subject, provider)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(repos)
+}
+
+// ListBranches handles GET /api/v1/git/branches?provider=&repo=
+func (h *GitHandler) ListBranches(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+	provider, ok := parseGitProvider(w, r, r.URL.Query().Get("provider"))
+	if !ok {
+		return
+	}
+	repo := r.URL.Query().Get("repo")
+	if repo == "" || len(repo) > 255 {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid repo")
+		return
+	}
+
+	branches, err := h.Service.ListBranches(r.Context(), userClaims.Subject, provider, repo)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(branches)
+}
+
+func parseGitProvider(w http.ResponseWriter, r *http.Request, raw string) (domain.GitProvider, bool) {
+	switch p := domain.GitProvider(raw); p {
+	case domain.GitProviderGitHub, domain.GitProviderGitLab:
+		return p, true
+	default:
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Unknown git provider")
+		return "", false
+	}
+}
@@ -0,0 +1,130 @@
+// api/internal/api/handlers/maintenance_window.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type MaintenanceWindowHandler struct {
+	Service domain.MaintenanceWindowManager
+	Zones   domain.TimezoneManager // Reads offset-less times on the admin's clock
+}
+
+func NewMaintenanceWindowHandler(service domain.MaintenanceWindowManager, zones domain.TimezoneManager) *MaintenanceWindowHandler {
+	return &MaintenanceWindowHandler{Service: service, Zones: zones}
+}
+
+// scheduleMaintenanceRequest takes RFC 3339 times, or wall-clock times
+// without an offset ("2026-03-29T02:00") in the admin's time zone.
+type scheduleMaintenanceRequest struct {
+	Title        string `json:"title" validate:"required,max=200"`
+	Description  string `json:"description" validate:"max=4000"`
+	StartsAt     string `json:"starts_at" validate:"required"`
+	EndsAt       string `json:"ends_at" validate:"required"`
+	PauseDeploys bool   `json:"pause_deploys"`
+}
+
+// publicMaintenanceWindow is what the unauthenticated status endpoint shows;
+// who scheduled it stays private.
+type publicMaintenanceWindow struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	Active      bool      `json:"active"`
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// Status handles GET /api/v1/status/maintenance
+// Public: planned downtime for status pages, active windows first.
+func (h *MaintenanceWindowHandler) Status(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	windows := []publicMaintenanceWindow{}
+	for _, mw := range h.Service.Upcoming() {
+		windows = append(windows, publicMaintenanceWindow{
+			Title:       mw.Title,
+			Description: mw.Description,
+			StartsAt:    mw.StartsAt,
+			EndsAt:      mw.EndsAt,
+			Active:      mw.ActiveAt(now),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	json.NewEncoder(w).Encode(map[string]any{"windows": windows})
+}
+
+// List handles GET /api/v1/admin/maintenance-windows
+func (h *MaintenanceWindowHandler) List(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Service.Upcoming())
+}
+
+// Create handles POST /api/v1/admin/maintenance-windows
+func (h *MaintenanceWindowHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req scheduleMaintenanceRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	loc := h.Zones.LocationFor(r.Context(), userClaims.Subject)
+	startsAt, err := domain.ParseLocalTime(req.StartsAt, loc)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	endsAt, err := domain.ParseLocalTime(req.EndsAt, loc)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	window, err := h.Service.Schedule(r.Context(), userClaims.Subject, &domain.MaintenanceWindow{
+		Title:        req.Title,
+		Description:  req.Description,
+		StartsAt:     startsAt,
+		EndsAt:       endsAt,
+		PauseDeploys: req.PauseDeploys,
+	})
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(window)
+}
+
+// Delete handles DELETE /api/v1/admin/maintenance-windows/{id}
+// Cancelling an active window ends it immediately.
+func (h *MaintenanceWindowHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid maintenance window ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.Service.Cancel(r.Context(), userClaims.Subject, id); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
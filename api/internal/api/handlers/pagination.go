@@ -0,0 +1,78 @@
+// api/internal/api/handlers/pagination.go
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Query Parsing (?limit=&cursor=&sort=)
+// ==============================================================================
+
+// parsePageRequest reads the shared pagination params. A leading "-" on sort
+// means descending (e.g. ?sort=-created_at). With no sort, newest rows come first.
+// The repository validates the sort key against its own whitelist.
+func parsePageRequest(r *http.Request) (domain.PageRequest, bool) {
+	q := r.URL.Query()
+	page := domain.PageRequest{Desc: true}
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return page, false
+		}
+		page.Limit = limit
+	}
+
+	if raw := q.Get("cursor"); raw != "" {
+		cursor, err := domain.DecodeCursor(raw)
+		if err != nil {
+			return page, false
+		}
+		page.Cursor = cursor
+	}
+
+	if raw := q.Get("sort"); raw != "" {
+		page.Desc = strings.HasPrefix(raw, "-")
+		page.Sort = strings.TrimPrefix(raw, "-")
+	}
+
+	return page.Normalize(), true
+}
+
+// parseUUIDParam parses an optional UUID filter param; empty yields uuid.Nil.
+func parseUUIDParam(r *http.Request, name string) (uuid.UUID, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return uuid.Nil, true
+	}
+	id, err := uuid.Parse(raw)
+	return id, err == nil
+}
+
+// ==============================================================================
+// 2. Response Headers
+// ==============================================================================
+
+// writePageHeaders exposes pagination metadata without changing list bodies:
+// X-Total-Count, X-Next-Cursor, and an RFC 8288 Link header with rel="next".
+func writePageHeaders(w http.ResponseWriter, r *http.Request, total int, nextCursor string) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	if nextCursor == "" {
+		return
+	}
+	w.Header().Set("X-Next-Cursor", nextCursor)
+
+	next := url.URL{Path: r.URL.Path}
+	q := r.URL.Query()
+	q.Set("cursor", nextCursor)
+	next.RawQuery = q.Encode()
+	w.Header().Set("Link", "<"+next.String()+`>; rel="next"`)
+}
@@ -0,0 +1,171 @@
+// api/internal/api/handlers/passkey.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+// FinishPasskeyRequest carries the browser's PublicKeyCredential verbatim
+// next to the ceremony ID returned by the matching begin call.
+type FinishPasskeyRequest struct {
+	CeremonyID uuid.UUID       `json:"ceremony_id" validate:"required"`
+	Name       string          `json:"name" validate:"max=100"` // Registration only
+	Credential json.RawMessage `json:"credential" validate:"required"`
+}
+
+type beginPasskeyResponse struct {
+	CeremonyID uuid.UUID `json:"ceremony_id"`
+	Options    any       `json:"options"` // Pass to navigator.credentials.create()/get()
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type PasskeyHandler struct {
+	Service domain.PasskeyService
+}
+
+func NewPasskeyHandler(service domain.PasskeyService) *PasskeyHandler {
+	return &PasskeyHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// BeginLogin handles POST /api/v1/auth/passkey/login/begin
+func (h *PasskeyHandler) BeginLogin(w http.ResponseWriter, r *http.Request) {
+	ceremonyID, options, err := h.Service.BeginLogin(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(beginPasskeyResponse{CeremonyID: ceremonyID, Options: options})
+}
+
+// FinishLogin handles POST /api/v1/auth/passkey/login/finish
+// Sets the same HttpOnly session cookies as a password login.
+func (h *PasskeyHandler) FinishLogin(w http.ResponseWriter, r *http.Request) {
+	var req FinishPasskeyRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	tokenPair, user, err := h.Service.FinishLogin(r.Context(), req.CeremonyID, req.Credential)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	setAuthCookies(w, tokenPair)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Login successful",
+		"user": map[string]interface{}{
+			"id":       user.ID,
+			"username": user.Username,
+			"email":    user.Email,
+			"role_id":  user.RoleID,
+		},
+	})
+}
+
+// List handles GET /api/v1/auth/passkeys
+func (h *PasskeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	keys, err := h.Service.List(r.Context(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(keys)
+}
+
+// BeginRegistration handles POST /api/v1/auth/passkeys/register/begin
+func (h *PasskeyHandler) BeginRegistration(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	ceremonyID, options, err := h.Service.BeginRegistration(r.Context(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(beginPasskeyResponse{CeremonyID: ceremonyID, Options: options})
+}
+
+// FinishRegistration handles POST /api/v1/auth/passkeys/register/finish
+func (h *PasskeyHandler) FinishRegistration(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req FinishPasskeyRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	passkey, err := h.Service.FinishRegistration(r.Context(), userClaims.Subject, req.CeremonyID, req.Name, req.Credential)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			writeError(w, r, http.StatusBadRequest, domain.CodeValidationFailed, "The authenticator response could not be verified")
+			return
+		}
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(passkey)
+}
+
+// Delete handles DELETE /api/v1/auth/passkeys/{id}
+func (h *PasskeyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid passkey ID format")
+		return
+	}
+
+	if err := h.Service.Delete(r.Context(), userClaims.Subject, id); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
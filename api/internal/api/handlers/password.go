@@ -0,0 +1,109 @@
+// api/internal/api/handlers/password.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+// Length limits beyond max=72 come from the admin-configured policy.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required,max=72"`
+	NewPassword     string `json:"new_password" validate:"required,max=72"`
+}
+
+// ExpiredPasswordRequest is the forced-change form shown after a login
+// answered PASSWORD_EXPIRED.
+type ExpiredPasswordRequest struct {
+	Email           string `json:"email" validate:"required,email,max=255"`
+	CurrentPassword string `json:"current_password" validate:"required,max=72"`
+	NewPassword     string `json:"new_password" validate:"required,max=72"`
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type PasswordHandler struct {
+	Service domain.PasswordService
+}
+
+func NewPasswordHandler(service domain.PasswordService) *PasswordHandler {
+	return &PasswordHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// Change handles POST /api/v1/auth/password
+// Other sessions are revoked; this one continues on freshly set cookies.
+func (h *PasswordHandler) Change(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req ChangePasswordRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	tokenPair, err := h.Service.ChangePassword(r.Context(), userClaims.Subject, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		h.handlePolicyError(w, r, err)
+		return
+	}
+
+	setAuthCookies(w, tokenPair)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ChangeExpired handles POST /api/v1/auth/password/expired
+// Succeeds like a login, so the user lands in the panel with the new password.
+func (h *PasswordHandler) ChangeExpired(w http.ResponseWriter, r *http.Request) {
+	var req ExpiredPasswordRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	tokenPair, user, err := h.Service.ChangeExpiredPassword(r.Context(), req.Email, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		h.handlePolicyError(w, r, err)
+		return
+	}
+
+	setAuthCookies(w, tokenPair)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Password changed",
+		"user": map[string]interface{}{
+			"id":       user.ID,
+			"username": user.Username,
+			"email":    user.Email,
+			"role_id":  user.RoleID,
+		},
+	})
+}
+
+// ==============================================================================
+// 4. Helpers
+// ==============================================================================
+
+// handlePolicyError shows which policy rule a new password broke.
+func (h *PasswordHandler) handlePolicyError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, domain.ErrValidation) {
+		writeError(w, r, http.StatusBadRequest, domain.CodeValidationFailed, err.Error())
+		return
+	}
+	HandleError(w, r, err)
+}
@@ -0,0 +1,146 @@
+// api/internal/api/handlers/redaction.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+type CreateRedactionRuleRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Pattern     string `json:"pattern" validate:"required,max=500"`
+	Replacement string `json:"replacement" validate:"max=100"`
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type RedactionHandler struct {
+	Service domain.RedactionManager
+}
+
+func NewRedactionHandler(service domain.RedactionManager) *RedactionHandler {
+	return &RedactionHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// List handles GET /api/v1/redaction-rules (the caller's own rules)
+func (h *RedactionHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, tenantScope)
+}
+
+// Create handles POST /api/v1/redaction-rules. Patterns use RE2 syntax.
+func (h *RedactionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	h.create(w, r, tenantScope)
+}
+
+// Delete handles DELETE /api/v1/redaction-rules/{id}
+func (h *RedactionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	h.delete(w, r, tenantScope)
+}
+
+// ListPlatform handles GET /api/v1/admin/redaction-rules (applies to every tenant)
+func (h *RedactionHandler) ListPlatform(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, platformScope)
+}
+
+// CreatePlatform handles POST /api/v1/admin/redaction-rules
+func (h *RedactionHandler) CreatePlatform(w http.ResponseWriter, r *http.Request) {
+	h.create(w, r, platformScope)
+}
+
+// DeletePlatform handles DELETE /api/v1/admin/redaction-rules/{id}
+func (h *RedactionHandler) DeletePlatform(w http.ResponseWriter, r *http.Request) {
+	h.delete(w, r, platformScope)
+}
+
+// ==============================================================================
+// 4. Helpers
+// ==============================================================================
+
+// A scope maps the caller to whose rules they touch: their own, or the
+// platform-wide set (nil).
+type redactionScope func(userID uuid.UUID) *uuid.UUID
+
+func tenantScope(userID uuid.UUID) *uuid.UUID { return &userID }
+
+func platformScope(uuid.UUID) *uuid.UUID { return nil }
+
+func (h *RedactionHandler) list(w http.ResponseWriter, r *http.Request, scope redactionScope) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	rules, err := h.Service.ListRules(r.Context(), scope(userClaims.Subject))
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+func (h *RedactionHandler) create(w http.ResponseWriter, r *http.Request, scope redactionScope) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req CreateRedactionRuleRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	rule, err := h.Service.CreateRule(r.Context(), userClaims.Subject, scope(userClaims.Subject), req.Name, req.Pattern, req.Replacement)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			// The regexp error tells the user what to fix
+			writeError(w, r, http.StatusBadRequest, domain.CodeValidationFailed, err.Error())
+			return
+		}
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+func (h *RedactionHandler) delete(w http.ResponseWriter, r *http.Request, scope redactionScope) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid redaction rule ID format")
+		return
+	}
+
+	if err := h.Service.DeleteRule(r.Context(), id, scope(userClaims.Subject)); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,175 @@
+// api/internal/api/handlers/registry.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+type CreateRegistryCredentialRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Registry string `json:"registry" validate:"required,max=255"` // docker.io, ghcr.io, registry.example.com:5000
+	Username string `json:"username" validate:"required,max=255"`
+	Password string `json:"password" validate:"required,max=4096"` // Password or access token
+}
+
+type AttachImageRequest struct {
+	Image                string     `json:"image" validate:"required,max=512"`
+	RegistryCredentialID *uuid.UUID `json:"registry_credential_id"` // null = public image
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type RegistryHandler struct {
+	Service domain.RegistryCredentialManager
+}
+
+func NewRegistryHandler(service domain.RegistryCredentialManager) *RegistryHandler {
+	return &RegistryHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// List handles GET /api/v1/registry-credentials
+func (h *RegistryHandler) List(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	creds, err := h.Service.ListCredentials(r.Context(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(creds)
+}
+
+// Create handles POST /api/v1/registry-credentials
+// The password is write-only: it is never returned by any endpoint.
+func (h *RegistryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req CreateRegistryCredentialRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	cred, err := h.Service.CreateCredential(r.Context(), userClaims.Subject, &domain.RegistryCredential{
+		Name:     req.Name,
+		Registry: req.Registry,
+		Username: req.Username,
+	}, req.Password)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(cred)
+}
+
+// Delete handles DELETE /api/v1/registry-credentials/{id}
+// Apps using the credential fall back to anonymous pulls.
+func (h *RegistryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid credential ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteCredential(r.Context(), id, userClaims.Subject); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Test handles POST /api/v1/registry-credentials/{id}/test
+// A rejected login still answers 200 with last_test_ok=false.
+func (h *RegistryHandler) Test(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid credential ID format")
+	if !ok {
+		return
+	}
+
+	cred, err := h.Service.TestCredential(r.Context(), id, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cred)
+}
+
+// AttachImage handles PUT /api/v1/applications/{id}/image
+func (h *RegistryHandler) AttachImage(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	var req AttachImageRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	app, err := h.Service.AttachImage(r.Context(), appID, userClaims.Subject, req.Image, req.RegistryCredentialID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(app)
+}
+
+// PullImage handles POST /api/v1/applications/{id}/pull
+func (h *RegistryHandler) PullImage(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.Service.PullImage(r.Context(), appID, userClaims.Subject); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseOwnedID extracts the caller and the {id} path parameter.
+func parseOwnedID(w http.ResponseWriter, r *http.Request, invalidMsg string) (*domain.UserClaims, uuid.UUID, bool) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, invalidMsg)
+		return nil, uuid.Nil, false
+	}
+	return userClaims, id, true
+}
@@ -0,0 +1,160 @@
+// api/internal/api/handlers/resource_quota.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+// SetLimitsRequest leaves a field null for "unlimited" (roles) or
+// "inherit the role" (overrides).
+type SetLimitsRequest struct {
+	MaxApplications *int `json:"max_applications" validate:"omitempty,min=0,max=100000"`
+	MaxDomains      *int `json:"max_domains" validate:"omitempty,min=0,max=100000"`
+	MaxMemoryMB     *int `json:"max_memory_mb" validate:"omitempty,min=0,max=16777216"`
+}
+
+type SetOverrideRequest struct {
+	SetLimitsRequest
+	Exempt bool   `json:"exempt"`
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+func (req SetLimitsRequest) limits() domain.ResourceLimits {
+	return domain.ResourceLimits{
+		MaxApplications: req.MaxApplications,
+		MaxDomains:      req.MaxDomains,
+		MaxMemoryMB:     req.MaxMemoryMB,
+	}
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type ResourceQuotaHandler struct {
+	Service domain.ResourceQuotaManager
+}
+
+func NewResourceQuotaHandler(service domain.ResourceQuotaManager) *ResourceQuotaHandler {
+	return &ResourceQuotaHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// Me handles GET /api/v1/quotas/me
+// The caller's effective limits next to what they currently own.
+func (h *ResourceQuotaHandler) Me(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	report, err := h.Service.GetReport(r.Context(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// ListRoles handles GET /api/v1/admin/quotas/roles
+func (h *ResourceQuotaHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	limits, err := h.Service.ListRoleLimits(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(limits)
+}
+
+// SetRole handles PUT /api/v1/admin/quotas/roles/{id}
+// Lowered limits only block new creations; nothing existing is removed.
+func (h *ResourceQuotaHandler) SetRole(w http.ResponseWriter, r *http.Request) {
+	_, roleID, ok := parseOwnedID(w, r, "Invalid role ID format")
+	if !ok {
+		return
+	}
+
+	var req SetLimitsRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	if err := h.Service.SetRoleLimits(r.Context(), roleID, req.limits()); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetUser handles GET /api/v1/admin/quotas/users/{id}
+func (h *ResourceQuotaHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	_, userID, ok := parseOwnedID(w, r, "Invalid user ID format")
+	if !ok {
+		return
+	}
+
+	report, err := h.Service.GetReport(r.Context(), userID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// SetOverride handles PUT /api/v1/admin/quotas/users/{id}/override
+// The reason is mandatory and lands on the tenant's audit chain.
+func (h *ResourceQuotaHandler) SetOverride(w http.ResponseWriter, r *http.Request) {
+	userClaims, userID, ok := parseOwnedID(w, r, "Invalid user ID format")
+	if !ok {
+		return
+	}
+
+	var req SetOverrideRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	report, err := h.Service.SetOverride(r.Context(), userClaims.Subject, userID, domain.LimitOverride{
+		ResourceLimits: req.limits(),
+		Exempt:         req.Exempt,
+		Reason:         req.Reason,
+	})
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// RemoveOverride handles DELETE /api/v1/admin/quotas/users/{id}/override
+func (h *ResourceQuotaHandler) RemoveOverride(w http.ResponseWriter, r *http.Request) {
+	userClaims, userID, ok := parseOwnedID(w, r, "Invalid user ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.Service.RemoveOverride(r.Context(), userClaims.Subject, userID); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,140 @@
+// api/internal/api/handlers/sbom.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type SBOMHandler struct {
+	Service domain.SBOMViewer
+}
+
+func NewSBOMHandler(service domain.SBOMViewer) *SBOMHandler {
+	return &SBOMHandler{Service: service}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// Get handles GET /api/v1/applications/{id}/sbom
+// The bill of materials of what the app currently runs, as CycloneDX 1.5 JSON
+// so it drops straight into existing supply-chain tooling.
+func (h *SBOMHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	sbom, err := h.Service.GetSBOM(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.cyclonedx+json")
+	json.NewEncoder(w).Encode(toCycloneDX(sbom))
+}
+
+// ListVulnerabilities handles GET /api/v1/applications/{id}/vulnerabilities
+// Open findings from the last scan, most severe first.
+func (h *SBOMHandler) ListVulnerabilities(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	findings, err := h.Service.ListVulnerabilities(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(findings)
+}
+
+// Licenses handles GET /api/v1/applications/{id}/licenses
+// License summary of the running build, flagging the operator's disallowed
+// licenses (DISALLOWED_LICENSES).
+func (h *SBOMHandler) Licenses(w http.ResponseWriter, r *http.Request) {
+	userClaims, appID, ok := parseOwnedID(w, r, "Invalid application ID format")
+	if !ok {
+		return
+	}
+
+	report, err := h.Service.LicenseReport(r.Context(), appID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// ==============================================================================
+// 3. CycloneDX Rendering
+// ==============================================================================
+
+type cdxLicense struct {
+	Expression string `json:"expression"`
+}
+
+type cdxComponent struct {
+	Type     string       `json:"type"`
+	Name     string       `json:"name"`
+	Version  string       `json:"version,omitempty"`
+	PURL     string       `json:"purl,omitempty"`
+	Licenses []cdxLicense `json:"licenses,omitempty"`
+}
+
+type cdxDocument struct {
+	BOMFormat    string `json:"bomFormat"`
+	SpecVersion  string `json:"specVersion"`
+	SerialNumber string `json:"serialNumber"`
+	Version      int    `json:"version"`
+	Metadata     struct {
+		Timestamp string `json:"timestamp"`
+		Tools     struct {
+			Components []cdxComponent `json:"components"`
+		} `json:"tools"`
+		Component  cdxComponent        `json:"component"`
+		Properties []map[string]string `json:"properties,omitempty"`
+	} `json:"metadata"`
+	Components []cdxComponent `json:"components"`
+}
+
+func toCycloneDX(s *domain.SBOM) *cdxDocument {
+	doc := &cdxDocument{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		// Stable per build, so re-downloads are recognizably the same BOM
+		SerialNumber: "urn:uuid:" + s.DeploymentID,
+		Version:      1,
+	}
+	doc.Metadata.Timestamp = s.CreatedAt.UTC().Format(time.RFC3339)
+	doc.Metadata.Tools.Components = []cdxComponent{{Type: "application", Name: s.Generator}}
+	doc.Metadata.Component = cdxComponent{Type: "application", Name: s.DomainName}
+	if s.Truncated {
+		doc.Metadata.Properties = []map[string]string{{"name": "kari:truncated", "value": "true"}}
+	}
+
+	doc.Components = make([]cdxComponent, 0, len(s.Components))
+	for _, c := range s.Components {
+		comp := cdxComponent{Type: "library", Name: c.Name, Version: c.Version, PURL: c.PURL}
+		for _, l := range c.Licenses {
+			comp.Licenses = append(comp.Licenses, cdxLicense{Expression: l})
+		}
+		doc.Components = append(doc.Components, comp)
+	}
+	return doc
+}
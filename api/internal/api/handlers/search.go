@@ -0,0 +1,110 @@
+// api/internal/api/handlers/search.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"kari/api/internal/api/middleware"
+	"kari/api/internal/core/domain"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	maxSearchQueryLen  = 200
+)
+
+// searchScopes maps each index to the JWT permission required to read it.
+var searchScopes = []struct {
+	kind       domain.SearchKind
+	permission string
+}{
+	{domain.SearchKindApplication, "applications:read"},
+	{domain.SearchKindDomain, "domains:read"},
+	{domain.SearchKindDeploymentLog, "applications:read"},
+	{domain.SearchKindAudit, "audit_logs:read"},
+}
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type SearchHandler struct {
+	Repo domain.SearchRepository
+}
+
+func NewSearchHandler(repo domain.SearchRepository) *SearchHandler {
+	return &SearchHandler{Repo: repo}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// Search handles GET /api/v1/search?q=&types=application,domain&limit=
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// 1. Validate the query text
+	text := strings.TrimSpace(r.URL.Query().Get("q"))
+	if utf8.RuneCountInString(text) < 2 || len(text) > maxSearchQueryLen {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Search query must be between 2 and 200 characters")
+		return
+	}
+
+	limit := defaultSearchLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid limit")
+			return
+		}
+		limit = min(n, maxSearchLimit)
+	}
+
+	// 2. 🛡️ Zero-Trust: Only query the indexes this identity may read,
+	// further narrowed by an optional ?types= filter.
+	requested := map[string]bool{}
+	if raw := r.URL.Query().Get("types"); raw != "" {
+		for _, t := range strings.Split(raw, ",") {
+			requested[strings.TrimSpace(t)] = true
+		}
+	}
+
+	var kinds []domain.SearchKind
+	for _, s := range searchScopes {
+		if len(requested) > 0 && !requested[string(s.kind)] {
+			continue
+		}
+		if middleware.HasPermission(userClaims.Permissions, s.permission) {
+			kinds = append(kinds, s.kind)
+		}
+	}
+
+	// 3. Execute
+	results, err := h.Repo.Search(r.Context(), domain.SearchQuery{
+		UserID:     userClaims.Subject,
+		Text:       text,
+		Kinds:      kinds,
+		AllTenants: middleware.HasPermission(userClaims.Permissions, "server:manage"),
+		Limit:      limit,
+	})
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"query":   text,
+		"results": results,
+	})
+}
@@ -0,0 +1,90 @@
+// api/internal/api/handlers/security_headers.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+// Vocabulary and character checks live in domain.ValidateSecurityHeaders.
+type UpdateSecurityHeadersRequest struct {
+	Preset            string `json:"preset" validate:"required,oneof=none basic strict"`
+	CSP               string `json:"content_security_policy" validate:"max=4096"`
+	FrameOptions      string `json:"x_frame_options" validate:"omitempty,oneof=DENY SAMEORIGIN"`
+	ReferrerPolicy    string `json:"referrer_policy" validate:"max=64"`
+	PermissionsPolicy string `json:"permissions_policy" validate:"max=4096"`
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type SecurityHeadersHandler struct {
+	Service domain.SecurityHeadersManager
+}
+
+func NewSecurityHeadersHandler(service domain.SecurityHeadersManager) *SecurityHeadersHandler {
+	return &SecurityHeadersHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// Presets handles GET /api/v1/domains/security-headers/presets
+func (h *SecurityHeadersHandler) Presets(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(domain.SecurityHeaderPresets)
+}
+
+// Get handles GET /api/v1/domains/{id}/security-headers
+func (h *SecurityHeadersHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userClaims, domainID, ok := parseOwnedID(w, r, "Invalid domain ID format")
+	if !ok {
+		return
+	}
+
+	headers, err := h.Service.GetHeaders(r.Context(), domainID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(headers)
+}
+
+// Update handles PUT /api/v1/domains/{id}/security-headers
+// The vhost is re-rendered and reloaded before the response is sent.
+func (h *SecurityHeadersHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userClaims, domainID, ok := parseOwnedID(w, r, "Invalid domain ID format")
+	if !ok {
+		return
+	}
+
+	var req UpdateSecurityHeadersRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	headers, err := h.Service.UpdateHeaders(r.Context(), domainID, userClaims.Subject, domain.SecurityHeaders{
+		Preset:            req.Preset,
+		CSP:               req.CSP,
+		FrameOptions:      req.FrameOptions,
+		ReferrerPolicy:    req.ReferrerPolicy,
+		PermissionsPolicy: req.PermissionsPolicy,
+	})
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(headers)
+}
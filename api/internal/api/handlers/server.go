@@ -0,0 +1,142 @@
+// api/internal/api/handlers/server.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type ServerHandler struct {
+	Service domain.ServerEnrollment
+}
+
+func NewServerHandler(service domain.ServerEnrollment) *ServerHandler {
+	return &ServerHandler{Service: service}
+}
+
+type enrollTokenRequest struct {
+	Name       string `json:"name" validate:"required,max=63"`
+	TTLMinutes int    `json:"ttl_minutes" validate:"omitempty,min=1,max=1440"`
+}
+
+type enrollRequest struct {
+	Token    string `json:"token" validate:"required,max=128"`
+	Hostname string `json:"hostname" validate:"required,max=253"`
+	CSR      string `json:"csr" validate:"required,max=16384"`
+}
+
+type sshServerRequest struct {
+	Name     string `json:"name" validate:"required,max=63"`
+	Hostname string `json:"hostname" validate:"required,max=253"`
+	Port     int    `json:"port" validate:"omitempty,min=1,max=65535"`
+	Username string `json:"username" validate:"required,max=32"`
+	HostKey  string `json:"host_key" validate:"required,max=8192"`
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// List handles GET /api/v1/servers
+func (h *ServerHandler) List(w http.ResponseWriter, r *http.Request) {
+	servers, err := h.Service.ListServers(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(servers)
+}
+
+// IssueEnrollToken handles POST /api/v1/servers/enroll-token
+// Returns the one-time join token once, with the CA hash the agent pins.
+func (h *ServerHandler) IssueEnrollToken(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req enrollTokenRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	token, err := h.Service.IssueEnrollToken(r.Context(), userClaims.Subject, req.Name, time.Duration(req.TTLMinutes)*time.Minute)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(token)
+}
+
+// Enroll handles POST /api/v1/servers/enroll
+// Public route: a joining agent has no identity yet; the token is the credential.
+func (h *ServerHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	var req enrollRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	enrollment, err := h.Service.Enroll(r.Context(), domain.EnrollRequest{
+		Token:    req.Token,
+		Hostname: req.Hostname,
+		CSR:      req.CSR,
+	})
+	if errors.Is(err, domain.ErrInvalidCredentials) {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeInvalidCredentials, "Enrollment token is invalid, expired or already used")
+		return
+	}
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(enrollment)
+}
+
+// RegisterSSH handles POST /api/v1/servers/ssh
+// Returns the Brain's public key once; it goes into the login's authorized_keys.
+func (h *ServerHandler) RegisterSSH(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req sshServerRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	server, err := h.Service.RegisterSSHServer(r.Context(), userClaims.Subject, domain.SSHServerRequest{
+		Name:     req.Name,
+		Hostname: req.Hostname,
+		Port:     req.Port,
+		Username: req.Username,
+		HostKey:  req.HostKey,
+	})
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(server)
+}
@@ -0,0 +1,162 @@
+// api/internal/api/handlers/settings.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+type SetMaintenanceRequest struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message" validate:"max=500"`
+}
+
+type SetTimezoneRequest struct {
+	Timezone string `json:"timezone" validate:"required,max=64"`
+}
+
+type SetPasskeyPolicyRequest struct {
+	RequireForRank0  bool `json:"require_for_rank0"`
+	PasswordFallback bool `json:"password_fallback"`
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type SettingsHandler struct {
+	Service domain.SettingsManager
+}
+
+func NewSettingsHandler(service domain.SettingsManager) *SettingsHandler {
+	return &SettingsHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// Maintenance handles GET /api/v1/maintenance
+// Public, so the login page can show the banner before anyone signs in.
+func (h *SettingsHandler) Maintenance(w http.ResponseWriter, r *http.Request) {
+	m := h.Service.Maintenance()
+	m.SetBy = nil // 🛡️ Privacy: Admin identities stay behind authentication
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m)
+}
+
+// Get handles GET /api/v1/admin/settings
+func (h *SettingsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.Service.GetSettings(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// SetMaintenance handles PUT /api/v1/admin/settings/maintenance
+// While enabled, non-admin POST/PUT/PATCH/DELETE requests get a 503.
+func (h *SettingsHandler) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req SetMaintenanceRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	m, err := h.Service.SetMaintenance(r.Context(), userClaims.Subject, req.Enabled, req.Message)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m)
+}
+
+// SetPasskeyPolicy handles PUT /api/v1/admin/settings/passkeys
+func (h *SettingsHandler) SetPasskeyPolicy(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req SetPasskeyPolicyRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	policy, err := h.Service.SetPasskeyPolicy(r.Context(), userClaims.Subject, domain.PasskeyPolicy{
+		RequireForRank0:  req.RequireForRank0,
+		PasswordFallback: req.PasswordFallback,
+	})
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy)
+}
+
+// SetPasswordPolicy handles PUT /api/v1/admin/settings/password-policy
+func (h *SettingsHandler) SetPasswordPolicy(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.PasswordPolicy
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	policy, err := h.Service.SetPasswordPolicy(r.Context(), userClaims.Subject, req)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy)
+}
+
+// SetTimezone handles PUT /api/v1/admin/settings/timezone
+// Schedules follow it from their next pass; users may still pick their own.
+func (h *SettingsHandler) SetTimezone(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req SetTimezoneRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	tz, err := h.Service.SetTimezone(r.Context(), userClaims.Subject, req.Timezone)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SetTimezoneRequest{Timezone: tz})
+}
@@ -15,6 +15,9 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/setup"
 	agent "kari/api/proto/kari/agent/v1"
 )
 
@@ -61,10 +64,17 @@ var bip39Words = []string{
 
 // SetupRequest is the finalize payload from the wizard UI.
 type SetupRequest struct {
-	AdminEmail    string `json:"admin_email"`
-	AdminPassword string `json:"admin_password"`
-	DatabaseURL   string `json:"database_url"`
-	AppDomain     string `json:"app_domain"`
+	AdminEmail    string `json:"admin_email" validate:"required,email,max=255"`
+	AdminPassword string `json:"admin_password" validate:"required,min=12,max=72"`
+	DatabaseURL   string `json:"database_url" validate:"required,pgurl,max=1024"`
+	AppDomain     string `json:"app_domain" validate:"required,fqdn,max=255"`
+	MasterKeyHex  string `json:"master_key_hex" validate:"required,len=64,hexadecimal"`
+	SecretsMode   string `json:"secrets_mode" validate:"omitempty,oneof=sealed plaintext"`
+}
+
+// TestDBRequest is the connectivity probe payload from the wizard UI.
+type TestDBRequest struct {
+	DatabaseURL string `json:"database_url" validate:"required,pgurl,max=1024"`
 }
 
 // SetupHandler manages the onboarding wizard lifecycle.
@@ -78,6 +88,10 @@ type SetupHandler struct {
 	mu          sync.RWMutex
 	locked      bool
 	shutdownFn  func() // Called to restart the Brain after lockdown
+
+	// Wizard progress per setup session, so a closed tab can resume (see setup_progress.go)
+	progressMu sync.Mutex
+	progress   map[string]*setupProgress
 }
 
 func NewSetupHandler(
@@ -95,6 +109,7 @@ func NewSetupHandler(
 		lockPath:    lockPath,
 		locked:      err == nil, // locked if file exists
 		shutdownFn:  shutdownFn,
+		progress:    map[string]*setupProgress{},
 	}
 }
 
@@ -118,7 +133,7 @@ func (h *SetupHandler) SetupGuard(next http.Handler) http.Handler {
 		if h.IsLocked() {
 			// System is configured — block setup endpoints
 			if strings.HasPrefix(path, "/api/v1/setup") || strings.HasPrefix(path, "/setup") {
-				http.Error(w, `{"message": "System is already configured"}`, http.StatusForbidden)
+				writeError(w, r, http.StatusForbidden, domain.CodeForbidden, "System is already configured")
 				return
 			}
 			next.ServeHTTP(w, r)
@@ -149,7 +164,7 @@ func (h *SetupHandler) SetupAuth(next http.Handler) http.Handler {
 			tokenStr = r.Header.Get("X-Setup-Token")
 		}
 		if tokenStr == "" {
-			http.Error(w, `{"message": "Missing setup token"}`, http.StatusUnauthorized)
+			writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Missing setup token")
 			return
 		}
 
@@ -163,22 +178,25 @@ func (h *SetupHandler) SetupAuth(next http.Handler) http.Handler {
 
 		if err != nil || !token.Valid {
 			h.logger.Warn("🛡️ Invalid setup token attempt", slog.Any("error", err))
-			http.Error(w, `{"message": "Invalid or expired setup token"}`, http.StatusUnauthorized)
+			writeError(w, r, http.StatusUnauthorized, domain.CodeSessionExpired, "Invalid or expired setup token")
 			return
 		}
 
 		// 🛡️ Verify this is a setup token (not a regular access token)
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
-			http.Error(w, `{"message": "Malformed token claims"}`, http.StatusUnauthorized)
+			writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Malformed token claims")
 			return
 		}
 		if claims["purpose"] != "kari-setup" {
-			http.Error(w, `{"message": "Token is not a setup token"}`, http.StatusForbidden)
+			writeError(w, r, http.StatusForbidden, domain.CodeForbidden, "Token is not a setup token")
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		// Refreshed tokens keep the session ID, so progress survives a refresh
+		sid, _ := claims["sid"].(string)
+		exp, _ := claims.GetExpirationTime()
+		next.ServeHTTP(w, r.WithContext(withSetupSession(r.Context(), sid, exp)))
 	})
 }
 
@@ -201,6 +219,7 @@ func (h *SetupHandler) TestMuscle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.recordProgress(r.Context(), func(p *setupProgress) { p.complete(SetupStepMuscle) })
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"healthy": true,
 		"version": status.AgentVersion,
@@ -211,23 +230,9 @@ func (h *SetupHandler) TestMuscle(w http.ResponseWriter, r *http.Request) {
 
 // TestDB verifies database connectivity.
 func (h *SetupHandler) TestDB(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		DatabaseURL string `json:"database_url"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]interface{}{
-			"healthy": false,
-			"error":   "Invalid request body",
-		})
-		return
-	}
-
-	// 🛡️ Zero-Trust: Validate the connection string format
-	if !strings.HasPrefix(req.DatabaseURL, "postgres://") && !strings.HasPrefix(req.DatabaseURL, "postgresql://") {
-		writeJSON(w, http.StatusBadRequest, map[string]interface{}{
-			"healthy": false,
-			"error":   "Database URL must start with postgres:// or postgresql://",
-		})
+	// 🛡️ Zero-Trust: Validate the connection string format (pgurl tag)
+	var req TestDBRequest
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
@@ -260,6 +265,10 @@ func (h *SetupHandler) TestDB(w http.ResponseWriter, r *http.Request) {
 	}
 	conn.Close()
 
+	h.recordProgress(r.Context(), func(p *setupProgress) {
+		p.DatabaseURL = req.DatabaseURL
+		p.complete(SetupStepDatabase)
+	})
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"healthy": true,
 		"host":    host,
@@ -273,13 +282,19 @@ func (h *SetupHandler) GenerateKey(w http.ResponseWriter, r *http.Request) {
 	keyBytes := make([]byte, 32)
 	if _, err := rand.Read(keyBytes); err != nil {
 		h.logger.Error("Setup: CSPRNG failure", "error", err)
-		http.Error(w, `{"message": "Cryptographic random generation failed"}`, http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, domain.CodeInternal, "Cryptographic random generation failed")
 		return
 	}
 
 	hexKey := hex.EncodeToString(keyBytes)
 	mnemonic := bytesToMnemonic(keyBytes)
 
+	// 🛡️ Only the fingerprint is kept: the key itself is shown once
+	h.recordProgress(r.Context(), func(p *setupProgress) {
+		p.KeyFingerprint = keyFingerprint(hexKey)
+		p.complete(SetupStepKey)
+	})
+
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"hex_key":         hexKey,
 		"recovery_phrase": mnemonic,
@@ -290,57 +305,51 @@ func (h *SetupHandler) GenerateKey(w http.ResponseWriter, r *http.Request) {
 
 // Finalize commits the production configuration and locks the system.
 func (h *SetupHandler) Finalize(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		AdminEmail    string `json:"admin_email"`
-		AdminPassword string `json:"admin_password"`
-		DatabaseURL   string `json:"database_url"`
-		AppDomain     string `json:"app_domain"`
-		MasterKeyHex  string `json:"master_key_hex"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	// 🛡️ Input validation: required fields, 12+ char password, 64-hex master key
+	var req SetupRequest
+	if !decodeAndValidate(w, r, &req) {
 		return
 	}
 
-	// 🛡️ Input validation
-	if req.AdminEmail == "" || req.AdminPassword == "" || req.DatabaseURL == "" || req.AppDomain == "" || req.MasterKeyHex == "" {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "All fields are required"})
-		return
+	// 🔐 Seal the secrets under this machine's key, so a copied env file or
+	// backup is useless elsewhere. Only the Muscle can unseal them at boot.
+	settings := setup.Settings{
+		DatabaseURL:  req.DatabaseURL,
+		JWTSecret:    generateRandomHex(32), // Fresh JWT secret
+		MasterKeyHex: req.MasterKeyHex,
+		AppDomain:    req.AppDomain,
+		AdminEmail:   req.AdminEmail,
 	}
-	if len(req.AdminPassword) < 12 {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Password must be at least 12 characters"})
-		return
-	}
-	if len(req.MasterKeyHex) != 64 {
-		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Master key must be exactly 64 hex characters (256 bits)"})
-		return
+	mode := setup.SecretsModePlaintext
+	if req.SecretsMode != setup.SecretsModePlaintext {
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		keyBinding, err := setup.Seal(ctx, h.agentClient, &settings)
+		cancel()
+		switch {
+		case err == nil:
+			mode = setup.SecretsModeSealed
+			h.logger.Info("🔐 Setup: Secrets sealed", slog.String("key_binding", keyBinding))
+		case req.SecretsMode == setup.SecretsModeSealed:
+			h.logger.Error("Setup: Failed to seal secrets", "error", err)
+			writeError(w, r, http.StatusServiceUnavailable, domain.CodeServiceUnavailable,
+				"This host cannot seal secrets (systemd-creds is required); choose plaintext mode instead")
+			return
+		default:
+			h.logger.Warn("Setup: Sealing unavailable, writing secrets in plaintext", "error", err)
+		}
 	}
 
-	// 🛡️ Write production .env (atomic)
-	envContent := fmt.Sprintf(
-		"DATABASE_URL=%s\nJWT_SECRET=%s\nENCRYPTION_KEY=%s\nAPP_DOMAIN=%s\nADMIN_EMAIL=%s\n",
-		req.DatabaseURL,
-		generateRandomHex(32), // Fresh JWT secret
-		req.MasterKeyHex,
-		req.AppDomain,
-		req.AdminEmail,
-	)
-
-	if err := os.WriteFile("/opt/kari/.env.production", []byte(envContent), 0600); err != nil {
+	// 🛡️ Write production .env
+	if err := setup.WriteEnvFile(setup.DefaultEnvFile, settings); err != nil {
 		h.logger.Error("Setup: Failed to write production env", "error", err)
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": "Failed to save configuration"})
+		writeError(w, r, http.StatusInternalServerError, domain.CodeInternal, "Failed to save configuration")
 		return
 	}
 
 	// 🛡️ Write setup.lock — this permanently locks the wizard
-	lockContent := fmt.Sprintf(`{"locked_at":"%s","admin_email":"%s","domain":"%s"}`,
-		time.Now().UTC().Format(time.RFC3339),
-		req.AdminEmail,
-		req.AppDomain,
-	)
-	if err := os.WriteFile(h.lockPath, []byte(lockContent), 0444); err != nil {
+	if err := setup.WriteLock(h.lockPath, req.AdminEmail, req.AppDomain); err != nil {
 		h.logger.Error("Setup: Failed to write setup.lock", "error", err)
-		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": "Failed to create lock file"})
+		writeError(w, r, http.StatusInternalServerError, domain.CodeInternal, "Failed to create lock file")
 		return
 	}
 
@@ -348,14 +357,16 @@ func (h *SetupHandler) Finalize(w http.ResponseWriter, r *http.Request) {
 	h.mu.Lock()
 	h.locked = true
 	h.mu.Unlock()
+	h.dropProgress()
 
 	h.logger.Info("🔒 Setup: System locked down. Restarting in Production Mode.",
 		slog.String("domain", req.AppDomain),
 		slog.String("admin", req.AdminEmail))
 
 	writeJSON(w, http.StatusOK, map[string]string{
-		"message": "Configuration saved. The panel will restart in Production Mode.",
-		"status":  "locked",
+		"message":      "Configuration saved. The panel will restart in Production Mode.",
+		"status":       "locked",
+		"secrets_mode": mode,
 	})
 
 	// 🛡️ Trigger graceful restart after response is sent
@@ -422,14 +433,25 @@ func generateRandomHex(n int) string {
 }
 
 // GenerateSetupToken creates a transient 15-minute JWT for setup wizard access.
-// Called from the CLI bootstrap or the main.go boot sequence.
+// Called from the CLI bootstrap or the main.go boot sequence. Each call
+// starts a new setup session.
 func GenerateSetupToken(secret string) (string, error) {
+	token, _, err := signSetupToken([]byte(secret), generateRandomHex(16))
+	return token, err
+}
+
+// signSetupToken issues a token for session sid, valid for setupTokenTTL.
+func signSetupToken(secret []byte, sid string) (string, time.Time, error) {
+	now := time.Now()
+	exp := now.Add(setupTokenTTL)
 	claims := jwt.MapClaims{
 		"purpose": "kari-setup",
 		"iss":     "kari-brain",
-		"exp":     time.Now().Add(15 * time.Minute).Unix(),
-		"iat":     time.Now().Unix(),
+		"sid":     sid,
+		"exp":     exp.Unix(),
+		"iat":     now.Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	signed, err := token.SignedString(secret)
+	return signed, exp, err
 }
\ No newline at end of file
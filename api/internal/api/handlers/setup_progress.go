@@ -0,0 +1,233 @@
+// api/internal/api/handlers/setup_progress.go
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"kari/api/internal/core/domain"
+)
+
+// setupTokenTTL bounds each setup token; the wizard refreshes before it runs out.
+const setupTokenTTL = 15 * time.Minute
+
+// Wizard checks the Brain has seen succeed for a session.
+const (
+	SetupStepMuscle   = "muscle"
+	SetupStepDatabase = "database"
+	SetupStepKey      = "key"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+// SaveSetupProgressRequest carries what only the browser knows: which screen
+// it is on and the non-secret form fields. Passwords are never sent here.
+type SaveSetupProgressRequest struct {
+	Step       int    `json:"step" validate:"required,min=1,max=4"`
+	AdminEmail string `json:"admin_email" validate:"omitempty,max=255"`
+	AppDomain  string `json:"app_domain" validate:"omitempty,max=255"`
+}
+
+// setupProgress is one setup session's progress. It is held in memory only:
+// a restarted Brain prints a new setup token and the wizard starts over.
+type setupProgress struct {
+	Step           int       `json:"step"`
+	CompletedSteps []string  `json:"completed_steps"`
+	DatabaseURL    string    `json:"database_url,omitempty"`    // Last URL that passed the probe
+	KeyFingerprint string    `json:"key_fingerprint,omitempty"` // The key itself was shown once and is gone
+	AdminEmail     string    `json:"admin_email,omitempty"`
+	AppDomain      string    `json:"app_domain,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	expiresAt time.Time // Latest token expiry of the session
+}
+
+type setupProgressResponse struct {
+	setupProgress
+	ResumeStep int       `json:"resume_step"` // Furthest screen the completed checks allow
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func (p *setupProgress) complete(step string) {
+	if !slices.Contains(p.CompletedSteps, step) {
+		p.CompletedSteps = append(p.CompletedSteps, step)
+	}
+}
+
+// resumeStep never skips a check: the database screen needs a reachable
+// Muscle, security a probed database, and the review a generated key.
+func (p *setupProgress) resumeStep() int {
+	allowed := 1
+	for i, step := range []string{SetupStepMuscle, SetupStepDatabase, SetupStepKey} {
+		if !slices.Contains(p.CompletedSteps, step) {
+			break
+		}
+		allowed = i + 2
+	}
+	return max(1, min(p.Step, allowed))
+}
+
+// ==============================================================================
+// 2. Session Tracking
+// ==============================================================================
+
+type setupSessionKey struct{}
+
+type setupSession struct {
+	id        string
+	expiresAt time.Time
+}
+
+func withSetupSession(ctx context.Context, sid string, exp *jwt.NumericDate) context.Context {
+	session := setupSession{id: sid}
+	if exp != nil {
+		session.expiresAt = exp.Time
+	}
+	return context.WithValue(ctx, setupSessionKey{}, session)
+}
+
+func setupSessionFrom(ctx context.Context) (setupSession, bool) {
+	session, ok := ctx.Value(setupSessionKey{}).(setupSession)
+	return session, ok && session.id != ""
+}
+
+// recordProgress updates the caller's session; tokens without a session ID
+// (issued before sessions existed) simply are not resumable.
+func (h *SetupHandler) recordProgress(ctx context.Context, update func(p *setupProgress)) {
+	session, ok := setupSessionFrom(ctx)
+	if !ok {
+		return
+	}
+
+	h.progressMu.Lock()
+	defer h.progressMu.Unlock()
+	h.pruneProgressLocked()
+
+	p, exists := h.progress[session.id]
+	if !exists {
+		p = &setupProgress{Step: 1, CompletedSteps: []string{}}
+		h.progress[session.id] = p
+	}
+	update(p)
+	p.UpdatedAt = time.Now().UTC()
+	if session.expiresAt.After(p.expiresAt) {
+		p.expiresAt = session.expiresAt
+	}
+}
+
+func (h *SetupHandler) pruneProgressLocked() {
+	now := time.Now()
+	for sid, p := range h.progress {
+		if now.After(p.expiresAt) {
+			delete(h.progress, sid)
+		}
+	}
+}
+
+// dropProgress forgets every session once the system is locked down.
+func (h *SetupHandler) dropProgress() {
+	h.progressMu.Lock()
+	h.progress = map[string]*setupProgress{}
+	h.progressMu.Unlock()
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// GetProgress handles GET /api/v1/setup/progress
+// A reopened tab resumes from here while the session's token is valid.
+func (h *SetupHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
+	session, ok := setupSessionFrom(r.Context())
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Setup token carries no session; restart the Brain for a new one")
+		return
+	}
+
+	resp := setupProgressResponse{
+		setupProgress: setupProgress{Step: 1, CompletedSteps: []string{}},
+		ExpiresAt:     session.expiresAt,
+	}
+	h.progressMu.Lock()
+	h.pruneProgressLocked()
+	if p, exists := h.progress[session.id]; exists {
+		resp.setupProgress = *p
+		resp.CompletedSteps = slices.Clone(p.CompletedSteps)
+		resp.ExpiresAt = p.expiresAt
+	}
+	h.progressMu.Unlock()
+	resp.ResumeStep = resp.resumeStep()
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// SaveProgress handles PUT /api/v1/setup/progress
+func (h *SetupHandler) SaveProgress(w http.ResponseWriter, r *http.Request) {
+	if _, ok := setupSessionFrom(r.Context()); !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Setup token carries no session; restart the Brain for a new one")
+		return
+	}
+
+	var req SaveSetupProgressRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	h.recordProgress(r.Context(), func(p *setupProgress) {
+		p.Step = req.Step
+		p.AdminEmail = req.AdminEmail
+		p.AppDomain = req.AppDomain
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RefreshToken handles POST /api/v1/setup/refresh
+// Issues a fresh 15-minute token for the same session, so a slow operator
+// is not locked out halfway. Only a still-valid token can be refreshed.
+func (h *SetupHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	session, ok := setupSessionFrom(r.Context())
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Setup token carries no session; restart the Brain for a new one")
+		return
+	}
+
+	token, exp, err := signSetupToken(h.jwtSecret, session.id)
+	if err != nil {
+		h.logger.Error("Setup: Failed to refresh token", "error", err)
+		writeError(w, r, http.StatusInternalServerError, domain.CodeInternal, "Failed to refresh setup token")
+		return
+	}
+
+	h.progressMu.Lock()
+	if p, exists := h.progress[session.id]; exists && exp.After(p.expiresAt) {
+		p.expiresAt = exp
+	}
+	h.progressMu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"token":      token,
+		"expires_at": exp.UTC(),
+	})
+}
+
+// ==============================================================================
+// 4. Helpers
+// ==============================================================================
+
+// keyFingerprint identifies a master key without revealing it.
+func keyFingerprint(hexKey string) string {
+	raw, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return "SHA256:" + hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,156 @@
+// api/internal/api/handlers/site.go
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request Payloads (Input Validation)
+// ==============================================================================
+
+// CreateSiteRequest combines POST /domains and POST /applications; the app
+// fields carry the same rules as CreateAppRequest.
+type CreateSiteRequest struct {
+	DomainName     string                        `json:"domain_name" validate:"required,fqdn,max=255"`
+	DocumentRoot   string                        `json:"document_root" validate:"omitempty,max=512"`
+	SSLEmail       string                        `json:"ssl_email" validate:"omitempty,email,max=255"` // Empty skips the certificate
+	AppType        string                        `json:"app_type" validate:"required,oneof=nodejs python go php ruby static image"`
+	RuntimeVersion string                        `json:"runtime_version" validate:"omitempty,max=20"`
+	ImageRef       string                        `json:"image_ref" validate:"required_if=AppType image,max=512"`
+	RepoURL        string                        `json:"repo_url" validate:"required_unless=AppType image,omitempty,url"`
+	Branch         string                        `json:"branch" validate:"required_unless=AppType image,max=100"`
+	BuildCommand   string                        `json:"build_command" validate:"required_unless=AppType image,max=255"`
+	StartCommand   string                        `json:"start_command" validate:"required_unless=AppType image,max=255"`
+	EnvVars        map[string]string             `json:"env_vars" validate:"max=50,dive,keys,envkey,endkeys,max=8192"`
+	Processes      map[string]domain.ProcessSpec `json:"processes" validate:"max=10"`
+	Instances      int                           `json:"instances" validate:"min=0,max=16"`
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type SiteHandler struct {
+	Service domain.SiteManager
+	Quotas  domain.QuotaChecker
+}
+
+func NewSiteHandler(service domain.SiteManager, quotas domain.QuotaChecker) *SiteHandler {
+	return &SiteHandler{Service: service, Quotas: quotas}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// Create handles POST /api/v1/sites
+// Answers 202 with the operation; steps run in the background and roll back
+// in reverse order if one fails.
+func (h *SiteHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req CreateSiteRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+	if req.DocumentRoot == "" {
+		req.DocumentRoot = "public"
+	}
+
+	// 📏 Quotas: An early answer for the async operation only; the domain
+	// INSERT is what enforces the limit, and the app's own quota is
+	// enforced by the application step
+	if err := h.Quotas.CheckQuota(r.Context(), userClaims.Subject, domain.ResourceUsage{Domains: 1}); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	op, err := h.Service.CreateSite(r.Context(), userClaims.Subject, domain.SiteRequest{
+		DomainName:   req.DomainName,
+		DocumentRoot: req.DocumentRoot,
+		SSLEmail:     req.SSLEmail,
+		App: domain.Application{
+			AppType:        req.AppType,
+			RuntimeVersion: req.RuntimeVersion,
+			ImageRef:       req.ImageRef,
+			RepoURL:        req.RepoURL,
+			Branch:         req.Branch,
+			BuildCommand:   req.BuildCommand,
+			StartCommand:   req.StartCommand,
+			EnvVars:        req.EnvVars,
+			Processes:      req.Processes,
+			Instances:      req.Instances,
+		},
+	})
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/sites/operations/%s", op.ID))
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(op)
+}
+
+// GetOperation handles GET /api/v1/sites/operations/{id}
+func (h *SiteHandler) GetOperation(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid operation ID format")
+	if !ok {
+		return
+	}
+
+	op, err := h.Service.GetOperation(r.Context(), userClaims.Subject, id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(op)
+}
+
+// StreamOperation handles GET /api/v1/sites/operations/{id}/events
+// One SSE "progress" event per state change, starting with the current one;
+// the stream ends after the final state.
+func (h *SiteHandler) StreamOperation(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid operation ID format")
+	if !ok {
+		return
+	}
+
+	updates, err := h.Service.Watch(r.Context(), userClaims.Subject, id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	rc := http.NewResponseController(w)
+	// Site creation may outlast the server's write timeout (ACME especially)
+	rc.SetWriteDeadline(time.Time{})
+
+	for op := range updates {
+		frame, err := json.Marshal(op)
+		if err != nil {
+			continue
+		}
+		fmt.Fprint(w, "event: progress\n")
+		writeSSEData(w, string(frame))
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// api/internal/api/handlers/stack_registry.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+type addStackVersionRequest struct {
+	Runtime string `json:"runtime" validate:"required,max=20"`
+	Version string `json:"version" validate:"required,max=20"`
+}
+
+type setStackVersionStatusRequest struct {
+	Status domain.StackVersionStatus `json:"status" validate:"required,oneof=available deprecated"`
+}
+
+type migrateStackRequest struct {
+	From string `json:"from" validate:"required,max=20"`
+	To   string `json:"to" validate:"required,max=20"`
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type StackRegistryHandler struct {
+	Service domain.StackRegistryManager
+}
+
+func NewStackRegistryHandler(service domain.StackRegistryManager) *StackRegistryHandler {
+	return &StackRegistryHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// List handles GET /api/v1/system/stacks
+// Each version carries its lifecycle status and how many apps build with it.
+func (h *StackRegistryHandler) List(w http.ResponseWriter, r *http.Request) {
+	catalogue, err := h.Service.ListStackVersions(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(catalogue)
+}
+
+// Add handles POST /api/v1/system/stacks
+func (h *StackRegistryHandler) Add(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req addStackVersionRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	v, err := h.Service.AddStackVersion(r.Context(), userClaims.Subject, req.Runtime, req.Version)
+	if err != nil {
+		h.handleStackError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(v)
+}
+
+// SetStatus handles PUT /api/v1/system/stacks/{runtime}/{version}
+// Deprecating keeps existing pins working and refuses new ones.
+func (h *StackRegistryHandler) SetStatus(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req setStackVersionStatusRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	v, err := h.Service.SetStackVersionStatus(r.Context(), userClaims.Subject,
+		chi.URLParam(r, "runtime"), chi.URLParam(r, "version"), req.Status)
+	if err != nil {
+		h.handleStackError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// Remove handles DELETE /api/v1/system/stacks/{runtime}/{version}
+// Refused while any app is still pinned to the version.
+func (h *StackRegistryHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.Service.RemoveStackVersion(r.Context(), userClaims.Subject,
+		chi.URLParam(r, "runtime"), chi.URLParam(r, "version")); err != nil {
+		h.handleStackError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Apps handles GET /api/v1/system/stacks/{runtime}/{version}/apps
+func (h *StackRegistryHandler) Apps(w http.ResponseWriter, r *http.Request) {
+	apps, err := h.Service.StackVersionApps(r.Context(), chi.URLParam(r, "runtime"), chi.URLParam(r, "version"))
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(apps)
+}
+
+// Migrate handles POST /api/v1/system/stacks/{runtime}/migrate
+// Pins every app on "from" to "to" and queues a redeploy of each.
+func (h *StackRegistryHandler) Migrate(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req migrateStackRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	migration, err := h.Service.MigrateApps(r.Context(), userClaims.Subject, chi.URLParam(r, "runtime"), req.From, req.To)
+	if err != nil {
+		h.handleStackError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(migration)
+}
+
+// ==============================================================================
+// 4. Helpers
+// ==============================================================================
+
+// handleStackError shows which lifecycle rule was hit (e.g. deprecating the default).
+func (h *StackRegistryHandler) handleStackError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		writeError(w, r, http.StatusBadRequest, domain.CodeValidationFailed, err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		writeError(w, r, http.StatusConflict, domain.CodeConflict, err.Error())
+	default:
+		HandleError(w, r, err)
+	}
+}
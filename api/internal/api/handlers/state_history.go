@@ -0,0 +1,61 @@
+// api/internal/api/handlers/state_history.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type StateHistoryHandler struct {
+	Service domain.StateHistoryReader
+}
+
+func NewStateHistoryHandler(service domain.StateHistoryReader) *StateHistoryHandler {
+	return &StateHistoryHandler{Service: service}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// List handles GET /api/v1/admin/state-changes?resource_type=&resource_id=&status=&limit=
+// Newest first. Payloads never leave the outbox; only descriptions do.
+func (h *StateHistoryHandler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := domain.StateChangeFilter{
+		ResourceType: q.Get("resource_type"),
+		ResourceID:   q.Get("resource_id"),
+		Status:       domain.StateChangeStatus(q.Get("status")),
+		Limit:        domain.DefaultPageLimit,
+	}
+	switch filter.Status {
+	case "", domain.ChangePending, domain.ChangeApplied, domain.ChangeFailed, domain.ChangeRecorded:
+	default:
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid status; supported: pending, applied, failed, recorded")
+		return
+	}
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "limit must be a positive integer")
+			return
+		}
+		filter.Limit = min(n, domain.MaxPageLimit)
+	}
+
+	changes, err := h.Service.History(r.Context(), filter)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changes)
+}
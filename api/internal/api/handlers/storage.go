@@ -0,0 +1,34 @@
+// api/internal/api/handlers/storage.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// StorageHandler reports database growth so admins can tune retention.
+type StorageHandler struct {
+	Repo     domain.RetentionRepository
+	Policies []domain.RetentionPolicy
+}
+
+func NewStorageHandler(repo domain.RetentionRepository, policies []domain.RetentionPolicy) *StorageHandler {
+	return &StorageHandler{Repo: repo, Policies: policies}
+}
+
+// HandleGetTableSizes handles GET /api/v1/admin/storage
+func (h *StorageHandler) HandleGetTableSizes(w http.ResponseWriter, r *http.Request) {
+	sizes, err := h.Repo.TableSizes(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"tables":    sizes,
+		"retention": h.Policies,
+	})
+}
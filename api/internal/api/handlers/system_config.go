@@ -0,0 +1,38 @@
+// api/internal/api/handlers/system_config.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type ConfigHandler struct {
+	Inspector domain.ConfigInspector
+}
+
+func NewConfigHandler(inspector domain.ConfigInspector) *ConfigHandler {
+	return &ConfigHandler{Inspector: inspector}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// Get handles GET /api/v1/system/config
+// Answers this replica only: another replica may have booted before an edit.
+func (h *ConfigHandler) Get(w http.ResponseWriter, r *http.Request) {
+	report, err := h.Inspector.ConfigReport()
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
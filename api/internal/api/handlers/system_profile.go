@@ -0,0 +1,104 @@
+// api/internal/api/handlers/system_profile.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+// UpdateSystemProfileRequest is a partial update: omitted fields keep their
+// current value. Version is the one the admin last read.
+type UpdateSystemProfileRequest struct {
+	DefaultStackRegistry  map[string]string `json:"stack_defaults"`
+	SSLStrategy           *string           `json:"ssl_strategy"`
+	MaxMemoryPerAppMB     *int              `json:"max_memory_per_app_mb"`
+	MaxCPUPercentPerApp   *int              `json:"max_cpu_percent_per_app"`
+	DefaultFirewallPolicy *string           `json:"default_firewall_policy"`
+	AppUserUIDRangeStart  *int              `json:"app_user_uid_range_start"`
+	AppUserUIDRangeEnd    *int              `json:"app_user_uid_range_end"`
+	BackupRetentionDays   *int              `json:"backup_retention_days" validate:"omitempty,max=3650"`
+	Version               int               `json:"version" validate:"required,min=1"`
+}
+
+type systemProfileResponse struct {
+	*domain.SystemProfile
+	Rollouts []domain.ProfileRollout `json:"rollouts"`
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type SystemProfileHandler struct {
+	Service domain.SystemProfileManager
+}
+
+func NewSystemProfileHandler(service domain.SystemProfileManager) *SystemProfileHandler {
+	return &SystemProfileHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// Get handles GET /api/v1/system/profile
+// Rollouts show which profile version each agent has applied.
+func (h *SystemProfileHandler) Get(w http.ResponseWriter, r *http.Request) {
+	profile, rollouts, err := h.Service.Get(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	if rollouts == nil {
+		rollouts = []domain.ProfileRollout{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(systemProfileResponse{SystemProfile: profile, Rollouts: rollouts})
+}
+
+// Update handles PUT /api/v1/system/profile
+// A stale version is a 409; agents receive the new limits asynchronously.
+func (h *SystemProfileHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req UpdateSystemProfileRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	profile, err := h.Service.Update(r.Context(), userClaims.Subject, domain.SystemProfilePatch{
+		DefaultStackRegistry:  req.DefaultStackRegistry,
+		SSLStrategy:           req.SSLStrategy,
+		MaxMemoryPerAppMB:     req.MaxMemoryPerAppMB,
+		MaxCPUPercentPerApp:   req.MaxCPUPercentPerApp,
+		DefaultFirewallPolicy: req.DefaultFirewallPolicy,
+		AppUserUIDRangeStart:  req.AppUserUIDRangeStart,
+		AppUserUIDRangeEnd:    req.AppUserUIDRangeEnd,
+		BackupRetentionDays:   req.BackupRetentionDays,
+		Version:               req.Version,
+	})
+	if err != nil {
+		// The frontend shows the validation detail next to the form
+		if errors.Is(err, domain.ErrValidation) {
+			writeError(w, r, http.StatusBadRequest, domain.CodeValidationFailed, err.Error())
+			return
+		}
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
@@ -0,0 +1,58 @@
+// api/internal/api/handlers/system_update.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type SystemUpdateHandler struct {
+	Service domain.SystemUpdater
+}
+
+func NewSystemUpdateHandler(service domain.SystemUpdater) *SystemUpdateHandler {
+	return &SystemUpdateHandler{Service: service}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// Check handles GET /api/v1/system/update
+func (h *SystemUpdateHandler) Check(w http.ResponseWriter, r *http.Request) {
+	status, err := h.Service.Check(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// Apply handles POST /api/v1/system/update
+// 202: the Muscle restarts the Brain after this response is flushed, so clients
+// should poll /ping and then GET /system/update to confirm current_version.
+func (h *SystemUpdateHandler) Apply(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	status, err := h.Service.Apply(r.Context(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(status)
+}
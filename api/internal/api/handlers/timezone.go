@@ -0,0 +1,75 @@
+// api/internal/api/handlers/timezone.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+// TimezoneRequest sets an IANA zone; "" follows the install's zone again.
+type TimezoneRequest struct {
+	Timezone string `json:"timezone" validate:"max=64"`
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type TimezoneHandler struct {
+	Service domain.TimezoneManager
+}
+
+func NewTimezoneHandler(service domain.TimezoneManager) *TimezoneHandler {
+	return &TimezoneHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// GetPreference handles GET /api/v1/preferences/timezone
+func (h *TimezoneHandler) GetPreference(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	pref, err := h.Service.GetPreference(r.Context(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pref)
+}
+
+// SetPreference handles PUT /api/v1/preferences/timezone
+func (h *TimezoneHandler) SetPreference(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req TimezoneRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	pref, err := h.Service.SetPreference(r.Context(), userClaims.Subject, req.Timezone)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pref)
+}
@@ -0,0 +1,72 @@
+// api/internal/api/handlers/usage.go
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type UsageHandler struct {
+	Service domain.UsageReporter
+}
+
+func NewUsageHandler(service domain.UsageReporter) *UsageHandler {
+	return &UsageHandler{Service: service}
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// Export handles GET /api/v1/admin/usage?month=2025-01&format=csv
+// Per-app CPU-hours, RAM GB-hours, storage GB-hours and bandwidth for billing.
+func (h *UsageHandler) Export(w http.ResponseWriter, r *http.Request) {
+	month := r.URL.Query().Get("month")
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "format must be json or csv")
+		return
+	}
+	records, err := h.Service.Report(r.Context(), month)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	switch format {
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(records)
+	case "csv":
+		if month == "" {
+			month = "current"
+		}
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="kari-usage-%s.csv"`, month))
+
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"month", "tenant_id", "app_id", "domain_name", "cpu_hours", "ram_gb_hours", "storage_gb_hours", "bandwidth_bytes"})
+		for _, rec := range records {
+			cw.Write([]string{
+				rec.Month,
+				rec.TenantID.String(),
+				rec.AppID.String(),
+				rec.DomainName,
+				strconv.FormatFloat(rec.CPUHours, 'f', 4, 64),
+				strconv.FormatFloat(rec.RAMGBHours, 'f', 4, 64),
+				strconv.FormatFloat(rec.StorageGBHours, 'f', 4, 64),
+				strconv.FormatInt(rec.BandwidthBytes, 10),
+			})
+		}
+		cw.Flush()
+	}
+}
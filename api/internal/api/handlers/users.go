@@ -0,0 +1,211 @@
+// api/internal/api/handlers/users.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Request DTOs
+// ==============================================================================
+
+// CreateUserRequest sets an initial password; length and breach rules come
+// from the admin-configured password policy.
+type CreateUserRequest struct {
+	Email              string    `json:"email" validate:"required,email,max=255"`
+	Password           string    `json:"password" validate:"required,max=72"`
+	RoleID             uuid.UUID `json:"role_id" validate:"required"`
+	MustChangePassword *bool     `json:"must_change_password"` // Defaults to true
+}
+
+// UpdateUserRequest is a partial edit; omitted fields are left unchanged.
+type UpdateUserRequest struct {
+	Email              *string    `json:"email" validate:"omitempty,email,max=255"`
+	RoleID             *uuid.UUID `json:"role_id"`
+	IsActive           *bool      `json:"is_active"`
+	MustChangePassword *bool      `json:"must_change_password"`
+}
+
+// ==============================================================================
+// 2. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type UserHandler struct {
+	Service domain.UserAdminService
+}
+
+func NewUserHandler(service domain.UserAdminService) *UserHandler {
+	return &UserHandler{Service: service}
+}
+
+// ==============================================================================
+// 3. HTTP Methods
+// ==============================================================================
+
+// List handles GET /api/v1/users?q=&role_id=&active=
+func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, ok := parsePageRequest(r)
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid limit or cursor")
+		return
+	}
+	roleID, ok := parseUUIDParam(r, "role_id")
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid role_id")
+		return
+	}
+	filter := domain.UserFilter{Query: r.URL.Query().Get("q"), RoleID: roleID}
+	if raw := r.URL.Query().Get("active"); raw != "" {
+		active, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid active filter")
+			return
+		}
+		filter.Active = &active
+	}
+
+	result, err := h.Service.ListUsers(r.Context(), filter, page)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	writePageHeaders(w, r, result.Total, result.NextCursor)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result.Items)
+}
+
+// Get handles GET /api/v1/users/{id}
+func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid user ID format")
+		return
+	}
+
+	user, err := h.Service.GetUser(r.Context(), id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// Create handles POST /api/v1/users
+func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req CreateUserRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	mustChange := true
+	if req.MustChangePassword != nil {
+		mustChange = *req.MustChangePassword
+	}
+
+	user, err := h.Service.CreateUser(r.Context(), userClaims.Subject, domain.NewUser{
+		Email:              req.Email,
+		Password:           req.Password,
+		RoleID:             req.RoleID,
+		MustChangePassword: mustChange,
+	})
+	if err != nil {
+		h.handleUserError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+// Update handles PATCH /api/v1/users/{id}
+func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid user ID format")
+		return
+	}
+
+	var req UpdateUserRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	user, err := h.Service.UpdateUser(r.Context(), userClaims.Subject, id, domain.UserUpdate{
+		Email:              req.Email,
+		RoleID:             req.RoleID,
+		IsActive:           req.IsActive,
+		MustChangePassword: req.MustChangePassword,
+	})
+	if err != nil {
+		h.handleUserError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// Deactivate handles DELETE /api/v1/users/{id}
+// Accounts are deactivated, never deleted, so their apps and audit trail
+// keep a valid owner. Sessions are revoked immediately.
+func (h *UserHandler) Deactivate(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid user ID format")
+		return
+	}
+
+	if err := h.Service.DeactivateUser(r.Context(), userClaims.Subject, id); err != nil {
+		h.handleUserError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ==============================================================================
+// 4. Helpers
+// ==============================================================================
+
+// handleUserError shows which password rule or rank boundary was hit.
+func (h *UserHandler) handleUserError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		writeError(w, r, http.StatusBadRequest, domain.CodeValidationFailed, err.Error())
+	case errors.Is(err, domain.ErrForbidden):
+		writeError(w, r, http.StatusForbidden, domain.CodeForbidden, err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		writeError(w, r, http.StatusConflict, domain.CodeConflict, "A user with this email already exists")
+	default:
+		HandleError(w, r, err)
+	}
+}
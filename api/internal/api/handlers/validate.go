@@ -0,0 +1,92 @@
+// api/internal/api/handlers/validate.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Shared Validator Instance
+// ==============================================================================
+
+// Use a single instance of Validate, it caches struct info
+var validate = newValidator()
+
+// envVarKeyRegex mirrors the Muscle's accepted environment key shape (UPPER_SNAKE_CASE).
+var envVarKeyRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,127}$`)
+
+// appUserRegex restricts Linux jail identities to a safe, useradd-compatible subset.
+var appUserRegex = regexp.MustCompile(`^[a-z_][a-z0-9_-]{0,31}$`)
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// 🛡️ SLA: Report JSON field names (domain_name) instead of Go names (DomainName)
+	// so the Svelte forms can bind field errors without a translation table.
+	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return fld.Name
+		}
+		return name
+	})
+
+	// 🛡️ Zero-Trust: Domain-specific rules shared by every request struct
+	_ = v.RegisterValidation("envkey", func(fl validator.FieldLevel) bool {
+		return envVarKeyRegex.MatchString(fl.Field().String())
+	})
+	_ = v.RegisterValidation("appuser", func(fl validator.FieldLevel) bool {
+		return appUserRegex.MatchString(fl.Field().String())
+	})
+	_ = v.RegisterValidation("pgurl", func(fl validator.FieldLevel) bool {
+		s := fl.Field().String()
+		return strings.HasPrefix(s, "postgres://") || strings.HasPrefix(s, "postgresql://")
+	})
+
+	return v
+}
+
+// ==============================================================================
+// 2. Decode + Validate Helper
+// ==============================================================================
+
+// decodeAndValidate reads a JSON body into dst and runs struct validation.
+// On failure it writes the standard error envelope (with per-field details)
+// and returns false; handlers simply `return`.
+func decodeAndValidate(w http.ResponseWriter, r *http.Request, dst any) bool {
+	dec := json.NewDecoder(r.Body)
+	// 🛡️ Zero-Trust: Unknown fields are a client bug or a probing attempt
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			HandleError(w, r, err)
+		case errors.Is(err, io.EOF):
+			writeError(w, r, http.StatusBadRequest, domain.CodeInvalidJSON, "Request body is empty")
+		default:
+			writeError(w, r, http.StatusBadRequest, domain.CodeInvalidJSON, "Invalid JSON payload")
+		}
+		return false
+	}
+
+	if err := validate.Struct(dst); err != nil {
+		HandleError(w, r, err)
+		return false
+	}
+	return true
+}
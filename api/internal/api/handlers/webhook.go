@@ -0,0 +1,189 @@
+// api/internal/api/handlers/webhook.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. The Handler Struct (Dependency Injection)
+// ==============================================================================
+
+type WebhookHandler struct {
+	Service domain.WebhookManager
+}
+
+func NewWebhookHandler(service domain.WebhookManager) *WebhookHandler {
+	return &WebhookHandler{Service: service}
+}
+
+type createWebhookRequest struct {
+	URL    string   `json:"url" validate:"required,url,max=2048"`
+	Events []string `json:"events" validate:"required,min=1,dive,required"`
+	Secret string   `json:"secret" validate:"omitempty,min=16,max=256"` // Generated when omitted
+}
+
+type updateWebhookRequest struct {
+	URL     string   `json:"url" validate:"required,url,max=2048"`
+	Events  []string `json:"events" validate:"required,min=1,dive,required"`
+	Enabled bool     `json:"enabled"`
+}
+
+// ==============================================================================
+// 2. HTTP Methods
+// ==============================================================================
+
+// List handles GET /api/v1/outgoing-webhooks
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	hooks, err := h.Service.ListWebhooks(r.Context(), userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hooks)
+}
+
+// Create handles POST /api/v1/outgoing-webhooks
+// The response is the only time the signing secret is returned.
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req createWebhookRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	hook, err := h.Service.CreateWebhook(r.Context(), userClaims.Subject, &domain.OutgoingWebhook{
+		URL:    req.URL,
+		Events: req.Events,
+		Secret: req.Secret,
+	})
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hook)
+}
+
+// Update handles PUT /api/v1/outgoing-webhooks/{id}
+// The secret cannot be changed; delete and recreate the webhook to rotate it.
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid webhook ID format")
+	if !ok {
+		return
+	}
+
+	var req updateWebhookRequest
+	if !decodeAndValidate(w, r, &req) {
+		return
+	}
+
+	hook, err := h.Service.UpdateWebhook(r.Context(), userClaims.Subject, &domain.OutgoingWebhook{
+		ID:      id,
+		URL:     req.URL,
+		Events:  req.Events,
+		Enabled: req.Enabled,
+	})
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hook)
+}
+
+// Delete handles DELETE /api/v1/outgoing-webhooks/{id}
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid webhook ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteWebhook(r.Context(), id, userClaims.Subject); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Ping handles POST /api/v1/outgoing-webhooks/{id}/ping
+// Sends a webhook.ping event synchronously and returns the delivery.
+func (h *WebhookHandler) Ping(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid webhook ID format")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.Service.Ping(r.Context(), id, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// Deliveries handles GET /api/v1/outgoing-webhooks/{id}/deliveries
+// Most recent first, including payloads and the endpoint's response status.
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid webhook ID format")
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.Service.ListDeliveries(r.Context(), id, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// Redeliver handles POST /api/v1/outgoing-webhooks/{id}/deliveries/{deliveryID}/redeliver
+// Resends the original payload under the same event ID, so receivers can dedupe.
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	userClaims, id, ok := parseOwnedID(w, r, "Invalid webhook ID format")
+	if !ok {
+		return
+	}
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Invalid delivery ID format")
+		return
+	}
+
+	delivery, err := h.Service.Redeliver(r.Context(), id, deliveryID, userClaims.Subject)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(delivery)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/gorilla/websocket"
 
 	"kari/api/internal/core/domain"
+	"kari/api/internal/telemetry"
 )
 
 // ==============================================================================
@@ -64,18 +65,24 @@ func NewWebSocketHandler(service domain.DeploymentStreamService, logger *slog.Lo
 // ==============================================================================
 
 // StreamDeploymentLogs handles GET /api/v1/ws/deployments/{trace_id}
+// ?format=html|plain|ansi converts or strips terminal escapes (default ansi).
 func (h *WebSocketHandler) StreamDeploymentLogs(w http.ResponseWriter, r *http.Request) {
 	// 1. Extract the verified user from the JWT Context
 	// This physically prevents a tenant from guessing another tenant's trace_id and snooping on their logs.
 	userClaims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
 	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		writeError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	traceID := chi.URLParam(r, "trace_id")
 	if traceID == "" {
-		http.Error(w, "Missing trace_id", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Missing trace_id")
+		return
+	}
+	renderer, ok := logRendererFor(r)
+	if !ok {
+		writeError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "format must be html, plain or ansi")
 		return
 	}
 
@@ -112,14 +119,14 @@ func (h *WebSocketHandler) StreamDeploymentLogs(w http.ResponseWriter, r *http.R
 	
 	// The Write Pump takes the Go channel and streams it to the browser.
 	// This blocks the current HTTP handler thread until the deployment finishes or the user closes the tab.
-	h.writePump(ws, logChannel, traceID)
+	h.writePump(ws, logChannel, renderer, traceID)
 }
 
 // ==============================================================================
 // 4. The Write Pump (Streaming Logs to SvelteKit)
 // ==============================================================================
 
-func (h *WebSocketHandler) writePump(ws *websocket.Conn, logChannel <-chan domain.LogChunk, traceID string) {
+func (h *WebSocketHandler) writePump(ws *websocket.Conn, logChannel <-chan domain.LogChunk, renderer *telemetry.LogRenderer, traceID string) {
 	// Ensure the WebSocket is closed when this function exits to prevent memory leaks
 	defer func() {
 		ws.Close()
@@ -143,6 +150,7 @@ func (h *WebSocketHandler) writePump(ws *websocket.Conn, logChannel <-chan domai
 			}
 
 			// Serialize the chunk to JSON and push it over the WebSocket
+			chunk.Content = renderer.Render(chunk.Content)
 			err := ws.WriteJSON(chunk)
 			if err != nil {
 				h.Logger.Error("Failed to write JSON to WebSocket", 
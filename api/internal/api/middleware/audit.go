@@ -0,0 +1,256 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+
+	"kari/api/internal/core/domain"
+)
+
+// AuditSnapshot loads a resource as the audit diff should see it. It runs
+// with the request's context, so domain.RequestContextFrom gives the actor.
+type AuditSnapshot func(ctx context.Context, id uuid.UUID) (any, error)
+
+// AuditSnapshots maps the first path segment under /api/v1 ("applications")
+// to its loader. Resources without one are audited without a diff.
+type AuditSnapshots map[string]AuditSnapshot
+
+// maxAuditedBody bounds how much of a request body is parsed for field names.
+const maxAuditedBody = 64 << 10
+
+// sensitiveFields mark keys whose values never reach the audit trail; their
+// diff only says that they changed.
+var sensitiveFields = []string{"env", "secret", "password", "token", "key", "credential", "private"}
+
+// AuditTrail returns middleware that writes a tenant log for every mutating
+// request, whatever the outcome, so the trail is complete even for flows
+// whose service records nothing. Entries are named after the route, e.g.
+// POST /applications/{id}/deploy is "applications.deploy" and PUT
+// /applications/{id} is "applications.update".
+//
+// The request body is recorded as field names only. When a snapshot loader
+// exists for the resource, the entry also gets a before/after diff of its
+// top-level fields.
+//
+// 🛡️ Compliance: Must run AFTER RequireAuthentication, which supplies the actor.
+func AuditTrail(repo domain.AuditRepository, snapshots AuditSnapshots, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isMutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			claims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+			if !ok || claims == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			resource, id, sub := auditTarget(r.URL.Path)
+			load := snapshots[resource]
+			var before any
+			if load != nil && id != uuid.Nil {
+				before, _ = load(r.Context(), id)
+			}
+			fields := bodyFields(r)
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			// A dropped client must not drop its audit entry
+			ctx := context.WithoutCancel(r.Context())
+			metadata := map[string]any{
+				"method":  r.Method,
+				"path":    r.URL.Path,
+				"route":   chi.RouteContext(r.Context()).RoutePattern(),
+				"status":  status,
+				"outcome": outcome(status),
+			}
+			if len(fields) > 0 {
+				metadata["fields"] = fields
+			}
+
+			var after any
+			if load != nil && id != uuid.Nil && status < http.StatusBadRequest {
+				after, _ = load(ctx, id)
+				if diff := auditDiff(before, after); len(diff) > 0 {
+					metadata["diff"] = diff
+				}
+			}
+
+			entry := &domain.TenantLog{
+				TenantID:     ownerOf(after, ownerOf(before, claims.Subject)),
+				ActorID:      &claims.Subject,
+				Action:       auditAction(resource, sub, r.Method),
+				ResourceType: resource,
+				Metadata:     metadata,
+			}
+			if id != uuid.Nil {
+				entry.ResourceID = id.String()
+			}
+			if err := repo.CreateTenantLog(ctx, entry); err != nil {
+				logger.Error("Failed to record API audit entry",
+					slog.String("action", entry.Action),
+					slog.String("path", r.URL.Path),
+					slog.Any("error", err))
+			}
+		})
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func outcome(status int) string {
+	if status < http.StatusBadRequest {
+		return "success"
+	}
+	return "failure"
+}
+
+// auditTarget splits /api/v1/applications/{id}/deploy-key into the
+// resource ("applications"), its ID, and the remaining sub-path segments.
+func auditTarget(path string) (resource string, id uuid.UUID, sub []string) {
+	segs := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/v1"), "/"), "/")
+	if len(segs) == 0 || segs[0] == "" {
+		return "api", uuid.Nil, nil
+	}
+	resource, rest := segs[0], segs[1:]
+	if len(rest) > 0 {
+		if parsed, err := uuid.Parse(rest[0]); err == nil {
+			id, rest = parsed, rest[1:]
+		}
+	}
+	for _, s := range rest {
+		if _, err := uuid.Parse(s); err != nil && s != "" {
+			sub = append(sub, s)
+		}
+	}
+	return resource, id, sub
+}
+
+// auditAction names the call: a POST to a sub-path is the action itself
+// (deploy, restart); otherwise the method says what happened.
+func auditAction(resource string, sub []string, method string) string {
+	verb := map[string]string{
+		http.MethodPost:   "create",
+		http.MethodPut:    "update",
+		http.MethodPatch:  "update",
+		http.MethodDelete: "delete",
+	}[method]
+	parts := append([]string{resource}, sub...)
+	if method != http.MethodPost || len(sub) == 0 {
+		parts = append(parts, verb)
+	}
+	return strings.Join(parts, ".")
+}
+
+// bodyFields lists the top-level keys of a JSON body and restores the body
+// for the handler. Values are never recorded.
+func bodyFields(r *http.Request) []string {
+	if r.Body == nil || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAuditedBody+1))
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	if err != nil || len(body) > maxAuditedBody {
+		return nil
+	}
+	var doc map[string]json.RawMessage
+	if json.Unmarshal(body, &doc) != nil {
+		return nil
+	}
+	fields := make([]string, 0, len(doc))
+	for k := range doc {
+		fields = append(fields, k)
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// auditDiff compares the JSON forms of two snapshots field by field.
+// Sensitive fields are reported as changed without their values.
+func auditDiff(before, after any) map[string]any {
+	b, a := asFields(before), asFields(after)
+	if b == nil && a == nil {
+		return nil
+	}
+	diff := map[string]any{}
+	for _, k := range unionKeys(b, a) {
+		if k == "updated_at" || reflect.DeepEqual(b[k], a[k]) {
+			continue
+		}
+		if isSensitive(k) {
+			diff[k] = map[string]any{"changed": true}
+			continue
+		}
+		diff[k] = map[string]any{"before": b[k], "after": a[k]}
+	}
+	return diff
+}
+
+func asFields(v any) map[string]any {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil()) {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]any
+	if json.Unmarshal(raw, &fields) != nil {
+		return nil
+	}
+	return fields
+}
+
+func unionKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, dup := a[k]; !dup {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func isSensitive(field string) bool {
+	field = strings.ToLower(field)
+	return slices.ContainsFunc(sensitiveFields, func(s string) bool { return strings.Contains(field, s) })
+}
+
+// ownerOf files the entry under the resource owner's tenant when the
+// snapshot says who that is, else under fallback (the actor).
+func ownerOf(snapshot any, fallback uuid.UUID) uuid.UUID {
+	if owner, ok := asFields(snapshot)["owner_id"].(string); ok {
+		if id, err := uuid.Parse(owner); err == nil {
+			return id
+		}
+	}
+	return fallback
+}
@@ -5,11 +5,6 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
-	"sync"
-	"time"
-
-	"github.com/go-chi/chi/v5/middleware"
-	"golang.org/x/time/rate"
 
 	"kari/api/internal/core/domain"
 )
@@ -19,7 +14,6 @@ type AuthMiddleware struct {
 	RoleService domain.RoleService
 	UserRepo    domain.UserRepository // 🛡️ Added for Real-time Zero-Trust checks
 	Logger      *slog.Logger
-	visitors    sync.Map // 🛡️ Thread-safe Map for high-concurrency scaling
 }
 
 func NewAuthMiddleware(authService domain.AuthService, roleService domain.RoleService, userRepo domain.UserRepository, logger *slog.Logger) *AuthMiddleware {
@@ -29,8 +23,6 @@ func NewAuthMiddleware(authService domain.AuthService, roleService domain.RoleSe
 		UserRepo:    userRepo,
 		Logger:      logger,
 	}
-	// Start cleanup worker as a managed method, not a global init
-	go m.cleanupVisitors()
 	return m
 }
 
@@ -43,13 +35,13 @@ func (m *AuthMiddleware) RequireAuthentication(next http.Handler) http.Handler {
 		tokenString := m.extractToken(r)
 
 		if tokenString == "" {
-			http.Error(w, `{"message": "Unauthorized"}`, http.StatusUnauthorized)
+			WriteError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
 			return
 		}
 
 		claims, err := m.AuthService.ValidateAccessToken(r.Context(), tokenString)
 		if err != nil {
-			http.Error(w, `{"message": "Invalid token"}`, http.StatusUnauthorized)
+			WriteError(w, r, http.StatusUnauthorized, domain.CodeSessionExpired, "Invalid token")
 			return
 		}
 
@@ -57,60 +49,20 @@ func (m *AuthMiddleware) RequireAuthentication(next http.Handler) http.Handler {
 		user, err := m.UserRepo.GetByID(r.Context(), claims.UserID)
 		if err != nil || !user.IsActive {
 			m.Logger.Warn("Attempted access with ghost token", slog.String("user_id", claims.UserID.String()))
-			http.Error(w, `{"message": "Account suspended"}`, http.StatusForbidden)
+			WriteError(w, r, http.StatusForbidden, domain.CodeForbidden, "Account suspended")
 			return
 		}
 
 		ctx := context.WithValue(r.Context(), domain.UserContextKey, claims)
+		ctx = domain.WithActor(ctx, user.ID, user.Role.Rank)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-// ==============================================================================
-// 2. Performance & DoS Protection
-// ==============================================================================
-
-func (m *AuthMiddleware) RateLimit(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// 🛡️ Use X-Real-IP for proxy compatibility
-		ip := r.Header.Get("X-Real-IP")
-		if ip == "" {
-			ip = r.RemoteAddr
-		}
-
-		v, _ := m.visitors.LoadOrStore(ip, &visitor{
-			limiter:  rate.NewLimiter(rate.Limit(10), 30),
-			lastSeen: time.Now(),
-		})
-		
-		vis := v.(*visitor)
-		vis.lastSeen = time.Now()
-
-		if !vis.limiter.Allow() {
-			http.Error(w, `{"message": "Rate limit exceeded"}`, http.StatusTooManyRequests)
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
-
-func (m *AuthMiddleware) cleanupVisitors() {
-	ticker := time.NewTicker(time.Minute)
-	for range ticker.C {
-		m.visitors.Range(func(key, value interface{}) bool {
-			if time.Since(value.(*visitor).lastSeen) > 3*time.Minute {
-				m.visitors.Delete(key)
-			}
-			return true
-		})
-	}
-}
-
 // ... [EnforceTLS and StructuredLogger remain as helper functions] ...
 
 // ==============================================================================
-// 3. 🛡️ JWT Scope Enforcement (Stateless RBAC)
+// 2. 🛡️ JWT Scope Enforcement (Stateless RBAC)
 // ==============================================================================
 
 // RequirePermission returns middleware that checks if the authenticated user's JWT
@@ -122,17 +74,17 @@ func (m *AuthMiddleware) RequirePermission(resource, action string) func(http.Ha
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			claims := m.claimsFromContext(r.Context())
 			if claims == nil {
-				http.Error(w, `{"message": "Identity context missing"}`, http.StatusUnauthorized)
+				WriteError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Identity context missing")
 				return
 			}
 
 			// 🛡️ SLA: Check if the user's JWT-embedded permissions include the required scope
-			if !hasPermission(claims.Permissions, required) {
+			if !HasPermission(claims.Permissions, required) {
 				m.Logger.Warn("🛡️ Scope violation: user lacks required permission",
 					slog.String("user_id", claims.UserID.String()),
 					slog.String("required", required),
 					slog.Any("granted", claims.Permissions))
-				http.Error(w, `{"message": "Forbidden: insufficient scope"}`, http.StatusForbidden)
+				WriteError(w, r, http.StatusForbidden, domain.CodeForbidden, "Forbidden: insufficient scope")
 				return
 			}
 
@@ -150,12 +102,12 @@ func (m *AuthMiddleware) RequireScope(scopes ...string) func(http.Handler) http.
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			claims := m.claimsFromContext(r.Context())
 			if claims == nil {
-				http.Error(w, `{"message": "Identity context missing"}`, http.StatusUnauthorized)
+				WriteError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Identity context missing")
 				return
 			}
 
 			for _, scope := range scopes {
-				if hasPermission(claims.Permissions, scope) {
+				if HasPermission(claims.Permissions, scope) {
 					next.ServeHTTP(w, r)
 					return
 				}
@@ -164,7 +116,7 @@ func (m *AuthMiddleware) RequireScope(scopes ...string) func(http.Handler) http.
 			m.Logger.Warn("🛡️ Scope enforcement: view-only user attempted mutating action",
 				slog.String("user_id", claims.UserID.String()),
 				slog.Any("required_scopes", scopes))
-			http.Error(w, `{"message": "Forbidden: your account scope does not allow this action"}`, http.StatusForbidden)
+			WriteError(w, r, http.StatusForbidden, domain.CodeForbidden, "Forbidden: your account scope does not allow this action")
 		})
 	}
 }
@@ -183,7 +135,7 @@ func (m *AuthMiddleware) claimsFromContext(ctx context.Context) *domain.UserClai
 }
 
 // hasPermission checks if the permissions slice contains the target string.
-func hasPermission(permissions []string, target string) bool {
+func HasPermission(permissions []string, target string) bool {
 	for _, p := range permissions {
 		if p == target || p == "*" {
 			return true
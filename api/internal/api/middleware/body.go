@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"kari/api/internal/core/domain"
+)
+
+// BodyRule is the request body policy for paths under Prefix. A zero
+// MaxBytes or nil ContentTypes inherits the default rule's value; the
+// default rule must set MaxBytes.
+type BodyRule struct {
+	Prefix       string
+	MaxBytes     int64
+	ContentTypes []string // Media types without parameters, e.g. "application/json"
+}
+
+// BodyLimits enforces a size cap and an allowed Content-Type on every
+// request that carries a body, using the longest matching rule. It runs
+// before routing, so one rule table replaces per-handler caps.
+//
+// 🛡️ Decompression bombs: gzip bodies are inflated here and the cap counts
+// inflated bytes; other encodings are refused rather than passed through.
+func BodyLimits(defaults BodyRule, rules ...BodyRule) func(http.Handler) http.Handler {
+	// Longest prefix first so the most specific rule wins
+	rules = slices.Clone(rules)
+	slices.SortFunc(rules, func(a, b BodyRule) int { return len(b.Prefix) - len(a.Prefix) })
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil || r.Body == http.NoBody || (r.ContentLength == 0 && len(r.TransferEncoding) == 0) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rule := defaults
+			for _, candidate := range rules {
+				if strings.HasPrefix(r.URL.Path, candidate.Prefix) {
+					if candidate.MaxBytes > 0 {
+						rule.MaxBytes = candidate.MaxBytes
+					}
+					if candidate.ContentTypes != nil {
+						rule.ContentTypes = candidate.ContentTypes
+					}
+					break
+				}
+			}
+
+			if len(rule.ContentTypes) > 0 {
+				mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+				if err != nil || !slices.Contains(rule.ContentTypes, mediaType) {
+					w.Header().Set("Accept", strings.Join(rule.ContentTypes, ", "))
+					WriteError(w, r, http.StatusUnsupportedMediaType, domain.CodeUnsupportedMedia,
+						"Content-Type must be "+strings.Join(rule.ContentTypes, " or "))
+					return
+				}
+			}
+
+			// Fast path: a declared length over the cap never gets read
+			if r.ContentLength > rule.MaxBytes {
+				writeTooLarge(w, r, rule.MaxBytes)
+				return
+			}
+
+			switch encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))); encoding {
+			case "", "identity":
+				r.Body = http.MaxBytesReader(w, r.Body, rule.MaxBytes)
+			case "gzip":
+				// The compressed stream is capped too, so a truncated or
+				// endless gzip header cannot hold the connection either
+				compressed := http.MaxBytesReader(w, r.Body, rule.MaxBytes)
+				zr, err := gzip.NewReader(compressed)
+				if err != nil {
+					WriteError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Malformed gzip body")
+					return
+				}
+				r.Body = &inflatedBody{
+					Reader: io.LimitReader(zr, rule.MaxBytes+1),
+					zr:     zr,
+					raw:    compressed,
+					limit:  rule.MaxBytes,
+				}
+				r.Header.Del("Content-Encoding")
+				r.ContentLength = -1
+			default:
+				WriteError(w, r, http.StatusUnsupportedMediaType, domain.CodeUnsupportedMedia,
+					"Unsupported Content-Encoding "+strconv.Quote(encoding))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeTooLarge(w http.ResponseWriter, r *http.Request, limit int64) {
+	w.Header().Set("Connection", "close")
+	WriteError(w, r, http.StatusRequestEntityTooLarge, domain.CodePayloadTooLarge,
+		"Request body exceeds "+strconv.FormatInt(limit, 10)+" bytes")
+}
+
+// inflatedBody reports an inflated size over the cap as *http.MaxBytesError,
+// so handlers answer 413 exactly as for an oversized plain body.
+type inflatedBody struct {
+	io.Reader
+	zr    *gzip.Reader
+	raw   io.ReadCloser
+	limit int64
+	read  int64
+}
+
+func (b *inflatedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		return n - int(b.read-b.limit), &http.MaxBytesError{Limit: b.limit}
+	}
+	return n, err
+}
+
+func (b *inflatedBody) Close() error {
+	b.zr.Close()
+	return b.raw.Close()
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/i18n"
+)
+
+// WriteError renders the standard domain.ErrorResponse envelope.
+// 🛡️ SLA: Every failure leaving the Brain carries the request's trace ID so
+// operators can correlate a UI toast with the structured server log line.
+func WriteError(w http.ResponseWriter, r *http.Request, status int, code domain.ErrorCode, message string) {
+	WriteErrorResponse(w, r, status, domain.ErrorResponse{
+		Code:    code,
+		Message: message,
+	})
+}
+
+// WriteErrorResponse renders a fully populated envelope (e.g., with field errors).
+// The message (and title) is translated into the request's locale; field messages arrive
+// already localized, since only their builder knows the placeholders.
+func WriteErrorResponse(w http.ResponseWriter, r *http.Request, status int, resp domain.ErrorResponse) {
+	if r != nil {
+		if resp.TraceID == "" {
+			resp.TraceID = middleware.GetReqID(r.Context())
+		}
+		loc := i18n.FromContext(r.Context())
+		resp.Message = i18n.Translate(loc, resp.Message)
+		if resp.Title != "" {
+			resp.Title = i18n.Translate(loc, resp.Title)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
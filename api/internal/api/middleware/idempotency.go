@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+const (
+	// IdempotencyHeader is the client-supplied retry key (RFC draft "Idempotency-Key").
+	IdempotencyHeader = "Idempotency-Key"
+
+	// idempotencyTTL bounds how long a key protects against duplicates.
+	idempotencyTTL = 24 * time.Hour
+
+	// maxIdempotencyKeyLength rejects abusive keys before they reach Postgres.
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotency returns middleware that makes a mutating endpoint safe to retry.
+// If the request carries an Idempotency-Key header, the first execution's
+// response is cached and replayed for any retry with the same key and payload.
+// Requests without the header pass through unchanged.
+//
+// 🛡️ Stability: Must run AFTER RequireAuthentication — keys are scoped per user.
+func Idempotency(store domain.IdempotencyRepository, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyHeader)
+			if key == "" || r.Method != http.MethodPost {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				WriteError(w, r, http.StatusBadRequest, domain.CodeBadRequest, "Idempotency-Key exceeds 255 characters")
+				return
+			}
+
+			claims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+			if !ok || claims == nil {
+				WriteError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Identity context missing")
+				return
+			}
+
+			// 1. Fingerprint the request (body is re-wrapped for the handler)
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				WriteError(w, r, http.StatusRequestEntityTooLarge, domain.CodePayloadTooLarge, "Request body is too large")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			requestHash := fingerprintRequest(r, body)
+
+			// 2. Claim the key or load the previous outcome
+			existing, err := store.Reserve(r.Context(), claims.UserID, key, requestHash, idempotencyTTL)
+			if err != nil {
+				logger.Error("Idempotency reservation failed", slog.Any("error", err))
+				WriteError(w, r, http.StatusServiceUnavailable, domain.CodeServiceUnavailable, "Could not verify Idempotency-Key. Try again.")
+				return
+			}
+
+			if existing != nil {
+				switch {
+				case existing.RequestHash != requestHash:
+					WriteError(w, r, http.StatusUnprocessableEntity, domain.CodeConflict, "Idempotency-Key was already used with a different request payload")
+				case existing.InFlight():
+					WriteError(w, r, http.StatusConflict, domain.CodeConflict, "A request with this Idempotency-Key is still being processed")
+				default:
+					replayResponse(w, existing)
+				}
+				return
+			}
+
+			// 3. Execute the handler and capture its response
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			completed := false
+			defer func() {
+				if !completed {
+					// Panic or server failure: free the key so the client can retry.
+					_ = store.Release(context.WithoutCancel(r.Context()), claims.UserID, key)
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+
+			// 🛡️ SLA: Only cache deterministic outcomes. 5xx results are released
+			// so a transient failure doesn't pin the client to an error forever.
+			if rec.status >= http.StatusInternalServerError {
+				return
+			}
+			if err := store.Complete(context.WithoutCancel(r.Context()), claims.UserID, key, rec.status, rec.Header().Get("Content-Type"), rec.body.Bytes()); err != nil {
+				logger.Warn("Failed to persist idempotent response", slog.String("key", key), slog.Any("error", err))
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// fingerprintRequest binds the key to method, path, and exact body bytes.
+func fingerprintRequest(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replayResponse(w http.ResponseWriter, rec *domain.IdempotencyRecord) {
+	if rec.ContentType != "" {
+		w.Header().Set("Content-Type", rec.ContentType)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(rec.StatusCode)
+	w.Write(rec.ResponseBody)
+}
+
+// responseRecorder tees the handler output to the client and an in-memory copy.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (rr *responseRecorder) WriteHeader(code int) {
+	if !rr.wroteHeader {
+		rr.status = code
+		rr.wroteHeader = true
+	}
+	rr.ResponseWriter.WriteHeader(code)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if !rr.wroteHeader {
+		rr.WriteHeader(http.StatusOK)
+	}
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
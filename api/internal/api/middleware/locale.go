@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	"kari/api/internal/i18n"
+)
+
+// Locale negotiates the response language from Accept-Language, so error
+// envelopes (and anything else that reads i18n.FromContext) speak the
+// caller's language. The choice is echoed in Content-Language.
+func Locale(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loc := i18n.Negotiate(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Language", string(loc))
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r.WithContext(i18n.WithLocale(r.Context(), loc)))
+	})
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// maintenanceRetryAfter is a hint only; maintenance ends when an admin says so.
+const maintenanceRetryAfter = "300"
+
+// Maintenance returns middleware that rejects mutating requests with 503
+// while maintenance mode is on. Reads always pass, and so do admins
+// (server:manage) so they can work on the panel and switch the mode off.
+//
+// 🛡️ Must run AFTER RequireAuthentication to recognize admins; on public
+// routes (push webhooks) every mutating request is rejected.
+func Maintenance(state domain.MaintenanceState) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			m := state.Maintenance()
+			if !m.Enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if claims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims); ok && claims != nil &&
+				HasPermission(claims.Permissions, "server:manage") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Retry-After", maintenanceRetryAfter)
+			WriteError(w, r, http.StatusServiceUnavailable, domain.CodeMaintenance, m.Message)
+		})
+	}
+}
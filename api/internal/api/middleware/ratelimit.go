@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"kari/api/internal/core/domain"
+)
+
+// RateLimitConfig sizes the per-IP token buckets.
+type RateLimitConfig struct {
+	Name              string // "limiter" label on the metrics; "" = "global"
+	RequestsPerSecond float64
+	Burst             int
+	VisitorTTL        time.Duration // Idle visitors are forgotten after this long
+	MaxVisitors       int           // Hard cap on tracked IPs; 0 = unlimited
+}
+
+// RateLimiter is an in-memory token bucket per client IP.
+// 🛡️ Stability: Memory stays bounded under IP churn. Idle visitors are swept
+// after VisitorTTL, and once MaxVisitors are tracked, new IPs share a single
+// overflow bucket instead of growing the map.
+type RateLimiter struct {
+	cfg      RateLimitConfig
+	mu       sync.Mutex
+	visitors map[string]*visitor
+	overflow *rate.Limiter
+
+	rejected   atomic.Uint64
+	evicted    atomic.Uint64
+	overflowed atomic.Uint64
+
+	visitorsDesc   *prometheus.Desc
+	rejectedDesc   *prometheus.Desc
+	evictedDesc    *prometheus.Desc
+	overflowedDesc *prometheus.Desc
+}
+
+type visitor struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time // Guarded by RateLimiter.mu
+}
+
+func NewRateLimiter(cfg RateLimitConfig) *RateLimiter {
+	if cfg.Name == "" {
+		cfg.Name = "global"
+	}
+	labels := prometheus.Labels{"limiter": cfg.Name}
+	return &RateLimiter{
+		cfg:      cfg,
+		visitors: make(map[string]*visitor),
+		overflow: rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), cfg.Burst),
+
+		visitorsDesc:   prometheus.NewDesc("kari_rate_limit_visitors", "Client IPs currently tracked by the rate limiter.", nil, labels),
+		rejectedDesc:   prometheus.NewDesc("kari_rate_limit_rejected_total", "Requests answered with 429.", nil, labels),
+		evictedDesc:    prometheus.NewDesc("kari_rate_limit_evicted_total", "Idle visitors removed by the sweeper.", nil, labels),
+		overflowedDesc: prometheus.NewDesc("kari_rate_limit_overflow_total", "Requests limited by the shared bucket because MaxVisitors was reached.", nil, labels),
+	}
+}
+
+// Middleware rejects clients that exceed their bucket with a 429.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.allow(clientIP(r), time.Now()) {
+			l.rejected.Add(1)
+			WriteError(w, r, http.StatusTooManyRequests, domain.CodeRateLimited, "Rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *RateLimiter) allow(ip string, now time.Time) bool {
+	l.mu.Lock()
+	v, ok := l.visitors[ip]
+	if !ok {
+		if l.cfg.MaxVisitors > 0 && len(l.visitors) >= l.cfg.MaxVisitors {
+			l.mu.Unlock()
+			l.overflowed.Add(1)
+			return l.overflow.AllowN(now, 1)
+		}
+		v = &visitor{limiter: rate.NewLimiter(rate.Limit(l.cfg.RequestsPerSecond), l.cfg.Burst)}
+		l.visitors[ip] = v
+	}
+	v.lastSeen = now
+	l.mu.Unlock()
+
+	return v.limiter.AllowN(now, 1)
+}
+
+// Sweep forgets visitors idle for longer than VisitorTTL.
+func (l *RateLimiter) Sweep(now time.Time) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	removed := 0
+	for ip, v := range l.visitors {
+		if now.Sub(v.lastSeen) > l.cfg.VisitorTTL {
+			delete(l.visitors, ip)
+			removed++
+		}
+	}
+	l.evicted.Add(uint64(removed))
+	return removed
+}
+
+// Start runs the sweeper until ctx is cancelled. It sweeps at half the TTL,
+// so no idle visitor outlives 1.5x VisitorTTL.
+func (l *RateLimiter) Start(ctx context.Context) {
+	interval := l.cfg.VisitorTTL / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			l.Sweep(now)
+		}
+	}
+}
+
+// Describe and Collect make the limiter a prometheus.Collector.
+func (l *RateLimiter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- l.visitorsDesc
+	ch <- l.rejectedDesc
+	ch <- l.evictedDesc
+	ch <- l.overflowedDesc
+}
+
+func (l *RateLimiter) Collect(ch chan<- prometheus.Metric) {
+	l.mu.Lock()
+	tracked := len(l.visitors)
+	l.mu.Unlock()
+
+	ch <- prometheus.MustNewConstMetric(l.visitorsDesc, prometheus.GaugeValue, float64(tracked))
+	ch <- prometheus.MustNewConstMetric(l.rejectedDesc, prometheus.CounterValue, float64(l.rejected.Load()))
+	ch <- prometheus.MustNewConstMetric(l.evictedDesc, prometheus.CounterValue, float64(l.evicted.Load()))
+	ch <- prometheus.MustNewConstMetric(l.overflowedDesc, prometheus.CounterValue, float64(l.overflowed.Load()))
+}
+
+// clientIP keys visitors by address without the port; chi's RealIP has
+// already replaced RemoteAddr with X-Real-IP / X-Forwarded-For when present.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
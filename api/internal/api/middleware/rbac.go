@@ -35,7 +35,7 @@ func (m *RBACMiddleware) Authenticate(next http.Handler) http.Handler {
 		tokenStr := m.extractToken(r)
 
 		if tokenStr == "" {
-			http.Error(w, "Authentication required", http.StatusUnauthorized)
+			WriteError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Authentication required")
 			return
 		}
 
@@ -45,20 +45,20 @@ func (m *RBACMiddleware) Authenticate(next http.Handler) http.Handler {
 		})
 
 		if err != nil || !token.Valid {
-			http.Error(w, "Invalid session", http.StatusUnauthorized)
+			WriteError(w, r, http.StatusUnauthorized, domain.CodeSessionExpired, "Invalid session")
 			return
 		}
 
 		userID, err := uuid.Parse(claims.Subject)
 		if err != nil {
-			http.Error(w, "Malformed subject", http.StatusUnauthorized)
+			WriteError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Malformed subject")
 			return
 		}
 
 		// 🛡️ Zero-Trust: Real-time DB check with eager loading of Role
 		user, err := m.repo.GetByID(r.Context(), userID)
 		if err != nil || !user.IsActive {
-			http.Error(w, "Account inactive", http.StatusForbidden)
+			WriteError(w, r, http.StatusForbidden, domain.CodeForbidden, "Account inactive")
 			return
 		}
 
@@ -75,7 +75,7 @@ func (m *RBACMiddleware) RequirePermission(resource, action string) func(http.Ha
 			// 🛡️ Safe context retrieval
 			val := r.Context().Value(UserKey)
 			if val == nil {
-				http.Error(w, "Identity context missing", http.StatusInternalServerError)
+				WriteError(w, r, http.StatusInternalServerError, domain.CodeInternal, "Identity context missing")
 				return
 			}
 			userID := val.(uuid.UUID)
@@ -83,7 +83,7 @@ func (m *RBACMiddleware) RequirePermission(resource, action string) func(http.Ha
 			// Consult the Dynamic RBAC Store
 			hasPerm, err := m.repo.HasPermission(r.Context(), userID, resource, action)
 			if err != nil || !hasPerm {
-				http.Error(w, "Forbidden", http.StatusForbidden)
+				WriteError(w, r, http.StatusForbidden, domain.CodeForbidden, "Forbidden")
 				return
 			}
 
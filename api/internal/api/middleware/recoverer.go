@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"runtime/debug"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"kari/api/internal/core/domain"
+)
+
+// Recoverer replaces chi's Recoverer: it turns a handler panic into a 500
+// with the standard error envelope and hands the stack and request context
+// to the crash reporter. A nil reporter only recovers.
+func Recoverer(reporter domain.CrashReporter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					// Deliberate abort; net/http handles it without logging a stack
+					panic(rec)
+				}
+
+				if reporter != nil {
+					reporter.Report(domain.CrashSourceHTTP, routeName(r), rec, debug.Stack(), &domain.CrashRequest{
+						RequestID:  middleware.GetReqID(r.Context()),
+						Method:     r.Method,
+						Path:       r.URL.Path,
+						RemoteAddr: r.RemoteAddr,
+						UserAgent:  r.UserAgent(),
+					})
+				}
+
+				// Hijacked connections (WebSocket) have no response to write
+				if !headerHasToken(r.Header, "Connection", "upgrade") {
+					WriteError(w, r, http.StatusInternalServerError, domain.CodeInternal, "Internal server error")
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routeName groups crashes by route pattern rather than by concrete path,
+// so /apps/{id} panics for different apps land in one bucket.
+func routeName(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return r.Method + " " + pattern
+		}
+	}
+	return r.Method + " " + r.URL.Path
+}
+
+// headerHasToken reports whether any comma-separated value of the header is
+// token, ignoring case ("keep-alive, Upgrade" counts as an upgrade).
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
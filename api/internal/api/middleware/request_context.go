@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"kari/api/internal/core/domain"
+)
+
+// RequestContext attaches the caller's IP and the request ID to every
+// request; RequireAuthentication adds the user once the token checks out.
+// It must run after chi's RequestID and RealIP.
+func RequestContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := domain.WithRequestContext(r.Context(), domain.RequestContext{
+			IP:      clientIP(r),
+			TraceID: middleware.GetReqID(r.Context()),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
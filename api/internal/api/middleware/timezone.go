@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// DisplayTimezone converts the timestamps of a JSON response into the zone
+// named by ?tz=, so clients without a tz database (CLI scripts, status
+// widgets) get local times. tz is an IANA name, "user" (the caller's own
+// zone, else the install's) or "install". Stored times stay UTC; only the
+// rendering changes, and the zone used is echoed in X-Kari-Timezone.
+//
+// Only timestamp fields are converted: RFC 3339 values under keys ending in
+// "_at" (created_at, expires_at, ...). Any other string, such as a log line
+// or a setting that happens to hold a date, is returned as stored. Bodies
+// larger than the ETag buffer, streams and WebSocket upgrades pass through
+// untouched.
+//
+// 🛡️ Must run AFTER RequireAuthentication, which tz=user reads.
+func DisplayTimezone(zones domain.TimezoneManager, install domain.TimezoneSource) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tz := r.URL.Query().Get("tz")
+			if tz == "" || r.Header.Get("Upgrade") != "" || strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var loc *time.Location
+			switch tz {
+			case "install":
+				loc = install.Location()
+			case "user":
+				claims, ok := r.Context().Value(domain.UserContextKey).(*domain.UserClaims)
+				if !ok {
+					WriteError(w, r, http.StatusUnauthorized, domain.CodeUnauthorized, "Unauthorized")
+					return
+				}
+				loc = zones.LocationFor(r.Context(), claims.Subject)
+			default:
+				var err error
+				if loc, err = domain.LoadTimezone(tz); err != nil {
+					WriteError(w, r, http.StatusBadRequest, domain.CodeValidationFailed, "tz must be an IANA time zone, user or install")
+					return
+				}
+			}
+
+			bw := &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(bw, r)
+			if bw.passthrough {
+				return
+			}
+			if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+				if converted, ok := convertTimestamps(bw.buf.Bytes(), loc); ok {
+					bw.buf.Reset()
+					bw.buf.Write(converted)
+					w.Header().Del("Content-Length")
+					w.Header().Del("ETag") // The tag describes the UTC rendering
+					w.Header().Set("X-Kari-Timezone", loc.String())
+				}
+			}
+			bw.flush()
+		})
+	}
+}
+
+// convertTimestamps rewrites the timestamp fields of a JSON document into
+// loc. ok is false when the body is not a single JSON document.
+func convertTimestamps(body []byte, loc *time.Location) ([]byte, bool) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber() // Keep int64 IDs and byte counts exact
+	var doc any
+	if err := dec.Decode(&doc); err != nil || dec.More() {
+		return nil, false
+	}
+	out, err := json.Marshal(inZone(doc, loc, false))
+	if err != nil {
+		return nil, false
+	}
+	return append(out, '\n'), true
+}
+
+// isTimestampKey names the fields DisplayTimezone converts.
+func isTimestampKey(key string) bool {
+	return strings.HasSuffix(key, "_at")
+}
+
+// inZone converts timestamp strings in v; timestamp says v sits under a
+// timestamp key (arrays inherit it from theirs).
+func inZone(v any, loc *time.Location, timestamp bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = inZone(item, loc, isTimestampKey(k))
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = inZone(item, loc, timestamp)
+		}
+	case string:
+		// Cheap shape check before parsing: "2006-01-02T..."
+		if timestamp && len(v) >= 20 && v[4] == '-' && v[10] == 'T' {
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t.In(loc).Format(time.RFC3339Nano)
+			}
+		}
+	}
+	return v
+}
@@ -12,19 +12,78 @@ import (
 
 	"kari/api/internal/api/handlers"
 	auth_middleware "kari/api/internal/api/middleware"
+	"kari/api/internal/core/domain"
+	kari_http "kari/api/internal/delivery/http"
 )
 
 // RouterConfig defines the strict dependencies required to build the API routing tree.
 type RouterConfig struct {
-	AuthHandler    *handlers.AuthHandler
-	AppHandler     *handlers.AppHandler
-	DomainHandler  *handlers.DomainHandler
-	AuditHandler   *handlers.AuditHandler
-	WSHandler      *handlers.WebSocketHandler
-	SetupHandler   *handlers.SetupHandler
-	AuthMiddleware *auth_middleware.AuthMiddleware
-	DeployHandler  *handlers.DeploymentHandler
-	Logger         *slog.Logger
+	AuthHandler      *handlers.AuthHandler
+	AppHandler       *handlers.AppHandler
+	OverviewHandler  *handlers.AppOverviewHandler
+	DomainHandler    *handlers.DomainHandler
+	SiteHandler      *handlers.SiteHandler
+	AuditHandler     *handlers.AuditHandler
+	WSHandler        *handlers.WebSocketHandler
+	SetupHandler     *handlers.SetupHandler
+	AuthMiddleware   *auth_middleware.AuthMiddleware
+	RateLimiter      *auth_middleware.RateLimiter
+	AuthRateLimiter  *auth_middleware.RateLimiter // Stricter per-IP bucket for sign-in; nil = global limit only
+	MetricsHandler   http.Handler // nil when no METRICS_TOKEN is configured
+	DeployHandler    *handlers.DeploymentHandler
+	DeployLogHandler *handlers.DeploymentLogHandler
+	SearchHandler    *handlers.SearchHandler
+	StorageHandler   *handlers.StorageHandler
+	AuditSinkHandler *handlers.AuditSinkHandler
+	UpdateHandler    *handlers.SystemUpdateHandler
+	AgentHandler     *handlers.AgentHandler
+	DeployKeyHandler *handlers.DeployKeyHandler
+	GitHandler       *handlers.GitHandler
+	RegistryHandler  *handlers.RegistryHandler
+	BucketHandler    *handlers.BucketHandler
+	FileScanHandler  *handlers.FileScanHandler
+	HeadersHandler   *handlers.SecurityHeadersHandler
+	AnalyticsHandler *handlers.AccessAnalyticsHandler
+	BandwidthHandler *handlers.BandwidthHandler
+	UsageHandler     *handlers.UsageHandler
+	QuotaHandler     *handlers.ResourceQuotaHandler
+	SettingsHandler  *handlers.SettingsHandler
+	ProfileHandler   *handlers.SystemProfileHandler
+	StackHandler     *handlers.StackRegistryHandler
+	ConfigHandler    *handlers.ConfigHandler
+	HealthHandler    *kari_http.HealthHandler
+	CrashHandler     *handlers.CrashHandler
+	DriftHandler     *handlers.DriftHandler
+	HistoryHandler   *handlers.StateHistoryHandler
+	WebhookHandler   *handlers.WebhookHandler
+	ChatOpsHandler   *handlers.ChatOpsHandler
+	ApprovalHandler  *handlers.ApprovalHandler
+	FeedHandler      *handlers.FeedHandler
+	WindowHandler    *handlers.MaintenanceWindowHandler
+	ScheduleHandler  *handlers.DeployScheduleHandler
+	ArtifactHandler  *handlers.ArtifactHandler
+	SBOMHandler      *handlers.SBOMHandler
+	AgentRPCHandler  *handlers.AgentRPCHandler
+	GraphQLHandler   http.Handler            // nil unless GRAPHQL_ENABLED
+	ServerHandler    *handlers.ServerHandler // nil when the agent CA is unavailable
+	RedactionHandler *handlers.RedactionHandler
+	PasskeyHandler   *handlers.PasskeyHandler // nil when no WebAuthn relying party is configured
+	PasswordHandler  *handlers.PasswordHandler
+	UserHandler      *handlers.UserHandler
+	DigestHandler    *handlers.DigestHandler
+	TimezoneHandler  *handlers.TimezoneHandler
+	Logger           *slog.Logger
+
+	// CrashReporter records handler panics (nil only recovers)
+	CrashReporter domain.CrashReporter
+
+	// IdempotencyRepo backs the Idempotency-Key guard on retry-prone POSTs
+	IdempotencyRepo domain.IdempotencyRepository
+
+	// AuditRepo receives an entry for every authenticated mutating call;
+	// AuditSnapshots adds before/after diffs for the resources it covers
+	AuditRepo      domain.AuditRepository
+	AuditSnapshots auth_middleware.AuditSnapshots
 }
 
 // NewRouter constructs the Chi multiplexer, attaches global middleware, and wires all endpoints.
@@ -37,15 +96,30 @@ func NewRouter(cfg RouterConfig) *chi.Mux {
 
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
+	r.Use(auth_middleware.RequestContext)
+	r.Use(auth_middleware.Locale)
 	r.Use(auth_middleware.StructuredLogger(cfg.Logger))
-	r.Use(middleware.Recoverer)
+	r.Use(auth_middleware.Recoverer(cfg.CrashReporter))
 	r.Use(middleware.Timeout(60 * time.Second))
 
-	// 🛡️ Limit all incoming JSON requests to 1 Megabyte max (OOM Protection)
-	r.Use(auth_middleware.MaxBytes(1_048_576))
+	// 🛡️ OOM Protection: Per-route body caps and Content-Type checks (JSON
+	// unless a rule says otherwise); gzip bodies are capped after inflation
+	r.Use(auth_middleware.BodyLimits(
+		auth_middleware.BodyRule{MaxBytes: 1 << 20, ContentTypes: []string{"application/json"}},
+		auth_middleware.BodyRule{Prefix: "/api/v1/auth/", MaxBytes: 10 << 10},
+		auth_middleware.BodyRule{Prefix: "/api/v1/auth/passkey", MaxBytes: 64 << 10}, // Attestations carry certificate chains
+		auth_middleware.BodyRule{Prefix: "/api/v1/webhooks/", MaxBytes: 10 << 20},
+		auth_middleware.BodyRule{Prefix: "/api/v1/servers/enroll", MaxBytes: 64 << 10}, // Token + hostname + CSR
+		auth_middleware.BodyRule{Prefix: "/api/v1/integrations/slack/", ContentTypes: []string{"application/x-www-form-urlencoded"}},
+		// RFC 8058 one-click unsubscribe: mail clients POST a form, never JSON
+		auth_middleware.BodyRule{Prefix: "/api/v1/digest/unsubscribe", MaxBytes: 4 << 10, ContentTypes: []string{"application/x-www-form-urlencoded", "multipart/form-data"}},
+		auth_middleware.BodyRule{Prefix: "/api/v1/rpc/", ContentTypes: []string{
+			"application/json", "application/proto", "application/grpc-web", "application/grpc-web+proto", "application/grpc-web+json",
+		}},
+	))
 
-	// 🛡️ In-memory token bucket rate limiting
-	r.Use(auth_middleware.RateLimitMiddleware)
+	// 🛡️ In-memory token bucket rate limiting (bounded; swept by a worker)
+	r.Use(cfg.RateLimiter.Middleware)
 
 	// 🔒 Force all connections to use TLS/SSL and inject HSTS headers
 	r.Use(auth_middleware.EnforceTLS)
@@ -54,8 +128,8 @@ func NewRouter(cfg RouterConfig) *chi.Mux {
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://localhost:5173"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Hub-Signature-256", "X-GitHub-Event"},
-		ExposedHeaders:   []string{"Link", "Set-Cookie"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Hub-Signature-256", "X-GitHub-Event", "Idempotency-Key", "X-Grpc-Web", "X-User-Agent", "Connect-Protocol-Version", "If-None-Match"},
+		ExposedHeaders:   []string{"Link", "Set-Cookie", "Idempotent-Replayed", "X-Total-Count", "X-Next-Cursor", "Grpc-Status", "Grpc-Message", "ETag", "X-Kari-Timezone"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -64,6 +138,39 @@ func NewRouter(cfg RouterConfig) *chi.Mux {
 	// 2. API v1 Routing Tree
 	// =========================================================================
 
+	// 🛡️ Stability: Retried POSTs (flaky clients, CI) replay the first outcome
+	// instead of creating duplicate apps, deployments, or ACME orders.
+	idempotent := auth_middleware.Idempotency(cfg.IdempotencyRepo, cfg.Logger)
+
+	// 🧾 Compliance: Every authenticated POST/PUT/PATCH/DELETE lands in the tenant log
+	auditTrail := auth_middleware.AuditTrail(cfg.AuditRepo, cfg.AuditSnapshots, cfg.Logger)
+
+	// 🚧 Read-only maintenance mode: non-admin mutations get a 503
+	maintenance := auth_middleware.Maintenance(cfg.SettingsHandler.Service)
+
+	// 🕒 ?tz= renders response timestamps in an IANA, the caller's or the install's zone
+	displayTimezone := auth_middleware.DisplayTimezone(cfg.TimezoneHandler.Service, cfg.SettingsHandler.Service)
+
+	// Orchestrator probes: legacy single check, liveness, component readiness
+	if cfg.HealthHandler != nil {
+		r.Get("/health", cfg.HealthHandler.Check)
+		r.Get("/health/live", cfg.HealthHandler.Live)
+		r.Get("/health/ready", cfg.HealthHandler.Ready)
+	}
+
+	// 📈 Prometheus scrape endpoint (bearer token)
+	if cfg.MetricsHandler != nil {
+		r.Handle("/metrics", cfg.MetricsHandler)
+	}
+
+	// 🧩 Optional GraphQL: Dashboard aggregation in one round trip; each
+	// resolver checks the permission its data needs. The schema has no
+	// mutations, so read-only maintenance mode does not apply.
+	if cfg.GraphQLHandler != nil {
+		r.With(cfg.AuthMiddleware.RequireAuthentication()).
+			Post("/api/graphql", cfg.GraphQLHandler.ServeHTTP)
+	}
+
 	r.Route("/api/v1", func(r chi.Router) {
 
 		// ---------------------------------------------------------------------
@@ -76,6 +183,9 @@ func NewRouter(cfg RouterConfig) *chi.Mux {
 				r.Post("/test-db", cfg.SetupHandler.TestDB)
 				r.Post("/generate-key", cfg.SetupHandler.GenerateKey)
 				r.Post("/finalize", cfg.SetupHandler.Finalize)
+				r.Get("/progress", cfg.SetupHandler.GetProgress)
+				r.Put("/progress", cfg.SetupHandler.SaveProgress)
+				r.Post("/refresh", cfg.SetupHandler.RefreshToken)
 			})
 		}
 
@@ -83,11 +193,69 @@ func NewRouter(cfg RouterConfig) *chi.Mux {
 		// Public Routes (No Auth Required)
 		// ---------------------------------------------------------------------
 		r.Group(func(r chi.Router) {
-			r.Post("/auth/login", cfg.AuthHandler.Login)
+			// 🛡️ Brute force: Every way to present a credential shares one tight
+			// per-IP bucket, so passkeys are no side door around the password limit
+			signIn := r
+			if cfg.AuthRateLimiter != nil {
+				signIn = r.With(cfg.AuthRateLimiter.Middleware)
+			}
+			signIn.Post("/auth/login", cfg.AuthHandler.Login)
 			r.Post("/auth/refresh", cfg.AuthHandler.Refresh)
-			
+			signIn.Post("/auth/password/expired", cfg.PasswordHandler.ChangeExpired)
+			r.Get("/digest/unsubscribe", cfg.DigestHandler.Unsubscribe)
+			r.Post("/digest/unsubscribe", cfg.DigestHandler.Unsubscribe)
+
+			// Calendar / RSS subscriptions; the token in the URL is the credential
+			r.Get("/feeds/calendar.ics", cfg.FeedHandler.Calendar)
+			r.Get("/feeds/rss", cfg.FeedHandler.RSS)
+			if cfg.PasskeyHandler != nil {
+				signIn.Post("/auth/passkey/login/begin", cfg.PasskeyHandler.BeginLogin)
+				signIn.Post("/auth/passkey/login/finish", cfg.PasskeyHandler.FinishLogin)
+			}
+			// Remote agent join; the one-time token is the credential
+			if cfg.ServerHandler != nil {
+				r.With(maintenance).Post("/servers/enroll", cfg.ServerHandler.Enroll)
+			}
+			r.Get("/maintenance", cfg.SettingsHandler.Maintenance)
+			r.Get("/status/maintenance", cfg.WindowHandler.Status)
+
 			// Webhook now takes an {id} to isolate database lookups
-			r.Post("/webhooks/github/{id}", cfg.AppHandler.HandleGitHubWebhook)
+			r.With(maintenance).Post("/webhooks/github/{id}", cfg.AppHandler.HandleGitHubWebhook)
+			r.With(maintenance).Post("/webhooks/gitlab/{id}", cfg.AppHandler.HandleGitLabWebhook)
+
+			// OAuth provider redirect; the sealed state carries the user identity
+			r.Get("/git/callback/{provider}", cfg.GitHandler.Callback)
+
+			// Slack slash commands; the request signature is the credential
+			r.With(maintenance).Post("/integrations/slack/commands", cfg.ChatOpsHandler.SlackCommand)
+			r.With(maintenance).Post("/integrations/slack/interactions", cfg.ChatOpsHandler.SlackInteraction)
+			r.With(maintenance).Post("/integrations/discord/interactions", cfg.ChatOpsHandler.DiscordInteraction)
+		})
+
+		// ---------------------------------------------------------------------
+		// Account Security (Any signed-in user, view-only operators included,
+		// so it sits outside the mutating scope guard below)
+		// ---------------------------------------------------------------------
+		r.Group(func(r chi.Router) {
+			r.Use(cfg.AuthMiddleware.RequireAuthentication())
+			r.Use(auditTrail)
+			r.Use(maintenance)
+			r.Use(displayTimezone)
+			r.Post("/auth/password", cfg.PasswordHandler.Change)
+			r.Get("/digest/preferences", cfg.DigestHandler.GetPreference)
+			r.Put("/digest/preferences", cfg.DigestHandler.SetPreference)
+			r.Get("/preferences/timezone", cfg.TimezoneHandler.GetPreference)
+			r.Put("/preferences/timezone", cfg.TimezoneHandler.SetPreference)
+			r.Post("/feeds/token", cfg.FeedHandler.IssueToken)
+			r.Delete("/feeds/token", cfg.FeedHandler.RevokeToken)
+			if cfg.PasskeyHandler != nil {
+				r.Route("/auth/passkeys", func(r chi.Router) {
+					r.Get("/", cfg.PasskeyHandler.List)
+					r.Post("/register/begin", cfg.PasskeyHandler.BeginRegistration)
+					r.Post("/register/finish", cfg.PasskeyHandler.FinishRegistration)
+					r.Delete("/{id}", cfg.PasskeyHandler.Delete)
+				})
+			}
 		})
 
 		// ---------------------------------------------------------------------
@@ -95,6 +263,9 @@ func NewRouter(cfg RouterConfig) *chi.Mux {
 		// ---------------------------------------------------------------------
 		r.Group(func(r chi.Router) {
 			r.Use(cfg.AuthMiddleware.RequireAuthentication())
+			r.Use(auditTrail)
+			r.Use(maintenance)
+			r.Use(displayTimezone)
 
 			// --- Mutating Method Guard (Stateless RBAC) ---
 			// 🛡️ Zero-Trust: Even if a specific route forgets a RequirePermission check,
@@ -103,12 +274,12 @@ func NewRouter(cfg RouterConfig) *chi.Mux {
 				return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 					if req.Method == http.MethodPost || req.Method == http.MethodPut ||
 						req.Method == http.MethodDelete || req.Method == http.MethodPatch {
-						
+
 						// The scopes that permit mutation
 						guard := cfg.AuthMiddleware.RequireScope(
 							"domains:write", "domains:delete",
 							"applications:write", "applications:deploy", "applications:delete",
-							"server:manage",
+							"server:manage", "users:manage", "quotas:manage",
 						)
 						guard(next).ServeHTTP(w, req)
 						return
@@ -119,48 +290,404 @@ func NewRouter(cfg RouterConfig) *chi.Mux {
 
 			// --- Domains & SSL ---
 			r.Route("/domains", func(r chi.Router) {
-				r.With(cfg.AuthMiddleware.RequirePermission("domains", "read")).
+				r.With(cfg.AuthMiddleware.RequirePermission("domains", "read"), auth_middleware.ETag).
 					Get("/", cfg.DomainHandler.List)
-				
+
 				r.With(cfg.AuthMiddleware.RequirePermission("domains", "write")).
 					Post("/", cfg.DomainHandler.Create)
-				
+
 				r.With(cfg.AuthMiddleware.RequirePermission("domains", "delete")).
 					Delete("/{id}", cfg.DomainHandler.Delete)
-				
+
 				r.With(cfg.AuthMiddleware.RequirePermission("domains", "write")).
+					With(idempotent).
 					Post("/{id}/ssl", cfg.DomainHandler.ProvisionSSL)
+
+				// 🛡️ Security response headers, rendered into the vhost
+				r.With(cfg.AuthMiddleware.RequirePermission("domains", "read")).
+					Get("/security-headers/presets", cfg.HeadersHandler.Presets)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("domains", "read")).
+					Get("/{id}/security-headers", cfg.HeadersHandler.Get)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("domains", "write")).
+					Put("/{id}/security-headers", cfg.HeadersHandler.Update)
+
+				// 📊 Traffic analytics from the proxy access logs
+				r.With(cfg.AuthMiddleware.RequirePermission("domains", "read")).
+					Get("/{id}/analytics", cfg.AnalyticsHandler.Get)
+			})
+
+			// --- Sites: domain + app + SSL + first deploy as one saga ---
+			r.Route("/sites", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("domains", "write"))
+				r.Use(cfg.AuthMiddleware.RequirePermission("applications", "write"))
+
+				r.With(idempotent).Post("/", cfg.SiteHandler.Create)
+				r.Get("/operations/{id}", cfg.SiteHandler.GetOperation)
+				r.Get("/operations/{id}/events", cfg.SiteHandler.StreamOperation)
 			})
 
 			// --- Applications & Deployments ---
 			r.Route("/applications", func(r chi.Router) {
-				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read"), auth_middleware.ETag).
 					Get("/", cfg.AppHandler.List)
-				
+
 				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					With(idempotent).
 					Post("/", cfg.AppHandler.Create)
-				
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+					Get("/runtimes", cfg.AppHandler.ListRuntimes)
+
 				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
 					Get("/{id}", cfg.AppHandler.GetByID)
-				
+
+				// 🧭 Aggregated detail: live status, last deploy, certificate, usage
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+					Get("/{id}/overview", cfg.OverviewHandler.Get)
+
+				// 🧾 Dependencies of the running build (CycloneDX) and their known vulnerabilities
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+					Get("/{id}/sbom", cfg.SBOMHandler.Get)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+					Get("/{id}/vulnerabilities", cfg.SBOMHandler.ListVulnerabilities)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+					Get("/{id}/licenses", cfg.SBOMHandler.Licenses)
+
 				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
-					With(middleware.ValidateEnvVars).
 					Put("/{id}/env", cfg.AppHandler.UpdateEnv)
-				
+
+				// 🏗️ Build-only variables (build args), never in the running unit
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Put("/{id}/build-env", cfg.AppHandler.UpdateBuildEnv)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Put("/{id}/settings", cfg.AppHandler.UpdateSettings)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Put("/{id}/runtime", cfg.AppHandler.UpdateRuntime)
+
+				// ⚙️ Process types (web / worker / scheduler)
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+					Get("/{id}/processes", cfg.AppHandler.GetProcesses)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Put("/{id}/processes", cfg.AppHandler.UpdateProcesses)
+
+				// ⚖️ Horizontal scaling of the web process
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Put("/{id}/instances", cfg.AppHandler.Scale)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Post("/{id}/restart", cfg.AppHandler.Restart)
+
+				// 🚦 Deploy approvals: gated apps wait for a human before building
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+					Get("/{id}/approval-gate", cfg.ApprovalHandler.GetGate)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Put("/{id}/approval-gate", cfg.ApprovalHandler.SetGate)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Delete("/{id}/approval-gate", cfg.ApprovalHandler.DeleteGate)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+					Get("/{id}/approvals", cfg.ApprovalHandler.List)
+
+				// 🔑 Deploy keys for private repositories (public half only)
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+					Get("/{id}/deploy-key", cfg.DeployKeyHandler.Get)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Post("/{id}/deploy-key", cfg.DeployKeyHandler.Generate)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Delete("/{id}/deploy-key", cfg.DeployKeyHandler.Revoke)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Post("/{id}/git-link", cfg.AppHandler.LinkRepo)
+
+				// 🪣 Per-app object storage
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+					Get("/{id}/bucket", cfg.BucketHandler.Get)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					With(idempotent).
+					Post("/{id}/bucket", cfg.BucketHandler.Provision)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Put("/{id}/bucket/quota", cfg.BucketHandler.UpdateQuota)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "delete")).
+					Delete("/{id}/bucket", cfg.BucketHandler.Delete)
+
+				// 🧬 Integrity & malware scans of the app directory
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+					Get("/{id}/scans", cfg.FileScanHandler.List)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Post("/{id}/scans", cfg.FileScanHandler.Scan)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Post("/{id}/scans/baseline", cfg.FileScanHandler.AcceptChanges)
+
+				// 📶 Monthly transfer accounting & quotas
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+					Get("/{id}/bandwidth", cfg.BandwidthHandler.Get)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Put("/{id}/bandwidth/quota", cfg.BandwidthHandler.SetQuota)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Delete("/{id}/bandwidth/quota", cfg.BandwidthHandler.RemoveQuota)
+
+				// 🐳 Image-based apps
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "write")).
+					Put("/{id}/image", cfg.RegistryHandler.AttachImage)
+
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "deploy")).
+					Post("/{id}/pull", cfg.RegistryHandler.PullImage)
+
 				r.With(cfg.AuthMiddleware.RequirePermission("applications", "deploy")).
+					With(idempotent).
 					Post("/{id}/deploy", cfg.AppHandler.TriggerDeploy)
+
+				// 🧪 One-off branch / build env, streamed live and never stored
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "deploy")).
+					Post("/{id}/deploy/once", cfg.AppHandler.DeployOnce)
+
+				// ⏰ Deploy later (e.g. 03:00); the deploy scheduler queues it when due
+				r.With(cfg.AuthMiddleware.RequirePermission("applications", "deploy")).
+					With(idempotent).
+					Post("/{id}/scheduled-deployments", cfg.ScheduleHandler.Schedule)
+			})
+
+			// --- Deployment Build Logs ---
+			r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+				Get("/deployments/{id}/logs", cfg.DeployLogHandler.List)
+			r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+				Get("/deployments/{id}/logs/download", cfg.DeployLogHandler.Download)
+
+			// --- Scheduled Deployments ---
+			r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+				Get("/scheduled-deployments", cfg.ScheduleHandler.List)
+			r.With(cfg.AuthMiddleware.RequirePermission("applications", "deploy")).
+				Post("/deployments/{id}/cancel", cfg.ScheduleHandler.Cancel)
+
+			// --- Build Artifacts (redeploy without rebuilding) ---
+			r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
+				Get("/deployments/{id}/artifact", cfg.ArtifactHandler.Get)
+			r.With(cfg.AuthMiddleware.RequirePermission("applications", "deploy")).
+				With(idempotent).
+				Post("/deployments/{id}/redeploy", cfg.ArtifactHandler.Redeploy)
+
+			// --- Container Registry Credentials ---
+			// Per-user; passwords are write-only and never returned.
+			r.Route("/registry-credentials", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("applications", "write"))
+				r.Get("/", cfg.RegistryHandler.List)
+				r.Post("/", cfg.RegistryHandler.Create)
+				r.Delete("/{id}", cfg.RegistryHandler.Delete)
+				r.Post("/{id}/test", cfg.RegistryHandler.Test)
+			})
+
+			// --- ChatOps Account Linking ---
+			// Codes link the caller's chat identity; commands then run as the caller.
+			r.Route("/integrations/chat", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("applications", "read"))
+				r.Post("/link-code", cfg.ChatOpsHandler.CreateLinkCode)
+				r.Get("/identities", cfg.ChatOpsHandler.ListIdentities)
+			})
+
+			// --- Deploy Approvals ---
+			// The service also lets server:manage holders decide for any app.
+			r.Route("/deployment-approvals", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("applications", "write"))
+				r.Post("/{id}/approve", cfg.ApprovalHandler.Approve)
+				r.Post("/{id}/reject", cfg.ApprovalHandler.Reject)
+			})
+
+			// --- Outgoing Webhooks ---
+			// Per-user endpoints for Kari events; secrets are shown once on create.
+			r.Route("/outgoing-webhooks", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("applications", "write"))
+				r.Get("/", cfg.WebhookHandler.List)
+				r.Post("/", cfg.WebhookHandler.Create)
+				r.Put("/{id}", cfg.WebhookHandler.Update)
+				r.Delete("/{id}", cfg.WebhookHandler.Delete)
+				r.Post("/{id}/ping", cfg.WebhookHandler.Ping)
+				r.Get("/{id}/deliveries", cfg.WebhookHandler.Deliveries)
+				r.Post("/{id}/deliveries/{deliveryID}/redeliver", cfg.WebhookHandler.Redeliver)
+			})
+
+			// --- Git Provider Integration ---
+			// Connections are per-user; each call only ever uses the caller's own tokens.
+			r.Route("/git", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("applications", "write"))
+				r.Get("/connect/{provider}", cfg.GitHandler.Connect)
+				r.Get("/connections", cfg.GitHandler.ListConnections)
+				r.Delete("/connections/{provider}", cfg.GitHandler.Disconnect)
+				r.Get("/repos", cfg.GitHandler.ListRepos)
+				r.Get("/branches", cfg.GitHandler.ListBranches)
 			})
 
+			// --- Global Search ---
+			// Per-index permission filtering happens inside the handler, so any
+			// authenticated identity may call it and simply sees fewer result kinds.
+			r.Get("/search", cfg.SearchHandler.Search)
+
 			// --- Privacy-First Observability & Audit Logs ---
 			r.With(cfg.AuthMiddleware.RequirePermission("audit_logs", "read")).
 				Get("/audit", cfg.AuditHandler.HandleGetTenantLogs)
 
-			r.With(cfg.AuthMiddleware.RequirePermission("server", "manage")).
+			r.With(cfg.AuthMiddleware.RequirePermission("server", "manage"), auth_middleware.ETag).
 				Get("/admin/alerts", cfg.AuditHandler.HandleGetAdminAlerts)
 
+			r.With(cfg.AuthMiddleware.RequirePermission("server", "manage")).
+				Get("/admin/storage", cfg.StorageHandler.HandleGetTableSizes)
+
+			r.With(cfg.AuthMiddleware.RequirePermission("server", "manage")).
+				Get("/admin/audit/verify", cfg.AuditHandler.HandleVerifyChain)
+
+			r.With(cfg.AuthMiddleware.RequirePermission("server", "manage")).
+				Get("/admin/agent", cfg.AgentHandler.HandleGetStatus)
+
+			// 🔌 gRPC-Web / Connect gateway to allowlisted, read-only agent RPCs
+			r.With(cfg.AuthMiddleware.RequirePermission("server", "manage")).
+				Post("/rpc/kari.agent.v1.SystemAgent/{method}", cfg.AgentRPCHandler.Call)
+
+			// 🖥️ Remote servers: admins mint one-time join tokens for new agents,
+			// or register SSH hosts that run without the Muscle
+			if cfg.ServerHandler != nil {
+				r.With(cfg.AuthMiddleware.RequirePermission("server", "manage")).
+					Get("/servers", cfg.ServerHandler.List)
+				r.With(cfg.AuthMiddleware.RequirePermission("server", "manage")).
+					Post("/servers/enroll-token", cfg.ServerHandler.IssueEnrollToken)
+				r.With(cfg.AuthMiddleware.RequirePermission("server", "manage")).
+					Post("/servers/ssh", cfg.ServerHandler.RegisterSSH)
+			}
+
+			// 🧾 Reseller billing: monthly usage as JSON or CSV
+			r.With(cfg.AuthMiddleware.RequirePermission("server", "manage")).
+				Get("/admin/usage", cfg.UsageHandler.Export)
+
+			// --- Panel Settings (Admin) ---
+			r.Route("/admin/settings", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("server", "manage"))
+				r.Get("/", cfg.SettingsHandler.Get)
+				r.Put("/maintenance", cfg.SettingsHandler.SetMaintenance)
+				r.Put("/passkeys", cfg.SettingsHandler.SetPasskeyPolicy)
+				r.Put("/password-policy", cfg.SettingsHandler.SetPasswordPolicy)
+				r.Put("/timezone", cfg.SettingsHandler.SetTimezone)
+			})
+
+			// --- Planned Maintenance Windows (Admin) ---
+			r.Route("/admin/maintenance-windows", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("server", "manage"))
+				r.Get("/", cfg.WindowHandler.List)
+				r.Post("/", cfg.WindowHandler.Create)
+				r.Delete("/{id}", cfg.WindowHandler.Delete)
+			})
+
+			// --- Crash Reports ---
+			r.Route("/admin/crashes", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("server", "manage"))
+				r.Get("/", cfg.CrashHandler.List)
+				r.Get("/{id}", cfg.CrashHandler.Get)
+			})
+
+			// --- Drift Reconciliation (host resources vs. database) ---
+			r.Route("/admin/drift", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("server", "manage"))
+				r.Get("/", cfg.DriftHandler.List)
+				r.Post("/{id}/adopt", cfg.DriftHandler.Adopt)
+				r.Post("/{id}/cleanup", cfg.DriftHandler.Cleanup)
+			})
+
+			// --- State Change History (the outbox) ---
+			r.With(cfg.AuthMiddleware.RequirePermission("server", "manage")).
+				Get("/admin/state-changes", cfg.HistoryHandler.List)
+
+			// --- Log Redaction Rules (tenant-owned, plus platform-wide for admins) ---
+			r.Route("/redaction-rules", func(r chi.Router) {
+				r.Get("/", cfg.RedactionHandler.List)
+				r.Post("/", cfg.RedactionHandler.Create)
+				r.Delete("/{id}", cfg.RedactionHandler.Delete)
+			})
+
+			r.Route("/admin/redaction-rules", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("server", "manage"))
+				r.Get("/", cfg.RedactionHandler.ListPlatform)
+				r.Post("/", cfg.RedactionHandler.CreatePlatform)
+				r.Delete("/{id}", cfg.RedactionHandler.DeletePlatform)
+			})
+
+			// --- User Management (rank checks happen in the service) ---
+			r.Route("/users", func(r chi.Router) {
+				r.With(cfg.AuthMiddleware.RequirePermission("users", "read")).Get("/", cfg.UserHandler.List)
+				r.With(cfg.AuthMiddleware.RequirePermission("users", "read")).Get("/{id}", cfg.UserHandler.Get)
+				r.With(cfg.AuthMiddleware.RequirePermission("users", "manage")).Post("/", cfg.UserHandler.Create)
+				r.With(cfg.AuthMiddleware.RequirePermission("users", "manage")).Patch("/{id}", cfg.UserHandler.Update)
+				r.With(cfg.AuthMiddleware.RequirePermission("users", "manage")).Delete("/{id}", cfg.UserHandler.Deactivate)
+			})
+
+			// --- Resource Quotas ---
+			r.Get("/quotas/me", cfg.QuotaHandler.Me)
+
+			r.Route("/admin/quotas", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("quotas", "manage"))
+				r.Get("/roles", cfg.QuotaHandler.ListRoles)
+				r.Put("/roles/{id}", cfg.QuotaHandler.SetRole)
+				r.Get("/users/{id}", cfg.QuotaHandler.GetUser)
+				r.Put("/users/{id}/override", cfg.QuotaHandler.SetOverride)
+				r.Delete("/users/{id}/override", cfg.QuotaHandler.RemoveOverride)
+			})
+
+			// --- SIEM Forwarding (Admin) ---
+			r.Route("/admin/audit/sink", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("server", "manage"))
+				r.Get("/", cfg.AuditSinkHandler.Get)
+				r.Put("/", cfg.AuditSinkHandler.Update)
+				r.Post("/test", cfg.AuditSinkHandler.Test)
+			})
+
+			// --- System Profile (Admin): platform-wide app ceilings ---
+			r.Route("/system/profile", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("server", "manage"))
+				r.Get("/", cfg.ProfileHandler.Get)
+				r.Put("/", cfg.ProfileHandler.Update)
+			})
+
+			// --- Stack Registry (Admin): toolchain version lifecycle ---
+			r.Route("/system/stacks", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("server", "manage"))
+				r.Get("/", cfg.StackHandler.List)
+				r.Post("/", cfg.StackHandler.Add)
+				r.Put("/{runtime}/{version}", cfg.StackHandler.SetStatus)
+				r.Delete("/{runtime}/{version}", cfg.StackHandler.Remove)
+				r.Get("/{runtime}/{version}/apps", cfg.StackHandler.Apps)
+				r.Post("/{runtime}/migrate", cfg.StackHandler.Migrate)
+			})
+
+			// --- Effective Configuration (Admin): secrets masked, env file drift ---
+			r.Route("/system/config", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("server", "manage"))
+				r.Get("/", cfg.ConfigHandler.Get)
+			})
+
+			// --- Brain Self-Update (Admin) ---
+			r.Route("/system/update", func(r chi.Router) {
+				r.Use(cfg.AuthMiddleware.RequirePermission("server", "manage"))
+				r.Get("/", cfg.UpdateHandler.Check)
+				r.Post("/", cfg.UpdateHandler.Apply)
+			})
+
 			// --- WebSocket Real-Time Terminal Streaming ---
 			r.With(cfg.AuthMiddleware.RequirePermission("applications", "read")).
-				With(middleware.ValidateTraceID("trace_id")).
+				With(auth_middleware.ValidateTraceID("trace_id")).
 				Get("/ws/deployments/{trace_id}", cfg.WSHandler.StreamDeploymentLogs)
 		})
 	})
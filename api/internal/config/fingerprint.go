@@ -0,0 +1,200 @@
+package config
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// resolved records every variable Load consulted and where its value came
+// from. Load runs once at boot, before anything else reads the config.
+var resolved = map[string]resolvedSetting{}
+
+type resolvedSetting struct {
+	value   string
+	fromEnv bool
+}
+
+func record(key, value string, fromEnv bool) {
+	resolved[key] = resolvedSetting{value: value, fromEnv: fromEnv}
+}
+
+// bootState is the configuration as this process started with it.
+type bootState struct {
+	at          time.Time
+	settings    []domain.EffectiveSetting // Masked
+	fingerprint string
+	file        map[string]string // The env file as of boot; nil if it was missing
+}
+
+func snapshotBoot(envFile string) bootState {
+	keys := make([]string, 0, len(resolved))
+	for k := range resolved {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	h := sha256.New()
+	settings := make([]domain.EffectiveSetting, 0, len(keys))
+	for _, k := range keys {
+		r := resolved[k]
+		h.Write([]byte(k + "=" + r.value + "\n"))
+
+		source := "default"
+		if r.fromEnv {
+			source = "env"
+		}
+		value, secret := mask(k, r.value)
+		settings = append(settings, domain.EffectiveSetting{Key: k, Value: value, Secret: secret, Source: source})
+	}
+
+	file, _ := readEnvFile(envFile)
+	return bootState{
+		at:          time.Now().UTC(),
+		settings:    settings,
+		fingerprint: hex.EncodeToString(h.Sum(nil)),
+		file:        file,
+	}
+}
+
+// Fingerprint identifies the effective configuration this process booted
+// with. Two replicas with the same fingerprint run the same settings.
+func (c *Config) Fingerprint() string {
+	return c.boot.fingerprint
+}
+
+// ConfigReport implements domain.ConfigInspector: the effective settings
+// (secrets masked) and how the env file has drifted from them.
+func (c *Config) ConfigReport() (*domain.ConfigReport, error) {
+	current, err := readEnvFile(c.EnvFile)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := envFileDrift(c.boot.file, current)
+	return &domain.ConfigReport{
+		Fingerprint:     c.boot.fingerprint,
+		BootedAt:        c.boot.at,
+		EnvFile:         c.EnvFile,
+		EnvFileFound:    current != nil,
+		Settings:        c.boot.settings,
+		Drift:           drift,
+		RestartRequired: len(drift) > 0,
+	}, nil
+}
+
+// envFileDrift compares the env file now with the file at boot and with
+// this process's environment, which never changes after start.
+func envFileDrift(boot, current map[string]string) []domain.ConfigDrift {
+	drift := []domain.ConfigDrift{}
+	for _, k := range sortedKeys(current) {
+		v := current[k]
+		loaded, inProcess := os.LookupEnv(k)
+		was, inBoot := boot[k]
+		switch {
+		case inProcess && loaded == v:
+			// Running with exactly what the file says
+		case !inBoot:
+			drift = append(drift, domain.ConfigDrift{Key: k, Kind: domain.ConfigDriftAdded})
+		case was != v:
+			drift = append(drift, domain.ConfigDrift{Key: k, Kind: domain.ConfigDriftChanged})
+		default:
+			drift = append(drift, domain.ConfigDrift{Key: k, Kind: domain.ConfigDriftNotLoaded})
+		}
+	}
+	for _, k := range sortedKeys(boot) {
+		if _, still := current[k]; still {
+			continue
+		}
+		if _, inProcess := os.LookupEnv(k); inProcess {
+			drift = append(drift, domain.ConfigDrift{Key: k, Kind: domain.ConfigDriftRemoved})
+		}
+	}
+	return drift
+}
+
+// readEnvFile parses KEY=VALUE lines the way systemd's EnvironmentFile=
+// does for the files the setup wizard writes. A missing file is nil, nil.
+func readEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	vars := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars[strings.TrimSpace(key)] = value
+	}
+	return vars, scanner.Err()
+}
+
+// mask hides secret values. Connection URLs keep everything but the password,
+// so the report still shows which database the Brain talks to; sealed URLs
+// show nothing.
+func mask(key, value string) (string, bool) {
+	if strings.HasPrefix(value, SealedPrefix) {
+		return "(sealed)", true
+	}
+	if key == "DATABASE_URL" {
+		u, err := url.Parse(value)
+		if err != nil {
+			return "********", true
+		}
+		return u.Redacted(), true
+	}
+	if !isSecret(key) {
+		return value, false
+	}
+	if value == "" {
+		return "", true
+	}
+	return "********", true
+}
+
+func isSecret(key string) bool {
+	if strings.Contains(key, "PUBLIC") {
+		return false // Verification keys (UPDATE_PUBLIC_KEY_HEX, DISCORD_PUBLIC_KEY)
+	}
+	for _, marker := range []string{"SECRET", "PASSWORD", "TOKEN", "_KEY", "DSN"} {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
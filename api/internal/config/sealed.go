@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SealedPrefix marks an env file value encrypted by the Muscle under this
+// machine's key (systemd-creds: TPM2 when present, else the host key).
+const SealedPrefix = "sealed:"
+
+// sealable are the secrets the setup wizard seals.
+func (c *Config) sealable() map[string]*string {
+	return map[string]*string{
+		"DATABASE_URL":   &c.DatabaseURL,
+		"JWT_SECRET":     &c.JWTSecret,
+		"ENCRYPTION_KEY": &c.MasterKeyHex,
+	}
+}
+
+// Sealed returns the ciphertext of every setting still sealed, keyed by name.
+// Empty when the env file holds plaintext (or nothing at all).
+func (c *Config) Sealed() map[string]string {
+	sealed := map[string]string{}
+	for key, field := range c.sealable() {
+		if strings.HasPrefix(*field, SealedPrefix) {
+			sealed[key] = strings.TrimPrefix(*field, SealedPrefix)
+		}
+	}
+	return sealed
+}
+
+// ApplyUnsealed swaps the ciphertext for the plaintext the Muscle returned.
+// The plaintext lives only in this Config; the report and fingerprint keep
+// describing the sealed values.
+func (c *Config) ApplyUnsealed(plain map[string]string) error {
+	fields := c.sealable()
+	for key := range c.Sealed() {
+		value, ok := plain[key]
+		if !ok || value == "" {
+			return fmt.Errorf("%s was not unsealed", key)
+		}
+		*fields[key] = value
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnalyticsRanges are the windows offered by GET /domains/{id}/analytics.
+var AnalyticsRanges = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// AnalyticsRetention bounds how long hourly rollups are kept.
+const AnalyticsRetention = 90 * 24 * time.Hour
+
+// ParseAnalyticsRange maps "7d"-style input to a window; empty means 24h.
+func ParseAnalyticsRange(s string) (time.Duration, error) {
+	if s == "" {
+		s = "24h"
+	}
+	d, ok := AnalyticsRanges[s]
+	if !ok {
+		return 0, fmt.Errorf("%w: range must be one of 24h, 7d, 30d", ErrValidation)
+	}
+	return d, nil
+}
+
+// AccessBucket is one hour of a domain's traffic as reported by the Muscle.
+// Clients are already anonymized and paths stripped of query strings.
+type AccessBucket struct {
+	Hour          time.Time
+	Requests      int64
+	BytesSent     int64
+	BytesReceived int64 // 0 for vhosts still logging the plain combined format
+	StatusClasses map[string]int64
+	Paths         map[string]int64
+	Clients       map[string]int64
+}
+
+// AnalyticsTarget is a domain the collector reads logs for.
+type AnalyticsTarget struct {
+	DomainID   uuid.UUID
+	DomainName string
+	Offset     int64 // Byte cursor into the domain's access log
+}
+
+type AnalyticsPoint struct {
+	Hour          time.Time `json:"hour"`
+	Requests      int64     `json:"requests"`
+	BytesSent     int64     `json:"bytes_sent"`
+	BytesReceived int64     `json:"bytes_received"`
+}
+
+type AnalyticsCount struct {
+	Key  string `json:"key"`
+	Hits int64  `json:"hits"`
+}
+
+type DomainAnalytics struct {
+	DomainID      uuid.UUID        `json:"domain_id"`
+	Range         string           `json:"range"`
+	Since         time.Time        `json:"since"`
+	Requests      int64            `json:"requests"`
+	BytesSent     int64            `json:"bytes_sent"`
+	BytesReceived int64            `json:"bytes_received"`
+	Status        map[string]int64 `json:"status"`
+	Series        []AnalyticsPoint `json:"series"`
+	TopPaths      []AnalyticsCount `json:"top_paths"`
+	TopClients    []AnalyticsCount `json:"top_clients"`
+}
+
+type AccessAnalyticsRepository interface {
+	// ListTargets returns every domain with its current log cursor.
+	ListTargets(ctx context.Context) ([]AnalyticsTarget, error)
+	// RecordBatch merges buckets into the hourly rollups (and the owning
+	// app's monthly bandwidth) and advances the cursor in one transaction,
+	// so a crash never double-counts a read.
+	RecordBatch(ctx context.Context, domainID uuid.UUID, buckets []AccessBucket, nextOffset int64) error
+	// Summarize returns ErrNotFound unless ownerID owns the domain.
+	Summarize(ctx context.Context, domainID, ownerID uuid.UUID, since time.Time, top int) (*DomainAnalytics, error)
+	PruneBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// AccessAnalyticsManager is the tenant-facing analytics API.
+type AccessAnalyticsManager interface {
+	GetAnalytics(ctx context.Context, domainID, userID uuid.UUID, rangeName string) (*DomainAnalytics, error)
+}
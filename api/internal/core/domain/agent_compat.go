@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+// AgentFeature names an RPC capability introduced at a given protocol revision.
+type AgentFeature string
+
+const (
+	AgentFeatureBrainUpdate     AgentFeature = "brain_update"     // ApplyBrainUpdate (rev 2)
+	AgentFeaturePHPRuntime      AgentFeature = "php_runtime"      // DeployRequest.runtime = "php" (rev 3)
+	AgentFeatureRuntimePinning  AgentFeature = "runtime_pinning"  // DeployRequest.runtime_version toolchains (rev 4)
+	AgentFeatureImagePull       AgentFeature = "image_pull"       // PullImage (rev 5)
+	AgentFeatureProcessTypes    AgentFeature = "process_types"    // DeployRequest.processes, GetProcessStatus (rev 6)
+	AgentFeatureInstances       AgentFeature = "instances"        // DeployRequest.instances, per-instance health (rev 7)
+	AgentFeatureRestart         AgentFeature = "restart"          // RestartApp, incl. graceful mode (rev 8)
+	AgentFeatureFileScan        AgentFeature = "file_scan"        // ScanAppFiles (rev 9)
+	AgentFeatureSecurityHeaders AgentFeature = "security_headers" // ApplySecurityHeaders (rev 10)
+	AgentFeatureAccessLogs      AgentFeature = "access_logs"      // CollectAccessLogs (rev 11)
+	AgentFeatureBandwidthLimit  AgentFeature = "bandwidth_limit"  // SetBandwidthLimit (rev 12)
+	AgentFeatureCPUUsage        AgentFeature = "cpu_usage"        // ProcessState.cpu_usage_nsec (rev 13)
+	AgentFeatureInventory       AgentFeature = "inventory"        // ListManagedResources, RemoveManagedResource (rev 14)
+	AgentFeatureBuildEnv        AgentFeature = "build_env"        // DeployRequest.build_env (rev 16)
+	AgentFeatureArtifacts       AgentFeature = "artifacts"        // DeployRequest.artifact_id / reuse_artifact, DeleteArtifacts (rev 17)
+	AgentFeatureSBOM            AgentFeature = "sbom"             // LogChunk.sbom (rev 18)
+	AgentFeatureCapabilities    AgentFeature = "capabilities"     // GetCapabilities (rev 19)
+	AgentFeatureSystemDefaults  AgentFeature = "system_defaults"  // ApplySystemDefaults (rev 20)
+)
+
+// AgentCompatibility is the outcome of the Brain<->Muscle protocol handshake.
+type AgentCompatibility struct {
+	AgentVersion       string    `json:"agent_version"`
+	AgentProtocolMin   uint32    `json:"agent_protocol_min"`
+	AgentProtocolMax   uint32    `json:"agent_protocol_max"`
+	NegotiatedProtocol uint32    `json:"negotiated_protocol"` // 0 when incompatible
+	Compatible         bool      `json:"compatible"`
+	NegotiatedAt       time.Time `json:"negotiated_at"`
+}
+
+// AgentCapabilities lets services degrade gracefully on older Muscles.
+type AgentCapabilities interface {
+	Supports(feature AgentFeature) bool
+}
+
+// AgentLinkStats is a point-in-time view of the Brain's gRPC link to the Muscle.
+type AgentLinkStats struct {
+	ConnState           string    `json:"conn_state"` // gRPC connectivity state
+	Breaker             string    `json:"breaker"`    // closed, open, half_open
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Calls               uint64    `json:"calls"`
+	Failures            uint64    `json:"failures"`
+	Rejected            uint64    `json:"rejected"` // Short-circuited while the breaker was open
+	Reconnects          uint64    `json:"reconnects"`
+	LastStateChange     time.Time `json:"last_state_change"`
+}
+
+// AgentLinkMonitor exposes link health to the admin API.
+type AgentLinkMonitor interface {
+	Stats() AgentLinkStats
+}
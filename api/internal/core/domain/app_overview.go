@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sections of an AppOverview, as named in AppOverview.Unavailable.
+const (
+	OverviewSectionStatus         = "status"
+	OverviewSectionUsage          = "usage"
+	OverviewSectionLastDeployment = "last_deployment"
+	OverviewSectionCertificate    = "certificate"
+)
+
+// AppOverview is the dashboard's single-call detail view of an app: the
+// stored record plus live state gathered from the Muscle and related tables.
+// A section that failed or timed out is nil and listed in Unavailable, so a
+// slow agent never hides the rest of the page.
+type AppOverview struct {
+	Application    *Application        `json:"application"`
+	Status         *AppRuntimeStatus   `json:"status"`
+	Usage          *AppResourceUsage   `json:"usage"`
+	LastDeployment *DeploymentSummary  `json:"last_deployment"` // Also nil when never deployed
+	Certificate    *CertificateSummary `json:"certificate"`     // Also nil when no certificate was issued
+	Unavailable    map[string]string   `json:"unavailable,omitempty"`
+}
+
+// AppRuntimeStatus condenses the app's systemd units into one state:
+// active, failed, degraded (some units down) or, for apps the Muscle does not
+// report per process, the status last recorded by the availability monitor.
+type AppRuntimeStatus struct {
+	State     string          `json:"state"`
+	Processes []ProcessStatus `json:"processes"`
+}
+
+// AppResourceUsage sums the live footprint of every process of the app.
+type AppResourceUsage struct {
+	MemoryBytes uint64 `json:"memory_bytes"`
+	CPUUsageNS  uint64 `json:"cpu_usage_ns"` // Cumulative since each unit last started
+}
+
+// DeploymentSummary is the latest deployment of an app.
+type DeploymentSummary struct {
+	ID        uuid.UUID `json:"id"`
+	Status    string    `json:"status"`
+	Branch    string    `json:"branch"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CertificateSummary is the public state of the app domain's TLS certificate.
+type CertificateSummary struct {
+	Issuer    string    `json:"issuer"`
+	Status    string    `json:"status"` // active, expiring, failed, revoked
+	ExpiresAt time.Time `json:"expires_at"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+type AppOverviewRepository interface {
+	// LastDeployment returns ErrNotFound when the app was never deployed.
+	LastDeployment(ctx context.Context, appID uuid.UUID) (*DeploymentSummary, error)
+	// Certificate returns ErrNotFound when the domain has no certificate.
+	Certificate(ctx context.Context, domainID uuid.UUID) (*CertificateSummary, error)
+}
+
+type AppOverviewReader interface {
+	GetOverview(ctx context.Context, appID uuid.UUID, userID uuid.UUID) (*AppOverview, error)
+}
@@ -8,20 +8,49 @@ import (
 
 // Application represents the core deployment entity.
 type Application struct {
-	ID           uuid.UUID         `json:"id"`
-	DomainID     uuid.UUID         `json:"domain_id"`
-	DomainName   string            `json:"domain_name,omitempty"` // Eagerly loaded for Agent gRPC
-	OwnerID      uuid.UUID         `json:"owner_id"`              // For IDOR & Rank checks
-	AppUser      string            `json:"app_user"`             // OS-level jail identity
-	RepoURL      string            `json:"repo_url"`
-	Branch       string            `json:"branch"`
-	BuildCommand string            `json:"build_command"`
-	StartCommand string            `json:"start_command"`
-	EnvVars      map[string]string `json:"env_vars"`             // JSONB GIN-indexed
-	Port         int               `json:"port"`
-	Status       string            `json:"status"`               // enum: stopped, starting, running, failed
-	CreatedAt    time.Time         `json:"created_at"`
-	UpdatedAt    time.Time         `json:"updated_at"`
+	ID             uuid.UUID              `json:"id"`
+	DomainID       uuid.UUID              `json:"domain_id"`
+	DomainName     string                 `json:"domain_name,omitempty"` // Eagerly loaded for Agent gRPC
+	OwnerID        uuid.UUID              `json:"owner_id"`              // For IDOR & Rank checks
+	ServerID       *uuid.UUID             `json:"server_id,omitempty" db:"server_id"` // nil = the Brain's own host
+	AppUser        string                 `json:"app_user"`              // OS-level jail identity
+	AppUID         *int                   `json:"app_uid,omitempty"`     // From the UID ledger (nil for legacy apps)
+	AppType        string                 `json:"app_type"`              // enum: nodejs, python, go, php, ruby, static, image
+	RuntimeVersion string                 `json:"runtime_version"`       // Pinned toolchain ("" = stack registry default)
+	ImageRef       string                 `json:"image_ref,omitempty"`   // image apps only
+	RegistryCredID *uuid.UUID             `json:"registry_credential_id,omitempty" db:"registry_credential_id"`
+	RepoURL        string                 `json:"repo_url"`
+	Branch         string                 `json:"branch"`
+	BuildCommand   string                 `json:"build_command"`
+	StartCommand   string                 `json:"start_command"`
+	EnvVars        map[string]string      `json:"env_vars"` // JSONB GIN-indexed
+	BuildEnvVars   map[string]string      `json:"build_env_vars" db:"build_env_vars"` // Build step only, never in the running unit
+	Port           int                    `json:"port"`
+	Settings       AppSettings            `json:"settings"`            // Runtime tuning (JSONB)
+	Processes      map[string]ProcessSpec `json:"processes,omitempty"` // Empty = single StartCommand process
+	Instances      int                    `json:"instances"`           // Web copies behind the proxy (1 = unscaled)
+	Status         string                 `json:"status"`              // enum: stopped, starting, running, failed
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+
+	// 🛡️ Privacy: Decrypted push webhook secret, loaded only for verification
+	WebhookSecret []byte `json:"-" db:"-"`
+}
+
+// AppSettings holds per-app runtime tuning. Zero values mean "platform default".
+type AppSettings struct {
+	PHPMaxChildren int `json:"php_max_children,omitempty"` // PHP-FPM pm.max_children
+}
+
+// DefaultPHPMaxChildren sizes a pool for a small WordPress-class site.
+const DefaultPHPMaxChildren = 5
+
+// EffectivePHPMaxChildren resolves the pool size sent to the Muscle.
+func (s AppSettings) EffectivePHPMaxChildren() int {
+	if s.PHPMaxChildren > 0 {
+		return s.PHPMaxChildren
+	}
+	return DefaultPHPMaxChildren
 }
 
 // ApplicationMetadata is a "Value Object" used specifically for high-performance 
@@ -35,19 +64,76 @@ type ApplicationMetadata struct {
 	OwnerRank  int // 🛡️ Injected via SQL Join for Rank-based security
 }
 
+// ApplicationFilter narrows GET /api/v1/applications.
+type ApplicationFilter struct {
+	OwnerID  uuid.UUID // 🛡️ Tenant Isolation: Always set by the handler from the JWT
+	Status   string
+	DomainID uuid.UUID
+}
+
+// AppHealth is the availability monitor's latest probe of an app.
+type AppHealth struct {
+	Up         bool      `json:"up"`
+	StatusCode int       `json:"status_code,omitempty"` // 0 when the listener did not answer
+	LatencyMS  int64     `json:"latency_ms"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// AppHealthSource serves the monitor's last sweep from memory, so list views
+// can show live-looking status without one agent or HTTP call per app.
+type AppHealthSource interface {
+	// LastHealth returns nil for apps the monitor has not probed (yet).
+	LastHealth(appID uuid.UUID) *AppHealth
+}
+
+// UIDRange is the SystemProfile's app_user UID window (inclusive).
+type UIDRange struct {
+	Start int
+	End   int
+}
+
+// UIDRecycleCooldown is how long a torn-down app's UID stays quarantined
+// before the ledger may assign it to a new app.
+const UIDRecycleCooldown = 24 * time.Hour
+
 // ApplicationRepository defines the platform-agnostic contract.
 type ApplicationRepository interface {
-	Create(ctx context.Context, app *Application) error
+	// Create inserts the app and assigns it a UID from uids in one transaction.
+	// Returns ErrConflict when the range is exhausted.
+	Create(ctx context.Context, app *Application, uids UIDRange) error
 	
+	// List returns one keyset page of the owner's applications
+	List(ctx context.Context, filter ApplicationFilter, page PageRequest) (Page[Application], error)
+
 	// GetByID handles standard tenant-isolated lookups
 	GetByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*Application, error)
 	
+	// GetByIDSystem skips the ownership filter. Only for callers that
+	// authenticate otherwise, such as a signed push webhook.
+	GetByIDSystem(ctx context.Context, id uuid.UUID) (*Application, error)
+
+	// ListAllActive returns every non-stopped app across tenants, for
+	// background sweeps (availability monitor, file scanner). Never expose it to a handler.
+	ListAllActive(ctx context.Context) ([]Application, error)
+
 	// GetByIDWithMetadata supports the Rank-Based Deletion flow
 	GetByIDWithMetadata(ctx context.Context, id uuid.UUID) (*ApplicationMetadata, error)
 	
 	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
 	UpdateEnvVars(ctx context.Context, id uuid.UUID, envVars map[string]string) error
+	UpdateBuildEnvVars(ctx context.Context, id uuid.UUID, envVars map[string]string) error
+	UpdateSettings(ctx context.Context, id uuid.UUID, settings AppSettings) error
+	UpdateRuntimeVersion(ctx context.Context, id uuid.UUID, version string) error
+	SetWebhookSecret(ctx context.Context, id uuid.UUID, ciphertext string) error
+	GetWebhookSecret(ctx context.Context, id uuid.UUID) (string, error)
+	SetImage(ctx context.Context, id uuid.UUID, image string, credentialID *uuid.UUID) error
+	UpdateProcesses(ctx context.Context, id uuid.UUID, procs map[string]ProcessSpec) error
+	UpdateInstances(ctx context.Context, id uuid.UUID, instances int) error
 	
-	// Delete handles the atomic removal of the record
+	// Delete handles the atomic removal of the record. Call it only after the
+	// Muscle confirmed teardown: it quarantines the app's UID for recycling.
 	Delete(ctx context.Context, id uuid.UUID) error
 }
+
+// SagaKindAppDelete is the saga kind behind an application teardown.
+const SagaKindAppDelete = "application.delete"
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Deployment states added by the approval gate. A gated deployment is saved
+// as AWAITING_APPROVAL, which the worker never claims; approval flips it to
+// PENDING, rejection to REJECTED.
+const (
+	StatusAwaitingApproval Status = "AWAITING_APPROVAL"
+	StatusRejected         Status = "REJECTED"
+)
+
+// ApprovalGate makes every deployment of an app wait for a human decision.
+// Channel IDs say where the interactive approval message is posted.
+type ApprovalGate struct {
+	AppID            uuid.UUID `json:"app_id"`
+	SlackChannelID   *string   `json:"slack_channel_id,omitempty"`
+	DiscordChannelID *string   `json:"discord_channel_id,omitempty"`
+	CreatedBy        uuid.UUID `json:"created_by"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type ApprovalStatus string
+
+const (
+	ApprovalPending  ApprovalStatus = "pending"
+	ApprovalApproved ApprovalStatus = "approved"
+	ApprovalRejected ApprovalStatus = "rejected"
+)
+
+// DeploymentApproval is the decision record for one gated deployment.
+type DeploymentApproval struct {
+	ID           uuid.UUID      `json:"id"`
+	DeploymentID string         `json:"deployment_id"`
+	AppID        uuid.UUID      `json:"app_id"`
+	DomainName   string         `json:"domain_name"`
+	Branch       string         `json:"branch"`
+	Status       ApprovalStatus `json:"status"`
+	DecidedBy    *uuid.UUID     `json:"decided_by,omitempty"`
+	DecidedVia   *string        `json:"decided_via,omitempty"` // api | slack | discord
+	CreatedAt    time.Time      `json:"created_at"`
+	DecidedAt    *time.Time     `json:"decided_at,omitempty"`
+}
+
+type ApprovalRepository interface {
+	GetGate(ctx context.Context, appID uuid.UUID) (*ApprovalGate, error)
+	SetGate(ctx context.Context, gate *ApprovalGate) error
+	DeleteGate(ctx context.Context, appID uuid.UUID) error
+
+	Create(ctx context.Context, a *DeploymentApproval) error
+	Get(ctx context.Context, id uuid.UUID) (*DeploymentApproval, error)
+	ListByApp(ctx context.Context, appID uuid.UUID, limit int) ([]DeploymentApproval, error)
+	// Decide settles a pending approval and releases (PENDING) or cancels
+	// (REJECTED) its deployment in one transaction. ErrConflict when the
+	// approval was already decided.
+	Decide(ctx context.Context, id uuid.UUID, status ApprovalStatus, deciderID uuid.UUID, via string) (*DeploymentApproval, error)
+}
+
+// ApprovalNotifier posts an interactive approve/reject message to a channel.
+type ApprovalNotifier interface {
+	RequestApproval(ctx context.Context, channelID string, a *DeploymentApproval) error
+}
+
+// ApprovalManager is the contract behind the approval endpoints and chat buttons.
+type ApprovalManager interface {
+	GetGate(ctx context.Context, appID uuid.UUID, userID uuid.UUID) (*ApprovalGate, error)
+	SetGate(ctx context.Context, appID uuid.UUID, userID uuid.UUID, gate *ApprovalGate) (*ApprovalGate, error)
+	DeleteGate(ctx context.Context, appID uuid.UUID, userID uuid.UUID) error
+	ListApprovals(ctx context.Context, appID uuid.UUID, userID uuid.UUID) ([]DeploymentApproval, error)
+	Decide(ctx context.Context, id uuid.UUID, userID uuid.UUID, approve bool, via string) (*DeploymentApproval, error)
+}
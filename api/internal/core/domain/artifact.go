@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeploymentArtifact is the archived release of one successful build. The
+// tarball stays on the Muscle; the Brain keeps its checksum so a redeploy can
+// prove it unpacks exactly what was built.
+type DeploymentArtifact struct {
+	DeploymentID string     `json:"deployment_id"`
+	AppID        string     `json:"app_id"`
+	DomainName   string     `json:"domain_name"`
+	Branch       string     `json:"branch"`
+	SHA256       string     `json:"sha256"`
+	SizeBytes    int64      `json:"size_bytes"`
+	CreatedAt    time.Time  `json:"created_at"`
+	PrunedAt     *time.Time `json:"pruned_at,omitempty"` // Deleted from the Muscle; no longer redeployable
+}
+
+// ArtifactRetention decides which artifacts the pruner deletes: everything
+// beyond the newest KeepPerApp of an app, and anything older than MaxAge. The
+// newest artifact of an app, and any a queued redeploy needs, are always kept.
+type ArtifactRetention struct {
+	KeepPerApp int
+	MaxAge     time.Duration // 0 prunes by count only
+}
+
+type ArtifactRepository interface {
+	Record(ctx context.Context, a *DeploymentArtifact) error
+	// Get returns ErrNotFound for deployments without an artifact.
+	Get(ctx context.Context, deploymentID string) (*DeploymentArtifact, error)
+	// LinkRedeploy marks a not-yet-saved deployment as unpacking sourceDeploymentID.
+	LinkRedeploy(ctx context.Context, deploymentID string, sourceDeploymentID string, by uuid.UUID) error
+	UnlinkRedeploy(ctx context.Context, deploymentID string) error
+	// RedeploySource returns ErrNotFound for regular (building) deployments.
+	RedeploySource(ctx context.Context, deploymentID string) (*DeploymentArtifact, error)
+	// ListPrunable returns unpruned artifacts outside the retention policy, oldest first.
+	ListPrunable(ctx context.Context, policy ArtifactRetention, now time.Time, limit int) ([]DeploymentArtifact, error)
+	MarkPruned(ctx context.Context, deploymentIDs []string) error
+}
+
+// Redeployer is the contract behind POST /deployments/{id}/redeploy.
+type Redeployer interface {
+	GetArtifact(ctx context.Context, deploymentID string, userID uuid.UUID) (*DeploymentArtifact, error)
+	// Redeploy queues a new deployment that unpacks deploymentID's artifact.
+	Redeploy(ctx context.Context, deploymentID string, userID uuid.UUID) (*Deployment, error)
+}
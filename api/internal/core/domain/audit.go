@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ==============================================================================
+// 1. Action Center Alerts (Platform-wide, admin-facing)
+// ==============================================================================
+
+// SystemAlert is a persisted operational event surfaced in the Action Center.
+type SystemAlert struct {
+	ID         uuid.UUID      `json:"id" db:"id"`
+	Severity   string         `json:"severity" db:"severity"` // enum: info, warning, critical, fatal
+	Category   string         `json:"category" db:"category"` // e.g. ssl, system, gitops
+	ResourceID *string        `json:"resource_id,omitempty" db:"resource_id"`
+	Message    string         `json:"message" db:"message"`
+	IsResolved bool           `json:"is_resolved" db:"is_resolved"`
+	Metadata   map[string]any `json:"metadata" db:"metadata"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+}
+
+// AlertFilter narrows the Action Center feed.
+type AlertFilter struct {
+	ResourceID uuid.UUID
+	Severity   string
+	Category   string
+	IsResolved *bool
+	TraceID    string
+	Page       PageRequest
+}
+
+// ==============================================================================
+// 2. Tenant Logs (Per-tenant audit trail)
+// ==============================================================================
+
+// TenantLog records a user-visible action against a tenant's resources.
+type TenantLog struct {
+	ID           uuid.UUID      `json:"id" db:"id"`
+	TenantID     uuid.UUID      `json:"tenant_id" db:"tenant_id"` // Owner of the affected resource
+	ActorID      *uuid.UUID     `json:"actor_id,omitempty" db:"actor_id"`
+	Action       string         `json:"action" db:"action"` // e.g. application.deploy
+	ResourceType string         `json:"resource_type" db:"resource_type"`
+	ResourceID   string         `json:"resource_id" db:"resource_id"`
+	Metadata     map[string]any `json:"metadata" db:"metadata"`
+	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
+
+	// 🛡️ Integrity Chain: RowHash = SHA-256(PrevHash + row content)
+	Seq      int64   `json:"seq" db:"chain_seq"`
+	PrevHash *string `json:"prev_hash,omitempty" db:"prev_hash"` // nil for legacy (pre-chain) rows
+	RowHash  *string `json:"row_hash,omitempty" db:"row_hash"`
+}
+
+// GenesisHash is the PrevHash of the first chained row.
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// ComputeHash derives the row's chain hash from prevHash and every persisted field.
+// Fields are NUL-separated so ("ab","c") and ("a","bc") never collide.
+func (l *TenantLog) ComputeHash(prevHash string) string {
+	actor := ""
+	if l.ActorID != nil {
+		actor = l.ActorID.String()
+	}
+
+	h := sha256.New()
+	for _, part := range []string{
+		prevHash,
+		strconv.FormatInt(l.Seq, 10),
+		l.ID.String(),
+		l.TenantID.String(),
+		actor,
+		l.Action,
+		l.ResourceType,
+		l.ResourceID,
+		string(canonicalJSON(l.Metadata)),
+		l.CreatedAt.UTC().Format(time.RFC3339Nano),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalJSON renders metadata exactly as it will read back from JSONB:
+// a round-trip through map[string]any normalizes numbers and typed values
+// (e.g. uuid.UUID -> string), and encoding/json sorts map keys.
+func canonicalJSON(m map[string]any) []byte {
+	if len(m) == 0 {
+		return []byte("{}")
+	}
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return []byte("{}")
+	}
+	var normalized any
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return raw
+	}
+	out, _ := json.Marshal(normalized)
+	return out
+}
+
+// ChainReport is the result of walking the tenant_logs hash chain.
+type ChainReport struct {
+	Valid       bool      `json:"valid"`
+	RowsChecked int64     `json:"rows_checked"`
+	LegacyRows  int64     `json:"legacy_rows"` // Rows written before chaining was enabled
+	AnchorSeq   int64     `json:"anchor_seq"`  // First verified row (older rows may be archived)
+	HeadSeq     int64     `json:"head_seq"`
+	BrokenAtSeq int64     `json:"broken_at_seq,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	VerifiedAt  time.Time `json:"verified_at"`
+}
+
+// Fail marks the report invalid at seq and returns it.
+func (r *ChainReport) Fail(seq int64, reason string) *ChainReport {
+	r.Valid = false
+	r.BrokenAtSeq = seq
+	r.Reason = reason
+	return r
+}
+
+// AuditVerifier checks the integrity of the audit trail.
+type AuditVerifier interface {
+	VerifyTenantLogChain(ctx context.Context) (*ChainReport, error)
+}
+
+// TenantLogFilter narrows the tenant audit trail.
+// 🛡️ Tenant Isolation: TenantID is forced to the caller for non-admins.
+type TenantLogFilter struct {
+	TenantID     uuid.UUID
+	ActorID      uuid.UUID
+	Action       string
+	ResourceType string
+	TraceID      string // Request ID of the API call that caused the entry
+	Page         PageRequest
+}
+
+// ==============================================================================
+// 3. Repository Contract
+// ==============================================================================
+
+type AuditRepository interface {
+	CreateAlert(ctx context.Context, alert *SystemAlert) error
+	GetFilteredAlerts(ctx context.Context, filter AlertFilter) (Page[SystemAlert], error)
+	ResolveAlert(ctx context.Context, alertID uuid.UUID, resolverID uuid.UUID) error
+
+	// CreateTenantLog appends to the hash chain; ID, Seq, hashes and CreatedAt are assigned.
+	CreateTenantLog(ctx context.Context, entry *TenantLog) error
+	GetTenantLogs(ctx context.Context, filter TenantLogFilter) (Page[TenantLog], error)
+
+	// Integrity chain traversal (ordered by Seq)
+	TenantLogChainPage(ctx context.Context, afterSeq int64, limit int) ([]TenantLog, error)
+	TenantLogChainHead(ctx context.Context) (seq int64, hash string, err error)
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ==============================================================================
+// 1. Wire Format
+// ==============================================================================
+
+// AuditEvent is the structured, SIEM-facing representation of a tenant log row.
+// 🛡️ SLA: Field names are part of the external contract with customers' SIEM
+// parsers; treat them as append-only.
+type AuditEvent struct {
+	ID           uuid.UUID      `json:"id"`
+	Seq          int64          `json:"seq"`
+	Timestamp    time.Time      `json:"timestamp"`
+	TenantID     uuid.UUID      `json:"tenant_id"`
+	ActorID      *uuid.UUID     `json:"actor_id,omitempty"`
+	Action       string         `json:"action"`
+	ResourceType string         `json:"resource_type"`
+	ResourceID   string         `json:"resource_id"`
+	Metadata     map[string]any `json:"metadata,omitempty"`
+	Hash         string         `json:"hash,omitempty"` // Chain hash for cross-checking against Postgres
+}
+
+// NewAuditEvent projects a persisted tenant log into the wire format.
+func NewAuditEvent(l *TenantLog) AuditEvent {
+	ev := AuditEvent{
+		ID:           l.ID,
+		Seq:          l.Seq,
+		Timestamp:    l.CreatedAt,
+		TenantID:     l.TenantID,
+		ActorID:      l.ActorID,
+		Action:       l.Action,
+		ResourceType: l.ResourceType,
+		ResourceID:   l.ResourceID,
+		Metadata:     l.Metadata,
+	}
+	if l.RowHash != nil {
+		ev.Hash = *l.RowHash
+	}
+	return ev
+}
+
+// ==============================================================================
+// 2. Sink Contracts
+// ==============================================================================
+
+// AuditSinkKind selects the remote transport.
+type AuditSinkKind string
+
+const (
+	AuditSinkSyslog    AuditSinkKind = "syslog"     // RFC 5424 over UDP, TCP, or TLS
+	AuditSinkSplunkHEC AuditSinkKind = "splunk_hec" // Splunk HTTP Event Collector
+	AuditSinkHTTPS     AuditSinkKind = "https"      // Generic JSON webhook
+)
+
+// AuditSinkConfig is the admin-managed forwarding target.
+type AuditSinkConfig struct {
+	Enabled   bool          `json:"enabled"`
+	Kind      AuditSinkKind `json:"kind"`
+	Endpoint  string        `json:"endpoint"`          // host:port for syslog, URL otherwise
+	Network   string        `json:"network,omitempty"` // syslog only: udp, tcp, tls
+	Token     string        `json:"-"`                 // 🛡️ Write-only: never serialized back to the UI
+	HasToken  bool          `json:"has_token"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// AuditSink forwards batches of audit events to an external system.
+type AuditSink interface {
+	Send(ctx context.Context, events []AuditEvent) error
+	Close() error
+}
+
+// AuditEventPublisher accepts events for asynchronous forwarding.
+// Publish must never block the request path.
+type AuditEventPublisher interface {
+	Publish(ev AuditEvent)
+}
+
+// AuditSinkConfigRepository persists the single forwarding configuration.
+// The token is stored encrypted; repositories only ever see ciphertext.
+type AuditSinkConfigRepository interface {
+	Get(ctx context.Context) (cfg *AuditSinkConfig, tokenCiphertext string, err error)
+	Save(ctx context.Context, cfg *AuditSinkConfig, tokenCiphertext string) error
+}
+
+// AuditSinkManager is the admin-facing contract behind /admin/audit/sink.
+type AuditSinkManager interface {
+	GetConfig(ctx context.Context) (*AuditSinkConfig, error)
+	UpdateConfig(ctx context.Context, cfg *AuditSinkConfig) error
+	SendTestEvent(ctx context.Context, tenantID, actorID uuid.UUID) error
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Transfer quota actions taken once an app's monthly transfer passes its quota.
+const (
+	QuotaActionAlert    = "alert"
+	QuotaActionThrottle = "throttle" // Also alerts; the proxy caps each connection
+)
+
+// Quota enforcement states, reset at the start of every month.
+const (
+	QuotaStateOK        = "ok"
+	QuotaStateAlerted   = "alerted"
+	QuotaStateThrottled = "throttled"
+)
+
+const DefaultThrottleKBps = 256
+
+// BandwidthUsage is one month of an app's proxied traffic.
+type BandwidthUsage struct {
+	Month    time.Time `json:"month"` // First day of the month, UTC
+	BytesIn  int64     `json:"bytes_in"`
+	BytesOut int64     `json:"bytes_out"`
+}
+
+// Total is what counts against the transfer quota.
+func (u BandwidthUsage) Total() int64 {
+	return u.BytesIn + u.BytesOut
+}
+
+type TransferQuota struct {
+	QuotaGB      int    `json:"quota_gb"`
+	Action       string `json:"action"`
+	ThrottleKBps int    `json:"throttle_kbps"`
+	State        string `json:"state"`
+}
+
+// QuotaBytes converts the configured quota to bytes (GiB).
+func (q TransferQuota) QuotaBytes() int64 {
+	return int64(q.QuotaGB) << 30
+}
+
+// QuotaStatus is a quota joined with the month's usage, for enforcement.
+type QuotaStatus struct {
+	AppID         uuid.UUID
+	OwnerID       uuid.UUID
+	Domains       []string
+	Quota         TransferQuota
+	EnforcedMonth *time.Time // Month the current State applies to
+	Used          int64
+}
+
+type AppBandwidth struct {
+	AppID uuid.UUID        `json:"app_id"`
+	Quota *TransferQuota   `json:"quota,omitempty"`
+	Usage []BandwidthUsage `json:"usage"` // Newest month first
+}
+
+// CurrentMonth is the accounting month containing t.
+func CurrentMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+type BandwidthRepository interface {
+	ListUsage(ctx context.Context, appID uuid.UUID, months int) ([]BandwidthUsage, error)
+	// GetQuota returns ErrNotFound when the app has no quota.
+	GetQuota(ctx context.Context, appID uuid.UUID) (*TransferQuota, error)
+	UpsertQuota(ctx context.Context, appID uuid.UUID, q TransferQuota) error
+	DeleteQuota(ctx context.Context, appID uuid.UUID) error
+	// QuotaStatuses returns every quota (or just appID's when set) with usage for month.
+	QuotaStatuses(ctx context.Context, month time.Time, appID *uuid.UUID) ([]QuotaStatus, error)
+	SetQuotaState(ctx context.Context, appID uuid.UUID, state string, month time.Time) error
+}
+
+// BandwidthManager is the tenant-facing transfer accounting API.
+type BandwidthManager interface {
+	GetBandwidth(ctx context.Context, appID, userID uuid.UUID) (*AppBandwidth, error)
+	SetQuota(ctx context.Context, appID, userID uuid.UUID, q TransferQuota) (*TransferQuota, error)
+	RemoveQuota(ctx context.Context, appID, userID uuid.UUID) error
+}
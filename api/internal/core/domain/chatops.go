@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ChatProviderSlack   = "slack"
+	ChatProviderDiscord = "discord"
+)
+
+// ChatLinkCodeTTL is how long a code from POST /integrations/chat/link-code
+// stays redeemable with "/kari link CODE".
+const ChatLinkCodeTTL = 10 * time.Minute
+
+// ChatIdentity links one chat workspace user to a Kari user.
+type ChatIdentity struct {
+	Provider       string    `json:"provider"`
+	TeamID         string    `json:"team_id"`
+	ExternalUserID string    `json:"external_user_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ChatCommand is a verified slash command, normalized across providers.
+type ChatCommand struct {
+	Provider       string
+	TeamID         string
+	ExternalUserID string
+	Text           string // Everything after the command name, e.g. "deploy example.com"
+	ResponseURL    string // Where follow-up messages go once the initial reply is sent
+}
+
+// Button identifiers on approval messages (Slack action_id, Discord custom_id
+// prefix before ":<approval id>").
+const (
+	ChatApproveAction = "kari_approve"
+	ChatRejectAction  = "kari_reject"
+)
+
+// ChatAction is a verified button click on an approval message.
+type ChatAction struct {
+	Provider       string
+	TeamID         string
+	ExternalUserID string
+	ApprovalID     uuid.UUID
+	Approve        bool
+	ResponseURL    string // Slack only; Discord updates the message in the interaction response
+}
+
+// ChatReply is the immediate answer to a command. Ephemeral replies are only
+// shown to the caller; Replace swaps out the message that was clicked.
+type ChatReply struct {
+	Text      string
+	Ephemeral bool
+	Replace   bool
+}
+
+type ChatOpsRepository interface {
+	// Resolve returns ErrNotFound for unlinked chat users.
+	Resolve(ctx context.Context, provider, teamID, externalUserID string) (uuid.UUID, error)
+	Link(ctx context.Context, identity *ChatIdentity) error
+	Unlink(ctx context.Context, provider, teamID, externalUserID string) error
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]ChatIdentity, error)
+
+	CreateLinkCode(ctx context.Context, codeHash string, userID uuid.UUID, expiresAt time.Time) error
+	// ConsumeLinkCode deletes the code and returns its user; ErrNotFound when
+	// unknown or expired.
+	ConsumeLinkCode(ctx context.Context, codeHash string) (uuid.UUID, error)
+}
+
+// ChatResponder posts a follow-up message to a command's response URL.
+type ChatResponder interface {
+	Respond(ctx context.Context, responseURL string, reply ChatReply) error
+}
+
+// ChatOps is the contract behind the chat integration endpoints.
+type ChatOps interface {
+	IssueLinkCode(ctx context.Context, userID uuid.UUID) (code string, expiresAt time.Time, err error)
+	ListIdentities(ctx context.Context, userID uuid.UUID) ([]ChatIdentity, error)
+	HandleCommand(ctx context.Context, cmd ChatCommand) ChatReply
+	HandleAction(ctx context.Context, action ChatAction) ChatReply
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// EffectiveSetting is one configuration value as the running Brain resolved
+// it. Secrets are masked before they ever leave the config package.
+type EffectiveSetting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Secret bool   `json:"secret,omitempty"`
+	Source string `json:"source"` // "env" or "default"
+}
+
+// ConfigDriftKind says how the env file and the running process disagree.
+type ConfigDriftKind string
+
+const (
+	ConfigDriftChanged   ConfigDriftKind = "changed"    // Edited since boot
+	ConfigDriftAdded     ConfigDriftKind = "added"      // New since boot
+	ConfigDriftRemoved   ConfigDriftKind = "removed"    // Gone from the file, still in the process
+	ConfigDriftNotLoaded ConfigDriftKind = "not_loaded" // Unchanged since boot, but the process never received it
+)
+
+// ConfigDrift names a key only: values may be secrets.
+type ConfigDrift struct {
+	Key  string          `json:"key"`
+	Kind ConfigDriftKind `json:"kind"`
+}
+
+// ConfigReport is what GET /system/config shows.
+type ConfigReport struct {
+	Fingerprint     string             `json:"fingerprint"` // SHA-256 of the effective settings at boot
+	BootedAt        time.Time          `json:"booted_at"`
+	EnvFile         string             `json:"env_file"`
+	EnvFileFound    bool               `json:"env_file_found"`
+	Settings        []EffectiveSetting `json:"settings"`
+	Drift           []ConfigDrift      `json:"drift"`
+	RestartRequired bool               `json:"restart_required"`
+}
+
+// ConfigInspector compares the running configuration with the env file
+// on disk. Implemented by config.Config.
+type ConfigInspector interface {
+	ConfigReport() (*ConfigReport, error)
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Crash report sources.
+const (
+	CrashSourceHTTP   = "http"
+	CrashSourceWorker = "worker"
+)
+
+// CrashRequest is the request context captured when a handler panics.
+// 🛡️ Zero-Trust: Headers are not stored; they carry tokens and cookies.
+type CrashRequest struct {
+	RequestID  string `json:"request_id,omitempty"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	RemoteAddr string `json:"remote_addr,omitempty"`
+	UserAgent  string `json:"user_agent,omitempty"`
+}
+
+// CrashLogLine is one entry of the in-memory log ring attached to a report.
+type CrashLogLine struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"msg"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// CrashReport is a recovered panic with everything needed to debug it later.
+type CrashReport struct {
+	ID         uuid.UUID      `json:"id"`
+	Source     string         `json:"source"`    // CrashSourceHTTP or CrashSourceWorker
+	Component  string         `json:"component"` // Route pattern or worker name
+	Panic      string         `json:"panic"`
+	Stack      string         `json:"stack"`
+	Request    *CrashRequest  `json:"request,omitempty"`
+	RecentLogs []CrashLogLine `json:"recent_logs"`
+	ExportedAt *time.Time     `json:"exported_at,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+type CrashRepository interface {
+	Create(ctx context.Context, c *CrashReport) error
+	// List returns the newest reports first, without stacks and logs.
+	List(ctx context.Context, limit int) ([]CrashReport, error)
+	Get(ctx context.Context, id uuid.UUID) (*CrashReport, error)
+	MarkExported(ctx context.Context, id uuid.UUID) error
+}
+
+// CrashReporter is called from recover() sites. It must never panic and
+// must not depend on the (possibly cancelled) context of the crashed work.
+type CrashReporter interface {
+	Report(source, component string, recovered any, stack []byte, req *CrashRequest)
+}
+
+// CrashReportReader backs the admin crash report endpoints.
+type CrashReportReader interface {
+	List(ctx context.Context, limit int) ([]CrashReport, error)
+	Get(ctx context.Context, id uuid.UUID) (*CrashReport, error)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeployKey is an app's SSH keypair for cloning private repositories.
+// The user registers PublicKey as a read-only deploy key on GitHub/GitLab.
+type DeployKey struct {
+	AppID       uuid.UUID `json:"app_id"`
+	PublicKey   string    `json:"public_key"`  // authorized_keys line
+	Fingerprint string    `json:"fingerprint"` // SHA256:...
+	CreatedAt   time.Time `json:"created_at"`
+
+	// 🛡️ Privacy: Never serialized; AAD = AppID
+	PrivateKeyCiphertext string `json:"-"`
+}
+
+// DeployKeyManager is the user-facing deploy key lifecycle.
+// 🛡️ Tenant Isolation: Every call is scoped to an app the user owns.
+type DeployKeyManager interface {
+	// GenerateDeployKey creates the app's keypair, replacing any previous one.
+	GenerateDeployKey(ctx context.Context, appID, userID uuid.UUID) (*DeployKey, error)
+	GetDeployKey(ctx context.Context, appID, userID uuid.UUID) (*DeployKey, error)
+	RevokeDeployKey(ctx context.Context, appID, userID uuid.UUID) error
+}
+
+// DeployKeySource hands the decrypted private key to the clone path.
+// Returns "" with a nil error when the app has no deploy key.
+type DeployKeySource interface {
+	PrivateKeyFor(ctx context.Context, appID uuid.UUID) (string, error)
+}
+
+type DeployKeyRepository interface {
+	// Upsert stores the key, replacing the app's previous one (rotation)
+	Upsert(ctx context.Context, key *DeployKey) error
+	GetByAppID(ctx context.Context, appID uuid.UUID) (*DeployKey, error)
+	Delete(ctx context.Context, appID uuid.UUID) error
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxOverrideBranchLength = 100
+	maxOverrideBuildEnv     = 50
+)
+
+var (
+	buildEnvKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,127}$`) // Same rule as envkey
+	branchPattern      = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+)
+
+// DeployOverrides apply to a single deployment and are never persisted:
+// "deploy branch X with DEBUG=1 once". Zero values keep the app's own config.
+type DeployOverrides struct {
+	Branch   string            `json:"branch,omitempty"`
+	BuildEnv map[string]string `json:"build_env,omitempty"` // Over the app's build env, build step only
+}
+
+// IsZero reports whether the deployment runs with the app's stored config.
+func (o DeployOverrides) IsZero() bool {
+	return o.Branch == "" && len(o.BuildEnv) == 0
+}
+
+// Validate re-checks what the HTTP DTO already enforces, since chat commands
+// reach the service without one.
+func (o DeployOverrides) Validate() error {
+	if o.Branch != "" {
+		if len(o.Branch) > maxOverrideBranchLength || !branchPattern.MatchString(o.Branch) || strings.Contains(o.Branch, "..") {
+			return fmt.Errorf("%w: invalid branch name %q", ErrValidation, o.Branch)
+		}
+	}
+	if len(o.BuildEnv) > maxOverrideBuildEnv {
+		return fmt.Errorf("%w: at most %d build variables", ErrValidation, maxOverrideBuildEnv)
+	}
+	for k := range o.BuildEnv {
+		if !buildEnvKeyPattern.MatchString(k) {
+			return fmt.Errorf("%w: invalid build variable name %q", ErrValidation, k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Deployment statuses for queued-for-later builds.
+const (
+	StatusScheduled Status = "SCHEDULED"
+	StatusCancelled Status = "CANCELLED"
+)
+
+// MaxScheduleAhead bounds scheduled_at so forgotten deployments do not build
+// months later against whatever the branch has become.
+const MaxScheduleAhead = 30 * 24 * time.Hour
+
+// ScheduledDeployment is a deployment waiting for its scheduled_at.
+type ScheduledDeployment struct {
+	ID          string     `json:"id"`
+	AppID       string     `json:"app_id"`
+	DomainName  string     `json:"domain_name"`
+	Branch      string     `json:"branch"`
+	Status      Status     `json:"status"` // SCHEDULED, or AWAITING_APPROVAL when gated
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	ScheduledBy *uuid.UUID `json:"scheduled_by,omitempty"`
+}
+
+type DeploymentScheduleRepository interface {
+	SetSchedule(ctx context.Context, deploymentID string, at time.Time, by uuid.UUID) error
+	// Get returns ErrNotFound for deployments that were never scheduled.
+	Get(ctx context.Context, deploymentID string) (*ScheduledDeployment, error)
+	// ListWaiting returns deployments still before their time, soonest first;
+	// a nil scheduledBy lists everyone's.
+	ListWaiting(ctx context.Context, scheduledBy *uuid.UUID, limit int) ([]ScheduledDeployment, error)
+	// Cancel returns ErrConflict once the deployment has left SCHEDULED.
+	Cancel(ctx context.Context, deploymentID string) error
+	// PromoteDue moves due SCHEDULED deployments to PENDING and returns them.
+	PromoteDue(ctx context.Context, now time.Time) ([]ScheduledDeployment, error)
+}
+
+// DeploymentScheduler is the contract behind the scheduled deployment endpoints.
+type DeploymentScheduler interface {
+	ScheduleAppDeployment(ctx context.Context, appID uuid.UUID, userID uuid.UUID, at time.Time) (*ScheduledDeployment, error)
+	ListScheduled(ctx context.Context, userID uuid.UUID) ([]ScheduledDeployment, error)
+	Cancel(ctx context.Context, deploymentID string, userID uuid.UUID) error
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeploymentLogRecord identifies a deployment whose build log is requested
+// or due for archival.
+type DeploymentLogRecord struct {
+	DeploymentID uuid.UUID
+	AppID        uuid.UUID
+	DomainName   string
+	Status       string
+	UpdatedAt    time.Time
+}
+
+// DeploymentLogArchive points at a gzip build log moved to object storage.
+type DeploymentLogArchive struct {
+	DeploymentID    uuid.UUID `json:"deployment_id"`
+	ObjectKey       string    `json:"object_key"`
+	CompressedBytes int64     `json:"compressed_bytes"`
+	Chunks          int       `json:"chunks"`
+	ArchivedAt      time.Time `json:"archived_at"`
+}
+
+const (
+	// DefaultLogPageLimit is used when the client omits ?limit= on log reads.
+	DefaultLogPageLimit = 500
+	// MaxLogPageLimit caps one page of log lines.
+	MaxLogPageLimit = 2000
+)
+
+// DeploymentLogLine is a persisted log message with its position in the
+// deployment's log. Seq starts at 1 and increases by one per line.
+type DeploymentLogLine struct {
+	Seq int64 `json:"seq"`
+	LogMessage
+}
+
+// DeploymentLogPage is one slice of a deployment log read with ?after_seq=.
+type DeploymentLogPage struct {
+	Lines        []DeploymentLogLine `json:"lines"`
+	NextAfterSeq int64               `json:"next_after_seq"` // Pass back as after_seq; unchanged when no new lines
+	HasMore      bool                `json:"has_more"`
+	// Archived logs live in object storage and are only served by /logs/download.
+	Archived bool `json:"archived"`
+}
+
+type DeploymentLogRepository interface {
+	GetDeployment(ctx context.Context, id uuid.UUID) (*DeploymentLogRecord, error)
+	// ListLines returns up to limit lines with seq > afterSeq, in seq order.
+	ListLines(ctx context.Context, id uuid.UUID, afterSeq int64, limit int) ([]DeploymentLogLine, error)
+	// StreamChunks calls fn with every log chunk in write order.
+	StreamChunks(ctx context.Context, id uuid.UUID, fn func(content string) error) error
+	GetArchive(ctx context.Context, id uuid.UUID) (*DeploymentLogArchive, error)
+	// ListArchivable returns finished deployments last updated before cutoff
+	// that still hold log rows.
+	ListArchivable(ctx context.Context, cutoff time.Time, limit int) ([]DeploymentLogRecord, error)
+	// CompleteArchive records the archive and deletes the log rows atomically.
+	CompleteArchive(ctx context.Context, a *DeploymentLogArchive) error
+}
+
+// BlobStore holds platform-owned objects (not tenant buckets).
+type BlobStore interface {
+	PutObject(ctx context.Context, bucket, key string, r io.Reader, size int64, contentType string) error
+	// GetObject returns ErrNotFound for a missing key.
+	GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error)
+}
+
+// LogDownload is a gzip build log ready to stream. WriteTo runs after the
+// response headers are sent, so access checks happen in OpenDownload.
+type LogDownload struct {
+	Filename string
+	io.WriterTo
+}
+
+type DeploymentLogService interface {
+	OpenDownload(ctx context.Context, userID, deploymentID uuid.UUID) (*LogDownload, error)
+	ReadLines(ctx context.Context, userID, deploymentID uuid.UUID, afterSeq int64, limit int) (*DeploymentLogPage, error)
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DigestFrequency is how often an admin receives the Action Center digest.
+type DigestFrequency string
+
+const (
+	DigestOff    DigestFrequency = "off"
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly" // Default for new admins
+)
+
+// DigestRecipient is an active admin together with their digest preference.
+type DigestRecipient struct {
+	UserID           uuid.UUID       `json:"user_id"`
+	Email            string          `json:"email"`
+	Frequency        DigestFrequency `json:"frequency"`
+	Timezone         string          `json:"timezone,omitempty"` // "" = the install's zone
+	UnsubscribeToken string          `json:"-"`
+}
+
+// CertificateRenewal is a certificate that expires inside the digest horizon.
+type CertificateRenewal struct {
+	DomainName string    `json:"domain_name"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Status     string    `json:"status"`
+}
+
+// FailedDeploy is a deployment that ended in FAILED during the period.
+type FailedDeploy struct {
+	ID         uuid.UUID `json:"id"`
+	AppID      uuid.UUID `json:"app_id"`
+	DomainName string    `json:"domain_name"`
+	FailedAt   time.Time `json:"failed_at"`
+}
+
+// UsageTotals sums the usage meters of every app for one month.
+type UsageTotals struct {
+	CPUSeconds     float64 `json:"cpu_seconds"`
+	RAMGBHours     float64 `json:"ram_gb_hours"`
+	StorageGBHours float64 `json:"storage_gb_hours"`
+	BandwidthBytes int64   `json:"bandwidth_bytes"`
+}
+
+// DigestSummary is the platform-wide content shared by every digest of a period.
+type DigestSummary struct {
+	Since             time.Time            `json:"since"`
+	UnresolvedAlerts  map[string]int       `json:"unresolved_alerts"` // By severity
+	TopAlerts         []SystemAlert        `json:"top_alerts"`        // Most severe first
+	RenewalsDue       []CertificateRenewal `json:"renewals_due"`
+	FailedDeploys     []FailedDeploy       `json:"failed_deploys"`
+	FailedDeployCount int                  `json:"failed_deploy_count"` // May exceed len(FailedDeploys)
+	UsageThisMonth    UsageTotals          `json:"usage_this_month"`    // Month to date
+	UsageLastMonth    UsageTotals          `json:"usage_last_month"`
+}
+
+type DigestRepository interface {
+	// ListRecipients returns every active admin, creating default
+	// preferences for admins that have none yet.
+	ListRecipients(ctx context.Context) ([]DigestRecipient, error)
+	GetPreference(ctx context.Context, userID uuid.UUID) (DigestFrequency, error)
+	SetPreference(ctx context.Context, userID uuid.UUID, f DigestFrequency) error
+	UnsubscribeByToken(ctx context.Context, token string) error
+
+	Summarize(ctx context.Context, since, renewalHorizon time.Time) (*DigestSummary, error)
+
+	// ClaimDelivery reserves (user, period). It returns false if the digest
+	// was already sent or another claim is still fresh.
+	ClaimDelivery(ctx context.Context, userID uuid.UUID, period string, staleAfter time.Duration) (bool, error)
+	MarkDelivered(ctx context.Context, userID uuid.UUID, period string) error
+	ReleaseDelivery(ctx context.Context, userID uuid.UUID, period string) error
+}
+
+// EmailMessage is a multipart/alternative email. Headers carries extras
+// such as List-Unsubscribe.
+type EmailMessage struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	Headers map[string]string
+}
+
+// Mailer delivers transactional email.
+type Mailer interface {
+	Send(ctx context.Context, msg EmailMessage) error
+}
+
+// DigestManager is the digest preferences API.
+type DigestManager interface {
+	GetPreference(ctx context.Context, userID uuid.UUID) (DigestFrequency, error)
+	SetPreference(ctx context.Context, userID uuid.UUID, f DigestFrequency) error
+	Unsubscribe(ctx context.Context, token string) error
+}
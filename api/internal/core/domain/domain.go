@@ -0,0 +1,9 @@
+package domain
+
+import "github.com/google/uuid"
+
+// DomainFilter narrows GET /api/v1/domains.
+type DomainFilter struct {
+	OwnerID uuid.UUID // 🛡️ Tenant Isolation: Always set by the handler from the JWT
+	Status  string    // enum: provisioning, active, failed, deleting
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DriftKind names a mismatch between the database and what the Muscle
+// reports on the host.
+type DriftKind string
+
+const (
+	DriftOrphanUnit        DriftKind = "orphan_unit"        // systemd unit no app claims
+	DriftOrphanVhost       DriftKind = "orphan_vhost"       // Kari vhost for an unregistered domain
+	DriftOrphanJail        DriftKind = "orphan_jail"        // kari-app-* user with no app
+	DriftOrphanCertificate DriftKind = "orphan_certificate" // Stored certificate for an unregistered domain
+	DriftMissingUnit       DriftKind = "missing_unit"       // Running app without its unit
+	DriftMissingVhost      DriftKind = "missing_vhost"      // Running app without its vhost
+)
+
+// Actions an admin can take on an open finding.
+const (
+	DriftActionAdopt   = "adopt"   // Register the resource's domain to an owner
+	DriftActionCleanup = "cleanup" // Remove the resource from the host
+)
+
+type DriftStatus string
+
+const (
+	DriftOpen     DriftStatus = "open"
+	DriftResolved DriftStatus = "resolved" // No longer observed by a later sweep
+	DriftAdopted  DriftStatus = "adopted"
+	DriftCleaned  DriftStatus = "cleaned"
+)
+
+// AvailableActions lists what can be done about an open finding. Only an
+// orphan serving a known domain can be adopted; missing resources have no
+// action at all, since redeploying the app recreates them.
+func (f *DriftFinding) AvailableActions() []string {
+	if f.Status != DriftOpen {
+		return []string{}
+	}
+	switch f.Kind {
+	case DriftOrphanUnit, DriftOrphanVhost, DriftOrphanCertificate:
+		if f.Domain == "" {
+			return []string{DriftActionCleanup}
+		}
+		return []string{DriftActionAdopt, DriftActionCleanup}
+	case DriftOrphanJail:
+		return []string{DriftActionCleanup}
+	default:
+		return []string{}
+	}
+}
+
+// Allows reports whether action is currently available on the finding.
+func (f *DriftFinding) Allows(action string) bool {
+	return slices.Contains(f.AvailableActions(), action)
+}
+
+// DriftFinding is one resource out of step with the database. A finding stays
+// open across sweeps while the drift persists, so each one alerts only once.
+type DriftFinding struct {
+	ID          uuid.UUID   `json:"id"`
+	Kind        DriftKind   `json:"kind"`
+	Resource    string      `json:"resource"`         // Unit name, domain or username
+	Domain      string      `json:"domain,omitempty"` // Domain the resource serves, when known
+	AppID       *uuid.UUID  `json:"app_id,omitempty"` // Missing resources only
+	Status      DriftStatus `json:"status"`
+	Actions     []string    `json:"actions"`
+	AlertID     *uuid.UUID  `json:"alert_id,omitempty"`
+	FirstSeenAt time.Time   `json:"first_seen_at"`
+	LastSeenAt  time.Time   `json:"last_seen_at"`
+	SettledAt   *time.Time  `json:"settled_at,omitempty"`
+	SettledBy   *uuid.UUID  `json:"settled_by,omitempty"` // Nil when a sweep resolved it
+}
+
+// DesiredApp is an application as the reconciler sees it.
+type DesiredApp struct {
+	ID         uuid.UUID
+	DomainName string
+	AppType    string
+	Status     string
+}
+
+// DesiredResources is what the database says the host should run.
+type DesiredResources struct {
+	Domains      map[string]bool // Every registered domain name
+	Apps         []DesiredApp
+	Certificates map[string]bool // Domains with a live certificate
+}
+
+type DriftRepository interface {
+	DesiredState(ctx context.Context) (*DesiredResources, error)
+	// Record refreshes open findings from one sweep: observed ones are
+	// upserted by (kind, resource), open ones not observed are resolved. It
+	// returns the findings this sweep opened and those it resolved.
+	Record(ctx context.Context, observed []DriftFinding) (opened, resolved []DriftFinding, err error)
+	AttachAlert(ctx context.Context, id uuid.UUID, alertID uuid.UUID) error
+	List(ctx context.Context, status DriftStatus) ([]DriftFinding, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*DriftFinding, error)
+	// Settle closes an open finding; ErrConflict if it is no longer open.
+	Settle(ctx context.Context, id uuid.UUID, status DriftStatus, actorID uuid.UUID) error
+}
+
+// DriftReconciler is the admin-facing drift API.
+type DriftReconciler interface {
+	ListDrift(ctx context.Context, status DriftStatus) ([]DriftFinding, error)
+	// Adopt registers the orphan's domain to ownerID.
+	Adopt(ctx context.Context, id uuid.UUID, actorID uuid.UUID, ownerID uuid.UUID) (*DriftFinding, error)
+	// Cleanup removes the orphan from the host.
+	Cleanup(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (*DriftFinding, error)
+}
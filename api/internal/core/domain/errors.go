@@ -1,5 +1,75 @@
 package domain
 
+import "errors"
+
+// ==============================================================================
+// 1. Sentinel Domain Errors
+// ==============================================================================
+
+// Services and repositories wrap these sentinels (fmt.Errorf("...: %w", ErrNotFound))
+// so the HTTP edge can map them to status codes without string matching.
+var (
+	ErrNotFound           = errors.New("resource not found")
+	ErrForbidden          = errors.New("forbidden")
+	ErrConflict           = errors.New("resource conflict")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrValidation         = errors.New("validation failed")
+	ErrUnavailable        = errors.New("dependency unavailable")
+	ErrQuotaExceeded      = errors.New("resource quota exceeded")
+	ErrPasskeyRequired    = errors.New("passkey sign-in required")
+	ErrPasswordExpired    = errors.New("password expired")
+)
+
+// ==============================================================================
+// 2. API Error Envelope (Machine-Readable Catalogue)
+// ==============================================================================
+
+// ErrorCode is a stable, machine-readable identifier the Svelte UI uses as a
+// localization key. 🛡️ SLA: Codes are append-only; never rename a published code.
+type ErrorCode string
+
+const (
+	CodeBadRequest         ErrorCode = "BAD_REQUEST"
+	CodeInvalidJSON        ErrorCode = "INVALID_JSON"
+	CodeValidationFailed   ErrorCode = "VALIDATION_FAILED"
+	CodeUnauthorized       ErrorCode = "UNAUTHORIZED"
+	CodeInvalidCredentials ErrorCode = "INVALID_CREDENTIALS"
+	CodeSessionExpired     ErrorCode = "SESSION_EXPIRED"
+	CodeForbidden          ErrorCode = "FORBIDDEN"
+	CodeNotFound           ErrorCode = "NOT_FOUND"
+	CodeConflict           ErrorCode = "CONFLICT"
+	CodePayloadTooLarge    ErrorCode = "PAYLOAD_TOO_LARGE"
+	CodeRateLimited        ErrorCode = "RATE_LIMITED"
+	CodeServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
+	CodeInternal           ErrorCode = "INTERNAL_ERROR"
+	CodeQuotaExceeded      ErrorCode = "QUOTA_EXCEEDED"
+	CodeMaintenance        ErrorCode = "MAINTENANCE"
+	CodePasskeyRequired    ErrorCode = "PASSKEY_REQUIRED"
+	CodePasswordExpired    ErrorCode = "PASSWORD_EXPIRED"
+	CodeUnsupportedMedia   ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+)
+
+// FieldError describes a single invalid input field for inline form rendering.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"` // validator tag, e.g. "required", "fqdn", "max"
+	Message string `json:"message"`
+}
+
+// ErrorResponse is the single JSON shape every handler and middleware returns on failure.
+// 🛡️ Zero-Trust: Message is always UI-safe; raw errors stay in the server log keyed by TraceID.
+type ErrorResponse struct {
+	Code    ErrorCode    `json:"code"`
+	Title   string       `json:"title,omitempty"` // Classified agent errors only
+	Message string       `json:"message"`
+	TraceID string       `json:"trace_id,omitempty"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// ==============================================================================
+// 3. Agent (Muscle) Error Classification
+// ==============================================================================
+
 // AgentErrorCode maps raw gRPC error messages from the Rust Muscle
 // into human-readable error codes that the Svelte UI can present
 // as styled alerts. This prevents exposing raw system internals to tenants.
@@ -28,6 +98,32 @@ type AgentError struct {
 	Title    string         `json:"title"`
 	Message  string         `json:"message"`
 	Severity string         `json:"severity"` // "critical", "warning", "info"
+
+	// Cause is the raw transport error, kept for server-side logs and errors.Is.
+	Cause error `json:"-"`
+}
+
+// Error satisfies the error interface so classified agent failures can travel
+// through service return values and be unwrapped by the HTTP edge.
+func (e AgentError) Error() string {
+	return string(e.Code) + ": " + e.Title
+}
+
+// Unwrap exposes the raw cause (e.g. a gRPC status) to errors.Is/As.
+func (e AgentError) Unwrap() error {
+	return e.Cause
+}
+
+// AsAgentError returns err's AgentError if it was already classified at the
+// gRPC boundary, and classifies the raw message otherwise.
+func AsAgentError(err error) AgentError {
+	var agentErr AgentError
+	if errors.As(err, &agentErr) {
+		return agentErr
+	}
+	agentErr = ClassifyAgentError(err.Error())
+	agentErr.Cause = err
+	return agentErr
 }
 
 // ClassifyAgentError transforms a raw gRPC error string from the Rust Muscle
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FeedItemKind string
+
+const (
+	FeedCertificateExpiry FeedItemKind = "certificate_expiry"
+	FeedMaintenance       FeedItemKind = "maintenance"
+	FeedIncident          FeedItemKind = "incident"
+)
+
+// FeedItem is one entry of the subscription feeds: a VEVENT in iCal, an
+// <item> in RSS. UID stays stable across fetches so clients update in place.
+type FeedItem struct {
+	UID         string       `json:"uid"`
+	Kind        FeedItemKind `json:"kind"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Start       time.Time    `json:"start"`
+	End         *time.Time   `json:"end,omitempty"` // nil = a point in time or still ongoing
+	Updated     time.Time    `json:"updated"`
+}
+
+// Feed is what a feed URL resolves to, newest first.
+type Feed struct {
+	GeneratedAt time.Time  `json:"generated_at"`
+	Link        string     `json:"link"` // The panel, for RSS <link>
+	Items       []FeedItem `json:"items"`
+}
+
+// FeedToken is returned once when issued; the URLs embed the secret.
+type FeedToken struct {
+	Token       string `json:"token"`
+	CalendarURL string `json:"calendar_url"`
+	RSSURL      string `json:"rss_url"`
+}
+
+// FeedScope limits feed queries to one user's resources. A nil OwnerID
+// means every resource on the server (server:manage holders).
+type FeedScope struct {
+	OwnerID *uuid.UUID
+}
+
+type FeedRepository interface {
+	// SetToken replaces the user's token; ResolveToken returns ErrNotFound
+	// for unknown or revoked tokens.
+	SetToken(ctx context.Context, userID uuid.UUID, tokenHash string) error
+	DeleteToken(ctx context.Context, userID uuid.UUID) error
+	ResolveToken(ctx context.Context, tokenHash string) (uuid.UUID, error)
+
+	CertificateExpiries(ctx context.Context, scope FeedScope, from, until time.Time, limit int) ([]CertificateRenewal, error)
+	// Incidents are critical and fatal alerts raised since the given time.
+	Incidents(ctx context.Context, scope FeedScope, since time.Time, limit int) ([]SystemAlert, error)
+}
+
+// FeedManager is the contract behind the feed endpoints.
+type FeedManager interface {
+	IssueToken(ctx context.Context, userID uuid.UUID) (*FeedToken, error)
+	RevokeToken(ctx context.Context, userID uuid.UUID) error
+	// FeedForToken returns ErrNotFound for unknown tokens and inactive users.
+	FeedForToken(ctx context.Context, token string) (*Feed, error)
+}
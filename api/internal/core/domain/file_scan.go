@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scan outcomes.
+const (
+	FileScanClean    = "clean"
+	FileScanFindings = "findings"
+	FileScanBaseline = "baseline" // First run or accepted changes: nothing compared
+	FileScanFailed   = "failed"
+)
+
+// Finding kinds reported by the Muscle.
+const (
+	FindingModified = "modified"
+	FindingAdded    = "added"
+	FindingRemoved  = "removed"
+	FindingInfected = "infected"
+)
+
+// DefaultScanExcludes are app-relative prefixes whose churn is expected
+// (uploads, caches). They skip the integrity diff but not ClamAV.
+var DefaultScanExcludes = []string{
+	"wp-content/uploads/",
+	"wp-content/cache/",
+	"wp-content/upgrade/",
+}
+
+// FileFinding is one deviation from the app's known-good state.
+type FileFinding struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail,omitempty"` // Signature name for infected files
+}
+
+// FileScan is one scan run over an app's directory.
+type FileScan struct {
+	ID           uuid.UUID     `json:"id"`
+	AppID        uuid.UUID     `json:"app_id"`
+	Status       string        `json:"status"`
+	FilesScanned int64         `json:"files_scanned"`
+	Findings     []FileFinding `json:"findings"`
+	Error        string        `json:"error,omitempty"`
+	StartedAt    time.Time     `json:"started_at"`
+	FinishedAt   time.Time     `json:"finished_at"`
+}
+
+// FileScanManager is the tenant-facing scan API (owner-scoped).
+type FileScanManager interface {
+	ScanApp(ctx context.Context, appID, userID uuid.UUID) (*FileScan, error)
+	// AcceptChanges makes the current tree the new known-good baseline.
+	AcceptChanges(ctx context.Context, appID, userID uuid.UUID) (*FileScan, error)
+	ListScans(ctx context.Context, appID, userID uuid.UUID) ([]FileScan, error)
+}
+
+type FileScanRepository interface {
+	Create(ctx context.Context, scan *FileScan) error
+	// ListByApp returns the newest scans first.
+	ListByApp(ctx context.Context, appID uuid.UUID, limit int) ([]FileScan, error)
+}
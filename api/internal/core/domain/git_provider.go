@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GitProvider identifies a supported OAuth Git host.
+type GitProvider string
+
+const (
+	GitProviderGitHub GitProvider = "github"
+	GitProviderGitLab GitProvider = "gitlab"
+)
+
+// GitConnection is a user's linked provider account.
+type GitConnection struct {
+	UserID       uuid.UUID   `json:"user_id"`
+	Provider     GitProvider `json:"provider"`
+	AccountLogin string      `json:"account_login"`
+	ExpiresAt    *time.Time  `json:"expires_at,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+
+	// 🛡️ Privacy: Never serialized
+	AccessTokenCiphertext  string `json:"-"`
+	RefreshTokenCiphertext string `json:"-"`
+}
+
+// GitToken is the plaintext result of an OAuth exchange or refresh.
+type GitToken struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    *time.Time
+}
+
+// GitRepo is one repository the connected account can access.
+type GitRepo struct {
+	FullName      string `json:"full_name"` // owner/name (GitLab: namespace path)
+	SSHURL        string `json:"ssh_url"`   // Cloned with the app's deploy key
+	DefaultBranch string `json:"default_branch"`
+	Private       bool   `json:"private"`
+}
+
+type GitBranch struct {
+	Name      string `json:"name"`
+	CommitSHA string `json:"commit_sha"`
+}
+
+// GitProviderClient is the per-host OAuth and REST adapter.
+type GitProviderClient interface {
+	Provider() GitProvider
+	AuthorizeURL(state, redirectURI string) string
+	Exchange(ctx context.Context, code, redirectURI string) (*GitToken, error)
+	Refresh(ctx context.Context, refreshToken string) (*GitToken, error)
+	AccountLogin(ctx context.Context, accessToken string) (string, error)
+
+	ListRepos(ctx context.Context, accessToken string) ([]GitRepo, error)
+	ListBranches(ctx context.Context, accessToken, repo string) ([]GitBranch, error)
+	// AddDeployKey registers a read-only deploy key on repo
+	AddDeployKey(ctx context.Context, accessToken, repo, title, publicKey string) error
+	// AddPushWebhook subscribes url to push events, signed/tokened with secret
+	AddPushWebhook(ctx context.Context, accessToken, repo, url, secret string) error
+}
+
+// GitIntegration is the user-facing OAuth and repo-picking surface.
+// 🛡️ Tenant Isolation: Tokens are always resolved from the caller's own connection.
+type GitIntegration interface {
+	AuthorizeURL(ctx context.Context, userID uuid.UUID, provider GitProvider) (string, error)
+	CompleteOAuth(ctx context.Context, provider GitProvider, code, state string) (*GitConnection, error)
+	ListConnections(ctx context.Context, userID uuid.UUID) ([]GitConnection, error)
+	Disconnect(ctx context.Context, userID uuid.UUID, provider GitProvider) error
+
+	ListRepos(ctx context.Context, userID uuid.UUID, provider GitProvider) ([]GitRepo, error)
+	ListBranches(ctx context.Context, userID uuid.UUID, provider GitProvider, repo string) ([]GitBranch, error)
+
+	// LinkApplication installs the app's deploy key and push webhook on repo.
+	LinkApplication(ctx context.Context, userID uuid.UUID, app *Application, provider GitProvider, repo string) error
+}
+
+type GitConnectionRepository interface {
+	Upsert(ctx context.Context, conn *GitConnection) error
+	Get(ctx context.Context, userID uuid.UUID, provider GitProvider) (*GitConnection, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]GitConnection, error)
+	Delete(ctx context.Context, userID uuid.UUID, provider GitProvider) error
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Component and aggregate health states reported by /health/ready.
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded" // Serving, but something needs attention
+	HealthDown     = "down"     // Not ready for traffic
+)
+
+// ComponentHealth is one dependency's line in the readiness report.
+// A critical component that is down takes the whole Brain down.
+type ComponentHealth struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	Detail    string `json:"detail,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+type ReadinessReport struct {
+	Status      string            `json:"status"`
+	Maintenance bool              `json:"maintenance"`
+	Components  []ComponentHealth `json:"components"`
+	CheckedAt   time.Time         `json:"checked_at"`
+}
+
+// WorkerHeartbeat is the last sign of life of a background loop.
+type WorkerHeartbeat struct {
+	Name         string
+	Interval     time.Duration
+	RegisteredAt time.Time
+	LastBeat     time.Time // Zero until the first tick completes
+}
+
+// HeartbeatRecorder is implemented by the worker heartbeat registry.
+type HeartbeatRecorder interface {
+	Register(name string, interval time.Duration)
+	Beat(name string)
+}
+
+// SchemaChecker reports tables the Brain expects that the database lacks,
+// which means migrations were not (all) applied.
+type SchemaChecker interface {
+	MissingRelations(ctx context.Context) ([]string, error)
+}
+
+// ReadinessChecker aggregates every dependency into one report.
+type ReadinessChecker interface {
+	Ready(ctx context.Context) ReadinessReport
+}
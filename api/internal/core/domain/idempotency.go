@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyRecord is a cached outcome of a mutating request keyed by the
+// client-supplied Idempotency-Key header.
+type IdempotencyRecord struct {
+	UserID       uuid.UUID
+	Key          string
+	RequestHash  string
+	StatusCode   int // 0 while the original request is still in flight
+	ResponseBody []byte
+	ContentType  string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// InFlight reports whether the original request has not completed yet.
+func (r *IdempotencyRecord) InFlight() bool {
+	return r.StatusCode == 0
+}
+
+// IdempotencyRepository persists idempotency reservations and their results.
+type IdempotencyRepository interface {
+	// Reserve atomically claims the key. It returns (nil, nil) if the caller won
+	// the reservation, or the existing record if the key was already used.
+	Reserve(ctx context.Context, userID uuid.UUID, key, requestHash string, ttl time.Duration) (*IdempotencyRecord, error)
+
+	// Complete stores the final response for replay.
+	Complete(ctx context.Context, userID uuid.UUID, key string, statusCode int, contentType string, body []byte) error
+
+	// Release drops a reservation so the client may retry (used on 5xx / panics).
+	Release(ctx context.Context, userID uuid.UUID, key string) error
+
+	// DeleteExpired purges keys past their TTL and returns the number removed.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "context"
+
+// LeaderElection lets singleton workers (monitors, schedulers, scanners) run
+// on exactly one Brain replica, failing over when that replica goes away.
+type LeaderElection interface {
+	// Lead blocks until this replica is the leader. The returned context is
+	// canceled when leadership is lost or ctx is done; call release when finished.
+	Lead(ctx context.Context) (leading context.Context, release context.CancelFunc, err error)
+}
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// LicensePolicy lists the SPDX license IDs an operator will not ship, e.g.
+// agencies whose client contracts rule out copyleft. IDs match regardless of
+// case and of the -only / -or-later / + suffixes.
+type LicensePolicy struct {
+	Disallowed []string
+}
+
+func NewLicensePolicy(ids []string) LicensePolicy {
+	var p LicensePolicy
+	for _, id := range ids {
+		if id = strings.TrimSpace(id); id != "" {
+			p.Disallowed = append(p.Disallowed, id)
+		}
+	}
+	return p
+}
+
+// Allows evaluates an SPDX expression: an OR is allowed when any branch is,
+// an AND only when every term is. Non-SPDX license names are matched as-is.
+func (p LicensePolicy) Allows(expr string) bool {
+	return p.allows(tokenizeSPDX(expr))
+}
+
+// Violations returns the disallowed IDs an expression mentions.
+func (p LicensePolicy) Violations(expr string) []string {
+	var hits []string
+	for _, tok := range tokenizeSPDX(expr) {
+		if p.disallowed(tok) {
+			hits = append(hits, tok)
+		}
+	}
+	return hits
+}
+
+func (p LicensePolicy) allows(tokens []string) bool {
+	for _, alt := range splitTopLevel(tokens, "OR") {
+		ok := true
+		for _, term := range splitTopLevel(alt, "AND") {
+			if !p.allowsTerm(term) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (p LicensePolicy) allowsTerm(term []string) bool {
+	if len(term) == 0 {
+		return true
+	}
+	if term[0] == "(" && term[len(term)-1] == ")" {
+		return p.allows(term[1 : len(term)-1])
+	}
+	// "GPL-2.0-only WITH Classpath-exception-2.0": the license is what counts
+	return !p.disallowed(term[0])
+}
+
+func (p LicensePolicy) disallowed(id string) bool {
+	switch strings.ToUpper(id) {
+	case "(", ")", "AND", "OR", "WITH":
+		return false
+	}
+	for _, d := range p.Disallowed {
+		if strings.EqualFold(normalizeSPDX(id), normalizeSPDX(d)) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeSPDX(id string) string {
+	id = strings.TrimSuffix(id, "+")
+	id = strings.TrimSuffix(id, "-only")
+	return strings.TrimSuffix(id, "-or-later")
+}
+
+// tokenizeSPDX splits an expression into IDs, operators and parentheses.
+// Anything that is not an expression becomes a single token.
+func tokenizeSPDX(expr string) []string {
+	fields := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr))
+	isExpr := false
+	for i, f := range fields {
+		switch u := strings.ToUpper(f); u {
+		case "AND", "OR", "WITH":
+			fields[i], isExpr = u, true
+		case "(", ")":
+			isExpr = true
+		}
+	}
+	if !isExpr && len(fields) > 1 {
+		return []string{strings.Join(fields, " ")} // e.g. "BSD style"
+	}
+	return fields
+}
+
+// splitTopLevel splits tokens on op outside of parentheses.
+func splitTopLevel(tokens []string, op string) [][]string {
+	var parts [][]string
+	depth, start := 0, 0
+	for i, t := range tokens {
+		switch t {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case op:
+			if depth == 0 {
+				parts = append(parts, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, tokens[start:])
+}
+
+// LicenseUsage counts the components under one license (or expression).
+type LicenseUsage struct {
+	License    string `json:"license"`
+	Components int    `json:"components"`
+	Disallowed bool   `json:"disallowed"`
+}
+
+// LicenseViolation is a component whose licensing the policy does not allow.
+type LicenseViolation struct {
+	Name       string   `json:"name"`
+	Version    string   `json:"version"`
+	PURL       string   `json:"purl"`
+	Licenses   []string `json:"licenses"`
+	Disallowed []string `json:"disallowed"` // The policy IDs it matched
+}
+
+// LicenseReport summarizes the licenses of what an app currently runs.
+type LicenseReport struct {
+	AppID        string             `json:"app_id"`
+	DeploymentID string             `json:"deployment_id"`
+	GeneratedAt  time.Time          `json:"generated_at"` // When the SBOM was taken
+	Components   int                `json:"components"`
+	Unlicensed   int                `json:"unlicensed"` // No license detected; review by hand
+	Licenses     []LicenseUsage     `json:"licenses"`
+	Violations   []LicenseViolation `json:"violations"`
+	Disallowed   []string           `json:"disallowed"` // The policy in force
+	Compliant    bool               `json:"compliant"`
+	Truncated    bool               `json:"truncated"` // The SBOM hit the Muscle's component cap
+}
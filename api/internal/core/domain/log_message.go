@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+// LogLevel grades a build log message so the UI can colour-code it and the
+// deployment_logs table can be filtered by it.
+type LogLevel string
+
+const (
+	LogLevelInfo  LogLevel = "info"
+	LogLevelWarn  LogLevel = "warn"
+	LogLevelError LogLevel = "error"
+)
+
+// Deployment stages reported in LogMessage.Stage.
+const (
+	LogStageInit     = "init"     // Brain-side setup before the Muscle is dialled
+	LogStageBuild    = "build"    // Output relayed from the Muscle
+	LogStageComplete = "complete" // Terminal success
+	LogStageFailed   = "failed"   // Terminal failure
+)
+
+// LogMessage is one frame of the deployment log protocol. The Hub fans it
+// out as-is and the SSE stream serializes it as a JSON frame.
+type LogMessage struct {
+	Level     LogLevel  `json:"level"`
+	Stage     string    `json:"stage"`
+	Timestamp time.Time `json:"timestamp"`
+	Content   string    `json:"content"`
+	Skipped   int64     `json:"skipped,omitempty"` // Set on the Hub's marker after dropped messages
+}
+
+// NewLogMessage stamps content with the current time and, for Muscle output,
+// a level inferred from the text.
+func NewLogMessage(stage string, level LogLevel, content string) LogMessage {
+	if level == "" {
+		level = InferLogLevel(content)
+	}
+	return LogMessage{Level: level, Stage: stage, Timestamp: time.Now().UTC(), Content: content}
+}
+
+var (
+	errorLinePattern = regexp.MustCompile(`(?i)\b(error|fatal|panic|failed|err!)`)
+	warnLinePattern  = regexp.MustCompile(`(?i)\b(warn|warning|deprecated)\b`)
+)
+
+// InferLogLevel grades raw build output, which carries no level of its own.
+// The most severe match in a multi-line chunk wins.
+func InferLogLevel(content string) LogLevel {
+	switch {
+	case errorLinePattern.MatchString(content):
+		return LogLevelError
+	case warnLinePattern.MatchString(content):
+		return LogLevelWarn
+	}
+	return LogLevelInfo
+}
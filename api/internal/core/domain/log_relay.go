@@ -0,0 +1,19 @@
+package domain
+
+import "context"
+
+// LogRelayEnvelope is one Hub broadcast on its way to the other replicas.
+type LogRelayEnvelope struct {
+	Origin       string     `json:"o"` // The publishing replica, which skips its own echo
+	DeploymentID string     `json:"d"`
+	Message      LogMessage `json:"m"`
+}
+
+// LogRelay carries Hub broadcasts between Brain replicas, so a browser on one
+// replica can watch a build streaming on another.
+type LogRelay interface {
+	Publish(ctx context.Context, env LogRelayEnvelope) error
+	// Listen delivers envelopes from every replica, this one included, until
+	// ctx is done (nil) or the connection fails.
+	Listen(ctx context.Context, deliver func(LogRelayEnvelope)) error
+}
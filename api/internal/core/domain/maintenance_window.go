@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaintenanceWindow is planned downtime. While it is active the AppMonitor
+// raises no alerts and, if PauseDeploys is set, PENDING deployments wait.
+// Both resume on their own once EndsAt passes.
+type MaintenanceWindow struct {
+	ID           uuid.UUID  `json:"id"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description,omitempty"`
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       time.Time  `json:"ends_at"`
+	PauseDeploys bool       `json:"pause_deploys"`
+	CreatedBy    *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func (w *MaintenanceWindow) ActiveAt(t time.Time) bool {
+	return !t.Before(w.StartsAt) && t.Before(w.EndsAt)
+}
+
+type MaintenanceWindowRepository interface {
+	Create(ctx context.Context, w *MaintenanceWindow) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListUnended returns windows whose end is after now, soonest first.
+	ListUnended(ctx context.Context, now time.Time) ([]MaintenanceWindow, error)
+}
+
+// MaintenanceSchedule is the hot-path view read by workers and the status
+// endpoint; it is served from memory.
+type MaintenanceSchedule interface {
+	ActiveWindow(now time.Time) *MaintenanceWindow
+	DeploysPaused(now time.Time) bool
+	// Upcoming returns active and future windows, soonest first.
+	Upcoming() []MaintenanceWindow
+}
+
+// MaintenanceWindowManager is the admin API for planned downtime.
+type MaintenanceWindowManager interface {
+	MaintenanceSchedule
+	Schedule(ctx context.Context, actorID uuid.UUID, w *MaintenanceWindow) (*MaintenanceWindow, error)
+	// Cancel removes a window; cancelling an active one resumes at once.
+	Cancel(ctx context.Context, actorID uuid.UUID, id uuid.UUID) error
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UsageRecord is one app's metered usage for a month, in billing units.
+// 🛡️ SLA: Field names are part of the reseller export contract; append-only.
+type UsageRecord struct {
+	Month          string    `json:"month"` // YYYY-MM
+	TenantID       uuid.UUID `json:"tenant_id"`
+	AppID          uuid.UUID `json:"app_id"`
+	DomainName     string    `json:"domain_name"`
+	CPUHours       float64   `json:"cpu_hours"`
+	RAMGBHours     float64   `json:"ram_gb_hours"`
+	StorageGBHours float64   `json:"storage_gb_hours"` // Object storage (app buckets)
+	BandwidthBytes int64     `json:"bandwidth_bytes"`  // In + out through the proxy
+}
+
+// UsageAccrual is what one sampling pass adds to an app's month.
+type UsageAccrual struct {
+	AppID          uuid.UUID
+	TenantID       uuid.UUID
+	DomainName     string
+	Month          time.Time
+	CPUSeconds     float64
+	RAMGBHours     float64
+	StorageGBHours float64
+}
+
+// MeterCursor is the previous reading of one metered source.
+type MeterCursor struct {
+	Key       string
+	Counter   int64
+	StartedAt *time.Time
+	SampledAt time.Time
+}
+
+// UsageExport tracks the webhook delivery of a closed month.
+type UsageExport struct {
+	Month     time.Time `json:"month"`
+	Status    string    `json:"status"` // delivered, failed
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ParseUsageMonth parses "YYYY-MM"; empty means the current month.
+func ParseUsageMonth(s string) (time.Time, error) {
+	if s == "" {
+		return CurrentMonth(time.Now()), nil
+	}
+	t, err := time.Parse("2006-01", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: month must be formatted YYYY-MM", ErrValidation)
+	}
+	return t.UTC(), nil
+}
+
+type MeteringRepository interface {
+	Cursors(ctx context.Context, appID uuid.UUID) (map[string]MeterCursor, error)
+	// Accrue adds to the app's month, refreshes its bandwidth copy and moves
+	// the cursors in one transaction.
+	Accrue(ctx context.Context, a UsageAccrual, cursors []MeterCursor) error
+	Report(ctx context.Context, month time.Time) ([]UsageRecord, error)
+	GetExport(ctx context.Context, month time.Time) (*UsageExport, error)
+	RecordExport(ctx context.Context, month time.Time, exportErr error) error
+}
+
+// UsageReporter is the admin-facing metering API.
+type UsageReporter interface {
+	Report(ctx context.Context, month string) ([]UsageRecord, error)
+}
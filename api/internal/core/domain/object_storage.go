@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AppBucket is an app's private S3-compatible bucket and the credential
+// scoped to it.
+type AppBucket struct {
+	AppID      uuid.UUID `json:"app_id"`
+	Bucket     string    `json:"bucket"`
+	AccessKey  string    `json:"access_key"`
+	QuotaBytes int64     `json:"quota_bytes"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// 🛡️ Privacy: Never serialized; AAD = "app_bucket:<app_id>"
+	SecretKeyCiphertext string `json:"-"`
+}
+
+// BucketUsage is the live view returned to the dashboard.
+type BucketUsage struct {
+	AppBucket
+	Endpoint   string `json:"endpoint"`
+	UsedBytes  uint64 `json:"used_bytes"`
+	Objects    uint64 `json:"objects"`
+	UsageKnown bool   `json:"usage_known"` // False until the server's usage scanner has run
+}
+
+// ObjectStorageProvider is the admin surface of the object store (MinIO).
+type ObjectStorageProvider interface {
+	// CreateBucket makes the bucket, a policy granting access to it alone,
+	// and a user bound to that policy.
+	CreateBucket(ctx context.Context, bucket, accessKey, secretKey string, quotaBytes int64) error
+	SetQuota(ctx context.Context, bucket string, quotaBytes int64) error
+	Usage(ctx context.Context, bucket string) (usedBytes, objects uint64, known bool, err error)
+	// DeleteBucket removes the bucket with all objects, its user and policy.
+	DeleteBucket(ctx context.Context, bucket, accessKey string) error
+	// Endpoint is the URL apps use to reach the store.
+	Endpoint() string
+	Region() string
+}
+
+// BucketManager is the tenant-facing bucket lifecycle.
+type BucketManager interface {
+	// overrideDefault lets a quota admin go past the platform default.
+	ProvisionBucket(ctx context.Context, appID, userID uuid.UUID, quotaBytes int64, overrideDefault bool) (*AppBucket, error)
+	GetBucket(ctx context.Context, appID, userID uuid.UUID) (*BucketUsage, error)
+	SetBucketQuota(ctx context.Context, appID, userID uuid.UUID, quotaBytes int64, overrideDefault bool) (*AppBucket, error)
+	DeleteBucket(ctx context.Context, appID, userID uuid.UUID) error
+}
+
+// AppBucketSource is the application lifecycle's view of bucket storage.
+type AppBucketSource interface {
+	// BucketEnvFor returns the S3_* variables injected at deploy time.
+	// An app without a bucket yields an empty map.
+	BucketEnvFor(ctx context.Context, appID uuid.UUID) (map[string]string, error)
+	// ReleaseBucket destroys the app's bucket on app deletion (no-op without one).
+	ReleaseBucket(ctx context.Context, appID uuid.UUID) error
+}
+
+type AppBucketRepository interface {
+	Create(ctx context.Context, b *AppBucket) error
+	GetByAppID(ctx context.Context, appID uuid.UUID) (*AppBucket, error)
+	UpdateQuota(ctx context.Context, appID uuid.UUID, quotaBytes int64) error
+	Delete(ctx context.Context, appID uuid.UUID) error
+}
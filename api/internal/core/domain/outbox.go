@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StateChangeStatus tracks an outbox entry through dispatch.
+type StateChangeStatus string
+
+const (
+	ChangePending  StateChangeStatus = "pending"  // Waiting to be applied by the Muscle
+	ChangeApplied  StateChangeStatus = "applied"  // The Muscle acknowledged it
+	ChangeFailed   StateChangeStatus = "failed"   // The agent answered with an error; not retried
+	ChangeRecorded StateChangeStatus = "recorded" // History only: nothing to apply on the host
+)
+
+// State change events. The first three are history written in the same
+// transaction as the change; the rest are applied on the host.
+const (
+	EventAppCreated      = "app.created"
+	EventAppDeleted      = "app.deleted"
+	EventAppEnvUpdated   = "app.env_updated"
+	EventCertInstalled   = "cert.installed"
+	EventVhostWritten    = "vhost.written"
+	EventServiceReloaded = "service.reloaded"
+)
+
+// StateChange is one outbox entry. Entries are dispatched strictly in seq
+// order and stay in the table afterwards as the state-change history.
+type StateChange struct {
+	ID                uuid.UUID         `json:"id"`
+	Seq               int64             `json:"seq"`
+	Event             string            `json:"event"`
+	ResourceType      string            `json:"resource_type"` // application, domain, certificate, service
+	ResourceID        string            `json:"resource_id"`
+	ActorID           *uuid.UUID        `json:"actor_id,omitempty"`
+	Method            string            `json:"method,omitempty"` // Full gRPC method; empty when history only
+	PayloadCiphertext string            `json:"-"`
+	Description       string            `json:"description"`
+	Status            StateChangeStatus `json:"status"`
+	Attempts          int               `json:"attempts"`
+	LastError         *string           `json:"last_error,omitempty"`
+	CreatedAt         time.Time         `json:"created_at"`
+	ProcessedAt       *time.Time        `json:"processed_at,omitempty"`
+}
+
+// StateChangeFilter narrows the history feed.
+type StateChangeFilter struct {
+	ResourceType string
+	ResourceID   string
+	Status       StateChangeStatus
+	Limit        int
+}
+
+// OutboxRepository persists the outbox.
+type OutboxRepository interface {
+	// Append stores a change: pending when it carries an RPC, recorded otherwise.
+	Append(ctx context.Context, c *StateChange) error
+	// NextPending returns pending changes in submission (seq) order.
+	NextPending(ctx context.Context, limit int) ([]StateChange, error)
+	CountPending(ctx context.Context) (int, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*StateChange, error)
+	// RecordAttempt bumps attempts on a change that could not reach the agent yet.
+	RecordAttempt(ctx context.Context, id uuid.UUID, reason string) error
+	MarkApplied(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string) error
+	List(ctx context.Context, filter StateChangeFilter) ([]StateChange, error)
+}
+
+// StateHistoryReader serves the admin state-change history.
+type StateHistoryReader interface {
+	History(ctx context.Context, filter StateChangeFilter) ([]StateChange, error)
+}
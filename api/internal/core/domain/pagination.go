@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ==============================================================================
+// 1. Page Request (Shared by every list endpoint)
+// ==============================================================================
+
+const (
+	// DefaultPageLimit is used when the client omits ?limit=
+	DefaultPageLimit = 50
+	// MaxPageLimit caps a single page to protect the DB and the JSON encoder.
+	MaxPageLimit = 100
+)
+
+// PageRequest carries keyset pagination and sort intent from the HTTP layer
+// down to the repositories.
+type PageRequest struct {
+	Limit  int
+	Cursor *Cursor // nil = first page
+	Sort   string  // Public sort key (e.g. "created_at"); whitelisted per resource
+	Desc   bool
+}
+
+// Normalize clamps the limit into [1, MaxPageLimit].
+func (p PageRequest) Normalize() PageRequest {
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+	return p
+}
+
+// Page is a single slice of a list result.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string // Empty on the last page
+	Total      int    // Total rows matching the filter (ignores the cursor)
+}
+
+// ==============================================================================
+// 2. Opaque Cursor
+// ==============================================================================
+
+// Cursor identifies the last row of the previous page: the value of the sort
+// column plus the row ID as a unique tie-breaker.
+// 🛡️ Stability: Keyset pagination never skips or duplicates rows when new
+// records are inserted between page loads (unlike OFFSET).
+type Cursor struct {
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// Encode serializes the cursor into an opaque, URL-safe token.
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses a token produced by Cursor.Encode.
+func DecodeCursor(token string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(token))
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrValidation)
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == uuid.Nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrValidation)
+	}
+	return &c, nil
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebAuthn ceremonies tracked between their begin and finish calls.
+const (
+	CeremonyRegistration = "registration"
+	CeremonyLogin        = "login"
+)
+
+// Passkey is a WebAuthn credential registered to a user. Only the public
+// half ever reaches the Brain.
+type Passkey struct {
+	ID           uuid.UUID       `json:"id"`
+	UserID       uuid.UUID       `json:"user_id"`
+	Name         string          `json:"name"`
+	CredentialID []byte          `json:"-"`
+	Credential   json.RawMessage `json:"-"` // Serialized webauthn.Credential
+	CreatedAt    time.Time       `json:"created_at"`
+	LastUsedAt   *time.Time      `json:"last_used_at,omitempty"`
+}
+
+type PasskeyRepository interface {
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]Passkey, error)
+	GetByCredentialID(ctx context.Context, credentialID []byte) (*Passkey, error)
+	Create(ctx context.Context, p *Passkey) error
+	// UpdateCredential stores the new sign count and stamps last_used_at.
+	UpdateCredential(ctx context.Context, id uuid.UUID, credential json.RawMessage) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	// ReenrollPending reports a break-glass recovery awaiting a new passkey;
+	// Create clears it.
+	ReenrollPending(ctx context.Context, userID uuid.UUID) (bool, error)
+
+	SaveSession(ctx context.Context, id uuid.UUID, userID *uuid.UUID, ceremony string, data json.RawMessage, expiresAt time.Time) error
+	// TakeSession consumes an unexpired session; a challenge is single-use.
+	TakeSession(ctx context.Context, id uuid.UUID, ceremony string) (*uuid.UUID, json.RawMessage, error)
+}
+
+// PasskeyService runs the WebAuthn ceremonies. Options are returned as the
+// JSON the browser passes to navigator.credentials.create()/get().
+type PasskeyService interface {
+	BeginRegistration(ctx context.Context, userID uuid.UUID) (uuid.UUID, any, error)
+	FinishRegistration(ctx context.Context, userID, ceremonyID uuid.UUID, name string, response []byte) (*Passkey, error)
+	BeginLogin(ctx context.Context) (uuid.UUID, any, error)
+	FinishLogin(ctx context.Context, ceremonyID uuid.UUID, response []byte) (*TokenPair, *User, error)
+
+	List(ctx context.Context, userID uuid.UUID) ([]Passkey, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
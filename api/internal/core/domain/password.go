@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// BreachChecker reports how often a password appears in public breach
+// corpora. Implementations must never send the password itself.
+type BreachChecker interface {
+	Breached(ctx context.Context, password string) (int, error)
+}
+
+// PasswordService enforces the password policy wherever a password is set.
+type PasswordService interface {
+	// ValidatePassword checks a candidate against the policy; email is
+	// rejected as a password.
+	ValidatePassword(ctx context.Context, email, password string) error
+	HashPassword(password string) (string, error)
+
+	// ChangePassword rotates a signed-in user's password, revokes their
+	// other sessions and returns a fresh session for the caller.
+	ChangePassword(ctx context.Context, userID uuid.UUID, current, next string) (*TokenPair, error)
+	// ChangeExpiredPassword is the forced-change flow: a login refused with
+	// ErrPasswordExpired retries here with a new password and gets a session.
+	ChangeExpiredPassword(ctx context.Context, email, current, next string) (*TokenPair, *User, error)
+}
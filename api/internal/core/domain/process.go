@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// WebProcess is the process the reverse proxy routes to.
+const WebProcess = "web"
+
+const (
+	MaxProcessesPerApp      = 10
+	DefaultProcessCPU       = 100 // CPUQuota %, one core
+	DefaultProcessMemoryMB  = 512
+	MaxProcessCPUPercent    = 400
+	MinProcessMemoryMB      = 64
+	MaxProcessMemoryMB      = 16384
+	maxProcessCommandLength = 255
+
+	MaxInstances   = 16   // Web copies per app, each on its own port
+	DefaultAppPort = 3000 // Port the Muscle assumes when the app sets none
+)
+
+var processNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]{0,29}$`)
+
+// ProcessSpec is one long-running process of an app, each in its own systemd unit.
+type ProcessSpec struct {
+	Command    string `json:"command"`
+	CPUPercent int    `json:"cpu_percent,omitempty"` // 0 = DefaultProcessCPU
+	MemoryMB   int    `json:"memory_mb,omitempty"`   // 0 = DefaultProcessMemoryMB
+}
+
+// EffectiveCPU and EffectiveMemoryMB resolve the share sent to the Muscle.
+func (p ProcessSpec) EffectiveCPU() int {
+	if p.CPUPercent > 0 {
+		return p.CPUPercent
+	}
+	return DefaultProcessCPU
+}
+
+func (p ProcessSpec) EffectiveMemoryMB() int {
+	if p.MemoryMB > 0 {
+		return p.MemoryMB
+	}
+	return DefaultProcessMemoryMB
+}
+
+// ValidateProcesses checks a process map before it is stored. An empty map
+// keeps the legacy single-process behavior (StartCommand).
+func ValidateProcesses(appType string, procs map[string]ProcessSpec) error {
+	if len(procs) > MaxProcessesPerApp {
+		return fmt.Errorf("%w: at most %d processes per app", ErrValidation, MaxProcessesPerApp)
+	}
+	for name, p := range procs {
+		if !processNamePattern.MatchString(name) {
+			return fmt.Errorf("%w: process name %q must be lowercase letters and digits", ErrValidation, name)
+		}
+		// 🛡️ Zero-Trust: A newline would inject directives into the unit file
+		if strings.TrimSpace(p.Command) == "" || len(p.Command) > maxProcessCommandLength || strings.ContainsAny(p.Command, "\r\n") {
+			return fmt.Errorf("%w: process %q needs a single-line command", ErrValidation, name)
+		}
+		if p.CPUPercent < 0 || p.CPUPercent > MaxProcessCPUPercent {
+			return fmt.Errorf("%w: process %q cpu_percent must be 1-%d", ErrValidation, name, MaxProcessCPUPercent)
+		}
+		if p.MemoryMB != 0 && (p.MemoryMB < MinProcessMemoryMB || p.MemoryMB > MaxProcessMemoryMB) {
+			return fmt.Errorf("%w: process %q memory_mb must be %d-%d", ErrValidation, name, MinProcessMemoryMB, MaxProcessMemoryMB)
+		}
+	}
+	if _, ok := procs[WebProcess]; ok && (appType == "php" || appType == "static") {
+		return fmt.Errorf("%w: %s apps cannot define a web process", ErrValidation, appType)
+	}
+	return nil
+}
+
+// ValidateInstances checks a web instance count against the app's shape.
+// Only apps the proxy reaches through a web process can scale out.
+func ValidateInstances(app *Application, instances int) error {
+	if instances < 1 || instances > MaxInstances {
+		return fmt.Errorf("%w: instances must be 1-%d", ErrValidation, MaxInstances)
+	}
+	if instances == 1 {
+		return nil
+	}
+	switch app.AppType {
+	case "php", "static", "image":
+		return fmt.Errorf("%w: %s apps cannot run multiple instances", ErrValidation, app.AppType)
+	}
+	if _, ok := app.Processes[WebProcess]; len(app.Processes) > 0 && !ok {
+		return fmt.Errorf("%w: scaling requires a web process", ErrValidation)
+	}
+	if app.EffectivePort()+instances-1 > 65535 {
+		return fmt.Errorf("%w: %d instances from port %d exceed 65535", ErrValidation, instances, app.EffectivePort())
+	}
+	return nil
+}
+
+// EffectivePort resolves the port of web instance 1.
+func (a *Application) EffectivePort() int {
+	if a.Port > 0 {
+		return a.Port
+	}
+	return DefaultAppPort
+}
+
+// ReservedMemoryMB is the memory ceiling of all the app's units together,
+// which is what memory quotas count. Static sites run no units.
+func (a *Application) ReservedMemoryMB() int {
+	if a.AppType == "static" {
+		return 0
+	}
+	instances := max(a.Instances, 1)
+	if len(a.Processes) == 0 {
+		return instances * DefaultProcessMemoryMB
+	}
+	total := 0
+	for name, p := range a.Processes {
+		if name == WebProcess {
+			total += instances * p.EffectiveMemoryMB()
+		} else {
+			total += p.EffectiveMemoryMB()
+		}
+	}
+	return total
+}
+
+// ProcessStatus is one row of the app's process table in the dashboard.
+type ProcessStatus struct {
+	Name        string      `json:"name"`
+	Spec        ProcessSpec `json:"spec"`
+	ActiveState string      `json:"active_state"` // active, failed, inactive, ...
+	SubState    string      `json:"sub_state"`
+	MemoryBytes uint64      `json:"memory_bytes"`
+	CPUUsageNS  uint64      `json:"cpu_usage_ns"` // Cumulative since Since (rev 13 Muscles)
+	Since       *time.Time  `json:"since,omitempty"`
+	Instance    int         `json:"instance,omitempty"` // 1-based, web instances only
+	Port        int         `json:"port,omitempty"`
+	Healthy     *bool       `json:"healthy,omitempty"` // Web instances: port accepted a connection
+}
@@ -2,7 +2,7 @@ package domain
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -35,25 +35,106 @@ type SystemProfile struct {
 	UpdatedAt             time.Time         `json:"updated_at"`
 }
 
+// UID bounds for app_user jails: below 1000 are system accounts, and above
+// 60000 the distributions keep nobody/nogroup and their own reserved ranges.
+const (
+	MinAppUserUID = 1000
+	MaxAppUserUID = 60000
+)
+
+// Default inbound firewall policies the Muscle can enforce. Empty leaves the
+// host firewall unmanaged.
+const (
+	FirewallPolicyUnmanaged = ""
+	FirewallPolicyAllow     = "allow"
+	FirewallPolicyDeny      = "deny"
+)
+
 // 🛡️ Domain-Driven Integrity
 // Validate ensures the struct contains mathematically and logically sound intent
 // before it is ever sent to the database or the Rust Muscle.
 func (p *SystemProfile) Validate() error {
-	if p.MaxMemoryPerAppMB < 128 {
-		return errors.New("domain validation failed: MaxMemoryPerAppMB must be at least 128MB")
+	if p.MaxMemoryPerAppMB < 128 || p.MaxMemoryPerAppMB > 1048576 {
+		return fmt.Errorf("%w: MaxMemoryPerAppMB must be between 128MB and 1TB", ErrValidation)
 	}
 	if p.MaxCPUPercentPerApp < 10 || p.MaxCPUPercentPerApp > 100 {
-		return errors.New("domain validation failed: MaxCPUPercentPerApp must be between 10 and 100")
+		return fmt.Errorf("%w: MaxCPUPercentPerApp must be between 10 and 100", ErrValidation)
+	}
+	if p.AppUserUIDRangeStart < MinAppUserUID || p.AppUserUIDRangeEnd > MaxAppUserUID {
+		return fmt.Errorf("%w: UID range must stay within %d-%d", ErrValidation, MinAppUserUID, MaxAppUserUID)
 	}
 	if p.AppUserUIDRangeStart >= p.AppUserUIDRangeEnd {
-		return errors.New("domain validation failed: UID range start must be strictly less than range end")
+		return fmt.Errorf("%w: UID range start must be strictly less than range end", ErrValidation)
 	}
 	if p.BackupRetentionDays < 0 {
-		return errors.New("domain validation failed: BackupRetentionDays cannot be negative")
+		return fmt.Errorf("%w: BackupRetentionDays cannot be negative", ErrValidation)
+	}
+	switch p.DefaultFirewallPolicy {
+	case FirewallPolicyUnmanaged, FirewallPolicyAllow, FirewallPolicyDeny:
+	default:
+		return fmt.Errorf("%w: DefaultFirewallPolicy must be empty, %q or %q", ErrValidation, FirewallPolicyAllow, FirewallPolicyDeny)
 	}
+	// Stack defaults are checked against the live registry (StackCatalogue.ValidateDefaults)
 	return nil
 }
 
+// SystemProfilePatch is a partial update: nil fields keep their current
+// value. Version is the one the admin last read (OCC).
+type SystemProfilePatch struct {
+	DefaultStackRegistry  map[string]string
+	SSLStrategy           *string
+	MaxMemoryPerAppMB     *int
+	MaxCPUPercentPerApp   *int
+	DefaultFirewallPolicy *string
+	AppUserUIDRangeStart  *int
+	AppUserUIDRangeEnd    *int
+	BackupRetentionDays   *int
+	Version               int
+}
+
+// Apply returns a copy of p with the patch applied; p is left untouched.
+func (patch SystemProfilePatch) Apply(p SystemProfile) SystemProfile {
+	if patch.DefaultStackRegistry != nil {
+		p.DefaultStackRegistry = patch.DefaultStackRegistry
+	}
+	if patch.SSLStrategy != nil {
+		p.SSLStrategy = *patch.SSLStrategy
+	}
+	if patch.MaxMemoryPerAppMB != nil {
+		p.MaxMemoryPerAppMB = *patch.MaxMemoryPerAppMB
+	}
+	if patch.MaxCPUPercentPerApp != nil {
+		p.MaxCPUPercentPerApp = *patch.MaxCPUPercentPerApp
+	}
+	if patch.DefaultFirewallPolicy != nil {
+		p.DefaultFirewallPolicy = *patch.DefaultFirewallPolicy
+	}
+	if patch.AppUserUIDRangeStart != nil {
+		p.AppUserUIDRangeStart = *patch.AppUserUIDRangeStart
+	}
+	if patch.AppUserUIDRangeEnd != nil {
+		p.AppUserUIDRangeEnd = *patch.AppUserUIDRangeEnd
+	}
+	if patch.BackupRetentionDays != nil {
+		p.BackupRetentionDays = *patch.BackupRetentionDays
+	}
+	p.Version = patch.Version
+	return p
+}
+
+// ProfileRollout tracks which profile version each agent has applied.
+// Target is "local" for the Brain's own Muscle, otherwise the server ID.
+type ProfileRollout struct {
+	Target         string     `json:"target"`
+	AppliedVersion int        `json:"applied_version"`
+	AppliedAt      *time.Time `json:"applied_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// LocalRolloutTarget names the Brain's own Muscle in ProfileRollout.Target.
+const LocalRolloutTarget = "local"
+
 // SystemProfileRepository defines the interface for state persistence.
 type SystemProfileRepository interface {
 	GetActiveProfile(ctx context.Context) (*SystemProfile, error)
@@ -62,4 +143,16 @@ type SystemProfileRepository interface {
 	// 🛡️ Implementation detail for adapters: Must check the 'Version' field and 
 	// return a concurrency error if the DB version > the struct version.
 	UpdateProfile(ctx context.Context, profile *SystemProfile) error
+
+	ListRollouts(ctx context.Context) ([]ProfileRollout, error)
+	// RecordRollout upserts by Target. A failure keeps the last applied version.
+	RecordRollout(ctx context.Context, r ProfileRollout) error
+}
+
+// SystemProfileManager is the use-case boundary the HTTP handler depends on.
+type SystemProfileManager interface {
+	Get(ctx context.Context) (*SystemProfile, []ProfileRollout, error)
+	// Update returns ErrValidation for unsound limits and
+	// ErrConcurrencyConflict (db) for a stale Version.
+	Update(ctx context.Context, actorID uuid.UUID, patch SystemProfilePatch) (*SystemProfile, error)
 }
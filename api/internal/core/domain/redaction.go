@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultRedactionReplacement stands in for matched text when a rule sets none.
+const DefaultRedactionReplacement = "[REDACTED]"
+
+// RedactionRule is a custom pattern scrubbed from logs and audit metadata on
+// top of the built-in secret and PII patterns.
+type RedactionRule struct {
+	ID          uuid.UUID  `json:"id"`
+	TenantID    *uuid.UUID `json:"tenant_id,omitempty"` // nil = platform-wide
+	Name        string     `json:"name"`
+	Pattern     string     `json:"pattern"` // RE2 syntax
+	Replacement string     `json:"replacement"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type RedactionRuleRepository interface {
+	// List returns the platform-wide rules when tenantID is nil, otherwise
+	// only that tenant's own rules.
+	List(ctx context.Context, tenantID *uuid.UUID) ([]RedactionRule, error)
+	Create(ctx context.Context, rule *RedactionRule) error
+	// Delete removes a rule within the same scope List uses.
+	Delete(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) error
+}
+
+// LogRedactor scrubs secrets and PII before anything is persisted or
+// broadcast. uuid.Nil applies the platform-wide rules only.
+type LogRedactor interface {
+	RedactText(ctx context.Context, tenantID uuid.UUID, text string) string
+	RedactMetadata(ctx context.Context, tenantID uuid.UUID, metadata map[string]any) map[string]any
+}
+
+// RedactionManager is the rule administration surface.
+type RedactionManager interface {
+	ListRules(ctx context.Context, tenantID *uuid.UUID) ([]RedactionRule, error)
+	CreateRule(ctx context.Context, actorID uuid.UUID, tenantID *uuid.UUID, name, pattern, replacement string) (*RedactionRule, error)
+	DeleteRule(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) error
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RegistryCredential authenticates image pulls from a container registry.
+type RegistryCredential struct {
+	ID           uuid.UUID  `json:"id"`
+	OwnerID      uuid.UUID  `json:"owner_id"`
+	Name         string     `json:"name"`
+	Registry     string     `json:"registry"` // Host[:port]
+	Username     string     `json:"username"`
+	LastTestedAt *time.Time `json:"last_tested_at,omitempty"`
+	LastTestOK   *bool      `json:"last_test_ok,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+
+	// 🛡️ Privacy: Never serialized; AAD = "registry_credential:<id>"
+	PasswordCiphertext string `json:"-"`
+}
+
+// DockerHubRegistry is the host implied by unqualified refs like "nginx:1.27".
+const DockerHubRegistry = "docker.io"
+
+// ImageRegistryHost extracts the registry host from an image reference using
+// the Docker rules: the first path component is a host only if it contains
+// a '.' or ':' or is "localhost".
+func ImageRegistryHost(image string) string {
+	first, _, found := strings.Cut(image, "/")
+	if !found {
+		return DockerHubRegistry
+	}
+	if strings.ContainsAny(first, ".:") || first == "localhost" {
+		return first
+	}
+	return DockerHubRegistry
+}
+
+// NormalizeRegistryHost folds Docker Hub aliases onto one name.
+func NormalizeRegistryHost(host string) string {
+	switch host = strings.ToLower(strings.TrimSpace(host)); host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return DockerHubRegistry
+	default:
+		return host
+	}
+}
+
+var (
+	registryHostPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]*[a-z0-9])?(:[0-9]{1,5})?$`)
+	// 🛡️ Zero-Trust: repo[:tag][@digest] with no shell or flag metacharacters
+	imageRefPattern = regexp.MustCompile(`^[a-z0-9][a-zA-Z0-9._/:-]*(@sha256:[a-f0-9]{64})?$`)
+)
+
+// ValidateRegistryHost accepts a bare host[:port]; schemes and paths are rejected.
+func ValidateRegistryHost(host string) error {
+	if !registryHostPattern.MatchString(host) {
+		return fmt.Errorf("%w: registry must be a host name such as ghcr.io or registry.example.com:5000", ErrValidation)
+	}
+	return nil
+}
+
+// ValidateImageRef checks an image reference before it is stored or sent to the Muscle.
+func ValidateImageRef(image string) error {
+	if len(image) > 512 || !imageRefPattern.MatchString(image) {
+		return fmt.Errorf("%w: invalid image reference %q", ErrValidation, image)
+	}
+	return nil
+}
+
+// RegistryVerifier checks a credential against the registry's v2 API.
+type RegistryVerifier interface {
+	Verify(ctx context.Context, registry, username, password string) error
+}
+
+// RegistryCredentialManager is the tenant-facing credential and image-pull surface.
+// 🛡️ Tenant Isolation: Credentials and apps are always resolved for the caller.
+type RegistryCredentialManager interface {
+	CreateCredential(ctx context.Context, ownerID uuid.UUID, cred *RegistryCredential, password string) (*RegistryCredential, error)
+	ListCredentials(ctx context.Context, ownerID uuid.UUID) ([]RegistryCredential, error)
+	DeleteCredential(ctx context.Context, id, ownerID uuid.UUID) error
+	TestCredential(ctx context.Context, id, ownerID uuid.UUID) (*RegistryCredential, error)
+
+	// AttachImage points an image-based app at image, optionally with a credential.
+	AttachImage(ctx context.Context, appID, ownerID uuid.UUID, image string, credentialID *uuid.UUID) (*Application, error)
+	// PullImage asks the Muscle to pull the app's image, injecting its credential.
+	PullImage(ctx context.Context, appID, ownerID uuid.UUID) error
+}
+
+type RegistryCredentialRepository interface {
+	Create(ctx context.Context, cred *RegistryCredential) error
+	ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]RegistryCredential, error)
+	// GetByID is owner-scoped; returns ErrNotFound for other tenants' credentials
+	GetByID(ctx context.Context, id, ownerID uuid.UUID) (*RegistryCredential, error)
+	RecordTest(ctx context.Context, id uuid.UUID, ok bool) error
+	Delete(ctx context.Context, id, ownerID uuid.UUID) error
+}
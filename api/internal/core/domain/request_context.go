@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RequestContext is who made an API request and from where. The HTTP layer
+// attaches it to the request context; services and the audit trail read it
+// back instead of threading IP and trace IDs through every signature.
+type RequestContext struct {
+	UserID   uuid.UUID // uuid.Nil until authentication succeeds
+	RoleRank int       // Only meaningful when Authenticated(); 0 is Super Admin
+	IP       string
+	TraceID  string // The request ID echoed in error responses as trace_id
+}
+
+type requestContextKey struct{}
+
+// WithRequestContext returns ctx carrying rc, replacing any earlier one.
+func WithRequestContext(ctx context.Context, rc RequestContext) context.Context {
+	return context.WithValue(ctx, requestContextKey{}, rc)
+}
+
+// RequestContextFrom returns the request's context; ok is false outside an
+// API request (workers, schedulers, boot).
+func RequestContextFrom(ctx context.Context) (RequestContext, bool) {
+	rc, ok := ctx.Value(requestContextKey{}).(RequestContext)
+	return rc, ok
+}
+
+// WithActor records the authenticated user on the request's context.
+func WithActor(ctx context.Context, userID uuid.UUID, roleRank int) context.Context {
+	rc, _ := RequestContextFrom(ctx)
+	rc.UserID, rc.RoleRank = userID, roleRank
+	return WithRequestContext(ctx, rc)
+}
+
+func (rc RequestContext) Authenticated() bool { return rc.UserID != uuid.Nil }
+
+// AuditMetadata is the "request" object stored with audit entries, so an
+// entry can be traced to the API call (and error report) that caused it.
+func (rc RequestContext) AuditMetadata() map[string]any {
+	meta := map[string]any{}
+	if rc.IP != "" {
+		meta["ip"] = rc.IP
+	}
+	if rc.TraceID != "" {
+		meta["trace_id"] = rc.TraceID
+	}
+	if rc.Authenticated() {
+		meta["actor_id"] = rc.UserID.String()
+		meta["role_rank"] = rc.RoleRank
+	}
+	return meta
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResourceLimits caps what one user may own. A nil field is unlimited.
+type ResourceLimits struct {
+	MaxApplications *int `json:"max_applications"`
+	MaxDomains      *int `json:"max_domains"`
+	MaxMemoryMB     *int `json:"max_memory_mb"` // Sum of ReservedMemoryMB over the user's apps
+}
+
+// Merge returns l with every limit set in override replacing the inherited one.
+func (l ResourceLimits) Merge(override ResourceLimits) ResourceLimits {
+	if override.MaxApplications != nil {
+		l.MaxApplications = override.MaxApplications
+	}
+	if override.MaxDomains != nil {
+		l.MaxDomains = override.MaxDomains
+	}
+	if override.MaxMemoryMB != nil {
+		l.MaxMemoryMB = override.MaxMemoryMB
+	}
+	return l
+}
+
+// ResourceUsage is what a user owns, or what an operation would add.
+type ResourceUsage struct {
+	Applications int `json:"applications"`
+	Domains      int `json:"domains"`
+	MemoryMB     int `json:"memory_mb"`
+}
+
+// RoleLimits is a role's quota row.
+type RoleLimits struct {
+	RoleID   uuid.UUID `json:"role_id"`
+	RoleName string    `json:"role_name"`
+	ResourceLimits
+}
+
+// LimitOverride is an admin's per-user exception to the role quota.
+type LimitOverride struct {
+	ResourceLimits
+	Exempt    bool       `json:"exempt"` // Lifts every limit
+	Reason    string     `json:"reason"`
+	SetBy     *uuid.UUID `json:"set_by,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// QuotaReport is a user's effective limits next to their current usage.
+type QuotaReport struct {
+	UserID   uuid.UUID      `json:"user_id"`
+	RoleName string         `json:"role_name"`
+	Limits   ResourceLimits `json:"limits"` // Role limits with the override applied
+	Usage    ResourceUsage  `json:"usage"`
+	Override *LimitOverride `json:"override,omitempty"`
+}
+
+type ResourceQuotaRepository interface {
+	ListRoleLimits(ctx context.Context) ([]RoleLimits, error)
+	UpsertRoleLimits(ctx context.Context, roleID uuid.UUID, l ResourceLimits) error
+	// UserLimits returns the user's role name, role limits and override (nil if none).
+	UserLimits(ctx context.Context, userID uuid.UUID) (string, ResourceLimits, *LimitOverride, error)
+	UpsertOverride(ctx context.Context, userID uuid.UUID, o LimitOverride) error
+	DeleteOverride(ctx context.Context, userID uuid.UUID) error
+	Usage(ctx context.Context, userID uuid.UUID) (ResourceUsage, error)
+}
+
+// QuotaChecker is consulted by the creating services before they persist.
+// Returns ErrQuotaExceeded when adding delta would pass an effective limit.
+type QuotaChecker interface {
+	CheckQuota(ctx context.Context, userID uuid.UUID, delta ResourceUsage) error
+}
+
+// ResourceQuotaManager is the quota reporting and administration surface.
+type ResourceQuotaManager interface {
+	GetReport(ctx context.Context, userID uuid.UUID) (*QuotaReport, error)
+	ListRoleLimits(ctx context.Context) ([]RoleLimits, error)
+	SetRoleLimits(ctx context.Context, roleID uuid.UUID, l ResourceLimits) error
+	SetOverride(ctx context.Context, actorID, userID uuid.UUID, o LimitOverride) (*QuotaReport, error)
+	RemoveOverride(ctx context.Context, actorID, userID uuid.UUID) error
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// RetentionPolicy bounds how long rows in a log table are kept online.
+type RetentionPolicy struct {
+	Table         string `json:"table"`
+	RetentionDays int    `json:"retention_days"` // 0 disables pruning for this table
+}
+
+// ArchivedRow is a single expired row serialized for cold storage.
+type ArchivedRow struct {
+	ID        string          `json:"id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"` // Full row as JSONB (to_jsonb)
+}
+
+// TableSize reports on-disk usage for the admin storage dashboard.
+type TableSize struct {
+	Table       string `json:"table"`
+	RowEstimate int64  `json:"row_estimate"`
+	TotalBytes  int64  `json:"total_bytes"`
+	TableBytes  int64  `json:"table_bytes"`
+	IndexBytes  int64  `json:"index_bytes"`
+}
+
+// RetentionRepository exposes the primitives the pruning worker needs.
+type RetentionRepository interface {
+	// FetchExpired returns up to limit rows older than cutoff, oldest first.
+	FetchExpired(ctx context.Context, table string, cutoff time.Time, limit int) ([]ArchivedRow, error)
+	// DeleteRows removes rows by ID once they are safely archived.
+	DeleteRows(ctx context.Context, table string, ids []string) (int64, error)
+	// TableSizes reports storage usage for every application table.
+	TableSizes(ctx context.Context) ([]TableSize, error)
+}
+
+// ArchiveSink persists expired rows to cold storage (compressed files, S3, ...).
+// 🛡️ Stability: Implementations MUST return only after the batch is durable;
+// the pruner deletes rows from Postgres as soon as Archive returns nil.
+type ArchiveSink interface {
+	Archive(ctx context.Context, table string, rows []ArchivedRow) (location string, err error)
+}
@@ -0,0 +1,32 @@
+package domain
+
+// SupportedRuntimeVersions lists the runtimes Kari can build, with the
+// toolchain versions the stack registry was seeded with (migration 053).
+// The live catalogue is the StackCatalogue; admins add and deprecate
+// versions there without a release.
+var SupportedRuntimeVersions = map[string][]string{
+	"nodejs": {"18", "20", "22"},
+	"python": {"3.10", "3.11", "3.12"},
+	"go":     {"1.21", "1.22", "1.23"},
+	"php":    {"8.1", "8.2", "8.3"},
+	"ruby":   {"3.2", "3.3"},
+	"static": nil, // No toolchain
+	"image":  nil, // Prebuilt container image
+}
+
+// RuntimeOption is one entry of the runtime picker exposed to the UI.
+type RuntimeOption struct {
+	Runtime        string   `json:"runtime"`
+	Versions       []string `json:"versions"`                  // Available for new pins
+	Deprecated     []string `json:"deprecated,omitempty"`      // Kept by the apps already on them
+	DefaultVersion string   `json:"default_version,omitempty"` // From the stack registry
+}
+
+// ResolveRuntimeVersion returns the version an app builds with: its own pin,
+// otherwise the registry default.
+func (a *Application) ResolveRuntimeVersion(profile *SystemProfile) string {
+	if a.RuntimeVersion != "" {
+		return a.RuntimeVersion
+	}
+	return profile.DefaultStackRegistry[a.AppType]
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SagaStepStatus string
+
+const (
+	SagaStepPending     SagaStepStatus = "pending"
+	SagaStepRunning     SagaStepStatus = "running"
+	SagaStepDone        SagaStepStatus = "done"
+	SagaStepFailed      SagaStepStatus = "failed"
+	SagaStepSkipped     SagaStepStatus = "skipped"
+	SagaStepCompensated SagaStepStatus = "compensated" // Undone during rollback
+)
+
+type SagaStatus string
+
+const (
+	SagaRunning    SagaStatus = "running"
+	SagaSucceeded  SagaStatus = "succeeded"
+	SagaRolledBack SagaStatus = "rolled_back"
+	// SagaFailed means the saga could neither finish nor fully roll back;
+	// Steps shows what is left behind.
+	SagaFailed SagaStatus = "failed"
+)
+
+type SagaStep struct {
+	Name      string         `json:"name"`
+	Status    SagaStepStatus `json:"status"`
+	Error     string         `json:"error,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// Saga is the persisted state of one multi-step provisioning run. Data holds
+// the identifiers of resources created so far, which is all a compensation
+// needs to undo them after a restart.
+type Saga struct {
+	ID        uuid.UUID                  `json:"id"`
+	Kind      string                     `json:"kind"`
+	OwnerID   uuid.UUID                  `json:"-"`
+	Status    SagaStatus                 `json:"status"`
+	Steps     []SagaStep                 `json:"steps"`
+	Data      map[string]json.RawMessage `json:"-"`
+	CreatedAt time.Time                  `json:"created_at"`
+	UpdatedAt time.Time                  `json:"updated_at"`
+}
+
+// Finished reports whether the saga has stopped making progress.
+func (s *Saga) Finished() bool {
+	return s.Status != SagaRunning
+}
+
+type SagaRepository interface {
+	Create(ctx context.Context, s *Saga) error
+	// Update overwrites status, steps and data.
+	Update(ctx context.Context, s *Saga) error
+	// GetByID is tenant-isolated; another owner's saga is ErrNotFound.
+	GetByID(ctx context.Context, id uuid.UUID, ownerID uuid.UUID) (*Saga, error)
+	// ListRunning returns sagas a crash interrupted, oldest first.
+	ListRunning(ctx context.Context) ([]Saga, error)
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Vulnerability severities as OSV advisories report them (GHSA scale).
+const (
+	VulnSeverityCritical = "CRITICAL"
+	VulnSeverityHigh     = "HIGH"
+	VulnSeverityModerate = "MODERATE"
+	VulnSeverityLow      = "LOW"
+	VulnSeverityUnknown  = "UNKNOWN"
+)
+
+// SBOMComponent is one dependency cataloged from a built release.
+type SBOMComponent struct {
+	Name     string   `json:"name"`
+	Version  string   `json:"version"`
+	PURL     string   `json:"purl"` // Package URL; what vulnerability feeds match on
+	Licenses []string `json:"licenses,omitempty"`
+}
+
+// SBOM is the software bill of materials the Muscle produced for one build.
+// A redeploy unpacks an existing build, so it shares its source's SBOM.
+type SBOM struct {
+	DeploymentID string          `json:"deployment_id"`
+	AppID        string          `json:"app_id"`
+	DomainName   string          `json:"domain_name"`
+	Generator    string          `json:"generator"`
+	Components   []SBOMComponent `json:"components"`
+	Truncated    bool            `json:"truncated"` // The Muscle caps the component count
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// Vulnerability is an advisory as the feed describes it.
+type Vulnerability struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases"` // e.g. the CVE of a GHSA advisory
+	Summary  string   `json:"summary"`
+	Severity string   `json:"severity"`
+}
+
+// VulnerabilityFinding is an advisory matched against a component an app runs.
+type VulnerabilityFinding struct {
+	ID             string     `json:"id"`
+	AppID          string     `json:"app_id"`
+	DeploymentID   string     `json:"deployment_id"`
+	VulnID         string     `json:"vuln_id"`
+	Aliases        []string   `json:"aliases"`
+	Summary        string     `json:"summary"`
+	Severity       string     `json:"severity"`
+	PackageName    string     `json:"package_name"`
+	PackageVersion string     `json:"package_version"`
+	PURL           string     `json:"purl"`
+	FirstSeenAt    time.Time  `json:"first_seen_at"`
+	LastSeenAt     time.Time  `json:"last_seen_at"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+}
+
+type SBOMRepository interface {
+	Record(ctx context.Context, s *SBOM) error
+	// Current returns the SBOM of the app's latest successful deployment, or
+	// ErrNotFound when that deployment has none.
+	Current(ctx context.Context, appID string) (*SBOM, error)
+	// ListCurrent returns the current SBOM of every app that has one.
+	ListCurrent(ctx context.Context) ([]SBOM, error)
+}
+
+type VulnerabilityRepository interface {
+	// Sync replaces the app's open findings with this scan's: new and reappearing
+	// ones are returned, ones the scan no longer sees are resolved.
+	Sync(ctx context.Context, appID string, deploymentID string, findings []VulnerabilityFinding) ([]VulnerabilityFinding, error)
+	ListOpen(ctx context.Context, appID string) ([]VulnerabilityFinding, error)
+}
+
+// VulnerabilityFeed is an advisory database queried by Package URL (OSV).
+type VulnerabilityFeed interface {
+	// Query returns the advisory IDs affecting each purl; unaffected purls are absent.
+	Query(ctx context.Context, purls []string) (map[string][]string, error)
+	Get(ctx context.Context, id string) (*Vulnerability, error)
+}
+
+// SBOMViewer is the contract behind /applications/{id}/sbom, /vulnerabilities
+// and /licenses.
+type SBOMViewer interface {
+	GetSBOM(ctx context.Context, appID uuid.UUID, userID uuid.UUID) (*SBOM, error)
+	ListVulnerabilities(ctx context.Context, appID uuid.UUID, userID uuid.UUID) ([]VulnerabilityFinding, error)
+	LicenseReport(ctx context.Context, appID uuid.UUID, userID uuid.UUID) (*LicenseReport, error)
+}
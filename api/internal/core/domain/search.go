@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchKind identifies which index a hit came from.
+type SearchKind string
+
+const (
+	SearchKindApplication   SearchKind = "application"
+	SearchKindDomain        SearchKind = "domain"
+	SearchKindDeploymentLog SearchKind = "deployment_log"
+	SearchKindAudit         SearchKind = "audit"
+)
+
+// SearchQuery is the permission-resolved input to a global search.
+// 🛡️ Zero-Trust: Kinds is computed by the handler from the caller's JWT scopes;
+// an index the caller cannot read is never queried at all.
+type SearchQuery struct {
+	UserID     uuid.UUID
+	Text       string
+	Kinds      []SearchKind
+	AllTenants bool // Platform admins only: skip tenant scoping for audit hits
+	Limit      int
+}
+
+// SearchResult is a single ranked hit.
+type SearchResult struct {
+	Kind       SearchKind `json:"kind"`
+	ID         string     `json:"id"`
+	Title      string     `json:"title"`
+	Snippet    string     `json:"snippet,omitempty"`
+	ResourceID string     `json:"resource_id,omitempty"` // Parent app/deployment for deep links
+	Rank       float32    `json:"rank"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type SearchRepository interface {
+	Search(ctx context.Context, q SearchQuery) ([]SearchResult, error)
+}
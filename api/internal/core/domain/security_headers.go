@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Security header presets. Explicit fields in SecurityHeaders override the
+// preset's value for that header; "none" starts from nothing.
+const (
+	HeaderPresetNone   = "none"
+	HeaderPresetBasic  = "basic"  // Safe for almost any site, no CSP
+	HeaderPresetStrict = "strict" // Same-origin everything; may break third-party embeds
+)
+
+const maxHeaderValueLength = 4096
+
+// SecurityHeaders is a domain's response header policy as stored.
+type SecurityHeaders struct {
+	Preset            string `json:"preset"`
+	CSP               string `json:"content_security_policy,omitempty"`
+	FrameOptions      string `json:"x_frame_options,omitempty"`
+	ReferrerPolicy    string `json:"referrer_policy,omitempty"`
+	PermissionsPolicy string `json:"permissions_policy,omitempty"`
+}
+
+// HTTPHeader is one rendered response header.
+type HTTPHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// DomainSecurityHeaders is the API view: the stored policy plus what the
+// vhost actually sends.
+type DomainSecurityHeaders struct {
+	DomainID   uuid.UUID       `json:"domain_id"`
+	DomainName string          `json:"domain_name"`
+	Settings   SecurityHeaders `json:"settings"`
+	Effective  []HTTPHeader    `json:"effective"`
+	UpdatedAt  *time.Time      `json:"updated_at,omitempty"` // nil = never configured
+}
+
+// SecurityHeaderPresets are the starting points offered in the dashboard.
+var SecurityHeaderPresets = map[string]SecurityHeaders{
+	HeaderPresetNone: {},
+	HeaderPresetBasic: {
+		FrameOptions:      "SAMEORIGIN",
+		ReferrerPolicy:    "strict-origin-when-cross-origin",
+		PermissionsPolicy: "camera=(), microphone=(), geolocation=()",
+	},
+	HeaderPresetStrict: {
+		CSP:               "default-src 'self'; object-src 'none'; base-uri 'self'; frame-ancestors 'none'",
+		FrameOptions:      "DENY",
+		ReferrerPolicy:    "no-referrer",
+		PermissionsPolicy: "camera=(), microphone=(), geolocation=(), payment=(), usb=()",
+	},
+}
+
+var (
+	frameOptionValues    = []string{"DENY", "SAMEORIGIN"}
+	referrerPolicyValues = []string{
+		"no-referrer", "no-referrer-when-downgrade", "origin", "origin-when-cross-origin",
+		"same-origin", "strict-origin", "strict-origin-when-cross-origin", "unsafe-url",
+	}
+)
+
+// Resolve merges the preset with explicit overrides into the header list
+// sent to the Muscle, in a stable order.
+func (s SecurityHeaders) Resolve() []HTTPHeader {
+	base := SecurityHeaderPresets[s.Preset]
+	pick := func(override, preset string) string {
+		if override != "" {
+			return override
+		}
+		return preset
+	}
+
+	headers := []HTTPHeader{}
+	for _, h := range []HTTPHeader{
+		{"Content-Security-Policy", pick(s.CSP, base.CSP)},
+		{"X-Frame-Options", pick(s.FrameOptions, base.FrameOptions)},
+		{"Referrer-Policy", pick(s.ReferrerPolicy, base.ReferrerPolicy)},
+		{"Permissions-Policy", pick(s.PermissionsPolicy, base.PermissionsPolicy)},
+	} {
+		if h.Value != "" {
+			headers = append(headers, h)
+		}
+	}
+	return headers
+}
+
+// ValidateSecurityHeaders rejects unknown presets, out-of-vocabulary values,
+// and anything that could escape the quoted header value in a vhost file.
+func ValidateSecurityHeaders(s SecurityHeaders) error {
+	if _, ok := SecurityHeaderPresets[s.Preset]; !ok {
+		return fmt.Errorf("%w: preset must be one of none, basic, strict", ErrValidation)
+	}
+	if s.FrameOptions != "" && !slices.Contains(frameOptionValues, s.FrameOptions) {
+		return fmt.Errorf("%w: x_frame_options must be DENY or SAMEORIGIN", ErrValidation)
+	}
+	if s.ReferrerPolicy != "" && !slices.Contains(referrerPolicyValues, s.ReferrerPolicy) {
+		return fmt.Errorf("%w: unknown referrer_policy %q", ErrValidation, s.ReferrerPolicy)
+	}
+	for name, value := range map[string]string{
+		"content_security_policy": s.CSP,
+		"permissions_policy":      s.PermissionsPolicy,
+	} {
+		if err := validateHeaderValue(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateHeaderValue allows printable ASCII minus the characters nginx and
+// Apache treat specially inside a quoted directive argument ("\$%).
+func validateHeaderValue(name, value string) error {
+	if len(value) > maxHeaderValueLength {
+		return fmt.Errorf("%w: %s exceeds %d characters", ErrValidation, name, maxHeaderValueLength)
+	}
+	for _, c := range value {
+		if c < 0x20 || c > 0x7e || strings.ContainsRune(`"\$%`, c) {
+			return fmt.Errorf("%w: %s contains a forbidden character %q", ErrValidation, name, c)
+		}
+	}
+	return nil
+}
+
+type SecurityHeadersRepository interface {
+	// Get returns the domain's policy; ErrNotFound unless ownerID owns the domain.
+	Get(ctx context.Context, domainID, ownerID uuid.UUID) (*DomainSecurityHeaders, error)
+	Upsert(ctx context.Context, domainID uuid.UUID, settings SecurityHeaders) (time.Time, error)
+}
+
+// SecurityHeadersManager is the tenant-facing header policy API.
+type SecurityHeadersManager interface {
+	GetHeaders(ctx context.Context, domainID, userID uuid.UUID) (*DomainSecurityHeaders, error)
+	UpdateHeaders(ctx context.Context, domainID, userID uuid.UUID, settings SecurityHeaders) (*DomainSecurityHeaders, error)
+}
@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ServerStatus string
+
+const (
+	ServerPending ServerStatus = "pending" // Enrolled, never answered a probe
+	ServerOnline  ServerStatus = "online"
+	ServerOffline ServerStatus = "offline"
+)
+
+// ServerExecutor is how the Brain drives a server.
+type ServerExecutor string
+
+const (
+	ExecutorAgent ServerExecutor = "agent" // Rust Muscle over mTLS gRPC
+	ExecutorSSH   ServerExecutor = "ssh"   // Shell commands over SSH, for hosts without the Muscle
+)
+
+// ServerCapability is a feature flag a server's executor declares.
+// Services check it before sending work the server cannot do.
+type ServerCapability string
+
+const (
+	CapabilityAppUsers    ServerCapability = "app_users"    // ProvisionAppJail: jail user + app directory
+	CapabilityAppUnits    ServerCapability = "app_units"    // ProvisionAppJail also writes the cgroup-limited unit
+	CapabilitySystemFiles ServerCapability = "system_files" // WriteSystemFile: vhosts, units, certificates
+	CapabilityServices    ServerCapability = "services"     // ManageService
+	CapabilityBuilds      ServerCapability = "builds"       // StreamDeployment: clone + build
+	CapabilityReleases    ServerCapability = "releases"     // StreamDeployment also installs and starts the release
+
+	// Discovered from the host itself (GetCapabilities), not the executor
+	CapabilityContainers ServerCapability = "containers"  // A container runtime for image-based apps
+	CapabilityPHP        ServerCapability = "php"         // At least one PHP-FPM version
+	CapabilityDiskQuotas ServerCapability = "disk_quotas" // Per-user filesystem quotas
+	CapabilityFirewall   ServerCapability = "firewall"    // ApplyFirewallPolicy has a backend
+)
+
+// AgentServerCapabilities are declared for a server that enrolled with the
+// Muscle: everything the Brain can ask of it.
+var AgentServerCapabilities = []ServerCapability{
+	CapabilityAppUsers, CapabilityAppUnits, CapabilitySystemFiles,
+	CapabilityServices, CapabilityBuilds, CapabilityReleases,
+}
+
+// SSHServerCapabilities are what the SSH executor can do with plain shell
+// commands. Jailed units and release activation need the Muscle.
+var SSHServerCapabilities = []ServerCapability{
+	CapabilityAppUsers, CapabilitySystemFiles, CapabilityServices, CapabilityBuilds,
+}
+
+// HostCapabilities is what a server reported about itself the last time it
+// was asked. Nil on a Server means discovery has not run yet.
+type HostCapabilities struct {
+	ContainerRuntime string    `json:"container_runtime,omitempty"` // podman, docker
+	PHPVersions      []string  `json:"php_versions,omitempty"`
+	DiskQuotas       bool      `json:"disk_quotas"`
+	Firewall         string    `json:"firewall,omitempty"` // nftables, iptables
+	AgentVersion     string    `json:"agent_version"`      // Rediscovered when this changes
+	DiscoveredAt     time.Time `json:"discovered_at"`
+}
+
+// Capabilities are the flags the host facts imply.
+func (h *HostCapabilities) Capabilities() []ServerCapability {
+	var caps []ServerCapability
+	if h.ContainerRuntime != "" {
+		caps = append(caps, CapabilityContainers)
+	}
+	if len(h.PHPVersions) > 0 {
+		caps = append(caps, CapabilityPHP)
+	}
+	if h.DiskQuotas {
+		caps = append(caps, CapabilityDiskQuotas)
+	}
+	if h.Firewall != "" {
+		caps = append(caps, CapabilityFirewall)
+	}
+	return caps
+}
+
+// ExecutorCapabilities are the flags an executor declares by itself.
+func ExecutorCapabilities(e ServerExecutor) []ServerCapability {
+	if e == ExecutorSSH {
+		return SSHServerCapabilities
+	}
+	return AgentServerCapabilities
+}
+
+// Server is a remote host the Brain drives: a Muscle agent that joined with
+// an enrollment token, or an SSH host registered by an admin. The local
+// agent on the Brain's own host is implicit and has no row.
+type Server struct {
+	ID              uuid.UUID          `json:"id"`
+	Name            string             `json:"name"`
+	Hostname        string             `json:"hostname"`
+	Executor        ServerExecutor     `json:"executor"`
+	Capabilities    []ServerCapability `json:"capabilities"`
+	CertSerial      string             `json:"cert_serial,omitempty"`      // Agent servers only
+	CertFingerprint string             `json:"cert_fingerprint,omitempty"` // SHA-256 of the DER, hex
+	CertExpiresAt   *time.Time         `json:"cert_expires_at,omitempty"`
+	Host            *HostCapabilities  `json:"host,omitempty"`
+	Status          ServerStatus       `json:"status"`
+	LastSeenAt      *time.Time         `json:"last_seen_at,omitempty"`
+	AgentVersion    string             `json:"agent_version,omitempty"`
+	EnrolledAt      time.Time          `json:"enrolled_at"`
+}
+
+// Supports reports whether the server's executor declared the capability.
+func (s *Server) Supports(c ServerCapability) bool {
+	for _, have := range s.Capabilities {
+		if have == c {
+			return true
+		}
+	}
+	return false
+}
+
+// EnrollToken is returned once when issued. The agent presents Token and
+// pins the Brain's agent CA by CACertHash before trusting anything it gets.
+type EnrollToken struct {
+	Token      string    `json:"token"`
+	ServerName string    `json:"server_name"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CACertHash string    `json:"ca_cert_hash"` // "sha256:<hex>" of the CA certificate
+	EnrollURL  string    `json:"enroll_url"`
+}
+
+// EnrollTokenClaim is what an unused token authorizes: one server, by name.
+type EnrollTokenClaim struct {
+	ServerName string
+	CreatedBy  *uuid.UUID
+}
+
+// EnrollRequest is what a joining agent sends. CSR is a PEM certificate
+// request for the key the agent generated and never sends.
+type EnrollRequest struct {
+	Token    string `json:"token"`
+	Hostname string `json:"hostname"`
+	CSR      string `json:"csr"`
+}
+
+// Enrollment is the agent's long-term identity: a client/server certificate
+// signed by the agent CA, and the CA to verify the Brain with.
+type Enrollment struct {
+	ServerID      uuid.UUID `json:"server_id"`
+	Certificate   string    `json:"certificate"`    // PEM
+	CACertificate string    `json:"ca_certificate"` // PEM
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// SSHServerRequest registers a host the Brain drives over SSH. HostKey is
+// the server's public host key (a known_hosts or ssh-keyscan line); it is
+// pinned, never learned on first use.
+type SSHServerRequest struct {
+	Name     string `json:"name"`
+	Hostname string `json:"hostname"`
+	Port     int    `json:"port"`
+	Username string `json:"username"` // Needs passwordless sudo
+	HostKey  string `json:"host_key"`
+}
+
+// SSHServer is returned once on registration: PublicKey goes into the
+// user's authorized_keys before the first probe can succeed.
+type SSHServer struct {
+	Server
+	PublicKey string `json:"public_key"`
+}
+
+// SSHCredential is a stored SSH login, as the repository holds it.
+type SSHCredential struct {
+	ServerID             uuid.UUID
+	Username             string
+	Port                 int
+	HostKey              string
+	PublicKey            string
+	PrivateKeyCiphertext string // 🛡️ Privacy: AAD = ServerID
+}
+
+type ServerRepository interface {
+	CreateEnrollToken(ctx context.Context, tokenHash, serverName string, createdBy uuid.UUID, expiresAt time.Time) error
+	// PeekEnrollToken returns ErrNotFound for unknown, expired and used tokens.
+	PeekEnrollToken(ctx context.Context, tokenHash string) (*EnrollTokenClaim, error)
+	// EnrollServer burns the token and inserts the server in one transaction,
+	// so a rejected insert leaves the token usable. A token that was used
+	// or expired meanwhile returns ErrNotFound; a taken name ErrConflict.
+	EnrollServer(ctx context.Context, s *Server, tokenHash string) error
+	ListServers(ctx context.Context) ([]Server, error)
+	GetServer(ctx context.Context, id uuid.UUID) (*Server, error)
+
+	RecordHeartbeat(ctx context.Context, id uuid.UUID, agentVersion string, seenAt time.Time) error
+	SetServerStatus(ctx context.Context, id uuid.UUID, status ServerStatus) error
+	// RecordHostCapabilities stores what discovery found and replaces the
+	// server's capability flags with the executor's plus the host's.
+	RecordHostCapabilities(ctx context.Context, id uuid.UUID, host *HostCapabilities, caps []ServerCapability) error
+
+	// CreateSSHServer inserts the server and its credential together; a
+	// taken name returns ErrConflict.
+	CreateSSHServer(ctx context.Context, s *Server, cred *SSHCredential) error
+	GetSSHCredential(ctx context.Context, serverID uuid.UUID) (*SSHCredential, error)
+}
+
+// ServerAvailability is the ServerMonitor's in-memory view, read by workers
+// that should stay quiet about apps whose whole server is down.
+type ServerAvailability interface {
+	ServerOffline(id uuid.UUID) bool
+}
+
+// ServerEnrollment is the contract behind the /servers endpoints.
+type ServerEnrollment interface {
+	IssueEnrollToken(ctx context.Context, actorID uuid.UUID, serverName string, ttl time.Duration) (*EnrollToken, error)
+	// Enroll returns ErrInvalidCredentials for any token it will not honor.
+	Enroll(ctx context.Context, req EnrollRequest) (*Enrollment, error)
+	RegisterSSHServer(ctx context.Context, actorID uuid.UUID, req SSHServerRequest) (*SSHServer, error)
+	ListServers(ctx context.Context) ([]Server, error)
+}
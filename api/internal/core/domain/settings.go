@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultMaintenanceMessage is shown when an admin enables maintenance
+// mode without saying why.
+const DefaultMaintenanceMessage = "Kari is in maintenance mode; changes are temporarily disabled."
+
+// MaintenanceMode makes the panel read-only for everyone but admins.
+type MaintenanceMode struct {
+	Enabled bool       `json:"enabled"`
+	Message string     `json:"message,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+	SetBy   *uuid.UUID `json:"set_by,omitempty"`
+}
+
+// PasskeyPolicy decides when a password alone is enough to sign in.
+type PasskeyPolicy struct {
+	// RequireForRank0 makes Rank-0 (Super Admin) accounts sign in with a passkey.
+	RequireForRank0 bool `json:"require_for_rank0"`
+	// PasswordFallback lets a Rank-0 account that has not enrolled a passkey
+	// yet still use its password, so it can enroll one. Off locks it out.
+	PasswordFallback bool `json:"password_fallback"`
+}
+
+// DefaultPasskeyPolicy is used until an admin changes it.
+var DefaultPasskeyPolicy = PasskeyPolicy{PasswordFallback: true}
+
+// PasswordPolicy is enforced whenever a password is set or changed.
+type PasswordPolicy struct {
+	MinLength   int  `json:"min_length" validate:"min=8,max=72"` // bcrypt reads at most 72 bytes
+	MaxAgeDays  int  `json:"max_age_days" validate:"min=0"`      // 0 = passwords never expire
+	BreachCheck bool `json:"breach_check"`                       // Reject passwords found in public breach corpora
+}
+
+// DefaultPasswordPolicy is used until an admin changes it.
+var DefaultPasswordPolicy = PasswordPolicy{MinLength: 12, BreachCheck: true}
+
+// SystemSettings is the panel-wide configuration row.
+type SystemSettings struct {
+	Maintenance MaintenanceMode `json:"maintenance"`
+	Passkeys    PasskeyPolicy   `json:"passkeys"`
+	Passwords   PasswordPolicy  `json:"passwords"`
+	Timezone    string          `json:"timezone"` // IANA name; schedules and ?tz=install use it
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+type SettingsRepository interface {
+	Get(ctx context.Context) (*SystemSettings, error)
+	SaveMaintenance(ctx context.Context, m MaintenanceMode) error
+	SavePasskeyPolicy(ctx context.Context, p PasskeyPolicy) error
+	SavePasswordPolicy(ctx context.Context, p PasswordPolicy) error
+	SaveTimezone(ctx context.Context, tz string) error
+}
+
+// MaintenanceState is the hot-path view read by the request guard and /health.
+type MaintenanceState interface {
+	Maintenance() MaintenanceMode
+}
+
+// PasskeyPolicySource is the hot-path view read on every password login.
+type PasskeyPolicySource interface {
+	PasskeyPolicy() PasskeyPolicy
+}
+
+// PasswordPolicySource is read whenever a password is set or checked for age.
+type PasswordPolicySource interface {
+	PasswordPolicy() PasswordPolicy
+}
+
+// SettingsManager is the admin settings API.
+type SettingsManager interface {
+	MaintenanceState
+	PasskeyPolicySource
+	PasswordPolicySource
+	TimezoneSource
+	GetSettings(ctx context.Context) (*SystemSettings, error)
+	SetMaintenance(ctx context.Context, actorID uuid.UUID, enabled bool, message string) (*MaintenanceMode, error)
+	SetPasskeyPolicy(ctx context.Context, actorID uuid.UUID, p PasskeyPolicy) (*PasskeyPolicy, error)
+	SetPasswordPolicy(ctx context.Context, actorID uuid.UUID, p PasswordPolicy) (*PasswordPolicy, error)
+	SetTimezone(ctx context.Context, actorID uuid.UUID, tz string) (string, error)
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SagaKindSite is the saga kind behind a site creation.
+const SagaKindSite = "site.create"
+
+// Steps of a site creation, in execution order.
+const (
+	SiteStepDomain      = "domain"
+	SiteStepApplication = "application"
+	SiteStepSSL         = "ssl"
+	SiteStepDeployment  = "deployment"
+)
+
+// SiteRequest is POST /api/v1/sites: a domain, the app serving it, an
+// optional certificate and the first deployment.
+type SiteRequest struct {
+	DomainName   string
+	DocumentRoot string
+	App          Application
+	SSLEmail     string // ACME account email; empty skips the certificate
+}
+
+// SiteOperation tracks one site creation saga. Every change is published to
+// watchers as a full snapshot, so a client that connects late loses nothing.
+type SiteOperation struct {
+	ID           uuid.UUID  `json:"id"`
+	OwnerID      uuid.UUID  `json:"-"`
+	Status       SagaStatus `json:"status"`
+	DomainID     *uuid.UUID `json:"domain_id,omitempty"`
+	AppID        *uuid.UUID `json:"app_id,omitempty"`
+	DeploymentID string     `json:"deployment_id,omitempty"` // Subscribe to its build logs
+	Steps        []SagaStep `json:"steps"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Finished reports whether the saga has stopped making progress.
+func (o *SiteOperation) Finished() bool {
+	return o.Status != SagaRunning
+}
+
+type SiteManager interface {
+	// CreateSite starts the saga and returns at once; progress is read with
+	// GetOperation or streamed with Watch.
+	CreateSite(ctx context.Context, userID uuid.UUID, req SiteRequest) (*SiteOperation, error)
+	GetOperation(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*SiteOperation, error)
+	// Watch sends the current snapshot, then one per change, and closes the
+	// channel once the operation finishes or ctx ends.
+	Watch(ctx context.Context, userID uuid.UUID, id uuid.UUID) (<-chan SiteOperation, error)
+}
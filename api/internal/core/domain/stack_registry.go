@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StackVersionStatus is where a toolchain version is in its lifecycle.
+type StackVersionStatus string
+
+const (
+	StackVersionAvailable  StackVersionStatus = "available"  // Pinnable, and eligible as the default
+	StackVersionDeprecated StackVersionStatus = "deprecated" // Apps already on it keep it; no new pins
+)
+
+// StackVersion is one toolchain version in the stack registry.
+type StackVersion struct {
+	Runtime      string             `json:"runtime"`
+	Version      string             `json:"version"`
+	Status       StackVersionStatus `json:"status"`
+	IsDefault    bool               `json:"is_default"` // SystemProfile.DefaultStackRegistry names it
+	AppCount     int                `json:"app_count"`  // Apps building with it, pinned or through the default
+	AddedAt      time.Time          `json:"added_at"`
+	DeprecatedAt *time.Time         `json:"deprecated_at,omitempty"`
+}
+
+// StackVersionApp is an app building with a given stack version.
+type StackVersionApp struct {
+	AppID      uuid.UUID `json:"app_id"`
+	DomainName string    `json:"domain_name"`
+	OwnerID    uuid.UUID `json:"owner_id"`
+	Pinned     bool      `json:"pinned"` // false = follows the registry default
+}
+
+// StackMigration reports a bulk move of apps from one version to another.
+type StackMigration struct {
+	Runtime       string   `json:"runtime"`
+	From          string   `json:"from"`
+	To            string   `json:"to"`
+	Apps          int      `json:"apps"`
+	DeploymentIDs []string `json:"deployment_ids"` // Queued redeploys (some may await approval)
+}
+
+// stackVersionPattern matches what the Muscle accepts as a runtimes
+// directory segment: digits and dots, e.g. "8.4" or "1.23".
+var stackVersionPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+){0,2}$`)
+
+// ValidateStackVersion checks a new registry entry.
+func ValidateStackVersion(runtime, version string) error {
+	versions, ok := SupportedRuntimeVersions[runtime]
+	if !ok {
+		return fmt.Errorf("%w: unsupported runtime %q", ErrValidation, runtime)
+	}
+	if versions == nil {
+		return fmt.Errorf("%w: %s has no toolchain to version", ErrValidation, runtime)
+	}
+	if !stackVersionPattern.MatchString(version) {
+		return fmt.Errorf("%w: version must look like 8.4 or 1.23", ErrValidation)
+	}
+	return nil
+}
+
+// StackCatalogue is the live stack registry.
+type StackCatalogue []StackVersion
+
+// SeedStackCatalogue is the catalogue before any admin change, for callers
+// without a registry repository.
+func SeedStackCatalogue() StackCatalogue {
+	var c StackCatalogue
+	for _, runtime := range slices.Sorted(maps.Keys(SupportedRuntimeVersions)) {
+		for _, v := range SupportedRuntimeVersions[runtime] {
+			c = append(c, StackVersion{Runtime: runtime, Version: v, Status: StackVersionAvailable})
+		}
+	}
+	return c
+}
+
+func (c StackCatalogue) Find(runtime, version string) *StackVersion {
+	for i := range c {
+		if c[i].Runtime == runtime && c[i].Version == version {
+			return &c[i]
+		}
+	}
+	return nil
+}
+
+// ValidatePin checks the version an app is about to pin. An empty version
+// means "follow the stack registry default". A deprecated version is only
+// accepted if the app is already on it (current).
+func (c StackCatalogue) ValidatePin(runtime, version, current string) error {
+	if _, ok := SupportedRuntimeVersions[runtime]; !ok {
+		return fmt.Errorf("%w: unsupported runtime %q", ErrValidation, runtime)
+	}
+	if version == "" {
+		return nil
+	}
+	v := c.Find(runtime, version)
+	switch {
+	case v == nil:
+		return fmt.Errorf("%w: %s %s is not a supported version", ErrValidation, runtime, version)
+	case v.Status == StackVersionDeprecated && version != current:
+		return fmt.Errorf("%w: %s %s is deprecated", ErrValidation, runtime, version)
+	}
+	return nil
+}
+
+// ValidateDefaults checks the registry defaults of a SystemProfile: each
+// toolchain runtime's default must be an available version. Non-runtime
+// entries (e.g. databases) are not pinned here.
+func (c StackCatalogue) ValidateDefaults(defaults map[string]string) error {
+	for runtime, version := range defaults {
+		if versions, known := SupportedRuntimeVersions[runtime]; !known || versions == nil {
+			continue
+		}
+		v := c.Find(runtime, version)
+		if v == nil || v.Status != StackVersionAvailable {
+			return fmt.Errorf("%w: stack default %s %s is not an available version", ErrValidation, runtime, version)
+		}
+	}
+	return nil
+}
+
+// StackRegistryRepository persists the catalogue. AppCount and IsDefault
+// are derived from the applications and the active SystemProfile.
+type StackRegistryRepository interface {
+	ListStackVersions(ctx context.Context) (StackCatalogue, error)
+	// CreateStackVersion returns ErrConflict if the version is already listed.
+	CreateStackVersion(ctx context.Context, runtime, version string) (*StackVersion, error)
+	SetStackVersionStatus(ctx context.Context, runtime, version string, status StackVersionStatus) error
+	// DeleteStackVersion returns ErrConflict while any app is pinned to it.
+	DeleteStackVersion(ctx context.Context, runtime, version string) error
+	ListStackVersionApps(ctx context.Context, runtime, version string) ([]StackVersionApp, error)
+	// RepinApps moves every app pinned to from onto to, plus the unpinned
+	// ones when includeUnpinned (from is the default), and returns them.
+	RepinApps(ctx context.Context, runtime, from, to string, includeUnpinned bool) ([]Application, error)
+}
+
+// StackRegistryManager is the admin stack registry API.
+type StackRegistryManager interface {
+	ListStackVersions(ctx context.Context) (StackCatalogue, error)
+	AddStackVersion(ctx context.Context, actorID uuid.UUID, runtime, version string) (*StackVersion, error)
+	SetStackVersionStatus(ctx context.Context, actorID uuid.UUID, runtime, version string, status StackVersionStatus) (*StackVersion, error)
+	RemoveStackVersion(ctx context.Context, actorID uuid.UUID, runtime, version string) error
+	StackVersionApps(ctx context.Context, runtime, version string) ([]StackVersionApp, error)
+	MigrateApps(ctx context.Context, actorID uuid.UUID, runtime, from, to string) (*StackMigration, error)
+}
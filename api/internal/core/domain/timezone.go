@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTimezone is the install time zone until an admin sets one.
+const DefaultTimezone = "UTC"
+
+// TimezoneSource is the install time zone. Schedulers read it on every pass,
+// so a change applies without a restart.
+type TimezoneSource interface {
+	Location() *time.Location
+}
+
+// UserTimezoneRepository stores each user's own zone; "" follows the install.
+type UserTimezoneRepository interface {
+	GetUserTimezone(ctx context.Context, userID uuid.UUID) (string, error)
+	SetUserTimezone(ctx context.Context, userID uuid.UUID, tz string) error
+}
+
+// TimezonePreference is a user's setting and the zone actually in effect.
+type TimezonePreference struct {
+	Timezone  string `json:"timezone"`  // "" = the install's zone
+	Effective string `json:"effective"` // What schedules and ?tz=user use
+}
+
+// TimezoneManager is the per-user time zone API.
+type TimezoneManager interface {
+	GetPreference(ctx context.Context, userID uuid.UUID) (*TimezonePreference, error)
+	SetPreference(ctx context.Context, userID uuid.UUID, tz string) (*TimezonePreference, error)
+	// LocationFor is the user's zone, else the install's.
+	LocationFor(ctx context.Context, userID uuid.UUID) *time.Location
+}
+
+// LoadTimezone resolves an IANA zone name such as "Europe/Istanbul".
+// "Local" is refused: it is the server time this setting exists to replace.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" || strings.EqualFold(name, "local") {
+		return nil, fmt.Errorf("%w: timezone must be an IANA name such as Europe/Istanbul", ErrValidation)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrValidation, name)
+	}
+	return loc, nil
+}
+
+// NextLocalHour returns the first moment after now at which the wall clock
+// in loc reads hour:00. Across a DST change it is the same wall-clock hour,
+// not 24 elapsed hours later.
+func NextLocalHour(now time.Time, loc *time.Location, hour int) time.Time {
+	local := now.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), hour, 0, 0, 0, loc)
+	if !next.After(now) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, hour, 0, 0, 0, loc)
+	}
+	return next
+}
+
+// localLayouts are the wall-clock forms ParseLocalTime accepts besides RFC 3339.
+var localLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"}
+
+// ParseLocalTime parses an RFC 3339 timestamp as is, or a wall-clock time
+// without an offset ("2026-03-29T02:00") in loc.
+func ParseLocalTime(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q is not an RFC 3339 or local (YYYY-MM-DDTHH:MM) time", ErrValidation, s)
+}
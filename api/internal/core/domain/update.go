@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReleaseInfo is one entry of the signed release feed.
+type ReleaseInfo struct {
+	Version     string    `json:"version"`
+	URL         string    `json:"url"`
+	SHA256      string    `json:"sha256"`    // Hex digest of the kari-api binary
+	Signature   string    `json:"signature"` // Base64 Ed25519 signature over SignedManifest()
+	Notes       string    `json:"notes,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// SignedManifest is the exact byte string the release key signs. Binding the
+// version to the digest prevents replaying an old signed binary as a "new" one.
+func (r ReleaseInfo) SignedManifest() []byte {
+	return []byte("kari-api " + r.Version + " sha256:" + r.SHA256)
+}
+
+// UpdateState tracks the self-update lifecycle.
+type UpdateState string
+
+const (
+	UpdateStateIdle        UpdateState = "idle"
+	UpdateStateDownloading UpdateState = "downloading"
+	UpdateStateVerifying   UpdateState = "verifying"
+	UpdateStateInstalling  UpdateState = "installing" // Handed to the Muscle; restart imminent
+	UpdateStateFailed      UpdateState = "failed"
+)
+
+// UpdateStatus is returned by GET /api/v1/system/update.
+type UpdateStatus struct {
+	CurrentVersion  string       `json:"current_version"`
+	Latest          *ReleaseInfo `json:"latest,omitempty"`
+	UpdateAvailable bool         `json:"update_available"`
+	State           UpdateState  `json:"state"`
+	LastError       string       `json:"last_error,omitempty"`
+	CheckedAt       time.Time    `json:"checked_at"`
+}
+
+// SystemUpdater is the admin-facing self-update contract.
+type SystemUpdater interface {
+	Check(ctx context.Context) (*UpdateStatus, error)
+	Apply(ctx context.Context, actorID uuid.UUID) (*UpdateStatus, error)
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ManagedUser is the admin view of an account. It never carries the
+// password hash or refresh token.
+type ManagedUser struct {
+	ID                 uuid.UUID  `json:"id"`
+	Email              string     `json:"email"`
+	RoleID             uuid.UUID  `json:"role_id"`
+	RoleName           string     `json:"role_name"`
+	RoleRank           int        `json:"role_rank"`
+	IsActive           bool       `json:"is_active"`
+	MustChangePassword bool       `json:"must_change_password"`
+	PasswordChangedAt  time.Time  `json:"password_changed_at"`
+	DeactivatedAt      *time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// UserFilter narrows the admin user list. Zero values match everything.
+type UserFilter struct {
+	Query  string // Case-insensitive email substring
+	RoleID uuid.UUID
+	Active *bool
+}
+
+// NewUser is an admin-created account. The password goes through the
+// password policy before it is hashed.
+type NewUser struct {
+	Email              string
+	Password           string
+	RoleID             uuid.UUID
+	MustChangePassword bool // Typical for admin-issued initial passwords
+}
+
+// UserUpdate is a partial edit; nil fields are left unchanged.
+type UserUpdate struct {
+	Email              *string
+	RoleID             *uuid.UUID
+	IsActive           *bool
+	MustChangePassword *bool
+}
+
+type UserAdminRepository interface {
+	List(ctx context.Context, filter UserFilter, page PageRequest) (Page[ManagedUser], error)
+	Get(ctx context.Context, id uuid.UUID) (*ManagedUser, error)
+	Create(ctx context.Context, email, passwordHash string, roleID uuid.UUID, mustChangePassword bool) (*ManagedUser, error)
+	UpdateEmail(ctx context.Context, id uuid.UUID, email string) error
+	SetMustChangePassword(ctx context.Context, id uuid.UUID, must bool) error
+	// SetActive(false) also drops the stored refresh token; the per-request
+	// is_active check in RequireAuthentication rejects live access tokens.
+	SetActive(ctx context.Context, id uuid.UUID, active bool) error
+}
+
+// RolePermissionReader lists a role's grants as "resource:action" strings.
+type RolePermissionReader interface {
+	ListRolePermissions(ctx context.Context, roleID uuid.UUID) ([]string, error)
+}
+
+// UserAdminService is the /users API. Every mutation is rank-checked: an
+// actor may only manage accounts whose rank is not superior to their own.
+type UserAdminService interface {
+	ListUsers(ctx context.Context, filter UserFilter, page PageRequest) (Page[ManagedUser], error)
+	GetUser(ctx context.Context, id uuid.UUID) (*ManagedUser, error)
+	CreateUser(ctx context.Context, actorID uuid.UUID, u NewUser) (*ManagedUser, error)
+	UpdateUser(ctx context.Context, actorID, id uuid.UUID, u UserUpdate) (*ManagedUser, error)
+	DeactivateUser(ctx context.Context, actorID, id uuid.UUID) error
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Events a user can subscribe an outgoing webhook to.
+const (
+	WebhookDeploymentSucceeded = "deployment.succeeded"
+	WebhookDeploymentFailed    = "deployment.failed"
+	WebhookAppCrashed          = "app.crashed"
+	WebhookCertRenewed         = "cert.renewed"
+	WebhookRoleChanged         = "user.role_changed" // Sent to the affected user
+	WebhookPing                = "webhook.ping"      // Sent on demand; never subscribed to
+)
+
+// WebhookEvents lists the subscribable events.
+var WebhookEvents = []string{
+	WebhookDeploymentSucceeded,
+	WebhookDeploymentFailed,
+	WebhookAppCrashed,
+	WebhookCertRenewed,
+	WebhookRoleChanged,
+}
+
+// OutgoingWebhook is a user-registered endpoint for Kari events. Payloads are
+// signed with the webhook's secret (X-Kari-Signature-256).
+type OutgoingWebhook struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Enabled   bool      `json:"enabled"`
+	Secret    string    `json:"secret,omitempty"` // 🛡️ Only ever returned by Create
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliveryDelivered WebhookDeliveryStatus = "delivered"
+	DeliveryFailed    WebhookDeliveryStatus = "failed" // Retries exhausted
+)
+
+// WebhookPayload is the signed JSON body. EventID is stable across retries
+// and redeliveries, so receivers can deduplicate on it.
+type WebhookPayload struct {
+	EventID   uuid.UUID `json:"id"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// WebhookDelivery is one attempt series of one event to one webhook.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `json:"id"`
+	WebhookID      uuid.UUID             `json:"webhook_id"`
+	EventID        uuid.UUID             `json:"event_id"`
+	Event          string                `json:"event"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty"` // Pending only
+	ResponseStatus *int                  `json:"response_status,omitempty"`
+	LastError      *string               `json:"last_error,omitempty"`
+	DurationMS     *int                  `json:"duration_ms,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+}
+
+// WebhookAttempt is the outcome of one HTTP POST.
+type WebhookAttempt struct {
+	ResponseStatus int // 0 when no response arrived
+	Duration       time.Duration
+	Err            error
+}
+
+// WebhookTarget is a due delivery joined with what sending it needs.
+type WebhookTarget struct {
+	Delivery         WebhookDelivery
+	UserID           uuid.UUID
+	URL              string
+	SecretCiphertext string
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, w *OutgoingWebhook, secretCiphertext string) error
+	List(ctx context.Context, userID uuid.UUID) ([]OutgoingWebhook, error)
+	Get(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*OutgoingWebhook, error)
+	Update(ctx context.Context, w *OutgoingWebhook) error
+	Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	// Subscribers returns the user's enabled webhooks subscribed to event.
+	Subscribers(ctx context.Context, userID uuid.UUID, event string) ([]OutgoingWebhook, error)
+
+	// EnqueueDelivery stores a pending delivery, due immediately.
+	EnqueueDelivery(ctx context.Context, d *WebhookDelivery) error
+	// DueDeliveries returns pending deliveries whose next attempt is due.
+	DueDeliveries(ctx context.Context, limit int) ([]WebhookTarget, error)
+	// Target loads one delivery for an immediate send.
+	Target(ctx context.Context, deliveryID uuid.UUID) (*WebhookTarget, error)
+	// RecordAttempt stores an attempt; a nil nextAttempt ends the series in status.
+	RecordAttempt(ctx context.Context, id uuid.UUID, status WebhookDeliveryStatus, attempt WebhookAttempt, nextAttempt *time.Time) error
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, userID uuid.UUID, limit int) ([]WebhookDelivery, error)
+	GetDelivery(ctx context.Context, id uuid.UUID, webhookID uuid.UUID, userID uuid.UUID) (*WebhookDelivery, error)
+}
+
+// WebhookSender POSTs a signed payload to a user endpoint.
+type WebhookSender interface {
+	Send(ctx context.Context, url string, secret []byte, headers map[string]string, body []byte) WebhookAttempt
+}
+
+// WebhookEmitter is how the rest of the Brain raises user-facing events. It
+// only queues deliveries; sending happens in the background.
+type WebhookEmitter interface {
+	Emit(ctx context.Context, ownerID uuid.UUID, event string, data any)
+	EmitForApp(ctx context.Context, appID uuid.UUID, event string, data any)
+}
+
+// WebhookManager is the user-facing contract behind /outgoing-webhooks.
+type WebhookManager interface {
+	CreateWebhook(ctx context.Context, userID uuid.UUID, w *OutgoingWebhook) (*OutgoingWebhook, error)
+	ListWebhooks(ctx context.Context, userID uuid.UUID) ([]OutgoingWebhook, error)
+	UpdateWebhook(ctx context.Context, userID uuid.UUID, w *OutgoingWebhook) (*OutgoingWebhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	Ping(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, id uuid.UUID, userID uuid.UUID) ([]WebhookDelivery, error)
+	// Redeliver sends a past delivery's payload again, right away.
+	Redeliver(ctx context.Context, id uuid.UUID, deliveryID uuid.UUID, userID uuid.UUID) (*WebhookDelivery, error)
+}
@@ -0,0 +1,490 @@
+// Package saga runs multi-step provisioning as a sequence of forward actions,
+// each paired with the action that undoes it. State is persisted after every
+// transition, so a crash mid-saga is rolled back (or, past the pivot, finished)
+// on the next start instead of leaving half-created units, vhosts and rows.
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+const (
+	// compensateTimeout gives each undo its own budget, so a saga whose
+	// deadline expired can still clean up after itself.
+	compensateTimeout = 2 * time.Minute
+	// persistTimeout bounds each state write, detached from the saga context.
+	persistTimeout = 5 * time.Second
+	// finishedRetention is how long finished sagas stay readable.
+	finishedRetention = 7 * 24 * time.Hour
+	pruneInterval     = time.Hour
+)
+
+// ErrSkip, returned by Execute, records the step as skipped.
+var ErrSkip = errors.New("saga: step skipped")
+
+// Step is one forward action and its undo.
+type Step struct {
+	Name    string
+	Execute func(ctx context.Context, st *State) error
+	// Compensate undoes Execute and must be idempotent: after a crash it also
+	// runs for the step that was in flight, whose resource may not exist.
+	// Nil means there is nothing to undo.
+	Compensate func(ctx context.Context, st *State) error
+	// Optional failures are recorded but do not fail the saga.
+	Optional bool
+	// Pivot marks the point of no return. Failures after it stop the saga
+	// without compensation and recovery retries forward, so later steps must
+	// be idempotent and work from State data alone.
+	Pivot bool
+}
+
+// Definition is a registered saga kind. Steps are fixed per kind so a saga
+// interrupted by a restart can be matched back to its compensations.
+type Definition struct {
+	Kind    string
+	Steps   []Step
+	Timeout time.Duration // Bounds the forward run; zero leaves it to ctx
+}
+
+// Params starts one saga.
+type Params struct {
+	OwnerID uuid.UUID
+	// Data seeds the persisted state.
+	Data map[string]any
+	// Input is handed to Execute but never persisted; use it for request
+	// payloads that may carry secrets.
+	Input any
+	// OnChange receives a snapshot after every transition.
+	OnChange func(domain.Saga)
+}
+
+type Orchestrator struct {
+	repo   domain.SagaRepository
+	logger *slog.Logger
+
+	mu   sync.RWMutex
+	defs map[string]*Definition
+}
+
+func NewOrchestrator(repo domain.SagaRepository, logger *slog.Logger) *Orchestrator {
+	return &Orchestrator{
+		repo:   repo,
+		logger: logger,
+		defs:   make(map[string]*Definition),
+	}
+}
+
+// Register adds a saga kind. Registering a kind twice is a wiring bug.
+func (o *Orchestrator) Register(def Definition) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, dup := o.defs[def.Kind]; dup {
+		panic("saga: duplicate definition " + def.Kind)
+	}
+	o.defs[def.Kind] = &def
+}
+
+// ==============================================================================
+// 1. Running Sagas
+// ==============================================================================
+
+// Launch persists a new saga and runs it in the background, detached from
+// ctx, returning the initial snapshot.
+func (o *Orchestrator) Launch(ctx context.Context, kind string, p Params) (*domain.Saga, error) {
+	r, err := o.begin(ctx, kind, p)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := r.snapshot()
+
+	runCtx := context.WithoutCancel(ctx)
+	go func() { _ = o.drive(runCtx, r, 0) }()
+	return &snapshot, nil
+}
+
+// Execute persists a new saga and runs it to the end. The error is that of
+// the step which stopped the saga; the snapshot tells whether it rolled back.
+func (o *Orchestrator) Execute(ctx context.Context, kind string, p Params) (*domain.Saga, error) {
+	r, err := o.begin(ctx, kind, p)
+	if err != nil {
+		return nil, err
+	}
+	err = o.drive(ctx, r, 0)
+	snapshot := r.snapshot()
+	return &snapshot, err
+}
+
+// Get returns a persisted saga of the given kind, tenant-isolated.
+func (o *Orchestrator) Get(ctx context.Context, kind string, id uuid.UUID, ownerID uuid.UUID) (*domain.Saga, error) {
+	s, err := o.repo.GetByID(ctx, id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if s.Kind != kind {
+		return nil, domain.ErrNotFound
+	}
+	return s, nil
+}
+
+func (o *Orchestrator) begin(ctx context.Context, kind string, p Params) (*run, error) {
+	o.mu.RLock()
+	def := o.defs[kind]
+	o.mu.RUnlock()
+	if def == nil {
+		return nil, fmt.Errorf("saga: unknown kind %q", kind)
+	}
+
+	now := time.Now().UTC()
+	s := domain.Saga{
+		ID:        uuid.New(),
+		Kind:      kind,
+		OwnerID:   p.OwnerID,
+		Status:    domain.SagaRunning,
+		Data:      make(map[string]json.RawMessage, len(p.Data)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for _, step := range def.Steps {
+		s.Steps = append(s.Steps, domain.SagaStep{Name: step.Name, Status: domain.SagaStepPending, UpdatedAt: now})
+	}
+	for key, v := range p.Data {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("saga: failed to encode %q: %w", key, err)
+		}
+		s.Data[key] = raw
+	}
+
+	// Without a record a crash would strand whatever the steps create
+	if err := o.repo.Create(ctx, &s); err != nil {
+		return nil, fmt.Errorf("failed to persist saga: %w", err)
+	}
+	return &run{o: o, def: def, saga: s, input: p.Input, onChange: p.OnChange}, nil
+}
+
+// drive executes steps from index from onward.
+func (o *Orchestrator) drive(ctx context.Context, r *run, from int) error {
+	if r.def.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.def.Timeout)
+		defer cancel()
+	}
+
+	st := &State{run: r, Input: r.input}
+	pivoted := r.pivoted()
+	for i := from; i < len(r.def.Steps); i++ {
+		step := r.def.Steps[i]
+		if step.Execute == nil {
+			r.setStep(i, domain.SagaStepSkipped, nil)
+			continue
+		}
+
+		r.setStep(i, domain.SagaStepRunning, nil)
+		err := step.Execute(ctx, st)
+		switch {
+		case err == nil:
+			r.setStep(i, domain.SagaStepDone, nil)
+			pivoted = pivoted || step.Pivot
+		case errors.Is(err, ErrSkip):
+			r.setStep(i, domain.SagaStepSkipped, nil)
+		case step.Optional:
+			o.logger.Warn("Optional saga step failed", r.attrs(step.Name, err)...)
+			r.setStep(i, domain.SagaStepFailed, err)
+		case pivoted:
+			// Past the point of no return: undoing earlier steps would only
+			// leave a different half-state behind
+			o.logger.Error("Saga step failed after pivot; not rolling back", r.attrs(step.Name, err)...)
+			r.setStep(i, domain.SagaStepFailed, err)
+			r.finish(domain.SagaFailed)
+			return err
+		default:
+			o.logger.Error("Saga step failed; rolling back", r.attrs(step.Name, err)...)
+			r.setStep(i, domain.SagaStepFailed, err)
+			r.finish(o.rollback(ctx, r))
+			return err
+		}
+	}
+	r.finish(domain.SagaSucceeded)
+	return nil
+}
+
+// rollback undoes done and in-flight steps in reverse order. It keeps going
+// after a failed undo, so one stuck resource does not strand the others.
+func (o *Orchestrator) rollback(ctx context.Context, r *run) domain.SagaStatus {
+	st := &State{run: r, Input: r.input}
+	status := domain.SagaRolledBack
+	for i := len(r.def.Steps) - 1; i >= 0; i-- {
+		step := r.def.Steps[i]
+		switch r.stepStatus(i) {
+		case domain.SagaStepDone, domain.SagaStepRunning:
+		default:
+			continue
+		}
+		if step.Compensate == nil {
+			continue
+		}
+
+		compCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), compensateTimeout)
+		err := step.Compensate(compCtx, st)
+		cancel()
+		if err != nil {
+			o.logger.Error("Saga compensation failed; resource left behind", r.attrs(step.Name, err)...)
+			r.setStep(i, domain.SagaStepFailed, fmt.Errorf("rollback failed: %w", err))
+			status = domain.SagaFailed
+			continue
+		}
+		r.setStep(i, domain.SagaStepCompensated, nil)
+	}
+	return status
+}
+
+// ==============================================================================
+// 2. Recovery & Retention
+// ==============================================================================
+
+// Start recovers sagas interrupted by the last shutdown, then prunes finished
+// ones hourly. Only one API instance may run it against a database.
+func (o *Orchestrator) Start(ctx context.Context) {
+	if err := o.Recover(ctx); err != nil {
+		o.logger.Error("Saga recovery failed", slog.Any("error", err))
+	}
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := o.repo.DeleteFinishedBefore(ctx, time.Now().Add(-finishedRetention))
+			if err != nil {
+				o.logger.Warn("Failed to prune finished sagas", slog.Any("error", err))
+			} else if n > 0 {
+				o.logger.Info("Pruned finished sagas", slog.Int64("count", n))
+			}
+		}
+	}
+}
+
+// Recover settles every saga still marked running. Before the pivot the
+// saga is rolled back, in-flight step included; past it the remaining steps
+// are retried. Input is gone after a restart, so neither path may need it.
+func (o *Orchestrator) Recover(ctx context.Context) error {
+	sagas, err := o.repo.ListRunning(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sagas {
+		o.mu.RLock()
+		def := o.defs[s.Kind]
+		o.mu.RUnlock()
+
+		r := &run{o: o, def: def, saga: s}
+		if def == nil || !sameSteps(def, s.Steps) {
+			// A release changed the kind under it; guessing would be worse
+			o.logger.Error("Cannot recover saga with unknown definition; left for an operator",
+				slog.String("saga_id", s.ID.String()), slog.String("kind", s.Kind))
+			r.def = &Definition{Kind: s.Kind}
+			r.finish(domain.SagaFailed)
+			continue
+		}
+
+		if r.pivoted() {
+			o.logger.Warn("Resuming saga interrupted past its pivot",
+				slog.String("saga_id", s.ID.String()), slog.String("kind", s.Kind))
+			_ = o.drive(ctx, r, r.resumeIndex())
+			continue
+		}
+		o.logger.Warn("Rolling back saga interrupted by restart",
+			slog.String("saga_id", s.ID.String()), slog.String("kind", s.Kind))
+		r.finish(o.rollback(ctx, r))
+	}
+	return nil
+}
+
+func sameSteps(def *Definition, steps []domain.SagaStep) bool {
+	if len(def.Steps) != len(steps) {
+		return false
+	}
+	for i := range steps {
+		if def.Steps[i].Name != steps[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// ==============================================================================
+// 3. Run State
+// ==============================================================================
+
+type run struct {
+	o        *Orchestrator
+	def      *Definition
+	input    any
+	onChange func(domain.Saga)
+
+	mu   sync.Mutex
+	saga domain.Saga
+}
+
+func (r *run) setStep(i int, status domain.SagaStepStatus, err error) {
+	r.update(func(s *domain.Saga) {
+		s.Steps[i].Status = status
+		s.Steps[i].Error = ""
+		if err != nil {
+			s.Steps[i].Error = publicError(err)
+		}
+		s.Steps[i].UpdatedAt = s.UpdatedAt
+	})
+}
+
+func (r *run) finish(status domain.SagaStatus) {
+	r.update(func(s *domain.Saga) { s.Status = status })
+	r.o.logger.Info("Saga finished",
+		slog.String("saga_id", r.saga.ID.String()),
+		slog.String("kind", r.saga.Kind),
+		slog.String("status", string(status)))
+}
+
+// update applies fn, persists the result and publishes it. A failed write is
+// logged rather than failing the step: the saga can still finish or roll back
+// from memory, it just cannot be recovered if the process dies too.
+func (r *run) update(fn func(s *domain.Saga)) {
+	r.mu.Lock()
+	r.saga.UpdatedAt = time.Now().UTC()
+	fn(&r.saga)
+	snapshot := r.snapshotLocked()
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), persistTimeout)
+	defer cancel()
+	if err := r.o.repo.Update(ctx, &snapshot); err != nil {
+		r.o.logger.Error("Failed to persist saga state",
+			slog.String("saga_id", snapshot.ID.String()), slog.Any("error", err))
+	}
+	if r.onChange != nil {
+		r.onChange(snapshot)
+	}
+}
+
+func (r *run) snapshot() domain.Saga {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshotLocked()
+}
+
+func (r *run) snapshotLocked() domain.Saga {
+	s := r.saga
+	s.Steps = append([]domain.SagaStep(nil), r.saga.Steps...)
+	s.Data = make(map[string]json.RawMessage, len(r.saga.Data))
+	for k, v := range r.saga.Data {
+		s.Data[k] = v
+	}
+	return s
+}
+
+func (r *run) stepStatus(i int) domain.SagaStepStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.saga.Steps[i].Status
+}
+
+func (r *run) pivoted() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, step := range r.def.Steps {
+		if step.Pivot && r.saga.Steps[i].Status == domain.SagaStepDone {
+			return true
+		}
+	}
+	return false
+}
+
+// resumeIndex is the first step that neither completed nor was settled.
+func (r *run) resumeIndex() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, step := range r.saga.Steps {
+		switch step.Status {
+		case domain.SagaStepDone, domain.SagaStepSkipped:
+		case domain.SagaStepFailed:
+			if !r.def.Steps[i].Optional {
+				return i
+			}
+		default:
+			return i
+		}
+	}
+	return len(r.saga.Steps)
+}
+
+func (r *run) attrs(step string, err error) []any {
+	return []any{
+		slog.String("saga_id", r.saga.ID.String()),
+		slog.String("kind", r.saga.Kind),
+		slog.String("step", step),
+		slog.Any("error", err),
+	}
+}
+
+// publicError keeps messages the client can act on (validation, quota,
+// conflicts) and hides infrastructure detail, which is logged instead. Step
+// errors are persisted and served, so nothing else leaves the process.
+func publicError(err error) string {
+	for _, target := range []error{domain.ErrValidation, domain.ErrConflict, domain.ErrQuotaExceeded, domain.ErrForbidden, domain.ErrUnavailable} {
+		if errors.Is(err, target) {
+			return err.Error()
+		}
+	}
+	return "internal error; see server logs"
+}
+
+// ==============================================================================
+// 4. Step State
+// ==============================================================================
+
+// State is what a step sees of its saga.
+type State struct {
+	run *run
+	// Input is the transient payload from Params; nil after a restart.
+	Input any
+}
+
+func (st *State) ID() uuid.UUID      { return st.run.saga.ID }
+func (st *State) OwnerID() uuid.UUID { return st.run.saga.OwnerID }
+
+// Get decodes the value stored under key into v and reports whether it was set.
+func (st *State) Get(key string, v any) (bool, error) {
+	st.run.mu.Lock()
+	raw, ok := st.run.saga.Data[key]
+	st.run.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("saga: failed to decode %q: %w", key, err)
+	}
+	return true, nil
+}
+
+// Set records v under key and persists it at once. Call it right after a
+// resource is created so a crash cannot lose the ID its compensation needs.
+func (st *State) Set(key string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("saga: failed to encode %q: %w", key, err)
+	}
+	st.run.update(func(s *domain.Saga) { s.Data[key] = raw })
+	return nil
+}
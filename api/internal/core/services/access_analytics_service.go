@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/grpc/rustagent"
+)
+
+// analyticsTopN is how many paths/clients the analytics view returns.
+const analyticsTopN = 20
+
+// AccessAnalyticsService pulls aggregated access logs from the Muscle and
+// serves per-domain traffic summaries. Raw log lines never reach the Brain.
+type AccessAnalyticsService struct {
+	repo        domain.AccessAnalyticsRepository
+	agentClient rustagent.SystemAgentClient
+	agentCaps   domain.AgentCapabilities
+	logger      *slog.Logger
+}
+
+func NewAccessAnalyticsService(
+	repo domain.AccessAnalyticsRepository,
+	agent rustagent.SystemAgentClient,
+	agentCaps domain.AgentCapabilities,
+	logger *slog.Logger,
+) *AccessAnalyticsService {
+	return &AccessAnalyticsService{
+		repo:        repo,
+		agentClient: agent,
+		agentCaps:   agentCaps,
+		logger:      logger,
+	}
+}
+
+func (s *AccessAnalyticsService) GetAnalytics(ctx context.Context, domainID, userID uuid.UUID, rangeName string) (*domain.DomainAnalytics, error) {
+	window, err := domain.ParseAnalyticsRange(rangeName)
+	if err != nil {
+		return nil, err
+	}
+	if rangeName == "" {
+		rangeName = "24h"
+	}
+
+	since := time.Now().UTC().Add(-window).Truncate(time.Hour)
+	a, err := s.repo.Summarize(ctx, domainID, userID, since, analyticsTopN)
+	if err != nil {
+		return nil, err
+	}
+	a.Range = rangeName
+	return a, nil
+}
+
+// Available reports whether the connected Muscle can read access logs.
+func (s *AccessAnalyticsService) Available() bool {
+	return s.agentCaps.Supports(domain.AgentFeatureAccessLogs)
+}
+
+// Collect reads everything new in one domain's access log and merges it
+// into the hourly rollups. The cursor only advances once the merge commits.
+func (s *AccessAnalyticsService) Collect(ctx context.Context, target domain.AnalyticsTarget) (int64, error) {
+	resp, err := s.agentClient.CollectAccessLogs(ctx, &rustagent.AccessLogRequest{
+		TraceId:    fmt.Sprintf("access-%s-%d", target.DomainID.String()[:8], time.Now().UnixMilli()),
+		DomainName: target.DomainName,
+		Offset:     uint64(target.Offset),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w: access log collection failed: %v", domain.ErrUnavailable, err)
+	}
+
+	var requests int64
+	buckets := make([]domain.AccessBucket, 0, len(resp.Buckets))
+	for _, b := range resp.Buckets {
+		buckets = append(buckets, domain.AccessBucket{
+			Hour:          time.Unix(b.HourUnix, 0).UTC(),
+			Requests:      int64(b.Requests),
+			BytesSent:     int64(b.BytesSent),
+			BytesReceived: int64(b.BytesReceived),
+			StatusClasses: toInt64Counts(b.StatusClasses),
+			Paths:         toInt64Counts(b.Paths),
+			Clients:       toInt64Counts(b.Clients),
+		})
+		requests += int64(b.Requests)
+	}
+
+	if int64(resp.NextOffset) == target.Offset && len(buckets) == 0 {
+		return 0, nil
+	}
+	if err := s.repo.RecordBatch(ctx, target.DomainID, buckets, int64(resp.NextOffset)); err != nil {
+		return 0, err
+	}
+	if resp.SkippedLines > 0 {
+		s.logger.Debug("Unparseable access log lines skipped",
+			slog.String("domain", target.DomainName),
+			slog.Uint64("skipped", resp.SkippedLines))
+	}
+	return requests, nil
+}
+
+// Prune drops rollups older than the retention window.
+func (s *AccessAnalyticsService) Prune(ctx context.Context) (int64, error) {
+	return s.repo.PruneBefore(ctx, time.Now().UTC().Add(-domain.AnalyticsRetention))
+}
+
+func toInt64Counts(in map[string]uint64) map[string]int64 {
+	out := make(map[string]int64, len(in))
+	for k, v := range in {
+		out[k] = int64(v)
+	}
+	return out
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/grpc/rustagent"
+	"kari/api/internal/version"
+)
+
+// agentFeatureRevisions maps each gated capability to the protocol revision
+// that introduced it. Anything not listed is part of the baseline (rev 1).
+var agentFeatureRevisions = map[domain.AgentFeature]uint32{
+	domain.AgentFeatureBrainUpdate:     2,
+	domain.AgentFeaturePHPRuntime:      3,
+	domain.AgentFeatureRuntimePinning:  4,
+	domain.AgentFeatureImagePull:       5,
+	domain.AgentFeatureProcessTypes:    6,
+	domain.AgentFeatureInstances:       7,
+	domain.AgentFeatureRestart:         8,
+	domain.AgentFeatureFileScan:        9,
+	domain.AgentFeatureSecurityHeaders: 10,
+	domain.AgentFeatureAccessLogs:      11,
+	domain.AgentFeatureBandwidthLimit:  12,
+	domain.AgentFeatureCPUUsage:        13,
+	domain.AgentFeatureInventory:       14,
+	domain.AgentFeatureBuildEnv:        16,
+	domain.AgentFeatureArtifacts:       17,
+	domain.AgentFeatureSBOM:            18,
+	domain.AgentFeatureCapabilities:    19,
+	domain.AgentFeatureSystemDefaults:  20,
+}
+
+// AgentCompatService negotiates the Brain<->Muscle protocol revision and gates
+// newer RPCs behind it, so an older agent keeps serving the baseline surface.
+type AgentCompatService struct {
+	agentClient rustagent.SystemAgentClient
+	audit       domain.AuditRepository
+	logger      *slog.Logger
+
+	mu      sync.RWMutex
+	current *domain.AgentCompatibility
+}
+
+func NewAgentCompatService(agent rustagent.SystemAgentClient, audit domain.AuditRepository, logger *slog.Logger) *AgentCompatService {
+	return &AgentCompatService{agentClient: agent, audit: audit, logger: logger}
+}
+
+// ==============================================================================
+// 1. Handshake
+// ==============================================================================
+
+// Handshake performs the connect-time negotiation. A failure here is not
+// fatal: the Health Prober re-runs negotiation on its next successful probe.
+func (s *AgentCompatService) Handshake(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	status, err := s.agentClient.GetSystemStatus(ctx, &rustagent.Empty{})
+	if err != nil {
+		return fmt.Errorf("agent handshake failed: %w", err)
+	}
+	s.ObserveAgent(ctx, status.AgentVersion, status.MinProtocolVersion, status.ProtocolVersion)
+	return nil
+}
+
+// ObserveAgent re-negotiates whenever the Muscle reports a different build,
+// e.g. after it was upgraded or restarted underneath a running Brain.
+func (s *AgentCompatService) ObserveAgent(ctx context.Context, agentVersion string, minProtocol, maxProtocol uint32) {
+	// Pre-negotiation agents report 0/0: they only speak the baseline revision
+	if maxProtocol == 0 {
+		maxProtocol = 1
+	}
+	if minProtocol == 0 {
+		minProtocol = 1
+	}
+
+	s.mu.RLock()
+	prev := s.current
+	s.mu.RUnlock()
+	if prev != nil && prev.AgentVersion == agentVersion &&
+		prev.AgentProtocolMin == minProtocol && prev.AgentProtocolMax == maxProtocol {
+		return
+	}
+
+	next := negotiateProtocol(agentVersion, minProtocol, maxProtocol)
+
+	s.mu.Lock()
+	s.current = next
+	s.mu.Unlock()
+
+	switch {
+	case !next.Compatible:
+		s.logger.Error("🚨 Incompatible Muscle agent detected",
+			slog.String("agent_version", agentVersion),
+			slog.Any("agent_protocol", []uint32{minProtocol, maxProtocol}),
+			slog.Any("brain_protocol", []uint32{version.AgentProtocolMin, version.AgentProtocolMax}))
+		s.raiseAlert(ctx, next)
+	case next.NegotiatedProtocol < version.AgentProtocolMax:
+		s.logger.Warn("🤝 Muscle agent is older than this Brain; newer features disabled",
+			slog.String("agent_version", agentVersion),
+			slog.Any("negotiated_protocol", next.NegotiatedProtocol))
+	default:
+		s.logger.Info("🤝 Muscle protocol negotiated",
+			slog.String("agent_version", agentVersion),
+			slog.Any("negotiated_protocol", next.NegotiatedProtocol))
+	}
+}
+
+// negotiateProtocol picks the highest revision inside both ranges.
+func negotiateProtocol(agentVersion string, agentMin, agentMax uint32) *domain.AgentCompatibility {
+	result := &domain.AgentCompatibility{
+		AgentVersion:     agentVersion,
+		AgentProtocolMin: agentMin,
+		AgentProtocolMax: agentMax,
+		NegotiatedAt:     time.Now().UTC(),
+	}
+
+	negotiated := min(agentMax, version.AgentProtocolMax)
+	if negotiated >= max(agentMin, version.AgentProtocolMin) {
+		result.NegotiatedProtocol = negotiated
+		result.Compatible = true
+	}
+	return result
+}
+
+// ==============================================================================
+// 2. Feature Gates
+// ==============================================================================
+
+// Supports reports whether the negotiated revision includes feature.
+// 🛡️ Fail-Closed: Gated features are off until a handshake has succeeded.
+func (s *AgentCompatService) Supports(feature domain.AgentFeature) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.current == nil || !s.current.Compatible {
+		return false
+	}
+	required, gated := agentFeatureRevisions[feature]
+	return !gated || s.current.NegotiatedProtocol >= required
+}
+
+// Current returns the latest negotiation result (nil before the first handshake).
+func (s *AgentCompatService) Current() *domain.AgentCompatibility {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.current == nil {
+		return nil
+	}
+	snapshot := *s.current
+	return &snapshot
+}
+
+func (s *AgentCompatService) raiseAlert(ctx context.Context, compat *domain.AgentCompatibility) {
+	if s.audit == nil {
+		return
+	}
+	err := s.audit.CreateAlert(ctx, &domain.SystemAlert{
+		Severity: "critical",
+		Category: "system",
+		Message: fmt.Sprintf("Muscle agent %s speaks protocol %d-%d; this Brain requires %d-%d. Upgrade the agent or the Brain.",
+			compat.AgentVersion, compat.AgentProtocolMin, compat.AgentProtocolMax,
+			version.AgentProtocolMin, version.AgentProtocolMax),
+		Metadata: map[string]any{
+			"agent_version":      compat.AgentVersion,
+			"agent_protocol_min": compat.AgentProtocolMin,
+			"agent_protocol_max": compat.AgentProtocolMax,
+			"brain_version":      version.Version,
+			"brain_protocol_min": version.AgentProtocolMin,
+			"brain_protocol_max": version.AgentProtocolMax,
+		},
+	})
+	if err != nil {
+		s.logger.Warn("Failed to raise agent compatibility alert", slog.Any("error", err))
+	}
+}
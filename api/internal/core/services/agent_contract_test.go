@@ -0,0 +1,328 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/metadata"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/saga"
+	"kari/api/internal/core/services"
+	"kari/api/internal/infrastructure/agentmock"
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+// These tests pin the RPC sequence each flow sends the Muscle. A change that
+// adds, drops or reorders a call fails here first, so it ships together with
+// the agent change it needs (and a protocol revision) instead of breaking a
+// deployed pair.
+
+// ==============================================================================
+// 1. Fakes
+// ==============================================================================
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+type contractApps struct {
+	domain.ApplicationRepository
+	app       domain.Application
+	ownerRank int
+	deleted   []uuid.UUID
+}
+
+func (r *contractApps) GetByID(_ context.Context, id, userID uuid.UUID) (*domain.Application, error) {
+	if id != r.app.ID || userID != r.app.OwnerID {
+		return nil, domain.ErrNotFound
+	}
+	app := r.app
+	return &app, nil
+}
+
+func (r *contractApps) GetByIDWithMetadata(_ context.Context, id uuid.UUID) (*domain.ApplicationMetadata, error) {
+	if id != r.app.ID {
+		return nil, domain.ErrNotFound
+	}
+	return &domain.ApplicationMetadata{
+		ID:         r.app.ID,
+		Name:       r.app.DomainName,
+		DomainName: r.app.DomainName,
+		OwnerID:    r.app.OwnerID,
+		OwnerRank:  r.ownerRank,
+	}, nil
+}
+
+func (r *contractApps) Delete(_ context.Context, id uuid.UUID) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+type contractAudit struct{ domain.AuditRepository }
+
+func (contractAudit) CreateAlert(context.Context, *domain.SystemAlert) error { return nil }
+
+type contractProfiles struct{ domain.SystemProfileRepository }
+
+func (contractProfiles) GetActiveProfile(context.Context) (*domain.SystemProfile, error) {
+	return &domain.SystemProfile{DefaultStackRegistry: map[string]string{"nodejs": "20"}}, nil
+}
+
+type contractSagas struct{ domain.SagaRepository }
+
+func (contractSagas) Create(context.Context, *domain.Saga) error { return nil }
+func (contractSagas) Update(context.Context, *domain.Saga) error { return nil }
+
+// newestAgent supports every feature, so no flow is cut short by a gate.
+type newestAgent struct{}
+
+func (newestAgent) Supports(domain.AgentFeature) bool { return true }
+
+type noDeployKey struct{}
+
+func (noDeployKey) PrivateKeyFor(context.Context, uuid.UUID) (string, error) { return "", nil }
+
+type noBuckets struct{}
+
+func (noBuckets) BucketEnvFor(context.Context, uuid.UUID) (map[string]string, error) { return nil, nil }
+func (noBuckets) ReleaseBucket(context.Context, uuid.UUID) error                     { return nil }
+
+type noQuota struct{}
+
+func (noQuota) CheckQuota(context.Context, uuid.UUID, domain.ResourceUsage) error { return nil }
+
+func newContractAppService(t *testing.T, client *agentmock.Client) (*services.ApplicationService, *contractApps) {
+	t.Helper()
+	apps := &contractApps{
+		app: domain.Application{
+			ID:         uuid.New(),
+			DomainName: "shop.example.com",
+			OwnerID:    uuid.New(),
+			AppType:    "nodejs",
+			RepoURL:    "https://git.example.com/acme/shop.git",
+			Branch:     "main",
+			Instances:  1,
+		},
+		ownerRank: 10,
+	}
+	svc := services.NewApplicationService(
+		apps, contractAudit{}, contractProfiles{}, client, newestAgent{},
+		noDeployKey{}, noBuckets{}, noQuota{},
+		saga.NewOrchestrator(contractSagas{}, discard), discard,
+	)
+	return svc, apps
+}
+
+// memOutbox keeps state changes in memory, in seq order.
+type memOutbox struct {
+	domain.OutboxRepository
+	changes []*domain.StateChange
+}
+
+func (r *memOutbox) Append(_ context.Context, c *domain.StateChange) error {
+	c.ID, c.Seq, c.Status = uuid.New(), int64(len(r.changes)+1), domain.ChangePending
+	stored := *c
+	r.changes = append(r.changes, &stored)
+	return nil
+}
+
+func (r *memOutbox) pending() []domain.StateChange {
+	var out []domain.StateChange
+	for _, c := range r.changes {
+		if c.Status == domain.ChangePending {
+			out = append(out, *c)
+		}
+	}
+	return out
+}
+
+func (r *memOutbox) CountPending(context.Context) (int, error) { return len(r.pending()), nil }
+
+func (r *memOutbox) NextPending(_ context.Context, limit int) ([]domain.StateChange, error) {
+	p := r.pending()
+	return p[:min(limit, len(p))], nil
+}
+
+func (r *memOutbox) RecordAttempt(_ context.Context, id uuid.UUID, _ string) error {
+	return r.set(id, func(c *domain.StateChange) { c.Attempts++ })
+}
+
+func (r *memOutbox) MarkApplied(_ context.Context, id uuid.UUID) error {
+	return r.set(id, func(c *domain.StateChange) { c.Status = domain.ChangeApplied })
+}
+
+func (r *memOutbox) MarkFailed(_ context.Context, id uuid.UUID, reason string) error {
+	return r.set(id, func(c *domain.StateChange) { c.Status, c.LastError = domain.ChangeFailed, &reason })
+}
+
+func (r *memOutbox) set(id uuid.UUID, fn func(c *domain.StateChange)) error {
+	for _, c := range r.changes {
+		if c.ID == id {
+			fn(c)
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+// plainCrypto stores payloads as they are: the outbox's sealing is not under test.
+type plainCrypto struct{}
+
+func (plainCrypto) Encrypt(_ context.Context, plaintext, _ []byte) (string, error) {
+	return string(plaintext), nil
+}
+
+func (plainCrypto) Decrypt(_ context.Context, ciphertext string, _ []byte) ([]byte, error) {
+	return []byte(ciphertext), nil
+}
+
+func assertSequence(t *testing.T, client *agentmock.Client, want ...string) {
+	t.Helper()
+	if got := client.Methods(); !slices.Equal(got, want) {
+		t.Fatalf("RPC sequence changed:\n got  %v\n want %v", got, want)
+	}
+}
+
+// ==============================================================================
+// 2. Contracts
+// ==============================================================================
+
+func TestAgentContractDeploy(t *testing.T) {
+	client := &agentmock.Client{
+		StreamDeploymentFunc: func(context.Context, *agent.DeployRequest) ([]*agent.LogChunk, error) {
+			return []*agent.LogChunk{{Content: "cloning\n"}, {Content: "built\n"}}, nil
+		},
+	}
+	svc, apps := newContractAppService(t, client)
+
+	logs, err := svc.Deploy(context.Background(), apps.app.ID, apps.app.OwnerID)
+	if err != nil {
+		t.Fatalf("deploy: %v", err)
+	}
+	var lines []string
+	for line := range logs {
+		lines = append(lines, line)
+	}
+
+	assertSequence(t, client, "StreamDeployment")
+	req := client.Calls()[0].Request.(*agent.DeployRequest)
+	if req.AppId != apps.app.ID.String() || req.DomainName != "shop.example.com" {
+		t.Fatalf("deploy targets %s/%s", req.AppId, req.DomainName)
+	}
+	if req.RepoUrl != apps.app.RepoURL || req.Branch != "main" {
+		t.Fatalf("deploy source is %s@%s", req.RepoUrl, req.Branch)
+	}
+	// The registry default is resolved by the Brain; the Muscle never guesses
+	if req.Runtime != "nodejs" || req.RuntimeVersion != "20" {
+		t.Fatalf("runtime is %s %s, want nodejs 20", req.Runtime, req.RuntimeVersion)
+	}
+	if req.SshKey != nil || req.Instances != 0 || len(req.Processes) != 0 {
+		t.Fatal("an unscaled public app must deploy as the legacy single unit")
+	}
+	if !slices.Equal(lines, []string{"cloning\n", "built\n"}) {
+		t.Fatalf("build log relayed as %q", lines)
+	}
+}
+
+func TestAgentContractDelete(t *testing.T) {
+	client := &agentmock.Client{}
+	svc, apps := newContractAppService(t, client)
+
+	if err := svc.DeleteApplication(context.Background(), apps.app.ID, apps.app.OwnerID, apps.ownerRank); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	assertSequence(t, client, "DeleteDeployment")
+	req := client.Calls()[0].Request.(*agent.DeleteRequest)
+	if req.AppId != apps.app.ID.String() || req.DomainName != "shop.example.com" {
+		t.Fatalf("teardown targets %s/%s", req.AppId, req.DomainName)
+	}
+	if !slices.Equal(apps.deleted, []uuid.UUID{apps.app.ID}) {
+		t.Fatal("the row must be deleted once the Muscle cleaned up")
+	}
+}
+
+func TestAgentContractDeleteKeepsRowWhenTeardownFails(t *testing.T) {
+	client := &agentmock.Client{
+		DeleteDeploymentFunc: func(context.Context, *agent.DeleteRequest) (*agent.AgentResponse, error) {
+			return nil, errors.New("unit busy")
+		},
+	}
+	svc, apps := newContractAppService(t, client)
+
+	if err := svc.DeleteApplication(context.Background(), apps.app.ID, apps.app.OwnerID, apps.ownerRank); err == nil {
+		t.Fatal("expected the failed teardown to surface")
+	}
+	assertSequence(t, client, "DeleteDeployment")
+	if len(apps.deleted) != 0 {
+		t.Fatal("a row whose host resources still exist must not be deleted")
+	}
+}
+
+func TestAgentContractCertificateInstall(t *testing.T) {
+	client := &agentmock.Client{}
+	repo := &memOutbox{}
+	outbox := services.NewOutbox(client, repo, plainCrypto{}, contractAudit{}, discard)
+
+	queued, err := outbox.Submit(context.Background(), &domain.StateChange{
+		Event:        domain.EventCertInstalled,
+		ResourceType: "certificate",
+		ResourceID:   "shop.example.com",
+		Method:       agent.SystemAgent_InstallCertificate_FullMethodName,
+	}, &agent.SslPayload{
+		DomainName:   "shop.example.com",
+		FullchainPem: []byte("chain"),
+		PrivkeyPem:   []byte("key"),
+	})
+	if err != nil || queued {
+		t.Fatalf("install: queued=%v err=%v", queued, err)
+	}
+
+	assertSequence(t, client, "InstallCertificate")
+	req := client.Calls()[0].Request.(*agent.SslPayload)
+	if req.DomainName != "shop.example.com" || string(req.FullchainPem) != "chain" || string(req.PrivkeyPem) != "key" {
+		t.Fatalf("certificate payload changed in transit: %v", req)
+	}
+	if repo.changes[0].Status != domain.ChangeApplied {
+		t.Fatalf("change is %s after the Muscle acknowledged it", repo.changes[0].Status)
+	}
+}
+
+// A certificate queued while the Muscle is away is redelivered with the same
+// change ID, which is what lets the Muscle's ledger drop a duplicate.
+func TestAgentContractCertificateRedelivery(t *testing.T) {
+	var changeIDs []string
+	down := true
+	client := &agentmock.Client{
+		InstallCertificateFunc: func(ctx context.Context, _ *agent.SslPayload) (*agent.AgentResponse, error) {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			changeIDs = append(changeIDs, md.Get(services.ChangeIDMetadataKey)...)
+			if down {
+				return nil, domain.AgentError{Code: domain.ErrAgentUnreachable, Title: "Muscle offline"}
+			}
+			return &agent.AgentResponse{Success: true}, nil
+		},
+	}
+	repo := &memOutbox{}
+	outbox := services.NewOutbox(client, repo, plainCrypto{}, contractAudit{}, discard)
+
+	queued, err := outbox.Submit(context.Background(), &domain.StateChange{
+		Event:  domain.EventCertInstalled,
+		Method: agent.SystemAgent_InstallCertificate_FullMethodName,
+	}, &agent.SslPayload{DomainName: "shop.example.com"})
+	if err != nil || !queued {
+		t.Fatalf("expected the install to queue, got queued=%v err=%v", queued, err)
+	}
+
+	down = false
+	outbox.Dispatch(context.Background())
+
+	assertSequence(t, client, "InstallCertificate", "InstallCertificate")
+	if len(changeIDs) != 2 || changeIDs[0] != changeIDs[1] || changeIDs[0] != repo.changes[0].ID.String() {
+		t.Fatalf("redelivery must carry the original change ID, got %v", changeIDs)
+	}
+}
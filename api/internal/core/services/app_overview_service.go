@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+	pb "kari/api/proto/kari/agent/v1"
+)
+
+const (
+	// overviewAgentTimeout bounds the Muscle round trip for live status.
+	overviewAgentTimeout = 3 * time.Second
+	// overviewQueryTimeout bounds each database-backed section.
+	overviewQueryTimeout = 2 * time.Second
+)
+
+// AppOverviewService assembles GET /applications/{id}/overview. Sections load
+// concurrently, each under its own deadline, and a failed section degrades to
+// an entry in Unavailable instead of failing the whole response.
+type AppOverviewService struct {
+	apps        domain.ApplicationRepository
+	repo        domain.AppOverviewRepository
+	agentClient pb.SystemAgentClient
+	agentCaps   domain.AgentCapabilities
+	logger      *slog.Logger
+}
+
+func NewAppOverviewService(
+	apps domain.ApplicationRepository,
+	repo domain.AppOverviewRepository,
+	agent pb.SystemAgentClient,
+	agentCaps domain.AgentCapabilities,
+	logger *slog.Logger,
+) *AppOverviewService {
+	return &AppOverviewService{
+		apps:        apps,
+		repo:        repo,
+		agentClient: agent,
+		agentCaps:   agentCaps,
+		logger:      logger,
+	}
+}
+
+func (s *AppOverviewService) GetOverview(ctx context.Context, appID uuid.UUID, userID uuid.UUID) (*domain.AppOverview, error) {
+	// 🛡️ Zero-Trust: Tenant-isolated lookup; everything below is keyed off this app
+	app, err := s.apps.GetByID(ctx, appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	overview := &domain.AppOverview{Application: app}
+
+	var mu sync.Mutex
+	fail := func(err error, sections ...string) {
+		reason := "unavailable"
+		if errors.Is(err, context.DeadlineExceeded) {
+			reason = "timed out"
+		}
+		s.logger.Warn("App overview section unavailable",
+			slog.String("app_id", app.ID.String()),
+			slog.Any("sections", sections),
+			slog.Any("error", err))
+
+		mu.Lock()
+		defer mu.Unlock()
+		if overview.Unavailable == nil {
+			overview.Unavailable = make(map[string]string)
+		}
+		for _, section := range sections {
+			overview.Unavailable[section] = reason
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+
+	// 1. Live unit state and resource usage share one Muscle call
+	go func() {
+		defer wg.Done()
+		sectionCtx, cancel := context.WithTimeout(ctx, overviewAgentTimeout)
+		defer cancel()
+
+		procs, err := readProcessStatus(sectionCtx, s.agentClient, s.agentCaps, app)
+		if err != nil {
+			fail(err, domain.OverviewSectionStatus, domain.OverviewSectionUsage)
+			return
+		}
+		overview.Status, overview.Usage = summarizeProcesses(app, procs)
+	}()
+
+	// 2. Last deployment
+	go func() {
+		defer wg.Done()
+		sectionCtx, cancel := context.WithTimeout(ctx, overviewQueryTimeout)
+		defer cancel()
+
+		d, err := s.repo.LastDeployment(sectionCtx, app.ID)
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+		case err != nil:
+			fail(err, domain.OverviewSectionLastDeployment)
+		default:
+			overview.LastDeployment = d
+		}
+	}()
+
+	// 3. TLS certificate of the app's domain
+	go func() {
+		defer wg.Done()
+		sectionCtx, cancel := context.WithTimeout(ctx, overviewQueryTimeout)
+		defer cancel()
+
+		c, err := s.repo.Certificate(sectionCtx, app.DomainID)
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+		case err != nil:
+			fail(err, domain.OverviewSectionCertificate)
+		default:
+			overview.Certificate = c
+		}
+	}()
+
+	wg.Wait()
+	return overview, nil
+}
+
+// summarizeProcesses folds per-unit states into the overview's status and
+// usage. Apps without declared processes are not reported per unit, so their
+// state falls back to the availability monitor's verdict and usage stays nil.
+func summarizeProcesses(app *domain.Application, procs []domain.ProcessStatus) (*domain.AppRuntimeStatus, *domain.AppResourceUsage) {
+	status := &domain.AppRuntimeStatus{State: app.Status, Processes: procs}
+	if len(procs) == 0 {
+		return status, nil
+	}
+
+	usage := &domain.AppResourceUsage{}
+	active, failed := 0, 0
+	for _, p := range procs {
+		usage.MemoryBytes += p.MemoryBytes
+		usage.CPUUsageNS += p.CPUUsageNS
+		switch p.ActiveState {
+		case "active":
+			active++
+		case "failed":
+			failed++
+		}
+	}
+
+	switch {
+	case active == len(procs):
+		status.State = "active"
+	case failed == len(procs):
+		status.State = "failed"
+	default:
+		status.State = "degraded"
+	}
+	return status, usage
+}
@@ -6,40 +6,194 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"kari/api/internal/core/domain"
+	"kari/api/internal/core/saga"
 	pb "kari/api/proto/kari/agent/v1"
 )
 
 type ApplicationService struct {
 	repo        domain.ApplicationRepository
 	auditRepo   domain.AuditRepository
+	profiles    domain.SystemProfileRepository
+	stacks      domain.StackRegistryRepository
 	agentClient pb.SystemAgentClient
+	agentCaps   domain.AgentCapabilities
+	deployKeys  domain.DeployKeySource
+	buckets     domain.AppBucketSource
+	quotas      domain.QuotaChecker
+	crypto      domain.CryptoService // Optional: decrypts push webhook secrets
+	sagas       *saga.Orchestrator
 	logger      *slog.Logger
 }
 
 func NewApplicationService(
 	repo domain.ApplicationRepository,
 	audit domain.AuditRepository,
+	profiles domain.SystemProfileRepository,
 	agent pb.SystemAgentClient,
+	agentCaps domain.AgentCapabilities,
+	deployKeys domain.DeployKeySource,
+	buckets domain.AppBucketSource,
+	quotas domain.QuotaChecker,
+	sagas *saga.Orchestrator,
 	logger *slog.Logger,
 ) *ApplicationService {
-	return &ApplicationService{
+	s := &ApplicationService{
 		repo:        repo,
 		auditRepo:   audit, // Fixed: was auditRepo: auditRepo
+		profiles:    profiles,
 		agentClient: agent,
+		agentCaps:   agentCaps,
+		deployKeys:  deployKeys,
+		buckets:     buckets,
+		quotas:      quotas,
+		sagas:       sagas,
 		logger:      logger,
 	}
+	sagas.Register(s.deletionSaga())
+	return s
+}
+
+// WithStacks validates pins against the live stack registry instead of the
+// seed catalogue.
+func (s *ApplicationService) WithStacks(stacks domain.StackRegistryRepository) *ApplicationService {
+	s.stacks = stacks
+	return s
+}
+
+// WithCrypto lets GetApplicationSystem decrypt the push webhook secret that
+// GitIntegrationService stored when it linked the repository.
+func (s *ApplicationService) WithCrypto(crypto domain.CryptoService) *ApplicationService {
+	s.crypto = crypto
+	return s
+}
+
+func (s *ApplicationService) stackCatalogue(ctx context.Context) (domain.StackCatalogue, error) {
+	if s.stacks == nil {
+		return domain.SeedStackCatalogue(), nil
+	}
+	return s.stacks.ListStackVersions(ctx)
+}
+
+// GetApplicationSystem loads an app for a push webhook along with its
+// decrypted secret. The signature is the only authorization on that route,
+// so the app must never be returned to the caller.
+func (s *ApplicationService) GetApplicationSystem(ctx context.Context, appID uuid.UUID) (*domain.Application, error) {
+	app, err := s.repo.GetByIDSystem(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := s.repo.GetWebhookSecret(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+	if ciphertext == "" {
+		// Never linked: an empty secret fails every signature check
+		return app, nil
+	}
+	if s.crypto == nil {
+		return nil, fmt.Errorf("%w: push webhooks require the crypto service", domain.ErrUnavailable)
+	}
+	// 🛡️ Same AAD as LinkApplication: a ciphertext copied to another app fails
+	secret, err := s.crypto.Decrypt(ctx, ciphertext, []byte("webhook:"+appID.String()))
+	if err != nil {
+		return nil, fmt.Errorf("security: failed to decrypt webhook secret: %w", err)
+	}
+	app.WebhookSecret = secret
+	return app, nil
+}
+
+// CreateApplication registers a new app and reserves its jail identity.
+// 🛡️ Tenant Isolation: The UID comes from the ledger inside the SystemProfile
+// range, never from the client and never from useradd's own numbering.
+func (s *ApplicationService) CreateApplication(ctx context.Context, ownerID uuid.UUID, app *domain.Application) (*domain.Application, error) {
+	profile, err := s.profiles.GetActiveProfile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load system profile: %w", err)
+	}
+
+	catalogue, err := s.stackCatalogue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := catalogue.ValidatePin(app.AppType, app.RuntimeVersion, ""); err != nil {
+		return nil, err
+	}
+	// PHP apps get an FPM pool for a concrete version; refuse early rather
+	// than at first deploy if neither the app nor the registry names one.
+	if app.AppType == "php" && app.ResolveRuntimeVersion(profile) == "" {
+		return nil, fmt.Errorf("%w: no php version in the stack registry", domain.ErrValidation)
+	}
+	if err := domain.ValidateProcesses(app.AppType, app.Processes); err != nil {
+		return nil, err
+	}
+	if app.Instances == 0 {
+		app.Instances = 1
+	}
+	if err := domain.ValidateInstances(app, app.Instances); err != nil {
+		return nil, err
+	}
+	// Image apps pull a prebuilt image; everything else builds from Git.
+	// Pull credentials are attached afterwards via the registry API.
+	if app.AppType == "image" {
+		if err := domain.ValidateImageRef(app.ImageRef); err != nil {
+			return nil, err
+		}
+	} else if app.ImageRef != "" {
+		return nil, fmt.Errorf("%w: image_ref is only valid for image-based apps", domain.ErrValidation)
+	}
+
+	// 📏 Quotas: Checked last so validation errors win over quota errors
+	if err := s.quotas.CheckQuota(ctx, ownerID, domain.ResourceUsage{Applications: 1, MemoryMB: app.ReservedMemoryMB()}); err != nil {
+		return nil, err
+	}
+
+	app.ID = uuid.New()
+	app.OwnerID = ownerID
+	app.AppUser = "kari-app-" + app.ID.String()
+	app.Status = "stopped"
+
+	uids := domain.UIDRange{Start: profile.AppUserUIDRangeStart, End: profile.AppUserUIDRangeEnd}
+	if err := s.repo.Create(ctx, app, uids); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Application created",
+		slog.String("app_id", app.ID.String()),
+		slog.Int("app_uid", *app.AppUID))
+	return app, nil
 }
 
 // Deploy triggers the GitOps workflow via the Rust Muscle
 func (s *ApplicationService) Deploy(ctx context.Context, appID uuid.UUID, userID uuid.UUID) (<-chan string, error) {
+	return s.DeployWithOverrides(ctx, appID, userID, domain.DeployOverrides{})
+}
+
+// DeployWithOverrides is Deploy with a one-off branch and/or build env. The
+// overrides ride along in the DeployRequest only; nothing about them is saved.
+func (s *ApplicationService) DeployWithOverrides(ctx context.Context, appID uuid.UUID, userID uuid.UUID, o domain.DeployOverrides) (<-chan string, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+
 	// 1. Fetch App & Verify Ownership (Zero-Trust IDOR Protection)
 	app, err := s.repo.GetByID(ctx, appID, userID)
 	if err != nil {
 		return nil, fmt.Errorf("deploy unauthorized or app not found: %w", err)
 	}
+	if app.AppType == "image" {
+		return nil, fmt.Errorf("%w: image-based apps are pulled, not built from Git", domain.ErrValidation)
+	}
+	branch := app.Branch
+	if o.Branch != "" {
+		branch = o.Branch
+	}
 
 	// 2. Generate Trace Identity for the Action Center
 	// Note: Fallback to current timestamp if request_start is missing from context
@@ -48,23 +202,114 @@ func (s *ApplicationService) Deploy(ctx context.Context, appID uuid.UUID, userID
 	
 	s.logger.Info("Starting deployment", 
 		slog.String("app", app.Name), 
+		slog.String("branch", branch),
+		slog.Bool("overrides", !o.IsZero()),
 		slog.String("trace_id", traceID))
 
-	// 3. Prepare the gRPC Stream with the Rust Muscle
-	stream, err := s.agentClient.StreamDeployment(ctx, &pb.DeployRequest{
-		TraceId:      traceID,
-		AppId:        app.ID.String(),
-		DomainName:   app.DomainName,
-		RepoUrl:      app.RepoURL,
-		Branch:       app.Branch,
-		BuildCommand: app.BuildCommand,
-		EnvVars:      app.EnvVars,
-	})
+	// 3. Resolve the runtime from the stack registry
+	profile, err := s.profiles.GetActiveProfile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load system profile: %w", err)
+	}
+	req := &pb.DeployRequest{
+		TraceId:        traceID,
+		AppId:          app.ID.String(),
+		DomainName:     app.DomainName,
+		RepoUrl:        app.RepoURL,
+		Branch:         branch,
+		BuildCommand:   app.BuildCommand,
+		EnvVars:        app.EnvVars,
+		Runtime:        app.AppType,
+		RuntimeVersion: app.ResolveRuntimeVersion(profile),
+	}
+	// 🏗️ Build args: the app's build env, then this deployment's overrides on top.
+	// Older Muscles would drop them and build something other than what was asked.
+	if len(app.BuildEnvVars) > 0 || len(o.BuildEnv) > 0 {
+		if !s.agentCaps.Supports(domain.AgentFeatureBuildEnv) {
+			return nil, fmt.Errorf("%w: the Muscle agent is too old to pass build-time variables", domain.ErrUnavailable)
+		}
+		req.BuildEnv = make(map[string]string, len(app.BuildEnvVars)+len(o.BuildEnv))
+		maps.Copy(req.BuildEnv, app.BuildEnvVars)
+		maps.Copy(req.BuildEnv, o.BuildEnv)
+	}
+	// An explicit pin must never silently fall back to the host's toolchain
+	if app.RuntimeVersion != "" && !s.agentCaps.Supports(domain.AgentFeatureRuntimePinning) {
+		return nil, fmt.Errorf("%w: the Muscle agent is too old to honor runtime version pins", domain.ErrUnavailable)
+	}
+	if app.AppType == "php" {
+		// Older Muscles would ignore the runtime and start a systemd unit instead
+		if !s.agentCaps.Supports(domain.AgentFeaturePHPRuntime) {
+			return nil, fmt.Errorf("%w: the Muscle agent is too old to run PHP applications", domain.ErrUnavailable)
+		}
+		req.PhpMaxChildren = uint32(app.Settings.EffectivePHPMaxChildren())
+	}
+
+	// 🛡️ Privacy: Private repos clone with the app's deploy key, decrypted only
+	// for this RPC (the Muscle zeroizes it after the clone)
+	sshKey, err := s.deployKeys.PrivateKeyFor(ctx, app.ID)
+	if err != nil {
+		return nil, err
+	}
+	if sshKey != "" {
+		req.SshKey = &sshKey
+	}
+
+	// ⚖️ Horizontal scaling: the Muscle runs web on port, port+1, ... behind a
+	// balanced vhost. A legacy single-command app scales as an implicit web process.
+	procs := app.Processes
+	if app.Instances > 1 {
+		// Older Muscles would start one copy and leave the rest of the capacity missing
+		if !s.agentCaps.Supports(domain.AgentFeatureInstances) {
+			return nil, fmt.Errorf("%w: the Muscle agent is too old to run multiple instances", domain.ErrUnavailable)
+		}
+		if len(procs) == 0 {
+			procs = map[string]domain.ProcessSpec{domain.WebProcess: {Command: app.StartCommand}}
+		}
+		port := int32(app.EffectivePort())
+		req.Instances = uint32(app.Instances)
+		req.Port = &port
+	}
+
+	// ⚙️ Multi-process apps: one unit per process type, switched together
+	if len(procs) > 0 {
+		// Older Muscles would silently run only the legacy single unit
+		if !s.agentCaps.Supports(domain.AgentFeatureProcessTypes) {
+			return nil, fmt.Errorf("%w: the Muscle agent is too old to run multiple process types", domain.ErrUnavailable)
+		}
+		for _, name := range slices.Sorted(maps.Keys(procs)) {
+			p := procs[name]
+			req.Processes = append(req.Processes, &pb.ProcessSpec{
+				Name:       name,
+				Command:    p.Command,
+				CpuPercent: uint32(p.EffectiveCPU()),
+				MemoryMb:   uint32(p.EffectiveMemoryMB()),
+			})
+		}
+	}
+
+	// 🪣 Object storage: S3_* fill in around the user's own env, never over it
+	bucketEnv, err := s.buckets.BucketEnvFor(ctx, app.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(bucketEnv) > 0 {
+		env := make(map[string]string, len(app.EnvVars)+len(bucketEnv))
+		for k, v := range bucketEnv {
+			env[k] = v
+		}
+		for k, v := range app.EnvVars {
+			env[k] = v
+		}
+		req.EnvVars = env
+	}
+
+	// 4. Prepare the gRPC Stream with the Rust Muscle
+	stream, err := s.agentClient.StreamDeployment(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to system agent: %w", err)
 	}
 
-	// 4. Async Log Pipeline (Memory-Safe Channel)
+	// 5. Async Log Pipeline (Memory-Safe Channel)
 	logChan := make(chan string, 100)
 
 	go func() {
@@ -97,6 +342,282 @@ func (s *ApplicationService) Deploy(ctx context.Context, appID uuid.UUID, userID
 	return logChan, nil
 }
 
+// UpdateApplicationSettings replaces the app's runtime tuning. Changes take
+// effect on the next deploy, when the Muscle re-renders the pool config.
+func (s *ApplicationService) UpdateApplicationSettings(ctx context.Context, appID uuid.UUID, userID uuid.UUID, settings domain.AppSettings) (*domain.Application, error) {
+	// 🛡️ Zero-Trust: Ownership check before any write
+	app, err := s.repo.GetByID(ctx, appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.PHPMaxChildren != 0 && app.AppType != "php" {
+		return nil, fmt.Errorf("%w: php_max_children only applies to php applications", domain.ErrValidation)
+	}
+
+	if err := s.repo.UpdateSettings(ctx, appID, settings); err != nil {
+		return nil, err
+	}
+	app.Settings = settings
+	return app, nil
+}
+
+// UpdateBuildEnvVars replaces the variables visible only to the build step
+// (build args). Running units never see them. Takes effect on the next deploy.
+func (s *ApplicationService) UpdateBuildEnvVars(ctx context.Context, appID uuid.UUID, userID uuid.UUID, vars map[string]string) (*domain.Application, error) {
+	// 🛡️ Zero-Trust: Ownership check before any write
+	app, err := s.repo.GetByID(ctx, appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if app.AppType == "image" {
+		return nil, fmt.Errorf("%w: image-based apps are pulled, not built", domain.ErrValidation)
+	}
+
+	if err := s.repo.UpdateBuildEnvVars(ctx, appID, vars); err != nil {
+		return nil, err
+	}
+	app.BuildEnvVars = vars
+	return app, nil
+}
+
+// UpdateRuntimeVersion pins the app to a supported toolchain version, or
+// unpins it with "". Takes effect on the next deploy.
+func (s *ApplicationService) UpdateRuntimeVersion(ctx context.Context, appID uuid.UUID, userID uuid.UUID, version string) (*domain.Application, error) {
+	// 🛡️ Zero-Trust: Ownership check before any write
+	app, err := s.repo.GetByID(ctx, appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	catalogue, err := s.stackCatalogue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// A deprecated version stays valid for an app already pinned to it
+	if err := catalogue.ValidatePin(app.AppType, version, app.RuntimeVersion); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateRuntimeVersion(ctx, appID, version); err != nil {
+		return nil, err
+	}
+	app.RuntimeVersion = version
+	return app, nil
+}
+
+// UpdateProcesses replaces the app's process types. An empty map returns the
+// app to the single StartCommand process. Takes effect on the next deploy,
+// which switches every process unit to the new release together.
+func (s *ApplicationService) UpdateProcesses(ctx context.Context, appID uuid.UUID, userID uuid.UUID, procs map[string]domain.ProcessSpec) (*domain.Application, error) {
+	// 🛡️ Zero-Trust: Ownership check before any write
+	app, err := s.repo.GetByID(ctx, appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateProcesses(app.AppType, procs); err != nil {
+		return nil, err
+	}
+	// A scaled app must keep something to scale
+	if app.Instances > 1 {
+		if err := domain.ValidateInstances(&domain.Application{AppType: app.AppType, Port: app.Port, Processes: procs}, app.Instances); err != nil {
+			return nil, err
+		}
+	}
+	next := *app
+	next.Processes = procs
+	if err := s.quotas.CheckQuota(ctx, userID, domain.ResourceUsage{MemoryMB: next.ReservedMemoryMB() - app.ReservedMemoryMB()}); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateProcesses(ctx, appID, procs); err != nil {
+		return nil, err
+	}
+	app.Processes = procs
+	return app, nil
+}
+
+// ScaleApplication sets how many web instances the proxy balances across.
+// Takes effect on the next deploy, which starts or retires instance units.
+func (s *ApplicationService) ScaleApplication(ctx context.Context, appID uuid.UUID, userID uuid.UUID, instances int) (*domain.Application, error) {
+	// 🛡️ Zero-Trust: Ownership check before any write
+	app, err := s.repo.GetByID(ctx, appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateInstances(app, instances); err != nil {
+		return nil, err
+	}
+	next := *app
+	next.Instances = instances
+	if err := s.quotas.CheckQuota(ctx, userID, domain.ResourceUsage{MemoryMB: next.ReservedMemoryMB() - app.ReservedMemoryMB()}); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateInstances(ctx, appID, instances); err != nil {
+		return nil, err
+	}
+	app.Instances = instances
+
+	s.logger.Info("Application scaled",
+		slog.String("app_id", app.ID.String()),
+		slog.Int("instances", instances))
+	return app, nil
+}
+
+// GetProcessStatus reports the live systemd state of each declared process.
+func (s *ApplicationService) GetProcessStatus(ctx context.Context, appID uuid.UUID, userID uuid.UUID) ([]domain.ProcessStatus, error) {
+	app, err := s.repo.GetByID(ctx, appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return readProcessStatus(ctx, s.agentClient, s.agentCaps, app)
+}
+
+// readProcessStatus asks the Muscle for the units of an already-authorized
+// app. Shared with the usage meter, which walks apps across tenants.
+func readProcessStatus(ctx context.Context, agent pb.SystemAgentClient, caps domain.AgentCapabilities, app *domain.Application) ([]domain.ProcessStatus, error) {
+	procs := app.Processes
+	if len(procs) == 0 && app.Instances > 1 {
+		procs = map[string]domain.ProcessSpec{domain.WebProcess: {Command: app.StartCommand}}
+	}
+	if len(procs) == 0 {
+		return []domain.ProcessStatus{}, nil
+	}
+	if !caps.Supports(domain.AgentFeatureProcessTypes) {
+		return nil, fmt.Errorf("%w: the Muscle agent is too old to report process status", domain.ErrUnavailable)
+	}
+
+	req := &pb.ProcessStatusRequest{
+		DomainName: app.DomainName,
+		Names:      slices.Sorted(maps.Keys(procs)),
+	}
+	// Per-instance rows and health probes need rev 7; older Muscles report web once
+	if caps.Supports(domain.AgentFeatureInstances) {
+		req.WebInstances = uint32(max(app.Instances, 1))
+		req.BasePort = uint32(app.EffectivePort())
+	}
+	resp, err := agent.GetProcessStatus(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read process status: %v", domain.ErrUnavailable, err)
+	}
+
+	statuses := make([]domain.ProcessStatus, 0, len(resp.Processes))
+	for _, p := range resp.Processes {
+		st := domain.ProcessStatus{
+			Name:        p.Name,
+			Spec:        procs[p.Name],
+			ActiveState: p.ActiveState,
+			SubState:    p.SubState,
+			MemoryBytes: p.MemoryBytes,
+			CPUUsageNS:  p.CpuUsageNsec,
+		}
+		if p.Instance > 0 {
+			healthy := p.Healthy
+			st.Instance, st.Port, st.Healthy = int(p.Instance), int(p.Port), &healthy
+		}
+		if p.ActiveSinceUnix > 0 {
+			since := time.Unix(p.ActiveSinceUnix, 0)
+			st.Since = &since
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+// RestartApplication restarts every process of the app on its current release.
+// Graceful mode keeps live WebSocket/SSE connections: the Muscle parks web
+// traffic on a surge copy while each instance drains and comes back.
+func (s *ApplicationService) RestartApplication(ctx context.Context, appID uuid.UUID, userID uuid.UUID, graceful bool) error {
+	app, err := s.repo.GetByID(ctx, appID, userID)
+	if err != nil {
+		return err
+	}
+	if app.AppType == "static" || app.AppType == "image" {
+		return fmt.Errorf("%w: %s apps have no processes to restart", domain.ErrValidation, app.AppType)
+	}
+
+	names := slices.Sorted(maps.Keys(app.Processes))
+	if len(names) == 0 && app.AppType != "php" {
+		names = []string{domain.WebProcess} // Legacy single start_command unit
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("%w: application has no processes to restart", domain.ErrValidation)
+	}
+	instances := max(app.Instances, 1)
+	if graceful {
+		if !slices.Contains(names, domain.WebProcess) {
+			return fmt.Errorf("%w: graceful restart needs a web process", domain.ErrValidation)
+		}
+		// The surge copy listens on the port after the last instance
+		if app.EffectivePort()+instances > 65535 {
+			return fmt.Errorf("%w: no free port above the web instances for a graceful restart", domain.ErrValidation)
+		}
+	}
+	if !s.agentCaps.Supports(domain.AgentFeatureRestart) {
+		return fmt.Errorf("%w: the Muscle agent is too old to restart applications", domain.ErrUnavailable)
+	}
+
+	traceID := fmt.Sprintf("rst-%s-%d", app.ID.String()[:8], time.Now().UnixMilli())
+	resp, err := s.agentClient.RestartApp(ctx, &pb.RestartRequest{
+		TraceId:      traceID,
+		DomainName:   app.DomainName,
+		Names:        names,
+		Graceful:     graceful,
+		WebInstances: uint32(instances),
+		BasePort:     uint32(app.EffectivePort()),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: restart failed: %v", domain.ErrUnavailable, err)
+	}
+	if !resp.Success {
+		return fmt.Errorf("restart failed: %s", resp.ErrorMessage)
+	}
+
+	s.logger.Info("Application restarted",
+		slog.String("app_id", app.ID.String()),
+		slog.String("trace_id", traceID),
+		slog.Bool("graceful", graceful))
+	return nil
+}
+
+// ListRuntimes exposes the supported-version catalogue with the stack
+// registry defaults, for the runtime picker.
+func (s *ApplicationService) ListRuntimes(ctx context.Context) ([]domain.RuntimeOption, error) {
+	profile, err := s.profiles.GetActiveProfile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load system profile: %w", err)
+	}
+
+	catalogue, err := s.stackCatalogue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	options := make([]domain.RuntimeOption, 0, len(domain.SupportedRuntimeVersions))
+	for runtime := range domain.SupportedRuntimeVersions {
+		opt := domain.RuntimeOption{Runtime: runtime, DefaultVersion: profile.DefaultStackRegistry[runtime]}
+		for _, v := range catalogue {
+			switch {
+			case v.Runtime != runtime:
+			case v.Status == domain.StackVersionDeprecated:
+				opt.Deprecated = append(opt.Deprecated, v.Version)
+			default:
+				opt.Versions = append(opt.Versions, v.Version)
+			}
+		}
+		options = append(options, opt)
+	}
+	slices.SortFunc(options, func(a, b domain.RuntimeOption) int { return strings.Compare(a.Runtime, b.Runtime) })
+	return options, nil
+}
+
+// ListApplications returns one page of the caller's applications.
+// 🛡️ Tenant Isolation: The owner scope always comes from the verified identity,
+// never from client-supplied filter params.
+func (s *ApplicationService) ListApplications(ctx context.Context, userID uuid.UUID, filter domain.ApplicationFilter, page domain.PageRequest) (domain.Page[domain.Application], error) {
+	filter.OwnerID = userID
+	return s.repo.List(ctx, filter, page)
+}
+
 // 🛡️ DeleteApplication enforces Rank-Based Ownership and OS-level cleanup
 func (s *ApplicationService) DeleteApplication(ctx context.Context, appID uuid.UUID, actorID uuid.UUID, actorRank int) error {
 	// 1. Fetch Target App with Internal Metadata (joins users to get owner_rank)
@@ -124,15 +645,87 @@ func (s *ApplicationService) DeleteApplication(ctx context.Context, appID uuid.U
 		Metadata: map[string]any{"app_id": appID, "actor_id": actorID},
 	})
 
-	// 4. Invoke Rust Muscle (gRPC) for physical cleanup (systemd, nginx, directories)
-	_, err = s.agentClient.DeleteDeployment(ctx, &pb.DeleteRequest{
-		AppId:      app.ID.String(),
-		DomainName: app.DomainName,
+	// 4. Teardown runs as a persisted saga: a crash between the Muscle cleanup
+	// and the row deletion is finished on restart instead of orphaning either
+	_, err = s.sagas.Execute(ctx, domain.SagaKindAppDelete, saga.Params{
+		OwnerID: actorID,
+		Data:    map[string]any{appDeleteDataApp: appTeardown{ID: app.ID, DomainName: app.DomainName}},
 	})
-	if err != nil {
-		return fmt.Errorf("system agent failed to clean up resource: %w", err)
+	return err
+}
+
+const appDeleteDataApp = "app"
+
+// appTeardown is everything the deletion steps need, persisted so a resumed
+// saga can finish without the original request.
+type appTeardown struct {
+	ID         uuid.UUID `json:"id"`
+	DomainName string    `json:"domain_name"`
+}
+
+// deletionSaga tears an app down. The Muscle cleanup is the pivot: once
+// systemd units, vhost and directories are gone there is nothing to restore,
+// so the bucket and row deletions after it are retried rather than undone.
+func (s *ApplicationService) deletionSaga() saga.Definition {
+	target := func(st *saga.State) (appTeardown, error) {
+		var t appTeardown
+		ok, err := st.Get(appDeleteDataApp, &t)
+		if err == nil && !ok {
+			err = errors.New("teardown target missing from saga data")
+		}
+		return t, err
 	}
 
-	// 5. Atomic DB Deletion
-	return s.repo.Delete(ctx, appID)
+	return saga.Definition{
+		Kind: domain.SagaKindAppDelete,
+		Steps: []saga.Step{
+			{
+				// Invoke Rust Muscle (gRPC) for physical cleanup (systemd, nginx, directories)
+				Name:  "teardown",
+				Pivot: true,
+				Execute: func(ctx context.Context, st *saga.State) error {
+					t, err := target(st)
+					if err != nil {
+						return err
+					}
+					_, err = s.agentClient.DeleteDeployment(ctx, &pb.DeleteRequest{
+						AppId:      t.ID.String(),
+						DomainName: t.DomainName,
+					})
+					if err != nil {
+						return fmt.Errorf("system agent failed to clean up resource: %w", err)
+					}
+					return nil
+				},
+			},
+			{
+				// 🪣 The bucket is not cascaded by the DB row; destroy it explicitly
+				Name: "bucket",
+				Execute: func(ctx context.Context, st *saga.State) error {
+					t, err := target(st)
+					if err != nil {
+						return err
+					}
+					if err := s.buckets.ReleaseBucket(ctx, t.ID); err != nil {
+						return fmt.Errorf("failed to delete app bucket: %w", err)
+					}
+					return nil
+				},
+			},
+			{
+				// Atomic DB Deletion; a retry after a crash finds the row gone
+				Name: "record",
+				Execute: func(ctx context.Context, st *saga.State) error {
+					t, err := target(st)
+					if err != nil {
+						return err
+					}
+					if err := s.repo.Delete(ctx, t.ID); err != nil && !errors.Is(err, domain.ErrNotFound) {
+						return err
+					}
+					return nil
+				},
+			},
+		},
+	}
 }
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// ApprovalService holds deployments of gated apps until the owner (or a
+// server admin) approves them, from the API or a chat button.
+type ApprovalService struct {
+	repo    domain.ApprovalRepository
+	apps    domain.ApplicationRepository
+	perms   chatPermissions
+	slack   domain.ApprovalNotifier // nil when no Slack bot token is configured
+	discord domain.ApprovalNotifier // nil when no Discord bot token is configured
+	logger  *slog.Logger
+}
+
+func NewApprovalService(
+	repo domain.ApprovalRepository,
+	apps domain.ApplicationRepository,
+	perms chatPermissions,
+	slack domain.ApprovalNotifier,
+	discord domain.ApprovalNotifier,
+	logger *slog.Logger,
+) *ApprovalService {
+	return &ApprovalService{
+		repo:    repo,
+		apps:    apps,
+		perms:   perms,
+		slack:   slack,
+		discord: discord,
+		logger:  logger,
+	}
+}
+
+// ==============================================================================
+// 1. Gates
+// ==============================================================================
+
+func (s *ApprovalService) GetGate(ctx context.Context, appID uuid.UUID, userID uuid.UUID) (*domain.ApprovalGate, error) {
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetGate(ctx, appID)
+}
+
+func (s *ApprovalService) SetGate(ctx context.Context, appID uuid.UUID, userID uuid.UUID, gate *domain.ApprovalGate) (*domain.ApprovalGate, error) {
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return nil, err
+	}
+	// A channel nobody can post to would hold deployments with no way to see them
+	if gate.SlackChannelID != nil && s.slack == nil {
+		return nil, fmt.Errorf("%w: Slack is not configured on this server", domain.ErrValidation)
+	}
+	if gate.DiscordChannelID != nil && s.discord == nil {
+		return nil, fmt.Errorf("%w: Discord is not configured on this server", domain.ErrValidation)
+	}
+	gate.AppID = appID
+	gate.CreatedBy = userID
+	if err := s.repo.SetGate(ctx, gate); err != nil {
+		return nil, fmt.Errorf("failed to save approval gate: %w", err)
+	}
+	return gate, nil
+}
+
+// DeleteGate stops gating new deployments. Deployments already waiting stay
+// pending until decided.
+func (s *ApprovalService) DeleteGate(ctx context.Context, appID uuid.UUID, userID uuid.UUID) error {
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return err
+	}
+	return s.repo.DeleteGate(ctx, appID)
+}
+
+func (s *ApprovalService) ListApprovals(ctx context.Context, appID uuid.UUID, userID uuid.UUID) ([]domain.DeploymentApproval, error) {
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByApp(ctx, appID, domain.DefaultPageLimit)
+}
+
+// ==============================================================================
+// 2. Decisions
+// ==============================================================================
+
+// Decide approves or rejects a waiting deployment. The app owner may decide,
+// as may anyone holding server:manage.
+func (s *ApprovalService) Decide(ctx context.Context, id uuid.UUID, userID uuid.UUID, approve bool, via string) (*domain.DeploymentApproval, error) {
+	a, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, a.AppID, userID); err != nil {
+		return nil, err
+	}
+
+	status := domain.ApprovalRejected
+	if approve {
+		status = domain.ApprovalApproved
+	}
+	decided, err := s.repo.Decide(ctx, id, status, userID, via)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("🚦 Deployment approval decided",
+		slog.String("deployment_id", decided.DeploymentID),
+		slog.String("status", string(decided.Status)),
+		slog.String("via", via),
+		slog.String("user_id", userID.String()))
+	return decided, nil
+}
+
+func (s *ApprovalService) authorize(ctx context.Context, appID uuid.UUID, userID uuid.UUID) error {
+	if admin, err := s.perms.HasPermission(ctx, userID, "server", "manage"); err != nil {
+		return err
+	} else if admin {
+		return nil
+	}
+	canWrite, err := s.perms.HasPermission(ctx, userID, "applications", "write")
+	if err != nil {
+		return err
+	}
+	if !canWrite {
+		return fmt.Errorf("%w: deciding approvals requires applications:write", domain.ErrForbidden)
+	}
+	// 🛡️ Tenant Isolation: Not found for someone else's app, same as every lookup
+	_, err = s.apps.GetByID(ctx, appID, userID)
+	return err
+}
+
+// ==============================================================================
+// 3. The Gate on Deployment Creation
+// ==============================================================================
+
+// Gate wraps a deployment repository so every Save for a gated app is held
+// as AWAITING_APPROVAL and announced in the gate's channels. Callers that
+// enqueue deployments need no changes.
+func (s *ApprovalService) Gate(inner domain.DeploymentRepository) domain.DeploymentRepository {
+	return &gatedDeployments{DeploymentRepository: inner, approvals: s}
+}
+
+type gatedDeployments struct {
+	domain.DeploymentRepository
+	approvals *ApprovalService
+}
+
+func (g *gatedDeployments) Save(ctx context.Context, d *domain.Deployment) error {
+	appID, err := uuid.Parse(d.AppID)
+	if err != nil {
+		return g.DeploymentRepository.Save(ctx, d)
+	}
+	gate, err := g.approvals.repo.GetGate(ctx, appID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return g.DeploymentRepository.Save(ctx, d)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check approval gate: %w", err)
+	}
+
+	d.Status = domain.StatusAwaitingApproval
+	if err := g.DeploymentRepository.Save(ctx, d); err != nil {
+		return err
+	}
+
+	a := &domain.DeploymentApproval{
+		DeploymentID: d.ID,
+		AppID:        appID,
+		DomainName:   d.DomainName,
+		Branch:       d.Branch,
+	}
+	if err := g.approvals.repo.Create(ctx, a); err != nil {
+		// Without an approval row nothing could ever release it
+		_ = g.DeploymentRepository.UpdateStatus(ctx, d.ID, domain.StatusFailed)
+		return fmt.Errorf("failed to open deployment approval: %w", err)
+	}
+	g.approvals.announce(ctx, gate, a)
+	return nil
+}
+
+// announce posts the interactive message to each configured channel. A
+// failed post is logged, not fatal: the approval can still be decided via the API.
+func (s *ApprovalService) announce(ctx context.Context, gate *domain.ApprovalGate, a *domain.DeploymentApproval) {
+	post := func(provider string, n domain.ApprovalNotifier, channel *string) {
+		if n == nil || channel == nil {
+			return
+		}
+		if err := n.RequestApproval(ctx, *channel, a); err != nil {
+			s.logger.Warn("failed to post approval request",
+				slog.String("provider", provider),
+				slog.String("deployment_id", a.DeploymentID),
+				slog.Any("error", err))
+		}
+	}
+	post(domain.ChatProviderSlack, s.slack, gate.SlackChannelID)
+	post(domain.ChatProviderDiscord, s.discord, gate.DiscordChannelID)
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/grpc/rustagent"
+)
+
+// pruneBatch bounds one retention sweep; the rest waits for the next tick.
+const pruneBatch = 500
+
+// ArtifactService redeploys stored releases and enforces artifact retention.
+// The deployment worker records artifacts as builds finish; this service only
+// reads them, queues redeploys and asks the Muscle to delete expired ones.
+type ArtifactService struct {
+	repo        domain.ArtifactRepository
+	deployments domain.DeploymentRepository // Approval-gated, like every other enqueue
+	apps        domain.ApplicationRepository
+	agentClient rustagent.SystemAgentClient
+	agentCaps   domain.AgentCapabilities
+	policy      domain.ArtifactRetention
+	logger      *slog.Logger
+}
+
+func NewArtifactService(
+	repo domain.ArtifactRepository,
+	deployments domain.DeploymentRepository,
+	apps domain.ApplicationRepository,
+	agent rustagent.SystemAgentClient,
+	agentCaps domain.AgentCapabilities,
+	policy domain.ArtifactRetention,
+	logger *slog.Logger,
+) *ArtifactService {
+	if policy.KeepPerApp < 1 {
+		policy.KeepPerApp = 1
+	}
+	return &ArtifactService{
+		repo:        repo,
+		deployments: deployments,
+		apps:        apps,
+		agentClient: agent,
+		agentCaps:   agentCaps,
+		policy:      policy,
+		logger:      logger,
+	}
+}
+
+// ==============================================================================
+// 1. Redeploys
+// ==============================================================================
+
+// GetArtifact resolves the artifact a deployment would redeploy. A redeploy
+// has no artifact of its own, so it resolves to its source's.
+func (s *ArtifactService) GetArtifact(ctx context.Context, deploymentID string, userID uuid.UUID) (*domain.DeploymentArtifact, error) {
+	a, _, err := s.ownedArtifact(ctx, deploymentID, userID)
+	return a, err
+}
+
+func (s *ArtifactService) Redeploy(ctx context.Context, deploymentID string, userID uuid.UUID) (*domain.Deployment, error) {
+	if !s.agentCaps.Supports(domain.AgentFeatureArtifacts) {
+		return nil, fmt.Errorf("%w: the Muscle agent is too old to redeploy stored artifacts", domain.ErrUnavailable)
+	}
+	src, app, err := s.ownedArtifact(ctx, deploymentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if src.PrunedAt != nil {
+		return nil, fmt.Errorf("%w: the artifact was pruned; deploy again to rebuild", domain.ErrConflict)
+	}
+
+	d := &domain.Deployment{
+		ID:           uuid.New().String(),
+		AppID:        app.ID.String(),
+		DomainName:   app.DomainName,
+		RepoURL:      app.RepoURL,
+		Branch:       src.Branch,
+		BuildCommand: app.BuildCommand,
+		TargetPort:   app.EffectivePort(),
+		Status:       domain.StatusPending,
+	}
+	// Linked first: the worker may claim the row the moment it is saved
+	if err := s.repo.LinkRedeploy(ctx, d.ID, src.DeploymentID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.deployments.Save(ctx, d); err != nil {
+		_ = s.repo.UnlinkRedeploy(ctx, d.ID)
+		return nil, err
+	}
+
+	s.logger.Info("📦 Redeploy queued",
+		slog.String("deployment_id", d.ID),
+		slog.String("source_deployment_id", src.DeploymentID),
+		slog.String("app_id", d.AppID),
+		slog.String("user_id", userID.String()))
+	return d, nil
+}
+
+// ownedArtifact loads the artifact and its app. 🛡️ Tenant Isolation: Someone
+// else's deployment is simply not found.
+func (s *ArtifactService) ownedArtifact(ctx context.Context, deploymentID string, userID uuid.UUID) (*domain.DeploymentArtifact, *domain.Application, error) {
+	a, err := s.repo.Get(ctx, deploymentID)
+	if errors.Is(err, domain.ErrNotFound) {
+		a, err = s.repo.RedeploySource(ctx, deploymentID)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	appID, err := uuid.Parse(a.AppID)
+	if err != nil {
+		return nil, nil, domain.ErrNotFound
+	}
+	app, err := s.apps.GetByID(ctx, appID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, app, nil
+}
+
+// ==============================================================================
+// 2. Retention (ArtifactPruner worker)
+// ==============================================================================
+
+// Prune deletes artifacts outside the retention policy from the Muscle, then
+// marks them pruned. An app whose delete fails is retried on the next sweep.
+func (s *ArtifactService) Prune(ctx context.Context) error {
+	if !s.agentCaps.Supports(domain.AgentFeatureArtifacts) {
+		return nil // Older Muscles never stored any
+	}
+	expired, err := s.repo.ListPrunable(ctx, s.policy, time.Now(), pruneBatch)
+	if err != nil {
+		return err
+	}
+
+	byApp := make(map[string][]string)
+	for _, a := range expired {
+		byApp[a.AppID] = append(byApp[a.AppID], a.DeploymentID)
+	}
+	for appID, ids := range byApp {
+		resp, err := s.agentClient.DeleteArtifacts(ctx, &rustagent.DeleteArtifactsRequest{
+			AppId:       appID,
+			ArtifactIds: ids,
+		})
+		if err == nil && !resp.Success {
+			err = errors.New(resp.ErrorMessage)
+		}
+		if err != nil {
+			s.logger.Warn("⚠️ Failed to prune artifacts", slog.String("app_id", appID), slog.Any("error", err))
+			continue
+		}
+		if err := s.repo.MarkPruned(ctx, ids); err != nil {
+			return err
+		}
+		s.logger.Info("📦 Artifacts pruned", slog.String("app_id", appID), slog.Int("count", len(ids)))
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/infrastructure/siem"
+)
+
+// auditSinkTokenAAD binds the encrypted SIEM token to its purpose.
+var auditSinkTokenAAD = []byte("audit_sink_config:token")
+
+// sinkSwitcher is satisfied by workers.AuditForwarder.
+type sinkSwitcher interface {
+	SetSink(sink domain.AuditSink)
+}
+
+// ==============================================================================
+// 1. Forwarding Decorator ("in addition to Postgres")
+// ==============================================================================
+
+// ForwardingAuditRepository persists tenant logs through the wrapped repository
+// and then publishes them for SIEM forwarding.
+type ForwardingAuditRepository struct {
+	domain.AuditRepository
+	publisher domain.AuditEventPublisher
+}
+
+func NewForwardingAuditRepository(inner domain.AuditRepository, publisher domain.AuditEventPublisher) *ForwardingAuditRepository {
+	return &ForwardingAuditRepository{AuditRepository: inner, publisher: publisher}
+}
+
+// CreateTenantLog only forwards rows that were durably committed (and hashed).
+func (r *ForwardingAuditRepository) CreateTenantLog(ctx context.Context, entry *domain.TenantLog) error {
+	if err := r.AuditRepository.CreateTenantLog(ctx, entry); err != nil {
+		return err
+	}
+	r.publisher.Publish(domain.NewAuditEvent(entry))
+	return nil
+}
+
+// ==============================================================================
+// 2. Admin Configuration
+// ==============================================================================
+
+type AuditSinkService struct {
+	repo      domain.AuditSinkConfigRepository
+	crypto    domain.CryptoService
+	forwarder sinkSwitcher
+	logger    *slog.Logger
+}
+
+func NewAuditSinkService(repo domain.AuditSinkConfigRepository, crypto domain.CryptoService, forwarder sinkSwitcher, logger *slog.Logger) *AuditSinkService {
+	return &AuditSinkService{repo: repo, crypto: crypto, forwarder: forwarder, logger: logger}
+}
+
+// Activate loads the stored configuration at boot and starts forwarding if enabled.
+func (s *AuditSinkService) Activate(ctx context.Context) error {
+	cfg, err := s.load(ctx)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil // Never configured
+	}
+	if err != nil {
+		return err
+	}
+	sink, err := s.build(cfg)
+	if err != nil {
+		return err
+	}
+	s.swap(cfg, sink)
+	return nil
+}
+
+// GetConfig returns the configuration without the secret token.
+func (s *AuditSinkService) GetConfig(ctx context.Context) (*domain.AuditSinkConfig, error) {
+	cfg, _, err := s.repo.Get(ctx)
+	return cfg, err
+}
+
+// UpdateConfig validates, encrypts, persists, and hot-swaps the sink.
+// An empty Token keeps the previously stored secret (write-only field).
+func (s *AuditSinkService) UpdateConfig(ctx context.Context, cfg *domain.AuditSinkConfig) error {
+	if err := validateSinkConfig(cfg); err != nil {
+		return err
+	}
+
+	ciphertext := ""
+	if cfg.Token != "" {
+		if s.crypto == nil {
+			return fmt.Errorf("%w: encryption is not initialized", domain.ErrUnavailable)
+		}
+		enc, err := s.crypto.Encrypt(ctx, []byte(cfg.Token), auditSinkTokenAAD)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt sink token: %w", err)
+		}
+		ciphertext = enc
+	} else if existing, stored, err := s.repo.Get(ctx); err == nil && existing.HasToken {
+		ciphertext = stored
+		cfg.Token, err = s.decrypt(ctx, stored)
+		if err != nil {
+			return err
+		}
+	}
+
+	// 🛡️ Stability: Build the sink BEFORE saving so a bad endpoint never
+	// replaces a working configuration, and swap it in only AFTER saving so
+	// the running sink never differs from what the next boot will load.
+	sink, err := s.build(cfg)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Save(ctx, cfg, ciphertext); err != nil {
+		if sink != nil {
+			sink.Close()
+		}
+		return err
+	}
+	s.swap(cfg, sink)
+	return nil
+}
+
+// SendTestEvent pushes a synthetic event synchronously so admins get immediate feedback.
+// tenantID is the tenant the event is filed under; uuid.Nil is platform scope.
+func (s *AuditSinkService) SendTestEvent(ctx context.Context, tenantID, actorID uuid.UUID) error {
+	cfg, err := s.load(ctx)
+	if err != nil {
+		return err
+	}
+	sink, err := siem.NewSink(*cfg)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrValidation, err)
+	}
+	defer sink.Close()
+
+	ev := domain.AuditEvent{
+		ID:           uuid.New(),
+		Timestamp:    time.Now().UTC(),
+		TenantID:     tenantID,
+		ActorID:      &actorID,
+		Action:       "audit_sink.test",
+		ResourceType: "audit_sink",
+		ResourceID:   string(cfg.Kind),
+		Metadata:     map[string]any{"message": "Karı SIEM connectivity test"},
+	}
+	if err := sink.Send(ctx, []domain.AuditEvent{ev}); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrUnavailable, err)
+	}
+	return nil
+}
+
+// load fetches the configuration and decrypts its token.
+func (s *AuditSinkService) load(ctx context.Context) (*domain.AuditSinkConfig, error) {
+	cfg, ciphertext, err := s.repo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ciphertext != "" {
+		if cfg.Token, err = s.decrypt(ctx, ciphertext); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+func (s *AuditSinkService) decrypt(ctx context.Context, ciphertext string) (string, error) {
+	if s.crypto == nil {
+		return "", fmt.Errorf("%w: encryption is not initialized", domain.ErrUnavailable)
+	}
+	plain, err := s.crypto.Decrypt(ctx, ciphertext, auditSinkTokenAAD)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt sink token: %w", err)
+	}
+	return string(plain), nil
+}
+
+// build constructs the sink cfg describes; nil when forwarding is disabled.
+func (s *AuditSinkService) build(cfg *domain.AuditSinkConfig) (domain.AuditSink, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	sink, err := siem.NewSink(*cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrValidation, err)
+	}
+	return sink, nil
+}
+
+// swap hands the forwarder a sink from build.
+func (s *AuditSinkService) swap(cfg *domain.AuditSinkConfig, sink domain.AuditSink) {
+	s.forwarder.SetSink(sink)
+	if sink != nil {
+		s.logger.Info("Audit forwarding enabled", slog.String("kind", string(cfg.Kind)))
+	}
+}
+
+// validateSinkConfig enforces transport-level safety rules.
+func validateSinkConfig(cfg *domain.AuditSinkConfig) error {
+	switch cfg.Kind {
+	case domain.AuditSinkSyslog:
+		return nil // host:port and network are validated by siem.NewSyslogSink
+	case domain.AuditSinkSplunkHEC, domain.AuditSinkHTTPS:
+		u, err := url.Parse(cfg.Endpoint)
+		// 🛡️ Zero-Trust: Bearer/HEC tokens must never travel in plaintext
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("%w: endpoint must be an https:// URL", domain.ErrValidation)
+		}
+		cfg.Network = ""
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported sink kind %q", domain.ErrValidation, cfg.Kind)
+	}
+}
@@ -17,10 +17,17 @@ import (
 // Dummy hash to equalize timing attacks. This is a valid bcrypt hash of the word "dummy".
 var dummyBcryptHash = []byte("$2a$10$wTf/0J/Q32r.5R7bU4X8uO4b2pE7Z9H5a0rY4q1w4s7c9d0x2z5eG")
 
+// passwordGate vetoes password logins a policy forbids (passkey required,
+// password expired).
+type passwordGate interface {
+	AllowPassword(ctx context.Context, user *domain.User) error
+}
+
 // AuthService orchestrates secure login flows and session generation.
 type AuthService struct {
 	repo         domain.UserRepository
 	tokenService *TokenService // 🛡️ SOLID: Inject the cryptographic engine
+	passwordGates []passwordGate // Empty = passwords always suffice
 }
 
 // NewAuthService creates a new authentication orchestrator.
@@ -31,6 +38,27 @@ func NewAuthService(repo domain.UserRepository, ts *TokenService) *AuthService {
 	}
 }
 
+// WithPasswordGate adds a policy check to password logins. Gates run in
+// the order they were added; the first refusal wins.
+func (s *AuthService) WithPasswordGate(g passwordGate) *AuthService {
+	s.passwordGates = append(s.passwordGates, g)
+	return s
+}
+
+// AllowPasswordExcept runs every password gate but skip. The expired-password
+// change uses it so that, say, the passkey policy still applies to it.
+func (s *AuthService) AllowPasswordExcept(ctx context.Context, user *domain.User, skip passwordGate) error {
+	for _, gate := range s.passwordGates {
+		if gate == skip {
+			continue
+		}
+		if err := gate.AllowPassword(ctx, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Login authenticates a user safely against timing and enumeration attacks.
 func (s *AuthService) Login(ctx context.Context, email, password string) (string, string, error) {
 	user, err := s.repo.GetByEmail(ctx, email)
@@ -52,6 +80,14 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 		return "", "", errors.New("invalid credentials")
 	}
 
+	// 3. 🔑 Passkey & password policy: Checked only after the password is
+	// proven, so it reveals nothing to someone guessing passwords.
+	for _, gate := range s.passwordGates {
+		if err := gate.AllowPassword(ctx, user); err != nil {
+			return "", "", err
+		}
+	}
+
 	return s.GenerateTokenPair(ctx, user)
 }
 
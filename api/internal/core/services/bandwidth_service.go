@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/grpc/rustagent"
+)
+
+// bandwidthHistoryMonths is how much monthly history GET /bandwidth returns.
+const bandwidthHistoryMonths = 12
+
+// BandwidthService reports per-app monthly transfer (fed by the access log
+// collector) and enforces optional transfer quotas.
+type BandwidthService struct {
+	apps        domain.ApplicationRepository
+	repo        domain.BandwidthRepository
+	auditRepo   domain.AuditRepository
+	agentClient rustagent.SystemAgentClient
+	agentCaps   domain.AgentCapabilities
+	logger      *slog.Logger
+}
+
+func NewBandwidthService(
+	apps domain.ApplicationRepository,
+	repo domain.BandwidthRepository,
+	audit domain.AuditRepository,
+	agent rustagent.SystemAgentClient,
+	agentCaps domain.AgentCapabilities,
+	logger *slog.Logger,
+) *BandwidthService {
+	return &BandwidthService{
+		apps:        apps,
+		repo:        repo,
+		auditRepo:   audit,
+		agentClient: agent,
+		agentCaps:   agentCaps,
+		logger:      logger,
+	}
+}
+
+// ==============================================================================
+// 1. Tenant-Facing Operations
+// ==============================================================================
+
+func (s *BandwidthService) GetBandwidth(ctx context.Context, appID, userID uuid.UUID) (*domain.AppBandwidth, error) {
+	// 🛡️ Zero-Trust: Ownership check before reading usage
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return nil, err
+	}
+
+	usage, err := s.repo.ListUsage(ctx, appID, bandwidthHistoryMonths)
+	if err != nil {
+		return nil, err
+	}
+	quota, err := s.repo.GetQuota(ctx, appID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	return &domain.AppBandwidth{AppID: appID, Quota: quota, Usage: usage}, nil
+}
+
+// SetQuota creates or changes the app's quota, then re-evaluates it at once
+// so raising a quota lifts an active throttle without waiting for a sweep.
+func (s *BandwidthService) SetQuota(ctx context.Context, appID, userID uuid.UUID, q domain.TransferQuota) (*domain.TransferQuota, error) {
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return nil, err
+	}
+	if q.Action == "" {
+		q.Action = domain.QuotaActionAlert
+	}
+	if q.ThrottleKBps == 0 {
+		q.ThrottleKBps = domain.DefaultThrottleKBps
+	}
+	if q.Action == domain.QuotaActionThrottle && !s.agentCaps.Supports(domain.AgentFeatureBandwidthLimit) {
+		return nil, fmt.Errorf("%w: the Muscle agent is too old to throttle bandwidth", domain.ErrUnavailable)
+	}
+
+	if err := s.repo.UpsertQuota(ctx, appID, q); err != nil {
+		return nil, err
+	}
+	if err := s.enforceApp(ctx, appID); err != nil {
+		s.logger.Error("Transfer quota re-evaluation failed", slog.String("app_id", appID.String()), slog.Any("error", err))
+	}
+	return s.repo.GetQuota(ctx, appID)
+}
+
+// RemoveQuota drops the quota and lifts any throttle it imposed.
+func (s *BandwidthService) RemoveQuota(ctx context.Context, appID, userID uuid.UUID) error {
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return err
+	}
+	statuses, err := s.repo.QuotaStatuses(ctx, domain.CurrentMonth(time.Now()), &appID)
+	if err != nil {
+		return err
+	}
+	if len(statuses) == 0 {
+		return domain.ErrNotFound
+	}
+	if statuses[0].Quota.State == domain.QuotaStateThrottled {
+		if err := s.setLimit(ctx, statuses[0].Domains, 0); err != nil {
+			return err
+		}
+	}
+	return s.repo.DeleteQuota(ctx, appID)
+}
+
+// ==============================================================================
+// 2. Enforcement (run after every access log sweep)
+// ==============================================================================
+
+// EnforceQuotas alerts on (and optionally throttles) apps over their monthly
+// quota, and lifts last month's throttles once a new month starts.
+func (s *BandwidthService) EnforceQuotas(ctx context.Context) error {
+	statuses, err := s.repo.QuotaStatuses(ctx, domain.CurrentMonth(time.Now()), nil)
+	if err != nil {
+		return err
+	}
+	for i := range statuses {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.enforce(ctx, &statuses[i]); err != nil {
+			s.logger.Error("Transfer quota enforcement failed",
+				slog.String("app_id", statuses[i].AppID.String()),
+				slog.Any("error", err))
+		}
+	}
+	return nil
+}
+
+func (s *BandwidthService) enforceApp(ctx context.Context, appID uuid.UUID) error {
+	statuses, err := s.repo.QuotaStatuses(ctx, domain.CurrentMonth(time.Now()), &appID)
+	if err != nil || len(statuses) == 0 {
+		return err
+	}
+	return s.enforce(ctx, &statuses[0])
+}
+
+// enforce moves the app to the state its usage calls for: ok, alerted, or
+// throttled. Enforcement lapses at month end, so a new month starts at ok.
+func (s *BandwidthService) enforce(ctx context.Context, st *domain.QuotaStatus) error {
+	month := domain.CurrentMonth(time.Now())
+	state := st.Quota.State
+	if st.EnforcedMonth == nil || !st.EnforcedMonth.Equal(month) {
+		if state == domain.QuotaStateThrottled {
+			if err := s.setLimit(ctx, st.Domains, 0); err != nil {
+				return err
+			}
+		}
+		state = domain.QuotaStateOK
+	}
+
+	want := domain.QuotaStateOK
+	if st.Used >= st.Quota.QuotaBytes() {
+		want = domain.QuotaStateAlerted
+		if st.Quota.Action == domain.QuotaActionThrottle && s.agentCaps.Supports(domain.AgentFeatureBandwidthLimit) {
+			want = domain.QuotaStateThrottled
+		}
+	}
+
+	if want != state {
+		switch {
+		case want == domain.QuotaStateThrottled:
+			if err := s.setLimit(ctx, st.Domains, st.Quota.ThrottleKBps); err != nil {
+				return err
+			}
+		case state == domain.QuotaStateThrottled:
+			if err := s.setLimit(ctx, st.Domains, 0); err != nil {
+				return err
+			}
+		}
+		if state == domain.QuotaStateOK {
+			s.raiseAlert(ctx, st, want)
+		}
+	}
+	if want == st.Quota.State && st.EnforcedMonth != nil && st.EnforcedMonth.Equal(month) {
+		return nil
+	}
+	return s.repo.SetQuotaState(ctx, st.AppID, want, month)
+}
+
+func (s *BandwidthService) setLimit(ctx context.Context, domains []string, kbps int) error {
+	for _, name := range domains {
+		_, err := s.agentClient.SetBandwidthLimit(ctx, &rustagent.BandwidthLimitRequest{
+			TraceId:    fmt.Sprintf("bw-%s-%d", name, time.Now().UnixMilli()),
+			DomainName: name,
+			RateKbps:   uint32(kbps),
+		})
+		if err != nil {
+			return fmt.Errorf("%w: failed to set bandwidth limit on %s: %v", domain.ErrUnavailable, name, err)
+		}
+	}
+	return nil
+}
+
+func (s *BandwidthService) raiseAlert(ctx context.Context, st *domain.QuotaStatus, state string) {
+	message := fmt.Sprintf("App exceeded its %d GB monthly transfer quota", st.Quota.QuotaGB)
+	if state == domain.QuotaStateThrottled {
+		message += fmt.Sprintf("; throttled to %d KiB/s until the quota is raised or the month ends", st.Quota.ThrottleKBps)
+	}
+
+	resourceID := st.AppID.String()
+	if err := s.auditRepo.CreateAlert(ctx, &domain.SystemAlert{
+		Severity:   "warning",
+		Category:   "bandwidth",
+		ResourceID: &resourceID,
+		Message:    message,
+		Metadata: map[string]any{
+			"app_id":     st.AppID,
+			"owner_id":   st.OwnerID,
+			"used_bytes": st.Used,
+			"quota_gb":   st.Quota.QuotaGB,
+			"action":     st.Quota.Action,
+		},
+	}); err != nil {
+		s.logger.Error("Failed to raise transfer quota alert", slog.String("app_id", resourceID), slog.Any("error", err))
+	}
+}
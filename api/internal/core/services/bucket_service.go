@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// maxBucketQuotaBytes caps a single app's bucket at 1 TiB.
+const maxBucketQuotaBytes int64 = 1 << 40
+
+// BucketService provisions per-app object storage buckets with credentials
+// that can reach that bucket only.
+type BucketService struct {
+	apps         domain.ApplicationRepository
+	buckets      domain.AppBucketRepository
+	store        domain.ObjectStorageProvider // nil when MinIO is not configured
+	crypto       domain.CryptoService
+	defaultQuota int64
+	logger       *slog.Logger
+}
+
+func NewBucketService(
+	apps domain.ApplicationRepository,
+	buckets domain.AppBucketRepository,
+	store domain.ObjectStorageProvider,
+	crypto domain.CryptoService,
+	defaultQuotaBytes int64,
+	logger *slog.Logger,
+) *BucketService {
+	return &BucketService{
+		apps:         apps,
+		buckets:      buckets,
+		store:        store,
+		crypto:       crypto,
+		defaultQuota: defaultQuotaBytes,
+		logger:       logger,
+	}
+}
+
+// ==============================================================================
+// 1. User-Facing Lifecycle
+// ==============================================================================
+
+// ProvisionBucket creates the app's bucket. quotaBytes 0 applies the platform default.
+// Only a quota admin (overrideDefault) may ask for more than the default.
+func (s *BucketService) ProvisionBucket(ctx context.Context, appID, userID uuid.UUID, quotaBytes int64, overrideDefault bool) (*domain.AppBucket, error) {
+	// 🛡️ Zero-Trust: Ownership check before any write
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	if quotaBytes == 0 {
+		quotaBytes = s.defaultQuota
+	}
+	if err := s.validateQuota(quotaBytes, overrideDefault); err != nil {
+		return nil, err
+	}
+	if _, err := s.buckets.GetByAppID(ctx, appID); err == nil {
+		return nil, fmt.Errorf("%w: application already has a bucket", domain.ErrConflict)
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	accessKey, secretKey, err := generateBucketCredentials()
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := s.crypto.Encrypt(ctx, []byte(secretKey), bucketAAD(appID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt bucket secret: %w", err)
+	}
+
+	b := &domain.AppBucket{
+		AppID:               appID,
+		Bucket:              "kari-" + appID.String(), // Lowercase, 41 chars: valid S3 name
+		AccessKey:           accessKey,
+		QuotaBytes:          quotaBytes,
+		SecretKeyCiphertext: ciphertext,
+	}
+	if err := s.store.CreateBucket(ctx, b.Bucket, b.AccessKey, secretKey, quotaBytes); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrUnavailable, err)
+	}
+	if err := s.buckets.Create(ctx, b); err != nil {
+		// Don't leave an untracked bucket behind
+		if derr := s.store.DeleteBucket(context.WithoutCancel(ctx), b.Bucket, b.AccessKey); derr != nil {
+			s.logger.Error("Failed to roll back orphaned bucket",
+				slog.String("bucket", b.Bucket), slog.Any("error", derr))
+		}
+		return nil, err
+	}
+
+	s.logger.Info("App bucket provisioned",
+		slog.String("app_id", appID.String()),
+		slog.String("bucket", b.Bucket),
+		slog.Int64("quota_bytes", quotaBytes))
+	return b, nil
+}
+
+func (s *BucketService) GetBucket(ctx context.Context, appID, userID uuid.UUID) (*domain.BucketUsage, error) {
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return nil, err
+	}
+	b, err := s.buckets.GetByAppID(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &domain.BucketUsage{AppBucket: *b}
+	if s.store == nil {
+		return usage, nil
+	}
+	usage.Endpoint = s.store.Endpoint()
+
+	// Usage is advisory: a slow scanner should not break the storage page
+	used, objects, known, err := s.store.Usage(ctx, b.Bucket)
+	if err != nil {
+		s.logger.Warn("Bucket usage unavailable",
+			slog.String("bucket", b.Bucket), slog.Any("error", err))
+		return usage, nil
+	}
+	usage.UsedBytes, usage.Objects, usage.UsageKnown = used, objects, known
+	return usage, nil
+}
+
+// SetBucketQuota resizes the bucket; above the platform default it needs overrideDefault.
+func (s *BucketService) SetBucketQuota(ctx context.Context, appID, userID uuid.UUID, quotaBytes int64, overrideDefault bool) (*domain.AppBucket, error) {
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+	if err := s.validateQuota(quotaBytes, overrideDefault); err != nil {
+		return nil, err
+	}
+	b, err := s.buckets.GetByAppID(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.SetQuota(ctx, b.Bucket, quotaBytes); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrUnavailable, err)
+	}
+	if err := s.buckets.UpdateQuota(ctx, appID, quotaBytes); err != nil {
+		return nil, err
+	}
+	b.QuotaBytes = quotaBytes
+	return b, nil
+}
+
+// DeleteBucket destroys the bucket and every object in it.
+func (s *BucketService) DeleteBucket(ctx context.Context, appID, userID uuid.UUID) error {
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return err
+	}
+	return s.ReleaseBucket(ctx, appID)
+}
+
+// ==============================================================================
+// 2. Application Lifecycle Hooks
+// ==============================================================================
+
+// BucketEnvFor satisfies domain.AppBucketSource. The secret is decrypted only
+// for the deploy RPC that carries it.
+func (s *BucketService) BucketEnvFor(ctx context.Context, appID uuid.UUID) (map[string]string, error) {
+	b, err := s.buckets.GetByAppID(ctx, appID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ready(); err != nil {
+		return nil, err
+	}
+
+	secret, err := s.crypto.Decrypt(ctx, b.SecretKeyCiphertext, bucketAAD(appID))
+	if err != nil {
+		return nil, fmt.Errorf("security: failed to decrypt bucket secret: %w", err)
+	}
+	return map[string]string{
+		"S3_ENDPOINT":          s.store.Endpoint(),
+		"S3_REGION":            s.store.Region(),
+		"S3_BUCKET":            b.Bucket,
+		"S3_ACCESS_KEY_ID":     b.AccessKey,
+		"S3_SECRET_ACCESS_KEY": string(secret),
+	}, nil
+}
+
+// ReleaseBucket satisfies domain.AppBucketSource.
+func (s *BucketService) ReleaseBucket(ctx context.Context, appID uuid.UUID) error {
+	b, err := s.buckets.GetByAppID(ctx, appID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if s.store == nil {
+		return fmt.Errorf("%w: object storage is not configured", domain.ErrUnavailable)
+	}
+
+	if err := s.store.DeleteBucket(ctx, b.Bucket, b.AccessKey); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrUnavailable, err)
+	}
+	if err := s.buckets.Delete(ctx, appID); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	s.logger.Info("App bucket deleted",
+		slog.String("app_id", appID.String()),
+		slog.String("bucket", b.Bucket))
+	return nil
+}
+
+// ==============================================================================
+// 3. Helpers
+// ==============================================================================
+
+func (s *BucketService) ready() error {
+	if s.store == nil {
+		return fmt.Errorf("%w: object storage is not configured", domain.ErrUnavailable)
+	}
+	if s.crypto == nil {
+		return fmt.Errorf("%w: object storage requires the crypto service", domain.ErrUnavailable)
+	}
+	return nil
+}
+
+// validateQuota bounds quotaBytes to 1 TiB, and to the platform default
+// unless the caller may override it: a tenant cannot buy itself storage.
+func (s *BucketService) validateQuota(quotaBytes int64, overrideDefault bool) error {
+	if quotaBytes <= 0 || quotaBytes > maxBucketQuotaBytes {
+		return fmt.Errorf("%w: bucket quota must be between 1 byte and 1 TiB", domain.ErrValidation)
+	}
+	if quotaBytes > s.defaultQuota && !overrideDefault {
+		return fmt.Errorf("%w: a bucket quota above the plan default (%d bytes) needs an administrator", domain.ErrForbidden, s.defaultQuota)
+	}
+	return nil
+}
+
+// generateBucketCredentials returns an AWS-shaped key pair (20/40 chars).
+func generateBucketCredentials() (string, string, error) {
+	ak := make([]byte, 10)
+	sk := make([]byte, 30)
+	if _, err := rand.Read(ak); err != nil {
+		return "", "", fmt.Errorf("failed to generate bucket access key: %w", err)
+	}
+	if _, err := rand.Read(sk); err != nil {
+		return "", "", fmt.Errorf("failed to generate bucket secret: %w", err)
+	}
+	return strings.ToUpper(hex.EncodeToString(ak)), base64.RawURLEncoding.EncodeToString(sk), nil
+}
+
+// bucketAAD binds the secret to its app so rows cannot be swapped between apps.
+func bucketAAD(appID uuid.UUID) []byte {
+	return []byte("app_bucket:" + appID.String())
+}
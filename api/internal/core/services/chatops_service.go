@@ -0,0 +1,395 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// chatDeployer is the slice of ApplicationService the deploy command needs.
+type chatDeployer interface {
+	DeployWithOverrides(ctx context.Context, appID uuid.UUID, userID uuid.UUID, o domain.DeployOverrides) (<-chan string, error)
+}
+
+// chatPermissions re-checks RBAC for the linked user on every command; chat
+// requests carry no JWT, so a demoted or deactivated user loses access at once.
+type chatPermissions interface {
+	HasPermission(ctx context.Context, userID uuid.UUID, resource string, action string) (bool, error)
+}
+
+// chatApprovals decides deployment approvals from chat buttons.
+type chatApprovals interface {
+	Decide(ctx context.Context, id uuid.UUID, userID uuid.UUID, approve bool, via string) (*domain.DeploymentApproval, error)
+}
+
+// chatDeployTimeout bounds a chat-triggered build; the slash command request
+// itself has long returned by then.
+const chatDeployTimeout = 30 * time.Minute
+
+const chatHelp = "Usage:\n" +
+	"• `/kari status` lists your applications\n" +
+	"• `/kari status <domain>` shows one application\n" +
+	"• `/kari deploy <domain> [branch] [KEY=VALUE …]` starts a deployment; branch and build variables apply to this one only\n" +
+	"• `/kari link <code>` links this chat account (get a code under Settings → Integrations)\n" +
+	"• `/kari unlink` removes the link"
+
+// ChatOpsService executes chat slash commands on behalf of linked Kari users,
+// through the same services and permission checks as the HTTP API.
+type ChatOpsService struct {
+	repo      domain.ChatOpsRepository
+	apps      domain.ApplicationRepository
+	perms     chatPermissions
+	deployer  chatDeployer           // nil until an ApplicationService is wired
+	health    domain.AppHealthSource // Optional: live status in /kari status
+	responder domain.ChatResponder
+	approvals chatApprovals // Optional: approve/reject buttons
+	logger    *slog.Logger
+}
+
+func NewChatOpsService(
+	repo domain.ChatOpsRepository,
+	apps domain.ApplicationRepository,
+	perms chatPermissions,
+	deployer chatDeployer,
+	health domain.AppHealthSource,
+	responder domain.ChatResponder,
+	logger *slog.Logger,
+) *ChatOpsService {
+	return &ChatOpsService{
+		repo:      repo,
+		apps:      apps,
+		perms:     perms,
+		deployer:  deployer,
+		health:    health,
+		responder: responder,
+		logger:    logger,
+	}
+}
+
+// WithApprovals enables the approve/reject buttons on approval messages.
+func (s *ChatOpsService) WithApprovals(a chatApprovals) *ChatOpsService {
+	s.approvals = a
+	return s
+}
+
+// ==============================================================================
+// 1. Account Linking
+// ==============================================================================
+
+// IssueLinkCode returns a one-time code the user types into chat as
+// "/kari link CODE". Only its hash is stored.
+func (s *ChatOpsService) IssueLinkCode(ctx context.Context, userID uuid.UUID) (string, time.Time, error) {
+	raw := make([]byte, 10)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate link code: %w", err)
+	}
+	code := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw)
+	code = code[:8] + "-" + code[8:]
+
+	expiresAt := time.Now().Add(domain.ChatLinkCodeTTL)
+	if err := s.repo.CreateLinkCode(ctx, hashLinkCode(code), userID, expiresAt); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to store link code: %w", err)
+	}
+	return code, expiresAt, nil
+}
+
+func (s *ChatOpsService) ListIdentities(ctx context.Context, userID uuid.UUID) ([]domain.ChatIdentity, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+func hashLinkCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToUpper(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
+
+// ==============================================================================
+// 2. Command Dispatch
+// ==============================================================================
+
+// HandleCommand answers within the provider's reply deadline; anything slow
+// (a deploy) continues in the background and reports to cmd.ResponseURL.
+func (s *ChatOpsService) HandleCommand(ctx context.Context, cmd domain.ChatCommand) domain.ChatReply {
+	args := strings.Fields(cmd.Text)
+	if len(args) == 0 || args[0] == "help" {
+		return domain.ChatReply{Text: chatHelp, Ephemeral: true}
+	}
+
+	if args[0] == "link" {
+		if len(args) != 2 {
+			return domain.ChatReply{Text: "Usage: `/kari link <code>`", Ephemeral: true}
+		}
+		return s.link(ctx, cmd, args[1])
+	}
+
+	userID, err := s.repo.Resolve(ctx, cmd.Provider, cmd.TeamID, cmd.ExternalUserID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.ChatReply{Text: "This chat account is not linked to Kari. Get a code under Settings → Integrations, then run `/kari link <code>`.", Ephemeral: true}
+	}
+	if err != nil {
+		s.logger.Error("failed to resolve chat identity", slog.String("provider", cmd.Provider), slog.Any("error", err))
+		return chatFailure()
+	}
+
+	switch args[0] {
+	case "unlink":
+		if err := s.repo.Unlink(ctx, cmd.Provider, cmd.TeamID, cmd.ExternalUserID); err != nil {
+			return chatFailure()
+		}
+		return domain.ChatReply{Text: "Unlinked. Kari commands from this account are disabled.", Ephemeral: true}
+	case "status":
+		if !s.allowed(ctx, userID, "applications", "read") {
+			return chatForbidden()
+		}
+		if len(args) > 1 {
+			return s.appStatus(ctx, userID, args[1])
+		}
+		return s.listStatus(ctx, userID)
+	case "deploy":
+		if len(args) < 2 {
+			return domain.ChatReply{Text: "Usage: `/kari deploy <domain> [branch] [KEY=VALUE …]`", Ephemeral: true}
+		}
+		overrides, ok := parseDeployOverrides(args[2:])
+		if !ok {
+			return domain.ChatReply{Text: "Usage: `/kari deploy <domain> [branch] [KEY=VALUE …]`", Ephemeral: true}
+		}
+		if !s.allowed(ctx, userID, "applications", "write") {
+			return chatForbidden()
+		}
+		return s.deploy(ctx, cmd, userID, args[1], overrides)
+	default:
+		return domain.ChatReply{Text: fmt.Sprintf("Unknown command `%s`.\n%s", args[0], chatHelp), Ephemeral: true}
+	}
+}
+
+// HandleAction decides an approval on behalf of the linked user who clicked.
+// The decision itself is authorized by the approval service, not here.
+func (s *ChatOpsService) HandleAction(ctx context.Context, action domain.ChatAction) domain.ChatReply {
+	if s.approvals == nil {
+		return domain.ChatReply{Text: "Deployment approvals are not available on this server.", Ephemeral: true}
+	}
+	userID, err := s.repo.Resolve(ctx, action.Provider, action.TeamID, action.ExternalUserID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.ChatReply{Text: "Link your chat account with `/kari link <code>` before deciding approvals.", Ephemeral: true}
+	}
+	if err != nil {
+		s.logger.Error("failed to resolve chat identity", slog.String("provider", action.Provider), slog.Any("error", err))
+		return chatFailure()
+	}
+
+	a, err := s.approvals.Decide(ctx, action.ApprovalID, userID, action.Approve, action.Provider)
+	switch {
+	case errors.Is(err, domain.ErrConflict):
+		return domain.ChatReply{Text: "This deployment was already decided.", Ephemeral: true}
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrForbidden):
+		return domain.ChatReply{Text: "You can't decide this deployment.", Ephemeral: true}
+	case err != nil:
+		s.logger.Error("failed to decide approval from chat", slog.Any("error", err))
+		return chatFailure()
+	}
+
+	verdict := "✅ approved"
+	if a.Status == domain.ApprovalRejected {
+		verdict = "⛔ rejected"
+	}
+	reply := domain.ChatReply{
+		Text:    fmt.Sprintf("Deployment of `%s` (%s) %s by <@%s>.", a.DomainName, a.Branch, verdict, action.ExternalUserID),
+		Replace: true,
+	}
+	if action.ResponseURL != "" && s.responder != nil {
+		if err := s.responder.Respond(ctx, action.ResponseURL, reply); err != nil {
+			s.logger.Warn("failed to update approval message", slog.Any("error", err))
+		}
+	}
+	return reply
+}
+
+func (s *ChatOpsService) link(ctx context.Context, cmd domain.ChatCommand, code string) domain.ChatReply {
+	userID, err := s.repo.ConsumeLinkCode(ctx, hashLinkCode(code))
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.ChatReply{Text: "That code is invalid or has expired. Generate a new one and try again.", Ephemeral: true}
+	}
+	if err != nil {
+		s.logger.Error("failed to redeem link code", slog.Any("error", err))
+		return chatFailure()
+	}
+
+	err = s.repo.Link(ctx, &domain.ChatIdentity{
+		Provider:       cmd.Provider,
+		TeamID:         cmd.TeamID,
+		ExternalUserID: cmd.ExternalUserID,
+		UserID:         userID,
+	})
+	if err != nil {
+		s.logger.Error("failed to link chat identity", slog.Any("error", err))
+		return chatFailure()
+	}
+	s.logger.Info("🔗 Chat identity linked",
+		slog.String("provider", cmd.Provider),
+		slog.String("team_id", cmd.TeamID),
+		slog.String("user_id", userID.String()))
+	return domain.ChatReply{Text: "Linked. Try `/kari status`.", Ephemeral: true}
+}
+
+func (s *ChatOpsService) allowed(ctx context.Context, userID uuid.UUID, resource, action string) bool {
+	ok, err := s.perms.HasPermission(ctx, userID, resource, action)
+	if err != nil {
+		s.logger.Error("chat permission check failed", slog.String("user_id", userID.String()), slog.Any("error", err))
+		return false
+	}
+	return ok
+}
+
+// ==============================================================================
+// 3. Commands
+// ==============================================================================
+
+func (s *ChatOpsService) listStatus(ctx context.Context, userID uuid.UUID) domain.ChatReply {
+	page, err := s.apps.List(ctx, domain.ApplicationFilter{OwnerID: userID}, domain.PageRequest{Limit: domain.MaxPageLimit})
+	if err != nil {
+		s.logger.Error("failed to list applications for chat", slog.Any("error", err))
+		return chatFailure()
+	}
+	if len(page.Items) == 0 {
+		return domain.ChatReply{Text: "You have no applications yet.", Ephemeral: true}
+	}
+
+	var b strings.Builder
+	for _, app := range page.Items {
+		fmt.Fprintf(&b, "• `%s` %s\n", app.DomainName, s.describe(&app))
+	}
+	if page.Total > len(page.Items) {
+		fmt.Fprintf(&b, "…and %d more", page.Total-len(page.Items))
+	}
+	return domain.ChatReply{Text: b.String(), Ephemeral: true}
+}
+
+func (s *ChatOpsService) appStatus(ctx context.Context, userID uuid.UUID, name string) domain.ChatReply {
+	app, reply, ok := s.findApp(ctx, userID, name)
+	if !ok {
+		return reply
+	}
+	return domain.ChatReply{Text: fmt.Sprintf("`%s` %s", app.DomainName, s.describe(app)), Ephemeral: true}
+}
+
+func (s *ChatOpsService) describe(app *domain.Application) string {
+	status := app.Status
+	if s.health != nil {
+		if h := s.health.LastHealth(app.ID); h != nil {
+			if h.Up {
+				status += fmt.Sprintf(" (responding, %dms)", h.LatencyMS)
+			} else {
+				status += " (not responding)"
+			}
+		}
+	}
+	return status
+}
+
+func (s *ChatOpsService) deploy(ctx context.Context, cmd domain.ChatCommand, userID uuid.UUID, name string, o domain.DeployOverrides) domain.ChatReply {
+	if s.deployer == nil {
+		return domain.ChatReply{Text: "Deployments from chat are not available on this server.", Ephemeral: true}
+	}
+	app, reply, ok := s.findApp(ctx, userID, name)
+	if !ok {
+		return reply
+	}
+
+	// The slash command request ends long before the build does
+	deployCtx, cancel := context.WithTimeout(context.Background(), chatDeployTimeout)
+	logs, err := s.deployer.DeployWithOverrides(deployCtx, app.ID, userID, o)
+	if err != nil {
+		cancel()
+		var reason string
+		switch {
+		case errors.Is(err, domain.ErrValidation), errors.Is(err, domain.ErrUnavailable):
+			reason = err.Error()
+		default:
+			s.logger.Error("chat deploy failed to start", slog.String("app_id", app.ID.String()), slog.Any("error", err))
+			reason = "the deployment could not be started"
+		}
+		return domain.ChatReply{Text: fmt.Sprintf("❌ `%s`: %s", app.DomainName, reason), Ephemeral: true}
+	}
+
+	s.logger.Info("🚀 Deployment triggered from chat",
+		slog.String("provider", cmd.Provider),
+		slog.String("app_id", app.ID.String()),
+		slog.Bool("overrides", !o.IsZero()),
+		slog.String("user_id", userID.String()))
+
+	go func() {
+		defer cancel()
+		var last string
+		for line := range logs {
+			if trimmed := strings.TrimSpace(line); trimmed != "" {
+				last = trimmed
+			}
+		}
+		text := fmt.Sprintf("Deployment of `%s` finished.", app.DomainName)
+		if last != "" {
+			text += "\n> " + last
+		}
+		if cmd.ResponseURL == "" || s.responder == nil {
+			return
+		}
+		if err := s.responder.Respond(deployCtx, cmd.ResponseURL, domain.ChatReply{Text: text}); err != nil {
+			s.logger.Warn("failed to post chat follow-up", slog.String("app_id", app.ID.String()), slog.Any("error", err))
+		}
+	}()
+
+	return domain.ChatReply{Text: fmt.Sprintf("🚀 Deploying `%s`…", app.DomainName)}
+}
+
+// parseDeployOverrides reads "[branch] [KEY=VALUE …]". The values are build
+// variables for this deployment only; the service validates names.
+func parseDeployOverrides(args []string) (domain.DeployOverrides, bool) {
+	var o domain.DeployOverrides
+	for i, arg := range args {
+		key, value, isVar := strings.Cut(arg, "=")
+		if !isVar {
+			// Only the first word may be a branch
+			if i != 0 {
+				return o, false
+			}
+			o.Branch = arg
+			continue
+		}
+		if o.BuildEnv == nil {
+			o.BuildEnv = make(map[string]string)
+		}
+		o.BuildEnv[key] = value
+	}
+	return o, true
+}
+
+// findApp resolves a domain name among the user's own applications.
+func (s *ChatOpsService) findApp(ctx context.Context, userID uuid.UUID, name string) (*domain.Application, domain.ChatReply, bool) {
+	page, err := s.apps.List(ctx, domain.ApplicationFilter{OwnerID: userID}, domain.PageRequest{Limit: domain.MaxPageLimit})
+	if err != nil {
+		s.logger.Error("failed to list applications for chat", slog.Any("error", err))
+		return nil, chatFailure(), false
+	}
+	for i := range page.Items {
+		if strings.EqualFold(page.Items[i].DomainName, name) {
+			return &page.Items[i], domain.ChatReply{}, true
+		}
+	}
+	return nil, domain.ChatReply{Text: fmt.Sprintf("No application `%s` found.", name), Ephemeral: true}, false
+}
+
+func chatFailure() domain.ChatReply {
+	return domain.ChatReply{Text: "Something went wrong on the Kari side. Please try again.", Ephemeral: true}
+}
+
+func chatForbidden() domain.ChatReply {
+	return domain.ChatReply{Text: "Your Kari role does not allow this.", Ephemeral: true}
+}
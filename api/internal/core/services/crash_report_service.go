@@ -0,0 +1,245 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/version"
+)
+
+// recentLogSource is satisfied by telemetry.LogRing.
+type recentLogSource interface {
+	Recent() []domain.CrashLogLine
+}
+
+// sentryTarget is the store endpoint and auth header derived from a DSN.
+type sentryTarget struct {
+	storeURL string
+	auth     string
+}
+
+// CrashReportService persists recovered panics and, when a DSN is set,
+// forwards them to a Sentry-compatible collector (Sentry, GlitchTip, ...).
+type CrashReportService struct {
+	repo      domain.CrashRepository
+	auditRepo domain.AuditRepository
+	logs      recentLogSource
+	sentry    *sentryTarget // nil disables export
+	redactor  domain.LogRedactor
+	client    *http.Client
+	hostname  string
+	logger    *slog.Logger
+}
+
+func NewCrashReportService(repo domain.CrashRepository, audit domain.AuditRepository, logs recentLogSource, dsn string, logger *slog.Logger) (*CrashReportService, error) {
+	s := &CrashReportService{
+		repo:      repo,
+		auditRepo: audit,
+		logs:      logs,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		logger:    logger,
+	}
+	s.hostname, _ = os.Hostname()
+
+	if dsn != "" {
+		target, err := parseSentryDSN(dsn)
+		if err != nil {
+			return nil, err
+		}
+		s.sentry = target
+	}
+	return s, nil
+}
+
+// WithRedactor scrubs every report with the platform redaction rules before
+// it is logged, stored or exported: panic values, stacks and recent log
+// lines routinely carry tokens, DSNs and request data.
+func (s *CrashReportService) WithRedactor(r domain.LogRedactor) *CrashReportService {
+	s.redactor = r
+	return s
+}
+
+// parseSentryDSN turns https://<key>@host[/prefix]/<project> into the
+// legacy store endpoint, which every Sentry-compatible server accepts.
+func parseSentryDSN(dsn string) (*sentryTarget, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil || u.User.Username() == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid crash report DSN: expected scheme://key@host/project")
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	slash := strings.LastIndex(path, "/")
+	project := path[slash+1:]
+	if project == "" {
+		return nil, fmt.Errorf("invalid crash report DSN: missing project ID")
+	}
+
+	return &sentryTarget{
+		storeURL: fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, path[:slash], project),
+		auth: fmt.Sprintf("Sentry sentry_version=7, sentry_client=kari-brain/%s, sentry_key=%s",
+			version.Version, u.User.Username()),
+	}, nil
+}
+
+// Report records a recovered panic. It is called from deferred recover()
+// blocks, so it guards itself: a failure here must not escalate the crash.
+func (s *CrashReportService) Report(source, component string, recovered any, stack []byte, req *domain.CrashRequest) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			s.logger.Error("Crash reporter panicked", slog.Any("panic", rec))
+		}
+	}()
+
+	report := &domain.CrashReport{
+		ID:         uuid.New(),
+		Source:     source,
+		Component:  component,
+		Panic:      fmt.Sprint(recovered),
+		Stack:      string(stack),
+		Request:    req,
+		RecentLogs: s.logs.Recent(),
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	// The crashed request or worker context may already be cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.redact(ctx, report)
+
+	s.logger.Error("💥 Panic recovered",
+		slog.String("crash_id", report.ID.String()),
+		slog.String("source", source),
+		slog.String("component", component),
+		slog.String("panic", report.Panic),
+	)
+
+	if err := s.repo.Create(ctx, report); err != nil {
+		s.logger.Error("Failed to persist crash report", slog.Any("error", err))
+	}
+	if err := s.auditRepo.CreateAlert(ctx, &domain.SystemAlert{
+		Severity: "critical",
+		Category: "crash",
+		Message:  fmt.Sprintf("Panic in %s %s: %s", source, component, report.Panic),
+		Metadata: map[string]any{
+			"crash_id":  report.ID,
+			"source":    source,
+			"component": component,
+		},
+	}); err != nil {
+		s.logger.Error("Failed to record crash alert", slog.Any("error", err))
+	}
+
+	if s.sentry != nil {
+		go s.export(report)
+	}
+}
+
+// redact scrubs the report in place. Crashes belong to no tenant, so the
+// built-in and platform-wide rules apply.
+func (s *CrashReportService) redact(ctx context.Context, report *domain.CrashReport) {
+	if s.redactor == nil {
+		return
+	}
+	report.Panic = s.redactor.RedactText(ctx, uuid.Nil, report.Panic)
+	report.Stack = s.redactor.RedactText(ctx, uuid.Nil, report.Stack)
+	for i := range report.RecentLogs {
+		line := &report.RecentLogs[i]
+		line.Message = s.redactor.RedactText(ctx, uuid.Nil, line.Message)
+		line.Attrs = s.redactor.RedactMetadata(ctx, uuid.Nil, line.Attrs)
+	}
+	if report.Request != nil {
+		req := *report.Request // The caller's copy stays as it was
+		req.Path = s.redactor.RedactText(ctx, uuid.Nil, req.Path)
+		req.UserAgent = s.redactor.RedactText(ctx, uuid.Nil, req.UserAgent)
+		req.RemoteAddr = s.redactor.RedactText(ctx, uuid.Nil, req.RemoteAddr)
+		report.Request = &req
+	}
+}
+
+func (s *CrashReportService) List(ctx context.Context, limit int) ([]domain.CrashReport, error) {
+	return s.repo.List(ctx, limit)
+}
+
+func (s *CrashReportService) Get(ctx context.Context, id uuid.UUID) (*domain.CrashReport, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// ==============================================================================
+// Sentry-compatible Export
+// ==============================================================================
+
+func (s *CrashReportService) export(report *domain.CrashReport) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if err := s.deliver(ctx, report); err != nil {
+		s.logger.Warn("Crash report export failed", slog.String("crash_id", report.ID.String()), slog.Any("error", err))
+		return
+	}
+	if err := s.repo.MarkExported(ctx, report.ID); err != nil {
+		s.logger.Warn("Failed to mark crash report exported", slog.Any("error", err))
+	}
+}
+
+func (s *CrashReportService) deliver(ctx context.Context, report *domain.CrashReport) error {
+	event := map[string]any{
+		"event_id":    strings.ReplaceAll(report.ID.String(), "-", ""),
+		"timestamp":   report.CreatedAt.Format(time.RFC3339),
+		"level":       "fatal",
+		"platform":    "go",
+		"logger":      report.Source,
+		"server_name": s.hostname,
+		"release":     version.Version,
+		"message":     map[string]string{"formatted": report.Panic},
+		"exception": map[string]any{
+			"values": []map[string]string{{"type": "panic", "value": report.Panic, "module": report.Component}},
+		},
+		"tags": map[string]string{"source": report.Source, "component": report.Component},
+		"extra": map[string]any{
+			"stack":       report.Stack,
+			"recent_logs": report.RecentLogs,
+		},
+	}
+	if report.Request != nil {
+		event["request"] = map[string]any{
+			"method":  report.Request.Method,
+			"url":     report.Request.Path,
+			"headers": map[string]string{"User-Agent": report.Request.UserAgent},
+			"env":     map[string]string{"REMOTE_ADDR": report.Request.RemoteAddr},
+		}
+		event["tags"].(map[string]string)["request_id"] = report.Request.RequestID
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.sentry.storeURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid crash export request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", s.sentry.auth)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("crash export delivery failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("crash collector returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
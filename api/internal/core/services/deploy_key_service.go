@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/ssh"
+	"kari/api/internal/core/domain"
+)
+
+// DeployKeyService issues per-app ed25519 deploy keys for private repositories.
+type DeployKeyService struct {
+	apps   domain.ApplicationRepository
+	keys   domain.DeployKeyRepository
+	crypto domain.CryptoService
+	logger *slog.Logger
+}
+
+func NewDeployKeyService(apps domain.ApplicationRepository, keys domain.DeployKeyRepository, crypto domain.CryptoService, logger *slog.Logger) *DeployKeyService {
+	return &DeployKeyService{apps: apps, keys: keys, crypto: crypto, logger: logger}
+}
+
+// ==============================================================================
+// 1. User-Facing Lifecycle
+// ==============================================================================
+
+// GenerateDeployKey creates a fresh keypair. Regenerating rotates the key: the
+// old public key must be removed from the Git provider by the user.
+func (s *DeployKeyService) GenerateDeployKey(ctx context.Context, appID, userID uuid.UUID) (*domain.DeployKey, error) {
+	// 🛡️ Zero-Trust: Ownership check before any write
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return nil, err
+	}
+	if s.crypto == nil {
+		return nil, fmt.Errorf("%w: deploy keys require the crypto service", domain.ErrUnavailable)
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate deploy key: %w", err)
+	}
+	comment := "kari-deploy-" + appID.String()
+
+	block, err := ssh.MarshalPrivateKey(priv, comment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode deploy key: %w", err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode deploy key: %w", err)
+	}
+
+	// 🛡️ Privacy: AAD = AppID, the same binding the deployment worker uses for
+	// EncryptedSSHKey, so a leaked row cannot be replayed against another app.
+	ciphertext, err := s.crypto.Encrypt(ctx, pem.EncodeToMemory(block), []byte(appID.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt deploy key: %w", err)
+	}
+
+	key := &domain.DeployKey{
+		AppID:                appID,
+		PublicKey:            strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub))) + " " + comment,
+		Fingerprint:          ssh.FingerprintSHA256(sshPub),
+		PrivateKeyCiphertext: ciphertext,
+	}
+	if err := s.keys.Upsert(ctx, key); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Deploy key generated",
+		slog.String("app_id", appID.String()),
+		slog.String("fingerprint", key.Fingerprint))
+	return key, nil
+}
+
+func (s *DeployKeyService) GetDeployKey(ctx context.Context, appID, userID uuid.UUID) (*domain.DeployKey, error) {
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return nil, err
+	}
+	return s.keys.GetByAppID(ctx, appID)
+}
+
+func (s *DeployKeyService) RevokeDeployKey(ctx context.Context, appID, userID uuid.UUID) error {
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return err
+	}
+	return s.keys.Delete(ctx, appID)
+}
+
+// ==============================================================================
+// 2. Clone Path
+// ==============================================================================
+
+// PrivateKeyFor satisfies domain.DeployKeySource. The caller must only hold
+// the result for the duration of the clone RPC.
+func (s *DeployKeyService) PrivateKeyFor(ctx context.Context, appID uuid.UUID) (string, error) {
+	key, err := s.keys.GetByAppID(ctx, appID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return "", nil // Public repository
+	}
+	if err != nil {
+		return "", err
+	}
+	if s.crypto == nil {
+		return "", fmt.Errorf("%w: crypto service unavailable", domain.ErrUnavailable)
+	}
+
+	plaintext, err := s.crypto.Decrypt(ctx, key.PrivateKeyCiphertext, []byte(appID.String()))
+	if err != nil {
+		return "", fmt.Errorf("security: failed to decrypt deploy key: %w", err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// DeployScheduleService queues deployments for a later time. They are saved
+// as SCHEDULED, which the deployment worker never claims, and promoted to
+// PENDING by the DeployScheduler worker once scheduled_at passes.
+type DeployScheduleService struct {
+	deployments domain.DeploymentRepository // Approval-gated, like every other enqueue
+	repo        domain.DeploymentScheduleRepository
+	apps        domain.ApplicationRepository
+	perms       chatPermissions
+	logger      *slog.Logger
+}
+
+func NewDeployScheduleService(
+	deployments domain.DeploymentRepository,
+	repo domain.DeploymentScheduleRepository,
+	apps domain.ApplicationRepository,
+	perms chatPermissions,
+	logger *slog.Logger,
+) *DeployScheduleService {
+	return &DeployScheduleService{
+		deployments: deployments,
+		repo:        repo,
+		apps:        apps,
+		perms:       perms,
+		logger:      logger,
+	}
+}
+
+// ==============================================================================
+// 1. Scheduling
+// ==============================================================================
+
+func (s *DeployScheduleService) ScheduleAppDeployment(ctx context.Context, appID uuid.UUID, userID uuid.UUID, at time.Time) (*domain.ScheduledDeployment, error) {
+	now := time.Now()
+	if !at.After(now) {
+		return nil, fmt.Errorf("%w: scheduled_at must be in the future", domain.ErrValidation)
+	}
+	if at.After(now.Add(domain.MaxScheduleAhead)) {
+		return nil, fmt.Errorf("%w: scheduled_at may be at most %d days ahead", domain.ErrValidation, int(domain.MaxScheduleAhead.Hours()/24))
+	}
+
+	// 🛡️ Tenant Isolation: Not found for someone else's app
+	app, err := s.apps.GetByID(ctx, appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if app.AppType == "image" {
+		return nil, fmt.Errorf("%w: image-based apps are pulled, not built from Git", domain.ErrValidation)
+	}
+
+	d := &domain.Deployment{
+		ID:           uuid.New().String(),
+		AppID:        app.ID.String(),
+		DomainName:   app.DomainName,
+		RepoURL:      app.RepoURL,
+		Branch:       app.Branch,
+		BuildCommand: app.BuildCommand,
+		TargetPort:   app.EffectivePort(),
+		Status:       domain.StatusScheduled,
+	}
+	if err := s.deployments.Save(ctx, d); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetSchedule(ctx, d.ID, at, userID); err != nil {
+		// Without a time the scheduler would never promote it
+		_ = s.deployments.UpdateStatus(ctx, d.ID, domain.StatusFailed)
+		return nil, err
+	}
+
+	s.logger.Info("⏰ Deployment scheduled",
+		slog.String("deployment_id", d.ID),
+		slog.String("app_id", d.AppID),
+		slog.Time("scheduled_at", at),
+		slog.String("user_id", userID.String()))
+	return &domain.ScheduledDeployment{
+		ID:          d.ID,
+		AppID:       d.AppID,
+		DomainName:  d.DomainName,
+		Branch:      d.Branch,
+		Status:      d.Status, // AWAITING_APPROVAL if the app is gated
+		ScheduledAt: at,
+		ScheduledBy: &userID,
+	}, nil
+}
+
+// ListScheduled returns the caller's waiting deployments; server:manage
+// holders see everyone's.
+func (s *DeployScheduleService) ListScheduled(ctx context.Context, userID uuid.UUID) ([]domain.ScheduledDeployment, error) {
+	admin, err := s.perms.HasPermission(ctx, userID, "server", "manage")
+	if err != nil {
+		return nil, err
+	}
+	var scheduledBy *uuid.UUID
+	if !admin {
+		scheduledBy = &userID
+	}
+	return s.repo.ListWaiting(ctx, scheduledBy, domain.DefaultPageLimit)
+}
+
+// Cancel is allowed for whoever scheduled it, the app owner, and server:manage.
+func (s *DeployScheduleService) Cancel(ctx context.Context, deploymentID string, userID uuid.UUID) error {
+	d, err := s.repo.Get(ctx, deploymentID)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeCancel(ctx, d, userID); err != nil {
+		return err
+	}
+	if err := s.repo.Cancel(ctx, deploymentID); err != nil {
+		return err
+	}
+	s.logger.Info("⏰ Scheduled deployment cancelled",
+		slog.String("deployment_id", deploymentID),
+		slog.String("user_id", userID.String()))
+	return nil
+}
+
+func (s *DeployScheduleService) authorizeCancel(ctx context.Context, d *domain.ScheduledDeployment, userID uuid.UUID) error {
+	if d.ScheduledBy != nil && *d.ScheduledBy == userID {
+		return nil
+	}
+	if admin, err := s.perms.HasPermission(ctx, userID, "server", "manage"); err != nil {
+		return err
+	} else if admin {
+		return nil
+	}
+	appID, err := uuid.Parse(d.AppID)
+	if err != nil {
+		return domain.ErrNotFound
+	}
+	_, err = s.apps.GetByID(ctx, appID, userID)
+	return err
+}
+
+// ==============================================================================
+// 2. Promotion (DeployScheduler worker)
+// ==============================================================================
+
+// PromoteDue hands due deployments to the regular queue. Promotion is one
+// UPDATE, so several Brains promoting at once never double-queue a build.
+func (s *DeployScheduleService) PromoteDue(ctx context.Context) error {
+	promoted, err := s.repo.PromoteDue(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, d := range promoted {
+		s.logger.Info("⏰ Scheduled deployment is due; queued",
+			slog.String("deployment_id", d.ID),
+			slog.String("app_id", d.AppID),
+			slog.Time("scheduled_at", d.ScheduledAt))
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// logArchiveBatch bounds how many deployments one archival pass uploads.
+const logArchiveBatch = 100
+
+// DeploymentLogService serves full build logs as gzip downloads and moves the
+// logs of old, finished deployments out of Postgres into object storage.
+type DeploymentLogService struct {
+	repo       domain.DeploymentLogRepository
+	apps       domain.ApplicationRepository
+	store      domain.BlobStore // nil = no archival; live logs still download
+	bucket     string
+	archiveAge time.Duration
+	logger     *slog.Logger
+}
+
+func NewDeploymentLogService(
+	repo domain.DeploymentLogRepository,
+	apps domain.ApplicationRepository,
+	store domain.BlobStore,
+	bucket string,
+	archiveDays int,
+	logger *slog.Logger,
+) *DeploymentLogService {
+	return &DeploymentLogService{
+		repo:       repo,
+		apps:       apps,
+		store:      store,
+		bucket:     bucket,
+		archiveAge: time.Duration(archiveDays) * 24 * time.Hour,
+		logger:     logger,
+	}
+}
+
+// ==============================================================================
+// 1. Download
+// ==============================================================================
+
+// OpenDownload resolves the log source up front so a missing deployment or
+// archive surfaces as an HTTP error rather than a truncated gzip stream.
+func (s *DeploymentLogService) OpenDownload(ctx context.Context, userID, deploymentID uuid.UUID) (*domain.LogDownload, error) {
+	d, err := s.repo.GetDeployment(ctx, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	// 🛡️ Zero-Trust: Ownership check; another tenant's deployment is a 404
+	if _, err := s.apps.GetByID(ctx, d.AppID, userID); err != nil {
+		return nil, err
+	}
+
+	download := &domain.LogDownload{Filename: fmt.Sprintf("deployment-%s.log.gz", deploymentID)}
+
+	archive, err := s.repo.GetArchive(ctx, deploymentID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		download.WriterTo = liveLog{repo: s.repo, ctx: ctx, id: deploymentID}
+		return download, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if s.store == nil {
+		return nil, fmt.Errorf("%w: log archive storage is not configured", domain.ErrUnavailable)
+	}
+	obj, err := s.store.GetObject(ctx, s.bucket, archive.ObjectKey)
+	if err != nil {
+		return nil, err
+	}
+	download.WriterTo = archivedLog{obj}
+	return download, nil
+}
+
+// ReadLines returns one page of a deployment's log after afterSeq. Viewers
+// poll or scroll by passing NextAfterSeq back.
+func (s *DeploymentLogService) ReadLines(ctx context.Context, userID, deploymentID uuid.UUID, afterSeq int64, limit int) (*domain.DeploymentLogPage, error) {
+	d, err := s.repo.GetDeployment(ctx, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	// 🛡️ Zero-Trust: Ownership check; another tenant's deployment is a 404
+	if _, err := s.apps.GetByID(ctx, d.AppID, userID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = domain.DefaultLogPageLimit
+	}
+	limit = min(limit, domain.MaxLogPageLimit)
+
+	// One extra row tells us whether another page follows
+	lines, err := s.repo.ListLines(ctx, deploymentID, afterSeq, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &domain.DeploymentLogPage{Lines: lines, NextAfterSeq: afterSeq}
+	if len(lines) > limit {
+		page.Lines, page.HasMore = lines[:limit], true
+	}
+	if n := len(page.Lines); n > 0 {
+		page.NextAfterSeq = page.Lines[n-1].Seq
+		return page, nil
+	}
+
+	// An empty log may mean its rows were moved to object storage
+	switch _, err := s.repo.GetArchive(ctx, deploymentID); {
+	case err == nil:
+		page.Archived = true
+	case !errors.Is(err, domain.ErrNotFound):
+		return nil, err
+	}
+	return page, nil
+}
+
+// liveLog gzips deployment_logs chunks on the fly as they are read.
+type liveLog struct {
+	repo domain.DeploymentLogRepository
+	ctx  context.Context
+	id   uuid.UUID
+}
+
+func (l liveLog) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	_, err := writeGzipLog(l.ctx, l.repo, l.id, cw)
+	return cw.n, err
+}
+
+// archivedLog copies an already-compressed object straight through.
+type archivedLog struct {
+	obj io.ReadCloser
+}
+
+func (a archivedLog) WriteTo(w io.Writer) (int64, error) {
+	defer a.obj.Close()
+	return io.Copy(w, a.obj)
+}
+
+// writeGzipLog compresses every chunk of a deployment into w and reports
+// how many chunks it wrote.
+func writeGzipLog(ctx context.Context, repo domain.DeploymentLogRepository, id uuid.UUID, w io.Writer) (int, error) {
+	zw := gzip.NewWriter(w)
+	chunks := 0
+	err := repo.StreamChunks(ctx, id, func(content string) error {
+		chunks++
+		_, err := io.WriteString(zw, content)
+		return err
+	})
+	if err != nil {
+		zw.Close()
+		return chunks, err
+	}
+	return chunks, zw.Close()
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// ==============================================================================
+// 2. Archival
+// ==============================================================================
+
+// ArchiveDue uploads the logs of deployments that finished more than
+// archiveAge ago, then deletes their rows. The upload happens before the
+// rows go, so a crash mid-pass at worst re-uploads the same object.
+func (s *DeploymentLogService) ArchiveDue(ctx context.Context) {
+	if s.store == nil || s.archiveAge <= 0 {
+		return
+	}
+
+	due, err := s.repo.ListArchivable(ctx, time.Now().Add(-s.archiveAge), logArchiveBatch)
+	if err != nil {
+		s.logger.Error("Deployment log archival unavailable", slog.Any("error", err))
+		return
+	}
+
+	archived := 0
+	for _, d := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.archive(ctx, d); err != nil {
+			s.logger.Warn("Deployment log archival failed",
+				slog.String("deployment_id", d.DeploymentID.String()),
+				slog.Any("error", err))
+			continue
+		}
+		archived++
+	}
+
+	if archived > 0 {
+		s.logger.Info("🗄️ Deployment logs archived to object storage", slog.Int("count", archived))
+	}
+}
+
+func (s *DeploymentLogService) archive(ctx context.Context, d domain.DeploymentLogRecord) error {
+	var buf bytes.Buffer
+	chunks, err := writeGzipLog(ctx, s.repo, d.DeploymentID, &buf)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("deployment-logs/%s/%s.log.gz", d.AppID, d.DeploymentID)
+	size := int64(buf.Len())
+	if err := s.store.PutObject(ctx, s.bucket, key, &buf, size, "application/gzip"); err != nil {
+		return err
+	}
+
+	return s.repo.CompleteArchive(ctx, &domain.DeploymentLogArchive{
+		DeploymentID:    d.DeploymentID,
+		ObjectKey:       key,
+		CompressedBytes: size,
+		Chunks:          chunks,
+	})
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/url"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+const (
+	// renewalHorizon lists certificates expiring within this window.
+	renewalHorizon = 14 * 24 * time.Hour
+	// digestClaimTimeout lets a crashed send be retried on a later pass.
+	digestClaimTimeout = time.Hour
+)
+
+// DigestService mails each admin one Action Center summary per day or week.
+// Every (admin, period) pair is claimed in the delivery ledger before the
+// send, so restarts and multiple Brains never mail a digest twice.
+type DigestService struct {
+	repo      domain.DigestRepository
+	mailer    domain.Mailer // nil = digests disabled; preferences still work
+	publicURL string
+	sendHour  int                   // Hour, in the recipient's zone, from which the period's digest goes out
+	zones     domain.TimezoneSource // Optional: the install zone; UTC without it
+	logger    *slog.Logger
+}
+
+func NewDigestService(repo domain.DigestRepository, mailer domain.Mailer, publicURL string, sendHour int, logger *slog.Logger) *DigestService {
+	return &DigestService{
+		repo:      repo,
+		mailer:    mailer,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+		sendHour:  sendHour,
+		logger:    logger,
+	}
+}
+
+// WithTimezone schedules digests for admins without their own zone on the
+// install's wall clock instead of UTC.
+func (s *DigestService) WithTimezone(zones domain.TimezoneSource) *DigestService {
+	s.zones = zones
+	return s
+}
+
+// ==============================================================================
+// 1. Preferences
+// ==============================================================================
+
+func (s *DigestService) GetPreference(ctx context.Context, userID uuid.UUID) (domain.DigestFrequency, error) {
+	return s.repo.GetPreference(ctx, userID)
+}
+
+func (s *DigestService) SetPreference(ctx context.Context, userID uuid.UUID, f domain.DigestFrequency) error {
+	switch f {
+	case domain.DigestOff, domain.DigestDaily, domain.DigestWeekly:
+		return s.repo.SetPreference(ctx, userID, f)
+	default:
+		return fmt.Errorf("%w: frequency must be off, daily or weekly", domain.ErrValidation)
+	}
+}
+
+func (s *DigestService) Unsubscribe(ctx context.Context, token string) error {
+	return s.repo.UnsubscribeByToken(ctx, token)
+}
+
+// ==============================================================================
+// 2. Delivery
+// ==============================================================================
+
+// SendDue mails every digest whose period has started and is not yet sent.
+// Periods follow each recipient's wall clock: daily digests go out from
+// sendHour each local day; weekly ones from sendHour on the first day of the
+// local ISO week the Brain is up for.
+func (s *DigestService) SendDue(ctx context.Context, now time.Time) {
+	if s.mailer == nil {
+		return
+	}
+
+	recipients, err := s.repo.ListRecipients(ctx)
+	if err != nil {
+		s.logger.Error("Digest recipients unavailable", slog.Any("error", err))
+		return
+	}
+
+	// One summary per frequency; every admin sees the same platform state
+	summaries := map[domain.DigestFrequency]*domain.DigestSummary{}
+	sent := 0
+	for _, rc := range recipients {
+		if rc.Frequency == domain.DigestOff {
+			continue
+		}
+		local := now.In(s.locationOf(rc))
+		if local.Hour() < s.sendHour {
+			continue
+		}
+		period, since := digestPeriod(rc.Frequency, local)
+
+		claimed, err := s.repo.ClaimDelivery(ctx, rc.UserID, period, digestClaimTimeout)
+		if err != nil {
+			s.logger.Error("Digest claim failed", slog.String("user_id", rc.UserID.String()), slog.Any("error", err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		summary, ok := summaries[rc.Frequency]
+		if !ok {
+			summary, err = s.repo.Summarize(ctx, since, now.Add(renewalHorizon))
+			if err != nil {
+				s.logger.Error("Digest summary failed", slog.Any("error", err))
+				s.release(ctx, rc.UserID, period)
+				return
+			}
+			summaries[rc.Frequency] = summary
+		}
+
+		msg, err := s.render(rc, period, summary)
+		if err == nil {
+			err = s.mailer.Send(ctx, msg)
+		}
+		if err != nil {
+			s.logger.Warn("Digest delivery failed", slog.String("user_id", rc.UserID.String()), slog.Any("error", err))
+			s.release(ctx, rc.UserID, period)
+			continue
+		}
+		if err := s.repo.MarkDelivered(ctx, rc.UserID, period); err != nil {
+			s.logger.Error("Digest sent but not recorded", slog.String("user_id", rc.UserID.String()), slog.Any("error", err))
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		s.logger.Info("📬 Action Center digests sent", slog.Int("count", sent))
+	}
+}
+
+// locationOf is the recipient's zone, else the install's, else UTC.
+func (s *DigestService) locationOf(rc domain.DigestRecipient) *time.Location {
+	if rc.Timezone != "" {
+		if loc, err := domain.LoadTimezone(rc.Timezone); err == nil {
+			return loc
+		}
+	}
+	if s.zones != nil {
+		return s.zones.Location()
+	}
+	return time.UTC
+}
+
+func (s *DigestService) release(ctx context.Context, userID uuid.UUID, period string) {
+	if err := s.repo.ReleaseDelivery(ctx, userID, period); err != nil {
+		s.logger.Error("Failed to release digest claim", slog.Any("error", err))
+	}
+}
+
+// digestPeriod names the ledger period (from now's own wall clock) and the
+// start of the reporting window.
+func digestPeriod(f domain.DigestFrequency, now time.Time) (string, time.Time) {
+	if f == domain.DigestDaily {
+		return now.Format("2006-01-02"), now.Add(-24 * time.Hour)
+	}
+	year, week := now.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week), now.Add(-7 * 24 * time.Hour)
+}
+
+// ==============================================================================
+// 3. Rendering
+// ==============================================================================
+
+type digestView struct {
+	Period         string
+	Frequency      domain.DigestFrequency
+	Summary        *domain.DigestSummary
+	AlertTotal     int
+	PreferencesURL string
+	UnsubscribeURL string
+	Location       *time.Location // Dates are shown on the recipient's calendar
+}
+
+func (s *DigestService) render(rc domain.DigestRecipient, period string, summary *domain.DigestSummary) (domain.EmailMessage, error) {
+	unsubscribe := s.publicURL + "/api/v1/digest/unsubscribe?token=" + url.QueryEscape(rc.UnsubscribeToken)
+	view := digestView{
+		Period:         period,
+		Frequency:      rc.Frequency,
+		Summary:        summary,
+		PreferencesURL: s.publicURL + "/settings/notifications",
+		UnsubscribeURL: unsubscribe,
+		Location:       s.locationOf(rc),
+	}
+	for _, n := range summary.UnresolvedAlerts {
+		view.AlertTotal += n
+	}
+
+	var text, html bytes.Buffer
+	if err := digestTextTemplate.Execute(&text, view); err != nil {
+		return domain.EmailMessage{}, fmt.Errorf("failed to render digest: %w", err)
+	}
+	if err := digestHTMLTemplate.Execute(&html, view); err != nil {
+		return domain.EmailMessage{}, fmt.Errorf("failed to render digest: %w", err)
+	}
+
+	return domain.EmailMessage{
+		To:      rc.Email,
+		Subject: fmt.Sprintf("Kari %s digest (%s): %d open alerts, %d failed deploys", rc.Frequency, period, view.AlertTotal, summary.FailedDeployCount),
+		Text:    text.String(),
+		HTML:    html.String(),
+		Headers: map[string]string{
+			// RFC 8058 one-click unsubscribe for mail clients
+			"List-Unsubscribe":      "<" + unsubscribe + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+var digestFuncs = map[string]any{
+	"date":  func(t time.Time, loc *time.Location) string { return t.In(loc).Format("2006-01-02") },
+	"gib":   func(b int64) string { return fmt.Sprintf("%.1f GiB", float64(b)/(1<<30)) },
+	"float": func(f float64) string { return fmt.Sprintf("%.1f", f) },
+}
+
+var digestTextTemplate = texttemplate.Must(texttemplate.New("digest").Funcs(digestFuncs).Parse(
+	`Kari Action Center {{.Frequency}} digest ({{.Period}})
+
+Unresolved alerts: {{.AlertTotal}}{{range $sev, $n := .Summary.UnresolvedAlerts}}
+  {{$sev}}: {{$n}}{{end}}
+{{range .Summary.TopAlerts}}
+  - [{{.Severity}}] {{.Category}}: {{.Message}}{{end}}
+
+Certificate renewals due: {{len .Summary.RenewalsDue}}{{range .Summary.RenewalsDue}}
+  - {{.DomainName}} expires {{date .ExpiresAt $.Location}} ({{.Status}}){{end}}
+
+Failed deploys since {{date .Summary.Since $.Location}}: {{.Summary.FailedDeployCount}}{{range .Summary.FailedDeploys}}
+  - {{.DomainName}} at {{date .FailedAt $.Location}}{{end}}
+
+Resources (month to date vs last month)
+  CPU:       {{float .Summary.UsageThisMonth.CPUSeconds}} s vs {{float .Summary.UsageLastMonth.CPUSeconds}} s
+  RAM:       {{float .Summary.UsageThisMonth.RAMGBHours}} GB-h vs {{float .Summary.UsageLastMonth.RAMGBHours}} GB-h
+  Storage:   {{float .Summary.UsageThisMonth.StorageGBHours}} GB-h vs {{float .Summary.UsageLastMonth.StorageGBHours}} GB-h
+  Bandwidth: {{gib .Summary.UsageThisMonth.BandwidthBytes}} vs {{gib .Summary.UsageLastMonth.BandwidthBytes}}
+
+Digest preferences: {{.PreferencesURL}}
+Unsubscribe: {{.UnsubscribeURL}}
+`))
+
+var digestHTMLTemplate = template.Must(template.New("digest").Funcs(digestFuncs).Parse(
+	`<!doctype html>
+<html><body style="font-family:sans-serif;max-width:640px">
+<h2>Kari Action Center {{.Frequency}} digest <small>({{.Period}})</small></h2>
+
+<h3>Unresolved alerts: {{.AlertTotal}}</h3>
+<p>{{range $sev, $n := .Summary.UnresolvedAlerts}}{{$sev}}: <b>{{$n}}</b> &nbsp; {{end}}</p>
+{{if .Summary.TopAlerts}}<ul>{{range .Summary.TopAlerts}}<li>[{{.Severity}}] {{.Category}}: {{.Message}}</li>{{end}}</ul>{{end}}
+
+<h3>Certificate renewals due: {{len .Summary.RenewalsDue}}</h3>
+{{if .Summary.RenewalsDue}}<ul>{{range .Summary.RenewalsDue}}<li>{{.DomainName}} expires {{date .ExpiresAt $.Location}} ({{.Status}})</li>{{end}}</ul>{{end}}
+
+<h3>Failed deploys since {{date .Summary.Since $.Location}}: {{.Summary.FailedDeployCount}}</h3>
+{{if .Summary.FailedDeploys}}<ul>{{range .Summary.FailedDeploys}}<li>{{.DomainName}} at {{date .FailedAt $.Location}}</li>{{end}}</ul>{{end}}
+
+<h3>Resources</h3>
+<table cellpadding="4">
+<tr><th></th><th>Month to date</th><th>Last month</th></tr>
+<tr><td>CPU (s)</td><td>{{float .Summary.UsageThisMonth.CPUSeconds}}</td><td>{{float .Summary.UsageLastMonth.CPUSeconds}}</td></tr>
+<tr><td>RAM (GB-h)</td><td>{{float .Summary.UsageThisMonth.RAMGBHours}}</td><td>{{float .Summary.UsageLastMonth.RAMGBHours}}</td></tr>
+<tr><td>Storage (GB-h)</td><td>{{float .Summary.UsageThisMonth.StorageGBHours}}</td><td>{{float .Summary.UsageLastMonth.StorageGBHours}}</td></tr>
+<tr><td>Bandwidth</td><td>{{gib .Summary.UsageThisMonth.BandwidthBytes}}</td><td>{{gib .Summary.UsageLastMonth.BandwidthBytes}}</td></tr>
+</table>
+
+<p style="color:#666;font-size:12px">
+<a href="{{.PreferencesURL}}">Digest preferences</a> &middot; <a href="{{.UnsubscribeURL}}">Unsubscribe</a>
+</p>
+</body></html>
+`))
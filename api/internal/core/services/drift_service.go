@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/grpc/rustagent"
+)
+
+// driftDomains is the slice of the domain service adoption needs.
+type driftDomains interface {
+	CreateDomain(ctx context.Context, d *domain.Domain) (*domain.Domain, error)
+}
+
+// DriftService compares what the database says the host should run with the
+// Muscle's inventory of units, vhosts, jail users and certificates. Each
+// mismatch becomes a finding with one Action Center alert; orphans can then
+// be adopted into a tenant's account or removed from the host.
+type DriftService struct {
+	repo        domain.DriftRepository
+	domains     driftDomains
+	auditRepo   domain.AuditRepository
+	agentClient rustagent.SystemAgentClient
+	agentCaps   domain.AgentCapabilities
+	logger      *slog.Logger
+}
+
+func NewDriftService(
+	repo domain.DriftRepository,
+	domains driftDomains,
+	audit domain.AuditRepository,
+	agent rustagent.SystemAgentClient,
+	agentCaps domain.AgentCapabilities,
+	logger *slog.Logger,
+) *DriftService {
+	return &DriftService{
+		repo:        repo,
+		domains:     domains,
+		auditRepo:   audit,
+		agentClient: agent,
+		agentCaps:   agentCaps,
+		logger:      logger,
+	}
+}
+
+// Available reports whether the connected Muscle can list its resources.
+func (s *DriftService) Available() bool {
+	return s.agentCaps.Supports(domain.AgentFeatureInventory)
+}
+
+// ==============================================================================
+// 1. The Sweep (shared with the scheduled worker)
+// ==============================================================================
+
+// Sweep takes one inventory, records the drift it shows and alerts on each
+// new finding. Findings no longer observed are resolved along with their
+// alerts. A failed inventory records nothing: an empty host would otherwise
+// resolve every open finding.
+func (s *DriftService) Sweep(ctx context.Context) (opened, resolved int, err error) {
+	if !s.Available() {
+		return 0, 0, fmt.Errorf("%w: the Muscle agent is too old to list its resources", domain.ErrUnavailable)
+	}
+
+	inv, err := s.agentClient.ListManagedResources(ctx, &rustagent.Empty{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: resource inventory failed: %v", domain.ErrUnavailable, err)
+	}
+	desired, err := s.repo.DesiredState(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	newFindings, gone, err := s.repo.Record(ctx, detectDrift(desired, inv))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for i := range newFindings {
+		s.raiseAlert(ctx, &newFindings[i])
+	}
+	for _, f := range gone {
+		s.resolveAlert(ctx, &f, uuid.Nil)
+	}
+	return len(newFindings), len(gone), nil
+}
+
+// detectDrift classifies an inventory against the desired state. Scheduled
+// job units are left alone: they belong to cron entries, not to apps.
+func detectDrift(desired *domain.DesiredResources, inv *rustagent.ResourceInventory) []domain.DriftFinding {
+	appIDs := make(map[string]bool, len(desired.Apps))
+	appDomains := make(map[string]bool, len(desired.Apps))
+	for _, app := range desired.Apps {
+		appIDs[app.ID.String()] = true
+		appDomains[app.DomainName] = true
+	}
+
+	var findings []domain.DriftFinding
+	unitDomains := make(map[string]bool)
+	for _, unit := range inv.Units {
+		if strings.HasPrefix(unit, "kari-job-") {
+			continue
+		}
+		if id, ok := strings.CutPrefix(unit, "kari-app-"); ok {
+			if !appIDs[id] {
+				findings = append(findings, domain.DriftFinding{Kind: domain.DriftOrphanUnit, Resource: unit})
+			}
+			continue
+		}
+		// kari-{domain} or kari-{domain}_{process}
+		name, _, _ := strings.Cut(strings.TrimPrefix(unit, "kari-"), "_")
+		unitDomains[name] = true
+		if !appDomains[name] {
+			findings = append(findings, domain.DriftFinding{Kind: domain.DriftOrphanUnit, Resource: unit, Domain: name})
+		}
+	}
+
+	vhosts := make(map[string]bool, len(inv.Vhosts))
+	for _, vhost := range inv.Vhosts {
+		vhosts[vhost] = true
+		if !desired.Domains[vhost] {
+			findings = append(findings, domain.DriftFinding{Kind: domain.DriftOrphanVhost, Resource: vhost, Domain: vhost})
+		}
+	}
+
+	for _, user := range inv.JailUsers {
+		if !appIDs[strings.TrimPrefix(user, "kari-app-")] {
+			findings = append(findings, domain.DriftFinding{Kind: domain.DriftOrphanJail, Resource: user})
+		}
+	}
+
+	for _, cert := range inv.Certificates {
+		if !desired.Domains[cert] {
+			findings = append(findings, domain.DriftFinding{Kind: domain.DriftOrphanCertificate, Resource: cert, Domain: cert})
+		}
+	}
+
+	// Only running apps are expected on the host; PHP runs in a shared FPM pool
+	for _, app := range desired.Apps {
+		if app.Status != "running" {
+			continue
+		}
+		appID := app.ID
+		if app.AppType != "php" && !unitDomains[app.DomainName] {
+			findings = append(findings, domain.DriftFinding{
+				Kind: domain.DriftMissingUnit, Resource: "kari-" + app.DomainName, Domain: app.DomainName, AppID: &appID,
+			})
+		}
+		if !vhosts[app.DomainName] {
+			findings = append(findings, domain.DriftFinding{
+				Kind: domain.DriftMissingVhost, Resource: app.DomainName, Domain: app.DomainName, AppID: &appID,
+			})
+		}
+	}
+	return findings
+}
+
+// ==============================================================================
+// 2. Admin Actions
+// ==============================================================================
+
+func (s *DriftService) ListDrift(ctx context.Context, status domain.DriftStatus) ([]domain.DriftFinding, error) {
+	return s.repo.List(ctx, status)
+}
+
+// Adopt registers the orphan's domain to ownerID, so the resource is Kari's
+// again instead of being removed.
+func (s *DriftService) Adopt(ctx context.Context, id uuid.UUID, actorID uuid.UUID, ownerID uuid.UUID) (*domain.DriftFinding, error) {
+	f, err := s.openFinding(ctx, id, domain.DriftActionAdopt)
+	if err != nil {
+		return nil, err
+	}
+	if s.domains == nil {
+		return nil, fmt.Errorf("%w: domain registration is not available", domain.ErrUnavailable)
+	}
+
+	// A domain registered since the sweep already claims the resource
+	if _, err := s.domains.CreateDomain(ctx, &domain.Domain{
+		UserID:     ownerID,
+		DomainName: f.Domain,
+		SSLStatus:  "none",
+	}); err != nil {
+		return nil, err
+	}
+	return s.settle(ctx, f, domain.DriftAdopted, actorID)
+}
+
+// Cleanup removes the orphan from the host. The desired state is re-read
+// first, so a resource claimed since the sweep is never removed.
+func (s *DriftService) Cleanup(ctx context.Context, id uuid.UUID, actorID uuid.UUID) (*domain.DriftFinding, error) {
+	f, err := s.openFinding(ctx, id, domain.DriftActionCleanup)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Available() {
+		return nil, fmt.Errorf("%w: the Muscle agent is too old to remove resources", domain.ErrUnavailable)
+	}
+
+	desired, err := s.repo.DesiredState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !stillOrphaned(desired, f) {
+		return nil, fmt.Errorf("%w: %s is no longer orphaned", domain.ErrConflict, f.Resource)
+	}
+
+	resp, err := s.agentClient.RemoveManagedResource(ctx, &rustagent.RemoveResourceRequest{
+		TraceId: fmt.Sprintf("drift-%s-%d", f.ID.String()[:8], time.Now().UnixMilli()),
+		Kind:    driftResourceKinds[f.Kind],
+		Name:    f.Resource,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: resource removal failed: %v", domain.ErrUnavailable, err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("resource removal failed: %s", resp.ErrorMessage)
+	}
+
+	s.logger.Info("Orphaned resource removed",
+		slog.String("kind", string(f.Kind)),
+		slog.String("resource", f.Resource),
+		slog.String("actor_id", actorID.String()))
+	return s.settle(ctx, f, domain.DriftCleaned, actorID)
+}
+
+var driftResourceKinds = map[domain.DriftKind]rustagent.ResourceKind{
+	domain.DriftOrphanUnit:        rustagent.ResourceKind_UNIT,
+	domain.DriftOrphanVhost:       rustagent.ResourceKind_VHOST,
+	domain.DriftOrphanJail:        rustagent.ResourceKind_JAIL_USER,
+	domain.DriftOrphanCertificate: rustagent.ResourceKind_CERTIFICATE,
+}
+
+// stillOrphaned re-checks one finding against a fresh desired state.
+func stillOrphaned(desired *domain.DesiredResources, f *domain.DriftFinding) bool {
+	for _, candidate := range detectDrift(desired, &rustagent.ResourceInventory{
+		Units:        []string{f.Resource},
+		Vhosts:       []string{f.Resource},
+		JailUsers:    []string{f.Resource},
+		Certificates: []string{f.Resource},
+	}) {
+		if candidate.Kind == f.Kind && candidate.Resource == f.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *DriftService) openFinding(ctx context.Context, id uuid.UUID, action string) (*domain.DriftFinding, error) {
+	f, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if f.Status != domain.DriftOpen {
+		return nil, fmt.Errorf("%w: drift finding is already %s", domain.ErrConflict, f.Status)
+	}
+	if !f.Allows(action) {
+		return nil, fmt.Errorf("%w: %s is not possible for a %s finding", domain.ErrValidation, action, f.Kind)
+	}
+	return f, nil
+}
+
+func (s *DriftService) settle(ctx context.Context, f *domain.DriftFinding, status domain.DriftStatus, actorID uuid.UUID) (*domain.DriftFinding, error) {
+	if err := s.repo.Settle(ctx, f.ID, status, actorID); err != nil {
+		return nil, err
+	}
+	s.resolveAlert(ctx, f, actorID)
+
+	now := time.Now().UTC()
+	f.Status, f.SettledAt, f.SettledBy = status, &now, &actorID
+	f.Actions = f.AvailableActions()
+	return f, nil
+}
+
+// ==============================================================================
+// 3. Action Center
+// ==============================================================================
+
+var driftMessages = map[domain.DriftKind]string{
+	domain.DriftOrphanUnit:        "Systemd unit %s runs without a matching application",
+	domain.DriftOrphanVhost:       "A vhost for %s is served, but the domain is not registered",
+	domain.DriftOrphanJail:        "Jail user %s exists without a matching application",
+	domain.DriftOrphanCertificate: "A certificate for %s is stored, but the domain is not registered",
+	domain.DriftMissingUnit:       "Systemd unit %s of a running application is missing",
+	domain.DriftMissingVhost:      "The vhost of running application %s is missing",
+}
+
+func (s *DriftService) raiseAlert(ctx context.Context, f *domain.DriftFinding) {
+	resourceID := f.ID.String()
+	alert := &domain.SystemAlert{
+		Severity:   "warning",
+		Category:   "drift",
+		ResourceID: &resourceID,
+		Message:    fmt.Sprintf(driftMessages[f.Kind], f.Resource),
+		Metadata: map[string]any{
+			"finding_id": f.ID,
+			"kind":       f.Kind,
+			"resource":   f.Resource,
+			"domain":     f.Domain,
+			"actions":    f.Actions,
+		},
+	}
+	if err := s.auditRepo.CreateAlert(ctx, alert); err != nil {
+		s.logger.Error("Failed to raise drift alert", slog.String("resource", f.Resource), slog.Any("error", err))
+		return
+	}
+	if err := s.repo.AttachAlert(ctx, f.ID, alert.ID); err != nil {
+		s.logger.Error("Failed to link drift alert", slog.String("finding_id", f.ID.String()), slog.Any("error", err))
+	}
+}
+
+func (s *DriftService) resolveAlert(ctx context.Context, f *domain.DriftFinding, resolverID uuid.UUID) {
+	if f.AlertID == nil {
+		return
+	}
+	if err := s.auditRepo.ResolveAlert(ctx, *f.AlertID, resolverID); err != nil {
+		s.logger.Error("Failed to resolve drift alert", slog.String("finding_id", f.ID.String()), slog.Any("error", err))
+	}
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// Feed windows: certificates from shortly after expiry (so a lapsed one
+// stays visible) to the next renewal cycle, and a month of incidents.
+const (
+	feedCertificatePast    = 7 * 24 * time.Hour
+	feedCertificateHorizon = 60 * 24 * time.Hour
+	feedIncidentWindow     = 30 * 24 * time.Hour
+	feedItemLimit          = 100
+)
+
+// FeedService serves the iCal and RSS subscription feeds. The feed URL
+// carries a per-user token because calendar clients cannot send a JWT.
+type FeedService struct {
+	repo        domain.FeedRepository
+	perms       chatPermissions
+	maintenance domain.MaintenanceState
+	windows     domain.MaintenanceSchedule // Optional: planned maintenance windows
+	publicURL   string
+	logger      *slog.Logger
+}
+
+func NewFeedService(
+	repo domain.FeedRepository,
+	perms chatPermissions,
+	maintenance domain.MaintenanceState,
+	publicURL string,
+	logger *slog.Logger,
+) *FeedService {
+	return &FeedService{
+		repo:        repo,
+		perms:       perms,
+		maintenance: maintenance,
+		publicURL:   strings.TrimSuffix(publicURL, "/"),
+		logger:      logger,
+	}
+}
+
+// WithMaintenanceWindows adds planned windows to the feed as timed events.
+func (s *FeedService) WithMaintenanceWindows(w domain.MaintenanceSchedule) *FeedService {
+	s.windows = w
+	return s
+}
+
+// ==============================================================================
+// 1. Tokens
+// ==============================================================================
+
+// IssueToken replaces any previous token, so a leaked URL is revoked by
+// issuing a new one. Only the hash is stored.
+func (s *FeedService) IssueToken(ctx context.Context, userID uuid.UUID) (*domain.FeedToken, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := s.repo.SetToken(ctx, userID, hashFeedToken(token)); err != nil {
+		return nil, err
+	}
+	query := "?token=" + url.QueryEscape(token)
+	return &domain.FeedToken{
+		Token:       token,
+		CalendarURL: s.publicURL + "/api/v1/feeds/calendar.ics" + query,
+		RSSURL:      s.publicURL + "/api/v1/feeds/rss" + query,
+	}, nil
+}
+
+func (s *FeedService) RevokeToken(ctx context.Context, userID uuid.UUID) error {
+	return s.repo.DeleteToken(ctx, userID)
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ==============================================================================
+// 2. Feed Content
+// ==============================================================================
+
+// FeedForToken builds the feed for the token's owner. Admins see every
+// resource on the server; everyone else sees their own apps and domains.
+func (s *FeedService) FeedForToken(ctx context.Context, token string) (*domain.Feed, error) {
+	if token == "" {
+		return nil, domain.ErrNotFound
+	}
+	userID, err := s.repo.ResolveToken(ctx, hashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	// 🛡️ Zero-Trust: RBAC is re-checked on every fetch; a deactivated user's
+	// URL stops working without anyone revoking it
+	scope, err := s.scopeFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	feed := &domain.Feed{GeneratedAt: now, Link: s.publicURL + "/", Items: []domain.FeedItem{}}
+
+	certs, err := s.repo.CertificateExpiries(ctx, scope, now.Add(-feedCertificatePast), now.Add(feedCertificateHorizon), feedItemLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range certs {
+		feed.Items = append(feed.Items, domain.FeedItem{
+			UID:         fmt.Sprintf("cert-%s-%d", c.DomainName, c.ExpiresAt.Unix()),
+			Kind:        domain.FeedCertificateExpiry,
+			Title:       fmt.Sprintf("Certificate for %s expires", c.DomainName),
+			Description: fmt.Sprintf("The TLS certificate for %s expires at %s (status: %s).", c.DomainName, c.ExpiresAt.UTC().Format(time.RFC1123), c.Status),
+			Start:       c.ExpiresAt,
+			Updated:     c.ExpiresAt,
+		})
+	}
+
+	incidents, err := s.repo.Incidents(ctx, scope, now.Add(-feedIncidentWindow), feedItemLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range incidents {
+		description := a.Message
+		if a.IsResolved {
+			description += " (resolved)"
+		}
+		feed.Items = append(feed.Items, domain.FeedItem{
+			UID:         "incident-" + a.ID.String(),
+			Kind:        domain.FeedIncident,
+			Title:       fmt.Sprintf("[%s] %s incident", strings.ToUpper(a.Severity), a.Category),
+			Description: description,
+			Start:       a.CreatedAt,
+			Updated:     a.CreatedAt,
+		})
+	}
+
+	feed.Items = append(feed.Items, s.maintenanceItems()...)
+
+	sort.SliceStable(feed.Items, func(i, j int) bool {
+		return feed.Items[i].Start.After(feed.Items[j].Start)
+	})
+	return feed, nil
+}
+
+func (s *FeedService) scopeFor(ctx context.Context, userID uuid.UUID) (domain.FeedScope, error) {
+	admin, err := s.perms.HasPermission(ctx, userID, "server", "manage")
+	if err != nil {
+		return domain.FeedScope{}, err
+	}
+	if admin {
+		return domain.FeedScope{}, nil
+	}
+	canRead, err := s.perms.HasPermission(ctx, userID, "applications", "read")
+	if err != nil {
+		return domain.FeedScope{}, err
+	}
+	if !canRead {
+		// Indistinguishable from an unknown token
+		return domain.FeedScope{}, domain.ErrNotFound
+	}
+	return domain.FeedScope{OwnerID: &userID}, nil
+}
+
+// maintenanceItems reports planned windows and the panel-wide maintenance
+// mode while it is on.
+func (s *FeedService) maintenanceItems() []domain.FeedItem {
+	items := []domain.FeedItem{}
+	if s.windows != nil {
+		for _, w := range s.windows.Upcoming() {
+			end := w.EndsAt
+			description := w.Description
+			if w.PauseDeploys {
+				description = strings.TrimSpace(description + " Deployments are paused during this window.")
+			}
+			items = append(items, domain.FeedItem{
+				UID:         "maintenance-window-" + w.ID.String(),
+				Kind:        domain.FeedMaintenance,
+				Title:       "Planned maintenance: " + w.Title,
+				Description: description,
+				Start:       w.StartsAt,
+				End:         &end,
+				Updated:     w.CreatedAt,
+			})
+		}
+	}
+
+	if s.maintenance == nil {
+		return items
+	}
+	m := s.maintenance.Maintenance()
+	if !m.Enabled || m.Since == nil {
+		return items
+	}
+	message := m.Message
+	if message == "" {
+		message = domain.DefaultMaintenanceMessage
+	}
+	return append(items, domain.FeedItem{
+		UID:         fmt.Sprintf("maintenance-%d", m.Since.Unix()),
+		Kind:        domain.FeedMaintenance,
+		Title:       "Maintenance in progress",
+		Description: message,
+		Start:       *m.Since,
+		Updated:     *m.Since,
+	})
+}
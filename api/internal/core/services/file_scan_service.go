@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/grpc/rustagent"
+)
+
+// alertSampleSize caps how many paths an Action Center alert carries; the
+// full list stays on the scan record.
+const alertSampleSize = 20
+
+// FileScanService runs integrity and malware scans over app directories via
+// the Muscle and turns findings into Action Center alerts.
+type FileScanService struct {
+	apps        domain.ApplicationRepository
+	scans       domain.FileScanRepository
+	auditRepo   domain.AuditRepository
+	agentClient rustagent.SystemAgentClient
+	agentCaps   domain.AgentCapabilities
+	clamav      bool
+	logger      *slog.Logger
+}
+
+func NewFileScanService(
+	apps domain.ApplicationRepository,
+	scans domain.FileScanRepository,
+	audit domain.AuditRepository,
+	agent rustagent.SystemAgentClient,
+	agentCaps domain.AgentCapabilities,
+	clamav bool,
+	logger *slog.Logger,
+) *FileScanService {
+	return &FileScanService{
+		apps:        apps,
+		scans:       scans,
+		auditRepo:   audit,
+		agentClient: agent,
+		agentCaps:   agentCaps,
+		clamav:      clamav,
+		logger:      logger,
+	}
+}
+
+// ==============================================================================
+// 1. Tenant-Facing Operations
+// ==============================================================================
+
+// ScanApp runs an on-demand scan of one of the caller's apps.
+func (s *FileScanService) ScanApp(ctx context.Context, appID, userID uuid.UUID) (*domain.FileScan, error) {
+	app, err := s.apps.GetByID(ctx, appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Scan(ctx, app, false)
+}
+
+// AcceptChanges re-baselines the app: the current tree becomes known-good.
+// ClamAV still runs, so accepting changes never hides an infected file.
+func (s *FileScanService) AcceptChanges(ctx context.Context, appID, userID uuid.UUID) (*domain.FileScan, error) {
+	app, err := s.apps.GetByID(ctx, appID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Scan(ctx, app, true)
+}
+
+func (s *FileScanService) ListScans(ctx context.Context, appID, userID uuid.UUID) ([]domain.FileScan, error) {
+	// 🛡️ Zero-Trust: Ownership check before reading history
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return nil, err
+	}
+	return s.scans.ListByApp(ctx, appID, 50)
+}
+
+// Available reports whether the connected Muscle can scan at all.
+func (s *FileScanService) Available() bool {
+	return s.agentCaps.Supports(domain.AgentFeatureFileScan)
+}
+
+// ==============================================================================
+// 2. Scan Execution (shared with the scheduled worker)
+// ==============================================================================
+
+// Scan asks the Muscle to diff the app against its baseline, records the run
+// and raises an alert for any finding. A failed scan is recorded too, so a
+// silently broken scanner shows up in the history.
+func (s *FileScanService) Scan(ctx context.Context, app *domain.Application, rebaseline bool) (*domain.FileScan, error) {
+	if app.AppType == "image" {
+		return nil, fmt.Errorf("%w: image-based apps have no app directory to scan", domain.ErrValidation)
+	}
+	if !s.Available() {
+		return nil, fmt.Errorf("%w: the Muscle agent is too old to scan app files", domain.ErrUnavailable)
+	}
+
+	scan := &domain.FileScan{ID: uuid.New(), AppID: app.ID, StartedAt: time.Now()}
+	resp, err := s.agentClient.ScanAppFiles(ctx, &rustagent.FileScanRequest{
+		TraceId:    fmt.Sprintf("scan-%s-%d", app.ID.String()[:8], scan.StartedAt.UnixMilli()),
+		AppId:      app.ID.String(),
+		DomainName: app.DomainName,
+		Rebaseline: rebaseline,
+		Clamav:     s.clamav,
+		Exclude:    domain.DefaultScanExcludes,
+	})
+	if err != nil {
+		scan.Status, scan.Error = domain.FileScanFailed, err.Error()
+		if recErr := s.scans.Create(ctx, scan); recErr != nil {
+			s.logger.Error("Failed to record failed file scan", slog.Any("error", recErr))
+		}
+		return nil, fmt.Errorf("%w: file scan failed: %v", domain.ErrUnavailable, err)
+	}
+
+	scan.FilesScanned = int64(resp.FilesScanned)
+	for _, f := range resp.Findings {
+		scan.Findings = append(scan.Findings, domain.FileFinding{Path: f.Path, Kind: f.Kind, Detail: f.Detail})
+	}
+	switch {
+	case len(scan.Findings) > 0:
+		scan.Status = domain.FileScanFindings
+	case resp.BaselineCreated:
+		scan.Status = domain.FileScanBaseline
+	default:
+		scan.Status = domain.FileScanClean
+	}
+	if err := s.scans.Create(ctx, scan); err != nil {
+		return nil, err
+	}
+
+	if len(scan.Findings) > 0 {
+		s.raiseAlert(ctx, app, scan)
+	}
+	return scan, nil
+}
+
+// raiseAlert files one Action Center alert per scan: critical for malware
+// signatures, warning for integrity drift alone.
+func (s *FileScanService) raiseAlert(ctx context.Context, app *domain.Application, scan *domain.FileScan) {
+	counts := map[string]int{}
+	sample := make([]string, 0, alertSampleSize)
+	for _, f := range scan.Findings {
+		counts[f.Kind]++
+		if len(sample) < alertSampleSize {
+			sample = append(sample, f.Kind+": "+f.Path)
+		}
+	}
+
+	severity := "warning"
+	message := fmt.Sprintf("%d file(s) changed outside a deploy on %s", len(scan.Findings), app.DomainName)
+	if counts[domain.FindingInfected] > 0 {
+		severity = "critical"
+		message = fmt.Sprintf("Malware signatures found in %d file(s) on %s", counts[domain.FindingInfected], app.DomainName)
+	}
+
+	resourceID := app.ID.String()
+	if err := s.auditRepo.CreateAlert(ctx, &domain.SystemAlert{
+		Severity:   severity,
+		Category:   "security",
+		ResourceID: &resourceID,
+		Message:    message,
+		Metadata: map[string]any{
+			"scan_id":  scan.ID,
+			"counts":   counts,
+			"sample":   sample,
+			"app_id":   app.ID,
+			"app_type": app.AppType,
+		},
+	}); err != nil {
+		s.logger.Error("Failed to raise file scan alert", slog.String("app_id", app.ID.String()), slog.Any("error", err))
+	}
+}
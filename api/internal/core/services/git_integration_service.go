@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+var gitOAuthStateAAD = []byte("git_oauth_state")
+
+// gitOAuthStateTTL bounds how long a user may sit on the provider's consent screen.
+const gitOAuthStateTTL = 10 * time.Minute
+
+// gitOAuthState is sealed with the CryptoService, so the callback needs no
+// server-side session and a forged or replayed-late state fails to decrypt/validate.
+type gitOAuthState struct {
+	UserID   uuid.UUID          `json:"u"`
+	Provider domain.GitProvider `json:"p"`
+	Expires  int64              `json:"e"`
+	Nonce    string             `json:"n"`
+}
+
+// GitIntegrationService connects user accounts on GitHub/GitLab and wires
+// deploy keys and push webhooks into linked repositories.
+type GitIntegrationService struct {
+	clients    map[domain.GitProvider]domain.GitProviderClient
+	conns      domain.GitConnectionRepository
+	apps       domain.ApplicationRepository
+	deployKeys domain.DeployKeyManager
+	crypto     domain.CryptoService
+	publicURL  string
+	logger     *slog.Logger
+}
+
+// NewGitIntegrationService registers only the providers that are configured.
+func NewGitIntegrationService(
+	clients []domain.GitProviderClient,
+	conns domain.GitConnectionRepository,
+	apps domain.ApplicationRepository,
+	deployKeys domain.DeployKeyManager,
+	crypto domain.CryptoService,
+	publicURL string,
+	logger *slog.Logger,
+) *GitIntegrationService {
+	byProvider := make(map[domain.GitProvider]domain.GitProviderClient, len(clients))
+	for _, c := range clients {
+		byProvider[c.Provider()] = c
+	}
+	return &GitIntegrationService{
+		clients:    byProvider,
+		conns:      conns,
+		apps:       apps,
+		deployKeys: deployKeys,
+		crypto:     crypto,
+		publicURL:  strings.TrimRight(publicURL, "/"),
+		logger:     logger,
+	}
+}
+
+// ==============================================================================
+// 1. OAuth Connect Flow
+// ==============================================================================
+
+func (s *GitIntegrationService) AuthorizeURL(ctx context.Context, userID uuid.UUID, provider domain.GitProvider) (string, error) {
+	client, err := s.client(provider)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate oauth nonce: %w", err)
+	}
+	raw, _ := json.Marshal(gitOAuthState{
+		UserID:   userID,
+		Provider: provider,
+		Expires:  time.Now().Add(gitOAuthStateTTL).Unix(),
+		Nonce:    hex.EncodeToString(nonce),
+	})
+	state, err := s.crypto.Encrypt(ctx, raw, gitOAuthStateAAD)
+	if err != nil {
+		return "", fmt.Errorf("failed to seal oauth state: %w", err)
+	}
+	return client.AuthorizeURL(state, s.callbackURL(provider)), nil
+}
+
+// CompleteOAuth runs on the provider redirect. The user is identified by the
+// sealed state, not by a session, because the browser arrives from the provider.
+func (s *GitIntegrationService) CompleteOAuth(ctx context.Context, provider domain.GitProvider, code, state string) (*domain.GitConnection, error) {
+	client, err := s.client(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := s.crypto.Decrypt(ctx, state, gitOAuthStateAAD)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid oauth state", domain.ErrForbidden)
+	}
+	var st gitOAuthState
+	if err := json.Unmarshal(raw, &st); err != nil || st.Provider != provider {
+		return nil, fmt.Errorf("%w: invalid oauth state", domain.ErrForbidden)
+	}
+	if time.Now().Unix() > st.Expires {
+		return nil, fmt.Errorf("%w: oauth state expired, please reconnect", domain.ErrForbidden)
+	}
+
+	token, err := client.Exchange(ctx, code, s.callbackURL(provider))
+	if err != nil {
+		return nil, err
+	}
+	login, err := client.AccountLogin(ctx, token.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s account: %w", provider, err)
+	}
+
+	conn := &domain.GitConnection{UserID: st.UserID, Provider: provider, AccountLogin: login}
+	if err := s.storeToken(ctx, conn, token); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Git provider connected",
+		slog.String("user_id", st.UserID.String()),
+		slog.String("provider", string(provider)),
+		slog.String("login", login))
+	return conn, nil
+}
+
+func (s *GitIntegrationService) ListConnections(ctx context.Context, userID uuid.UUID) ([]domain.GitConnection, error) {
+	return s.conns.ListByUser(ctx, userID)
+}
+
+// Disconnect forgets the tokens. Deploy keys and webhooks already installed
+// on repositories are left in place so existing apps keep deploying.
+func (s *GitIntegrationService) Disconnect(ctx context.Context, userID uuid.UUID, provider domain.GitProvider) error {
+	return s.conns.Delete(ctx, userID, provider)
+}
+
+// ==============================================================================
+// 2. Repository Browsing
+// ==============================================================================
+
+func (s *GitIntegrationService) ListRepos(ctx context.Context, userID uuid.UUID, provider domain.GitProvider) ([]domain.GitRepo, error) {
+	client, token, err := s.session(ctx, userID, provider)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListRepos(ctx, token)
+}
+
+func (s *GitIntegrationService) ListBranches(ctx context.Context, userID uuid.UUID, provider domain.GitProvider, repo string) ([]domain.GitBranch, error) {
+	client, token, err := s.session(ctx, userID, provider)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListBranches(ctx, token, repo)
+}
+
+// ==============================================================================
+// 3. App Linking (Deploy Key + Push Webhook)
+// ==============================================================================
+
+// LinkApplication installs a fresh read-only deploy key and a push webhook on
+// repo. The caller must already own app.
+func (s *GitIntegrationService) LinkApplication(ctx context.Context, userID uuid.UUID, app *domain.Application, provider domain.GitProvider, repo string) error {
+	client, token, err := s.session(ctx, userID, provider)
+	if err != nil {
+		return err
+	}
+
+	// 1. Deploy key: the private half never leaves the Brain/Muscle
+	key, err := s.deployKeys.GenerateDeployKey(ctx, app.ID, userID)
+	if err != nil {
+		return err
+	}
+	if err := client.AddDeployKey(ctx, token, repo, "kari-"+app.ID.String(), key.PublicKey); err != nil {
+		return fmt.Errorf("failed to install deploy key on %s: %w", repo, err)
+	}
+
+	// 2. Push webhook (needs a reachable public URL)
+	if s.publicURL == "" {
+		s.logger.Warn("KARI_PUBLIC_URL unset; skipping push webhook",
+			slog.String("app_id", app.ID.String()))
+		return nil
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	secretHex := hex.EncodeToString(secret)
+	ciphertext, err := s.crypto.Encrypt(ctx, []byte(secretHex), []byte("webhook:"+app.ID.String()))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+	if err := s.apps.SetWebhookSecret(ctx, app.ID, ciphertext); err != nil {
+		return err
+	}
+
+	hookURL := fmt.Sprintf("%s/api/v1/webhooks/%s/%s", s.publicURL, provider, app.ID)
+	if err := client.AddPushWebhook(ctx, token, repo, hookURL, secretHex); err != nil {
+		return fmt.Errorf("failed to install push webhook on %s: %w", repo, err)
+	}
+
+	s.logger.Info("Application linked to git repository",
+		slog.String("app_id", app.ID.String()),
+		slog.String("provider", string(provider)),
+		slog.String("repo", repo))
+	return nil
+}
+
+// ==============================================================================
+// 4. Helpers
+// ==============================================================================
+
+func (s *GitIntegrationService) client(provider domain.GitProvider) (domain.GitProviderClient, error) {
+	client, ok := s.clients[provider]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s integration is not configured", domain.ErrUnavailable, provider)
+	}
+	if s.crypto == nil {
+		return nil, fmt.Errorf("%w: git integration requires the crypto service", domain.ErrUnavailable)
+	}
+	return client, nil
+}
+
+// session resolves the caller's own connection and a usable access token,
+// refreshing it first when the provider issued an expiring one.
+func (s *GitIntegrationService) session(ctx context.Context, userID uuid.UUID, provider domain.GitProvider) (domain.GitProviderClient, string, error) {
+	client, err := s.client(provider)
+	if err != nil {
+		return nil, "", err
+	}
+	conn, err := s.conns.Get(ctx, userID, provider)
+	if err != nil {
+		return nil, "", err
+	}
+	aad := tokenAAD(conn)
+
+	if conn.ExpiresAt != nil && time.Now().Add(time.Minute).After(*conn.ExpiresAt) && conn.RefreshTokenCiphertext != "" {
+		refresh, err := s.crypto.Decrypt(ctx, conn.RefreshTokenCiphertext, aad)
+		if err != nil {
+			return nil, "", fmt.Errorf("security: failed to decrypt refresh token: %w", err)
+		}
+		token, err := client.Refresh(ctx, string(refresh))
+		if err != nil {
+			return nil, "", err
+		}
+		if err := s.storeToken(ctx, conn, token); err != nil {
+			return nil, "", err
+		}
+		return client, token.AccessToken, nil
+	}
+
+	access, err := s.crypto.Decrypt(ctx, conn.AccessTokenCiphertext, aad)
+	if err != nil {
+		return nil, "", fmt.Errorf("security: failed to decrypt access token: %w", err)
+	}
+	return client, string(access), nil
+}
+
+func (s *GitIntegrationService) storeToken(ctx context.Context, conn *domain.GitConnection, token *domain.GitToken) error {
+	aad := tokenAAD(conn)
+	access, err := s.crypto.Encrypt(ctx, []byte(token.AccessToken), aad)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt access token: %w", err)
+	}
+	conn.AccessTokenCiphertext = access
+	conn.RefreshTokenCiphertext = ""
+	if token.RefreshToken != "" {
+		if conn.RefreshTokenCiphertext, err = s.crypto.Encrypt(ctx, []byte(token.RefreshToken), aad); err != nil {
+			return fmt.Errorf("failed to encrypt refresh token: %w", err)
+		}
+	}
+	conn.ExpiresAt = token.ExpiresAt
+	return s.conns.Upsert(ctx, conn)
+}
+
+func (s *GitIntegrationService) callbackURL(provider domain.GitProvider) string {
+	return s.publicURL + "/api/v1/git/callback/" + string(provider)
+}
+
+// tokenAAD binds tokens to their owner so rows cannot be swapped between users.
+func tokenAAD(conn *domain.GitConnection) []byte {
+	return []byte("git_connection:" + conn.UserID.String() + ":" + string(conn.Provider))
+}
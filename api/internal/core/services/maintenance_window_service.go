@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// MaintenanceWindowService owns planned downtime. Windows that have not
+// ended are cached in memory because the AppMonitor and deployment worker
+// consult them on every tick; Sync reloads them so every Brain converges.
+type MaintenanceWindowService struct {
+	repo      domain.MaintenanceWindowRepository
+	auditRepo domain.AuditRepository
+	logger    *slog.Logger
+	windows   atomic.Pointer[[]domain.MaintenanceWindow]
+
+	// IDs of the windows active at the last Sync, to announce transitions once.
+	// The first Sync only primes it, so a restart does not re-announce.
+	syncMu sync.Mutex
+	active map[uuid.UUID]bool
+	primed bool
+}
+
+func NewMaintenanceWindowService(repo domain.MaintenanceWindowRepository, audit domain.AuditRepository, logger *slog.Logger) *MaintenanceWindowService {
+	s := &MaintenanceWindowService{
+		repo:      repo,
+		auditRepo: audit,
+		logger:    logger,
+		active:    map[uuid.UUID]bool{},
+	}
+	s.windows.Store(&[]domain.MaintenanceWindow{})
+	return s
+}
+
+// ==============================================================================
+// 1. Hot Path (memory only)
+// ==============================================================================
+
+func (s *MaintenanceWindowService) ActiveWindow(now time.Time) *domain.MaintenanceWindow {
+	for _, w := range *s.windows.Load() {
+		if w.ActiveAt(now) {
+			return &w
+		}
+	}
+	return nil
+}
+
+// DeploysPaused is true while any active window pauses the queue.
+func (s *MaintenanceWindowService) DeploysPaused(now time.Time) bool {
+	for _, w := range *s.windows.Load() {
+		if w.PauseDeploys && w.ActiveAt(now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MaintenanceWindowService) Upcoming() []domain.MaintenanceWindow {
+	now := time.Now()
+	upcoming := []domain.MaintenanceWindow{}
+	for _, w := range *s.windows.Load() {
+		if w.EndsAt.After(now) {
+			upcoming = append(upcoming, w)
+		}
+	}
+	return upcoming
+}
+
+// ==============================================================================
+// 2. Admin API
+// ==============================================================================
+
+func (s *MaintenanceWindowService) Schedule(ctx context.Context, actorID uuid.UUID, w *domain.MaintenanceWindow) (*domain.MaintenanceWindow, error) {
+	w.Title = strings.TrimSpace(w.Title)
+	if w.Title == "" {
+		return nil, fmt.Errorf("%w: title is required", domain.ErrValidation)
+	}
+	if !w.EndsAt.After(w.StartsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", domain.ErrValidation)
+	}
+	if !w.EndsAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: the window has already ended", domain.ErrValidation)
+	}
+
+	w.CreatedBy = &actorID
+	if err := s.repo.Create(ctx, w); err != nil {
+		return nil, fmt.Errorf("failed to schedule maintenance window: %w", err)
+	}
+	s.logger.Info("🚧 Maintenance window scheduled",
+		slog.String("window_id", w.ID.String()),
+		slog.Time("starts_at", w.StartsAt),
+		slog.Time("ends_at", w.EndsAt),
+		slog.Bool("pause_deploys", w.PauseDeploys),
+		slog.String("actor_id", actorID.String()))
+
+	// A window starting now takes effect without waiting for the next Sync
+	if err := s.Sync(ctx); err != nil {
+		s.logger.Warn("failed to reload maintenance windows", slog.Any("error", err))
+	}
+	return w, nil
+}
+
+func (s *MaintenanceWindowService) Cancel(ctx context.Context, actorID uuid.UUID, id uuid.UUID) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("🚧 Maintenance window cancelled",
+		slog.String("window_id", id.String()),
+		slog.String("actor_id", actorID.String()))
+	if err := s.Sync(ctx); err != nil {
+		s.logger.Warn("failed to reload maintenance windows", slog.Any("error", err))
+	}
+	return nil
+}
+
+// ==============================================================================
+// 3. Sync (MaintenanceScheduler worker)
+// ==============================================================================
+
+// Sync reloads the cache and announces windows that started or ended since
+// the last call. Nothing needs to be undone when a window ends: every check
+// is time-based, so monitoring and the deploy queue resume by themselves.
+func (s *MaintenanceWindowService) Sync(ctx context.Context) error {
+	now := time.Now()
+	windows, err := s.repo.ListUnended(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	s.windows.Store(&windows)
+
+	active := map[uuid.UUID]bool{}
+	for _, w := range windows {
+		if !w.ActiveAt(now) {
+			continue
+		}
+		active[w.ID] = true
+		if s.primed && !s.active[w.ID] {
+			s.raiseAlert(ctx, fmt.Sprintf("Maintenance window started: %s (until %s)", w.Title, w.EndsAt.UTC().Format(time.RFC3339)), w.ID)
+		}
+	}
+	for id := range s.active {
+		if !active[id] {
+			s.raiseAlert(ctx, "Maintenance window ended; monitoring and deployments resumed", id)
+		}
+	}
+	s.active = active
+	s.primed = true
+	return nil
+}
+
+func (s *MaintenanceWindowService) raiseAlert(ctx context.Context, message string, windowID uuid.UUID) {
+	s.logger.Warn("🚧 "+message, slog.String("window_id", windowID.String()))
+
+	if err := s.auditRepo.CreateAlert(ctx, &domain.SystemAlert{
+		Severity: "info",
+		Category: "maintenance",
+		Message:  message,
+		Metadata: map[string]any{"window_id": windowID},
+	}); err != nil {
+		s.logger.Error("Failed to record maintenance alert", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/utils"
+	pb "kari/api/proto/kari/agent/v1"
+)
+
+// UsageWebhookPayload is the body POSTed to the reseller's billing endpoint.
+type UsageWebhookPayload struct {
+	Event   string               `json:"event"` // "usage.monthly"
+	Month   string               `json:"month"`
+	Records []domain.UsageRecord `json:"records"`
+}
+
+// MeteringService samples per-app resource usage into monthly meters and
+// exports closed months for external billing. "Storage" is the app's object
+// storage bucket: Kari keeps no per-app backup archive to meter.
+type MeteringService struct {
+	apps          domain.ApplicationRepository
+	repo          domain.MeteringRepository
+	agentClient   pb.SystemAgentClient
+	buckets       domain.AppBucketRepository
+	store         domain.ObjectStorageProvider // nil when object storage is disabled
+	agentCaps     domain.AgentCapabilities
+	interval      time.Duration
+	webhookURL    string
+	webhookSecret []byte
+	client        *http.Client
+	logger        *slog.Logger
+}
+
+func NewMeteringService(
+	apps domain.ApplicationRepository,
+	repo domain.MeteringRepository,
+	agent pb.SystemAgentClient,
+	buckets domain.AppBucketRepository,
+	store domain.ObjectStorageProvider,
+	agentCaps domain.AgentCapabilities,
+	interval time.Duration,
+	webhookURL, webhookSecret string,
+	logger *slog.Logger,
+) *MeteringService {
+	return &MeteringService{
+		apps:          apps,
+		repo:          repo,
+		agentClient:   agent,
+		buckets:       buckets,
+		store:         store,
+		agentCaps:     agentCaps,
+		interval:      interval,
+		webhookURL:    webhookURL,
+		webhookSecret: []byte(webhookSecret),
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: logger,
+	}
+}
+
+// ==============================================================================
+// 1. Sampling
+// ==============================================================================
+
+// SampleAll accrues one interval of usage for every active app.
+func (s *MeteringService) SampleAll(ctx context.Context) {
+	apps, err := s.apps.ListAllActive(ctx)
+	if err != nil {
+		s.logger.Error("Usage sampling could not list apps", slog.Any("error", err))
+		return
+	}
+	for i := range apps {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.sample(ctx, &apps[i]); err != nil {
+			s.logger.Warn("Usage sample failed", slog.String("app_id", apps[i].ID.String()), slog.Any("error", err))
+		}
+	}
+}
+
+// sample turns the gap since each source's last reading into usage. Gaps
+// longer than two intervals (Brain downtime) are not billed: the usage in
+// them is unknown.
+func (s *MeteringService) sample(ctx context.Context, app *domain.Application) error {
+	now := time.Now().UTC()
+	cursors, err := s.repo.Cursors(ctx, app.ID)
+	if err != nil {
+		return err
+	}
+	elapsed := func(key string) float64 {
+		prev, ok := cursors[key]
+		if !ok {
+			return 0
+		}
+		gap := now.Sub(prev.SampledAt)
+		if gap <= 0 || gap > 2*s.interval {
+			return 0
+		}
+		return gap.Hours()
+	}
+
+	accrual := domain.UsageAccrual{
+		AppID:      app.ID,
+		TenantID:   app.OwnerID,
+		DomainName: app.DomainName,
+		Month:      domain.CurrentMonth(now),
+	}
+	var next []domain.MeterCursor
+
+	// ⚙️ Process units: RAM from MemoryCurrent, CPU from the cumulative counter
+	if app.AppType != "static" {
+		statuses, err := readProcessStatus(ctx, s.agentClient, s.agentCaps, app)
+		if err != nil && !errors.Is(err, domain.ErrUnavailable) {
+			return err
+		}
+		for _, st := range statuses {
+			if st.ActiveState != "active" {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s/%d", app.ID, st.Name, st.Instance)
+			accrual.RAMGBHours += float64(st.MemoryBytes) / (1 << 30) * elapsed(key)
+
+			cpu := int64(st.CPUUsageNS)
+			if s.agentCaps.Supports(domain.AgentFeatureCPUUsage) {
+				if prev, ok := cursors[key]; ok && sameStart(prev.StartedAt, st.Since) && cpu >= prev.Counter {
+					accrual.CPUSeconds += float64(cpu-prev.Counter) / 1e9
+				} else if ok {
+					accrual.CPUSeconds += float64(cpu) / 1e9 // Restarted: the counter began again at 0
+				}
+			}
+			next = append(next, domain.MeterCursor{Key: key, Counter: cpu, StartedAt: st.Since, SampledAt: now})
+		}
+	}
+
+	// 🪣 Object storage, billed as the usage seen at each sample
+	if s.store != nil {
+		if bucket, err := s.buckets.GetByAppID(ctx, app.ID); err == nil {
+			used, _, known, err := s.store.Usage(ctx, bucket.Bucket)
+			if err == nil && known {
+				key := app.ID.String() + "/bucket"
+				accrual.StorageGBHours = float64(used) / (1 << 30) * elapsed(key)
+				next = append(next, domain.MeterCursor{Key: key, SampledAt: now})
+			}
+		}
+	}
+
+	return s.repo.Accrue(ctx, accrual, next)
+}
+
+func sameStart(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// ==============================================================================
+// 2. Reporting & Export
+// ==============================================================================
+
+func (s *MeteringService) Report(ctx context.Context, month string) ([]domain.UsageRecord, error) {
+	m, err := domain.ParseUsageMonth(month)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Report(ctx, m)
+}
+
+// ExportDue delivers last month's report to the billing webhook once.
+// Failed deliveries are retried on every sampling pass.
+func (s *MeteringService) ExportDue(ctx context.Context) {
+	if s.webhookURL == "" {
+		return
+	}
+	month := domain.CurrentMonth(time.Now()).AddDate(0, -1, 0)
+	if exp, err := s.repo.GetExport(ctx, month); err == nil && exp.Status == "delivered" {
+		return
+	} else if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		s.logger.Error("Usage export state unavailable", slog.Any("error", err))
+		return
+	}
+
+	records, err := s.repo.Report(ctx, month)
+	if err != nil {
+		s.logger.Error("Usage export could not build report", slog.Any("error", err))
+		return
+	}
+	sendErr := s.deliver(ctx, UsageWebhookPayload{Event: "usage.monthly", Month: month.Format("2006-01"), Records: records})
+	if err := s.repo.RecordExport(ctx, month, sendErr); err != nil {
+		s.logger.Error("Failed to record usage export", slog.Any("error", err))
+	}
+	if sendErr != nil {
+		s.logger.Warn("Usage export delivery failed", slog.String("month", month.Format("2006-01")), slog.Any("error", sendErr))
+		return
+	}
+	s.logger.Info("📤 Usage export delivered", slog.String("month", month.Format("2006-01")), slog.Int("records", len(records)))
+}
+
+func (s *MeteringService) deliver(ctx context.Context, payload UsageWebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid usage webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "kari-brain/usage-export")
+	if len(s.webhookSecret) > 0 {
+		req.Header.Set("X-Kari-Signature-256", utils.SignPayload(body, s.webhookSecret))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("usage webhook delivery failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("usage webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/grpc/rustagent"
+)
+
+// outboxPayloadAAD binds stored payloads to the outbox table. It predates the
+// rename so changes queued by older Brains still decrypt.
+var outboxPayloadAAD = []byte("agent_op_queue:payload")
+
+// ChangeIDMetadataKey carries the outbox entry ID to the Muscle, which keeps
+// a ledger of applied IDs and acknowledges a redelivery without re-applying it.
+const ChangeIDMetadataKey = "x-kari-change-id"
+
+// dispatchableRPCs lists the mutating RPCs that go through the outbox, with
+// a factory for their request type so payloads can be decoded on dispatch.
+// 🛡️ Stability: Only intents that are safe to apply late belong here.
+var dispatchableRPCs = map[string]func() proto.Message{
+	rustagent.SystemAgent_InstallCertificate_FullMethodName: func() proto.Message { return &rustagent.SslPayload{} },
+	rustagent.SystemAgent_WriteSystemFile_FullMethodName:    func() proto.Message { return &rustagent.FileWriteRequest{} },
+	rustagent.SystemAgent_ManageService_FullMethodName:      func() proto.Message { return &rustagent.ServiceRequest{} },
+}
+
+const outboxDispatchBatch = 50
+
+// Outbox records every intended state change before anything touches the
+// host, then dispatches the ones with an RPC to the Muscle in order. A change
+// is retried until the agent answers; the change ID sent with it lets the
+// agent drop a redelivery, so each change is applied exactly once even when
+// the Brain dies between the agent's ack and marking the entry applied.
+type Outbox struct {
+	conn   grpc.ClientConnInterface
+	repo   domain.OutboxRepository
+	crypto domain.CryptoService
+	audit  domain.AuditRepository
+	logger *slog.Logger
+
+	dispatching sync.Mutex
+}
+
+func NewOutbox(conn grpc.ClientConnInterface, repo domain.OutboxRepository, crypto domain.CryptoService, audit domain.AuditRepository, logger *slog.Logger) *Outbox {
+	return &Outbox{conn: conn, repo: repo, crypto: crypto, audit: audit, logger: logger}
+}
+
+// ==============================================================================
+// 1. Submission
+// ==============================================================================
+
+// Submit appends change (carrying req for change.Method) and applies it right
+// away when nothing older is waiting. If the agent is unreachable, or earlier
+// changes are still pending, it stays queued and queued=true is returned
+// with a nil error so the user request still succeeds.
+func (o *Outbox) Submit(ctx context.Context, change *domain.StateChange, req proto.Message) (bool, error) {
+	if _, ok := dispatchableRPCs[change.Method]; !ok {
+		return false, fmt.Errorf("agent op %s cannot go through the outbox", change.Method)
+	}
+	if err := o.seal(ctx, change, req); err != nil {
+		return false, err
+	}
+	if err := o.repo.Append(ctx, change); err != nil {
+		return false, err
+	}
+
+	// 🛡️ Stability: Never let a new change overtake ones already waiting
+	pending, err := o.repo.CountPending(ctx)
+	if err != nil || pending > 1 {
+		return true, nil // Persisted either way: the dispatcher applies it
+	}
+
+	o.dispatching.Lock()
+	defer o.dispatching.Unlock()
+	return o.drainLocked(ctx, change.ID)
+}
+
+// Record appends a history-only change: one with nothing to apply on the host.
+func (o *Outbox) Record(ctx context.Context, change *domain.StateChange) error {
+	change.Method, change.PayloadCiphertext = "", ""
+	return o.repo.Append(ctx, change)
+}
+
+// History serves the admin state-change feed.
+func (o *Outbox) History(ctx context.Context, filter domain.StateChangeFilter) ([]domain.StateChange, error) {
+	return o.repo.List(ctx, filter)
+}
+
+func (o *Outbox) seal(ctx context.Context, change *domain.StateChange, req proto.Message) error {
+	if o.crypto == nil {
+		return fmt.Errorf("%w: the outbox requires the crypto service", domain.ErrUnavailable)
+	}
+	raw, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal agent op: %w", err)
+	}
+	ciphertext, err := o.crypto.Encrypt(ctx, raw, outboxPayloadAAD)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt agent op: %w", err)
+	}
+	change.PayloadCiphertext = ciphertext
+	return nil
+}
+
+// ==============================================================================
+// 2. Dispatch
+// ==============================================================================
+
+// ObserveAgent satisfies workers.AgentObserver: every successful heartbeat
+// drains the outbox if anything is waiting.
+func (o *Outbox) ObserveAgent(ctx context.Context, _ string, _, _ uint32) {
+	pending, err := o.repo.CountPending(ctx)
+	if err != nil || pending == 0 {
+		return
+	}
+	go o.Dispatch(ctx)
+}
+
+// Dispatch drains pending changes in seq order. It is what the dispatcher
+// worker ticks, and returns at once if a drain is already in flight.
+func (o *Outbox) Dispatch(ctx context.Context) {
+	if !o.dispatching.TryLock() {
+		return
+	}
+	defer o.dispatching.Unlock()
+	o.drainLocked(ctx, uuid.Nil)
+}
+
+// drainLocked applies pending changes in order, stopping at the first one
+// that still cannot reach the agent so later ones never run ahead of it.
+// It reports how the watched change ended: queued while still pending, or
+// the agent's rejection.
+func (o *Outbox) drainLocked(ctx context.Context, watch uuid.UUID) (queued bool, err error) {
+	seen, settled := false, false
+	for {
+		changes, loadErr := o.repo.NextPending(ctx, outboxDispatchBatch)
+		if loadErr != nil {
+			o.logger.Error("Failed to load pending state changes", slog.Any("error", loadErr))
+			return watch != uuid.Nil && !settled, err
+		}
+		if len(changes) == 0 {
+			break
+		}
+
+		for _, c := range changes {
+			seen = seen || c.ID == watch
+			applyErr := o.apply(ctx, c)
+			switch {
+			case applyErr == nil:
+				if markErr := o.repo.MarkApplied(ctx, c.ID); markErr != nil {
+					// The agent's ledger absorbs the redelivery this causes
+					o.logger.Error("Failed to mark state change applied", slog.Any("error", markErr))
+					return watch != uuid.Nil && !settled, err
+				}
+				settled = settled || c.ID == watch
+				o.logger.Info("✅ State change applied",
+					slog.Int64("seq", c.Seq),
+					slog.String("event", c.Event),
+					slog.String("description", c.Description))
+
+			case isAgentUnreachable(applyErr):
+				_ = o.repo.RecordAttempt(ctx, c.ID, applyErr.Error())
+				if watch != uuid.Nil {
+					o.logger.Warn("Muscle unreachable; state change queued",
+						slog.String("event", c.Event),
+						slog.String("description", c.Description))
+				}
+				return watch != uuid.Nil && !settled, err
+
+			default:
+				// The agent answered and rejected it: park the change
+				o.logger.Error("State change rejected by the agent",
+					slog.Int64("seq", c.Seq),
+					slog.String("method", c.Method),
+					slog.Any("error", applyErr))
+				if markErr := o.repo.MarkFailed(ctx, c.ID, applyErr.Error()); markErr != nil {
+					return watch != uuid.Nil && !settled, err
+				}
+				if c.ID == watch {
+					settled, err = true, applyErr // The caller surfaces it; no alert needed
+				} else {
+					o.raiseAlert(ctx, c)
+				}
+			}
+		}
+	}
+	if watch != uuid.Nil && !seen {
+		// A concurrent drain got to it first
+		c, getErr := o.repo.GetByID(ctx, watch)
+		if getErr == nil && c.Status == domain.ChangeFailed && c.LastError != nil {
+			return false, domain.AsAgentError(errors.New(*c.LastError))
+		}
+	}
+	return false, err
+}
+
+// apply sends one change through the shared connection, so outbox changes
+// get the same deadlines, breaker and error translation as direct calls.
+func (o *Outbox) apply(ctx context.Context, c domain.StateChange) error {
+	req, err := o.decode(ctx, c)
+	if err != nil {
+		return err
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, ChangeIDMetadataKey, c.ID.String())
+	resp := &rustagent.AgentResponse{}
+	if err := o.conn.Invoke(ctx, c.Method, req, resp); err != nil {
+		return err
+	}
+	if !resp.Success {
+		return domain.AsAgentError(errors.New(resp.ErrorMessage))
+	}
+	return nil
+}
+
+func (o *Outbox) decode(ctx context.Context, c domain.StateChange) (proto.Message, error) {
+	factory, ok := dispatchableRPCs[c.Method]
+	if !ok {
+		return nil, fmt.Errorf("agent op %s can no longer go through the outbox", c.Method)
+	}
+	if o.crypto == nil {
+		return nil, fmt.Errorf("%w: crypto service unavailable", domain.ErrUnavailable)
+	}
+	raw, err := o.crypto.Decrypt(ctx, c.PayloadCiphertext, outboxPayloadAAD)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt agent op: %w", err)
+	}
+	req := factory()
+	if err := proto.Unmarshal(raw, req); err != nil {
+		return nil, fmt.Errorf("corrupt agent op payload: %w", err)
+	}
+	return req, nil
+}
+
+// ==============================================================================
+// 3. Helpers
+// ==============================================================================
+
+func isAgentUnreachable(err error) bool {
+	var agentErr domain.AgentError
+	return errors.As(err, &agentErr) && agentErr.Code == domain.ErrAgentUnreachable
+}
+
+func (o *Outbox) raiseAlert(ctx context.Context, c domain.StateChange) {
+	if o.audit == nil {
+		return
+	}
+	err := o.audit.CreateAlert(ctx, &domain.SystemAlert{
+		Severity: "warning",
+		Category: "system",
+		Message:  "Queued change failed after the agent reconnected: " + c.Description,
+		Metadata: map[string]any{"change_id": c.ID.String(), "seq": c.Seq, "event": c.Event, "method": c.Method},
+	})
+	if err != nil {
+		o.logger.Warn("Failed to raise state change alert", slog.Any("error", err))
+	}
+}
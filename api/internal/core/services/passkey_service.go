@@ -0,0 +1,384 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// ceremonyTimeout bounds how long a browser prompt may stay open.
+const ceremonyTimeout = 5 * time.Minute
+
+// userLookup is satisfied by domain.UserRepository.
+type userLookup interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+}
+
+// tokenIssuer is satisfied by AuthService.
+type tokenIssuer interface {
+	GenerateTokenPair(ctx context.Context, user *domain.User) (string, string, error)
+}
+
+// PasskeyService registers WebAuthn credentials and signs users in with
+// them. Login is discoverable: the authenticator names the account, so the
+// login form needs no email first.
+type PasskeyService struct {
+	webauthn  *webauthn.WebAuthn
+	repo      domain.PasskeyRepository
+	users     userLookup
+	tokens    tokenIssuer
+	policy    domain.PasskeyPolicySource
+	auditRepo domain.AuditRepository
+	logger    *slog.Logger
+}
+
+func NewPasskeyService(
+	repo domain.PasskeyRepository,
+	users userLookup,
+	tokens tokenIssuer,
+	policy domain.PasskeyPolicySource,
+	audit domain.AuditRepository,
+	rpID string,
+	rpOrigins []string,
+	logger *slog.Logger,
+) (*PasskeyService, error) {
+	wa, err := webauthn.New(&webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: "Kari Panel",
+		RPOrigins:     rpOrigins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid WebAuthn relying party config: %w", err)
+	}
+	return &PasskeyService{
+		webauthn:  wa,
+		repo:      repo,
+		users:     users,
+		tokens:    tokens,
+		policy:    policy,
+		auditRepo: audit,
+		logger:    logger,
+	}, nil
+}
+
+// ==============================================================================
+// 1. Registration (authenticated)
+// ==============================================================================
+
+func (s *PasskeyService) BeginRegistration(ctx context.Context, userID uuid.UUID) (uuid.UUID, any, error) {
+	user, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	// Exclusions stop the same authenticator from being enrolled twice
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.credentials))
+	for _, c := range user.credentials {
+		exclusions = append(exclusions, c.Descriptor())
+	}
+
+	options, session, err := s.webauthn.BeginRegistration(user, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("failed to begin passkey registration: %w", err)
+	}
+
+	ceremonyID, err := s.saveSession(ctx, &userID, domain.CeremonyRegistration, session)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	return ceremonyID, options, nil
+}
+
+func (s *PasskeyService) FinishRegistration(ctx context.Context, userID, ceremonyID uuid.UUID, name string, response []byte) (*domain.Passkey, error) {
+	owner, session, err := s.takeSession(ctx, ceremonyID, domain.CeremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if owner == nil || *owner != userID {
+		return nil, domain.ErrNotFound // Someone else's ceremony
+	}
+
+	user, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed attestation: %v", domain.ErrValidation, err)
+	}
+	cred, err := s.webauthn.CreateCredential(user, *session, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: attestation rejected: %v", domain.ErrValidation, err)
+	}
+
+	raw, err := json.Marshal(cred)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode passkey: %w", err)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Passkey"
+	}
+
+	passkey := &domain.Passkey{
+		UserID:       userID,
+		Name:         name,
+		CredentialID: cred.ID,
+		Credential:   raw,
+	}
+	if err := s.repo.Create(ctx, passkey); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, userID, "passkey.register", passkey)
+	return passkey, nil
+}
+
+// ==============================================================================
+// 2. Login (public)
+// ==============================================================================
+
+func (s *PasskeyService) BeginLogin(ctx context.Context) (uuid.UUID, any, error) {
+	options, session, err := s.webauthn.BeginDiscoverableLogin()
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("failed to begin passkey login: %w", err)
+	}
+
+	ceremonyID, err := s.saveSession(ctx, nil, domain.CeremonyLogin, session)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	return ceremonyID, options, nil
+}
+
+// FinishLogin verifies the assertion and mints a session like a password
+// login would. 🛡️ Every failure is ErrInvalidCredentials so responses do
+// not reveal which credentials or accounts exist.
+func (s *PasskeyService) FinishLogin(ctx context.Context, ceremonyID uuid.UUID, response []byte) (*domain.TokenPair, *domain.User, error) {
+	_, session, err := s.takeSession(ctx, ceremonyID, domain.CeremonyLogin)
+	if err != nil {
+		return nil, nil, domain.ErrInvalidCredentials
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, nil, domain.ErrInvalidCredentials
+	}
+
+	var matched *domain.Passkey
+	var account *passkeyUser
+	resolve := func(rawID, userHandle []byte) (webauthn.User, error) {
+		pk, err := s.repo.GetByCredentialID(ctx, rawID)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(userHandle, pk.UserID[:]) {
+			return nil, errors.New("user handle does not match credential owner")
+		}
+		u, err := s.loadUser(ctx, pk.UserID)
+		if err != nil {
+			return nil, err
+		}
+		matched, account = pk, u
+		return u, nil
+	}
+
+	cred, err := s.webauthn.ValidateDiscoverableLogin(resolve, *session, parsed)
+	if err != nil {
+		s.logger.Info("Passkey assertion rejected", slog.Any("error", err))
+		return nil, nil, domain.ErrInvalidCredentials
+	}
+
+	// A sign count that went backwards means the private key was copied
+	if cred.Authenticator.CloneWarning {
+		s.raiseCloneAlert(ctx, matched)
+		return nil, nil, domain.ErrInvalidCredentials
+	}
+	if !account.user.IsActive {
+		return nil, nil, domain.ErrInvalidCredentials
+	}
+
+	if raw, err := json.Marshal(cred); err == nil {
+		if err := s.repo.UpdateCredential(ctx, matched.ID, raw); err != nil {
+			s.logger.Warn("Failed to persist passkey sign count", slog.Any("error", err))
+		}
+	}
+
+	access, refresh, err := s.tokens.GenerateTokenPair(ctx, account.user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &domain.TokenPair{AccessToken: access, RefreshToken: refresh}, account.user, nil
+}
+
+// ==============================================================================
+// 3. Management & Policy
+// ==============================================================================
+
+func (s *PasskeyService) List(ctx context.Context, userID uuid.UUID) ([]domain.Passkey, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// Delete refuses to remove the last passkey of an account that could then
+// no longer sign in at all.
+func (s *PasskeyService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if s.passkeyMandatory(user) {
+		keys, err := s.repo.ListByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 1 && keys[0].ID == id && !s.policy.PasskeyPolicy().PasswordFallback {
+			return fmt.Errorf("%w: the last passkey of an account that requires one cannot be removed", domain.ErrConflict)
+		}
+	}
+
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+	s.audit(ctx, userID, "passkey.delete", &domain.Passkey{ID: id})
+	return nil
+}
+
+// AllowPassword is consulted by AuthService after a correct password.
+// Rank-0 accounts under the passkey policy must use a passkey once they
+// have one; before that, PasswordFallback decides.
+func (s *PasskeyService) AllowPassword(ctx context.Context, user *domain.User) error {
+	if !s.passkeyMandatory(user) {
+		return nil
+	}
+	keys, err := s.repo.ListByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 && s.policy.PasskeyPolicy().PasswordFallback {
+		return nil
+	}
+	if len(keys) == 0 {
+		// Break-glass recovery removed the passkeys: the password may sign
+		// in until a new one is registered
+		pending, err := s.repo.ReenrollPending(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if pending {
+			return nil
+		}
+	}
+	return domain.ErrPasskeyRequired
+}
+
+func (s *PasskeyService) passkeyMandatory(user *domain.User) bool {
+	return s.policy.PasskeyPolicy().RequireForRank0 && user.Role.Rank == 0
+}
+
+// ==============================================================================
+// 4. Helpers
+// ==============================================================================
+
+// passkeyUser adapts a Kari user to webauthn.User. The WebAuthn user handle
+// is the raw 16-byte account UUID.
+type passkeyUser struct {
+	user        *domain.User
+	credentials []webauthn.Credential
+}
+
+func (u *passkeyUser) WebAuthnID() []byte {
+	id := u.user.ID
+	return id[:]
+}
+
+func (u *passkeyUser) WebAuthnName() string                       { return u.user.Email }
+func (u *passkeyUser) WebAuthnDisplayName() string                { return u.user.Email }
+func (u *passkeyUser) WebAuthnCredentials() []webauthn.Credential { return u.credentials }
+
+func (s *PasskeyService) loadUser(ctx context.Context, userID uuid.UUID) (*passkeyUser, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := make([]webauthn.Credential, 0, len(keys))
+	for _, k := range keys {
+		var c webauthn.Credential
+		if err := json.Unmarshal(k.Credential, &c); err != nil {
+			s.logger.Warn("Skipping undecodable passkey", slog.String("passkey_id", k.ID.String()), slog.Any("error", err))
+			continue
+		}
+		creds = append(creds, c)
+	}
+	return &passkeyUser{user: user, credentials: creds}, nil
+}
+
+func (s *PasskeyService) saveSession(ctx context.Context, userID *uuid.UUID, ceremony string, session *webauthn.SessionData) (uuid.UUID, error) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to encode webauthn session: %w", err)
+	}
+	id := uuid.New()
+	if err := s.repo.SaveSession(ctx, id, userID, ceremony, data, time.Now().Add(ceremonyTimeout)); err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+func (s *PasskeyService) takeSession(ctx context.Context, id uuid.UUID, ceremony string) (*uuid.UUID, *webauthn.SessionData, error) {
+	userID, data, err := s.repo.TakeSession(ctx, id, ceremony)
+	if err != nil {
+		return nil, nil, err
+	}
+	var session webauthn.SessionData
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode webauthn session: %w", err)
+	}
+	return userID, &session, nil
+}
+
+func (s *PasskeyService) audit(ctx context.Context, userID uuid.UUID, action string, p *domain.Passkey) {
+	if err := s.auditRepo.CreateTenantLog(ctx, &domain.TenantLog{
+		TenantID:     userID,
+		ActorID:      &userID,
+		Action:       action,
+		ResourceType: "passkey",
+		ResourceID:   p.ID.String(),
+		Metadata:     map[string]any{"name": p.Name},
+	}); err != nil {
+		s.logger.Error("Failed to record passkey audit log", slog.Any("error", err))
+	}
+}
+
+func (s *PasskeyService) raiseCloneAlert(ctx context.Context, p *domain.Passkey) {
+	resourceID := p.ID.String()
+	s.logger.Warn("🚨 Passkey sign count regressed; possible cloned authenticator", slog.String("passkey_id", resourceID))
+	if err := s.auditRepo.CreateAlert(ctx, &domain.SystemAlert{
+		Severity:   "critical",
+		Category:   "security",
+		ResourceID: &resourceID,
+		Message:    "Passkey sign count went backwards; the authenticator may have been cloned. Sign-in was refused.",
+		Metadata:   map[string]any{"user_id": p.UserID},
+	}); err != nil {
+		s.logger.Error("Failed to record passkey clone alert", slog.Any("error", err))
+	}
+}
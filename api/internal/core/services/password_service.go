@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"kari/api/internal/core/domain"
+)
+
+// bcryptMaxBytes is where bcrypt silently truncates its input.
+const bcryptMaxBytes = 72
+
+// passwordUsers is satisfied by domain.UserRepository.
+type passwordUsers interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	// UpdatePassword stamps password_changed_at, clears must_change_password
+	// and drops the stored refresh token.
+	UpdatePassword(ctx context.Context, id uuid.UUID, hash string) error
+}
+
+// sessionIssuer is satisfied by AuthService: it issues sessions and runs the
+// password gates other than this service.
+type sessionIssuer interface {
+	tokenIssuer
+	AllowPasswordExcept(ctx context.Context, user *domain.User, skip passwordGate) error
+}
+
+// PasswordService applies the admin-configured password policy on every
+// password write and expires passwords that outlive MaxAgeDays.
+type PasswordService struct {
+	users     passwordUsers
+	tokens    sessionIssuer
+	policy    domain.PasswordPolicySource
+	breaches  domain.BreachChecker // nil disables the corpus check regardless of policy
+	auditRepo domain.AuditRepository
+	logger    *slog.Logger
+}
+
+func NewPasswordService(
+	users passwordUsers,
+	tokens sessionIssuer,
+	policy domain.PasswordPolicySource,
+	breaches domain.BreachChecker,
+	audit domain.AuditRepository,
+	logger *slog.Logger,
+) *PasswordService {
+	return &PasswordService{
+		users:     users,
+		tokens:    tokens,
+		policy:    policy,
+		breaches:  breaches,
+		auditRepo: audit,
+		logger:    logger,
+	}
+}
+
+// ==============================================================================
+// 1. Policy
+// ==============================================================================
+
+func (s *PasswordService) ValidatePassword(ctx context.Context, email, password string) error {
+	policy := s.policy.PasswordPolicy()
+
+	if len([]rune(password)) < policy.MinLength {
+		return fmt.Errorf("%w: password must be at least %d characters", domain.ErrValidation, policy.MinLength)
+	}
+	if len(password) > bcryptMaxBytes {
+		return fmt.Errorf("%w: password must be at most %d bytes", domain.ErrValidation, bcryptMaxBytes)
+	}
+	if email != "" && strings.EqualFold(password, email) {
+		return fmt.Errorf("%w: password must not be the account email", domain.ErrValidation)
+	}
+
+	if policy.BreachCheck && s.breaches != nil {
+		count, err := s.breaches.Breached(ctx, password)
+		if err != nil {
+			// 🛡️ Fail open: an unreachable corpus must not lock admins out
+			s.logger.Warn("Password breach check skipped", slog.Any("error", err))
+			return nil
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: this password has appeared in a public data breach; choose another", domain.ErrValidation)
+		}
+	}
+	return nil
+}
+
+func (s *PasswordService) HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// AllowPassword is consulted by AuthService after a correct password. An
+// expired password is refused so the UI can switch to the forced-change form.
+func (s *PasswordService) AllowPassword(ctx context.Context, user *domain.User) error {
+	if user.MustChangePassword {
+		return domain.ErrPasswordExpired
+	}
+	if maxAge := s.policy.PasswordPolicy().MaxAgeDays; maxAge > 0 {
+		if time.Since(user.PasswordChangedAt) > time.Duration(maxAge)*24*time.Hour {
+			return domain.ErrPasswordExpired
+		}
+	}
+	return nil
+}
+
+// ==============================================================================
+// 2. Rotation
+// ==============================================================================
+
+// ChangePassword re-issues the caller's session: the rotation revokes the
+// stored refresh token, which would otherwise sign the caller out too.
+func (s *PasswordService) ChangePassword(ctx context.Context, userID uuid.UUID, current, next string) (*domain.TokenPair, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(current)); err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+	if err := s.rotate(ctx, user, next); err != nil {
+		return nil, err
+	}
+
+	access, refresh, err := s.tokens.GenerateTokenPair(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TokenPair{AccessToken: access, RefreshToken: refresh}, nil
+}
+
+// ChangeExpiredPassword is the forced-change form a refused login switches
+// to. It signs the user in only when their password really has expired and
+// the other password gates allow it.
+func (s *PasswordService) ChangeExpiredPassword(ctx context.Context, email, current, next string) (*domain.TokenPair, *domain.User, error) {
+	user, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		// 🛡️ Same anti-enumeration timing as a normal login
+		_ = bcrypt.CompareHashAndPassword(dummyBcryptHash, []byte(current))
+		return nil, nil, domain.ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(current)); err != nil {
+		return nil, nil, domain.ErrInvalidCredentials
+	}
+	if !user.IsActive {
+		return nil, nil, domain.ErrInvalidCredentials
+	}
+	// 🛡️ Only an expired password may be changed here; anything else signs
+	// in normally, through every gate
+	if err := s.AllowPassword(ctx, user); !errors.Is(err, domain.ErrPasswordExpired) {
+		return nil, nil, fmt.Errorf("%w: this password has not expired; sign in instead", domain.ErrForbidden)
+	}
+	// The rest of the login policy (passkey required) still applies
+	if err := s.tokens.AllowPasswordExcept(ctx, user, s); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.rotate(ctx, user, next); err != nil {
+		return nil, nil, err
+	}
+
+	access, refresh, err := s.tokens.GenerateTokenPair(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &domain.TokenPair{AccessToken: access, RefreshToken: refresh}, user, nil
+}
+
+func (s *PasswordService) rotate(ctx context.Context, user *domain.User, next string) error {
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(next)) == nil {
+		return fmt.Errorf("%w: the new password must differ from the current one", domain.ErrValidation)
+	}
+	if err := s.ValidatePassword(ctx, user.Email, next); err != nil {
+		return err
+	}
+
+	hash, err := s.HashPassword(next)
+	if err != nil {
+		return err
+	}
+	if err := s.users.UpdatePassword(ctx, user.ID, hash); err != nil {
+		return err
+	}
+
+	if err := s.auditRepo.CreateTenantLog(ctx, &domain.TenantLog{
+		TenantID:     user.ID,
+		ActorID:      &user.ID,
+		Action:       "password.change",
+		ResourceType: "user",
+		ResourceID:   user.ID.String(),
+	}); err != nil {
+		s.logger.Error("Failed to record password change audit log", slog.Any("error", err))
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+const (
+	// componentTimeout bounds each probe so one hung dependency cannot
+	// stall the whole report past an orchestrator's probe timeout.
+	componentTimeout = 2 * time.Second
+
+	// agentStaleAfter is how old the last successful Muscle heartbeat may
+	// be (the prober runs every 15s) before the link counts as down.
+	agentStaleAfter = 45 * time.Second
+
+	// workerGrace keeps slow loops (a long deploy, a big scan) from being
+	// reported stuck; a worker is stale after max(3 intervals, workerGrace).
+	workerGrace = 10 * time.Minute
+)
+
+type dbPinger interface {
+	Ping(ctx context.Context) error
+}
+
+// agentHealth is satisfied by workers.HealthProber.
+type agentHealth interface {
+	IsHealthy() bool
+	LastSuccess() time.Time
+}
+
+// heartbeatSource is satisfied by workers.Heartbeats.
+type heartbeatSource interface {
+	Snapshot() []domain.WorkerHeartbeat
+}
+
+// ReadinessService builds the per-component report behind /health/ready.
+type ReadinessService struct {
+	db          dbPinger
+	agent       agentHealth
+	schema      domain.SchemaChecker
+	heartbeats  heartbeatSource
+	maintenance domain.MaintenanceState
+}
+
+func NewReadinessService(
+	db dbPinger,
+	agent agentHealth,
+	schema domain.SchemaChecker,
+	heartbeats heartbeatSource,
+	maintenance domain.MaintenanceState,
+) *ReadinessService {
+	return &ReadinessService{
+		db:          db,
+		agent:       agent,
+		schema:      schema,
+		heartbeats:  heartbeats,
+		maintenance: maintenance,
+	}
+}
+
+// Ready runs every check. The aggregate is down if any critical component
+// is down, degraded if anything else is not ok.
+func (s *ReadinessService) Ready(ctx context.Context) domain.ReadinessReport {
+	report := domain.ReadinessReport{
+		Status:      domain.HealthOK,
+		Maintenance: s.maintenance.Maintenance().Enabled,
+		CheckedAt:   time.Now().UTC(),
+		Components: []domain.ComponentHealth{
+			s.timed(ctx, "database", true, s.checkDatabase),
+			s.timed(ctx, "migrations", true, s.checkSchema),
+			// A Muscle restart must not pull the API from the load balancer:
+			// reads, auth and queued work carry on without it
+			s.timed(ctx, "agent", false, s.checkAgent),
+			s.timed(ctx, "workers", false, s.checkWorkers),
+		},
+	}
+
+	for _, c := range report.Components {
+		switch {
+		case c.Status == domain.HealthOK:
+		case c.Critical && c.Status == domain.HealthDown:
+			report.Status = domain.HealthDown
+		case report.Status == domain.HealthOK:
+			report.Status = domain.HealthDegraded
+		}
+	}
+	return report
+}
+
+func (s *ReadinessService) timed(ctx context.Context, name string, critical bool, check func(context.Context) (string, string)) domain.ComponentHealth {
+	checkCtx, cancel := context.WithTimeout(ctx, componentTimeout)
+	defer cancel()
+
+	start := time.Now()
+	status, detail := check(checkCtx)
+	return domain.ComponentHealth{
+		Name:      name,
+		Status:    status,
+		Critical:  critical,
+		Detail:    detail,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+}
+
+// ==============================================================================
+// Component Checks
+// ==============================================================================
+
+func (s *ReadinessService) checkDatabase(ctx context.Context) (string, string) {
+	if err := s.db.Ping(ctx); err != nil {
+		return domain.HealthDown, "postgres unreachable"
+	}
+	return domain.HealthOK, ""
+}
+
+func (s *ReadinessService) checkSchema(ctx context.Context) (string, string) {
+	missing, err := s.schema.MissingRelations(ctx)
+	if err != nil {
+		return domain.HealthDown, "schema could not be inspected"
+	}
+	if len(missing) > 0 {
+		return domain.HealthDown, "migrations not applied: missing " + strings.Join(missing, ", ")
+	}
+	return domain.HealthOK, ""
+}
+
+// checkAgent reads the prober's cache instead of dialing the Muscle, so
+// readiness probes add no load on the gRPC link. An unreachable Muscle
+// degrades the Brain; it never takes it down.
+func (s *ReadinessService) checkAgent(ctx context.Context) (string, string) {
+	last := s.agent.LastSuccess()
+	switch {
+	case last.IsZero():
+		return domain.HealthDegraded, "no heartbeat from the Muscle yet"
+	case !s.agent.IsHealthy():
+		return domain.HealthDegraded, fmt.Sprintf("last heartbeat failed; last success %s ago", time.Since(last).Round(time.Second))
+	case time.Since(last) > agentStaleAfter:
+		return domain.HealthDegraded, fmt.Sprintf("heartbeat stale for %s", time.Since(last).Round(time.Second))
+	}
+	return domain.HealthOK, ""
+}
+
+func (s *ReadinessService) checkWorkers(ctx context.Context) (string, string) {
+	now := time.Now()
+	var stale []string
+	for _, hb := range s.heartbeats.Snapshot() {
+		last := hb.LastBeat
+		if last.IsZero() {
+			last = hb.RegisteredAt
+		}
+		if now.Sub(last) > max(3*hb.Interval, workerGrace) {
+			stale = append(stale, hb.Name)
+		}
+	}
+	if len(stale) > 0 {
+		return domain.HealthDegraded, "stale: " + strings.Join(stale, ", ")
+	}
+	return domain.HealthOK, ""
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+const (
+	// redactionCacheTTL bounds how long another Brain instance's rule edits
+	// take to apply here; edits made through this instance apply at once.
+	redactionCacheTTL = time.Minute
+
+	maxRedactionPatternLen = 500
+)
+
+type redactionPattern struct {
+	re          *regexp.Regexp
+	replacement string // May reference capture groups (${1})
+}
+
+// builtinRedactions run for every tenant before any custom rule. Order
+// matters: whole blocks and URL credentials go before the generic
+// key=value and email patterns that would otherwise split them.
+var builtinRedactions = []redactionPattern{
+	{regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`), "[REDACTED PRIVATE KEY]"},
+	{regexp.MustCompile(`(://[^/\s:@]+:)[^@\s/]+@`), "${1}[REDACTED]@"},
+	{regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`), "${1}[REDACTED]"},
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]+`), "[REDACTED JWT]"},
+	{regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|glpat-[A-Za-z0-9_-]{20,}|xox[abposr]-[A-Za-z0-9-]{10,})\b`), "[REDACTED TOKEN]"},
+	{regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`), "[REDACTED AWS KEY]"},
+	{regexp.MustCompile(`(?i)((?:password|passwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key|client[_-]?secret)["']?\s*[=:]\s*)("[^"]*"|'[^']*'|[^\s"',;&]+)`), "${1}[REDACTED]"},
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[EMAIL]"},
+}
+
+// ipRedactions are opt-in: IPs are PII in some jurisdictions but often
+// exactly what an operator needs in a deploy log.
+var ipRedactions = []redactionPattern{
+	{regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`), "[IP]"},
+	{regexp.MustCompile(`\b(?:[0-9a-fA-F]{1,4}:){7}[0-9a-fA-F]{1,4}\b|\b(?:[0-9a-fA-F]{1,4}:){1,6}:[0-9a-fA-F]{1,4}\b`), "[IP]"},
+}
+
+// sensitiveMetadataKeys have their whole value replaced in audit metadata,
+// whatever it looks like. Matches the key or a "_key" suffix (db_password).
+var sensitiveMetadataKeys = []string{"password", "secret", "token", "api_key", "apikey", "private_key", "authorization", "cookie", "ssh_key"}
+
+// appOwnerLookup is satisfied by domain.ApplicationRepository.
+type appOwnerLookup interface {
+	GetByIDWithMetadata(ctx context.Context, id uuid.UUID) (*domain.ApplicationMetadata, error)
+}
+
+type cachedRedactions struct {
+	patterns []redactionPattern
+	loadedAt time.Time
+}
+
+// RedactionService scrubs secrets and PII from deployment logs and audit
+// metadata: built-in patterns, then platform-wide rules, then the tenant's own.
+type RedactionService struct {
+	repo     domain.RedactionRuleRepository
+	apps     appOwnerLookup
+	builtins []redactionPattern
+	logger   *slog.Logger
+
+	mu     sync.RWMutex
+	cache  map[uuid.UUID]cachedRedactions // uuid.Nil = platform-wide only
+	owners sync.Map                       // app ID string -> owner uuid.UUID
+}
+
+func NewRedactionService(repo domain.RedactionRuleRepository, apps appOwnerLookup, redactIPs bool, logger *slog.Logger) *RedactionService {
+	builtins := builtinRedactions
+	if redactIPs {
+		builtins = append(append([]redactionPattern(nil), builtinRedactions...), ipRedactions...)
+	}
+	return &RedactionService{
+		repo:     repo,
+		apps:     apps,
+		builtins: builtins,
+		logger:   logger,
+		cache:    map[uuid.UUID]cachedRedactions{},
+	}
+}
+
+// ==============================================================================
+// 1. Scrubbing
+// ==============================================================================
+
+func (s *RedactionService) RedactText(ctx context.Context, tenantID uuid.UUID, text string) string {
+	for _, p := range s.patternsFor(ctx, tenantID) {
+		text = p.re.ReplaceAllString(text, p.replacement)
+	}
+	return text
+}
+
+// RedactMetadata returns a scrubbed copy; the caller's map is not modified.
+func (s *RedactionService) RedactMetadata(ctx context.Context, tenantID uuid.UUID, metadata map[string]any) map[string]any {
+	if metadata == nil {
+		return nil
+	}
+	patterns := s.patternsFor(ctx, tenantID)
+	return redactValue(metadata, patterns).(map[string]any)
+}
+
+// RedactAppLog scrubs a deployment log chunk with the rules of the app's owner.
+// 🛡️ Zero-Trust: If the owner cannot be resolved the platform-wide rules
+// still apply; a lookup failure never lets a chunk through unscrubbed.
+func (s *RedactionService) RedactAppLog(ctx context.Context, appID string, text string) string {
+	return s.RedactText(ctx, s.ownerOf(ctx, appID), text)
+}
+
+func (s *RedactionService) ownerOf(ctx context.Context, appID string) uuid.UUID {
+	if owner, ok := s.owners.Load(appID); ok {
+		return owner.(uuid.UUID)
+	}
+	id, err := uuid.Parse(appID)
+	if err != nil {
+		return uuid.Nil
+	}
+	meta, err := s.apps.GetByIDWithMetadata(ctx, id)
+	if err != nil {
+		s.logger.Warn("Redaction: app owner lookup failed; using platform rules", slog.String("app_id", appID), slog.Any("error", err))
+		return uuid.Nil
+	}
+	s.owners.Store(appID, meta.OwnerID)
+	return meta.OwnerID
+}
+
+func redactValue(v any, patterns []redactionPattern) any {
+	switch val := v.(type) {
+	case string:
+		for _, p := range patterns {
+			val = p.re.ReplaceAllString(val, p.replacement)
+		}
+		return val
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, inner := range val {
+			if isSensitiveKey(k) {
+				out[k] = domain.DefaultRedactionReplacement
+				continue
+			}
+			out[k] = redactValue(inner, patterns)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, inner := range val {
+			out[i] = redactValue(inner, patterns)
+		}
+		return out
+	case []string:
+		out := make([]string, len(val))
+		for i, inner := range val {
+			out[i] = redactValue(inner, patterns).(string)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	k := strings.ToLower(key)
+	for _, s := range sensitiveMetadataKeys {
+		if k == s || strings.HasSuffix(k, "_"+s) {
+			return true
+		}
+	}
+	return false
+}
+
+// patternsFor returns builtins + platform rules + the tenant's rules,
+// compiled once per redactionCacheTTL.
+func (s *RedactionService) patternsFor(ctx context.Context, tenantID uuid.UUID) []redactionPattern {
+	s.mu.RLock()
+	cached, ok := s.cache[tenantID]
+	s.mu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < redactionCacheTTL {
+		return cached.patterns
+	}
+
+	patterns := append([]redactionPattern(nil), s.builtins...)
+	patterns = append(patterns, s.loadRules(ctx, nil)...)
+	if tenantID != uuid.Nil {
+		patterns = append(patterns, s.loadRules(ctx, &tenantID)...)
+	}
+
+	s.mu.Lock()
+	s.cache[tenantID] = cachedRedactions{patterns: patterns, loadedAt: time.Now()}
+	s.mu.Unlock()
+	return patterns
+}
+
+func (s *RedactionService) loadRules(ctx context.Context, tenantID *uuid.UUID) []redactionPattern {
+	rules, err := s.repo.List(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("Redaction: failed to load custom rules; built-ins still apply", slog.Any("error", err))
+		return nil
+	}
+	patterns := make([]redactionPattern, 0, len(rules))
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			// Validated on create; only reachable if the row was edited by hand
+			s.logger.Warn("Redaction: skipping invalid rule", slog.String("rule_id", rule.ID.String()), slog.Any("error", err))
+			continue
+		}
+		patterns = append(patterns, redactionPattern{re: re, replacement: rule.Replacement})
+	}
+	return patterns
+}
+
+func (s *RedactionService) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = map[uuid.UUID]cachedRedactions{}
+}
+
+// ==============================================================================
+// 2. Rule Administration
+// ==============================================================================
+
+func (s *RedactionService) ListRules(ctx context.Context, tenantID *uuid.UUID) ([]domain.RedactionRule, error) {
+	return s.repo.List(ctx, tenantID)
+}
+
+func (s *RedactionService) CreateRule(ctx context.Context, actorID uuid.UUID, tenantID *uuid.UUID, name, pattern, replacement string) (*domain.RedactionRule, error) {
+	if len(pattern) > maxRedactionPatternLen {
+		return nil, fmt.Errorf("%w: pattern exceeds %d characters", domain.ErrValidation, maxRedactionPatternLen)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid pattern: %v", domain.ErrValidation, err)
+	}
+	if re.MatchString("") {
+		// Would splice the replacement between every character
+		return nil, fmt.Errorf("%w: pattern must not match the empty string", domain.ErrValidation)
+	}
+	if replacement == "" {
+		replacement = domain.DefaultRedactionReplacement
+	}
+
+	rule := &domain.RedactionRule{
+		TenantID:    tenantID,
+		Name:        strings.TrimSpace(name),
+		Pattern:     pattern,
+		Replacement: replacement,
+		CreatedBy:   &actorID,
+	}
+	if err := s.repo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return rule, nil
+}
+
+func (s *RedactionService) DeleteRule(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) error {
+	if err := s.repo.Delete(ctx, id, tenantID); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// ==============================================================================
+// 3. Audit Decorator
+// ==============================================================================
+
+// RedactingAuditRepository scrubs audit metadata and alert text before the
+// wrapped repository persists (and hash-chains) them.
+type RedactingAuditRepository struct {
+	domain.AuditRepository
+	redactor domain.LogRedactor
+}
+
+func NewRedactingAuditRepository(inner domain.AuditRepository, redactor domain.LogRedactor) *RedactingAuditRepository {
+	return &RedactingAuditRepository{AuditRepository: inner, redactor: redactor}
+}
+
+func (r *RedactingAuditRepository) CreateTenantLog(ctx context.Context, entry *domain.TenantLog) error {
+	entry.Metadata = r.redactor.RedactMetadata(ctx, entry.TenantID, entry.Metadata)
+	return r.AuditRepository.CreateTenantLog(ctx, entry)
+}
+
+// CreateAlert applies the platform-wide rules; alerts are admin-facing.
+func (r *RedactingAuditRepository) CreateAlert(ctx context.Context, alert *domain.SystemAlert) error {
+	alert.Message = r.redactor.RedactText(ctx, uuid.Nil, alert.Message)
+	alert.Metadata = r.redactor.RedactMetadata(ctx, uuid.Nil, alert.Metadata)
+	return r.AuditRepository.CreateAlert(ctx, alert)
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/grpc/rustagent"
+)
+
+// RegistryService manages container registry credentials and injects them
+// into the Muscle's image pulls for image-based apps.
+type RegistryService struct {
+	creds       domain.RegistryCredentialRepository
+	apps        domain.ApplicationRepository
+	verifier    domain.RegistryVerifier
+	crypto      domain.CryptoService
+	agentClient rustagent.SystemAgentClient
+	agentCaps   domain.AgentCapabilities
+	servers     domain.ServerRepository // nil: only the Brain's own host
+	logger      *slog.Logger
+}
+
+func NewRegistryService(
+	creds domain.RegistryCredentialRepository,
+	apps domain.ApplicationRepository,
+	verifier domain.RegistryVerifier,
+	crypto domain.CryptoService,
+	agent rustagent.SystemAgentClient,
+	agentCaps domain.AgentCapabilities,
+	logger *slog.Logger,
+) *RegistryService {
+	return &RegistryService{
+		creds:       creds,
+		apps:        apps,
+		verifier:    verifier,
+		crypto:      crypto,
+		agentClient: agent,
+		agentCaps:   agentCaps,
+		logger:      logger,
+	}
+}
+
+// WithServers gates image apps on remote servers by their discovered
+// container runtime.
+func (s *RegistryService) WithServers(servers domain.ServerRepository) *RegistryService {
+	s.servers = servers
+	return s
+}
+
+// ==============================================================================
+// 1. Credential Lifecycle
+// ==============================================================================
+
+func (s *RegistryService) CreateCredential(ctx context.Context, ownerID uuid.UUID, cred *domain.RegistryCredential, password string) (*domain.RegistryCredential, error) {
+	if s.crypto == nil {
+		return nil, fmt.Errorf("%w: registry credentials require the crypto service", domain.ErrUnavailable)
+	}
+	cred.Registry = domain.NormalizeRegistryHost(cred.Registry)
+	if err := domain.ValidateRegistryHost(cred.Registry); err != nil {
+		return nil, err
+	}
+
+	cred.ID = uuid.New()
+	cred.OwnerID = ownerID
+	ciphertext, err := s.crypto.Encrypt(ctx, []byte(password), credentialAAD(cred.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt registry credential: %w", err)
+	}
+	cred.PasswordCiphertext = ciphertext
+
+	if err := s.creds.Create(ctx, cred); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Registry credential created",
+		slog.String("credential_id", cred.ID.String()),
+		slog.String("registry", cred.Registry))
+	return cred, nil
+}
+
+func (s *RegistryService) ListCredentials(ctx context.Context, ownerID uuid.UUID) ([]domain.RegistryCredential, error) {
+	return s.creds.ListByOwner(ctx, ownerID)
+}
+
+func (s *RegistryService) DeleteCredential(ctx context.Context, id, ownerID uuid.UUID) error {
+	return s.creds.Delete(ctx, id, ownerID)
+}
+
+// TestCredential logs in to the registry. A rejected credential is a result,
+// not an error: it is recorded and returned with LastTestOK=false.
+func (s *RegistryService) TestCredential(ctx context.Context, id, ownerID uuid.UUID) (*domain.RegistryCredential, error) {
+	cred, err := s.creds.GetByID(ctx, id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	password, err := s.decrypt(ctx, cred)
+	if err != nil {
+		return nil, err
+	}
+
+	verr := s.verifier.Verify(ctx, cred.Registry, cred.Username, password)
+	if verr != nil && !errors.Is(verr, domain.ErrInvalidCredentials) {
+		return nil, verr // Registry unreachable: leave the last result untouched
+	}
+
+	ok := verr == nil
+	if err := s.creds.RecordTest(ctx, cred.ID, ok); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	cred.LastTestedAt, cred.LastTestOK = &now, &ok
+	return cred, nil
+}
+
+// ==============================================================================
+// 2. Image-Based Apps
+// ==============================================================================
+
+func (s *RegistryService) AttachImage(ctx context.Context, appID, ownerID uuid.UUID, image string, credentialID *uuid.UUID) (*domain.Application, error) {
+	app, err := s.apps.GetByID(ctx, appID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if app.AppType != "image" {
+		return nil, fmt.Errorf("%w: only image-based apps can reference a container image", domain.ErrValidation)
+	}
+	if err := domain.ValidateImageRef(image); err != nil {
+		return nil, err
+	}
+	if err := s.checkContainerHost(ctx, app); err != nil {
+		return nil, err
+	}
+
+	if credentialID != nil {
+		// 🛡️ Tenant Isolation: Only the caller's own credentials can be attached
+		cred, err := s.creds.GetByID(ctx, *credentialID, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkCredentialHost(cred, image); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.apps.SetImage(ctx, app.ID, image, credentialID); err != nil {
+		return nil, err
+	}
+	app.ImageRef, app.RegistryCredID = image, credentialID
+	return app, nil
+}
+
+// PullImage decrypts the app's credential just long enough to hand it to the Muscle.
+func (s *RegistryService) PullImage(ctx context.Context, appID, ownerID uuid.UUID) error {
+	app, err := s.apps.GetByID(ctx, appID, ownerID)
+	if err != nil {
+		return err
+	}
+	if app.AppType != "image" || app.ImageRef == "" {
+		return fmt.Errorf("%w: application has no container image", domain.ErrValidation)
+	}
+	if !s.agentCaps.Supports(domain.AgentFeatureImagePull) {
+		return fmt.Errorf("%w: the Muscle agent is too old to pull container images", domain.ErrUnavailable)
+	}
+	if err := s.checkContainerHost(ctx, app); err != nil {
+		return err
+	}
+
+	req := &rustagent.ImagePullRequest{
+		TraceId: fmt.Sprintf("pull-%s-%d", app.ID.String()[:8], time.Now().UnixMilli()),
+		AppId:   app.ID.String(),
+		Image:   app.ImageRef,
+	}
+	if app.RegistryCredID != nil {
+		cred, err := s.creds.GetByID(ctx, *app.RegistryCredID, ownerID)
+		if err != nil {
+			return err
+		}
+		// Re-checked at pull time: the image may have been retargeted since attaching
+		if err := checkCredentialHost(cred, app.ImageRef); err != nil {
+			return err
+		}
+		password, err := s.decrypt(ctx, cred)
+		if err != nil {
+			return err
+		}
+		req.Auth = &rustagent.RegistryAuth{Server: cred.Registry, Username: cred.Username, Password: password}
+	}
+
+	resp, err := s.agentClient.PullImage(ctx, req)
+	if err != nil {
+		return fmt.Errorf("%w: image pull failed: %v", domain.ErrUnavailable, err)
+	}
+	if !resp.Success {
+		return fmt.Errorf("image pull failed: %s", resp.ErrorMessage)
+	}
+
+	s.logger.Info("Container image pulled",
+		slog.String("app_id", app.ID.String()),
+		slog.String("image", app.ImageRef),
+		slog.Bool("authenticated", req.Auth != nil))
+	return nil
+}
+
+// ==============================================================================
+// 3. Helpers
+// ==============================================================================
+
+func (s *RegistryService) decrypt(ctx context.Context, cred *domain.RegistryCredential) (string, error) {
+	if s.crypto == nil {
+		return "", fmt.Errorf("%w: crypto service unavailable", domain.ErrUnavailable)
+	}
+	plaintext, err := s.crypto.Decrypt(ctx, cred.PasswordCiphertext, credentialAAD(cred.ID))
+	if err != nil {
+		return "", fmt.Errorf("security: failed to decrypt registry credential: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// checkContainerHost refuses image work for an app whose server has no
+// container runtime. A server that has not been probed yet counts as not
+// having one.
+func (s *RegistryService) checkContainerHost(ctx context.Context, app *domain.Application) error {
+	if app.ServerID == nil || s.servers == nil {
+		return nil
+	}
+	server, err := s.servers.GetServer(ctx, *app.ServerID)
+	if err != nil {
+		return err
+	}
+	if !server.Supports(domain.CapabilityContainers) {
+		return fmt.Errorf("%w: server %s has no container runtime", domain.ErrValidation, server.Name)
+	}
+	return nil
+}
+
+// checkCredentialHost stops a credential from being sent to a registry it was not issued for.
+func checkCredentialHost(cred *domain.RegistryCredential, image string) error {
+	host := domain.NormalizeRegistryHost(domain.ImageRegistryHost(image))
+	if !strings.EqualFold(host, cred.Registry) {
+		return fmt.Errorf("%w: credential %q is for %s, but the image is hosted on %s",
+			domain.ErrValidation, cred.Name, cred.Registry, host)
+	}
+	return nil
+}
+
+// credentialAAD binds the ciphertext to its row so it cannot be swapped onto another credential.
+func credentialAAD(id uuid.UUID) []byte {
+	return []byte("registry_credential:" + id.String())
+}
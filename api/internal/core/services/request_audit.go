@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"maps"
+
+	"kari/api/internal/core/domain"
+)
+
+// RequestAuditRepository stamps audit entries written during an API request
+// with that request's domain.RequestContext (caller IP, trace ID, actor and
+// role rank) under metadata["request"]. Services keep passing explicit actor
+// IDs; this only adds what they cannot see. Entries written by workers carry
+// no request and pass through unchanged.
+//
+// It must wrap the redacting repository, not sit inside it, so IP redaction
+// applies to the stamped address too.
+type RequestAuditRepository struct {
+	domain.AuditRepository
+}
+
+func NewRequestAuditRepository(inner domain.AuditRepository) *RequestAuditRepository {
+	return &RequestAuditRepository{AuditRepository: inner}
+}
+
+func (r *RequestAuditRepository) CreateTenantLog(ctx context.Context, entry *domain.TenantLog) error {
+	if rc, ok := domain.RequestContextFrom(ctx); ok {
+		if entry.ActorID == nil && rc.Authenticated() {
+			actor := rc.UserID
+			entry.ActorID = &actor
+		}
+		entry.Metadata = withRequest(entry.Metadata, rc)
+	}
+	return r.AuditRepository.CreateTenantLog(ctx, entry)
+}
+
+func (r *RequestAuditRepository) CreateAlert(ctx context.Context, alert *domain.SystemAlert) error {
+	if rc, ok := domain.RequestContextFrom(ctx); ok {
+		alert.Metadata = withRequest(alert.Metadata, rc)
+	}
+	return r.AuditRepository.CreateAlert(ctx, alert)
+}
+
+// withRequest copies metadata rather than mutating it; callers often reuse
+// one map across entries. A service that set "request" itself wins.
+func withRequest(metadata map[string]any, rc domain.RequestContext) map[string]any {
+	if _, taken := metadata["request"]; taken {
+		return metadata
+	}
+	out := maps.Clone(metadata)
+	if out == nil {
+		out = make(map[string]any, 1)
+	}
+	out["request"] = rc.AuditMetadata()
+	return out
+}
@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/services"
+)
+
+// capturingRepo records what reaches the persistence layer.
+type capturingRepo struct {
+	domain.AuditRepository
+	logs   []domain.TenantLog
+	alerts []domain.SystemAlert
+}
+
+func (r *capturingRepo) CreateTenantLog(_ context.Context, entry *domain.TenantLog) error {
+	r.logs = append(r.logs, *entry)
+	return nil
+}
+
+func (r *capturingRepo) CreateAlert(_ context.Context, alert *domain.SystemAlert) error {
+	r.alerts = append(r.alerts, *alert)
+	return nil
+}
+
+func TestRequestAuditStampsEntries(t *testing.T) {
+	inner := &capturingRepo{}
+	repo := services.NewRequestAuditRepository(inner)
+
+	user := uuid.New()
+	ctx := domain.WithRequestContext(context.Background(), domain.RequestContext{IP: "203.0.113.7", TraceID: "host/abc-000001"})
+	ctx = domain.WithActor(ctx, user, 2)
+
+	shared := map[string]any{"instances": 3}
+	if err := repo.CreateTenantLog(ctx, &domain.TenantLog{Action: "application.scale", Metadata: shared}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := inner.logs[0]
+	if got.ActorID == nil || *got.ActorID != user {
+		t.Fatalf("actor = %v, want %s from the request", got.ActorID, user)
+	}
+	req, ok := got.Metadata["request"].(map[string]any)
+	if !ok {
+		t.Fatalf("metadata has no request object: %v", got.Metadata)
+	}
+	want := map[string]any{"ip": "203.0.113.7", "trace_id": "host/abc-000001", "actor_id": user.String(), "role_rank": 2}
+	for k, v := range want {
+		if req[k] != v {
+			t.Errorf("request[%q] = %v, want %v", k, req[k], v)
+		}
+	}
+	if got.Metadata["instances"] != 3 {
+		t.Errorf("service metadata lost: %v", got.Metadata)
+	}
+	if _, leaked := shared["request"]; leaked {
+		t.Error("caller's metadata map was mutated")
+	}
+}
+
+func TestRequestAuditKeepsExplicitActor(t *testing.T) {
+	inner := &capturingRepo{}
+	repo := services.NewRequestAuditRepository(inner)
+
+	explicit := uuid.New()
+	ctx := domain.WithActor(context.Background(), uuid.New(), 0)
+	if err := repo.CreateTenantLog(ctx, &domain.TenantLog{ActorID: &explicit}); err != nil {
+		t.Fatal(err)
+	}
+	if *inner.logs[0].ActorID != explicit {
+		t.Fatalf("actor overwritten with %s", *inner.logs[0].ActorID)
+	}
+}
+
+func TestRequestAuditOutsideRequestPassesThrough(t *testing.T) {
+	inner := &capturingRepo{}
+	repo := services.NewRequestAuditRepository(inner)
+
+	if err := repo.CreateAlert(context.Background(), &domain.SystemAlert{Category: "ssl"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateTenantLog(context.Background(), &domain.TenantLog{}); err != nil {
+		t.Fatal(err)
+	}
+	if inner.alerts[0].Metadata != nil || inner.logs[0].Metadata != nil || inner.logs[0].ActorID != nil {
+		t.Fatalf("worker entries were stamped: %+v %+v", inner.alerts[0], inner.logs[0])
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// ResourceQuotaService resolves a user's effective limits (role quota with
+// any admin override applied) and checks creations against them.
+type ResourceQuotaService struct {
+	repo      domain.ResourceQuotaRepository
+	auditRepo domain.AuditRepository
+	logger    *slog.Logger
+}
+
+func NewResourceQuotaService(repo domain.ResourceQuotaRepository, audit domain.AuditRepository, logger *slog.Logger) *ResourceQuotaService {
+	return &ResourceQuotaService{
+		repo:      repo,
+		auditRepo: audit,
+		logger:    logger,
+	}
+}
+
+// ==============================================================================
+// 1. Enforcement
+// ==============================================================================
+
+// CheckQuota fails with ErrQuotaExceeded if delta would take the user past
+// any effective limit. Only growing dimensions are checked, so shrinking an
+// app never fails because the user is already over a lowered quota.
+func (s *ResourceQuotaService) CheckQuota(ctx context.Context, userID uuid.UUID, delta domain.ResourceUsage) error {
+	_, limits, override, err := s.repo.UserLimits(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if override != nil {
+		if override.Exempt {
+			return nil
+		}
+		limits = limits.Merge(override.ResourceLimits)
+	}
+	if limits.MaxApplications == nil && limits.MaxDomains == nil && limits.MaxMemoryMB == nil {
+		return nil
+	}
+
+	usage, err := s.repo.Usage(ctx, userID)
+	if err != nil {
+		return err
+	}
+	checks := []struct {
+		name        string
+		limit       *int
+		used, delta int
+	}{
+		{"applications", limits.MaxApplications, usage.Applications, delta.Applications},
+		{"domains", limits.MaxDomains, usage.Domains, delta.Domains},
+		{"memory_mb", limits.MaxMemoryMB, usage.MemoryMB, delta.MemoryMB},
+	}
+	for _, c := range checks {
+		if c.limit != nil && c.delta > 0 && c.used+c.delta > *c.limit {
+			return fmt.Errorf("%w: %s would reach %d of %d", domain.ErrQuotaExceeded, c.name, c.used+c.delta, *c.limit)
+		}
+	}
+	return nil
+}
+
+// ==============================================================================
+// 2. Reporting & Administration
+// ==============================================================================
+
+func (s *ResourceQuotaService) GetReport(ctx context.Context, userID uuid.UUID) (*domain.QuotaReport, error) {
+	roleName, limits, override, err := s.repo.UserLimits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.repo.Usage(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.QuotaReport{UserID: userID, RoleName: roleName, Limits: limits, Usage: usage, Override: override}
+	if override != nil {
+		if override.Exempt {
+			report.Limits = domain.ResourceLimits{}
+		} else {
+			report.Limits = limits.Merge(override.ResourceLimits)
+		}
+	}
+	return report, nil
+}
+
+func (s *ResourceQuotaService) ListRoleLimits(ctx context.Context) ([]domain.RoleLimits, error) {
+	return s.repo.ListRoleLimits(ctx)
+}
+
+// SetRoleLimits replaces a role's quota. Existing resources above a lowered
+// limit stay; the user just cannot create more until under it again.
+func (s *ResourceQuotaService) SetRoleLimits(ctx context.Context, roleID uuid.UUID, l domain.ResourceLimits) error {
+	if err := s.repo.UpsertRoleLimits(ctx, roleID, l); err != nil {
+		return err
+	}
+	s.logger.Info("Role quota updated", slog.String("role_id", roleID.String()))
+	return nil
+}
+
+func (s *ResourceQuotaService) SetOverride(ctx context.Context, actorID, userID uuid.UUID, o domain.LimitOverride) (*domain.QuotaReport, error) {
+	o.SetBy = &actorID
+	if err := s.repo.UpsertOverride(ctx, userID, o); err != nil {
+		return nil, err
+	}
+	s.recordOverride(ctx, actorID, userID, "quota.override.set", map[string]any{
+		"max_applications": o.MaxApplications,
+		"max_domains":      o.MaxDomains,
+		"max_memory_mb":    o.MaxMemoryMB,
+		"exempt":           o.Exempt,
+		"reason":           o.Reason,
+	})
+	return s.GetReport(ctx, userID)
+}
+
+func (s *ResourceQuotaService) RemoveOverride(ctx context.Context, actorID, userID uuid.UUID) error {
+	if err := s.repo.DeleteOverride(ctx, userID); err != nil {
+		return err
+	}
+	s.recordOverride(ctx, actorID, userID, "quota.override.remove", map[string]any{})
+	return nil
+}
+
+// recordOverride puts admin exceptions on the tenant's tamper-evident log.
+func (s *ResourceQuotaService) recordOverride(ctx context.Context, actorID, userID uuid.UUID, action string, metadata map[string]any) {
+	if err := s.auditRepo.CreateTenantLog(ctx, &domain.TenantLog{
+		TenantID:     userID,
+		ActorID:      &actorID,
+		Action:       action,
+		ResourceType: "user",
+		ResourceID:   userID.String(),
+		Metadata:     metadata,
+	}); err != nil {
+		s.logger.Error("Failed to audit quota override", slog.String("user_id", userID.String()), slog.Any("error", err))
+	}
+}
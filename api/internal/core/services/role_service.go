@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 
 	"github.com/google/uuid"
 	"kari/api/internal/core/domain"
@@ -13,6 +15,12 @@ import (
 type RoleService struct {
 	repo   domain.UserRepository
 	logger *slog.Logger
+
+	// Optional: privilege change records and notices (see WithChangeRecords)
+	auditRepo domain.AuditRepository
+	perms     domain.RolePermissionReader
+	mailer    domain.Mailer
+	webhooks  domain.WebhookEmitter
 }
 
 func NewRoleService(repo domain.UserRepository, logger *slog.Logger) *RoleService {
@@ -22,40 +30,260 @@ func NewRoleService(repo domain.UserRepository, logger *slog.Logger) *RoleServic
 	}
 }
 
+// WithChangeRecords audits every role change with the old and new role and
+// the permissions gained or lost, and tells the affected user by email (when
+// SMTP is configured) and the user.role_changed webhook. perms may be nil,
+// in which case only the roles are recorded.
+func (s *RoleService) WithChangeRecords(auditRepo domain.AuditRepository, perms domain.RolePermissionReader, mailer domain.Mailer, webhooks domain.WebhookEmitter) *RoleService {
+	s.auditRepo = auditRepo
+	s.perms = perms
+	s.mailer = mailer
+	s.webhooks = webhooks
+	return s
+}
+
 // AssignRole changes a user's role while enforcing Rank-based security boundaries.
 func (s *RoleService) AssignRole(ctx context.Context, actorID uuid.UUID, targetUserID uuid.UUID, newRoleID uuid.UUID) error {
-	// 1. Fetch the Actor (The person performing the change)
+	// 1. 🛡️ SLA Boundary: The actor must hold authority over the target
+	if err := s.CheckAuthority(ctx, actorID, targetUserID); err != nil {
+		return err
+	}
+
+	// 2. 🛡️ Privilege Escalation Prevention
+	targetRole, err := s.CheckAssignable(ctx, actorID, newRoleID)
+	if err != nil {
+		return err
+	}
+
+	// 🛡️ 3. Zero-Trust: "Last Admin" Protection
+	// If the target user is the last Rank 0 admin, prevent them from being demoted.
+	if targetRole.Rank > 0 {
+		if err := s.GuardLastAdmin(ctx, targetUserID); err != nil {
+			return err
+		}
+	}
+
+	// The role being replaced, for the audit record
+	target, err := s.repo.GetByID(ctx, targetUserID)
+	if err != nil {
+		return err
+	}
+	oldRole := target.Role
+	oldPerms := s.rolePermissions(ctx, oldRole.ID)
+
+	// 4. Execute Assignment
+	if err := s.repo.UpdateUserRole(ctx, targetUserID, newRoleID); err != nil {
+		return err
+	}
+	if oldRole.ID == newRoleID {
+		return nil
+	}
+
+	// 5. 📜 Compliance: Privilege changes are the highest-value audit events
+	s.recordPrivilegeChange(ctx, actorID, target, privilegeChange{
+		OldRole:  &oldRole,
+		NewRole:  targetRole,
+		OldPerms: oldPerms,
+		NewPerms: s.rolePermissions(ctx, newRoleID),
+	})
+	return nil
+}
+
+// CheckAssignable fails unless the actor may hand out roleID.
+// In Kari, lower numbers = higher power (0 is SuperUser).
+// An actor cannot assign a role with a rank superior to their own.
+func (s *RoleService) CheckAssignable(ctx context.Context, actorID uuid.UUID, roleID uuid.UUID) (*domain.Role, error) {
 	actor, err := s.repo.GetByID(ctx, actorID)
 	if err != nil {
-		return fmt.Errorf("failed to fetch actor: %w", err)
+		return nil, fmt.Errorf("failed to fetch actor: %w", err)
 	}
 
-	// 2. Fetch the Target Role metadata
-	targetRole, err := s.repo.GetRoleByID(ctx, newRoleID)
+	targetRole, err := s.repo.GetRoleByID(ctx, roleID)
 	if err != nil {
-		return fmt.Errorf("target role not found: %w", err)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("%w: target role not found", domain.ErrValidation)
+		}
+		return nil, err
 	}
 
-	// 🛡️ 3. SLA Boundary: Rank Check (Privilege Escalation Prevention)
-	// In Kari, lower numbers = higher power (0 is SuperUser).
-	// An actor cannot assign a role with a rank superior to their own.
 	if targetRole.Rank < actor.Role.Rank {
-		s.logger.Warn("Escalation attempt blocked", 
-			slog.String("actor", actor.Email), 
+		s.logger.Warn("Escalation attempt blocked",
+			slog.String("actor", actor.Email),
 			slog.String("attempted_rank", fmt.Sprintf("%d", targetRole.Rank)))
-		return errors.New("forbidden: cannot assign a role superior to your own rank")
+		return nil, fmt.Errorf("%w: cannot assign a role superior to your own rank", domain.ErrForbidden)
 	}
+	return targetRole, nil
+}
 
-	// 🛡️ 4. Zero-Trust: "Last Admin" Protection
-	// If the target user is the last Rank 0 admin, prevent them from being demoted.
-	targetUser, _ := s.repo.GetByID(ctx, targetUserID)
-	if targetUser.Role.Rank == 0 && targetRole.Rank > 0 {
-		count, _ := s.repo.CountAdmins(ctx)
-		if count <= 1 {
-			return errors.New("forbidden: cannot demote the last system administrator")
+// CheckAuthority fails unless the actor's rank is equal or superior to the
+// target user's, so operators cannot edit the admins above them.
+func (s *RoleService) CheckAuthority(ctx context.Context, actorID uuid.UUID, targetUserID uuid.UUID) error {
+	actor, err := s.repo.GetByID(ctx, actorID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch actor: %w", err)
+	}
+	target, err := s.repo.GetByID(ctx, targetUserID)
+	if err != nil {
+		return err
+	}
+
+	if target.Role.Rank < actor.Role.Rank {
+		s.logger.Warn("Cross-rank user edit blocked",
+			slog.String("actor", actor.Email),
+			slog.String("target_id", targetUserID.String()))
+		return fmt.Errorf("%w: cannot manage a user with a rank superior to your own", domain.ErrForbidden)
+	}
+	return nil
+}
+
+// GuardLastAdmin fails if taking targetUserID's Rank 0 powers away (demotion
+// or deactivation) would leave the system without an active administrator.
+func (s *RoleService) GuardLastAdmin(ctx context.Context, targetUserID uuid.UUID) error {
+	target, err := s.repo.GetByID(ctx, targetUserID)
+	if err != nil {
+		return err
+	}
+	if target.Role.Rank != 0 || !target.IsActive {
+		return nil
+	}
+
+	count, err := s.repo.CountAdmins(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count administrators: %w", err)
+	}
+	if count <= 1 {
+		return fmt.Errorf("%w: cannot remove the last system administrator", domain.ErrForbidden)
+	}
+	return nil
+}
+
+// ==============================================================================
+// Privilege Change Records
+// ==============================================================================
+
+// privilegeChange is one user's before/after view of a role assignment or of
+// an edit to their role's permission set.
+type privilegeChange struct {
+	OldRole, NewRole   *domain.Role
+	OldPerms, NewPerms []string // nil when the permission set could not be read
+}
+
+// rolePermissions is best-effort: a failed lookup costs the record its
+// permission diff, never the role change itself.
+func (s *RoleService) rolePermissions(ctx context.Context, roleID uuid.UUID) []string {
+	if s.perms == nil || s.auditRepo == nil {
+		return nil
+	}
+	perms, err := s.perms.ListRolePermissions(ctx, roleID)
+	if err != nil {
+		s.logger.Warn("Failed to list role permissions for audit",
+			slog.String("role_id", roleID.String()), slog.Any("error", err))
+		return nil
+	}
+	return perms
+}
+
+func (s *RoleService) recordPrivilegeChange(ctx context.Context, actorID uuid.UUID, user *domain.User, c privilegeChange) {
+	if s.auditRepo == nil {
+		return
+	}
+	// The change is committed; a dropped client must not drop its record
+	ctx = context.WithoutCancel(ctx)
+
+	metadata := map[string]any{
+		"old_role":   roleMetadata(c.OldRole),
+		"new_role":   roleMetadata(c.NewRole),
+		"escalation": c.NewRole.Rank < c.OldRole.Rank,
+	}
+	added, removed := diffPermissions(c.OldPerms, c.NewPerms)
+	if c.OldPerms != nil && c.NewPerms != nil {
+		metadata["old_permissions"] = c.OldPerms
+		metadata["new_permissions"] = c.NewPerms
+		metadata["permissions_added"] = added
+		metadata["permissions_removed"] = removed
+	}
+
+	if err := s.auditRepo.CreateTenantLog(ctx, &domain.TenantLog{
+		TenantID:     user.ID,
+		ActorID:      &actorID,
+		Action:       "user.role_change",
+		ResourceType: "user",
+		ResourceID:   user.ID.String(),
+		Metadata:     metadata,
+	}); err != nil {
+		s.logger.Error("Failed to record privilege change audit log",
+			slog.String("user_id", user.ID.String()), slog.Any("error", err))
+	}
+
+	// 🛡️ A new Super Admin is worth an Action Center entry, not just a log line
+	if c.NewRole.Rank == 0 && c.OldRole.Rank != 0 {
+		resourceID := user.ID.String()
+		if err := s.auditRepo.CreateAlert(ctx, &domain.SystemAlert{
+			Severity:   "warning",
+			Category:   "security",
+			ResourceID: &resourceID,
+			Message:    fmt.Sprintf("%s was granted the %s role (Rank 0)", user.Email, c.NewRole.Name),
+			Metadata:   map[string]any{"actor_id": actorID.String(), "old_role": roleMetadata(c.OldRole)},
+		}); err != nil {
+			s.logger.Error("Failed to raise privilege escalation alert", slog.Any("error", err))
 		}
 	}
 
-	// 5. Execute Assignment
-	return s.repo.UpdateUserRole(ctx, targetUserID, newRoleID)
+	s.notifyPrivilegeChange(ctx, user, c, added, removed)
+}
+
+// notifyPrivilegeChange tells the affected user, so a change they did not
+// expect is noticed by the one person certain to care.
+func (s *RoleService) notifyPrivilegeChange(ctx context.Context, user *domain.User, c privilegeChange, added, removed []string) {
+	if s.webhooks != nil {
+		s.webhooks.Emit(ctx, user.ID, domain.WebhookRoleChanged, map[string]any{
+			"user_id":             user.ID,
+			"old_role":            roleMetadata(c.OldRole),
+			"new_role":            roleMetadata(c.NewRole),
+			"permissions_added":   added,
+			"permissions_removed": removed,
+		})
+	}
+	if s.mailer == nil || user.Email == "" {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Your Kari role changed from %s to %s.\n", c.OldRole.Name, c.NewRole.Name)
+	if len(added) > 0 {
+		fmt.Fprintf(&b, "\nPermissions granted:\n  %s\n", strings.Join(added, "\n  "))
+	}
+	if len(removed) > 0 {
+		fmt.Fprintf(&b, "\nPermissions revoked:\n  %s\n", strings.Join(removed, "\n  "))
+	}
+	b.WriteString("\nIf you did not expect this change, contact your administrator.\n")
+
+	if err := s.mailer.Send(ctx, domain.EmailMessage{
+		To:      user.Email,
+		Subject: "Your Kari role has changed",
+		Text:    b.String(),
+	}); err != nil {
+		s.logger.Warn("Failed to send role change notice",
+			slog.String("user_id", user.ID.String()), slog.Any("error", err))
+	}
+}
+
+func roleMetadata(r *domain.Role) map[string]any {
+	return map[string]any{"id": r.ID.String(), "name": r.Name, "rank": r.Rank}
+}
+
+// diffPermissions returns the grants only in after, then those only in before.
+func diffPermissions(before, after []string) (added, removed []string) {
+	added, removed = []string{}, []string{}
+	for _, p := range after {
+		if !slices.Contains(before, p) {
+			added = append(added, p)
+		}
+	}
+	for _, p := range before {
+		if !slices.Contains(after, p) {
+			removed = append(removed, p)
+		}
+	}
+	return added, removed
 }
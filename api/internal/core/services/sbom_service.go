@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// SBOMService serves the dependency catalogs the deployment worker records
+// and periodically matches them against a vulnerability feed (OSV). New
+// critical findings in what an app currently runs raise Action Center alerts.
+type SBOMService struct {
+	sboms     domain.SBOMRepository
+	vulns     domain.VulnerabilityRepository
+	feed      domain.VulnerabilityFeed
+	apps      domain.ApplicationRepository
+	auditRepo domain.AuditRepository
+	agentCaps domain.AgentCapabilities
+	licenses  domain.LicensePolicy
+	logger    *slog.Logger
+}
+
+func NewSBOMService(
+	sboms domain.SBOMRepository,
+	vulns domain.VulnerabilityRepository,
+	feed domain.VulnerabilityFeed,
+	apps domain.ApplicationRepository,
+	audit domain.AuditRepository,
+	agentCaps domain.AgentCapabilities,
+	logger *slog.Logger,
+) *SBOMService {
+	return &SBOMService{
+		sboms:     sboms,
+		vulns:     vulns,
+		feed:      feed,
+		apps:      apps,
+		auditRepo: audit,
+		agentCaps: agentCaps,
+		logger:    logger,
+	}
+}
+
+// WithLicensePolicy flags the given licenses in license reports.
+func (s *SBOMService) WithLicensePolicy(p domain.LicensePolicy) *SBOMService {
+	s.licenses = p
+	return s
+}
+
+// ==============================================================================
+// 1. Tenant-Facing Operations
+// ==============================================================================
+
+func (s *SBOMService) GetSBOM(ctx context.Context, appID, userID uuid.UUID) (*domain.SBOM, error) {
+	// 🛡️ Zero-Trust: Ownership check before reading the catalog
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return nil, err
+	}
+	sbom, err := s.sboms.Current(ctx, appID.String())
+	if errors.Is(err, domain.ErrNotFound) && !s.agentCaps.Supports(domain.AgentFeatureSBOM) {
+		return nil, fmt.Errorf("%w: the Muscle agent is too old to generate SBOMs", domain.ErrUnavailable)
+	}
+	return sbom, err
+}
+
+func (s *SBOMService) ListVulnerabilities(ctx context.Context, appID, userID uuid.UUID) ([]domain.VulnerabilityFinding, error) {
+	if _, err := s.apps.GetByID(ctx, appID, userID); err != nil {
+		return nil, err
+	}
+	return s.vulns.ListOpen(ctx, appID.String())
+}
+
+// LicenseReport summarizes the licenses in the app's current SBOM and flags
+// components the policy disallows. A component listing several licenses is
+// flagged if any of them is disallowed; choosing among them is up to a human.
+func (s *SBOMService) LicenseReport(ctx context.Context, appID, userID uuid.UUID) (*domain.LicenseReport, error) {
+	sbom, err := s.GetSBOM(ctx, appID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.LicenseReport{
+		AppID:        sbom.AppID,
+		DeploymentID: sbom.DeploymentID,
+		GeneratedAt:  sbom.CreatedAt,
+		Components:   len(sbom.Components),
+		Licenses:     []domain.LicenseUsage{},
+		Violations:   []domain.LicenseViolation{},
+		Disallowed:   append([]string{}, s.licenses.Disallowed...),
+		Truncated:    sbom.Truncated,
+	}
+	usage := make(map[string]*domain.LicenseUsage)
+	for _, c := range sbom.Components {
+		if len(c.Licenses) == 0 {
+			report.Unlicensed++
+			continue
+		}
+		var hits []string
+		for _, l := range c.Licenses {
+			u, ok := usage[l]
+			if !ok {
+				u = &domain.LicenseUsage{License: l, Disallowed: !s.licenses.Allows(l)}
+				usage[l] = u
+			}
+			u.Components++
+			if u.Disallowed {
+				hits = append(hits, s.licenses.Violations(l)...)
+			}
+		}
+		if len(hits) > 0 {
+			report.Violations = append(report.Violations, domain.LicenseViolation{
+				Name:       c.Name,
+				Version:    c.Version,
+				PURL:       c.PURL,
+				Licenses:   c.Licenses,
+				Disallowed: hits,
+			})
+		}
+	}
+
+	for _, u := range usage {
+		report.Licenses = append(report.Licenses, *u)
+	}
+	// Most used first; ties alphabetical so the report is stable
+	sort.Slice(report.Licenses, func(i, j int) bool {
+		a, b := report.Licenses[i], report.Licenses[j]
+		if a.Components != b.Components {
+			return a.Components > b.Components
+		}
+		return a.License < b.License
+	})
+	report.Compliant = len(report.Violations) == 0
+	return report, nil
+}
+
+// ==============================================================================
+// 2. The Scan (VulnScanner worker)
+// ==============================================================================
+
+// Scan matches every app's current SBOM against the feed in one batch query
+// and syncs the findings per app. A failed feed query records nothing: an
+// empty answer would otherwise resolve every open finding.
+func (s *SBOMService) Scan(ctx context.Context) error {
+	sboms, err := s.sboms.ListCurrent(ctx)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	var purls []string
+	for _, sbom := range sboms {
+		for _, c := range sbom.Components {
+			if c.PURL != "" && !seen[c.PURL] {
+				seen[c.PURL] = true
+				purls = append(purls, c.PURL)
+			}
+		}
+	}
+	affected, err := s.feed.Query(ctx, purls)
+	if err != nil {
+		return fmt.Errorf("%w: vulnerability feed query failed: %v", domain.ErrUnavailable, err)
+	}
+
+	// Advisories shared by several apps are fetched once per scan
+	advisories := make(map[string]*domain.Vulnerability)
+	for _, sbom := range sboms {
+		var findings []domain.VulnerabilityFinding
+		for _, c := range sbom.Components {
+			for _, id := range affected[c.PURL] {
+				v, ok := advisories[id]
+				if !ok {
+					if v, err = s.feed.Get(ctx, id); err != nil {
+						return fmt.Errorf("%w: failed to fetch advisory %s: %v", domain.ErrUnavailable, id, err)
+					}
+					advisories[id] = v
+				}
+				findings = append(findings, domain.VulnerabilityFinding{
+					VulnID:         v.ID,
+					Aliases:        v.Aliases,
+					Summary:        v.Summary,
+					Severity:       v.Severity,
+					PackageName:    c.Name,
+					PackageVersion: c.Version,
+					PURL:           c.PURL,
+				})
+			}
+		}
+
+		opened, err := s.vulns.Sync(ctx, sbom.AppID, sbom.DeploymentID, findings)
+		if err != nil {
+			return err
+		}
+		s.raiseAlert(ctx, &sbom, opened)
+	}
+
+	s.logger.Info("🧾 Vulnerability scan complete",
+		slog.Int("apps", len(sboms)),
+		slog.Int("packages", len(purls)),
+		slog.Int("affected_packages", len(affected)))
+	return nil
+}
+
+// raiseAlert files one alert per app for its newly opened critical findings;
+// lower severities are only listed on the app.
+func (s *SBOMService) raiseAlert(ctx context.Context, sbom *domain.SBOM, opened []domain.VulnerabilityFinding) {
+	var ids []string
+	for _, f := range opened {
+		if f.Severity == domain.VulnSeverityCritical {
+			ids = append(ids, fmt.Sprintf("%s (%s %s)", advisoryName(&f), f.PackageName, f.PackageVersion))
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	total := len(ids)
+	if len(ids) > alertSampleSize {
+		ids = ids[:alertSampleSize]
+	}
+
+	alert := &domain.SystemAlert{
+		Severity:   "critical",
+		Category:   "security",
+		ResourceID: &sbom.AppID,
+		Message:    fmt.Sprintf("New critical vulnerabilities in the dependencies of %s: %d", sbom.DomainName, total),
+		Metadata: map[string]any{
+			"app_id":          sbom.AppID,
+			"domain":          sbom.DomainName,
+			"deployment_id":   sbom.DeploymentID,
+			"vulnerabilities": ids,
+			"truncated":       total > len(ids),
+		},
+	}
+	if err := s.auditRepo.CreateAlert(ctx, alert); err != nil {
+		s.logger.Error("Failed to raise vulnerability alert", slog.String("app_id", sbom.AppID), slog.Any("error", err))
+	}
+}
+
+// advisoryName prefers the CVE an advisory is best known by.
+func advisoryName(f *domain.VulnerabilityFinding) string {
+	for _, a := range f.Aliases {
+		if strings.HasPrefix(a, "CVE-") {
+			return a
+		}
+	}
+	return f.VulnID
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/grpc/rustagent"
+)
+
+// SecurityHeadersService validates per-domain header policies and has the
+// Muscle render them into the domain's vhost.
+type SecurityHeadersService struct {
+	repo        domain.SecurityHeadersRepository
+	agentClient rustagent.SystemAgentClient
+	agentCaps   domain.AgentCapabilities
+	logger      *slog.Logger
+}
+
+func NewSecurityHeadersService(
+	repo domain.SecurityHeadersRepository,
+	agent rustagent.SystemAgentClient,
+	agentCaps domain.AgentCapabilities,
+	logger *slog.Logger,
+) *SecurityHeadersService {
+	return &SecurityHeadersService{
+		repo:        repo,
+		agentClient: agent,
+		agentCaps:   agentCaps,
+		logger:      logger,
+	}
+}
+
+func (s *SecurityHeadersService) GetHeaders(ctx context.Context, domainID, userID uuid.UUID) (*domain.DomainSecurityHeaders, error) {
+	h, err := s.repo.Get(ctx, domainID, userID)
+	if err != nil {
+		return nil, err
+	}
+	h.Effective = h.Settings.Resolve()
+	return h, nil
+}
+
+// UpdateHeaders applies the policy on the host first and only then stores it,
+// so the database never claims headers the proxy refused (nginx -t failure).
+func (s *SecurityHeadersService) UpdateHeaders(ctx context.Context, domainID, userID uuid.UUID, settings domain.SecurityHeaders) (*domain.DomainSecurityHeaders, error) {
+	if err := domain.ValidateSecurityHeaders(settings); err != nil {
+		return nil, err
+	}
+	// 🛡️ Zero-Trust: Ownership check before touching the vhost
+	h, err := s.repo.Get(ctx, domainID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.agentCaps.Supports(domain.AgentFeatureSecurityHeaders) {
+		return nil, fmt.Errorf("%w: the Muscle agent is too old to manage security headers", domain.ErrUnavailable)
+	}
+
+	effective := settings.Resolve()
+	req := &rustagent.SecurityHeadersRequest{
+		TraceId:    fmt.Sprintf("headers-%s-%d", domainID.String()[:8], time.Now().UnixMilli()),
+		DomainName: h.DomainName,
+	}
+	for _, header := range effective {
+		req.Headers = append(req.Headers, &rustagent.HttpHeader{Name: header.Name, Value: header.Value})
+	}
+	resp, err := s.agentClient.ApplySecurityHeaders(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to apply security headers: %v", domain.ErrUnavailable, err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("%w: proxy rejected the headers: %s", domain.ErrValidation, resp.ErrorMessage)
+	}
+
+	updatedAt, err := s.repo.Upsert(ctx, domainID, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Security headers applied",
+		slog.String("domain", h.DomainName),
+		slog.String("preset", settings.Preset),
+		slog.Int("headers", len(effective)))
+	h.Settings, h.Effective, h.UpdatedAt = settings, effective, &updatedAt
+	return h, nil
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/ssh"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/infrastructure/agentpki"
+)
+
+// Join tokens live long enough to paste into a new server's shell, and no
+// longer. Agent certificates are long-term; re-enrolling replaces one.
+const (
+	DefaultEnrollTokenTTL = time.Hour
+	MaxEnrollTokenTTL     = 24 * time.Hour
+	agentCertValidity     = 365 * 24 * time.Hour
+)
+
+var (
+	serverNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+	hostnamePattern   = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?\.)*[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+	sshUserPattern    = regexp.MustCompile(`^[a-z_][a-z0-9_-]{0,31}$`)
+)
+
+// ServerEnrollmentService lets a new remote Muscle join with a one-time
+// token, Kubernetes node join style: an admin issues the token, the agent
+// presents it with a CSR and receives a certificate from the agent CA.
+type ServerEnrollmentService struct {
+	repo      domain.ServerRepository
+	ca        *agentpki.CA
+	crypto    domain.CryptoService // nil until setup; SSH servers need it
+	auditRepo domain.AuditRepository
+	publicURL string
+	logger    *slog.Logger
+}
+
+func NewServerEnrollmentService(
+	repo domain.ServerRepository,
+	ca *agentpki.CA,
+	crypto domain.CryptoService,
+	auditRepo domain.AuditRepository,
+	publicURL string,
+	logger *slog.Logger,
+) *ServerEnrollmentService {
+	return &ServerEnrollmentService{
+		repo:      repo,
+		ca:        ca,
+		crypto:    crypto,
+		auditRepo: auditRepo,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+		logger:    logger,
+	}
+}
+
+// ==============================================================================
+// 1. Tokens
+// ==============================================================================
+
+// IssueEnrollToken returns the secret once; only its hash is stored.
+func (s *ServerEnrollmentService) IssueEnrollToken(ctx context.Context, actorID uuid.UUID, serverName string, ttl time.Duration) (*domain.EnrollToken, error) {
+	serverName = strings.ToLower(strings.TrimSpace(serverName))
+	if !serverNamePattern.MatchString(serverName) {
+		return nil, fmt.Errorf("%w: server name must be a DNS label (a-z, 0-9, '-')", domain.ErrValidation)
+	}
+	if ttl == 0 {
+		ttl = DefaultEnrollTokenTTL
+	}
+	if ttl < time.Minute || ttl > MaxEnrollTokenTTL {
+		return nil, fmt.Errorf("%w: token lifetime must be between 1 minute and %s", domain.ErrValidation, MaxEnrollTokenTTL)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate enrollment token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	expiresAt := time.Now().Add(ttl).UTC()
+
+	if err := s.repo.CreateEnrollToken(ctx, hashEnrollToken(token), serverName, actorID, expiresAt); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, actorID, "server.enroll_token.issued", serverName, map[string]any{"expires_at": expiresAt})
+
+	return &domain.EnrollToken{
+		Token:      token,
+		ServerName: serverName,
+		ExpiresAt:  expiresAt,
+		CACertHash: s.ca.CertHash(),
+		EnrollURL:  s.publicURL + "/api/v1/servers/enroll",
+	}, nil
+}
+
+func hashEnrollToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ==============================================================================
+// 2. Enrollment
+// ==============================================================================
+
+// Enroll is called unauthenticated by the joining agent; the token is the
+// credential, so every token failure looks the same to the caller.
+func (s *ServerEnrollmentService) Enroll(ctx context.Context, req domain.EnrollRequest) (*domain.Enrollment, error) {
+	if req.Token == "" {
+		return nil, domain.ErrInvalidCredentials
+	}
+	tokenHash := hashEnrollToken(req.Token)
+
+	// Peek first, so a malformed CSR does not burn the token
+	claim, err := s.repo.PeekEnrollToken(ctx, tokenHash)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hostname := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(req.Hostname), "."))
+	if len(hostname) > 253 || !hostnamePattern.MatchString(hostname) {
+		return nil, fmt.Errorf("%w: hostname is not a valid DNS name", domain.ErrValidation)
+	}
+	block, _ := pem.Decode([]byte(req.CSR))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, fmt.Errorf("%w: csr must be a PEM CERTIFICATE REQUEST", domain.ErrValidation)
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: csr is malformed", domain.ErrValidation)
+	}
+
+	// 🛡️ Zero-Trust: The CN is the server ID the Brain assigns; nothing the
+	// agent wrote into its CSR subject ends up in the certificate.
+	serverID := uuid.New()
+	issued, err := s.ca.Sign(csr, serverID.String(), []string{hostname}, agentCertValidity)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrValidation, err)
+	}
+
+	server := &domain.Server{
+		ID:              serverID,
+		Name:            claim.ServerName,
+		Hostname:        hostname,
+		Executor:        domain.ExecutorAgent,
+		Capabilities:    domain.AgentServerCapabilities,
+		CertSerial:      issued.Serial,
+		CertFingerprint: issued.Fingerprint,
+		CertExpiresAt:   &issued.ExpiresAt,
+	}
+	if err := s.repo.EnrollServer(ctx, server, tokenHash); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrInvalidCredentials // Used by a concurrent request
+		}
+		return nil, err
+	}
+
+	s.logger.Info("🖥️ Server enrolled",
+		slog.String("server_id", serverID.String()),
+		slog.String("name", server.Name),
+		slog.String("hostname", hostname))
+	if claim.CreatedBy != nil {
+		s.audit(ctx, *claim.CreatedBy, "server.enrolled", serverID.String(), map[string]any{
+			"name":             server.Name,
+			"hostname":         hostname,
+			"cert_fingerprint": issued.Fingerprint,
+		})
+	}
+
+	return &domain.Enrollment{
+		ServerID:      serverID,
+		Certificate:   string(issued.CertPEM),
+		CACertificate: string(s.ca.CertPEM()),
+		ExpiresAt:     issued.ExpiresAt,
+	}, nil
+}
+
+// ==============================================================================
+// 3. SSH Servers
+// ==============================================================================
+
+// RegisterSSHServer adds a host the Brain drives over SSH instead of the
+// Muscle. The Brain generates the login key and returns only its public
+// half; the admin pins the host key up front, so there is no trust on
+// first use.
+func (s *ServerEnrollmentService) RegisterSSHServer(ctx context.Context, actorID uuid.UUID, req domain.SSHServerRequest) (*domain.SSHServer, error) {
+	if s.crypto == nil {
+		return nil, fmt.Errorf("%w: SSH servers require the crypto service", domain.ErrUnavailable)
+	}
+	name := strings.ToLower(strings.TrimSpace(req.Name))
+	if !serverNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: server name must be a DNS label (a-z, 0-9, '-')", domain.ErrValidation)
+	}
+	hostname := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(req.Hostname), "."))
+	if len(hostname) > 253 || !hostnamePattern.MatchString(hostname) {
+		return nil, fmt.Errorf("%w: hostname is not a valid DNS name", domain.ErrValidation)
+	}
+	port := req.Port
+	if port == 0 {
+		port = 22
+	}
+	if port < 1 || port > 65535 {
+		return nil, fmt.Errorf("%w: port must be between 1 and 65535", domain.ErrValidation)
+	}
+	if !sshUserPattern.MatchString(req.Username) {
+		return nil, fmt.Errorf("%w: username is not a valid login name", domain.ErrValidation)
+	}
+	hostKey, err := parseHostKey(req.HostKey)
+	if err != nil {
+		return nil, err
+	}
+
+	serverID := uuid.New()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate SSH key: %w", err)
+	}
+	comment := "kari-brain-" + name
+	block, err := ssh.MarshalPrivateKey(priv, comment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SSH key: %w", err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SSH key: %w", err)
+	}
+
+	// 🛡️ Privacy: AAD = ServerID, so a leaked row cannot log in to another host
+	ciphertext, err := s.crypto.Encrypt(ctx, pem.EncodeToMemory(block), []byte(serverID.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt SSH key: %w", err)
+	}
+
+	server := &domain.Server{
+		ID:           serverID,
+		Name:         name,
+		Hostname:     hostname,
+		Executor:     domain.ExecutorSSH,
+		Capabilities: domain.SSHServerCapabilities,
+	}
+	cred := &domain.SSHCredential{
+		ServerID:             serverID,
+		Username:             req.Username,
+		Port:                 port,
+		HostKey:              strings.TrimSpace(string(ssh.MarshalAuthorizedKey(hostKey))),
+		PublicKey:            strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub))) + " " + comment,
+		PrivateKeyCiphertext: ciphertext,
+	}
+	if err := s.repo.CreateSSHServer(ctx, server, cred); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("🖥️ SSH server registered",
+		slog.String("server_id", serverID.String()),
+		slog.String("name", name),
+		slog.String("hostname", hostname))
+	s.audit(ctx, actorID, "server.ssh.registered", serverID.String(), map[string]any{
+		"name":             name,
+		"hostname":         hostname,
+		"username":         req.Username,
+		"host_fingerprint": ssh.FingerprintSHA256(hostKey),
+	})
+
+	return &domain.SSHServer{Server: *server, PublicKey: cred.PublicKey}, nil
+}
+
+// parseHostKey accepts an authorized_keys style line ("ssh-ed25519 AAAA...")
+// or a known_hosts / ssh-keyscan line with the host in front.
+func parseHostKey(line string) (ssh.PublicKey, error) {
+	line = strings.TrimSpace(line)
+	if key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line)); err == nil {
+		return key, nil
+	}
+	if _, _, key, _, _, err := ssh.ParseKnownHosts([]byte(line)); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: host_key must be a public host key line, e.g. from ssh-keyscan", domain.ErrValidation)
+}
+
+func (s *ServerEnrollmentService) ListServers(ctx context.Context) ([]domain.Server, error) {
+	return s.repo.ListServers(ctx)
+}
+
+// The issuing admin is the tenant: enrollment happens on their behalf.
+func (s *ServerEnrollmentService) audit(ctx context.Context, actorID uuid.UUID, action, resourceID string, metadata map[string]any) {
+	if err := s.auditRepo.CreateTenantLog(ctx, &domain.TenantLog{
+		TenantID:     actorID,
+		ActorID:      &actorID,
+		Action:       action,
+		ResourceType: "server",
+		ResourceID:   resourceID,
+		Metadata:     metadata,
+	}); err != nil {
+		s.logger.Error("Failed to record server audit log", slog.Any("error", err))
+	}
+}
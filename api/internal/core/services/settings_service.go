@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// SettingsService owns the panel-wide settings row. The maintenance flag,
+// auth policies and time zone are cached in memory because requests, logins
+// and schedulers consult them.
+type SettingsService struct {
+	repo        domain.SettingsRepository
+	auditRepo   domain.AuditRepository
+	logger      *slog.Logger
+	maintenance atomic.Pointer[domain.MaintenanceMode]
+	passkeys    atomic.Pointer[domain.PasskeyPolicy]
+	passwords   atomic.Pointer[domain.PasswordPolicy]
+	timezone    atomic.Pointer[time.Location]
+}
+
+func NewSettingsService(repo domain.SettingsRepository, audit domain.AuditRepository, logger *slog.Logger) *SettingsService {
+	s := &SettingsService{
+		repo:      repo,
+		auditRepo: audit,
+		logger:    logger,
+	}
+	s.maintenance.Store(&domain.MaintenanceMode{})
+	policy := domain.DefaultPasskeyPolicy
+	s.passkeys.Store(&policy)
+	passwords := domain.DefaultPasswordPolicy
+	s.passwords.Store(&passwords)
+	s.timezone.Store(time.UTC)
+	return s
+}
+
+// Load primes the cache at boot so maintenance mode survives a restart.
+func (s *SettingsService) Load(ctx context.Context) error {
+	settings, err := s.repo.Get(ctx)
+	if err != nil {
+		return err
+	}
+	s.maintenance.Store(&settings.Maintenance)
+	s.passkeys.Store(&settings.Passkeys)
+	s.passwords.Store(&settings.Passwords)
+	if loc, err := domain.LoadTimezone(settings.Timezone); err == nil {
+		s.timezone.Store(loc)
+	} else {
+		s.logger.Warn("Stored timezone is unusable; schedules run on UTC",
+			slog.String("timezone", settings.Timezone), slog.Any("error", err))
+	}
+	if settings.Maintenance.Enabled {
+		s.logger.Warn("🚧 Kari Brain: Starting in maintenance mode (read-only for non-admins)")
+	}
+	return nil
+}
+
+func (s *SettingsService) Maintenance() domain.MaintenanceMode {
+	return *s.maintenance.Load()
+}
+
+func (s *SettingsService) PasskeyPolicy() domain.PasskeyPolicy {
+	return *s.passkeys.Load()
+}
+
+func (s *SettingsService) PasswordPolicy() domain.PasswordPolicy {
+	return *s.passwords.Load()
+}
+
+// Location is the install time zone.
+func (s *SettingsService) Location() *time.Location {
+	return s.timezone.Load()
+}
+
+func (s *SettingsService) GetSettings(ctx context.Context) (*domain.SystemSettings, error) {
+	return s.repo.Get(ctx)
+}
+
+// SetMaintenance toggles read-only mode. Re-enabling while already on only
+// updates the message, keeping the original start time.
+func (s *SettingsService) SetMaintenance(ctx context.Context, actorID uuid.UUID, enabled bool, message string) (*domain.MaintenanceMode, error) {
+	current := s.Maintenance()
+	next := domain.MaintenanceMode{}
+	if enabled {
+		next = domain.MaintenanceMode{Enabled: true, Message: strings.TrimSpace(message), Since: current.Since, SetBy: &actorID}
+		if next.Message == "" {
+			next.Message = domain.DefaultMaintenanceMessage
+		}
+		if !current.Enabled {
+			now := time.Now().UTC()
+			next.Since = &now
+		}
+	}
+
+	if err := s.repo.SaveMaintenance(ctx, next); err != nil {
+		return nil, err
+	}
+	s.maintenance.Store(&next)
+
+	if enabled != current.Enabled {
+		s.raiseAlert(ctx, actorID, next)
+	}
+	return &next, nil
+}
+
+// SetPasskeyPolicy changes who must sign in with a passkey. Every change is
+// alerted: loosening it is exactly what an attacker with admin access would do.
+func (s *SettingsService) SetPasskeyPolicy(ctx context.Context, actorID uuid.UUID, p domain.PasskeyPolicy) (*domain.PasskeyPolicy, error) {
+	if err := s.repo.SavePasskeyPolicy(ctx, p); err != nil {
+		return nil, err
+	}
+	s.passkeys.Store(&p)
+
+	s.logger.Warn("🔑 Passkey policy changed",
+		slog.String("actor_id", actorID.String()),
+		slog.Bool("require_for_rank0", p.RequireForRank0),
+		slog.Bool("password_fallback", p.PasswordFallback))
+	if err := s.auditRepo.CreateAlert(ctx, &domain.SystemAlert{
+		Severity: "warning",
+		Category: "security",
+		Message:  "Passkey sign-in policy changed",
+		Metadata: map[string]any{
+			"require_for_rank0": p.RequireForRank0,
+			"password_fallback": p.PasswordFallback,
+			"actor_id":          actorID,
+		},
+	}); err != nil {
+		s.logger.Error("Failed to record passkey policy alert", slog.Any("error", err))
+	}
+	return &p, nil
+}
+
+// SetPasswordPolicy applies to passwords set from now on; MaxAgeDays also
+// applies to existing passwords at their next login.
+func (s *SettingsService) SetPasswordPolicy(ctx context.Context, actorID uuid.UUID, p domain.PasswordPolicy) (*domain.PasswordPolicy, error) {
+	if err := s.repo.SavePasswordPolicy(ctx, p); err != nil {
+		return nil, err
+	}
+	s.passwords.Store(&p)
+
+	if err := s.auditRepo.CreateAlert(ctx, &domain.SystemAlert{
+		Severity: "info",
+		Category: "security",
+		Message:  "Password policy changed",
+		Metadata: map[string]any{
+			"min_length":   p.MinLength,
+			"max_age_days": p.MaxAgeDays,
+			"breach_check": p.BreachCheck,
+			"actor_id":     actorID,
+		},
+	}); err != nil {
+		s.logger.Error("Failed to record password policy alert", slog.Any("error", err))
+	}
+	return &p, nil
+}
+
+// SetTimezone changes the install time zone. Digests and renewal sweeps use
+// it from their next pass, as do users who have not picked their own.
+func (s *SettingsService) SetTimezone(ctx context.Context, actorID uuid.UUID, tz string) (string, error) {
+	loc, err := domain.LoadTimezone(strings.TrimSpace(tz))
+	if err != nil {
+		return "", err
+	}
+	previous := s.Location().String()
+	if err := s.repo.SaveTimezone(ctx, loc.String()); err != nil {
+		return "", err
+	}
+	s.timezone.Store(loc)
+
+	if err := s.auditRepo.CreateAlert(ctx, &domain.SystemAlert{
+		Severity: "info",
+		Category: "system",
+		Message:  "Install time zone changed to " + loc.String(),
+		Metadata: map[string]any{
+			"previous": previous,
+			"timezone": loc.String(),
+			"actor_id": actorID,
+		},
+	}); err != nil {
+		s.logger.Error("Failed to record timezone alert", slog.Any("error", err))
+	}
+	return loc.String(), nil
+}
+
+func (s *SettingsService) raiseAlert(ctx context.Context, actorID uuid.UUID, m domain.MaintenanceMode) {
+	message := "Maintenance mode disabled; the panel accepts changes again"
+	if m.Enabled {
+		message = "Maintenance mode enabled: " + m.Message
+	}
+	s.logger.Warn("🚧 "+message, slog.String("actor_id", actorID.String()))
+
+	if err := s.auditRepo.CreateAlert(ctx, &domain.SystemAlert{
+		Severity: "info",
+		Category: "maintenance",
+		Message:  message,
+		Metadata: map[string]any{
+			"enabled":  m.Enabled,
+			"actor_id": actorID,
+		},
+	}); err != nil {
+		s.logger.Error("Failed to record maintenance alert", slog.Any("error", err))
+	}
+}
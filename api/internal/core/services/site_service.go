@@ -0,0 +1,368 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/saga"
+)
+
+// siteSagaTimeout bounds a whole site creation, ACME included.
+const siteSagaTimeout = 10 * time.Minute
+
+// Saga data keys of a site creation.
+const (
+	siteDataDomainID     = "domain_id"
+	siteDataAppID        = "app_id"
+	siteDataDeploymentID = "deployment_id"
+)
+
+// The slices of the existing services a site creation drives.
+type siteDomains interface {
+	CreateDomain(ctx context.Context, d *domain.Domain) (*domain.Domain, error)
+	DeleteDomain(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+}
+
+type siteApps interface {
+	CreateApplication(ctx context.Context, ownerID uuid.UUID, app *domain.Application) (*domain.Application, error)
+	DeleteApplication(ctx context.Context, appID uuid.UUID, actorID uuid.UUID, actorRank int) error
+}
+
+type siteCertificates interface {
+	ProvisionCert(ctx context.Context, domainName string, email string) error
+}
+
+type siteDeployments interface {
+	Save(ctx context.Context, d *domain.Deployment) error
+}
+
+// SiteService creates a domain, its application, a certificate and the first
+// deployment as one saga. A failed step rolls back the steps before it in
+// reverse order, so a half-created site never lingers in Postgres or on disk;
+// the saga is persisted, so that holds across an API restart too.
+type SiteService struct {
+	domains     siteDomains
+	apps        siteApps
+	certs       siteCertificates
+	deployments siteDeployments
+	sagas       *saga.Orchestrator
+	logger      *slog.Logger
+
+	mu   sync.Mutex
+	live map[uuid.UUID]*siteOperation
+}
+
+// siteOperation is an in-flight saga with SSE watchers attached.
+type siteOperation struct {
+	op       domain.SiteOperation
+	watchers []chan domain.SiteOperation
+}
+
+func NewSiteService(
+	domains siteDomains,
+	apps siteApps,
+	certs siteCertificates,
+	deployments siteDeployments,
+	sagas *saga.Orchestrator,
+	logger *slog.Logger,
+) *SiteService {
+	s := &SiteService{
+		domains:     domains,
+		apps:        apps,
+		certs:       certs,
+		deployments: deployments,
+		sagas:       sagas,
+		logger:      logger,
+		live:        make(map[uuid.UUID]*siteOperation),
+	}
+	sagas.Register(s.definition())
+	return s
+}
+
+// ==============================================================================
+// 1. Public API
+// ==============================================================================
+
+func (s *SiteService) CreateSite(ctx context.Context, userID uuid.UUID, req domain.SiteRequest) (*domain.SiteOperation, error) {
+	started, err := s.sagas.Launch(ctx, domain.SagaKindSite, saga.Params{
+		OwnerID:  userID,
+		Input:    &siteRun{req: req}, // Env vars stay out of the saga table
+		OnChange: s.publish,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Site creation started",
+		slog.String("operation_id", started.ID.String()),
+		slog.String("domain", req.DomainName))
+	op := siteOperationFromSaga(*started)
+	return &op, nil
+}
+
+func (s *SiteService) GetOperation(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.SiteOperation, error) {
+	s.mu.Lock()
+	if live, ok := s.live[id]; ok && live.op.OwnerID == userID {
+		snapshot := live.snapshotLocked()
+		s.mu.Unlock()
+		return &snapshot, nil
+	}
+	s.mu.Unlock()
+
+	// 🛡️ Zero-Trust: Another user's operation is indistinguishable from a missing one
+	stored, err := s.sagas.Get(ctx, domain.SagaKindSite, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	op := siteOperationFromSaga(*stored)
+	return &op, nil
+}
+
+func (s *SiteService) Watch(ctx context.Context, userID uuid.UUID, id uuid.UUID) (<-chan domain.SiteOperation, error) {
+	s.mu.Lock()
+	live, ok := s.live[id]
+	if !ok || live.op.OwnerID != userID {
+		s.mu.Unlock()
+
+		// Finished, or started before a restart: one snapshot is all there is
+		op, err := s.GetOperation(ctx, userID, id)
+		if err != nil {
+			return nil, err
+		}
+		ch := make(chan domain.SiteOperation, 1)
+		ch <- *op
+		close(ch)
+		return ch, nil
+	}
+	defer s.mu.Unlock()
+
+	ch := make(chan domain.SiteOperation, 16)
+	ch <- live.snapshotLocked()
+	live.watchers = append(live.watchers, ch)
+
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, w := range live.watchers {
+			if w == ch {
+				live.watchers = append(live.watchers[:i], live.watchers[i+1:]...)
+				close(ch)
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// ==============================================================================
+// 2. The Saga
+// ==============================================================================
+
+func (s *SiteService) definition() saga.Definition {
+	return saga.Definition{
+		Kind:    domain.SagaKindSite,
+		Timeout: siteSagaTimeout,
+		Steps: []saga.Step{
+			{
+				Name: domain.SiteStepDomain,
+				Execute: func(ctx context.Context, st *saga.State) error {
+					sr, err := siteRunOf(st)
+					if err != nil {
+						return err
+					}
+					d, err := s.domains.CreateDomain(ctx, &domain.Domain{
+						UserID:       st.OwnerID(),
+						DomainName:   sr.req.DomainName,
+						DocumentRoot: sr.req.DocumentRoot,
+						SSLStatus:    "none",
+					})
+					if err != nil {
+						return err
+					}
+					return st.Set(siteDataDomainID, d.ID)
+				},
+				Compensate: func(ctx context.Context, st *saga.State) error {
+					var id uuid.UUID
+					if ok, err := st.Get(siteDataDomainID, &id); !ok || err != nil {
+						return err
+					}
+					return ignoreNotFound(s.domains.DeleteDomain(ctx, id, st.OwnerID()))
+				},
+			},
+			{
+				Name: domain.SiteStepApplication,
+				Execute: func(ctx context.Context, st *saga.State) error {
+					sr, err := siteRunOf(st)
+					if err != nil {
+						return err
+					}
+					var domainID uuid.UUID
+					if _, err := st.Get(siteDataDomainID, &domainID); err != nil {
+						return err
+					}
+					app := sr.req.App
+					app.DomainID = domainID
+					created, err := s.apps.CreateApplication(ctx, st.OwnerID(), &app)
+					if err != nil {
+						return err
+					}
+					sr.app = created
+					return st.Set(siteDataAppID, created.ID)
+				},
+				Compensate: func(ctx context.Context, st *saga.State) error {
+					var id uuid.UUID
+					if ok, err := st.Get(siteDataAppID, &id); !ok || err != nil {
+						return err
+					}
+					// Ownership alone authorizes the undo; no rank privilege is used
+					return ignoreNotFound(s.apps.DeleteApplication(ctx, id, st.OwnerID(), math.MaxInt))
+				},
+			},
+			{
+				// A certificate can be retried once DNS points at the server;
+				// a domain cannot be half-made
+				Name:     domain.SiteStepSSL,
+				Optional: true,
+				Execute: func(ctx context.Context, st *saga.State) error {
+					sr, err := siteRunOf(st)
+					if err != nil {
+						return err
+					}
+					if sr.req.SSLEmail == "" {
+						return saga.ErrSkip
+					}
+					return s.certs.ProvisionCert(ctx, sr.req.DomainName, sr.req.SSLEmail)
+				},
+			},
+			{
+				Name: domain.SiteStepDeployment,
+				Execute: func(ctx context.Context, st *saga.State) error {
+					sr, err := siteRunOf(st)
+					if err != nil {
+						return err
+					}
+					// Image apps are pulled by the Muscle on start, not built from Git
+					if sr.req.App.AppType == "image" {
+						return saga.ErrSkip
+					}
+					app := sr.app
+					d := &domain.Deployment{
+						ID:           uuid.New().String(),
+						AppID:        app.ID.String(),
+						DomainName:   sr.req.DomainName,
+						RepoURL:      app.RepoURL,
+						Branch:       app.Branch,
+						BuildCommand: app.BuildCommand,
+						TargetPort:   app.EffectivePort(),
+						Status:       domain.StatusPending,
+					}
+					if err := s.deployments.Save(ctx, d); err != nil {
+						return err
+					}
+					return st.Set(siteDataDeploymentID, d.ID)
+				},
+			},
+		},
+	}
+}
+
+// siteRun carries the request and created records between steps. It is
+// saga Input, so it is lost on restart; rollback never needs it.
+type siteRun struct {
+	req domain.SiteRequest
+	app *domain.Application
+}
+
+func siteRunOf(st *saga.State) (*siteRun, error) {
+	sr, ok := st.Input.(*siteRun)
+	if !ok {
+		return nil, errors.New("site request unavailable")
+	}
+	return sr, nil
+}
+
+func ignoreNotFound(err error) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// ==============================================================================
+// 3. Fan-out
+// ==============================================================================
+
+// publish is the saga's OnChange hook. Watchers too slow to take a snapshot
+// skip an intermediate state; they always get the final one, because closing
+// the channel follows the last send.
+func (s *SiteService) publish(sg domain.Saga) {
+	op := siteOperationFromSaga(sg)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	live, ok := s.live[op.ID]
+	if !ok {
+		live = &siteOperation{}
+		s.live[op.ID] = live
+	}
+	live.op = op
+	snapshot := live.snapshotLocked()
+	for _, w := range live.watchers {
+		select {
+		case w <- snapshot:
+		default:
+		}
+	}
+
+	if op.Finished() {
+		for _, w := range live.watchers {
+			close(w)
+		}
+		// From here on the persisted saga answers GetOperation
+		delete(s.live, op.ID)
+		s.logger.Info("Site creation finished",
+			slog.String("operation_id", op.ID.String()),
+			slog.String("status", string(op.Status)))
+	}
+}
+
+func (o *siteOperation) snapshotLocked() domain.SiteOperation {
+	snapshot := o.op
+	snapshot.Steps = append([]domain.SagaStep(nil), o.op.Steps...)
+	return snapshot
+}
+
+func siteOperationFromSaga(sg domain.Saga) domain.SiteOperation {
+	op := domain.SiteOperation{
+		ID:        sg.ID,
+		OwnerID:   sg.OwnerID,
+		Status:    sg.Status,
+		Steps:     sg.Steps,
+		CreatedAt: sg.CreatedAt,
+		UpdatedAt: sg.UpdatedAt,
+	}
+	var domainID, appID uuid.UUID
+	if sagaValue(sg, siteDataDomainID, &domainID) {
+		op.DomainID = &domainID
+	}
+	if sagaValue(sg, siteDataAppID, &appID) {
+		op.AppID = &appID
+	}
+	sagaValue(sg, siteDataDeploymentID, &op.DeploymentID)
+	return op
+}
+
+// sagaValue decodes one data key of a saga snapshot.
+func sagaValue(sg domain.Saga, key string, v any) bool {
+	raw, ok := sg.Data[key]
+	return ok && json.Unmarshal(raw, v) == nil
+}
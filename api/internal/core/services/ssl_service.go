@@ -14,9 +14,14 @@ import (
 type SslService struct {
 	repo        domain.SslRepository
 	agentClient rustagent.SystemAgentClient
+	outbox      *Outbox
 	logger      *slog.Logger
 }
 
+func NewSslService(repo domain.SslRepository, agent rustagent.SystemAgentClient, outbox *Outbox, logger *slog.Logger) *SslService {
+	return &SslService{repo: repo, agentClient: agent, outbox: outbox, logger: logger}
+}
+
 // ProvisionCert orchestrates the platform-independent ACME flow
 func (s *SslService) ProvisionCert(ctx context.Context, domainName string, email string) error {
 	s.logger.Info("Initiating ACME handshake", slog.String("domain", domainName))
@@ -48,12 +53,26 @@ func (s *SslService) ProvisionCert(ctx context.Context, domainName string, email
 
 	// 🛡️ 4. Unified Installation
 	// The Muscle receives the PEM bytes and installs them into the
-	// platform-specific paths (e.g., /etc/ssl/ or /etc/pki/)
-	_, err = s.agentClient.InstallCertificate(ctx, &rustagent.SslInstallRequest{
+	// platform-specific paths (e.g., /etc/ssl/ or /etc/pki/).
+	// The install goes through the outbox: if the Muscle is mid-restart it is
+	// kept (encrypted) and applied exactly once when it returns.
+	queued, err := s.outbox.Submit(ctx, &domain.StateChange{
+		Event:        domain.EventCertInstalled,
+		ResourceType: "certificate",
+		ResourceID:   domainName,
+		Method:       rustagent.SystemAgent_InstallCertificate_FullMethodName,
+		Description:  "Install certificate for " + domainName,
+	}, &rustagent.SslPayload{
 		DomainName:   domainName,
 		FullchainPem: certs.Certificate,
 		PrivkeyPem:   certs.PrivateKey,
 	})
+	if err != nil {
+		return fmt.Errorf("certificate_install_failed: %w", err)
+	}
+	if queued {
+		s.logger.Warn("Certificate install queued until the Muscle returns", slog.String("domain", domainName))
+	}
 
 	return s.repo.MarkAsSecure(ctx, domainName, certs.Expiry)
 }
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// StackRegistryService manages the toolchain versions apps may pin. The
+// per-runtime defaults live in the SystemProfile; this owns the catalogue
+// and moving apps between versions.
+type StackRegistryService struct {
+	repo        domain.StackRegistryRepository
+	deployments domain.DeploymentRepository // Approval-gated, like every other enqueue
+	auditRepo   domain.AuditRepository
+	logger      *slog.Logger
+}
+
+func NewStackRegistryService(
+	repo domain.StackRegistryRepository,
+	deployments domain.DeploymentRepository,
+	audit domain.AuditRepository,
+	logger *slog.Logger,
+) *StackRegistryService {
+	return &StackRegistryService{
+		repo:        repo,
+		deployments: deployments,
+		auditRepo:   audit,
+		logger:      logger,
+	}
+}
+
+// ==============================================================================
+// 1. Catalogue
+// ==============================================================================
+
+func (s *StackRegistryService) ListStackVersions(ctx context.Context) (domain.StackCatalogue, error) {
+	return s.repo.ListStackVersions(ctx)
+}
+
+// AddStackVersion lists a new version. The Muscle still has to have it
+// installed under its runtimes directory before a deploy can use it.
+func (s *StackRegistryService) AddStackVersion(ctx context.Context, actorID uuid.UUID, runtime, version string) (*domain.StackVersion, error) {
+	if err := domain.ValidateStackVersion(runtime, version); err != nil {
+		return nil, err
+	}
+	v, err := s.repo.CreateStackVersion(ctx, runtime, version)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, actorID, "stack.version.added", v, nil)
+	return v, nil
+}
+
+// SetStackVersionStatus deprecates a version or makes it available again.
+// The default of its runtime cannot be deprecated: unpinned apps would be
+// left building with a version nobody may pick.
+func (s *StackRegistryService) SetStackVersionStatus(ctx context.Context, actorID uuid.UUID, runtime, version string, status domain.StackVersionStatus) (*domain.StackVersion, error) {
+	if status != domain.StackVersionAvailable && status != domain.StackVersionDeprecated {
+		return nil, fmt.Errorf("%w: status must be %q or %q", domain.ErrValidation, domain.StackVersionAvailable, domain.StackVersionDeprecated)
+	}
+	v, err := s.find(ctx, runtime, version)
+	if err != nil {
+		return nil, err
+	}
+	if v.Status == status {
+		return v, nil
+	}
+	if status == domain.StackVersionDeprecated && v.IsDefault {
+		return nil, fmt.Errorf("%w: %s %s is the default; pick another default in the system profile first", domain.ErrConflict, runtime, version)
+	}
+
+	if err := s.repo.SetStackVersionStatus(ctx, runtime, version, status); err != nil {
+		return nil, err
+	}
+	v.Status = status
+	action := "stack.version.restored"
+	if status == domain.StackVersionDeprecated {
+		action = "stack.version.deprecated"
+	}
+	s.audit(ctx, actorID, action, v, map[string]any{"app_count": v.AppCount})
+	return s.find(ctx, runtime, version)
+}
+
+// RemoveStackVersion delists a version no app builds with any more.
+func (s *StackRegistryService) RemoveStackVersion(ctx context.Context, actorID uuid.UUID, runtime, version string) error {
+	v, err := s.find(ctx, runtime, version)
+	if err != nil {
+		return err
+	}
+	if v.IsDefault {
+		return fmt.Errorf("%w: %s %s is the default; pick another default in the system profile first", domain.ErrConflict, runtime, version)
+	}
+	if err := s.repo.DeleteStackVersion(ctx, runtime, version); err != nil {
+		return err
+	}
+	s.audit(ctx, actorID, "stack.version.removed", v, nil)
+	return nil
+}
+
+func (s *StackRegistryService) StackVersionApps(ctx context.Context, runtime, version string) ([]domain.StackVersionApp, error) {
+	if _, err := s.find(ctx, runtime, version); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStackVersionApps(ctx, runtime, version)
+}
+
+// ==============================================================================
+// 2. Bulk Migration
+// ==============================================================================
+
+// MigrateApps pins every app building with from to to, and queues a fresh
+// build of each. Apps that followed from as the default are pinned too,
+// so changing the default later does not move them again unannounced.
+func (s *StackRegistryService) MigrateApps(ctx context.Context, actorID uuid.UUID, runtime, from, to string) (*domain.StackMigration, error) {
+	if from == to {
+		return nil, fmt.Errorf("%w: from and to are the same version", domain.ErrValidation)
+	}
+	source, err := s.find(ctx, runtime, from)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.find(ctx, runtime, to)
+	if err != nil {
+		return nil, err
+	}
+	if target.Status != domain.StackVersionAvailable {
+		return nil, fmt.Errorf("%w: %s %s is deprecated", domain.ErrValidation, runtime, to)
+	}
+
+	apps, err := s.repo.RepinApps(ctx, runtime, from, to, source.IsDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.StackMigration{Runtime: runtime, From: from, To: to, Apps: len(apps), DeploymentIDs: []string{}}
+	for i := range apps {
+		app := &apps[i]
+		d := &domain.Deployment{
+			ID:           uuid.New().String(),
+			AppID:        app.ID.String(),
+			DomainName:   app.DomainName,
+			RepoURL:      app.RepoURL,
+			Branch:       app.Branch,
+			BuildCommand: app.BuildCommand,
+			TargetPort:   app.EffectivePort(),
+			Status:       domain.StatusPending,
+		}
+		// The pin is already saved; a failed enqueue only delays the switch to the next deploy
+		if err := s.deployments.Save(ctx, d); err != nil {
+			s.logger.Error("Failed to queue stack migration redeploy",
+				slog.String("app_id", d.AppID), slog.Any("error", err))
+			continue
+		}
+		result.DeploymentIDs = append(result.DeploymentIDs, d.ID)
+
+		// 🛡️ Audit: The tenant sees who changed their app's toolchain
+		if err := s.auditRepo.CreateTenantLog(ctx, &domain.TenantLog{
+			TenantID:     app.OwnerID,
+			ActorID:      &actorID,
+			Action:       "application.runtime_migrated",
+			ResourceType: "application",
+			ResourceID:   app.ID.String(),
+			Metadata:     map[string]any{"runtime": runtime, "from": from, "to": to, "deployment_id": d.ID},
+		}); err != nil {
+			s.logger.Error("Failed to record stack migration audit log", slog.Any("error", err))
+		}
+	}
+
+	s.logger.Info("🧰 Stack migration queued",
+		slog.String("runtime", runtime),
+		slog.String("from", from),
+		slog.String("to", to),
+		slog.Int("apps", result.Apps),
+		slog.Int("redeploys", len(result.DeploymentIDs)),
+		slog.String("actor_id", actorID.String()))
+	return result, nil
+}
+
+// ==============================================================================
+// 3. Helpers
+// ==============================================================================
+
+func (s *StackRegistryService) find(ctx context.Context, runtime, version string) (*domain.StackVersion, error) {
+	catalogue, err := s.repo.ListStackVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v := catalogue.Find(runtime, version)
+	if v == nil {
+		return nil, fmt.Errorf("%w: %s %s is not in the stack registry", domain.ErrNotFound, runtime, version)
+	}
+	return v, nil
+}
+
+func (s *StackRegistryService) audit(ctx context.Context, actorID uuid.UUID, action string, v *domain.StackVersion, metadata map[string]any) {
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadata["runtime"], metadata["version"] = v.Runtime, v.Version
+	if err := s.auditRepo.CreateTenantLog(ctx, &domain.TenantLog{
+		TenantID:     actorID,
+		ActorID:      &actorID,
+		Action:       action,
+		ResourceType: "stack_version",
+		ResourceID:   v.Runtime + "/" + v.Version,
+		Metadata:     metadata,
+	}); err != nil {
+		s.logger.Error("Failed to record stack registry audit log", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"kari/api/internal/core/domain"
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+// profileAgents is satisfied by agentlink.RemotePool.
+type profileAgents interface {
+	Client(s domain.Server) (agent.SystemAgentClient, error)
+}
+
+// SystemProfileService owns the platform-wide SystemProfile. Saving only
+// writes the database; Reconcile pushes the cgroup ceilings and the default
+// firewall policy to every agent that has not applied the latest version.
+type SystemProfileService struct {
+	repo        domain.SystemProfileRepository
+	auditRepo   domain.AuditRepository
+	agentClient agent.SystemAgentClient
+	agentCaps   domain.AgentCapabilities
+	servers     domain.ServerRepository
+	remotes     profileAgents
+	stacks      domain.StackRegistryRepository
+	logger      *slog.Logger
+
+	wake chan struct{}
+}
+
+func NewSystemProfileService(
+	repo domain.SystemProfileRepository,
+	audit domain.AuditRepository,
+	agentClient agent.SystemAgentClient,
+	agentCaps domain.AgentCapabilities,
+	logger *slog.Logger,
+) *SystemProfileService {
+	return &SystemProfileService{
+		repo:        repo,
+		auditRepo:   audit,
+		agentClient: agentClient,
+		agentCaps:   agentCaps,
+		logger:      logger,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// WithServers also rolls the profile out to enrolled remote agents.
+func (s *SystemProfileService) WithServers(servers domain.ServerRepository, remotes profileAgents) *SystemProfileService {
+	s.servers = servers
+	s.remotes = remotes
+	return s
+}
+
+// WithStacks checks the stack defaults against the live stack registry.
+func (s *SystemProfileService) WithStacks(stacks domain.StackRegistryRepository) *SystemProfileService {
+	s.stacks = stacks
+	return s
+}
+
+// Wake fires whenever a new profile version is saved.
+func (s *SystemProfileService) Wake() <-chan struct{} { return s.wake }
+
+// ==============================================================================
+// 1. Management
+// ==============================================================================
+
+func (s *SystemProfileService) Get(ctx context.Context) (*domain.SystemProfile, []domain.ProfileRollout, error) {
+	profile, err := s.repo.GetActiveProfile(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	rollouts, err := s.repo.ListRollouts(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return profile, rollouts, nil
+}
+
+// Update merges the patch onto the current profile and saves it under OCC.
+// The agents pick the new version up asynchronously.
+func (s *SystemProfileService) Update(ctx context.Context, actorID uuid.UUID, patch domain.SystemProfilePatch) (*domain.SystemProfile, error) {
+	current, err := s.repo.GetActiveProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	next := patch.Apply(*current)
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+	catalogue := domain.SeedStackCatalogue()
+	if s.stacks != nil {
+		if catalogue, err = s.stacks.ListStackVersions(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := catalogue.ValidateDefaults(next.DefaultStackRegistry); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateProfile(ctx, &next); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditRepo.CreateTenantLog(ctx, &domain.TenantLog{
+		TenantID:     actorID,
+		ActorID:      &actorID,
+		Action:       "system.profile.updated",
+		ResourceType: "system_profile",
+		ResourceID:   next.ID.String(),
+		Metadata: map[string]any{
+			"version":                 next.Version,
+			"max_memory_per_app_mb":   next.MaxMemoryPerAppMB,
+			"max_cpu_percent_per_app": next.MaxCPUPercentPerApp,
+			"default_firewall_policy": next.DefaultFirewallPolicy,
+			"uid_range":               fmt.Sprintf("%d-%d", next.AppUserUIDRangeStart, next.AppUserUIDRangeEnd),
+		},
+	}); err != nil {
+		s.logger.Error("Failed to record profile audit log", slog.Any("error", err))
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default: // A rollout is already pending
+	}
+	return &next, nil
+}
+
+// ==============================================================================
+// 2. Rollout (shared with the reconciler worker)
+// ==============================================================================
+
+// Reconcile pushes the profile to each agent whose applied version is behind.
+// Offline servers wait for the next pass; they are not counted as failures.
+func (s *SystemProfileService) Reconcile(ctx context.Context) {
+	profile, err := s.repo.GetActiveProfile(ctx)
+	if err != nil {
+		s.logger.Error("Failed to load system profile for rollout", slog.Any("error", err))
+		return
+	}
+	existing, err := s.repo.ListRollouts(ctx)
+	if err != nil {
+		s.logger.Error("Failed to load profile rollouts", slog.Any("error", err))
+		return
+	}
+	rollouts := make(map[string]domain.ProfileRollout, len(existing))
+	for _, ro := range existing {
+		rollouts[ro.Target] = ro
+	}
+
+	req := &agent.SystemDefaults{
+		ProfileVersion:        uint32(profile.Version),
+		MaxMemoryPerAppMb:     uint32(profile.MaxMemoryPerAppMB),
+		MaxCpuPercentPerApp:   uint32(profile.MaxCPUPercentPerApp),
+		DefaultFirewallPolicy: profile.DefaultFirewallPolicy,
+	}
+
+	if s.agentCaps.Supports(domain.AgentFeatureSystemDefaults) {
+		s.push(ctx, domain.LocalRolloutTarget, "this server", s.agentClient, req, rollouts[domain.LocalRolloutTarget])
+	}
+
+	if s.servers == nil {
+		return
+	}
+	servers, err := s.servers.ListServers(ctx)
+	if err != nil {
+		s.logger.Error("Failed to list servers for profile rollout", slog.Any("error", err))
+		return
+	}
+	for i := range servers {
+		srv := servers[i]
+		// SSH hosts have no Muscle to hold the ceilings
+		if srv.Executor != domain.ExecutorAgent || srv.Status != domain.ServerOnline {
+			continue
+		}
+		client, err := s.remotes.Client(srv)
+		if err != nil {
+			s.logger.Warn("Failed to reach server for profile rollout", slog.String("server", srv.Name), slog.Any("error", err))
+			continue
+		}
+		s.push(ctx, srv.ID.String(), srv.Name, client, req, rollouts[srv.ID.String()])
+	}
+}
+
+func (s *SystemProfileService) push(ctx context.Context, target, name string, client agent.SystemAgentClient, req *agent.SystemDefaults, prev domain.ProfileRollout) {
+	version := int(req.ProfileVersion)
+	if prev.AppliedVersion >= version {
+		return
+	}
+
+	pushCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	resp, err := client.ApplySystemDefaults(pushCtx, req)
+
+	ro := domain.ProfileRollout{Target: target}
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+		// A remote agent older than protocol rev 20; it catches up once upgraded
+		ro.LastError = "agent is too old to apply system defaults"
+		if prev.LastError == ro.LastError {
+			return
+		}
+	case err != nil:
+		ro.LastError = err.Error()
+	case !resp.Success:
+		ro.LastError = resp.ErrorMessage
+	default:
+		now := time.Now().UTC()
+		ro.AppliedVersion, ro.AppliedAt = version, &now
+	}
+
+	if err := s.repo.RecordRollout(ctx, ro); err != nil {
+		s.logger.Error("Failed to record profile rollout", slog.String("target", target), slog.Any("error", err))
+		return
+	}
+	if ro.LastError == "" {
+		s.logger.Info("📐 System profile applied", slog.String("server", name), slog.Int("version", version))
+		return
+	}
+
+	s.logger.Warn("System profile rollout failed", slog.String("server", name), slog.Int("version", version), slog.String("error", ro.LastError))
+	// 🛡️ SLA: One alert per failing streak, not one per retry
+	if prev.LastError != "" {
+		return
+	}
+	resourceID := target
+	if err := s.auditRepo.CreateAlert(ctx, &domain.SystemAlert{
+		Severity:   "warning",
+		Category:   "server",
+		ResourceID: &resourceID,
+		Message:    fmt.Sprintf("System profile v%d could not be applied on %s: %s", version, name, ro.LastError),
+		Metadata:   map[string]any{"server": name, "profile_version": version},
+	}); err != nil {
+		s.logger.Error("Failed to raise profile rollout alert", slog.Any("error", err))
+	}
+}
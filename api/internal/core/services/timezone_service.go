@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// TimezoneService resolves which zone applies to a user: their own setting
+// when they have one, else the install's. Schedules that belong to a user
+// (digests, maintenance windows they enter) and ?tz=user both go through it.
+type TimezoneService struct {
+	repo    domain.UserTimezoneRepository
+	install domain.TimezoneSource
+	logger  *slog.Logger
+}
+
+func NewTimezoneService(repo domain.UserTimezoneRepository, install domain.TimezoneSource, logger *slog.Logger) *TimezoneService {
+	return &TimezoneService{repo: repo, install: install, logger: logger}
+}
+
+func (s *TimezoneService) GetPreference(ctx context.Context, userID uuid.UUID) (*domain.TimezonePreference, error) {
+	tz, err := s.repo.GetUserTimezone(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TimezonePreference{Timezone: tz, Effective: s.resolve(tz).String()}, nil
+}
+
+// SetPreference stores an IANA zone; an empty one goes back to the install's.
+func (s *TimezoneService) SetPreference(ctx context.Context, userID uuid.UUID, tz string) (*domain.TimezonePreference, error) {
+	tz = strings.TrimSpace(tz)
+	if tz != "" {
+		loc, err := domain.LoadTimezone(tz)
+		if err != nil {
+			return nil, err
+		}
+		tz = loc.String() // Canonical spelling
+	}
+	if err := s.repo.SetUserTimezone(ctx, userID, tz); err != nil {
+		return nil, err
+	}
+	return &domain.TimezonePreference{Timezone: tz, Effective: s.resolve(tz).String()}, nil
+}
+
+// LocationFor never fails: a lookup error costs the user their own zone for
+// this call, not the request.
+func (s *TimezoneService) LocationFor(ctx context.Context, userID uuid.UUID) *time.Location {
+	tz, err := s.repo.GetUserTimezone(ctx, userID)
+	if err != nil {
+		s.logger.Warn("User timezone unavailable; using the install's",
+			slog.String("user_id", userID.String()), slog.Any("error", err))
+	}
+	return s.resolve(tz)
+}
+
+// resolve also guards against a zone that was valid when saved but is no
+// longer known to this build's tz database.
+func (s *TimezoneService) resolve(tz string) *time.Location {
+	if tz != "" {
+		if loc, err := domain.LoadTimezone(tz); err == nil {
+			return loc
+		}
+	}
+	return s.install.Location()
+}
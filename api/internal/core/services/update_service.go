@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/grpc/rustagent"
+	"kari/api/internal/version"
+)
+
+const (
+	maxReleaseBinaryBytes = 256 << 20 // 256 MiB
+	maxReleaseFeedBytes   = 1 << 20
+	brainHealthTimeout    = 60 // seconds the Muscle waits before rolling back
+)
+
+// releaseVersionPattern doubles as a path-safety check: versions become file names.
+var releaseVersionPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+(-[0-9A-Za-z.]+)?$`)
+
+// UpdateService checks the signed release feed and hands verified binaries to
+// the Muscle, which owns the binary swap, restart and rollback.
+type UpdateService struct {
+	feedURL     string
+	publicKey   ed25519.PublicKey
+	stagingDir  string
+	agentClient rustagent.SystemAgentClient
+	agentCaps   domain.AgentCapabilities
+	audit       domain.AuditRepository
+	httpClient  *http.Client
+	logger      *slog.Logger
+
+	mu     sync.Mutex
+	status domain.UpdateStatus
+}
+
+// NewUpdateService returns an error only for a malformed signing key. An empty
+// feed URL or key leaves the service in a disabled state (Check reports
+// ErrUnavailable) so the Brain still boots on unconfigured installs.
+func NewUpdateService(feedURL, publicKeyHex, stagingDir string, agent rustagent.SystemAgentClient, agentCaps domain.AgentCapabilities, audit domain.AuditRepository, logger *slog.Logger) (*UpdateService, error) {
+	s := &UpdateService{
+		feedURL:     feedURL,
+		stagingDir:  stagingDir,
+		agentClient: agent,
+		agentCaps:   agentCaps,
+		audit:       audit,
+		httpClient:  &http.Client{Timeout: 5 * time.Minute},
+		logger:      logger,
+		status: domain.UpdateStatus{
+			CurrentVersion: version.Version,
+			State:          domain.UpdateStateIdle,
+		},
+	}
+
+	if publicKeyHex != "" {
+		key, err := hex.DecodeString(publicKeyHex)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid UPDATE_PUBLIC_KEY: expected %d hex-encoded bytes", ed25519.PublicKeySize)
+		}
+		s.publicKey = ed25519.PublicKey(key)
+	}
+	return s, nil
+}
+
+// ==============================================================================
+// 1. Release Feed
+// ==============================================================================
+
+// Check fetches the release feed and reports whether a newer version exists.
+func (s *UpdateService) Check(ctx context.Context) (*domain.UpdateStatus, error) {
+	if s.feedURL == "" || s.publicKey == nil {
+		return nil, fmt.Errorf("%w: self-update is not configured", domain.ErrUnavailable)
+	}
+
+	latest, err := s.fetchLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.Latest = latest
+	s.status.UpdateAvailable = isNewerVersion(latest.Version, s.status.CurrentVersion)
+	s.status.CheckedAt = time.Now().UTC()
+
+	snapshot := s.status
+	return &snapshot, nil
+}
+
+func (s *UpdateService) fetchLatest(ctx context.Context) (*domain.ReleaseInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build feed request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: release feed unreachable: %v", domain.ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: release feed returned %d", domain.ErrUnavailable, resp.StatusCode)
+	}
+
+	var release domain.ReleaseInfo
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxReleaseFeedBytes)).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode release feed: %w", err)
+	}
+	if !releaseVersionPattern.MatchString(release.Version) {
+		return nil, fmt.Errorf("release feed advertised an invalid version %q", release.Version)
+	}
+	return &release, nil
+}
+
+// ==============================================================================
+// 2. Download, Verify, Hand Off
+// ==============================================================================
+
+// Apply downloads and verifies the latest release, then asks the Muscle to
+// install it. On success the Muscle restarts this process shortly after the
+// RPC returns, so the caller should answer 202 and let the UI poll /ping.
+func (s *UpdateService) Apply(ctx context.Context, actorID uuid.UUID) (*domain.UpdateStatus, error) {
+	status, err := s.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !status.UpdateAvailable {
+		return nil, fmt.Errorf("%w: already running %s", domain.ErrConflict, status.CurrentVersion)
+	}
+	// Older Muscles lack ApplyBrainUpdate; fail before downloading anything
+	if !s.agentCaps.Supports(domain.AgentFeatureBrainUpdate) {
+		return nil, fmt.Errorf("%w: the Muscle agent is too old to install Brain updates", domain.ErrUnavailable)
+	}
+	release := *status.Latest
+
+	// 🛡️ Stability: Only one update may be in flight
+	s.mu.Lock()
+	if s.status.State != domain.UpdateStateIdle && s.status.State != domain.UpdateStateFailed {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%w: an update is already in progress", domain.ErrConflict)
+	}
+	s.status.State = domain.UpdateStateDownloading
+	s.status.LastError = ""
+	s.mu.Unlock()
+
+	if err := s.install(ctx, release); err != nil {
+		s.setState(domain.UpdateStateFailed, err.Error())
+		s.logger.Error("Brain self-update failed",
+			slog.String("version", release.Version),
+			slog.Any("error", err))
+		return nil, err
+	}
+
+	s.setState(domain.UpdateStateInstalling, "")
+	s.logger.Warn("🔄 Brain update handed to Muscle; restart imminent",
+		slog.String("from", version.Version),
+		slog.String("to", release.Version))
+
+	s.recordAudit(ctx, actorID, release)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot := s.status
+	return &snapshot, nil
+}
+
+func (s *UpdateService) install(ctx context.Context, release domain.ReleaseInfo) error {
+	// 🛡️ Zero-Trust: Verify the signature over (version, digest) BEFORE downloading,
+	// so an unsigned feed can never make us fetch arbitrary URLs into the staging dir.
+	sig, err := base64.StdEncoding.DecodeString(release.Signature)
+	if err != nil || !ed25519.Verify(s.publicKey, release.SignedManifest(), sig) {
+		return fmt.Errorf("%w: release signature verification failed", domain.ErrForbidden)
+	}
+
+	stagedPath, err := s.download(ctx, release)
+	if err != nil {
+		return err
+	}
+
+	s.setState(domain.UpdateStateInstalling, "")
+	resp, err := s.agentClient.ApplyBrainUpdate(ctx, &rustagent.BrainUpdateRequest{
+		Version:              release.Version,
+		StagedPath:           stagedPath,
+		Sha256:               strings.ToLower(release.SHA256),
+		HealthTimeoutSeconds: brainHealthTimeout,
+	})
+	if err != nil {
+		os.Remove(stagedPath)
+		return fmt.Errorf("%w: agent rejected update: %v", domain.ErrUnavailable, err)
+	}
+	if !resp.Success {
+		os.Remove(stagedPath)
+		return fmt.Errorf("agent failed to install update: %s", resp.ErrorMessage)
+	}
+	return nil
+}
+
+// download streams the binary into the staging dir while hashing it. The file
+// only gets its final name once the digest matches the signed manifest.
+func (s *UpdateService) download(ctx context.Context, release domain.ReleaseInfo) (string, error) {
+	if !strings.HasPrefix(release.URL, "https://") {
+		return "", fmt.Errorf("%w: release URL must use https", domain.ErrValidation)
+	}
+	if err := os.MkdirAll(s.stagingDir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create staging dir: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, release.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build download request: %w", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: release download failed: %v", domain.ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: release download returned %d", domain.ErrUnavailable, resp.StatusCode)
+	}
+
+	tmp, err := os.CreateTemp(s.stagingDir, ".kari-api-*.part")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+
+	s.setState(domain.UpdateStateVerifying, "")
+	hasher := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, hasher), io.LimitReader(resp.Body, maxReleaseBinaryBytes+1))
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to stage release binary: %w", err)
+	}
+	if n > maxReleaseBinaryBytes {
+		return "", fmt.Errorf("%w: release binary exceeds %d bytes", domain.ErrValidation, maxReleaseBinaryBytes)
+	}
+
+	digest := hex.EncodeToString(hasher.Sum(nil))
+	if !strings.EqualFold(digest, release.SHA256) {
+		return "", fmt.Errorf("%w: release checksum mismatch", domain.ErrForbidden)
+	}
+
+	final := filepath.Join(s.stagingDir, "kari-api-"+release.Version)
+	if err := os.Rename(tmp.Name(), final); err != nil {
+		return "", fmt.Errorf("failed to finalize staged binary: %w", err)
+	}
+	return final, nil
+}
+
+// ==============================================================================
+// 3. Helpers
+// ==============================================================================
+
+func (s *UpdateService) setState(state domain.UpdateState, lastError string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.State = state
+	s.status.LastError = lastError
+}
+
+// recordAudit is best-effort: the update has already been handed off.
+func (s *UpdateService) recordAudit(ctx context.Context, actorID uuid.UUID, release domain.ReleaseInfo) {
+	if s.audit == nil {
+		return
+	}
+	err := s.audit.CreateAlert(ctx, &domain.SystemAlert{
+		Severity: "info",
+		Category: "system",
+		Message:  fmt.Sprintf("Brain update to %s started", release.Version),
+		Metadata: map[string]any{
+			"from_version": version.Version,
+			"to_version":   release.Version,
+			"sha256":       release.SHA256,
+			"actor_id":     actorID.String(),
+		},
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		s.logger.Warn("Failed to record update alert", slog.Any("error", err))
+	}
+}
+
+// isNewerVersion compares MAJOR.MINOR.PATCH. A pre-release sorts before its
+// release, and "dev" builds never auto-update.
+func isNewerVersion(candidate, current string) bool {
+	c, cPre, ok := parseVersion(candidate)
+	if !ok {
+		return false
+	}
+	cur, curPre, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	for i := range c {
+		if c[i] != cur[i] {
+			return c[i] > cur[i]
+		}
+	}
+	// Same core version: a release beats its pre-release
+	return cPre == "" && curPre != ""
+}
+
+func parseVersion(v string) ([3]int, string, bool) {
+	var out [3]int
+	if !releaseVersionPattern.MatchString(v) {
+		return out, "", false
+	}
+	v = strings.TrimPrefix(v, "v")
+	core, pre, _ := strings.Cut(v, "-")
+	for i, part := range strings.SplitN(core, ".", 3) {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return out, "", false
+		}
+		out[i] = n
+	}
+	return out, pre, true
+}
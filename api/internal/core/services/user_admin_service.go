@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// roleAuthority is satisfied by RoleService.
+type roleAuthority interface {
+	AssignRole(ctx context.Context, actorID, targetUserID, newRoleID uuid.UUID) error
+	CheckAssignable(ctx context.Context, actorID, roleID uuid.UUID) (*domain.Role, error)
+	CheckAuthority(ctx context.Context, actorID, targetUserID uuid.UUID) error
+	GuardLastAdmin(ctx context.Context, targetUserID uuid.UUID) error
+}
+
+// passwordPolicy is satisfied by PasswordService.
+type passwordPolicy interface {
+	ValidatePassword(ctx context.Context, email, password string) error
+	HashPassword(password string) (string, error)
+}
+
+// UserAdminService backs the /users API. Rank checks and last-admin
+// protection are delegated to RoleService so role changes made here and
+// through any other path follow the same rules.
+type UserAdminService struct {
+	repo      domain.UserAdminRepository
+	roles     roleAuthority
+	passwords passwordPolicy
+	auditRepo domain.AuditRepository
+	logger    *slog.Logger
+}
+
+func NewUserAdminService(
+	repo domain.UserAdminRepository,
+	roles roleAuthority,
+	passwords passwordPolicy,
+	audit domain.AuditRepository,
+	logger *slog.Logger,
+) *UserAdminService {
+	return &UserAdminService{
+		repo:      repo,
+		roles:     roles,
+		passwords: passwords,
+		auditRepo: audit,
+		logger:    logger,
+	}
+}
+
+func (s *UserAdminService) ListUsers(ctx context.Context, filter domain.UserFilter, page domain.PageRequest) (domain.Page[domain.ManagedUser], error) {
+	return s.repo.List(ctx, filter, page)
+}
+
+func (s *UserAdminService) GetUser(ctx context.Context, id uuid.UUID) (*domain.ManagedUser, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *UserAdminService) CreateUser(ctx context.Context, actorID uuid.UUID, u domain.NewUser) (*domain.ManagedUser, error) {
+	if _, err := s.roles.CheckAssignable(ctx, actorID, u.RoleID); err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(u.Email))
+	if err := s.passwords.ValidatePassword(ctx, email, u.Password); err != nil {
+		return nil, err
+	}
+	hash, err := s.passwords.HashPassword(u.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(ctx, email, hash, u.RoleID, u.MustChangePassword)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, actorID, created.ID, "user.create", map[string]any{
+		"email":                created.Email,
+		"role":                 created.RoleName,
+		"must_change_password": created.MustChangePassword,
+	})
+	return created, nil
+}
+
+// UpdateUser applies a partial edit. The role change runs first so a
+// refused escalation leaves the rest of the account untouched.
+func (s *UserAdminService) UpdateUser(ctx context.Context, actorID, id uuid.UUID, u domain.UserUpdate) (*domain.ManagedUser, error) {
+	if err := s.roles.CheckAuthority(ctx, actorID, id); err != nil {
+		return nil, err
+	}
+	if u.IsActive != nil && !*u.IsActive {
+		if err := s.guardDeactivation(ctx, actorID, id); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := map[string]any{}
+	if u.RoleID != nil {
+		if err := s.roles.AssignRole(ctx, actorID, id, *u.RoleID); err != nil {
+			return nil, err
+		}
+		changes["role_id"] = *u.RoleID
+	}
+	if u.Email != nil {
+		email := strings.ToLower(strings.TrimSpace(*u.Email))
+		if err := s.repo.UpdateEmail(ctx, id, email); err != nil {
+			return nil, err
+		}
+		changes["email"] = email
+	}
+	if u.MustChangePassword != nil {
+		if err := s.repo.SetMustChangePassword(ctx, id, *u.MustChangePassword); err != nil {
+			return nil, err
+		}
+		changes["must_change_password"] = *u.MustChangePassword
+	}
+	if u.IsActive != nil {
+		if err := s.repo.SetActive(ctx, id, *u.IsActive); err != nil {
+			return nil, err
+		}
+		changes["is_active"] = *u.IsActive
+	}
+
+	if len(changes) > 0 {
+		s.audit(ctx, actorID, id, "user.update", changes)
+	}
+	return s.repo.Get(ctx, id)
+}
+
+// DeactivateUser disables sign-in and revokes every session at once; the
+// account and everything it owns stay in place for reactivation or handover.
+func (s *UserAdminService) DeactivateUser(ctx context.Context, actorID, id uuid.UUID) error {
+	if err := s.roles.CheckAuthority(ctx, actorID, id); err != nil {
+		return err
+	}
+	if err := s.guardDeactivation(ctx, actorID, id); err != nil {
+		return err
+	}
+	if err := s.repo.SetActive(ctx, id, false); err != nil {
+		return err
+	}
+	s.audit(ctx, actorID, id, "user.deactivate", nil)
+	return nil
+}
+
+func (s *UserAdminService) guardDeactivation(ctx context.Context, actorID, id uuid.UUID) error {
+	if actorID == id {
+		return fmt.Errorf("%w: you cannot deactivate your own account", domain.ErrForbidden)
+	}
+	return s.roles.GuardLastAdmin(ctx, id)
+}
+
+func (s *UserAdminService) audit(ctx context.Context, actorID, userID uuid.UUID, action string, metadata map[string]any) {
+	if err := s.auditRepo.CreateTenantLog(ctx, &domain.TenantLog{
+		TenantID:     userID,
+		ActorID:      &actorID,
+		Action:       action,
+		ResourceType: "user",
+		ResourceID:   userID.String(),
+		Metadata:     metadata,
+	}); err != nil {
+		s.logger.Error("Failed to record user management audit log", slog.Any("error", err))
+	}
+}
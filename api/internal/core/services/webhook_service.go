@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// webhookRetrySchedule is the wait before each retry; a delivery that fails
+// after the last one is marked failed and only a manual redelivery resends it.
+var webhookRetrySchedule = []time.Duration{
+	30 * time.Second, 2 * time.Minute, 10 * time.Minute, 30 * time.Minute,
+	time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour,
+}
+
+const (
+	webhookBatchSize   = 50
+	webhookConcurrency = 8
+	maxWebhooksPerUser = 20
+)
+
+// WebhookService manages user-registered outgoing webhooks and delivers Kari
+// events to them. Emitting only queues rows; the dispatcher sends them.
+type WebhookService struct {
+	repo   domain.WebhookRepository
+	apps   domain.ApplicationRepository
+	crypto domain.CryptoService
+	sender domain.WebhookSender
+	logger *slog.Logger
+
+	wake chan struct{}
+	// 🛡️ Concurrency: One dispatch pass at a time, so a delivery is never
+	// picked up twice while its first attempt is still in flight
+	dispatching sync.Mutex
+}
+
+func NewWebhookService(
+	repo domain.WebhookRepository,
+	apps domain.ApplicationRepository,
+	crypto domain.CryptoService,
+	sender domain.WebhookSender,
+	logger *slog.Logger,
+) *WebhookService {
+	return &WebhookService{
+		repo:   repo,
+		apps:   apps,
+		crypto: crypto,
+		sender: sender,
+		logger: logger,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Wake fires whenever new deliveries are queued.
+func (s *WebhookService) Wake() <-chan struct{} { return s.wake }
+
+// ==============================================================================
+// 1. Management
+// ==============================================================================
+
+func (s *WebhookService) CreateWebhook(ctx context.Context, userID uuid.UUID, w *domain.OutgoingWebhook) (*domain.OutgoingWebhook, error) {
+	if s.crypto == nil {
+		return nil, fmt.Errorf("%w: crypto service unavailable", domain.ErrUnavailable)
+	}
+	if err := validateWebhook(w); err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxWebhooksPerUser {
+		return nil, fmt.Errorf("%w: at most %d webhooks per account", domain.ErrValidation, maxWebhooksPerUser)
+	}
+
+	secret := w.Secret
+	if secret == "" {
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		secret = hex.EncodeToString(raw)
+	} else if len(secret) < 16 {
+		return nil, fmt.Errorf("%w: secret must be at least 16 characters", domain.ErrValidation)
+	}
+	ciphertext, err := s.crypto.Encrypt(ctx, []byte(secret), webhookSecretAAD(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+
+	w.UserID = userID
+	w.Enabled = true
+	if err := s.repo.Create(ctx, w, ciphertext); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	// The secret is shown once; it is never readable again
+	w.Secret = secret
+	return w, nil
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context, userID uuid.UUID) ([]domain.OutgoingWebhook, error) {
+	return s.repo.List(ctx, userID)
+}
+
+func (s *WebhookService) UpdateWebhook(ctx context.Context, userID uuid.UUID, w *domain.OutgoingWebhook) (*domain.OutgoingWebhook, error) {
+	if err := validateWebhook(w); err != nil {
+		return nil, err
+	}
+	w.UserID = userID
+	w.Secret = ""
+	if err := s.repo.Update(ctx, w); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, w.ID, userID)
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	return s.repo.Delete(ctx, id, userID)
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, id uuid.UUID, userID uuid.UUID) ([]domain.WebhookDelivery, error) {
+	if _, err := s.repo.Get(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, id, userID, domain.DefaultPageLimit)
+}
+
+// Ping queues a webhook.ping event to one webhook and sends it right away.
+func (s *WebhookService) Ping(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*domain.WebhookDelivery, error) {
+	hook, err := s.repo.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(domain.WebhookPayload{
+		EventID:   uuid.New(),
+		Event:     domain.WebhookPing,
+		CreatedAt: time.Now().UTC(),
+		Data:      map[string]string{"webhook_id": hook.ID.String()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.sendNow(ctx, hook, uuid.Nil, domain.WebhookPing, payload)
+}
+
+// Redeliver resends a past delivery's exact payload as a new delivery with
+// the same event ID, and attempts it immediately.
+func (s *WebhookService) Redeliver(ctx context.Context, id uuid.UUID, deliveryID uuid.UUID, userID uuid.UUID) (*domain.WebhookDelivery, error) {
+	hook, err := s.repo.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	past, err := s.repo.GetDelivery(ctx, deliveryID, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.sendNow(ctx, hook, past.EventID, past.Event, past.Payload)
+}
+
+func (s *WebhookService) sendNow(ctx context.Context, hook *domain.OutgoingWebhook, eventID uuid.UUID, event string, payload []byte) (*domain.WebhookDelivery, error) {
+	if eventID == uuid.Nil {
+		var p domain.WebhookPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, err
+		}
+		eventID = p.EventID
+	}
+	d := &domain.WebhookDelivery{WebhookID: hook.ID, EventID: eventID, Event: event, Payload: payload}
+	if err := s.repo.EnqueueDelivery(ctx, d); err != nil {
+		return nil, fmt.Errorf("failed to queue delivery: %w", err)
+	}
+	target, err := s.repo.Target(ctx, d.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.attempt(ctx, target)
+	return s.repo.GetDelivery(ctx, d.ID, hook.ID, hook.UserID)
+}
+
+func validateWebhook(w *domain.OutgoingWebhook) error {
+	u, err := url.Parse(w.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return fmt.Errorf("%w: url must be an https:// URL without credentials", domain.ErrValidation)
+	}
+	if len(w.Events) == 0 {
+		return fmt.Errorf("%w: subscribe to at least one event", domain.ErrValidation)
+	}
+	for _, e := range w.Events {
+		if !slices.Contains(domain.WebhookEvents, e) {
+			return fmt.Errorf("%w: unknown event %q", domain.ErrValidation, e)
+		}
+	}
+	slices.Sort(w.Events)
+	w.Events = slices.Compact(w.Events)
+	return nil
+}
+
+func webhookSecretAAD(userID uuid.UUID) []byte {
+	return []byte("outgoing_webhook:" + userID.String())
+}
+
+// ==============================================================================
+// 2. Emission
+// ==============================================================================
+
+// Emit queues event for every webhook of ownerID subscribed to it. Failures
+// are logged, never returned: a webhook must not fail the action it reports.
+func (s *WebhookService) Emit(ctx context.Context, ownerID uuid.UUID, event string, data any) {
+	hooks, err := s.repo.Subscribers(ctx, ownerID, event)
+	if err != nil {
+		s.logger.Error("failed to load webhook subscribers", slog.String("event", event), slog.Any("error", err))
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+
+	eventID := uuid.New()
+	payload, err := json.Marshal(domain.WebhookPayload{
+		EventID:   eventID,
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		s.logger.Error("failed to encode webhook payload", slog.String("event", event), slog.Any("error", err))
+		return
+	}
+
+	for _, hook := range hooks {
+		d := &domain.WebhookDelivery{WebhookID: hook.ID, EventID: eventID, Event: event, Payload: payload}
+		if err := s.repo.EnqueueDelivery(ctx, d); err != nil {
+			s.logger.Error("failed to queue webhook delivery",
+				slog.String("webhook_id", hook.ID.String()), slog.Any("error", err))
+		}
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// EmitForApp resolves the application's owner, then emits.
+func (s *WebhookService) EmitForApp(ctx context.Context, appID uuid.UUID, event string, data any) {
+	meta, err := s.apps.GetByIDWithMetadata(ctx, appID)
+	if err != nil {
+		s.logger.Error("failed to resolve app owner for webhook",
+			slog.String("app_id", appID.String()), slog.Any("error", err))
+		return
+	}
+	s.Emit(ctx, meta.OwnerID, event, data)
+}
+
+// ==============================================================================
+// 3. Delivery
+// ==============================================================================
+
+// Dispatch sends every due delivery. Overlapping calls return immediately.
+func (s *WebhookService) Dispatch(ctx context.Context) {
+	if !s.dispatching.TryLock() {
+		return
+	}
+	defer s.dispatching.Unlock()
+
+	for {
+		due, err := s.repo.DueDeliveries(ctx, webhookBatchSize)
+		if err != nil {
+			s.logger.Error("failed to load due webhook deliveries", slog.Any("error", err))
+			return
+		}
+
+		sem := make(chan struct{}, webhookConcurrency)
+		var wg sync.WaitGroup
+		for i := range due {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(t *domain.WebhookTarget) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				s.attempt(ctx, t)
+			}(&due[i])
+		}
+		wg.Wait()
+
+		if len(due) < webhookBatchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// attempt makes one POST and records the outcome and the next retry.
+func (s *WebhookService) attempt(ctx context.Context, t *domain.WebhookTarget) {
+	d := t.Delivery
+	var result domain.WebhookAttempt
+
+	if s.crypto == nil {
+		result.Err = fmt.Errorf("%w: crypto service unavailable", domain.ErrUnavailable)
+	} else if secret, err := s.crypto.Decrypt(ctx, t.SecretCiphertext, webhookSecretAAD(t.UserID)); err != nil {
+		result.Err = fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	} else {
+		result = s.sender.Send(ctx, t.URL, secret, map[string]string{
+			"X-Kari-Event":    d.Event,
+			"X-Kari-Delivery": d.ID.String(),
+		}, d.Payload)
+	}
+
+	status := domain.DeliveryDelivered
+	var next *time.Time
+	if result.Err != nil {
+		status = domain.DeliveryFailed
+		if d.Attempts < len(webhookRetrySchedule) {
+			status = domain.DeliveryPending
+			at := time.Now().Add(webhookRetrySchedule[d.Attempts])
+			next = &at
+		}
+		s.logger.Warn("webhook delivery failed",
+			slog.String("delivery_id", d.ID.String()),
+			slog.String("event", d.Event),
+			slog.Int("attempt", d.Attempts+1),
+			slog.Any("error", result.Err))
+	}
+
+	if err := s.repo.RecordAttempt(ctx, d.ID, status, result, next); err != nil {
+		s.logger.Error("failed to record webhook attempt", slog.String("delivery_id", d.ID.String()), slog.Any("error", err))
+	}
+}
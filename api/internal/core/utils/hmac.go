@@ -1,12 +1,15 @@
 package utils
 
 import (
+	"crypto/ed25519"
 	"crypto/hmac"
 	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/hex"
 	"errors"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -55,3 +58,100 @@ func VerifyGitHubSignature(rawBody []byte, signatureHeader string, secret []byte
 
 	return nil
 }
+
+// VerifyGitLabToken compares the X-Gitlab-Token header against the hook
+// secret in constant time. GitLab does not sign payloads.
+func VerifyGitLabToken(tokenHeader string, secret []byte) error {
+	if len(secret) < 16 {
+		return errors.New("webhook secret entropy too low")
+	}
+	if tokenHeader == "" {
+		return errors.New("missing token header")
+	}
+	if subtle.ConstantTimeCompare([]byte(tokenHeader), secret) != 1 {
+		return errors.New("webhook token mismatch")
+	}
+	return nil
+}
+
+// ChatReplayWindow bounds how old a signed chat request may be.
+const ChatReplayWindow = 5 * time.Minute
+
+// VerifySlackSignature checks Slack's v0 request signature: an HMAC over
+// "v0:{X-Slack-Request-Timestamp}:{body}" sent as X-Slack-Signature "v0=HEX".
+// Requests outside ChatReplayWindow are rejected so a captured one can't be replayed.
+func VerifySlackSignature(rawBody []byte, timestampHeader, signatureHeader string, secret []byte, now time.Time) error {
+	if len(secret) < 16 {
+		return errors.New("signing secret entropy too low")
+	}
+	if timestampHeader == "" || signatureHeader == "" {
+		return errors.New("missing signature headers")
+	}
+
+	ts, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return errors.New("invalid request timestamp")
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > ChatReplayWindow || age < -ChatReplayWindow {
+		return errors.New("request timestamp outside replay window")
+	}
+
+	const prefix = "v0="
+	if !strings.HasPrefix(signatureHeader, prefix) {
+		return errors.New("unsupported signature version")
+	}
+	providedMAC, err := hex.DecodeString(strings.TrimPrefix(signatureHeader, prefix))
+	if err != nil {
+		return errors.New("invalid signature encoding")
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("v0:" + timestampHeader + ":"))
+	mac.Write(rawBody)
+	if subtle.ConstantTimeCompare(mac.Sum(nil), providedMAC) != 1 {
+		return errors.New("slack signature mismatch")
+	}
+	return nil
+}
+
+// VerifyDiscordSignature checks a Discord interaction: an Ed25519 signature
+// (X-Signature-Ed25519, hex) over timestamp + body, made with the key whose
+// public half is the application's public key. Stale timestamps are rejected
+// with the same window.
+func VerifyDiscordSignature(rawBody []byte, timestampHeader, signatureHeader string, publicKey ed25519.PublicKey, now time.Time) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return errors.New("invalid application public key")
+	}
+	if timestampHeader == "" || signatureHeader == "" {
+		return errors.New("missing signature headers")
+	}
+
+	ts, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return errors.New("invalid request timestamp")
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > ChatReplayWindow || age < -ChatReplayWindow {
+		return errors.New("request timestamp outside replay window")
+	}
+
+	sig, err := hex.DecodeString(signatureHeader)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return errors.New("invalid signature encoding")
+	}
+
+	msg := make([]byte, 0, len(timestampHeader)+len(rawBody))
+	msg = append(msg, timestampHeader...)
+	msg = append(msg, rawBody...)
+	if !ed25519.Verify(publicKey, msg, sig) {
+		return errors.New("discord signature mismatch")
+	}
+	return nil
+}
+
+// SignPayload produces the "sha256=HEX_DIGEST" signature Kari attaches to its
+// own outgoing webhooks (same scheme as GitHub, so receivers can reuse code).
+func SignPayload(rawBody []byte, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(rawBody)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,29 @@
+-- api/internal/db/migrations/004_idempotency_keys.sql
+-- Focus: Safe client retries for mutating endpoints (Idempotency-Key header)
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    -- 🛡️ Tenant Isolation: A key is only meaningful for the user who sent it
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    idempotency_key VARCHAR(255) NOT NULL,
+
+    -- Fingerprint of the original request (method + path + SHA-256 of body).
+    -- A retry with the same key but a different payload is rejected.
+    request_hash CHAR(64) NOT NULL,
+
+    -- NULL while the first request is still executing ("in flight")
+    status_code INTEGER,
+    response_body BYTEA,
+    content_type VARCHAR(255),
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL DEFAULT NOW() + INTERVAL '24 hours',
+
+    PRIMARY KEY (user_id, idempotency_key)
+);
+
+-- 🛡️ Performance: Supports the periodic purge of expired keys
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+COMMIT;
@@ -0,0 +1,36 @@
+-- api/internal/db/migrations/005_tenant_logs_pagination.sql
+-- Focus: Tenant audit trail + keyset pagination indexes for list endpoints
+
+BEGIN;
+
+-- ==============================================================================
+-- 1. Tenant Logs (Per-tenant audit trail behind GET /api/v1/audit)
+-- ==============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- 🛡️ Tenant Isolation: The owner of the affected resource
+    tenant_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- The user who performed the action (NULL for system actions)
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(100) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id VARCHAR(255) NOT NULL,
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_tenant_logs_tenant_page ON tenant_logs (tenant_id, created_at DESC, id DESC);
+CREATE INDEX idx_tenant_logs_actor ON tenant_logs (actor_id, created_at DESC);
+
+-- ==============================================================================
+-- 2. Keyset Pagination Indexes
+-- 🛡️ Performance: (sort column, id) tuples let "WHERE (created_at, id) < ($1, $2)"
+-- seek directly to the next page instead of scanning OFFSET rows.
+-- ==============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_applications_page ON applications (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_domains_page ON domains (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_system_alerts_page ON system_alerts (created_at DESC, id DESC);
+
+COMMIT;
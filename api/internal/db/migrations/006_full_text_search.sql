@@ -0,0 +1,30 @@
+-- api/internal/db/migrations/006_full_text_search.sql
+-- Focus: Global search (GET /api/v1/search) over apps, domains, deployment logs, and audit entries
+
+BEGIN;
+
+-- 🛡️ Performance: STORED generated columns keep the tsvector in sync without
+-- triggers, and GIN indexes make websearch_to_tsquery lookups index-only.
+-- The 'simple' dictionary avoids stemming hostnames, repo paths, and log tokens.
+
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('simple', coalesce(app_user, '') || ' ' || coalesce(repo_url, '') || ' ' || coalesce(branch, ''))
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_applications_search ON applications USING GIN (search_vector);
+
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', coalesce(name, ''))) STORED;
+CREATE INDEX IF NOT EXISTS idx_domains_search ON domains USING GIN (search_vector);
+
+ALTER TABLE deployment_logs ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', coalesce(content, ''))) STORED;
+CREATE INDEX IF NOT EXISTS idx_deployment_logs_search ON deployment_logs USING GIN (search_vector);
+
+ALTER TABLE tenant_logs ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('simple', replace(action, '.', ' ') || ' ' || resource_type || ' ' || resource_id)
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_tenant_logs_search ON tenant_logs USING GIN (search_vector);
+
+COMMIT;
@@ -0,0 +1,62 @@
+-- api/internal/db/migrations/007_tenant_log_chain.sql
+-- Focus: Tamper-evident audit trail (SHA-256 hash chaining over tenant_logs)
+
+BEGIN;
+
+-- ==============================================================================
+-- 1. Chain Columns
+-- ==============================================================================
+
+ALTER TABLE tenant_logs ADD COLUMN IF NOT EXISTS chain_seq BIGINT;
+ALTER TABLE tenant_logs ADD COLUMN IF NOT EXISTS prev_hash CHAR(64);
+ALTER TABLE tenant_logs ADD COLUMN IF NOT EXISTS row_hash CHAR(64);
+
+-- Legacy rows get a position in the chain but no hash; the verifier reports
+-- them as "unchained" rather than tampered.
+UPDATE tenant_logs t
+SET chain_seq = s.seq
+FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY created_at, id) AS seq FROM tenant_logs) s
+WHERE t.id = s.id;
+
+ALTER TABLE tenant_logs ALTER COLUMN chain_seq SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenant_logs_chain_seq ON tenant_logs (chain_seq);
+
+-- ==============================================================================
+-- 2. Chain Head (single row)
+-- 🛡️ Stability: SELECT ... FOR UPDATE on this row serializes appends across
+-- Brain replicas, and lets the verifier detect rows deleted from the tail.
+-- ==============================================================================
+
+CREATE TABLE IF NOT EXISTS tenant_log_chain_head (
+    id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+    last_seq BIGINT NOT NULL,
+    last_hash CHAR(64) NOT NULL
+);
+
+INSERT INTO tenant_log_chain_head (id, last_seq, last_hash)
+SELECT true, COALESCE(MAX(chain_seq), 0), repeat('0', 64) FROM tenant_logs
+ON CONFLICT (id) DO NOTHING;
+
+-- ==============================================================================
+-- 3. Immutability
+-- 🛡️ Zero-Trust: Audit rows are append-only. DELETE stays allowed for the
+-- retention pruner, which archives rows before removing them.
+-- ==============================================================================
+
+CREATE OR REPLACE FUNCTION tenant_logs_block_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'tenant_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS tenant_logs_immutable ON tenant_logs;
+CREATE TRIGGER tenant_logs_immutable
+BEFORE UPDATE ON tenant_logs FOR EACH ROW EXECUTE FUNCTION tenant_logs_block_update();
+
+-- Audit rows outlive the accounts they name. 005's ON DELETE SET NULL would
+-- be an UPDATE the trigger rejects (and a rewrite of hashed rows), and
+-- CASCADE would cut rows out of the chain, so the user FKs go.
+ALTER TABLE tenant_logs DROP CONSTRAINT IF EXISTS tenant_logs_tenant_id_fkey;
+ALTER TABLE tenant_logs DROP CONSTRAINT IF EXISTS tenant_logs_actor_id_fkey;
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- api/internal/db/migrations/008_audit_sink_config.sql
+-- Focus: Admin-configured SIEM forwarding for audit events
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS audit_sink_config (
+    id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id), -- Single-row table
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('syslog', 'splunk_hec', 'https')),
+    endpoint VARCHAR(1024) NOT NULL,
+    network VARCHAR(10) CHECK (network IN ('udp', 'tcp', 'tls')),
+    -- 🛡️ Zero-Trust: HEC/bearer tokens are AES-GCM encrypted by the Brain
+    token_ciphertext TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
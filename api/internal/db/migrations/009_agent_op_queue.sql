@@ -0,0 +1,23 @@
+-- api/internal/db/migrations/009_agent_op_queue.sql
+-- Focus: Durable queue for mutating agent intents issued while the Muscle is offline
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS agent_op_queue (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- 🛡️ Stability: Replay strictly follows submission order
+    seq BIGSERIAL NOT NULL UNIQUE,
+    method VARCHAR(200) NOT NULL,           -- Full gRPC method name
+    -- 🛡️ Zero-Trust: Protobuf payloads (e.g. private keys) are AES-GCM encrypted by the Brain
+    payload_ciphertext TEXT NOT NULL,
+    description VARCHAR(255) NOT NULL,      -- UI-safe summary for the Action Center
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'done', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_agent_op_queue_pending ON agent_op_queue (seq) WHERE status = 'pending';
+
+COMMIT;
@@ -0,0 +1,27 @@
+-- api/internal/db/migrations/010_app_uid_ledger.sql
+-- Focus: Transactional allocation and safe recycling of app jail UIDs
+
+BEGIN;
+
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS app_uid INTEGER UNIQUE;
+
+-- ==============================================================================
+-- UID Ledger
+-- 🛡️ Tenant Isolation: A UID is never handed to a new app while files or
+-- processes of its previous owner could still exist on disk.
+--   allocated   -> bound to app_id (app_id is NULLed if the app row vanishes
+--                  without a confirmed teardown; such UIDs are never recycled)
+--   quarantined -> teardown confirmed by the Muscle; reusable after cooldown
+-- ==============================================================================
+
+CREATE TABLE IF NOT EXISTS app_uid_ledger (
+    uid INTEGER PRIMARY KEY CHECK (uid >= 1000),
+    app_id UUID UNIQUE REFERENCES applications(id) ON DELETE SET NULL,
+    state VARCHAR(20) NOT NULL DEFAULT 'allocated' CHECK (state IN ('allocated', 'quarantined')),
+    allocated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    released_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_app_uid_ledger_recyclable ON app_uid_ledger (uid) WHERE state = 'quarantined';
+
+COMMIT;
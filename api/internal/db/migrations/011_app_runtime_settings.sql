@@ -0,0 +1,13 @@
+-- api/internal/db/migrations/011_app_runtime_settings.sql
+-- Focus: Persist the app runtime type and per-app runtime tuning (PHP-FPM pools)
+
+BEGIN;
+
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS app_type VARCHAR(20) NOT NULL DEFAULT 'nodejs'
+    CHECK (app_type IN ('nodejs', 'python', 'php', 'ruby', 'static'));
+
+-- Runtime tuning knobs (e.g. php_max_children). Validated by the API; the
+-- Muscle re-checks bounds before rendering any pool config.
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS settings JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+COMMIT;
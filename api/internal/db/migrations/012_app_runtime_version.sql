@@ -0,0 +1,15 @@
+-- api/internal/db/migrations/012_app_runtime_version.sql
+-- Focus: Per-application runtime version pinning
+
+BEGIN;
+
+ALTER TABLE applications DROP CONSTRAINT IF EXISTS applications_app_type_check;
+ALTER TABLE applications ADD CONSTRAINT applications_app_type_check
+    CHECK (app_type IN ('nodejs', 'python', 'go', 'php', 'ruby', 'static'));
+
+-- '' = follow the SystemProfile stack registry default. Pins are validated
+-- against the API's supported-version catalogue, not here, so adding a
+-- version never needs a migration.
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS runtime_version VARCHAR(20) NOT NULL DEFAULT '';
+
+COMMIT;
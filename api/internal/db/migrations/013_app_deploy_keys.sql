@@ -0,0 +1,21 @@
+-- api/internal/db/migrations/013_app_deploy_keys.sql
+-- Focus: Per-application SSH deploy keys for private Git repositories
+
+BEGIN;
+
+-- ==============================================================================
+-- Deploy Keys
+-- 🛡️ Privacy: Only the public half is ever returned by the API. The private
+-- half is AES-GCM ciphertext bound to the app ID (AAD), the same binding the
+-- deployment worker uses for deployments.encrypted_ssh_key.
+-- ==============================================================================
+
+CREATE TABLE IF NOT EXISTS app_deploy_keys (
+    app_id UUID PRIMARY KEY REFERENCES applications(id) ON DELETE CASCADE,
+    public_key TEXT NOT NULL,              -- authorized_keys format
+    fingerprint VARCHAR(64) NOT NULL,      -- SHA256:... as shown by GitHub/GitLab
+    private_key_ciphertext TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
@@ -0,0 +1,26 @@
+-- api/internal/db/migrations/014_git_connections.sql
+-- Focus: GitHub/GitLab OAuth connections and auto-configured push webhooks
+
+BEGIN;
+
+-- ==============================================================================
+-- Git Provider Connections (one per user per provider)
+-- 🛡️ Privacy: Tokens are AES-GCM ciphertext bound to "git_connection:<user>:<provider>".
+-- ==============================================================================
+
+CREATE TABLE IF NOT EXISTS git_connections (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL CHECK (provider IN ('github', 'gitlab')),
+    account_login VARCHAR(255) NOT NULL,
+    access_token_ciphertext TEXT NOT NULL,
+    refresh_token_ciphertext TEXT,            -- GitLab only; GitHub OAuth app tokens do not expire
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, provider)
+);
+
+-- Push webhook secret, generated when the app is linked to a provider repo
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS webhook_secret_ciphertext TEXT;
+
+COMMIT;
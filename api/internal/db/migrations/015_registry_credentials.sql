@@ -0,0 +1,35 @@
+-- api/internal/db/migrations/015_registry_credentials.sql
+-- Focus: Encrypted container registry credentials for image-based apps
+
+BEGIN;
+
+-- ==============================================================================
+-- Registry Credentials (tenant-owned)
+-- 🛡️ Privacy: password/token is AES-GCM ciphertext bound to the credential ID.
+-- ==============================================================================
+
+CREATE TABLE IF NOT EXISTS registry_credentials (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    registry VARCHAR(255) NOT NULL,          -- Host, e.g. docker.io, ghcr.io, registry.example.com:5000
+    username VARCHAR(255) NOT NULL,
+    password_ciphertext TEXT NOT NULL,
+    last_tested_at TIMESTAMPTZ,
+    last_test_ok BOOLEAN,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (owner_id, name)
+);
+
+CREATE INDEX idx_registry_credentials_owner ON registry_credentials (owner_id);
+
+-- Image-based apps pull a prebuilt image instead of building from Git
+ALTER TABLE applications DROP CONSTRAINT IF EXISTS applications_app_type_check;
+ALTER TABLE applications ADD CONSTRAINT applications_app_type_check
+    CHECK (app_type IN ('nodejs', 'python', 'go', 'php', 'ruby', 'static', 'image'));
+
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS image_ref VARCHAR(512) NOT NULL DEFAULT '';
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS registry_credential_id UUID
+    REFERENCES registry_credentials(id) ON DELETE SET NULL;
+
+COMMIT;
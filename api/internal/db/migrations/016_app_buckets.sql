@@ -0,0 +1,20 @@
+-- api/internal/db/migrations/016_app_buckets.sql
+-- Focus: Per-app S3-compatible buckets (MinIO) with scoped credentials
+
+BEGIN;
+
+-- ==============================================================================
+-- App Buckets (one per application)
+-- 🛡️ Privacy: secret key is AES-GCM ciphertext bound to the app ID.
+-- ==============================================================================
+
+CREATE TABLE IF NOT EXISTS app_buckets (
+    app_id UUID PRIMARY KEY REFERENCES applications(id) ON DELETE CASCADE,
+    bucket VARCHAR(63) NOT NULL UNIQUE,      -- S3 naming rules cap names at 63 chars
+    access_key VARCHAR(128) NOT NULL UNIQUE, -- MinIO user scoped to this bucket only
+    secret_key_ciphertext TEXT NOT NULL,
+    quota_bytes BIGINT NOT NULL CHECK (quota_bytes > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- api/internal/db/migrations/017_app_processes.sql
+-- Focus: Multi-process apps (web + worker + scheduler)
+
+BEGIN;
+
+-- name -> {command, cpu_percent, memory_mb}; '{}' = legacy single start_command
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS processes JSONB NOT NULL DEFAULT '{}';
+
+COMMIT;
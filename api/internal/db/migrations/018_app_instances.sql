@@ -0,0 +1,10 @@
+-- api/internal/db/migrations/018_app_instances.sql
+-- Focus: Horizontal scaling of the web process behind the proxy
+
+BEGIN;
+
+-- Copies of "web" on port, port+1, ...; the proxy load-balances across them
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS instances INT NOT NULL DEFAULT 1
+    CHECK (instances BETWEEN 1 AND 16);
+
+COMMIT;
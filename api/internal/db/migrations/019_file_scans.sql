@@ -0,0 +1,23 @@
+-- api/internal/db/migrations/019_file_scans.sql
+-- Focus: Integrity & malware scan history for app directories
+
+BEGIN;
+
+-- ==============================================================================
+-- File Scans (one row per scan run; findings feed the Action Center)
+-- ==============================================================================
+
+CREATE TABLE IF NOT EXISTS file_scans (
+    id UUID PRIMARY KEY,
+    app_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('clean', 'findings', 'baseline', 'failed')),
+    files_scanned BIGINT NOT NULL DEFAULT 0,
+    findings JSONB NOT NULL DEFAULT '[]',
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_scans_app_started ON file_scans (app_id, started_at DESC);
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- api/internal/db/migrations/020_domain_security_headers.sql
+-- Focus: Per-domain security response headers (CSP, X-Frame-Options, ...)
+
+BEGIN;
+
+-- One policy per domain; absent row = no headers beyond the vhost defaults
+CREATE TABLE IF NOT EXISTS domain_security_headers (
+    domain_id UUID PRIMARY KEY REFERENCES domains(id) ON DELETE CASCADE,
+    settings JSONB NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
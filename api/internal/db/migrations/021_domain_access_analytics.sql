@@ -0,0 +1,40 @@
+-- api/internal/db/migrations/021_domain_access_analytics.sql
+-- Focus: Hourly per-domain access rollups fed by the Muscle's log reader
+
+BEGIN;
+
+-- Totals per domain-hour; status classes are fixed columns for cheap sums
+CREATE TABLE IF NOT EXISTS domain_access_hourly (
+    domain_id UUID NOT NULL REFERENCES domains(id) ON DELETE CASCADE,
+    hour TIMESTAMPTZ NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    bytes_sent BIGINT NOT NULL DEFAULT 0,
+    status_2xx BIGINT NOT NULL DEFAULT 0,
+    status_3xx BIGINT NOT NULL DEFAULT 0,
+    status_4xx BIGINT NOT NULL DEFAULT 0,
+    status_5xx BIGINT NOT NULL DEFAULT 0,
+    status_other BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (domain_id, hour)
+);
+
+-- Top paths and (anonymized) clients per hour; only the Muscle's top-N land here
+CREATE TABLE IF NOT EXISTS domain_access_top (
+    domain_id UUID NOT NULL REFERENCES domains(id) ON DELETE CASCADE,
+    hour TIMESTAMPTZ NOT NULL,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('path', 'client')),
+    key TEXT NOT NULL,
+    hits BIGINT NOT NULL,
+    PRIMARY KEY (domain_id, hour, kind, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_domain_access_hourly_hour ON domain_access_hourly(hour);
+CREATE INDEX IF NOT EXISTS idx_domain_access_top_hour ON domain_access_top(hour);
+
+-- Byte offset into each domain's access log; reset by the Muscle on rotation
+CREATE TABLE IF NOT EXISTS domain_access_cursors (
+    domain_id UUID PRIMARY KEY REFERENCES domains(id) ON DELETE CASCADE,
+    log_offset BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
@@ -0,0 +1,29 @@
+-- api/internal/db/migrations/022_app_bandwidth.sql
+-- Focus: Monthly per-app transfer accounting and optional transfer quotas
+
+BEGIN;
+
+-- Request sizes from the kari_combined log format (bandwidth in)
+ALTER TABLE domain_access_hourly ADD COLUMN IF NOT EXISTS bytes_received BIGINT NOT NULL DEFAULT 0;
+
+-- Kept indefinitely: hourly rollups are pruned, monthly totals back invoices
+CREATE TABLE IF NOT EXISTS app_bandwidth_monthly (
+    app_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    month DATE NOT NULL, -- First day of the month, UTC
+    bytes_in BIGINT NOT NULL DEFAULT 0,
+    bytes_out BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (app_id, month)
+);
+
+-- state/enforced_month track what was done about the current month's overage
+CREATE TABLE IF NOT EXISTS app_transfer_quotas (
+    app_id UUID PRIMARY KEY REFERENCES applications(id) ON DELETE CASCADE,
+    quota_gb INT NOT NULL CHECK (quota_gb > 0),
+    action VARCHAR(10) NOT NULL DEFAULT 'alert' CHECK (action IN ('alert', 'throttle')),
+    throttle_kbps INT NOT NULL DEFAULT 256 CHECK (throttle_kbps > 0),
+    state VARCHAR(10) NOT NULL DEFAULT 'ok' CHECK (state IN ('ok', 'alerted', 'throttled')),
+    enforced_month DATE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
@@ -0,0 +1,40 @@
+-- api/internal/db/migrations/023_usage_metering.sql
+-- Focus: Monthly per-app usage meters for reseller billing exports
+
+BEGIN;
+
+-- No FK to applications: a deleted app's month still has to be billed
+CREATE TABLE IF NOT EXISTS usage_meter_monthly (
+    app_id UUID NOT NULL,
+    tenant_id UUID NOT NULL,
+    domain_name TEXT NOT NULL,         -- Snapshot for invoices after deletion
+    month DATE NOT NULL,               -- First day of the month, UTC
+    cpu_seconds DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ram_gb_hours DOUBLE PRECISION NOT NULL DEFAULT 0,
+    storage_gb_hours DOUBLE PRECISION NOT NULL DEFAULT 0,
+    bandwidth_bytes BIGINT NOT NULL DEFAULT 0, -- Copied from app_bandwidth_monthly
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (app_id, month)
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_meter_month_tenant ON usage_meter_monthly(month, tenant_id);
+
+-- Last reading per metered source (one per process unit, plus the bucket)
+CREATE TABLE IF NOT EXISTS usage_sample_cursors (
+    source_key TEXT PRIMARY KEY,
+    app_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    counter BIGINT NOT NULL DEFAULT 0,  -- Cumulative CPU ns for process units
+    started_at TIMESTAMPTZ,             -- Unit start; a change means the counter reset
+    sampled_at TIMESTAMPTZ NOT NULL
+);
+
+-- One webhook delivery per closed month
+CREATE TABLE IF NOT EXISTS usage_exports (
+    month DATE PRIMARY KEY,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('delivered', 'failed')),
+    attempts INT NOT NULL DEFAULT 1,
+    error TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
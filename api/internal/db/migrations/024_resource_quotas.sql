@@ -0,0 +1,36 @@
+-- api/internal/db/migrations/024_resource_quotas.sql
+-- Focus: Per-role resource quotas (apps, domains, memory) with per-user admin overrides
+
+BEGIN;
+
+-- NULL = unlimited. Roles without a row are unlimited too.
+CREATE TABLE IF NOT EXISTS role_resource_limits (
+    role_id UUID PRIMARY KEY REFERENCES roles(id) ON DELETE CASCADE,
+    max_applications INT CHECK (max_applications >= 0),
+    max_domains INT CHECK (max_domains >= 0),
+    max_memory_mb INT CHECK (max_memory_mb >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- NULL = inherit the role's limit; exempt lifts every limit for the user
+CREATE TABLE IF NOT EXISTS user_resource_overrides (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    max_applications INT CHECK (max_applications >= 0),
+    max_domains INT CHECK (max_domains >= 0),
+    max_memory_mb INT CHECK (max_memory_mb >= 0),
+    exempt BOOLEAN NOT NULL DEFAULT false,
+    reason TEXT NOT NULL DEFAULT '',
+    set_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO permissions (resource, action, description) VALUES
+    ('quotas', 'manage', 'Set role resource quotas and per-user overrides')
+ON CONFLICT (resource, action) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'Super Admin' AND p.resource = 'quotas'
+ON CONFLICT DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- api/internal/db/migrations/025_system_settings.sql
+-- Focus: Panel-wide settings, starting with read-only maintenance mode
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS system_settings (
+    id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id), -- Single-row table
+    maintenance_enabled BOOLEAN NOT NULL DEFAULT false,
+    maintenance_message TEXT NOT NULL DEFAULT '',
+    maintenance_since TIMESTAMPTZ,
+    maintenance_set_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO system_settings (id) VALUES (true) ON CONFLICT (id) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- api/internal/db/migrations/026_crash_reports.sql
+-- Focus: Recovered panics from HTTP handlers and worker goroutines
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS crash_reports (
+    id UUID PRIMARY KEY,
+    source TEXT NOT NULL CHECK (source IN ('http', 'worker')),
+    component TEXT NOT NULL,            -- Route pattern or worker name
+    panic TEXT NOT NULL,
+    stack TEXT NOT NULL,
+    request JSONB,                      -- NULL for worker crashes
+    recent_logs JSONB NOT NULL DEFAULT '[]',
+    exported_at TIMESTAMPTZ,            -- Set once the Sentry-compatible sink accepted it
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_crash_reports_created ON crash_reports (created_at DESC);
+
+COMMIT;
@@ -0,0 +1,19 @@
+-- api/internal/db/migrations/027_redaction_rules.sql
+-- Focus: Custom redaction patterns applied to deployment logs and audit metadata
+
+BEGIN;
+
+-- tenant_id NULL = platform-wide rule managed by admins
+CREATE TABLE IF NOT EXISTS redaction_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    pattern TEXT NOT NULL,              -- RE2 syntax, validated by the Brain
+    replacement TEXT NOT NULL DEFAULT '[REDACTED]',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_redaction_rules_tenant ON redaction_rules (tenant_id);
+
+COMMIT;
@@ -0,0 +1,31 @@
+-- api/internal/db/migrations/028_passkeys.sql
+-- Focus: WebAuthn passkeys alongside passwords, with a Rank-0 passkey requirement
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_passkeys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    credential_id BYTEA NOT NULL UNIQUE,
+    credential JSONB NOT NULL,          -- Public key, sign count, flags (webauthn.Credential)
+    name TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_passkeys_user ON user_passkeys (user_id);
+
+-- Challenge state between the begin and finish calls of a ceremony
+CREATE TABLE IF NOT EXISTS webauthn_sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE, -- NULL for discoverable login
+    ceremony TEXT NOT NULL CHECK (ceremony IN ('registration', 'login')),
+    data JSONB NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+ALTER TABLE system_settings
+    ADD COLUMN IF NOT EXISTS passkey_require_rank0 BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS passkey_password_fallback BOOLEAN NOT NULL DEFAULT true;
+
+COMMIT;
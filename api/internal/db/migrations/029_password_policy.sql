@@ -0,0 +1,15 @@
+-- api/internal/db/migrations/029_password_policy.sql
+-- Focus: Password policy settings, password age tracking, forced change at next login
+
+BEGIN;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE system_settings
+    ADD COLUMN IF NOT EXISTS password_min_length INT NOT NULL DEFAULT 12 CHECK (password_min_length BETWEEN 8 AND 72),
+    ADD COLUMN IF NOT EXISTS password_max_age_days INT NOT NULL DEFAULT 0 CHECK (password_max_age_days >= 0), -- 0 = never expires
+    ADD COLUMN IF NOT EXISTS password_breach_check BOOLEAN NOT NULL DEFAULT true;
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- api/internal/db/migrations/030_user_management.sql
+-- Focus: Admin user management (create, edit, deactivate) with its own permissions
+
+BEGIN;
+
+-- Set when an admin deactivates the account; cleared on reactivation
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMPTZ;
+
+INSERT INTO permissions (resource, action, description) VALUES
+    ('users', 'read', 'List user accounts'),
+    ('users', 'manage', 'Create, edit and deactivate user accounts')
+ON CONFLICT (resource, action) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'Super Admin' AND p.resource = 'users'
+ON CONFLICT DO NOTHING;
+
+COMMIT;
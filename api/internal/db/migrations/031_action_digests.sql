@@ -0,0 +1,26 @@
+-- api/internal/db/migrations/031_action_digests.sql
+-- Focus: Action Center digest emails (per-admin preferences, idempotent delivery ledger)
+
+BEGIN;
+
+-- One row per admin, created on the first digest run. The token backs the
+-- unsubscribe link, so it must stay unguessable.
+CREATE TABLE IF NOT EXISTS digest_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    frequency VARCHAR(10) NOT NULL DEFAULT 'weekly' CHECK (frequency IN ('off', 'daily', 'weekly')),
+    unsubscribe_token TEXT NOT NULL UNIQUE DEFAULT encode(gen_random_bytes(24), 'hex'),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- period is '2026-10-15' (daily) or '2026-W42' (weekly). A row is claimed
+-- before sending; sent_at is stamped after, so a crash mid-send is retried
+-- once the claim goes stale instead of mailing twice.
+CREATE TABLE IF NOT EXISTS digest_deliveries (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    period VARCHAR(10) NOT NULL,
+    claimed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ,
+    PRIMARY KEY (user_id, period)
+);
+
+COMMIT;
@@ -0,0 +1,18 @@
+-- api/internal/db/migrations/032_deployment_log_archives.sql
+-- Focus: Build logs of finished deployments moved to object storage as gzip
+
+BEGIN;
+
+-- A row here means the deployment_logs chunks were uploaded and deleted
+CREATE TABLE IF NOT EXISTS deployment_log_archives (
+    deployment_id UUID PRIMARY KEY REFERENCES deployments(id) ON DELETE CASCADE,
+    object_key TEXT NOT NULL,
+    compressed_bytes BIGINT NOT NULL,
+    chunks INT NOT NULL,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- ListArchivable walks deployments by age, then probes for log rows
+CREATE INDEX IF NOT EXISTS idx_deployment_logs_deployment_id ON deployment_logs(deployment_id, id);
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- api/internal/db/migrations/033_deployment_log_levels.sql
+-- Focus: Structured build log messages (level + stage) for filtering and colour-coding
+
+BEGIN;
+
+-- Existing rows predate levels; they read as plain build output
+ALTER TABLE deployment_logs
+    ADD COLUMN IF NOT EXISTS level TEXT NOT NULL DEFAULT 'info'
+        CHECK (level IN ('info', 'warn', 'error')),
+    ADD COLUMN IF NOT EXISTS stage TEXT NOT NULL DEFAULT 'build';
+
+-- "Show me only the errors of this deployment"
+CREATE INDEX IF NOT EXISTS idx_deployment_logs_level ON deployment_logs(deployment_id, level);
+
+COMMIT;
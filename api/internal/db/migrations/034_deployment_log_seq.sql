@@ -0,0 +1,35 @@
+-- api/internal/db/migrations/034_deployment_log_seq.sql
+-- Focus: Per-deployment log sequence numbers for seekable, paginated log reads
+
+BEGIN;
+
+-- deployments.log_seq is the high-water mark; writers bump it under the row
+-- lock, so sequence numbers commit in order and ?after_seq= never skips a row
+ALTER TABLE deployments
+    ADD COLUMN IF NOT EXISTS log_seq BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE deployment_logs
+    ADD COLUMN IF NOT EXISTS seq BIGINT;
+
+-- Backfill existing rows in their original write order
+UPDATE deployment_logs l
+SET seq = n.rn
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY deployment_id ORDER BY id) AS rn
+    FROM deployment_logs
+) n
+WHERE l.id = n.id AND l.seq IS NULL;
+
+UPDATE deployments d
+SET log_seq = m.max_seq
+FROM (
+    SELECT deployment_id, MAX(seq) AS max_seq FROM deployment_logs GROUP BY deployment_id
+) m
+WHERE d.id = m.deployment_id;
+
+ALTER TABLE deployment_logs ALTER COLUMN seq SET NOT NULL;
+
+-- Serves "WHERE deployment_id = $1 AND seq > $2 ORDER BY seq LIMIT n"
+CREATE UNIQUE INDEX IF NOT EXISTS idx_deployment_logs_seq ON deployment_logs(deployment_id, seq);
+
+COMMIT;
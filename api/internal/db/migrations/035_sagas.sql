@@ -0,0 +1,25 @@
+-- api/internal/db/migrations/035_sagas.sql
+-- Focus: Persisted saga state so multi-step provisioning can roll back after a restart
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS sagas (
+    id UUID PRIMARY KEY,
+    kind VARCHAR(64) NOT NULL,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'running'
+        CHECK (status IN ('running', 'succeeded', 'rolled_back', 'failed')),
+    -- Step names and states, in execution order
+    steps JSONB NOT NULL DEFAULT '[]',
+    -- Identifiers of created resources; compensations read them back after a
+    -- crash. Never request payloads, which may carry secrets.
+    data JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Startup recovery scans only what a crash interrupted
+CREATE INDEX IF NOT EXISTS idx_sagas_running ON sagas(created_at) WHERE status = 'running';
+CREATE INDEX IF NOT EXISTS idx_sagas_finished ON sagas(updated_at) WHERE status <> 'running';
+
+COMMIT;
@@ -0,0 +1,28 @@
+-- api/internal/db/migrations/036_drift_findings.sql
+-- Focus: Host resources out of step with the database, found by the drift reconciler
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS drift_findings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(32) NOT NULL
+        CHECK (kind IN ('orphan_unit', 'orphan_vhost', 'orphan_jail', 'orphan_certificate', 'missing_unit', 'missing_vhost')),
+    resource TEXT NOT NULL,
+    domain_name TEXT,
+    app_id UUID REFERENCES applications(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'resolved', 'adopted', 'cleaned')),
+    alert_id UUID REFERENCES system_alerts(id) ON DELETE SET NULL,
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    settled_at TIMESTAMPTZ,
+    settled_by UUID REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- One open finding per resource: a persisting drift is refreshed, not re-reported
+CREATE UNIQUE INDEX IF NOT EXISTS idx_drift_findings_open
+    ON drift_findings(kind, resource) WHERE status = 'open';
+
+CREATE INDEX IF NOT EXISTS idx_drift_findings_status ON drift_findings(status, last_seen_at DESC);
+
+COMMIT;
@@ -0,0 +1,29 @@
+-- api/internal/db/migrations/037_state_outbox.sql
+-- Focus: The offline agent-op queue becomes an outbox of every intended state change
+
+BEGIN;
+
+ALTER TABLE agent_op_queue RENAME TO state_outbox;
+ALTER INDEX idx_agent_op_queue_pending RENAME TO idx_state_outbox_pending;
+
+ALTER TABLE state_outbox
+    ADD COLUMN event VARCHAR(64) NOT NULL DEFAULT 'agent.op',  -- e.g. app.created, cert.installed
+    ADD COLUMN resource_type VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN resource_id VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    -- History-only changes carry no RPC
+    ALTER COLUMN method DROP NOT NULL,
+    ALTER COLUMN payload_ciphertext DROP NOT NULL;
+
+ALTER TABLE state_outbox DROP CONSTRAINT IF EXISTS agent_op_queue_status_check;
+UPDATE state_outbox SET status = 'applied' WHERE status = 'done';
+ALTER TABLE state_outbox
+    ADD CONSTRAINT state_outbox_status_check
+        CHECK (status IN ('pending', 'applied', 'failed', 'recorded')),
+    -- 🛡️ Stability: Only a change with an RPC can wait on the agent
+    ADD CONSTRAINT state_outbox_rpc_check
+        CHECK (status = 'recorded' OR (method IS NOT NULL AND payload_ciphertext IS NOT NULL));
+
+CREATE INDEX idx_state_outbox_resource ON state_outbox (resource_type, resource_id, seq DESC);
+
+COMMIT;
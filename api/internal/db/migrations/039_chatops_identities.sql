@@ -0,0 +1,25 @@
+-- api/internal/db/migrations/039_chatops_identities.sql
+-- Focus: Mapping chat workspace users (Slack) to Kari users for slash commands
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS chatops_identities (
+    provider VARCHAR(16) NOT NULL,           -- 'slack'
+    team_id VARCHAR(64) NOT NULL,            -- Workspace / guild
+    external_user_id VARCHAR(64) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, team_id, external_user_id)
+);
+
+CREATE INDEX idx_chatops_identities_user ON chatops_identities (user_id);
+
+-- 🛡️ Zero-Trust: A chat user proves they own a Kari account by echoing a
+-- short-lived code issued to the signed-in user. Only its hash is stored.
+CREATE TABLE IF NOT EXISTS chatops_link_codes (
+    code_hash CHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+COMMIT;
@@ -0,0 +1,29 @@
+-- api/internal/db/migrations/040_deploy_approvals.sql
+-- Focus: Approval gates that hold deployments until a human approves them
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS deploy_approval_gates (
+    app_id UUID PRIMARY KEY REFERENCES applications(id) ON DELETE CASCADE,
+    slack_channel_id VARCHAR(64),
+    discord_channel_id VARCHAR(64),
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS deploy_approvals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    deployment_id UUID NOT NULL UNIQUE REFERENCES deployments(id) ON DELETE CASCADE,
+    app_id UUID NOT NULL,
+    domain_name TEXT NOT NULL,
+    branch TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    decided_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    decided_via VARCHAR(16),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_deploy_approvals_app ON deploy_approvals (app_id, created_at DESC);
+
+COMMIT;
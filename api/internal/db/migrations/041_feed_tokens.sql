@@ -0,0 +1,15 @@
+-- api/internal/db/migrations/041_feed_tokens.sql
+-- Focus: Per-user secret tokens for the iCal / RSS subscription feeds
+
+BEGIN;
+
+-- 🛡️ Zero-Trust: Calendar clients cannot send bearer tokens, so the feed URL
+-- itself is the credential. One token per user; issuing a new one revokes
+-- the old URL. Only its hash is stored.
+CREATE TABLE IF NOT EXISTS feed_tokens (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
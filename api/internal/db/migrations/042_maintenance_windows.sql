@@ -0,0 +1,21 @@
+-- api/internal/db/migrations/042_maintenance_windows.sql
+-- Focus: Planned downtime that quiets the AppMonitor and can pause the deploy queue
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    pause_deploys BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+-- Every Brain reloads the windows that have not ended yet
+CREATE INDEX idx_maintenance_windows_ends ON maintenance_windows (ends_at);
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- api/internal/db/migrations/043_scheduled_deployments.sql
+-- Focus: Deployments queued for a later time (e.g. 03:00) instead of right away
+
+BEGIN;
+
+-- SCHEDULED deployments are promoted to PENDING by the deploy scheduler once
+-- scheduled_at passes; the worker never claims them before that.
+ALTER TABLE deployments
+    ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS scheduled_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_deployments_scheduled ON deployments (scheduled_at) WHERE status = 'SCHEDULED';
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- api/internal/db/migrations/044_app_build_env.sql
+-- Focus: Build-time environment (build args) kept apart from the runtime env
+
+BEGIN;
+
+-- Passed to the build step only; the Muscle never writes these into the
+-- app's systemd units. Per-deployment overrides are not stored at all.
+ALTER TABLE applications
+    ADD COLUMN IF NOT EXISTS build_env_vars JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+COMMIT;
@@ -0,0 +1,30 @@
+-- api/internal/db/migrations/045_deployment_artifacts.sql
+-- Focus: Stored release tarballs so redeploys skip the rebuild
+
+BEGIN;
+
+-- One row per archived build. The tarball itself lives on the Muscle;
+-- pruned_at is set once the retention sweep has deleted it there.
+CREATE TABLE IF NOT EXISTS deployment_artifacts (
+    deployment_id UUID PRIMARY KEY REFERENCES deployments(id) ON DELETE CASCADE,
+    app_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    sha256 CHAR(64) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    pruned_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_deployment_artifacts_app
+    ON deployment_artifacts (app_id, created_at DESC) WHERE pruned_at IS NULL;
+
+-- Redeploys unpack their source's artifact instead of building. The link is
+-- written before the deployment row, so the worker can never claim a redeploy
+-- without it (hence no foreign key on deployment_id).
+CREATE TABLE IF NOT EXISTS deployment_redeploys (
+    deployment_id UUID PRIMARY KEY,
+    source_deployment_id UUID NOT NULL REFERENCES deployment_artifacts(deployment_id) ON DELETE CASCADE,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
@@ -0,0 +1,43 @@
+-- api/internal/db/migrations/046_sbom_vulnerabilities.sql
+-- Focus: Per-build dependency catalogs (SBOM) and the vulnerabilities found in them
+
+BEGIN;
+
+-- One catalog per built deployment, reduced from the Muscle's CycloneDX output.
+-- Redeploys have none of their own; they resolve to their source's.
+CREATE TABLE IF NOT EXISTS deployment_sboms (
+    deployment_id UUID PRIMARY KEY REFERENCES deployments(id) ON DELETE CASCADE,
+    app_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    generator TEXT NOT NULL,
+    components JSONB NOT NULL DEFAULT '[]',
+    component_count INTEGER NOT NULL CHECK (component_count >= 0),
+    truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_deployment_sboms_app ON deployment_sboms (app_id, created_at DESC);
+
+-- Known vulnerabilities in what an app currently runs. A finding is resolved
+-- once a scan no longer sees it (upgraded away, or the advisory withdrawn) and
+-- reopened if it reappears, so alerts fire once per (vuln, package).
+CREATE TABLE IF NOT EXISTS vulnerability_findings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    app_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    deployment_id UUID NOT NULL,
+    vuln_id TEXT NOT NULL,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    summary TEXT NOT NULL DEFAULT '',
+    severity VARCHAR(20) NOT NULL DEFAULT 'UNKNOWN',
+    package_name TEXT NOT NULL,
+    package_version TEXT NOT NULL,
+    purl TEXT NOT NULL,
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ,
+    UNIQUE (app_id, vuln_id, purl)
+);
+
+CREATE INDEX IF NOT EXISTS idx_vulnerability_findings_open
+    ON vulnerability_findings (app_id, severity) WHERE resolved_at IS NULL;
+
+COMMIT;
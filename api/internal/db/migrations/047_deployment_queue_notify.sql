@@ -0,0 +1,22 @@
+-- api/internal/db/migrations/047_deployment_queue_notify.sql
+-- Focus: Wake deployment workers on NOTIFY instead of waiting for the next poll
+
+BEGIN;
+
+-- Fires for new deployments and for ones promoted to PENDING later
+-- (approvals, scheduled deploys). Delivered on commit, so a woken worker
+-- always finds the row; polling stays as the fallback.
+CREATE OR REPLACE FUNCTION notify_deployment_queued() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('kari_deployments', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS deployments_queued ON deployments;
+CREATE TRIGGER deployments_queued
+    AFTER INSERT OR UPDATE OF status ON deployments
+    FOR EACH ROW WHEN (NEW.status = 'PENDING')
+    EXECUTE FUNCTION notify_deployment_queued();
+
+COMMIT;
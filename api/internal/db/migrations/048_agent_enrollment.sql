@@ -0,0 +1,35 @@
+-- api/internal/db/migrations/048_agent_enrollment.sql
+-- Focus: Remote Muscle agents joining with one-time tokens
+
+BEGIN;
+
+-- One row per enrolled agent. The certificate's CN is the server ID; the
+-- fingerprint pins exactly which certificate was issued to it.
+CREATE TABLE IF NOT EXISTS servers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    hostname VARCHAR(253) NOT NULL,
+    cert_serial VARCHAR(40) NOT NULL,
+    cert_fingerprint CHAR(64) NOT NULL,
+    cert_expires_at TIMESTAMPTZ NOT NULL,
+    enrolled_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    enrolled_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 🛡️ Zero-Trust: Join tokens are bearer credentials. Only the hash is
+-- stored, each is short-lived, and used_at makes it single-use.
+CREATE TABLE IF NOT EXISTS server_enroll_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    server_name VARCHAR(100) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    server_id UUID REFERENCES servers(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_server_enroll_tokens_expiry
+    ON server_enroll_tokens (expires_at) WHERE used_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- api/internal/db/migrations/049_server_heartbeats.sql
+-- Focus: Remote server liveness, and which server each app runs on
+
+BEGIN;
+
+-- The ServerMonitor owns these: last_seen_at moves on every answered probe,
+-- status flips when it has not moved for SERVER_OFFLINE_SECONDS.
+ALTER TABLE servers
+    ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'online', 'offline')),
+    ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS agent_version VARCHAR(64) NOT NULL DEFAULT '';
+
+-- NULL is the Brain's own host. A server with apps on it cannot be deleted
+-- out from under them.
+ALTER TABLE applications
+    ADD COLUMN IF NOT EXISTS server_id UUID REFERENCES servers(id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_applications_server
+    ON applications (server_id) WHERE server_id IS NOT NULL;
+
+COMMIT;
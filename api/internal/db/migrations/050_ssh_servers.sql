@@ -0,0 +1,37 @@
+-- api/internal/db/migrations/050_ssh_servers.sql
+-- Focus: Servers driven over SSH where the Muscle cannot be installed
+
+BEGIN;
+
+-- executor picks the transport; capabilities are the feature flags it
+-- declares, so services can refuse work a server cannot do.
+ALTER TABLE servers
+    ADD COLUMN IF NOT EXISTS executor VARCHAR(16) NOT NULL DEFAULT 'agent'
+        CHECK (executor IN ('agent', 'ssh')),
+    ADD COLUMN IF NOT EXISTS capabilities TEXT[] NOT NULL DEFAULT '{}';
+
+-- Agents enrolled before this migration can do everything
+UPDATE servers
+SET capabilities = ARRAY['app_users', 'app_units', 'system_files', 'services', 'builds', 'releases']
+WHERE executor = 'agent' AND capabilities = '{}';
+
+-- SSH servers have no agent certificate
+ALTER TABLE servers
+    ALTER COLUMN cert_serial DROP NOT NULL,
+    ALTER COLUMN cert_fingerprint DROP NOT NULL,
+    ALTER COLUMN cert_expires_at DROP NOT NULL;
+
+-- 🛡️ Privacy: The private key is encrypted (AAD = server ID) and never
+-- returned; the operator installs the public half in authorized_keys.
+-- host_key pins the server, so a spoofed host never sees a command.
+CREATE TABLE IF NOT EXISTS server_ssh_credentials (
+    server_id UUID PRIMARY KEY REFERENCES servers(id) ON DELETE CASCADE,
+    username VARCHAR(32) NOT NULL,
+    port INT NOT NULL DEFAULT 22 CHECK (port BETWEEN 1 AND 65535),
+    host_key TEXT NOT NULL,
+    public_key TEXT NOT NULL,
+    private_key_ciphertext TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- api/internal/db/migrations/051_server_capabilities.sql
+-- Focus: Host capabilities discovered from each server (GetCapabilities)
+
+BEGIN;
+
+-- NULL until the first discovery; capabilities holds the flags it implied
+ALTER TABLE servers ADD COLUMN IF NOT EXISTS host_capabilities JSONB;
+
+COMMIT;
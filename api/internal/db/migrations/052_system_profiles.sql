@@ -0,0 +1,36 @@
+-- api/internal/db/migrations/052_system_profiles.sql
+-- Focus: The SystemProfile singleton and its rollout to each agent
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS system_profiles (
+    id                       UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    default_stack_registry   JSONB NOT NULL DEFAULT '{}',
+    ssl_strategy             TEXT NOT NULL DEFAULT 'letsencrypt',
+    max_memory_per_app_mb    INTEGER NOT NULL CHECK (max_memory_per_app_mb >= 128),
+    max_cpu_percent_per_app  INTEGER NOT NULL CHECK (max_cpu_percent_per_app BETWEEN 10 AND 100),
+    -- '' leaves the host firewall unmanaged
+    default_firewall_policy  TEXT NOT NULL DEFAULT '' CHECK (default_firewall_policy IN ('', 'allow', 'deny')),
+    app_user_uid_range_start INTEGER NOT NULL,
+    app_user_uid_range_end   INTEGER NOT NULL,
+    backup_retention_days    INTEGER NOT NULL DEFAULT 30,
+    version                  INTEGER NOT NULL DEFAULT 1,
+    updated_at               TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (app_user_uid_range_start < app_user_uid_range_end)
+);
+
+-- 🛡️ Singleton: the repository reads LIMIT 1, so only ever seed one row
+INSERT INTO system_profiles (max_memory_per_app_mb, max_cpu_percent_per_app, app_user_uid_range_start, app_user_uid_range_end)
+SELECT 512, 100, 10000, 59999
+WHERE NOT EXISTS (SELECT 1 FROM system_profiles);
+
+-- target is 'local' for the Brain's own Muscle, otherwise the server ID
+CREATE TABLE IF NOT EXISTS system_profile_rollouts (
+    target          TEXT PRIMARY KEY,
+    applied_version INTEGER NOT NULL DEFAULT 0,
+    applied_at      TIMESTAMPTZ,
+    last_error      TEXT NOT NULL DEFAULT '',
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
@@ -0,0 +1,30 @@
+-- api/internal/db/migrations/053_stack_versions.sql
+-- Focus: The stack registry catalogue and its version lifecycle
+
+BEGIN;
+
+-- The defaults stay in system_profiles.default_stack_registry; this is the
+-- set of versions apps may pin. 'deprecated' keeps existing pins working
+-- but refuses new ones.
+CREATE TABLE IF NOT EXISTS stack_versions (
+    runtime       VARCHAR(20) NOT NULL,
+    version       VARCHAR(20) NOT NULL,
+    status        TEXT NOT NULL DEFAULT 'available' CHECK (status IN ('available', 'deprecated')),
+    added_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deprecated_at TIMESTAMPTZ,
+    PRIMARY KEY (runtime, version)
+);
+
+-- Seeded from domain.SupportedRuntimeVersions
+INSERT INTO stack_versions (runtime, version) VALUES
+    ('nodejs', '18'), ('nodejs', '20'), ('nodejs', '22'),
+    ('python', '3.10'), ('python', '3.11'), ('python', '3.12'),
+    ('go', '1.21'), ('go', '1.22'), ('go', '1.23'),
+    ('php', '8.1'), ('php', '8.2'), ('php', '8.3'),
+    ('ruby', '3.2'), ('ruby', '3.3')
+ON CONFLICT DO NOTHING;
+
+-- Usage counts scan applications by runtime
+CREATE INDEX IF NOT EXISTS idx_applications_runtime ON applications (app_type, runtime_version);
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- api/internal/db/migrations/054_timezones.sql
+-- Focus: Install and per-user time zones for schedules and timestamp display
+
+BEGIN;
+
+-- IANA names, validated by the Brain; schedulers fall back to UTC on a bad one
+ALTER TABLE system_settings
+    ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- NULL follows the install's zone
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS timezone TEXT;
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- api/internal/db/migrations/055_passkey_reenroll.sql
+-- Focus: One-time passkey re-enrolment after a break-glass recovery
+
+BEGIN;
+
+-- Set by "kari-api recover-admin" after it deletes the account's passkeys:
+-- the password may sign in (even where Rank 0 requires a passkey) until a
+-- new passkey is registered, which clears it
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS passkey_reenroll BOOLEAN NOT NULL DEFAULT false;
+
+COMMIT;
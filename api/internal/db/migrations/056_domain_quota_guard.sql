@@ -0,0 +1,47 @@
+-- api/internal/db/migrations/056_domain_quota_guard.sql
+-- Focus: Enforce the domain quota inside the INSERT instead of count-then-insert
+
+BEGIN;
+
+-- Two concurrent creations could both count N-1 and both insert. The
+-- per-user advisory lock serializes them until commit, and the count runs
+-- after it, so it sees the other one's row. NULL limits and exempt
+-- overrides insert freely, like ResourceQuotaService.CheckQuota.
+CREATE OR REPLACE FUNCTION domains_enforce_quota() RETURNS trigger AS $$
+DECLARE
+    lim INT;
+    used INT;
+BEGIN
+    IF NEW.user_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT CASE WHEN COALESCE(o.exempt, false) THEN NULL
+                ELSE COALESCE(o.max_domains, l.max_domains) END
+    INTO lim
+    FROM users u
+    LEFT JOIN role_resource_limits l ON l.role_id = u.role_id
+    LEFT JOIN user_resource_overrides o ON o.user_id = u.id
+    WHERE u.id = NEW.user_id;
+
+    IF lim IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    PERFORM pg_advisory_xact_lock(hashtext('domain_quota:' || NEW.user_id::text));
+    SELECT COUNT(*) INTO used FROM domains WHERE user_id = NEW.user_id;
+    IF used >= lim THEN
+        -- KQ001 is mapped to domain.ErrQuotaExceeded by the repositories
+        RAISE EXCEPTION 'domains would reach % of %', used + 1, lim
+            USING ERRCODE = 'KQ001';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS domains_quota ON domains;
+CREATE TRIGGER domains_quota
+    BEFORE INSERT ON domains
+    FOR EACH ROW EXECUTE FUNCTION domains_enforce_quota();
+
+COMMIT;
@@ -0,0 +1,58 @@
+-- api/internal/db/migrations/057_identity_cache_notify.sql
+-- Focus: Tell every replica's identity cache about user, role and permission writes
+
+BEGIN;
+
+-- Payload is one user id, or '*' when a statement touched several users or
+-- any role or grant (a role change reaches everyone holding it). Covers
+-- writes the Brain's own wrappers never see: other replicas,
+-- "kari-api recover-admin", and SQL run by hand.
+CREATE OR REPLACE FUNCTION notify_identity_users() RETURNS trigger AS $$
+DECLARE
+    n INT;
+    one UUID;
+BEGIN
+    SELECT COUNT(*), (array_agg(id))[1] INTO n, one FROM changed_users;
+    IF n = 1 THEN
+        PERFORM pg_notify('kari_identity', one::text);
+    ELSIF n > 1 THEN
+        PERFORM pg_notify('kari_identity', '*');
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION notify_identity_all() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('kari_identity', '*');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Transition tables allow one event per trigger
+DROP TRIGGER IF EXISTS users_identity_update ON users;
+CREATE TRIGGER users_identity_update
+    AFTER UPDATE ON users REFERENCING OLD TABLE AS changed_users
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_identity_users();
+
+DROP TRIGGER IF EXISTS users_identity_delete ON users;
+CREATE TRIGGER users_identity_delete
+    AFTER DELETE ON users REFERENCING OLD TABLE AS changed_users
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_identity_users();
+
+DROP TRIGGER IF EXISTS roles_identity ON roles;
+CREATE TRIGGER roles_identity
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON roles
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_identity_all();
+
+DROP TRIGGER IF EXISTS role_permissions_identity ON role_permissions;
+CREATE TRIGGER role_permissions_identity
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON role_permissions
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_identity_all();
+
+DROP TRIGGER IF EXISTS permissions_identity ON permissions;
+CREATE TRIGGER permissions_identity
+    AFTER UPDATE OR DELETE OR TRUNCATE ON permissions
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_identity_all();
+
+COMMIT;
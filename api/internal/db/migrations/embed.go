@@ -0,0 +1,8 @@
+// Package migrations ships the schema with the binary, for headless setup.
+// Containers still mount this directory as Postgres init scripts.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var Files embed.FS
@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type AccessAnalyticsRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAccessAnalyticsRepo(pool *pgxpool.Pool) domain.AccessAnalyticsRepository {
+	return &AccessAnalyticsRepo{pool: pool}
+}
+
+func (r *AccessAnalyticsRepo) ListTargets(ctx context.Context) ([]domain.AnalyticsTarget, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT d.id, d.name, COALESCE(c.log_offset, 0)
+		FROM domains d
+		LEFT JOIN domain_access_cursors c ON c.domain_id = d.id
+		ORDER BY d.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list analytics targets: %w", err)
+	}
+	defer rows.Close()
+
+	targets := []domain.AnalyticsTarget{}
+	for rows.Next() {
+		var t domain.AnalyticsTarget
+		if err := rows.Scan(&t.DomainID, &t.DomainName, &t.Offset); err != nil {
+			return nil, fmt.Errorf("failed to scan analytics target: %w", err)
+		}
+		targets = append(targets, t)
+	}
+	return targets, rows.Err()
+}
+
+func (r *AccessAnalyticsRepo) RecordBatch(ctx context.Context, domainID uuid.UUID, buckets []domain.AccessBucket, nextOffset int64) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin analytics batch: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, b := range buckets {
+		s := b.StatusClasses
+		other := b.Requests - s["2xx"] - s["3xx"] - s["4xx"] - s["5xx"]
+		batch.Queue(`
+			INSERT INTO domain_access_hourly
+				(domain_id, hour, requests, bytes_sent, bytes_received, status_2xx, status_3xx, status_4xx, status_5xx, status_other)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (domain_id, hour) DO UPDATE SET
+				requests = domain_access_hourly.requests + EXCLUDED.requests,
+				bytes_sent = domain_access_hourly.bytes_sent + EXCLUDED.bytes_sent,
+				bytes_received = domain_access_hourly.bytes_received + EXCLUDED.bytes_received,
+				status_2xx = domain_access_hourly.status_2xx + EXCLUDED.status_2xx,
+				status_3xx = domain_access_hourly.status_3xx + EXCLUDED.status_3xx,
+				status_4xx = domain_access_hourly.status_4xx + EXCLUDED.status_4xx,
+				status_5xx = domain_access_hourly.status_5xx + EXCLUDED.status_5xx,
+				status_other = domain_access_hourly.status_other + EXCLUDED.status_other
+		`, domainID, b.Hour, b.Requests, b.BytesSent, b.BytesReceived, s["2xx"], s["3xx"], s["4xx"], s["5xx"], max(other, 0))
+
+		// Monthly transfer accounting for the app behind the domain, if any
+		batch.Queue(`
+			INSERT INTO app_bandwidth_monthly (app_id, month, bytes_in, bytes_out)
+			SELECT a.id, date_trunc('month', $2::timestamptz AT TIME ZONE 'UTC')::date, $3, $4
+			FROM domains d JOIN applications a ON a.id = d.app_id OR a.domain_id = d.id
+			WHERE d.id = $1
+			LIMIT 1
+			ON CONFLICT (app_id, month) DO UPDATE SET
+				bytes_in = app_bandwidth_monthly.bytes_in + EXCLUDED.bytes_in,
+				bytes_out = app_bandwidth_monthly.bytes_out + EXCLUDED.bytes_out
+		`, domainID, b.Hour, b.BytesReceived, b.BytesSent)
+
+		for kind, counts := range map[string]map[string]int64{"path": b.Paths, "client": b.Clients} {
+			for key, hits := range counts {
+				batch.Queue(`
+					INSERT INTO domain_access_top (domain_id, hour, kind, key, hits)
+					VALUES ($1, $2, $3, $4, $5)
+					ON CONFLICT (domain_id, hour, kind, key) DO UPDATE SET hits = domain_access_top.hits + EXCLUDED.hits
+				`, domainID, b.Hour, kind, key, hits)
+			}
+		}
+	}
+	batch.Queue(`
+		INSERT INTO domain_access_cursors (domain_id, log_offset) VALUES ($1, $2)
+		ON CONFLICT (domain_id) DO UPDATE SET log_offset = EXCLUDED.log_offset, updated_at = NOW()
+	`, domainID, nextOffset)
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to record access analytics: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *AccessAnalyticsRepo) Summarize(ctx context.Context, domainID, ownerID uuid.UUID, since time.Time, top int) (*domain.DomainAnalytics, error) {
+	// 🛡️ Zero-Trust: Ownership check before aggregating anything
+	var exists bool
+	err := r.pool.QueryRow(ctx, `SELECT true FROM domains WHERE id = $1 AND user_id = $2`, domainID, ownerID).Scan(&exists)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to verify domain ownership: %w", err)
+	}
+
+	a := &domain.DomainAnalytics{
+		DomainID:   domainID,
+		Since:      since,
+		Status:     map[string]int64{},
+		Series:     []domain.AnalyticsPoint{},
+		TopPaths:   []domain.AnalyticsCount{},
+		TopClients: []domain.AnalyticsCount{},
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT hour, requests, bytes_sent, bytes_received, status_2xx, status_3xx, status_4xx, status_5xx, status_other
+		FROM domain_access_hourly
+		WHERE domain_id = $1 AND hour >= $2
+		ORDER BY hour
+	`, domainID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load access series: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p domain.AnalyticsPoint
+		var s2, s3, s4, s5, other int64
+		if err := rows.Scan(&p.Hour, &p.Requests, &p.BytesSent, &p.BytesReceived, &s2, &s3, &s4, &s5, &other); err != nil {
+			return nil, fmt.Errorf("failed to scan access series: %w", err)
+		}
+		a.Series = append(a.Series, p)
+		a.Requests += p.Requests
+		a.BytesSent += p.BytesSent
+		a.BytesReceived += p.BytesReceived
+		a.Status["2xx"] += s2
+		a.Status["3xx"] += s3
+		a.Status["4xx"] += s4
+		a.Status["5xx"] += s5
+		a.Status["other"] += other
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for kind, dest := range map[string]*[]domain.AnalyticsCount{"path": &a.TopPaths, "client": &a.TopClients} {
+		if err := r.topKeys(ctx, domainID, kind, since, top, dest); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func (r *AccessAnalyticsRepo) topKeys(ctx context.Context, domainID uuid.UUID, kind string, since time.Time, top int, dest *[]domain.AnalyticsCount) error {
+	rows, err := r.pool.Query(ctx, `
+		SELECT key, SUM(hits) AS total
+		FROM domain_access_top
+		WHERE domain_id = $1 AND kind = $2 AND hour >= $3
+		GROUP BY key
+		ORDER BY total DESC, key
+		LIMIT $4
+	`, domainID, kind, since, top)
+	if err != nil {
+		return fmt.Errorf("failed to load top %ss: %w", kind, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c domain.AnalyticsCount
+		if err := rows.Scan(&c.Key, &c.Hits); err != nil {
+			return fmt.Errorf("failed to scan top %s: %w", kind, err)
+		}
+		*dest = append(*dest, c)
+	}
+	return rows.Err()
+}
+
+func (r *AccessAnalyticsRepo) PruneBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM domain_access_top WHERE hour < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune access top lists: %w", err)
+	}
+	pruned := tag.RowsAffected()
+	tag, err = r.pool.Exec(ctx, `DELETE FROM domain_access_hourly WHERE hour < $1`, cutoff)
+	if err != nil {
+		return pruned, fmt.Errorf("failed to prune access rollups: %w", err)
+	}
+	return pruned + tag.RowsAffected(), nil
+}
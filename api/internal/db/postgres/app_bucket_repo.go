@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type AppBucketRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAppBucketRepo(pool *pgxpool.Pool) domain.AppBucketRepository {
+	return &AppBucketRepo{pool: pool}
+}
+
+func (r *AppBucketRepo) Create(ctx context.Context, b *domain.AppBucket) error {
+	query := `
+		INSERT INTO app_buckets (app_id, bucket, access_key, secret_key_ciphertext, quota_bytes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`
+	err := r.pool.QueryRow(ctx, query, b.AppID, b.Bucket, b.AccessKey, b.SecretKeyCiphertext, b.QuotaBytes).
+		Scan(&b.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("%w: application already has a bucket", domain.ErrConflict)
+		}
+		return fmt.Errorf("failed to store app bucket: %w", err)
+	}
+	return nil
+}
+
+func (r *AppBucketRepo) GetByAppID(ctx context.Context, appID uuid.UUID) (*domain.AppBucket, error) {
+	var b domain.AppBucket
+	err := r.pool.QueryRow(ctx, `
+		SELECT app_id, bucket, access_key, secret_key_ciphertext, quota_bytes, created_at
+		FROM app_buckets WHERE app_id = $1
+	`, appID).Scan(&b.AppID, &b.Bucket, &b.AccessKey, &b.SecretKeyCiphertext, &b.QuotaBytes, &b.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load app bucket: %w", err)
+	}
+	return &b, nil
+}
+
+func (r *AppBucketRepo) UpdateQuota(ctx context.Context, appID uuid.UUID, quotaBytes int64) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE app_buckets SET quota_bytes = $2 WHERE app_id = $1`, appID, quotaBytes)
+	if err != nil {
+		return fmt.Errorf("failed to update bucket quota: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *AppBucketRepo) Delete(ctx context.Context, appID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM app_buckets WHERE app_id = $1`, appID)
+	if err != nil {
+		return fmt.Errorf("failed to delete app bucket: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
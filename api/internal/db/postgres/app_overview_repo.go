@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type AppOverviewRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAppOverviewRepo(pool *pgxpool.Pool) domain.AppOverviewRepository {
+	return &AppOverviewRepo{pool: pool}
+}
+
+func (r *AppOverviewRepo) LastDeployment(ctx context.Context, appID uuid.UUID) (*domain.DeploymentSummary, error) {
+	var d domain.DeploymentSummary
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, UPPER(status), branch, created_at, updated_at
+		FROM deployments
+		WHERE app_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, appID).Scan(&d.ID, &d.Status, &d.Branch, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load last deployment: %w", err)
+	}
+	return &d, nil
+}
+
+// Certificate prefers the live certificate over revoked history.
+func (r *AppOverviewRepo) Certificate(ctx context.Context, domainID uuid.UUID) (*domain.CertificateSummary, error) {
+	var c domain.CertificateSummary
+	var lastError *string
+	err := r.pool.QueryRow(ctx, `
+		SELECT issuer, status, expires_at, last_error
+		FROM ssl_certificates
+		WHERE domain_id = $1
+		ORDER BY (status = 'revoked'), expires_at DESC
+		LIMIT 1
+	`, domainID).Scan(&c.Issuer, &c.Status, &c.ExpiresAt, &lastError)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load certificate: %w", err)
+	}
+	if lastError != nil {
+		c.LastError = *lastError
+	}
+	return &c, nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -20,24 +21,95 @@ func NewApplicationRepo(pool *pgxpool.Pool) domain.ApplicationRepository {
 	return &ApplicationRepo{pool: pool}
 }
 
-// Create persists the app and the unprivileged OS user identity
-func (r *ApplicationRepo) Create(ctx context.Context, app *domain.Application) error {
+// Create persists the app and the unprivileged OS user identity, then binds a
+// ledger UID to it. Everything commits together so a failed create never leaks a UID.
+func (r *ApplicationRepo) Create(ctx context.Context, app *domain.Application, uids domain.UIDRange) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin application tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-		INSERT INTO applications (domain_id, repo_url, branch, build_command, start_command, env_vars, port, app_user, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		RETURNING id, created_at, updated_at
+		INSERT INTO applications (id, domain_id, app_type, runtime_version, image_ref, repo_url, branch, build_command, start_command, env_vars, build_env_vars, port, settings, processes, instances, app_user, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		RETURNING created_at, updated_at
 	`
-	err := r.pool.QueryRow(ctx, query,
-		app.DomainID, app.RepoURL, app.Branch, app.BuildCommand,
-		app.StartCommand, app.EnvVars, app.Port, app.AppUser, app.Status,
-	).Scan(&app.ID, &app.CreatedAt, &app.UpdatedAt)
-
+	err = tx.QueryRow(ctx, query,
+		app.ID, app.DomainID, app.AppType, app.RuntimeVersion, app.ImageRef, app.RepoURL, app.Branch, app.BuildCommand,
+		app.StartCommand, app.EnvVars, buildEnvJSON(app.BuildEnvVars), app.Port, app.Settings, processesJSON(app.Processes), app.Instances, app.AppUser, app.Status,
+	).Scan(&app.CreatedAt, &app.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create application: %w", err)
 	}
+
+	uid, err := allocateUID(ctx, tx, app.ID, uids)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE applications SET app_uid = $2 WHERE id = $1`, app.ID, uid); err != nil {
+		return fmt.Errorf("failed to bind app uid: %w", err)
+	}
+	if err := appendStateChange(ctx, tx, &domain.StateChange{
+		Event:        domain.EventAppCreated,
+		ResourceType: "application",
+		ResourceID:   app.ID.String(),
+		Description:  fmt.Sprintf("Create %s application", app.AppType),
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit application: %w", err)
+	}
+	app.AppUID = &uid
 	return nil
 }
 
+// allocateUID prefers the lowest recyclable UID, then the next never-used one.
+// 🛡️ Stability: A transaction-scoped advisory lock serializes allocators, so
+// concurrent creates cannot compute the same MAX(uid)+1.
+func allocateUID(ctx context.Context, tx pgx.Tx, appID uuid.UUID, uids domain.UIDRange) (int, error) {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('app_uid_ledger'))`); err != nil {
+		return 0, fmt.Errorf("failed to lock uid ledger: %w", err)
+	}
+
+	var uid int
+	err := tx.QueryRow(ctx, `
+		UPDATE app_uid_ledger
+		SET state = 'allocated', app_id = $1, allocated_at = NOW(), released_at = NULL
+		WHERE uid = (
+			SELECT uid FROM app_uid_ledger
+			WHERE state = 'quarantined'
+			  AND released_at < NOW() - make_interval(secs => $2)
+			  AND uid BETWEEN $3 AND $4
+			ORDER BY uid
+			LIMIT 1
+		)
+		RETURNING uid
+	`, appID, domain.UIDRecycleCooldown.Seconds(), uids.Start, uids.End).Scan(&uid)
+	if err == nil {
+		return uid, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("failed to recycle uid: %w", err)
+	}
+
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(MAX(uid) + 1, $1) FROM app_uid_ledger WHERE uid BETWEEN $1 AND $2
+	`, uids.Start, uids.End).Scan(&uid); err != nil {
+		return 0, fmt.Errorf("failed to compute next uid: %w", err)
+	}
+	if uid > uids.End {
+		return 0, fmt.Errorf("%w: app UID range %d-%d is exhausted", domain.ErrConflict, uids.Start, uids.End)
+	}
+
+	if _, err := tx.Exec(ctx, `INSERT INTO app_uid_ledger (uid, app_id) VALUES ($1, $2)`, uid, appID); err != nil {
+		return 0, fmt.Errorf("failed to record uid allocation: %w", err)
+	}
+	return uid, nil
+}
+
 // GetByIDWithMetadata performs a 3-way join to support Rank-Based Authorization logic.
 func (r *ApplicationRepo) GetByIDWithMetadata(ctx context.Context, id uuid.UUID) (*domain.ApplicationMetadata, error) {
 	// 🛡️ SLA: Single trip to DB to get everything needed for Authorization
@@ -68,7 +140,7 @@ func (r *ApplicationRepo) GetByIDWithMetadata(ctx context.Context, id uuid.UUID)
 // GetByID remains for standard UI lookups with strict ownership filtering
 func (r *ApplicationRepo) GetByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*domain.Application, error) {
 	query := `
-		SELECT a.id, a.domain_id, a.repo_url, a.branch, a.build_command, a.start_command, a.env_vars, a.port, a.app_user, a.status, a.created_at, a.updated_at
+		SELECT a.id, a.domain_id, a.app_type, a.runtime_version, a.image_ref, a.registry_credential_id, a.repo_url, a.branch, a.build_command, a.start_command, a.env_vars, a.build_env_vars, a.port, a.settings, a.processes, a.instances, a.app_user, a.app_uid, a.status, a.created_at, a.updated_at
 		FROM applications a
 		INNER JOIN domains d ON a.domain_id = d.id
 		WHERE a.id = $1 AND d.user_id = $2
@@ -90,16 +162,296 @@ func (r *ApplicationRepo) GetByID(ctx context.Context, id uuid.UUID, userID uuid
 	return &app, nil
 }
 
-// Delete removes the application record. The Service layer handles the Muscle cleanup first.
-func (r *ApplicationRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM applications WHERE id = $1`
-	tag, err := r.pool.Exec(ctx, query, id)
+// GetByIDSystem is GetByID without the ownership filter, for callers that
+// authenticate some other way (a signed push webhook).
+func (r *ApplicationRepo) GetByIDSystem(ctx context.Context, id uuid.UUID) (*domain.Application, error) {
+	query := `
+		SELECT a.id, a.domain_id, a.app_type, a.runtime_version, a.image_ref, a.registry_credential_id, a.repo_url, a.branch, a.build_command, a.start_command, a.env_vars, a.build_env_vars, a.port, a.settings, a.processes, a.instances, a.app_user, a.app_uid, a.status, a.created_at, a.updated_at
+		FROM applications a
+		WHERE a.id = $1
+	`
+	rows, err := r.pool.Query(ctx, query, id)
 	if err != nil {
-		return fmt.Errorf("failed to delete application: %w", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	app, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[domain.Application])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &app, nil
+}
+
+// ListAllActive serves background workers; it is deliberately not tenant-scoped.
+func (r *ApplicationRepo) ListAllActive(ctx context.Context) ([]domain.Application, error) {
+	query := `
+		SELECT a.id, a.domain_id, d.domain_name, d.user_id AS owner_id, a.server_id, a.app_type, a.start_command, a.env_vars, a.port, a.processes, a.instances, a.app_user, a.status, a.created_at, a.updated_at
+		FROM applications a
+		INNER JOIN domains d ON a.domain_id = d.id
+		WHERE a.status <> 'stopped'
+		ORDER BY a.created_at
+	`
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active applications: %w", err)
+	}
+	defer rows.Close()
+
+	apps, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[domain.Application])
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan active applications: %w", err)
+	}
+	return apps, nil
+}
+
+// UpdateEnvVars stores the (already encrypted) env map. The outbox entry only
+// says that the variables changed; their values never enter the history.
+func (r *ApplicationRepo) UpdateEnvVars(ctx context.Context, id uuid.UUID, envVars map[string]string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin env tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE applications SET env_vars = $2, updated_at = NOW() WHERE id = $1`, id, envVars)
+	if err != nil {
+		return fmt.Errorf("failed to update application env vars: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	// Applied on the host by the next deployment, which reads them fresh
+	if err := appendStateChange(ctx, tx, &domain.StateChange{
+		Event:        domain.EventAppEnvUpdated,
+		ResourceType: "application",
+		ResourceID:   id.String(),
+		Description:  "Update environment variables",
+	}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// UpdateBuildEnvVars replaces the build-only env. It reaches the host with the
+// next build and never touches running units, so no outbox entry is needed.
+func (r *ApplicationRepo) UpdateBuildEnvVars(ctx context.Context, id uuid.UUID, envVars map[string]string) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE applications SET build_env_vars = $2, updated_at = NOW() WHERE id = $1`, id, buildEnvJSON(envVars))
+	if err != nil {
+		return fmt.Errorf("failed to update application build env vars: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateSettings replaces the app's runtime tuning. Ownership is checked by the service.
+func (r *ApplicationRepo) UpdateSettings(ctx context.Context, id uuid.UUID, settings domain.AppSettings) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE applications SET settings = $2, updated_at = NOW() WHERE id = $1`, id, settings)
+	if err != nil {
+		return fmt.Errorf("failed to update application settings: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateRuntimeVersion pins (or, with "", unpins) the app's toolchain version.
+func (r *ApplicationRepo) UpdateRuntimeVersion(ctx context.Context, id uuid.UUID, version string) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE applications SET runtime_version = $2, updated_at = NOW() WHERE id = $1`, id, version)
+	if err != nil {
+		return fmt.Errorf("failed to update application runtime version: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
 	}
+	return nil
+}
 
+// SetWebhookSecret stores the push webhook secret (AES-GCM ciphertext).
+func (r *ApplicationRepo) SetWebhookSecret(ctx context.Context, id uuid.UUID, ciphertext string) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE applications SET webhook_secret_ciphertext = $2, updated_at = NOW() WHERE id = $1`, id, ciphertext)
+	if err != nil {
+		return fmt.Errorf("failed to store webhook secret: %w", err)
+	}
 	if tag.RowsAffected() == 0 {
 		return domain.ErrNotFound
 	}
 	return nil
 }
+
+// GetWebhookSecret returns the stored push webhook ciphertext; "" when the
+// app was never linked.
+func (r *ApplicationRepo) GetWebhookSecret(ctx context.Context, id uuid.UUID) (string, error) {
+	var ciphertext string
+	err := r.pool.QueryRow(ctx,
+		`SELECT COALESCE(webhook_secret_ciphertext, '') FROM applications WHERE id = $1`, id).Scan(&ciphertext)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrNotFound
+		}
+		return "", fmt.Errorf("failed to read webhook secret: %w", err)
+	}
+	return ciphertext, nil
+}
+
+// SetImage points an image-based app at a new reference and (optional) pull credential.
+// Credential ownership is checked by the service.
+func (r *ApplicationRepo) SetImage(ctx context.Context, id uuid.UUID, image string, credentialID *uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE applications SET image_ref = $2, registry_credential_id = $3, updated_at = NOW() WHERE id = $1`,
+		id, image, credentialID)
+	if err != nil {
+		return fmt.Errorf("failed to update application image: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateProcesses replaces the app's process map. Ownership is checked by the service.
+func (r *ApplicationRepo) UpdateProcesses(ctx context.Context, id uuid.UUID, procs map[string]domain.ProcessSpec) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE applications SET processes = $2, updated_at = NOW() WHERE id = $1`, id, processesJSON(procs))
+	if err != nil {
+		return fmt.Errorf("failed to update application processes: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateInstances sets how many copies of the web process the proxy balances across.
+func (r *ApplicationRepo) UpdateInstances(ctx context.Context, id uuid.UUID, instances int) error {
+	tag, err := r.pool.Exec(ctx,
+		`UPDATE applications SET instances = $2, updated_at = NOW() WHERE id = $1`, id, instances)
+	if err != nil {
+		return fmt.Errorf("failed to update application instances: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// processesJSON keeps the NOT NULL column at '{}' for single-process apps.
+func processesJSON(procs map[string]domain.ProcessSpec) map[string]domain.ProcessSpec {
+	if procs == nil {
+		return map[string]domain.ProcessSpec{}
+	}
+	return procs
+}
+
+// buildEnvJSON keeps the NOT NULL column at '{}' for apps without build args.
+func buildEnvJSON(env map[string]string) map[string]string {
+	if env == nil {
+		return map[string]string{}
+	}
+	return env
+}
+
+// Delete removes the application record. The Service layer handles the Muscle cleanup first,
+// so reaching here means the jail user is gone and its UID can enter quarantine.
+func (r *ApplicationRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin delete tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Quarantine before the DELETE: the FK would otherwise NULL app_id and
+	// leave the UID permanently allocated.
+	if _, err := tx.Exec(ctx, `
+		UPDATE app_uid_ledger SET state = 'quarantined', released_at = NOW()
+		WHERE app_id = $1
+	`, id); err != nil {
+		return fmt.Errorf("failed to release app uid: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM applications WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete application: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	if err := appendStateChange(ctx, tx, &domain.StateChange{
+		Event:        domain.EventAppDeleted,
+		ResourceType: "application",
+		ResourceID:   id.String(),
+		Description:  "Delete application",
+	}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// applicationSorts whitelists the columns clients may sort by.
+var applicationSorts = map[string]sortColumn{
+	"created_at": {expr: "a.created_at", cast: "timestamptz"},
+	"updated_at": {expr: "a.updated_at", cast: "timestamptz"},
+	"status":     {expr: "a.status", cast: "text"},
+}
+
+// List returns a keyset-paginated, owner-scoped slice of applications.
+func (r *ApplicationRepo) List(ctx context.Context, filter domain.ApplicationFilter, page domain.PageRequest) (domain.Page[domain.Application], error) {
+	page = page.Normalize()
+
+	// 🛡️ Tenant Isolation: Ownership is resolved through the parent domain
+	q := &listQuery{}
+	q.where("d.user_id = " + q.arg(filter.OwnerID))
+	if filter.Status != "" {
+		q.where("a.status = " + q.arg(filter.Status))
+	}
+	if filter.DomainID != uuid.Nil {
+		q.where("a.domain_id = " + q.arg(filter.DomainID))
+	}
+
+	from := ` FROM applications a INNER JOIN domains d ON a.domain_id = d.id`
+
+	var total int
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*)"+from+q.whereSQL(), q.args...).Scan(&total); err != nil {
+		return domain.Page[domain.Application]{}, fmt.Errorf("failed to count applications: %w", err)
+	}
+
+	tail, err := q.paginate(page, applicationSorts, "created_at", "a.id")
+	if err != nil {
+		return domain.Page[domain.Application]{}, err
+	}
+
+	query := `SELECT a.id, a.domain_id, d.user_id AS owner_id, a.app_type, a.runtime_version, a.image_ref, a.registry_credential_id, a.repo_url, a.branch, a.build_command, a.start_command,
+		a.env_vars, a.build_env_vars, a.port, a.settings, a.processes, a.instances, a.app_user, a.app_uid, a.status, a.created_at, a.updated_at` + from + q.whereSQL() + tail
+	rows, err := r.pool.Query(ctx, query, q.args...)
+	if err != nil {
+		return domain.Page[domain.Application]{}, fmt.Errorf("failed to list applications: %w", err)
+	}
+	defer rows.Close()
+
+	apps, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[domain.Application])
+	if err != nil {
+		return domain.Page[domain.Application]{}, fmt.Errorf("failed to scan applications: %w", err)
+	}
+
+	return buildPage(apps, page, total, func(a domain.Application) domain.Cursor {
+		switch page.Sort {
+		case "updated_at":
+			return domain.Cursor{Value: a.UpdatedAt.Format(time.RFC3339Nano), ID: a.ID}
+		case "status":
+			return domain.Cursor{Value: a.Status, ID: a.ID}
+		default:
+			return domain.Cursor{Value: a.CreatedAt.Format(time.RFC3339Nano), ID: a.ID}
+		}
+	}), nil
+}
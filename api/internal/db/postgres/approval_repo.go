@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type ApprovalRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewApprovalRepo(pool *pgxpool.Pool) domain.ApprovalRepository {
+	return &ApprovalRepo{pool: pool}
+}
+
+const approvalColumns = `id, deployment_id, app_id, domain_name, branch, status, decided_by, decided_via, created_at, decided_at`
+
+// ==============================================================================
+// 1. Gates
+// ==============================================================================
+
+func (r *ApprovalRepo) GetGate(ctx context.Context, appID uuid.UUID) (*domain.ApprovalGate, error) {
+	var g domain.ApprovalGate
+	err := r.pool.QueryRow(ctx, `
+		SELECT app_id, slack_channel_id, discord_channel_id, created_by, created_at
+		FROM deploy_approval_gates WHERE app_id = $1
+	`, appID).Scan(&g.AppID, &g.SlackChannelID, &g.DiscordChannelID, &g.CreatedBy, &g.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load approval gate: %w", err)
+	}
+	return &g, nil
+}
+
+func (r *ApprovalRepo) SetGate(ctx context.Context, g *domain.ApprovalGate) error {
+	return r.pool.QueryRow(ctx, `
+		INSERT INTO deploy_approval_gates (app_id, slack_channel_id, discord_channel_id, created_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (app_id) DO UPDATE
+		SET slack_channel_id = EXCLUDED.slack_channel_id,
+		    discord_channel_id = EXCLUDED.discord_channel_id
+		RETURNING created_by, created_at
+	`, g.AppID, g.SlackChannelID, g.DiscordChannelID, g.CreatedBy).Scan(&g.CreatedBy, &g.CreatedAt)
+}
+
+func (r *ApprovalRepo) DeleteGate(ctx context.Context, appID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM deploy_approval_gates WHERE app_id = $1`, appID)
+	if err != nil {
+		return fmt.Errorf("failed to delete approval gate: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ==============================================================================
+// 2. Approvals
+// ==============================================================================
+
+func (r *ApprovalRepo) Create(ctx context.Context, a *domain.DeploymentApproval) error {
+	a.Status = domain.ApprovalPending
+	return r.pool.QueryRow(ctx, `
+		INSERT INTO deploy_approvals (deployment_id, app_id, domain_name, branch)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, a.DeploymentID, a.AppID, a.DomainName, a.Branch).Scan(&a.ID, &a.CreatedAt)
+}
+
+func (r *ApprovalRepo) Get(ctx context.Context, id uuid.UUID) (*domain.DeploymentApproval, error) {
+	a, err := scanApproval(r.pool.QueryRow(ctx, `SELECT `+approvalColumns+` FROM deploy_approvals WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return a, err
+}
+
+func (r *ApprovalRepo) ListByApp(ctx context.Context, appID uuid.UUID, limit int) ([]domain.DeploymentApproval, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+approvalColumns+` FROM deploy_approvals
+		WHERE app_id = $1 ORDER BY created_at DESC LIMIT $2
+	`, appID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+	defer rows.Close()
+
+	approvals := []domain.DeploymentApproval{}
+	for rows.Next() {
+		a, err := scanApproval(rows)
+		if err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, *a)
+	}
+	return approvals, rows.Err()
+}
+
+func (r *ApprovalRepo) Decide(ctx context.Context, id uuid.UUID, status domain.ApprovalStatus, deciderID uuid.UUID, via string) (*domain.DeploymentApproval, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 🛡️ Concurrency: Only a pending row updates, so two clicks racing from
+	// different channels settle exactly once
+	a, err := scanApproval(tx.QueryRow(ctx, `
+		UPDATE deploy_approvals
+		SET status = $2, decided_by = $3, decided_via = $4, decided_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING `+approvalColumns, id, status, deciderID, via))
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM deploy_approvals WHERE id = $1)`, id).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("%w: approval was already decided", domain.ErrConflict)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	next := domain.StatusPending
+	if status == domain.ApprovalRejected {
+		next = domain.StatusRejected
+	}
+	// An approved deployment that was scheduled for later keeps waiting for its time
+	_, err = tx.Exec(ctx, `
+		UPDATE deployments
+		SET status = CASE WHEN $2::text = $4::text AND scheduled_at > NOW() THEN $5::text ELSE $2::text END, updated_at = NOW()
+		WHERE id = $1 AND status = $3
+	`, a.DeploymentID, next, domain.StatusAwaitingApproval, domain.StatusPending, domain.StatusScheduled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release deployment: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func scanApproval(row pgx.Row) (*domain.DeploymentApproval, error) {
+	var a domain.DeploymentApproval
+	err := row.Scan(&a.ID, &a.DeploymentID, &a.AppID, &a.DomainName, &a.Branch, &a.Status,
+		&a.DecidedBy, &a.DecidedVia, &a.CreatedAt, &a.DecidedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan approval: %w", err)
+	}
+	return &a, nil
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type ArtifactRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewArtifactRepo(pool *pgxpool.Pool) domain.ArtifactRepository {
+	return &ArtifactRepo{pool: pool}
+}
+
+const artifactColumns = `a.deployment_id, a.app_id, d.domain_name, d.branch, a.sha256, a.size_bytes, a.created_at, a.pruned_at`
+
+func (r *ArtifactRepo) Record(ctx context.Context, a *domain.DeploymentArtifact) error {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO deployment_artifacts (deployment_id, app_id, sha256, size_bytes)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (deployment_id) DO UPDATE SET sha256 = EXCLUDED.sha256, size_bytes = EXCLUDED.size_bytes, pruned_at = NULL
+		RETURNING created_at
+	`, a.DeploymentID, a.AppID, a.SHA256, a.SizeBytes).Scan(&a.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record artifact: %w", err)
+	}
+	return nil
+}
+
+func (r *ArtifactRepo) Get(ctx context.Context, deploymentID string) (*domain.DeploymentArtifact, error) {
+	a, err := scanArtifact(r.pool.QueryRow(ctx, `
+		SELECT `+artifactColumns+`
+		FROM deployment_artifacts a JOIN deployments d ON d.id = a.deployment_id
+		WHERE a.deployment_id = $1
+	`, deploymentID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return a, err
+}
+
+func (r *ArtifactRepo) LinkRedeploy(ctx context.Context, deploymentID string, sourceDeploymentID string, by uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO deployment_redeploys (deployment_id, source_deployment_id, requested_by)
+		VALUES ($1, $2, $3)
+	`, deploymentID, sourceDeploymentID, by)
+	if err != nil {
+		return fmt.Errorf("failed to link redeploy: %w", err)
+	}
+	return nil
+}
+
+func (r *ArtifactRepo) UnlinkRedeploy(ctx context.Context, deploymentID string) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM deployment_redeploys WHERE deployment_id = $1`, deploymentID); err != nil {
+		return fmt.Errorf("failed to unlink redeploy: %w", err)
+	}
+	return nil
+}
+
+func (r *ArtifactRepo) RedeploySource(ctx context.Context, deploymentID string) (*domain.DeploymentArtifact, error) {
+	a, err := scanArtifact(r.pool.QueryRow(ctx, `
+		SELECT `+artifactColumns+`
+		FROM deployment_redeploys rd
+		JOIN deployment_artifacts a ON a.deployment_id = rd.source_deployment_id
+		JOIN deployments d ON d.id = a.deployment_id
+		WHERE rd.deployment_id = $1
+	`, deploymentID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return a, err
+}
+
+func (r *ArtifactRepo) ListPrunable(ctx context.Context, policy domain.ArtifactRetention, now time.Time, limit int) ([]domain.DeploymentArtifact, error) {
+	var cutoff *time.Time
+	if policy.MaxAge > 0 {
+		c := now.Add(-policy.MaxAge)
+		cutoff = &c
+	}
+
+	// rank 1 (the app's newest) and sources of queued redeploys are never returned
+	rows, err := r.pool.Query(ctx, `
+		WITH ranked AS (
+			SELECT `+artifactColumns+`,
+				ROW_NUMBER() OVER (PARTITION BY a.app_id ORDER BY a.created_at DESC) AS rank
+			FROM deployment_artifacts a JOIN deployments d ON d.id = a.deployment_id
+			WHERE a.pruned_at IS NULL
+		)
+		SELECT deployment_id, app_id, domain_name, branch, sha256, size_bytes, created_at, pruned_at
+		FROM ranked
+		WHERE rank > 1
+		  AND (rank > $1 OR ($2::timestamptz IS NOT NULL AND created_at < $2))
+		  AND NOT EXISTS (
+			SELECT 1 FROM deployment_redeploys rd JOIN deployments q ON q.id = rd.deployment_id
+			WHERE rd.source_deployment_id = ranked.deployment_id AND q.status IN ($3, $4, $5, $6)
+		  )
+		ORDER BY created_at
+		LIMIT $7
+	`, policy.KeepPerApp, cutoff,
+		domain.StatusPending, domain.StatusRunning, domain.StatusAwaitingApproval, domain.StatusScheduled, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prunable artifacts: %w", err)
+	}
+	defer rows.Close()
+
+	artifacts := []domain.DeploymentArtifact{}
+	for rows.Next() {
+		a, err := scanArtifact(rows)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, *a)
+	}
+	return artifacts, rows.Err()
+}
+
+func (r *ArtifactRepo) MarkPruned(ctx context.Context, deploymentIDs []string) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE deployment_artifacts SET pruned_at = NOW()
+		WHERE deployment_id = ANY($1::uuid[]) AND pruned_at IS NULL
+	`, deploymentIDs)
+	if err != nil {
+		return fmt.Errorf("failed to mark artifacts pruned: %w", err)
+	}
+	return nil
+}
+
+func scanArtifact(row pgx.Row) (*domain.DeploymentArtifact, error) {
+	var a domain.DeploymentArtifact
+	err := row.Scan(&a.DeploymentID, &a.AppID, &a.DomainName, &a.Branch, &a.SHA256, &a.SizeBytes, &a.CreatedAt, &a.PrunedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan artifact: %w", err)
+	}
+	return &a, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -41,67 +42,73 @@ func (r *AuditRepository) CreateAlert(ctx context.Context, alert *domain.SystemA
 	).Scan(&alert.ID, &alert.CreatedAt)
 }
 
+// alertSorts whitelists the columns clients may sort the Action Center by.
+var alertSorts = map[string]sortColumn{
+	"created_at": {expr: "created_at", cast: "timestamptz"},
+	"severity":   {expr: "severity", cast: "text"},
+}
+
 // GetFilteredAlerts builds a dynamic query for the Action Center UI.
-func (r *AuditRepository) GetFilteredAlerts(ctx context.Context, filter domain.AlertFilter) ([]domain.SystemAlert, int, error) {
-	// Base queries
-	baseQuery := `SELECT id, severity, category, resource_id, message, is_resolved, metadata, created_at FROM system_alerts WHERE 1=1`
-	countQuery := `SELECT COUNT(*) FROM system_alerts WHERE 1=1`
-	
-	filterSQL := ""
-	args := []any{}
-	argIdx := 1
+func (r *AuditRepository) GetFilteredAlerts(ctx context.Context, filter domain.AlertFilter) (domain.Page[domain.SystemAlert], error) {
+	page := filter.Page.Normalize()
+	q := &listQuery{}
 
 	// 🛡️ Tenant Isolation: Enforce resource-level scoping
 	if filter.ResourceID != uuid.Nil {
-		filterSQL += fmt.Sprintf(" AND resource_id = $%d", argIdx)
-		args = append(args, filter.ResourceID.String())
-		argIdx++
+		q.where("resource_id = " + q.arg(filter.ResourceID.String()))
 	}
 
 	if filter.Severity != "" {
-		filterSQL += fmt.Sprintf(" AND severity = $%d", argIdx)
-		args = append(args, filter.Severity)
-		argIdx++
+		q.where("severity = " + q.arg(filter.Severity))
+	}
+
+	if filter.Category != "" {
+		q.where("category = " + q.arg(filter.Category))
 	}
 
 	if filter.IsResolved != nil {
-		filterSQL += fmt.Sprintf(" AND is_resolved = $%d", argIdx)
-		args = append(args, *filter.IsResolved)
-		argIdx++
+		q.where("is_resolved = " + q.arg(*filter.IsResolved))
 	}
 
 	// 🛡️ JSONB Deep Search: Utilize GIN index for trace_id searches
 	if filter.TraceID != "" {
-		filterSQL += fmt.Sprintf(" AND metadata @> jsonb_build_object('trace_id', $%d::text)", argIdx)
-		args = append(args, filter.TraceID)
-		argIdx++
+		q.where(fmt.Sprintf("metadata @> jsonb_build_object('trace_id', %s::text)", q.arg(filter.TraceID)))
 	}
 
 	// Get total count for UI pagination
 	var totalCount int
-	err := r.pool.QueryRow(ctx, countQuery+filterSQL, args...).Scan(&totalCount)
+	err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM system_alerts"+q.whereSQL(), q.args...).Scan(&totalCount)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count alerts: %w", err)
+		return domain.Page[domain.SystemAlert]{}, fmt.Errorf("failed to count alerts: %w", err)
 	}
 
-	// 🛡️ SLA: Strict Pagination Limits
-	limit := filter.Limit
-	if limit <= 0 || limit > 100 { limit = 50 }
-	
-	finalQuery := fmt.Sprintf("%s%s ORDER BY created_at DESC LIMIT $%d OFFSET $%d", 
-		baseQuery, filterSQL, argIdx, argIdx+1)
-	
-	args = append(args, limit, filter.Offset)
+	// 🛡️ SLA: Strict Pagination Limits (keyset, capped at domain.MaxPageLimit)
+	tail, err := q.paginate(page, alertSorts, "created_at", "id")
+	if err != nil {
+		return domain.Page[domain.SystemAlert]{}, err
+	}
 
-	rows, err := r.pool.Query(ctx, finalQuery, args...)
+	finalQuery := `SELECT id, severity, category, resource_id, message, is_resolved, metadata, created_at FROM system_alerts` +
+		q.whereSQL() + tail
+
+	rows, err := r.pool.Query(ctx, finalQuery, q.args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to fetch alerts: %w", err)
+		return domain.Page[domain.SystemAlert]{}, fmt.Errorf("failed to fetch alerts: %w", err)
 	}
 	defer rows.Close()
 
 	// 🛡️ Performance: Scan directly into domain structs
 	alerts, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.SystemAlert])
-	return alerts, totalCount, err
+	if err != nil {
+		return domain.Page[domain.SystemAlert]{}, fmt.Errorf("failed to scan alerts: %w", err)
+	}
+
+	return buildPage(alerts, page, totalCount, func(a domain.SystemAlert) domain.Cursor {
+		if page.Sort == "severity" {
+			return domain.Cursor{Value: a.Severity, ID: a.ID}
+		}
+		return domain.Cursor{Value: a.CreatedAt.Format(time.RFC3339Nano), ID: a.ID}
+	}), nil
 }
 
 // ResolveAlert marks an issue as fixed and logs the resolver identity.
@@ -125,3 +132,136 @@ func (r *AuditRepository) ResolveAlert(ctx context.Context, alertID uuid.UUID, r
 
 	return nil
 }
+
+// CreateTenantLog appends an entry to the tenant's audit trail and the global hash chain.
+// 🛡️ Stability: Locking the single chain-head row serializes appends across Brain
+// replicas, so two writers can never both link to the same previous hash.
+func (r *AuditRepository) CreateTenantLog(ctx context.Context, entry *domain.TenantLog) error {
+	if entry.Metadata == nil {
+		entry.Metadata = make(map[string]any)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin audit append: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var lastSeq int64
+	var lastHash string
+	err = tx.QueryRow(ctx, `SELECT last_seq, last_hash FROM tenant_log_chain_head WHERE id FOR UPDATE`).
+		Scan(&lastSeq, &lastHash)
+	if err != nil {
+		return fmt.Errorf("failed to lock audit chain head: %w", err)
+	}
+
+	// Postgres stores microseconds; truncate so the hash survives the round-trip.
+	entry.ID = uuid.New()
+	entry.Seq = lastSeq + 1
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	rowHash := entry.ComputeHash(lastHash)
+	entry.PrevHash = &lastHash
+	entry.RowHash = &rowHash
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO tenant_logs (id, tenant_id, actor_id, action, resource_type, resource_id, metadata, created_at, chain_seq, prev_hash, row_hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`,
+		entry.ID, entry.TenantID, entry.ActorID, entry.Action, entry.ResourceType, entry.ResourceID,
+		entry.Metadata, entry.CreatedAt, entry.Seq, lastHash, rowHash,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert tenant log: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE tenant_log_chain_head SET last_seq = $1, last_hash = $2 WHERE id`, entry.Seq, rowHash)
+	if err != nil {
+		return fmt.Errorf("failed to advance audit chain head: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// TenantLogChainPage returns rows in chain order for integrity verification.
+func (r *AuditRepository) TenantLogChainPage(ctx context.Context, afterSeq int64, limit int) ([]domain.TenantLog, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+tenantLogColumns+` FROM tenant_logs WHERE chain_seq > $1 ORDER BY chain_seq ASC LIMIT $2`,
+		afterSeq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit chain: %w", err)
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[domain.TenantLog])
+}
+
+// TenantLogChainHead returns the last appended sequence number and hash.
+func (r *AuditRepository) TenantLogChainHead(ctx context.Context) (int64, string, error) {
+	var seq int64
+	var hash string
+	err := r.pool.QueryRow(ctx, `SELECT last_seq, last_hash FROM tenant_log_chain_head WHERE id`).Scan(&seq, &hash)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read audit chain head: %w", err)
+	}
+	return seq, hash, nil
+}
+
+// tenantLogColumns matches the db tags on domain.TenantLog.
+const tenantLogColumns = `id, tenant_id, actor_id, action, resource_type, resource_id, metadata, created_at, chain_seq, prev_hash, row_hash`
+
+// tenantLogSorts whitelists the columns clients may sort the audit trail by.
+var tenantLogSorts = map[string]sortColumn{
+	"created_at": {expr: "created_at", cast: "timestamptz"},
+	"action":     {expr: "action", cast: "text"},
+}
+
+// GetTenantLogs returns a keyset-paginated slice of the audit trail.
+func (r *AuditRepository) GetTenantLogs(ctx context.Context, filter domain.TenantLogFilter) (domain.Page[domain.TenantLog], error) {
+	page := filter.Page.Normalize()
+	q := &listQuery{}
+
+	// 🛡️ Tenant Isolation: uuid.Nil is only passed through for platform admins
+	if filter.TenantID != uuid.Nil {
+		q.where("tenant_id = " + q.arg(filter.TenantID))
+	}
+	if filter.ActorID != uuid.Nil {
+		q.where("actor_id = " + q.arg(filter.ActorID))
+	}
+	if filter.Action != "" {
+		q.where("action = " + q.arg(filter.Action))
+	}
+	if filter.ResourceType != "" {
+		q.where("resource_type = " + q.arg(filter.ResourceType))
+	}
+	if filter.TraceID != "" {
+		q.where(fmt.Sprintf("metadata @> jsonb_build_object('request', jsonb_build_object('trace_id', %s::text))", q.arg(filter.TraceID)))
+	}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*) FROM tenant_logs"+q.whereSQL(), q.args...).Scan(&total); err != nil {
+		return domain.Page[domain.TenantLog]{}, fmt.Errorf("failed to count tenant logs: %w", err)
+	}
+
+	tail, err := q.paginate(page, tenantLogSorts, "created_at", "id")
+	if err != nil {
+		return domain.Page[domain.TenantLog]{}, err
+	}
+
+	query := `SELECT ` + tenantLogColumns + ` FROM tenant_logs` + q.whereSQL() + tail
+	rows, err := r.pool.Query(ctx, query, q.args...)
+	if err != nil {
+		return domain.Page[domain.TenantLog]{}, fmt.Errorf("failed to fetch tenant logs: %w", err)
+	}
+	defer rows.Close()
+
+	logs, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.TenantLog])
+	if err != nil {
+		return domain.Page[domain.TenantLog]{}, fmt.Errorf("failed to scan tenant logs: %w", err)
+	}
+
+	return buildPage(logs, page, total, func(l domain.TenantLog) domain.Cursor {
+		if page.Sort == "action" {
+			return domain.Cursor{Value: l.Action, ID: l.ID}
+		}
+		return domain.Cursor{Value: l.CreatedAt.Format(time.RFC3339Nano), ID: l.ID}
+	}), nil
+}
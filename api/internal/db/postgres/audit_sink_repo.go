@@ -0,0 +1,76 @@
+// api/internal/db/postgres/audit_sink_repo.go
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type AuditSinkConfigRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuditSinkConfigRepo(pool *pgxpool.Pool) domain.AuditSinkConfigRepository {
+	return &AuditSinkConfigRepo{pool: pool}
+}
+
+// Get returns domain.ErrNotFound until an admin saves a configuration.
+func (r *AuditSinkConfigRepo) Get(ctx context.Context) (*domain.AuditSinkConfig, string, error) {
+	var cfg domain.AuditSinkConfig
+	var network, token *string
+	err := r.pool.QueryRow(ctx, `
+		SELECT enabled, kind, endpoint, network, token_ciphertext, updated_at
+		FROM audit_sink_config WHERE id
+	`).Scan(&cfg.Enabled, &cfg.Kind, &cfg.Endpoint, &network, &token, &cfg.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", domain.ErrNotFound
+		}
+		return nil, "", fmt.Errorf("failed to load audit sink config: %w", err)
+	}
+
+	if network != nil {
+		cfg.Network = *network
+	}
+	ciphertext := ""
+	if token != nil {
+		ciphertext = *token
+		cfg.HasToken = ciphertext != ""
+	}
+	return &cfg, ciphertext, nil
+}
+
+// Save upserts the single configuration row.
+func (r *AuditSinkConfigRepo) Save(ctx context.Context, cfg *domain.AuditSinkConfig, tokenCiphertext string) error {
+	var network, token *string
+	if cfg.Network != "" {
+		network = &cfg.Network
+	}
+	if tokenCiphertext != "" {
+		token = &tokenCiphertext
+	}
+
+	query := `
+		INSERT INTO audit_sink_config (id, enabled, kind, endpoint, network, token_ciphertext, updated_at)
+		VALUES (true, $1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			kind = EXCLUDED.kind,
+			endpoint = EXCLUDED.endpoint,
+			network = EXCLUDED.network,
+			token_ciphertext = EXCLUDED.token_ciphertext,
+			updated_at = NOW()
+		RETURNING updated_at
+	`
+	err := r.pool.QueryRow(ctx, query, cfg.Enabled, cfg.Kind, cfg.Endpoint, network, token).Scan(&cfg.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save audit sink config: %w", err)
+	}
+	cfg.HasToken = token != nil
+	return nil
+}
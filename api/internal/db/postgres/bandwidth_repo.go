@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type BandwidthRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewBandwidthRepo(pool *pgxpool.Pool) domain.BandwidthRepository {
+	return &BandwidthRepo{pool: pool}
+}
+
+func (r *BandwidthRepo) ListUsage(ctx context.Context, appID uuid.UUID, months int) ([]domain.BandwidthUsage, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT month, bytes_in, bytes_out
+		FROM app_bandwidth_monthly WHERE app_id = $1
+		ORDER BY month DESC LIMIT $2
+	`, appID, months)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bandwidth usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []domain.BandwidthUsage{}
+	for rows.Next() {
+		var u domain.BandwidthUsage
+		if err := rows.Scan(&u.Month, &u.BytesIn, &u.BytesOut); err != nil {
+			return nil, fmt.Errorf("failed to scan bandwidth usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
+
+func (r *BandwidthRepo) GetQuota(ctx context.Context, appID uuid.UUID) (*domain.TransferQuota, error) {
+	var q domain.TransferQuota
+	err := r.pool.QueryRow(ctx, `
+		SELECT quota_gb, action, throttle_kbps, state FROM app_transfer_quotas WHERE app_id = $1
+	`, appID).Scan(&q.QuotaGB, &q.Action, &q.ThrottleKBps, &q.State)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load transfer quota: %w", err)
+	}
+	return &q, nil
+}
+
+// UpsertQuota changes the limits only; enforcement state is owned by SetQuotaState.
+func (r *BandwidthRepo) UpsertQuota(ctx context.Context, appID uuid.UUID, q domain.TransferQuota) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO app_transfer_quotas (app_id, quota_gb, action, throttle_kbps)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (app_id) DO UPDATE SET
+			quota_gb = EXCLUDED.quota_gb, action = EXCLUDED.action,
+			throttle_kbps = EXCLUDED.throttle_kbps, updated_at = NOW()
+	`, appID, q.QuotaGB, q.Action, q.ThrottleKBps)
+	if err != nil {
+		return fmt.Errorf("failed to store transfer quota: %w", err)
+	}
+	return nil
+}
+
+func (r *BandwidthRepo) DeleteQuota(ctx context.Context, appID uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM app_transfer_quotas WHERE app_id = $1`, appID); err != nil {
+		return fmt.Errorf("failed to delete transfer quota: %w", err)
+	}
+	return nil
+}
+
+func (r *BandwidthRepo) QuotaStatuses(ctx context.Context, month time.Time, appID *uuid.UUID) ([]domain.QuotaStatus, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT q.app_id, pd.user_id,
+		       (SELECT array_agg(d.name ORDER BY d.name) FROM domains d WHERE d.app_id = q.app_id OR d.id = a.domain_id),
+		       q.quota_gb, q.action, q.throttle_kbps, q.state, q.enforced_month,
+		       COALESCE(m.bytes_in + m.bytes_out, 0)
+		FROM app_transfer_quotas q
+		JOIN applications a ON a.id = q.app_id
+		JOIN domains pd ON pd.id = a.domain_id
+		LEFT JOIN app_bandwidth_monthly m ON m.app_id = q.app_id AND m.month = $1
+		WHERE $2::uuid IS NULL OR q.app_id = $2
+	`, month, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quota statuses: %w", err)
+	}
+	defer rows.Close()
+
+	statuses := []domain.QuotaStatus{}
+	for rows.Next() {
+		var s domain.QuotaStatus
+		if err := rows.Scan(&s.AppID, &s.OwnerID, &s.Domains,
+			&s.Quota.QuotaGB, &s.Quota.Action, &s.Quota.ThrottleKBps, &s.Quota.State, &s.EnforcedMonth,
+			&s.Used); err != nil {
+			return nil, fmt.Errorf("failed to scan quota status: %w", err)
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, rows.Err()
+}
+
+func (r *BandwidthRepo) SetQuotaState(ctx context.Context, appID uuid.UUID, state string, month time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE app_transfer_quotas SET state = $2, enforced_month = $3, updated_at = NOW() WHERE app_id = $1
+	`, appID, state, month)
+	if err != nil {
+		return fmt.Errorf("failed to update quota state: %w", err)
+	}
+	return nil
+}
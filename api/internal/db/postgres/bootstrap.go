@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+// ApplyMigrations brings a fresh database up to date for headless setup.
+// Migrations normally run as Postgres init scripts and keep no ledger, so
+// the schema sentinels decide: a complete schema gets nothing, an empty
+// database gets every file in name order (as initdb runs them), and a
+// partial one is refused rather than guessed at.
+func ApplyMigrations(ctx context.Context, pool *pgxpool.Pool, files fs.FS) (int, error) {
+	missing, err := NewSchemaCheck(pool).MissingRelations(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	var initialized bool
+	if err := pool.QueryRow(ctx, `SELECT to_regclass('users') IS NOT NULL`).Scan(&initialized); err != nil {
+		return 0, fmt.Errorf("failed to inspect schema: %w", err)
+	}
+	if initialized {
+		return 0, fmt.Errorf("database is partially migrated (missing %s); apply the remaining migrations by hand",
+			strings.Join(missing, ", "))
+	}
+
+	names, err := fs.Glob(files, "*.sql")
+	if err != nil {
+		return 0, err
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		sql, err := fs.ReadFile(files, name)
+		if err != nil {
+			return i, err
+		}
+		// Simple protocol: a migration file is many statements
+		if _, err := pool.Exec(ctx, string(sql), pgx.QueryExecModeSimpleProtocol); err != nil {
+			return i, fmt.Errorf("migration %s failed: %w", name, err)
+		}
+	}
+	return len(names), nil
+}
+
+// BootstrapAdmin creates the first Super Admin (rank 0). Re-running with the
+// same email is a no-op, so a retried setup converges; any other existing
+// rank-0 account means the panel was already set up.
+func BootstrapAdmin(ctx context.Context, pool *pgxpool.Pool, email, passwordHash string) (uuid.UUID, bool, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("failed to begin admin bootstrap: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var existingID uuid.UUID
+	var existingEmail string
+	err = tx.QueryRow(ctx, `
+		SELECT u.id, u.email FROM users u JOIN roles r ON u.role_id = r.id
+		WHERE r.rank = 0 ORDER BY u.created_at LIMIT 1`).Scan(&existingID, &existingEmail)
+	switch {
+	case err == nil && existingEmail == email:
+		return existingID, false, nil
+	case err == nil:
+		return uuid.Nil, false, fmt.Errorf("%w: a Super Admin (%s) already exists", domain.ErrConflict, existingEmail)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return uuid.Nil, false, fmt.Errorf("failed to look up admins: %w", err)
+	}
+
+	var roleID uuid.UUID
+	if err := tx.QueryRow(ctx, `SELECT id FROM roles WHERE rank = 0 ORDER BY name LIMIT 1`).Scan(&roleID); err != nil {
+		return uuid.Nil, false, fmt.Errorf("no rank-0 role seeded: %w", err)
+	}
+
+	var id uuid.UUID
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO users (email, password_hash, role_id)
+		VALUES ($1, $2, $3)
+		RETURNING id`, email, passwordHash, roleID).Scan(&id); err != nil {
+		return uuid.Nil, false, fmt.Errorf("failed to create admin: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, false, fmt.Errorf("failed to commit admin bootstrap: %w", err)
+	}
+	return id, true, nil
+}
+
+// RecoverAdmin is the break-glass path behind "kari-api recover-admin". With
+// create it adds a new rank-0 account; otherwise it resets the password of
+// the existing rank-0 account with that email, reactivating it and revoking
+// its sessions. Either way the password must be changed at next login.
+//
+// 🛡️ The account's passkeys go in the same transaction (a lost authenticator
+// is the usual reason to be here) and it gets one passkey-free sign-in to
+// enrol a new one, even where Rank 0 requires passkeys. Returns how many
+// passkeys were removed.
+func RecoverAdmin(ctx context.Context, pool *pgxpool.Pool, email, passwordHash string, create bool) (uuid.UUID, int64, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to begin recovery: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id uuid.UUID
+	if create {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO users (email, password_hash, role_id, must_change_password, passkey_reenroll)
+			SELECT $1, $2, id, true, true FROM roles WHERE rank = 0 ORDER BY name LIMIT 1
+			ON CONFLICT (email) DO NOTHING
+			RETURNING id`, email, passwordHash).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, 0, fmt.Errorf("%w: %s already exists (or no rank-0 role is seeded); reset it instead", domain.ErrConflict, email)
+		}
+		if err != nil {
+			return uuid.Nil, 0, fmt.Errorf("failed to create admin: %w", err)
+		}
+	} else {
+		err := tx.QueryRow(ctx, `
+			UPDATE users u
+			SET password_hash = $2, password_changed_at = NOW(), must_change_password = true,
+			    is_active = true, refresh_token = NULL, passkey_reenroll = true, updated_at = NOW()
+			FROM roles r
+			WHERE u.role_id = r.id AND r.rank = 0 AND u.email = $1
+			RETURNING u.id`, email, passwordHash).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, 0, fmt.Errorf("%w: no rank-0 account %s; use --create for a new one", domain.ErrNotFound, email)
+		}
+		if err != nil {
+			return uuid.Nil, 0, fmt.Errorf("failed to reset admin: %w", err)
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM user_passkeys WHERE user_id = $1`, id)
+	if err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to remove passkeys: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM webauthn_sessions WHERE user_id = $1`, id); err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to clear passkey ceremonies: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, 0, fmt.Errorf("failed to commit recovery: %w", err)
+	}
+	return id, tag.RowsAffected(), nil
+}
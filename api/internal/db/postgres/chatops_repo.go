@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type ChatOpsRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewChatOpsRepo(pool *pgxpool.Pool) domain.ChatOpsRepository {
+	return &ChatOpsRepo{pool: pool}
+}
+
+func (r *ChatOpsRepo) Resolve(ctx context.Context, provider, teamID, externalUserID string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		SELECT user_id FROM chatops_identities
+		WHERE provider = $1 AND team_id = $2 AND external_user_id = $3
+	`, provider, teamID, externalUserID).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, domain.ErrNotFound
+	}
+	return userID, err
+}
+
+// Link replaces any existing mapping, so re-linking moves the chat user to
+// the account that issued the newest code.
+func (r *ChatOpsRepo) Link(ctx context.Context, identity *domain.ChatIdentity) error {
+	return r.pool.QueryRow(ctx, `
+		INSERT INTO chatops_identities (provider, team_id, external_user_id, user_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (provider, team_id, external_user_id)
+		DO UPDATE SET user_id = EXCLUDED.user_id, created_at = NOW()
+		RETURNING created_at
+	`, identity.Provider, identity.TeamID, identity.ExternalUserID, identity.UserID).Scan(&identity.CreatedAt)
+}
+
+func (r *ChatOpsRepo) Unlink(ctx context.Context, provider, teamID, externalUserID string) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM chatops_identities
+		WHERE provider = $1 AND team_id = $2 AND external_user_id = $3
+	`, provider, teamID, externalUserID)
+	if err != nil {
+		return fmt.Errorf("failed to unlink chat identity: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ChatOpsRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.ChatIdentity, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT provider, team_id, external_user_id, user_id, created_at
+		FROM chatops_identities WHERE user_id = $1
+		ORDER BY created_at
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chat identities: %w", err)
+	}
+	defer rows.Close()
+
+	identities := []domain.ChatIdentity{}
+	for rows.Next() {
+		var i domain.ChatIdentity
+		if err := rows.Scan(&i.Provider, &i.TeamID, &i.ExternalUserID, &i.UserID, &i.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan chat identity: %w", err)
+		}
+		identities = append(identities, i)
+	}
+	return identities, rows.Err()
+}
+
+func (r *ChatOpsRepo) CreateLinkCode(ctx context.Context, codeHash string, userID uuid.UUID, expiresAt time.Time) error {
+	// Expired codes are swept on the way in; they are never redeemable anyway
+	if _, err := r.pool.Exec(ctx, `DELETE FROM chatops_link_codes WHERE expires_at < NOW()`); err != nil {
+		return fmt.Errorf("failed to sweep link codes: %w", err)
+	}
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO chatops_link_codes (code_hash, user_id, expires_at) VALUES ($1, $2, $3)
+	`, codeHash, userID, expiresAt)
+	return err
+}
+
+func (r *ChatOpsRepo) ConsumeLinkCode(ctx context.Context, codeHash string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		DELETE FROM chatops_link_codes
+		WHERE code_hash = $1 AND expires_at > NOW()
+		RETURNING user_id
+	`, codeHash).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, domain.ErrNotFound
+	}
+	return userID, err
+}
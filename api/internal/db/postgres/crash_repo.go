@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type CrashRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewCrashRepo(pool *pgxpool.Pool) domain.CrashRepository {
+	return &CrashRepo{pool: pool}
+}
+
+func (r *CrashRepo) Create(ctx context.Context, c *domain.CrashReport) error {
+	logs := c.RecentLogs
+	if logs == nil {
+		logs = []domain.CrashLogLine{}
+	}
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO crash_reports (id, source, component, panic, stack, request, recent_logs, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, c.ID, c.Source, c.Component, c.Panic, c.Stack, c.Request, logs, c.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record crash report: %w", err)
+	}
+	return nil
+}
+
+func (r *CrashRepo) List(ctx context.Context, limit int) ([]domain.CrashReport, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, source, component, panic, request, exported_at, created_at
+		FROM crash_reports
+		ORDER BY created_at DESC LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list crash reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []domain.CrashReport{}
+	for rows.Next() {
+		var c domain.CrashReport
+		if err := rows.Scan(&c.ID, &c.Source, &c.Component, &c.Panic, &c.Request, &c.ExportedAt, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan crash report: %w", err)
+		}
+		reports = append(reports, c)
+	}
+	return reports, rows.Err()
+}
+
+func (r *CrashRepo) Get(ctx context.Context, id uuid.UUID) (*domain.CrashReport, error) {
+	var c domain.CrashReport
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, source, component, panic, stack, request, recent_logs, exported_at, created_at
+		FROM crash_reports WHERE id = $1
+	`, id).Scan(&c.ID, &c.Source, &c.Component, &c.Panic, &c.Stack, &c.Request, &c.RecentLogs, &c.ExportedAt, &c.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load crash report: %w", err)
+	}
+	return &c, nil
+}
+
+func (r *CrashRepo) MarkExported(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `UPDATE crash_reports SET exported_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark crash report exported: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type DeployKeyRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDeployKeyRepo(pool *pgxpool.Pool) domain.DeployKeyRepository {
+	return &DeployKeyRepo{pool: pool}
+}
+
+func (r *DeployKeyRepo) Upsert(ctx context.Context, key *domain.DeployKey) error {
+	query := `
+		INSERT INTO app_deploy_keys (app_id, public_key, fingerprint, private_key_ciphertext)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (app_id) DO UPDATE SET
+			public_key = EXCLUDED.public_key,
+			fingerprint = EXCLUDED.fingerprint,
+			private_key_ciphertext = EXCLUDED.private_key_ciphertext,
+			created_at = NOW()
+		RETURNING created_at
+	`
+	err := r.pool.QueryRow(ctx, query, key.AppID, key.PublicKey, key.Fingerprint, key.PrivateKeyCiphertext).
+		Scan(&key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store deploy key: %w", err)
+	}
+	return nil
+}
+
+func (r *DeployKeyRepo) GetByAppID(ctx context.Context, appID uuid.UUID) (*domain.DeployKey, error) {
+	var key domain.DeployKey
+	err := r.pool.QueryRow(ctx, `
+		SELECT app_id, public_key, fingerprint, private_key_ciphertext, created_at
+		FROM app_deploy_keys WHERE app_id = $1
+	`, appID).Scan(&key.AppID, &key.PublicKey, &key.Fingerprint, &key.PrivateKeyCiphertext, &key.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load deploy key: %w", err)
+	}
+	return &key, nil
+}
+
+func (r *DeployKeyRepo) Delete(ctx context.Context, appID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM app_deploy_keys WHERE app_id = $1`, appID)
+	if err != nil {
+		return fmt.Errorf("failed to delete deploy key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
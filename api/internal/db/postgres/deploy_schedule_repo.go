@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type DeployScheduleRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDeployScheduleRepo(pool *pgxpool.Pool) domain.DeploymentScheduleRepository {
+	return &DeployScheduleRepo{pool: pool}
+}
+
+const scheduledColumns = `id, app_id, domain_name, branch, status, scheduled_at, scheduled_by`
+
+func (r *DeployScheduleRepo) SetSchedule(ctx context.Context, deploymentID string, at time.Time, by uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE deployments SET scheduled_at = $2, scheduled_by = $3, updated_at = NOW()
+		WHERE id = $1
+	`, deploymentID, at, by)
+	if err != nil {
+		return fmt.Errorf("failed to schedule deployment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *DeployScheduleRepo) Get(ctx context.Context, deploymentID string) (*domain.ScheduledDeployment, error) {
+	d, err := scanScheduled(r.pool.QueryRow(ctx, `
+		SELECT `+scheduledColumns+` FROM deployments
+		WHERE id = $1 AND scheduled_at IS NOT NULL
+	`, deploymentID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return d, err
+}
+
+func (r *DeployScheduleRepo) ListWaiting(ctx context.Context, scheduledBy *uuid.UUID, limit int) ([]domain.ScheduledDeployment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+scheduledColumns+` FROM deployments
+		WHERE status IN ($1, $2) AND scheduled_at > NOW()
+		  AND ($3::uuid IS NULL OR scheduled_by = $3)
+		ORDER BY scheduled_at
+		LIMIT $4
+	`, domain.StatusScheduled, domain.StatusAwaitingApproval, scheduledBy, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled deployments: %w", err)
+	}
+	defer rows.Close()
+
+	scheduled := []domain.ScheduledDeployment{}
+	for rows.Next() {
+		d, err := scanScheduled(rows)
+		if err != nil {
+			return nil, err
+		}
+		scheduled = append(scheduled, *d)
+	}
+	return scheduled, rows.Err()
+}
+
+func (r *DeployScheduleRepo) Cancel(ctx context.Context, deploymentID string) error {
+	// 🛡️ Concurrency: Only a still-scheduled row flips, so a cancel racing the
+	// scheduler either wins outright or reports the build already started
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE deployments SET status = $2, updated_at = NOW()
+		WHERE id = $1 AND status = $3
+	`, deploymentID, domain.StatusCancelled, domain.StatusScheduled)
+	if err != nil {
+		return fmt.Errorf("failed to cancel deployment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: deployment is no longer scheduled", domain.ErrConflict)
+	}
+	return nil
+}
+
+func (r *DeployScheduleRepo) PromoteDue(ctx context.Context, now time.Time) ([]domain.ScheduledDeployment, error) {
+	rows, err := r.pool.Query(ctx, `
+		UPDATE deployments SET status = $1, updated_at = NOW()
+		WHERE status = $2 AND scheduled_at <= $3
+		RETURNING `+scheduledColumns,
+		domain.StatusPending, domain.StatusScheduled, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to promote scheduled deployments: %w", err)
+	}
+	defer rows.Close()
+
+	promoted := []domain.ScheduledDeployment{}
+	for rows.Next() {
+		d, err := scanScheduled(rows)
+		if err != nil {
+			return nil, err
+		}
+		promoted = append(promoted, *d)
+	}
+	return promoted, rows.Err()
+}
+
+func scanScheduled(row pgx.Row) (*domain.ScheduledDeployment, error) {
+	var d domain.ScheduledDeployment
+	err := row.Scan(&d.ID, &d.AppID, &d.DomainName, &d.Branch, &d.Status, &d.ScheduledAt, &d.ScheduledBy)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan scheduled deployment: %w", err)
+	}
+	return &d, nil
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type DeploymentLogRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDeploymentLogRepo(pool *pgxpool.Pool) domain.DeploymentLogRepository {
+	return &DeploymentLogRepo{pool: pool}
+}
+
+func (r *DeploymentLogRepo) GetDeployment(ctx context.Context, id uuid.UUID) (*domain.DeploymentLogRecord, error) {
+	var d domain.DeploymentLogRecord
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, app_id, domain_name, status, updated_at FROM deployments WHERE id = $1
+	`, id).Scan(&d.DeploymentID, &d.AppID, &d.DomainName, &d.Status, &d.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load deployment: %w", err)
+	}
+	return &d, nil
+}
+
+func (r *DeploymentLogRepo) ListLines(ctx context.Context, id uuid.UUID, afterSeq int64, limit int) ([]domain.DeploymentLogLine, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT seq, level, stage, content, created_at
+		FROM deployment_logs
+		WHERE deployment_id = $1 AND seq > $2
+		ORDER BY seq
+		LIMIT $3
+	`, id, afterSeq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deployment logs: %w", err)
+	}
+	defer rows.Close()
+
+	lines := []domain.DeploymentLogLine{}
+	for rows.Next() {
+		var l domain.DeploymentLogLine
+		if err := rows.Scan(&l.Seq, &l.Level, &l.Stage, &l.Content, &l.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan deployment log: %w", err)
+		}
+		lines = append(lines, l)
+	}
+	return lines, rows.Err()
+}
+
+// StreamChunks reads row by row, so a long build log is never held in memory.
+func (r *DeploymentLogRepo) StreamChunks(ctx context.Context, id uuid.UUID, fn func(content string) error) error {
+	rows, err := r.pool.Query(ctx, `
+		SELECT content FROM deployment_logs WHERE deployment_id = $1 ORDER BY seq
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to read deployment logs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var content string
+		if err := rows.Scan(&content); err != nil {
+			return fmt.Errorf("failed to scan deployment log: %w", err)
+		}
+		if err := fn(content); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *DeploymentLogRepo) GetArchive(ctx context.Context, id uuid.UUID) (*domain.DeploymentLogArchive, error) {
+	var a domain.DeploymentLogArchive
+	err := r.pool.QueryRow(ctx, `
+		SELECT deployment_id, object_key, compressed_bytes, chunks, archived_at
+		FROM deployment_log_archives WHERE deployment_id = $1
+	`, id).Scan(&a.DeploymentID, &a.ObjectKey, &a.CompressedBytes, &a.Chunks, &a.ArchivedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load log archive: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *DeploymentLogRepo) ListArchivable(ctx context.Context, cutoff time.Time, limit int) ([]domain.DeploymentLogRecord, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT d.id, d.app_id, d.domain_name, d.status, d.updated_at
+		FROM deployments d
+		WHERE UPPER(d.status) IN ('SUCCESS', 'FAILED', 'CANCELLED')
+		  AND d.updated_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM deployment_log_archives a WHERE a.deployment_id = d.id)
+		  AND EXISTS (SELECT 1 FROM deployment_logs l WHERE l.deployment_id = d.id)
+		ORDER BY d.updated_at
+		LIMIT $2
+	`, cutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archivable deployments: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.DeploymentLogRecord
+	for rows.Next() {
+		var d domain.DeploymentLogRecord
+		if err := rows.Scan(&d.DeploymentID, &d.AppID, &d.DomainName, &d.Status, &d.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan deployment: %w", err)
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (r *DeploymentLogRepo) CompleteArchive(ctx context.Context, a *domain.DeploymentLogArchive) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin archive transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO deployment_log_archives (deployment_id, object_key, compressed_bytes, chunks)
+		VALUES ($1, $2, $3, $4)
+		RETURNING archived_at
+	`, a.DeploymentID, a.ObjectKey, a.CompressedBytes, a.Chunks).Scan(&a.ArchivedAt); err != nil {
+		return fmt.Errorf("failed to record log archive: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM deployment_logs WHERE deployment_id = $1`, a.DeploymentID); err != nil {
+		return fmt.Errorf("failed to delete archived logs: %w", err)
+	}
+	return tx.Commit(ctx)
+}
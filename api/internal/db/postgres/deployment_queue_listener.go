@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// deploymentQueueChannel is notified by the deployments_queued trigger
+// (migration 047) whenever a deployment becomes PENDING.
+const deploymentQueueChannel = "kari_deployments"
+
+// DeploymentQueueListener wakes the deployment worker the moment a build is
+// queued, by this replica or any other.
+type DeploymentQueueListener struct {
+	pool *pgxpool.Pool
+}
+
+func NewDeploymentQueueListener(pool *pgxpool.Pool) *DeploymentQueueListener {
+	return &DeploymentQueueListener{pool: pool}
+}
+
+func (l *DeploymentQueueListener) Listen(ctx context.Context, wake func()) error {
+	return listen(ctx, l.pool, deploymentQueueChannel, func(string) { wake() })
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+
 	"kari/api/internal/core/domain"
 )
 
@@ -59,11 +61,54 @@ func (r *PostgresDeploymentRepository) ClaimNextPending(ctx context.Context) (*d
 }
 
 // AppendLog 🛡️ SLA Visibility
-// Writes a log chunk to the database for the Kari Panel UI to consume.
-func (r *PostgresDeploymentRepository) AppendLog(ctx context.Context, deploymentID string, content string) error {
-	query := `INSERT INTO deployment_logs (deployment_id, content) VALUES ($1, $2)`
-	_, err := r.db.ExecContext(ctx, query, deploymentID, content)
-	return err
+// Writes a log message to the database for the Kari Panel UI to consume.
+func (r *PostgresDeploymentRepository) AppendLog(ctx context.Context, deploymentID string, msg domain.LogMessage) error {
+	return r.AppendLogs(ctx, deploymentID, []domain.LogMessage{msg})
+}
+
+// AppendLogs writes a batch of log messages in one multi-row INSERT, numbered
+// with consecutive per-deployment sequence numbers in stream order.
+// 🛡️ Ordering: Reserving the numbers takes the deployment's row lock until
+// commit, so concurrent writers commit in sequence order and a reader paging
+// with ?after_seq= never skips a row that becomes visible later.
+func (r *PostgresDeploymentRepository) AppendLogs(ctx context.Context, deploymentID string, msgs []domain.LogMessage) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var last int64
+	err = tx.QueryRowContext(ctx,
+		`UPDATE deployments SET log_seq = log_seq + $2 WHERE id = $1 RETURNING log_seq`,
+		deploymentID, len(msgs),
+	).Scan(&last)
+	if err != nil {
+		return fmt.Errorf("db: failed to reserve log sequence: %w", err)
+	}
+	first := last - int64(len(msgs)) + 1
+
+	var query strings.Builder
+	query.WriteString(`INSERT INTO deployment_logs (deployment_id, seq, level, stage, content, created_at) VALUES `)
+	args := make([]any, 0, 1+5*len(msgs))
+	args = append(args, deploymentID)
+	for i, msg := range msgs {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		n := len(args)
+		fmt.Fprintf(&query, "($1, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5)
+		args = append(args, first+int64(i), msg.Level, msg.Stage, msg.Content, msg.Timestamp)
+	}
+
+	if _, err := tx.ExecContext(ctx, query.String(), args...); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // UpdateStatus 🛡️ State Machine Integrity
@@ -0,0 +1,253 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+// digestListLimit caps each detail section; counts stay exact.
+const digestListLimit = 10
+
+type DigestRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDigestRepo(pool *pgxpool.Pool) domain.DigestRepository {
+	return &DigestRepo{pool: pool}
+}
+
+// digestAdmins selects active users allowed to manage the server, the same
+// permission that guards the Action Center admin routes.
+const digestAdmins = `
+	SELECT DISTINCT u.id FROM users u
+	JOIN role_permissions rp ON rp.role_id = u.role_id
+	JOIN permissions p ON p.id = rp.permission_id
+	WHERE u.is_active AND p.resource = 'server' AND p.action = 'manage'
+`
+
+// ==============================================================================
+// 1. Preferences
+// ==============================================================================
+
+func (r *DigestRepo) ListRecipients(ctx context.Context) ([]domain.DigestRecipient, error) {
+	if _, err := r.pool.Exec(ctx, `
+		INSERT INTO digest_preferences (user_id) `+digestAdmins+`
+		ON CONFLICT (user_id) DO NOTHING
+	`); err != nil {
+		return nil, fmt.Errorf("failed to seed digest preferences: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT u.id, u.email, dp.frequency, COALESCE(u.timezone, ''), dp.unsubscribe_token
+		FROM digest_preferences dp
+		JOIN users u ON u.id = dp.user_id
+		WHERE u.id IN (`+digestAdmins+`)
+		ORDER BY u.email
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list digest recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var recipients []domain.DigestRecipient
+	for rows.Next() {
+		var rc domain.DigestRecipient
+		if err := rows.Scan(&rc.UserID, &rc.Email, &rc.Frequency, &rc.Timezone, &rc.UnsubscribeToken); err != nil {
+			return nil, fmt.Errorf("failed to scan digest recipient: %w", err)
+		}
+		recipients = append(recipients, rc)
+	}
+	return recipients, rows.Err()
+}
+
+func (r *DigestRepo) GetPreference(ctx context.Context, userID uuid.UUID) (domain.DigestFrequency, error) {
+	var f domain.DigestFrequency
+	err := r.pool.QueryRow(ctx, `SELECT frequency FROM digest_preferences WHERE user_id = $1`, userID).Scan(&f)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.DigestWeekly, nil // Not yet seeded by the first digest run
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load digest preference: %w", err)
+	}
+	return f, nil
+}
+
+func (r *DigestRepo) SetPreference(ctx context.Context, userID uuid.UUID, f domain.DigestFrequency) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO digest_preferences (user_id, frequency) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET frequency = EXCLUDED.frequency, updated_at = NOW()
+	`, userID, f)
+	if err != nil {
+		return fmt.Errorf("failed to save digest preference: %w", err)
+	}
+	return nil
+}
+
+func (r *DigestRepo) UnsubscribeByToken(ctx context.Context, token string) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE digest_preferences SET frequency = 'off', updated_at = NOW()
+		WHERE unsubscribe_token = $1
+	`, token)
+	if err != nil {
+		return fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ==============================================================================
+// 2. Summary
+// ==============================================================================
+
+func (r *DigestRepo) Summarize(ctx context.Context, since, renewalHorizon time.Time) (*domain.DigestSummary, error) {
+	s := &domain.DigestSummary{Since: since, UnresolvedAlerts: map[string]int{}}
+
+	// 1. Unresolved alerts, whenever they were raised
+	rows, err := r.pool.Query(ctx, `
+		SELECT severity, COUNT(*) FROM system_alerts WHERE NOT is_resolved GROUP BY severity
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unresolved alerts: %w", err)
+	}
+	for rows.Next() {
+		var severity string
+		var n int
+		if err := rows.Scan(&severity, &n); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan alert counts: %w", err)
+		}
+		s.UnresolvedAlerts[severity] = n
+	}
+	rows.Close()
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT id, severity, category, resource_id, message, is_resolved, metadata, created_at
+		FROM system_alerts WHERE NOT is_resolved
+		ORDER BY CASE severity WHEN 'fatal' THEN 0 WHEN 'critical' THEN 1 WHEN 'warning' THEN 2 ELSE 3 END,
+		         created_at DESC
+		LIMIT $1
+	`, digestListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load top alerts: %w", err)
+	}
+	s.TopAlerts, err = pgx.CollectRows(rows, pgx.RowToStructByName[domain.SystemAlert])
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan alerts: %w", err)
+	}
+
+	// 2. Certificates expiring before the horizon (or already failing)
+	rows, err = r.pool.Query(ctx, `
+		SELECT common_name, expires_at, status FROM ssl_certificates
+		WHERE status <> 'revoked' AND (expires_at < $1 OR status = 'failed')
+		ORDER BY expires_at
+		LIMIT $2
+	`, renewalHorizon, digestListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate renewals: %w", err)
+	}
+	for rows.Next() {
+		var c domain.CertificateRenewal
+		if err := rows.Scan(&c.DomainName, &c.ExpiresAt, &c.Status); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan certificate renewal: %w", err)
+		}
+		s.RenewalsDue = append(s.RenewalsDue, c)
+	}
+	rows.Close()
+
+	// 3. Deployments that failed during the period
+	if err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM deployments WHERE UPPER(status) = 'FAILED' AND updated_at >= $1
+	`, since).Scan(&s.FailedDeployCount); err != nil {
+		return nil, fmt.Errorf("failed to count failed deployments: %w", err)
+	}
+	rows, err = r.pool.Query(ctx, `
+		SELECT id, app_id, domain_name, updated_at FROM deployments
+		WHERE UPPER(status) = 'FAILED' AND updated_at >= $1
+		ORDER BY updated_at DESC
+		LIMIT $2
+	`, since, digestListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load failed deployments: %w", err)
+	}
+	for rows.Next() {
+		var d domain.FailedDeploy
+		if err := rows.Scan(&d.ID, &d.AppID, &d.DomainName, &d.FailedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan failed deployment: %w", err)
+		}
+		s.FailedDeploys = append(s.FailedDeploys, d)
+	}
+	rows.Close()
+
+	// 4. Resource trend: month to date against the whole previous month
+	month := domain.CurrentMonth(time.Now())
+	for _, m := range []struct {
+		month time.Time
+		dst   *domain.UsageTotals
+	}{
+		{month, &s.UsageThisMonth},
+		{month.AddDate(0, -1, 0), &s.UsageLastMonth},
+	} {
+		if err := r.pool.QueryRow(ctx, `
+			SELECT COALESCE(SUM(cpu_seconds), 0), COALESCE(SUM(ram_gb_hours), 0),
+			       COALESCE(SUM(storage_gb_hours), 0), COALESCE(SUM(bandwidth_bytes), 0)::BIGINT
+			FROM usage_meter_monthly WHERE month = $1
+		`, m.month).Scan(&m.dst.CPUSeconds, &m.dst.RAMGBHours, &m.dst.StorageGBHours, &m.dst.BandwidthBytes); err != nil {
+			return nil, fmt.Errorf("failed to total usage: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+// ==============================================================================
+// 3. Delivery Ledger
+// ==============================================================================
+
+func (r *DigestRepo) ClaimDelivery(ctx context.Context, userID uuid.UUID, period string, staleAfter time.Duration) (bool, error) {
+	var claimed uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO digest_deliveries (user_id, period) VALUES ($1, $2)
+		ON CONFLICT (user_id, period) DO UPDATE SET claimed_at = NOW()
+		WHERE digest_deliveries.sent_at IS NULL AND digest_deliveries.claimed_at < $3
+		RETURNING user_id
+	`, userID, period, time.Now().Add(-staleAfter)).Scan(&claimed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to claim digest delivery: %w", err)
+	}
+	return true, nil
+}
+
+func (r *DigestRepo) MarkDelivered(ctx context.Context, userID uuid.UUID, period string) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE digest_deliveries SET sent_at = NOW() WHERE user_id = $1 AND period = $2
+	`, userID, period)
+	if err != nil {
+		return fmt.Errorf("failed to record digest delivery: %w", err)
+	}
+	return nil
+}
+
+// ReleaseDelivery drops a claim after a failed send so the next pass retries.
+func (r *DigestRepo) ReleaseDelivery(ctx context.Context, userID uuid.UUID, period string) error {
+	_, err := r.pool.Exec(ctx, `
+		DELETE FROM digest_deliveries WHERE user_id = $1 AND period = $2 AND sent_at IS NULL
+	`, userID, period)
+	if err != nil {
+		return fmt.Errorf("failed to release digest delivery: %w", err)
+	}
+	return nil
+}
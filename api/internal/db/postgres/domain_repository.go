@@ -2,10 +2,12 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 	"kari/api/internal/core/domain"
 )
@@ -18,7 +20,8 @@ func NewDomainRepository(db *sqlx.DB) *DomainRepository {
 	return &DomainRepository{db: db}
 }
 
-// Create persists the domain intent and ensures global uniqueness.
+// Create persists the domain intent and ensures global uniqueness. The
+// owner's domain quota is enforced by the insert itself (056).
 func (r *DomainRepository) Create(ctx context.Context, d *domain.Domain) error {
 	query := `
 		INSERT INTO domains (id, app_id, name, status, target_port, created_at, updated_at)
@@ -30,6 +33,10 @@ func (r *DomainRepository) Create(ctx context.Context, d *domain.Domain) error {
 
 	_, err := r.db.NamedExecContext(ctx, query, d)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == sqlStateQuotaExceeded {
+			return fmt.Errorf("%w: %s", domain.ErrQuotaExceeded, pgErr.Message)
+		}
 		// 🛡️ Zero-Trust: Catching unique constraint violations specifically
 		return fmt.Errorf("domain already registered or database error: %w", err)
 	}
@@ -60,3 +67,47 @@ func (r *DomainRepository) Delete(ctx context.Context, name string) error {
 	_, err := r.db.ExecContext(ctx, query, name)
 	return err
 }
+
+// domainSorts whitelists the columns clients may sort by.
+var domainSorts = map[string]sortColumn{
+	"created_at": {expr: "created_at", cast: "timestamptz"},
+	"name":       {expr: "name", cast: "text"},
+	"status":     {expr: "status", cast: "text"},
+}
+
+// List returns a keyset-paginated, owner-scoped slice of domains.
+func (r *DomainRepository) List(ctx context.Context, filter domain.DomainFilter, page domain.PageRequest) (domain.Page[domain.Domain], error) {
+	page = page.Normalize()
+
+	q := &listQuery{}
+	q.where("user_id = " + q.arg(filter.OwnerID))
+	if filter.Status != "" {
+		q.where("status = " + q.arg(filter.Status))
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM domains"+q.whereSQL(), q.args...); err != nil {
+		return domain.Page[domain.Domain]{}, fmt.Errorf("failed to count domains: %w", err)
+	}
+
+	tail, err := q.paginate(page, domainSorts, "created_at", "id")
+	if err != nil {
+		return domain.Page[domain.Domain]{}, err
+	}
+
+	var domains []domain.Domain
+	if err := r.db.SelectContext(ctx, &domains, "SELECT * FROM domains"+q.whereSQL()+tail, q.args...); err != nil {
+		return domain.Page[domain.Domain]{}, fmt.Errorf("failed to list domains: %w", err)
+	}
+
+	return buildPage(domains, page, total, func(d domain.Domain) domain.Cursor {
+		switch page.Sort {
+		case "name":
+			return domain.Cursor{Value: d.Name, ID: d.ID}
+		case "status":
+			return domain.Cursor{Value: d.Status, ID: d.ID}
+		default:
+			return domain.Cursor{Value: d.CreatedAt.Format(time.RFC3339Nano), ID: d.ID}
+		}
+	}), nil
+}
@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type DriftRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDriftRepo(pool *pgxpool.Pool) domain.DriftRepository {
+	return &DriftRepo{pool: pool}
+}
+
+const driftColumns = `id, kind, resource, domain_name, app_id, status, alert_id, first_seen_at, last_seen_at, settled_at, settled_by`
+
+func (r *DriftRepo) DesiredState(ctx context.Context) (*domain.DesiredResources, error) {
+	desired := &domain.DesiredResources{
+		Domains:      make(map[string]bool),
+		Certificates: make(map[string]bool),
+	}
+
+	names, err := r.pool.Query(ctx, `SELECT domain_name FROM domains`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load domains: %w", err)
+	}
+	for names.Next() {
+		var name string
+		if err := names.Scan(&name); err != nil {
+			names.Close()
+			return nil, fmt.Errorf("failed to scan domain: %w", err)
+		}
+		desired.Domains[name] = true
+	}
+	names.Close()
+	if err := names.Err(); err != nil {
+		return nil, err
+	}
+
+	apps, err := r.pool.Query(ctx, `
+		SELECT a.id, d.domain_name, a.app_type, a.status
+		FROM applications a
+		JOIN domains d ON a.domain_id = d.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load applications: %w", err)
+	}
+	for apps.Next() {
+		var app domain.DesiredApp
+		if err := apps.Scan(&app.ID, &app.DomainName, &app.AppType, &app.Status); err != nil {
+			apps.Close()
+			return nil, fmt.Errorf("failed to scan application: %w", err)
+		}
+		desired.Apps = append(desired.Apps, app)
+	}
+	apps.Close()
+	if err := apps.Err(); err != nil {
+		return nil, err
+	}
+
+	certs, err := r.pool.Query(ctx, `
+		SELECT d.domain_name
+		FROM ssl_certificates c
+		JOIN domains d ON c.domain_id = d.id
+		WHERE c.status IN ('active', 'expiring')
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificates: %w", err)
+	}
+	defer certs.Close()
+	for certs.Next() {
+		var name string
+		if err := certs.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan certificate: %w", err)
+		}
+		desired.Certificates[name] = true
+	}
+	return desired, certs.Err()
+}
+
+func (r *DriftRepo) Record(ctx context.Context, observed []domain.DriftFinding) (opened, resolved []domain.DriftFinding, err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin drift tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now().UTC()
+	for _, f := range observed {
+		var inserted bool
+		var domainName *string
+		if f.Domain != "" {
+			domainName = &f.Domain
+		}
+		// xmax = 0 only on a freshly inserted row
+		err := tx.QueryRow(ctx, `
+			INSERT INTO drift_findings (kind, resource, domain_name, app_id, first_seen_at, last_seen_at)
+			VALUES ($1, $2, $3, $4, $5, $5)
+			ON CONFLICT (kind, resource) WHERE status = 'open'
+			DO UPDATE SET last_seen_at = EXCLUDED.last_seen_at
+			RETURNING id, first_seen_at, (xmax = 0)
+		`, f.Kind, f.Resource, domainName, f.AppID, now).Scan(&f.ID, &f.FirstSeenAt, &inserted)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to record drift finding: %w", err)
+		}
+		if inserted {
+			f.Status, f.LastSeenAt = domain.DriftOpen, now
+			f.Actions = f.AvailableActions()
+			opened = append(opened, f)
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+		UPDATE drift_findings SET status = 'resolved', settled_at = $1
+		WHERE status = 'open' AND last_seen_at < $1
+		RETURNING `+driftColumns, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve drift findings: %w", err)
+	}
+	resolved, err = scanDriftRows(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit drift sweep: %w", err)
+	}
+	return opened, resolved, nil
+}
+
+func (r *DriftRepo) AttachAlert(ctx context.Context, id uuid.UUID, alertID uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `UPDATE drift_findings SET alert_id = $2 WHERE id = $1`, id, alertID)
+	if err != nil {
+		return fmt.Errorf("failed to attach drift alert: %w", err)
+	}
+	return nil
+}
+
+func (r *DriftRepo) List(ctx context.Context, status domain.DriftStatus) ([]domain.DriftFinding, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+driftColumns+` FROM drift_findings
+		WHERE $1 = '' OR status = $1
+		ORDER BY last_seen_at DESC
+		LIMIT 500
+	`, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list drift findings: %w", err)
+	}
+	return scanDriftRows(rows)
+}
+
+func (r *DriftRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.DriftFinding, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+driftColumns+` FROM drift_findings WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load drift finding: %w", err)
+	}
+	findings, err := scanDriftRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(findings) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return &findings[0], nil
+}
+
+func (r *DriftRepo) Settle(ctx context.Context, id uuid.UUID, status domain.DriftStatus, actorID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE drift_findings SET status = $2, settled_at = NOW(), settled_by = $3
+		WHERE id = $1 AND status = 'open'
+	`, id, status, actorID)
+	if err != nil {
+		return fmt.Errorf("failed to settle drift finding: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: drift finding is no longer open", domain.ErrConflict)
+	}
+	return nil
+}
+
+func scanDriftRows(rows pgx.Rows) ([]domain.DriftFinding, error) {
+	defer rows.Close()
+
+	var findings []domain.DriftFinding
+	for rows.Next() {
+		var f domain.DriftFinding
+		var domainName *string
+		if err := rows.Scan(&f.ID, &f.Kind, &f.Resource, &domainName, &f.AppID, &f.Status, &f.AlertID,
+			&f.FirstSeenAt, &f.LastSeenAt, &f.SettledAt, &f.SettledBy); err != nil {
+			return nil, fmt.Errorf("failed to scan drift finding: %w", err)
+		}
+		if domainName != nil {
+			f.Domain = *domainName
+		}
+		f.Actions = f.AvailableActions()
+		findings = append(findings, f)
+	}
+	return findings, rows.Err()
+}
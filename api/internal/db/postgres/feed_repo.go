@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type FeedRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewFeedRepo(pool *pgxpool.Pool) domain.FeedRepository {
+	return &FeedRepo{pool: pool}
+}
+
+// ==============================================================================
+// 1. Tokens
+// ==============================================================================
+
+func (r *FeedRepo) SetToken(ctx context.Context, userID uuid.UUID, tokenHash string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO feed_tokens (user_id, token_hash) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = NOW()
+	`, userID, tokenHash)
+	if err != nil {
+		return fmt.Errorf("failed to store feed token: %w", err)
+	}
+	return nil
+}
+
+func (r *FeedRepo) DeleteToken(ctx context.Context, userID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM feed_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke feed token: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *FeedRepo) ResolveToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.pool.QueryRow(ctx, `SELECT user_id FROM feed_tokens WHERE token_hash = $1`, tokenHash).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to resolve feed token: %w", err)
+	}
+	return userID, nil
+}
+
+// ==============================================================================
+// 2. Feed Content
+// ==============================================================================
+
+func (r *FeedRepo) CertificateExpiries(ctx context.Context, scope domain.FeedScope, from, until time.Time, limit int) ([]domain.CertificateRenewal, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT c.common_name, c.expires_at, c.status
+		FROM ssl_certificates c
+		JOIN domains d ON d.id = c.domain_id
+		WHERE c.status <> 'revoked'
+		  AND c.expires_at BETWEEN $1 AND $2
+		  AND ($3::uuid IS NULL OR d.user_id = $3)
+		ORDER BY c.expires_at
+		LIMIT $4
+	`, from, until, scope.OwnerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate expiries: %w", err)
+	}
+	defer rows.Close()
+
+	renewals := []domain.CertificateRenewal{}
+	for rows.Next() {
+		var c domain.CertificateRenewal
+		if err := rows.Scan(&c.DomainName, &c.ExpiresAt, &c.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan certificate expiry: %w", err)
+		}
+		renewals = append(renewals, c)
+	}
+	return renewals, rows.Err()
+}
+
+// Incidents matches alerts to tenants through resource_id, which holds
+// either an app ID or a domain name.
+func (r *FeedRepo) Incidents(ctx context.Context, scope domain.FeedScope, since time.Time, limit int) ([]domain.SystemAlert, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, severity, category, resource_id, message, is_resolved, metadata, created_at
+		FROM system_alerts
+		WHERE severity IN ('critical', 'fatal')
+		  AND created_at >= $1
+		  AND ($2::uuid IS NULL OR resource_id IN (
+		      SELECT a.id::text FROM applications a JOIN domains d ON d.id = a.domain_id WHERE d.user_id = $2
+		      UNION ALL
+		      SELECT d.domain_name FROM domains d WHERE d.user_id = $2
+		  ))
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, since, scope.OwnerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load incidents: %w", err)
+	}
+	alerts, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.SystemAlert])
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan incidents: %w", err)
+	}
+	return alerts, nil
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type FileScanRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewFileScanRepo(pool *pgxpool.Pool) domain.FileScanRepository {
+	return &FileScanRepo{pool: pool}
+}
+
+func (r *FileScanRepo) Create(ctx context.Context, s *domain.FileScan) error {
+	findings := s.Findings
+	if findings == nil {
+		findings = []domain.FileFinding{}
+	}
+	query := `
+		INSERT INTO file_scans (id, app_id, status, files_scanned, findings, error, started_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING finished_at
+	`
+	err := r.pool.QueryRow(ctx, query, s.ID, s.AppID, s.Status, s.FilesScanned, findings, s.Error, s.StartedAt).
+		Scan(&s.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record file scan: %w", err)
+	}
+	return nil
+}
+
+func (r *FileScanRepo) ListByApp(ctx context.Context, appID uuid.UUID, limit int) ([]domain.FileScan, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, app_id, status, files_scanned, findings, error, started_at, finished_at
+		FROM file_scans WHERE app_id = $1
+		ORDER BY started_at DESC LIMIT $2
+	`, appID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list file scans: %w", err)
+	}
+	defer rows.Close()
+
+	scans := []domain.FileScan{}
+	for rows.Next() {
+		var s domain.FileScan
+		if err := rows.Scan(&s.ID, &s.AppID, &s.Status, &s.FilesScanned, &s.Findings, &s.Error, &s.StartedAt, &s.FinishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan file scan row: %w", err)
+		}
+		scans = append(scans, s)
+	}
+	return scans, rows.Err()
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type GitConnectionRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewGitConnectionRepo(pool *pgxpool.Pool) domain.GitConnectionRepository {
+	return &GitConnectionRepo{pool: pool}
+}
+
+const gitConnectionColumns = `user_id, provider, account_login, access_token_ciphertext,
+	COALESCE(refresh_token_ciphertext, ''), expires_at, created_at, updated_at`
+
+func scanGitConnection(row pgx.Row) (*domain.GitConnection, error) {
+	var c domain.GitConnection
+	err := row.Scan(&c.UserID, &c.Provider, &c.AccountLogin, &c.AccessTokenCiphertext,
+		&c.RefreshTokenCiphertext, &c.ExpiresAt, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Upsert stores a connection; reconnecting replaces the previous tokens.
+func (r *GitConnectionRepo) Upsert(ctx context.Context, conn *domain.GitConnection) error {
+	query := `
+		INSERT INTO git_connections (user_id, provider, account_login, access_token_ciphertext, refresh_token_ciphertext, expires_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+		ON CONFLICT (user_id, provider) DO UPDATE SET
+			account_login = EXCLUDED.account_login,
+			access_token_ciphertext = EXCLUDED.access_token_ciphertext,
+			refresh_token_ciphertext = EXCLUDED.refresh_token_ciphertext,
+			expires_at = EXCLUDED.expires_at,
+			updated_at = NOW()
+		RETURNING created_at, updated_at
+	`
+	err := r.pool.QueryRow(ctx, query, conn.UserID, conn.Provider, conn.AccountLogin,
+		conn.AccessTokenCiphertext, conn.RefreshTokenCiphertext, conn.ExpiresAt,
+	).Scan(&conn.CreatedAt, &conn.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store git connection: %w", err)
+	}
+	return nil
+}
+
+func (r *GitConnectionRepo) Get(ctx context.Context, userID uuid.UUID, provider domain.GitProvider) (*domain.GitConnection, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+gitConnectionColumns+` FROM git_connections WHERE user_id = $1 AND provider = $2`, userID, provider)
+	conn, err := scanGitConnection(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load git connection: %w", err)
+	}
+	return conn, nil
+}
+
+func (r *GitConnectionRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.GitConnection, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+gitConnectionColumns+` FROM git_connections WHERE user_id = $1 ORDER BY provider`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list git connections: %w", err)
+	}
+	defer rows.Close()
+
+	conns := []domain.GitConnection{}
+	for rows.Next() {
+		conn, err := scanGitConnection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan git connection: %w", err)
+		}
+		conns = append(conns, *conn)
+	}
+	return conns, rows.Err()
+}
+
+func (r *GitConnectionRepo) Delete(ctx context.Context, userID uuid.UUID, provider domain.GitProvider) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM git_connections WHERE user_id = $1 AND provider = $2`, userID, provider)
+	if err != nil {
+		return fmt.Errorf("failed to delete git connection: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+const hubRelayChannel = "kari_hub"
+
+// notifyLimit keeps payloads under Postgres' 8000-byte NOTIFY cap.
+const notifyLimit = 7900
+
+// HubRelay fans Hub broadcasts out over LISTEN/NOTIFY. It needs nothing
+// beyond the database every replica already shares; delivery is best effort,
+// like the Hub itself (deployment_logs stays the complete record).
+type HubRelay struct {
+	pool *pgxpool.Pool
+}
+
+func NewHubRelay(pool *pgxpool.Pool) domain.LogRelay {
+	return &HubRelay{pool: pool}
+}
+
+// Publish splits oversized messages into several NOTIFYs, in order.
+func (r *HubRelay) Publish(ctx context.Context, env domain.LogRelayEnvelope) error {
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("failed to encode relay message: %w", err)
+	}
+	if len(payload) > notifyLimit {
+		head, tail := splitContent(env.Message.Content)
+		if head == "" || tail == "" {
+			return errors.New("relay message too large to split")
+		}
+		first, rest := env, env
+		first.Message.Content, rest.Message.Content = head, tail
+		if err := r.Publish(ctx, first); err != nil {
+			return err
+		}
+		return r.Publish(ctx, rest)
+	}
+
+	if _, err := r.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, hubRelayChannel, string(payload)); err != nil {
+		return fmt.Errorf("failed to publish relay message: %w", err)
+	}
+	return nil
+}
+
+func (r *HubRelay) Listen(ctx context.Context, deliver func(domain.LogRelayEnvelope)) error {
+	return listen(ctx, r.pool, hubRelayChannel, func(payload string) {
+		var env domain.LogRelayEnvelope
+		if err := json.Unmarshal([]byte(payload), &env); err != nil {
+			return // Not ours to understand; never worth dropping the session
+		}
+		deliver(env)
+	})
+}
+
+// splitContent halves s on a rune boundary.
+func splitContent(s string) (string, string) {
+	mid := len(s) / 2
+	for mid > 0 && !utf8.RuneStart(s[mid]) {
+		mid--
+	}
+	return s[:mid], s[mid:]
+}
@@ -0,0 +1,101 @@
+// api/internal/db/postgres/idempotency_repo.go
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type IdempotencyRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewIdempotencyRepo(pool *pgxpool.Pool) domain.IdempotencyRepository {
+	return &IdempotencyRepo{pool: pool}
+}
+
+// Reserve claims the key with INSERT ... ON CONFLICT DO NOTHING.
+// 🛡️ Stability: The primary key makes the claim atomic across Brain replicas —
+// two concurrent retries can never both execute the handler.
+func (r *IdempotencyRepo) Reserve(ctx context.Context, userID uuid.UUID, key, requestHash string, ttl time.Duration) (*domain.IdempotencyRecord, error) {
+	// Expired keys are treated as free: clear them before claiming.
+	_, err := r.pool.Exec(ctx,
+		`DELETE FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2 AND expires_at < NOW()`,
+		userID, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear expired idempotency key: %w", err)
+	}
+
+	tag, err := r.pool.Exec(ctx, `
+		INSERT INTO idempotency_keys (user_id, idempotency_key, request_hash, expires_at)
+		VALUES ($1, $2, $3, NOW() + $4::interval)
+		ON CONFLICT (user_id, idempotency_key) DO NOTHING
+	`, userID, key, requestHash, fmt.Sprintf("%d seconds", int(ttl.Seconds())))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if tag.RowsAffected() == 1 {
+		return nil, nil // We own the key
+	}
+
+	// Someone already used this key — return what they stored.
+	var rec domain.IdempotencyRecord
+	var status *int
+	var contentType *string
+	err = r.pool.QueryRow(ctx, `
+		SELECT user_id, idempotency_key, request_hash, status_code, response_body, content_type, created_at, expires_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND idempotency_key = $2
+	`, userID, key).Scan(
+		&rec.UserID, &rec.Key, &rec.RequestHash, &status, &rec.ResponseBody, &contentType, &rec.CreatedAt, &rec.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Released between our INSERT and SELECT; let the client retry.
+			return nil, domain.ErrConflict
+		}
+		return nil, fmt.Errorf("failed to load idempotency key: %w", err)
+	}
+	if status != nil {
+		rec.StatusCode = *status
+	}
+	if contentType != nil {
+		rec.ContentType = *contentType
+	}
+	return &rec, nil
+}
+
+// Complete persists the final response so retries are replayed verbatim.
+func (r *IdempotencyRepo) Complete(ctx context.Context, userID uuid.UUID, key string, statusCode int, contentType string, body []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, content_type = $4, response_body = $5
+		WHERE user_id = $1 AND idempotency_key = $2
+	`
+	_, err := r.pool.Exec(ctx, query, userID, key, statusCode, contentType, body)
+	return err
+}
+
+// Release removes an in-flight reservation after a server-side failure.
+func (r *IdempotencyRepo) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	_, err := r.pool.Exec(ctx,
+		`DELETE FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2 AND status_code IS NULL`,
+		userID, key)
+	return err
+}
+
+// DeleteExpired purges keys past their TTL.
+func (r *IdempotencyRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+// leaderLockKey names the session advisory lock every replica campaigns for.
+const leaderLockKey = "kari:brain_leader"
+
+// LeaderElector holds a Postgres session advisory lock for as long as this
+// replica leads. Postgres drops the lock with the session, so a crashed or
+// partitioned leader is replaced once its connection is gone; the leader
+// itself steps down as soon as a keepalive ping fails.
+type LeaderElector struct {
+	pool     *pgxpool.Pool
+	logger   *slog.Logger
+	interval time.Duration // Campaign retry and keepalive cadence
+
+	mu      sync.Mutex
+	term    context.Context // Non-nil while leading
+	elected chan struct{}   // Closed when a term starts
+}
+
+func NewLeaderElector(pool *pgxpool.Pool, logger *slog.Logger, interval time.Duration) *LeaderElector {
+	return &LeaderElector{
+		pool:     pool,
+		logger:   logger,
+		interval: interval,
+		elected:  make(chan struct{}),
+	}
+}
+
+var _ domain.LeaderElection = (*LeaderElector)(nil)
+
+// Run campaigns until ctx is done.
+func (e *LeaderElector) Run(ctx context.Context) {
+	for {
+		if conn := e.campaign(ctx); conn != nil {
+			e.lead(ctx, conn)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.interval):
+		}
+	}
+}
+
+func (e *LeaderElector) Lead(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	for {
+		e.mu.Lock()
+		term, elected := e.term, e.elected
+		e.mu.Unlock()
+
+		if term != nil && term.Err() == nil {
+			leading, cancel := context.WithCancel(ctx)
+			stop := context.AfterFunc(term, cancel)
+			return leading, func() { stop(); cancel() }, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-elected:
+		}
+	}
+}
+
+// campaign returns the lock-holding session on a win. The session leaves the
+// pool: the lock must outlive any single query.
+func (e *LeaderElector) campaign(ctx context.Context) *pgx.Conn {
+	pc, err := e.pool.Acquire(ctx)
+	if err != nil {
+		e.logger.Warn("⚠️ Leader election: no database connection", slog.Any("error", err))
+		return nil
+	}
+	var won bool
+	if err := pc.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, leaderLockKey).Scan(&won); err != nil {
+		pc.Release()
+		e.logger.Warn("⚠️ Leader election: lock attempt failed", slog.Any("error", err))
+		return nil
+	}
+	if !won {
+		pc.Release()
+		return nil
+	}
+	return pc.Hijack()
+}
+
+// lead holds the term until a keepalive fails or ctx is done.
+func (e *LeaderElector) lead(ctx context.Context, conn *pgx.Conn) {
+	term, end := context.WithCancel(ctx)
+	e.mu.Lock()
+	e.term = term
+	close(e.elected)
+	e.mu.Unlock()
+	e.logger.Info("👑 Kari Brain: This replica is now the leader")
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, e.interval)
+			err := conn.Ping(pingCtx)
+			cancel()
+			if err != nil && ctx.Err() == nil {
+				e.logger.Warn("⚠️ Kari Brain: Lost the leader lock session; stepping down", slog.Any("error", err))
+				break loop
+			}
+		}
+	}
+
+	// Singleton workers stop before the lock is released to the next replica
+	e.mu.Lock()
+	end()
+	e.term = nil
+	e.elected = make(chan struct{})
+	e.mu.Unlock()
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = conn.Close(closeCtx)
+}
@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// listen runs LISTEN on a session taken out of the pool (notifications are
+// per session) and hands each payload to handle until ctx is done (nil) or
+// the connection fails.
+func listen(ctx context.Context, pool *pgxpool.Pool, channel string, handle func(payload string)) error {
+	pc, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire %s listener connection: %w", channel, err)
+	}
+	conn := pc.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, `LISTEN `+channel); err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("%s listener connection lost: %w", channel, err)
+		}
+		handle(n.Payload)
+	}
+}
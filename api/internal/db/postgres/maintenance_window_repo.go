@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type MaintenanceWindowRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewMaintenanceWindowRepo(pool *pgxpool.Pool) domain.MaintenanceWindowRepository {
+	return &MaintenanceWindowRepo{pool: pool}
+}
+
+func (r *MaintenanceWindowRepo) Create(ctx context.Context, w *domain.MaintenanceWindow) error {
+	return r.pool.QueryRow(ctx, `
+		INSERT INTO maintenance_windows (title, description, starts_at, ends_at, pause_deploys, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, w.Title, w.Description, w.StartsAt, w.EndsAt, w.PauseDeploys, w.CreatedBy).Scan(&w.ID, &w.CreatedAt)
+}
+
+func (r *MaintenanceWindowRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM maintenance_windows WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MaintenanceWindowRepo) ListUnended(ctx context.Context, now time.Time) ([]domain.MaintenanceWindow, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, title, description, starts_at, ends_at, pause_deploys, created_by, created_at
+		FROM maintenance_windows
+		WHERE ends_at > $1
+		ORDER BY starts_at
+	`, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := []domain.MaintenanceWindow{}
+	for rows.Next() {
+		var w domain.MaintenanceWindow
+		if err := rows.Scan(&w.ID, &w.Title, &w.Description, &w.StartsAt, &w.EndsAt, &w.PauseDeploys, &w.CreatedBy, &w.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, w)
+	}
+	return windows, rows.Err()
+}
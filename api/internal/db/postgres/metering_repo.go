@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type MeteringRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewMeteringRepo(pool *pgxpool.Pool) domain.MeteringRepository {
+	return &MeteringRepo{pool: pool}
+}
+
+func (r *MeteringRepo) Cursors(ctx context.Context, appID uuid.UUID) (map[string]domain.MeterCursor, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT source_key, counter, started_at, sampled_at FROM usage_sample_cursors WHERE app_id = $1
+	`, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load meter cursors: %w", err)
+	}
+	defer rows.Close()
+
+	cursors := map[string]domain.MeterCursor{}
+	for rows.Next() {
+		var c domain.MeterCursor
+		if err := rows.Scan(&c.Key, &c.Counter, &c.StartedAt, &c.SampledAt); err != nil {
+			return nil, fmt.Errorf("failed to scan meter cursor: %w", err)
+		}
+		cursors[c.Key] = c
+	}
+	return cursors, rows.Err()
+}
+
+func (r *MeteringRepo) Accrue(ctx context.Context, a domain.UsageAccrual, cursors []domain.MeterCursor) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin usage accrual: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	batch.Queue(`
+		INSERT INTO usage_meter_monthly
+			(app_id, tenant_id, domain_name, month, cpu_seconds, ram_gb_hours, storage_gb_hours, bandwidth_bytes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7,
+			COALESCE((SELECT bytes_in + bytes_out FROM app_bandwidth_monthly WHERE app_id = $1 AND month = $4), 0))
+		ON CONFLICT (app_id, month) DO UPDATE SET
+			domain_name = EXCLUDED.domain_name,
+			cpu_seconds = usage_meter_monthly.cpu_seconds + EXCLUDED.cpu_seconds,
+			ram_gb_hours = usage_meter_monthly.ram_gb_hours + EXCLUDED.ram_gb_hours,
+			storage_gb_hours = usage_meter_monthly.storage_gb_hours + EXCLUDED.storage_gb_hours,
+			bandwidth_bytes = EXCLUDED.bandwidth_bytes,
+			updated_at = NOW()
+	`, a.AppID, a.TenantID, a.DomainName, a.Month, a.CPUSeconds, a.RAMGBHours, a.StorageGBHours)
+	for _, c := range cursors {
+		batch.Queue(`
+			INSERT INTO usage_sample_cursors (source_key, app_id, counter, started_at, sampled_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (source_key) DO UPDATE SET
+				counter = EXCLUDED.counter, started_at = EXCLUDED.started_at, sampled_at = EXCLUDED.sampled_at
+		`, c.Key, a.AppID, c.Counter, c.StartedAt, c.SampledAt)
+	}
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to accrue usage: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *MeteringRepo) Report(ctx context.Context, month time.Time) ([]domain.UsageRecord, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT tenant_id, app_id, domain_name, cpu_seconds, ram_gb_hours, storage_gb_hours, bandwidth_bytes
+		FROM usage_meter_monthly WHERE month = $1
+		ORDER BY tenant_id, domain_name
+	`, month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load usage report: %w", err)
+	}
+	defer rows.Close()
+
+	records := []domain.UsageRecord{}
+	for rows.Next() {
+		rec := domain.UsageRecord{Month: month.Format("2006-01")}
+		var cpuSeconds float64
+		if err := rows.Scan(&rec.TenantID, &rec.AppID, &rec.DomainName, &cpuSeconds,
+			&rec.RAMGBHours, &rec.StorageGBHours, &rec.BandwidthBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan usage record: %w", err)
+		}
+		rec.CPUHours = cpuSeconds / 3600
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+func (r *MeteringRepo) GetExport(ctx context.Context, month time.Time) (*domain.UsageExport, error) {
+	e := domain.UsageExport{Month: month}
+	err := r.pool.QueryRow(ctx, `
+		SELECT status, attempts, error, updated_at FROM usage_exports WHERE month = $1
+	`, month).Scan(&e.Status, &e.Attempts, &e.Error, &e.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load usage export: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *MeteringRepo) RecordExport(ctx context.Context, month time.Time, exportErr error) error {
+	status, msg := "delivered", ""
+	if exportErr != nil {
+		status, msg = "failed", exportErr.Error()
+	}
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO usage_exports (month, status, error) VALUES ($1, $2, $3)
+		ON CONFLICT (month) DO UPDATE SET
+			status = EXCLUDED.status, error = EXCLUDED.error,
+			attempts = usage_exports.attempts + 1, updated_at = NOW()
+	`, month, status, msg)
+	if err != nil {
+		return fmt.Errorf("failed to record usage export: %w", err)
+	}
+	return nil
+}
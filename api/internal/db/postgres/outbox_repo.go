@@ -0,0 +1,156 @@
+// api/internal/db/postgres/outbox_repo.go
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type OutboxRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewOutboxRepo(pool *pgxpool.Pool) domain.OutboxRepository {
+	return &OutboxRepo{pool: pool}
+}
+
+const stateChangeColumns = `id, seq, event, resource_type, resource_id, actor_id, COALESCE(method, ''), COALESCE(payload_ciphertext, ''),
+	description, status, attempts, last_error, created_at, processed_at`
+
+// rowQuerier is satisfied by both the pool and a transaction, so other repos
+// can append a change in the same transaction as the write it describes.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func (r *OutboxRepo) Append(ctx context.Context, c *domain.StateChange) error {
+	return appendStateChange(ctx, r.pool, c)
+}
+
+func appendStateChange(ctx context.Context, q rowQuerier, c *domain.StateChange) error {
+	status := domain.ChangeRecorded
+	var method, payload *string
+	if c.Method != "" {
+		status, method, payload = domain.ChangePending, &c.Method, &c.PayloadCiphertext
+	}
+
+	err := q.QueryRow(ctx, `
+		INSERT INTO state_outbox (event, resource_type, resource_id, actor_id, method, payload_ciphertext, description, status, processed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CASE WHEN $8 = 'recorded' THEN NOW() END)
+		RETURNING id, seq, status, created_at, processed_at
+	`, c.Event, c.ResourceType, c.ResourceID, c.ActorID, method, payload, c.Description, status).
+		Scan(&c.ID, &c.Seq, &c.Status, &c.CreatedAt, &c.ProcessedAt)
+	if err != nil {
+		return fmt.Errorf("failed to append state change: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepo) NextPending(ctx context.Context, limit int) ([]domain.StateChange, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+stateChangeColumns+`
+		FROM state_outbox
+		WHERE status = 'pending'
+		ORDER BY seq ASC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pending state changes: %w", err)
+	}
+	return scanStateChanges(rows)
+}
+
+func (r *OutboxRepo) CountPending(ctx context.Context) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM state_outbox WHERE status = 'pending'`).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending state changes: %w", err)
+	}
+	return n, nil
+}
+
+func (r *OutboxRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.StateChange, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+stateChangeColumns+` FROM state_outbox WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state change: %w", err)
+	}
+	changes, err := scanStateChanges(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return &changes[0], nil
+}
+
+func (r *OutboxRepo) RecordAttempt(ctx context.Context, id uuid.UUID, reason string) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE state_outbox SET attempts = attempts + 1, last_error = $2
+		WHERE id = $1
+	`, id, reason)
+	if err != nil {
+		return fmt.Errorf("failed to record state change attempt: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepo) MarkApplied(ctx context.Context, id uuid.UUID) error {
+	return r.finish(ctx, id, domain.ChangeApplied, nil)
+}
+
+func (r *OutboxRepo) MarkFailed(ctx context.Context, id uuid.UUID, reason string) error {
+	return r.finish(ctx, id, domain.ChangeFailed, &reason)
+}
+
+func (r *OutboxRepo) finish(ctx context.Context, id uuid.UUID, status domain.StateChangeStatus, reason *string) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE state_outbox
+		SET status = $2, attempts = attempts + 1, last_error = COALESCE($3, last_error), processed_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`, id, status, reason)
+	if err != nil {
+		return fmt.Errorf("failed to update state change: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepo) List(ctx context.Context, filter domain.StateChangeFilter) ([]domain.StateChange, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = domain.DefaultPageLimit
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+stateChangeColumns+`
+		FROM state_outbox
+		WHERE ($1 = '' OR resource_type = $1)
+		  AND ($2 = '' OR resource_id = $2)
+		  AND ($3 = '' OR status = $3)
+		ORDER BY seq DESC
+		LIMIT $4
+	`, filter.ResourceType, filter.ResourceID, filter.Status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list state changes: %w", err)
+	}
+	return scanStateChanges(rows)
+}
+
+func scanStateChanges(rows pgx.Rows) ([]domain.StateChange, error) {
+	defer rows.Close()
+
+	changes := []domain.StateChange{}
+	for rows.Next() {
+		var c domain.StateChange
+		if err := rows.Scan(&c.ID, &c.Seq, &c.Event, &c.ResourceType, &c.ResourceID, &c.ActorID, &c.Method,
+			&c.PayloadCiphertext, &c.Description, &c.Status, &c.Attempts, &c.LastError, &c.CreatedAt, &c.ProcessedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan state change: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
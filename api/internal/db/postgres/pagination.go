@@ -0,0 +1,81 @@
+// api/internal/db/postgres/pagination.go
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"kari/api/internal/core/domain"
+)
+
+// sortColumn maps a public sort key (?sort=created_at) to its SQL expression and
+// the cast applied to the cursor value when seeking past the previous page.
+type sortColumn struct {
+	expr string
+	cast string
+}
+
+// listQuery accumulates WHERE clauses and positional args for dynamic list queries.
+// 🛡️ Zero-Trust: Values only ever travel as $n parameters; identifiers only ever
+// come from the per-repository sortColumn whitelists.
+type listQuery struct {
+	conds []string
+	args  []any
+}
+
+// arg registers a parameter and returns its placeholder.
+func (q *listQuery) arg(v any) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+func (q *listQuery) where(cond string) {
+	q.conds = append(q.conds, cond)
+}
+
+func (q *listQuery) whereSQL() string {
+	if len(q.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conds, " AND ")
+}
+
+// paginate adds the keyset seek condition for page.Cursor and returns the
+// ORDER BY / LIMIT tail. Call it AFTER running the COUNT query so the total
+// reflects the filter, not the cursor position.
+func (q *listQuery) paginate(page domain.PageRequest, sorts map[string]sortColumn, defaultSort, idExpr string) (string, error) {
+	key := page.Sort
+	if key == "" {
+		key = defaultSort
+	}
+	col, ok := sorts[key]
+	if !ok {
+		return "", fmt.Errorf("%w: unsupported sort field %q", domain.ErrValidation, key)
+	}
+
+	cmp, dir := ">", "ASC"
+	if page.Desc {
+		cmp, dir = "<", "DESC"
+	}
+
+	if page.Cursor != nil {
+		q.where(fmt.Sprintf("(%s, %s) %s (%s::%s, %s)",
+			col.expr, idExpr, cmp, q.arg(page.Cursor.Value), col.cast, q.arg(page.Cursor.ID)))
+	}
+
+	// Fetch one extra row to learn whether a next page exists without a second COUNT.
+	return fmt.Sprintf(" ORDER BY %s %s, %s %s LIMIT %s", col.expr, dir, idExpr, dir, q.arg(page.Limit+1)), nil
+}
+
+// buildPage trims the look-ahead row and derives the next cursor from the last item.
+func buildPage[T any](items []T, page domain.PageRequest, total int, cursorOf func(T) domain.Cursor) domain.Page[T] {
+	result := domain.Page[T]{Items: items, Total: total}
+	if len(items) > page.Limit {
+		result.Items = items[:page.Limit]
+		result.NextCursor = cursorOf(result.Items[page.Limit-1]).Encode()
+	}
+	if result.Items == nil {
+		result.Items = []T{} // 🛡️ SLA: Always encode [] instead of null for the UI
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type PasskeyRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPasskeyRepo(pool *pgxpool.Pool) domain.PasskeyRepository {
+	return &PasskeyRepo{pool: pool}
+}
+
+func (r *PasskeyRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]domain.Passkey, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, user_id, name, credential_id, credential, created_at, last_used_at
+		FROM user_passkeys WHERE user_id = $1
+		ORDER BY created_at
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []domain.Passkey{}
+	for rows.Next() {
+		var p domain.Passkey
+		if err := rows.Scan(&p.ID, &p.UserID, &p.Name, &p.CredentialID, &p.Credential, &p.CreatedAt, &p.LastUsedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan passkey: %w", err)
+		}
+		keys = append(keys, p)
+	}
+	return keys, rows.Err()
+}
+
+func (r *PasskeyRepo) GetByCredentialID(ctx context.Context, credentialID []byte) (*domain.Passkey, error) {
+	var p domain.Passkey
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, user_id, name, credential_id, credential, created_at, last_used_at
+		FROM user_passkeys WHERE credential_id = $1
+	`, credentialID).Scan(&p.ID, &p.UserID, &p.Name, &p.CredentialID, &p.Credential, &p.CreatedAt, &p.LastUsedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load passkey: %w", err)
+	}
+	return &p, nil
+}
+
+func (r *PasskeyRepo) Create(ctx context.Context, p *domain.Passkey) error {
+	// A new passkey ends any re-enrolment a break-glass recovery opened
+	err := r.pool.QueryRow(ctx, `
+		WITH inserted AS (
+			INSERT INTO user_passkeys (user_id, name, credential_id, credential)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at
+		), enrolled AS (
+			UPDATE users SET passkey_reenroll = false WHERE id = $1 AND passkey_reenroll
+		)
+		SELECT id, created_at FROM inserted
+	`, p.UserID, p.Name, p.CredentialID, p.Credential).Scan(&p.ID, &p.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrConflict // Authenticator already registered
+		}
+		return fmt.Errorf("failed to register passkey: %w", err)
+	}
+	return nil
+}
+
+func (r *PasskeyRepo) ReenrollPending(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var pending bool
+	err := r.pool.QueryRow(ctx, `SELECT passkey_reenroll FROM users WHERE id = $1`, userID).Scan(&pending)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, domain.ErrNotFound
+		}
+		return false, fmt.Errorf("failed to read passkey re-enrolment: %w", err)
+	}
+	return pending, nil
+}
+
+func (r *PasskeyRepo) UpdateCredential(ctx context.Context, id uuid.UUID, credential json.RawMessage) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE user_passkeys SET credential = $2, last_used_at = NOW() WHERE id = $1
+	`, id, credential)
+	if err != nil {
+		return fmt.Errorf("failed to update passkey: %w", err)
+	}
+	return nil
+}
+
+func (r *PasskeyRepo) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM user_passkeys WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete passkey: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PasskeyRepo) SaveSession(ctx context.Context, id uuid.UUID, userID *uuid.UUID, ceremony string, data json.RawMessage, expiresAt time.Time) error {
+	// Opportunistic cleanup keeps the table tiny without a dedicated worker
+	if _, err := r.pool.Exec(ctx, `DELETE FROM webauthn_sessions WHERE expires_at < NOW()`); err != nil {
+		return fmt.Errorf("failed to prune webauthn sessions: %w", err)
+	}
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO webauthn_sessions (id, user_id, ceremony, data, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, id, userID, ceremony, data, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to save webauthn session: %w", err)
+	}
+	return nil
+}
+
+func (r *PasskeyRepo) TakeSession(ctx context.Context, id uuid.UUID, ceremony string) (*uuid.UUID, json.RawMessage, error) {
+	var userID *uuid.UUID
+	var data json.RawMessage
+	err := r.pool.QueryRow(ctx, `
+		DELETE FROM webauthn_sessions
+		WHERE id = $1 AND ceremony = $2 AND expires_at > NOW()
+		RETURNING user_id, data
+	`, id, ceremony).Scan(&userID, &data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, domain.ErrNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to load webauthn session: %w", err)
+	}
+	return userID, data, nil
+}
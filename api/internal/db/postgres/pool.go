@@ -3,34 +3,149 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// PoolOptions tunes the pool; zero values keep pgx defaults.
+type PoolOptions struct {
+	MaxConns          int32
+	MinConns          int32
+	HealthCheckPeriod time.Duration
+	// StatementCacheMode is one of cache_statement (default), cache_describe,
+	// describe_exec, exec or simple_protocol. Behind PgBouncer in transaction
+	// mode use cache_describe or simple_protocol.
+	StatementCacheMode string
+	// QueryTimeout bounds every query and pool acquire whose context has no
+	// deadline of its own; 0 disables the default.
+	QueryTimeout time.Duration
+	// Configure, if set, adjusts the final config (fault injection hooks in here).
+	Configure func(*pgxpool.Config)
+}
+
+var statementCacheModes = map[string]pgx.QueryExecMode{
+	"":                pgx.QueryExecModeCacheStatement,
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
+
 // NewPool initializes a new PostgreSQL connection pool using pgxpool.
 // 🛡️ SLA: Configures explicit pooling limits to prevent socket exhaustion during load spikes.
-func NewPool(ctx context.Context, databaseURL string) (*pgxpool.Pool, error) {
+// Connections are opened lazily; use WaitForDatabase to verify connectivity.
+func NewPool(ctx context.Context, databaseURL string, opts PoolOptions) (*pgxpool.Pool, error) {
 	config, err := pgxpool.ParseConfig(databaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse database url: %w", err)
 	}
 
 	// 🛡️ SLA Performance: Pooling thresholds
-	config.MaxConns = 50                 // Maximum open connections
-	config.MinConns = 5                  // Minimum idle connections kept alive
-	config.MaxConnLifetime = time.Hour   // Recycle connections every hour
-	config.MaxConnIdleTime = time.Minute * 30 // Close idle connections after 30 mins
+	config.MaxConns = 50                      // Maximum open connections
+	config.MinConns = 5                       // Minimum idle connections kept alive
+	config.MaxConnLifetime = time.Hour        // Recycle connections every hour
+	config.MaxConnIdleTime = 30 * time.Minute // Close idle connections after 30 mins
+	if opts.MaxConns > 0 {
+		config.MaxConns = opts.MaxConns
+	}
+	if opts.MinConns > 0 {
+		config.MinConns = min(opts.MinConns, config.MaxConns)
+	}
+	if opts.HealthCheckPeriod > 0 {
+		config.HealthCheckPeriod = opts.HealthCheckPeriod
+	}
+
+	mode, ok := statementCacheModes[opts.StatementCacheMode]
+	if !ok {
+		return nil, fmt.Errorf("unknown statement cache mode %q", opts.StatementCacheMode)
+	}
+	config.ConnConfig.DefaultQueryExecMode = mode
+
+	// 🛡️ Stability: No repository call can hang forever on a stuck query or
+	// an exhausted pool, even if its caller forgot a deadline
+	if opts.QueryTimeout > 0 {
+		config.ConnConfig.Tracer = deadlineTracer{timeout: opts.QueryTimeout}
+	}
+
+	if opts.Configure != nil {
+		opts.Configure(config)
+	}
 
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create connection pool: %w", err)
 	}
+	return pool, nil
+}
 
-	// 🛡️ Zero-Trust: Verify connectivity immediately
-	if err := pool.Ping(ctx); err != nil {
-		return nil, fmt.Errorf("database ping failed: %w", err)
+// WaitForDatabase pings until Postgres answers or ctx is done, backing off
+// exponentially between attempts. Under systemd or compose the Brain may
+// start before Postgres accepts connections.
+func WaitForDatabase(ctx context.Context, pool *pgxpool.Pool, logger *slog.Logger) error {
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := pool.Ping(pingCtx)
+		cancel()
+		if err == nil {
+			if attempt > 1 {
+				logger.Info("✅ Database reachable", slog.Int("attempts", attempt))
+			}
+			return nil
+		}
+
+		logger.Warn("⏳ Database not reachable yet",
+			slog.Int("attempt", attempt),
+			slog.Duration("retry_in", backoff),
+			slog.Any("error", err))
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database ping failed after %d attempts: %w", attempt, err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
 	}
+}
 
-	return pool, nil
+// deadlineTracer gives deadline-less contexts a default timeout. pgx runs
+// the query (and pgxpool the acquire) with the context a tracer returns, and
+// calls the matching End hook when the query or rows finish.
+type deadlineTracer struct {
+	timeout time.Duration
+}
+
+type deadlineCancelKey struct{}
+
+func (t deadlineTracer) withDeadline(ctx context.Context) context.Context {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx // The caller's own deadline wins, longer or shorter
+	}
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	return context.WithValue(ctx, deadlineCancelKey{}, cancel)
+}
+
+func (t deadlineTracer) release(ctx context.Context) {
+	if cancel, ok := ctx.Value(deadlineCancelKey{}).(context.CancelFunc); ok {
+		cancel()
+	}
+}
+
+func (t deadlineTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return t.withDeadline(ctx)
+}
+
+func (t deadlineTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryEndData) {
+	t.release(ctx)
+}
+
+func (t deadlineTracer) TraceAcquireStart(ctx context.Context, _ *pgxpool.Pool, _ pgxpool.TraceAcquireStartData) context.Context {
+	return t.withDeadline(ctx)
+}
+
+func (t deadlineTracer) TraceAcquireEnd(ctx context.Context, _ *pgxpool.Pool, _ pgxpool.TraceAcquireEndData) {
+	t.release(ctx)
 }
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector exports pgxpool statistics to Prometheus. Stat() is a cheap
+// snapshot, so every scrape reads it fresh instead of polling in the background.
+type PoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredDesc    *prometheus.Desc
+	idleDesc        *prometheus.Desc
+	totalDesc       *prometheus.Desc
+	maxDesc         *prometheus.Desc
+	acquiresDesc    *prometheus.Desc
+	waitsDesc       *prometheus.Desc
+	waitSecondsDesc *prometheus.Desc
+	canceledDesc    *prometheus.Desc
+}
+
+func NewPoolCollector(pool *pgxpool.Pool) *PoolCollector {
+	return &PoolCollector{
+		pool: pool,
+
+		acquiredDesc:    prometheus.NewDesc("kari_db_pool_acquired_conns", "Connections currently checked out of the pool.", nil, nil),
+		idleDesc:        prometheus.NewDesc("kari_db_pool_idle_conns", "Idle connections in the pool.", nil, nil),
+		totalDesc:       prometheus.NewDesc("kari_db_pool_total_conns", "Open connections, including those being established.", nil, nil),
+		maxDesc:         prometheus.NewDesc("kari_db_pool_max_conns", "Configured pool size limit.", nil, nil),
+		acquiresDesc:    prometheus.NewDesc("kari_db_pool_acquires_total", "Successful connection acquires.", nil, nil),
+		waitsDesc:       prometheus.NewDesc("kari_db_pool_acquire_waits_total", "Acquires that had to wait because no connection was idle.", nil, nil),
+		waitSecondsDesc: prometheus.NewDesc("kari_db_pool_acquire_wait_seconds_total", "Cumulative time spent acquiring connections.", nil, nil),
+		canceledDesc:    prometheus.NewDesc("kari_db_pool_acquire_canceled_total", "Acquires abandoned because the context ended first.", nil, nil),
+	}
+}
+
+// Describe and Collect make the pool a prometheus.Collector.
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredDesc
+	ch <- c.idleDesc
+	ch <- c.totalDesc
+	ch <- c.maxDesc
+	ch <- c.acquiresDesc
+	ch <- c.waitsDesc
+	ch <- c.waitSecondsDesc
+	ch <- c.canceledDesc
+}
+
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(c.acquiredDesc, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleDesc, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalDesc, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxDesc, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquiresDesc, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.waitsDesc, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.waitSecondsDesc, prometheus.CounterValue, s.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.canceledDesc, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+}
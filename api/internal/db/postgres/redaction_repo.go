@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type RedactionRuleRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewRedactionRuleRepo(pool *pgxpool.Pool) domain.RedactionRuleRepository {
+	return &RedactionRuleRepo{pool: pool}
+}
+
+func (r *RedactionRuleRepo) List(ctx context.Context, tenantID *uuid.UUID) ([]domain.RedactionRule, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, tenant_id, name, pattern, replacement, created_by, created_at
+		FROM redaction_rules
+		WHERE tenant_id IS NOT DISTINCT FROM $1
+		ORDER BY created_at
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list redaction rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []domain.RedactionRule{}
+	for rows.Next() {
+		var rule domain.RedactionRule
+		if err := rows.Scan(&rule.ID, &rule.TenantID, &rule.Name, &rule.Pattern, &rule.Replacement, &rule.CreatedBy, &rule.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan redaction rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *RedactionRuleRepo) Create(ctx context.Context, rule *domain.RedactionRule) error {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO redaction_rules (tenant_id, name, pattern, replacement, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, rule.TenantID, rule.Name, rule.Pattern, rule.Replacement, rule.CreatedBy).Scan(&rule.ID, &rule.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create redaction rule: %w", err)
+	}
+	return nil
+}
+
+func (r *RedactionRuleRepo) Delete(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM redaction_rules WHERE id = $1 AND tenant_id IS NOT DISTINCT FROM $2
+	`, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete redaction rule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type RegistryCredentialRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewRegistryCredentialRepo(pool *pgxpool.Pool) domain.RegistryCredentialRepository {
+	return &RegistryCredentialRepo{pool: pool}
+}
+
+const registryCredentialColumns = `id, owner_id, name, registry, username, password_ciphertext, last_tested_at, last_test_ok, created_at`
+
+func scanRegistryCredential(row pgx.Row, c *domain.RegistryCredential) error {
+	return row.Scan(&c.ID, &c.OwnerID, &c.Name, &c.Registry, &c.Username, &c.PasswordCiphertext,
+		&c.LastTestedAt, &c.LastTestOK, &c.CreatedAt)
+}
+
+func (r *RegistryCredentialRepo) Create(ctx context.Context, cred *domain.RegistryCredential) error {
+	query := `
+		INSERT INTO registry_credentials (id, owner_id, name, registry, username, password_ciphertext)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`
+	err := r.pool.QueryRow(ctx, query, cred.ID, cred.OwnerID, cred.Name, cred.Registry, cred.Username, cred.PasswordCiphertext).
+		Scan(&cred.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("%w: a registry credential named %q already exists", domain.ErrConflict, cred.Name)
+		}
+		return fmt.Errorf("failed to store registry credential: %w", err)
+	}
+	return nil
+}
+
+func (r *RegistryCredentialRepo) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]domain.RegistryCredential, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+registryCredentialColumns+` FROM registry_credentials WHERE owner_id = $1 ORDER BY name`, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registry credentials: %w", err)
+	}
+	defer rows.Close()
+
+	creds := []domain.RegistryCredential{}
+	for rows.Next() {
+		var c domain.RegistryCredential
+		if err := scanRegistryCredential(rows, &c); err != nil {
+			return nil, fmt.Errorf("failed to scan registry credential: %w", err)
+		}
+		creds = append(creds, c)
+	}
+	return creds, rows.Err()
+}
+
+func (r *RegistryCredentialRepo) GetByID(ctx context.Context, id, ownerID uuid.UUID) (*domain.RegistryCredential, error) {
+	var c domain.RegistryCredential
+	row := r.pool.QueryRow(ctx,
+		`SELECT `+registryCredentialColumns+` FROM registry_credentials WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if err := scanRegistryCredential(row, &c); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load registry credential: %w", err)
+	}
+	return &c, nil
+}
+
+func (r *RegistryCredentialRepo) RecordTest(ctx context.Context, id uuid.UUID, ok bool) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE registry_credentials SET last_tested_at = NOW(), last_test_ok = $2 WHERE id = $1`, id, ok)
+	if err != nil {
+		return fmt.Errorf("failed to record registry credential test: %w", err)
+	}
+	return nil
+}
+
+// Delete detaches the credential from any apps (FK ON DELETE SET NULL).
+func (r *RegistryCredentialRepo) Delete(ctx context.Context, id, ownerID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM registry_credentials WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	if err != nil {
+		return fmt.Errorf("failed to delete registry credential: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+// sqlStateQuotaExceeded is raised by quota triggers (056) when an INSERT
+// would pass the owner's effective limit.
+const sqlStateQuotaExceeded = "KQ001"
+
+type ResourceQuotaRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewResourceQuotaRepo(pool *pgxpool.Pool) domain.ResourceQuotaRepository {
+	return &ResourceQuotaRepo{pool: pool}
+}
+
+// ListRoleLimits returns every role, unlimited ones included.
+func (r *ResourceQuotaRepo) ListRoleLimits(ctx context.Context) ([]domain.RoleLimits, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT r.id, r.name, l.max_applications, l.max_domains, l.max_memory_mb
+		FROM roles r
+		LEFT JOIN role_resource_limits l ON l.role_id = r.id
+		ORDER BY r.rank, r.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role limits: %w", err)
+	}
+	defer rows.Close()
+
+	limits := []domain.RoleLimits{}
+	for rows.Next() {
+		var l domain.RoleLimits
+		if err := rows.Scan(&l.RoleID, &l.RoleName, &l.MaxApplications, &l.MaxDomains, &l.MaxMemoryMB); err != nil {
+			return nil, fmt.Errorf("failed to scan role limits: %w", err)
+		}
+		limits = append(limits, l)
+	}
+	return limits, rows.Err()
+}
+
+func (r *ResourceQuotaRepo) UpsertRoleLimits(ctx context.Context, roleID uuid.UUID, l domain.ResourceLimits) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO role_resource_limits (role_id, max_applications, max_domains, max_memory_mb)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (role_id) DO UPDATE SET
+			max_applications = EXCLUDED.max_applications, max_domains = EXCLUDED.max_domains,
+			max_memory_mb = EXCLUDED.max_memory_mb, updated_at = NOW()
+	`, roleID, l.MaxApplications, l.MaxDomains, l.MaxMemoryMB)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return domain.ErrNotFound // Unknown role
+		}
+		return fmt.Errorf("failed to store role limits: %w", err)
+	}
+	return nil
+}
+
+func (r *ResourceQuotaRepo) UserLimits(ctx context.Context, userID uuid.UUID) (string, domain.ResourceLimits, *domain.LimitOverride, error) {
+	var (
+		roleName string
+		limits   domain.ResourceLimits
+		o        domain.LimitOverride
+		hasO     bool
+	)
+	err := r.pool.QueryRow(ctx, `
+		SELECT COALESCE(ro.name, ''), l.max_applications, l.max_domains, l.max_memory_mb,
+		       o.user_id IS NOT NULL, o.max_applications, o.max_domains, o.max_memory_mb,
+		       COALESCE(o.exempt, false), COALESCE(o.reason, ''), o.set_by, COALESCE(o.updated_at, NOW())
+		FROM users u
+		LEFT JOIN roles ro ON ro.id = u.role_id
+		LEFT JOIN role_resource_limits l ON l.role_id = u.role_id
+		LEFT JOIN user_resource_overrides o ON o.user_id = u.id
+		WHERE u.id = $1
+	`, userID).Scan(&roleName, &limits.MaxApplications, &limits.MaxDomains, &limits.MaxMemoryMB,
+		&hasO, &o.MaxApplications, &o.MaxDomains, &o.MaxMemoryMB,
+		&o.Exempt, &o.Reason, &o.SetBy, &o.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", limits, nil, domain.ErrNotFound
+		}
+		return "", limits, nil, fmt.Errorf("failed to load user limits: %w", err)
+	}
+	if !hasO {
+		return roleName, limits, nil, nil
+	}
+	return roleName, limits, &o, nil
+}
+
+func (r *ResourceQuotaRepo) UpsertOverride(ctx context.Context, userID uuid.UUID, o domain.LimitOverride) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO user_resource_overrides (user_id, max_applications, max_domains, max_memory_mb, exempt, reason, set_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE SET
+			max_applications = EXCLUDED.max_applications, max_domains = EXCLUDED.max_domains,
+			max_memory_mb = EXCLUDED.max_memory_mb, exempt = EXCLUDED.exempt,
+			reason = EXCLUDED.reason, set_by = EXCLUDED.set_by, updated_at = NOW()
+	`, userID, o.MaxApplications, o.MaxDomains, o.MaxMemoryMB, o.Exempt, o.Reason, o.SetBy)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return domain.ErrNotFound // Unknown user
+		}
+		return fmt.Errorf("failed to store quota override: %w", err)
+	}
+	return nil
+}
+
+func (r *ResourceQuotaRepo) DeleteOverride(ctx context.Context, userID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM user_resource_overrides WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete quota override: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Usage counts the user's domains and apps. Memory is summed in Go so the
+// per-process defaults live in one place (Application.ReservedMemoryMB).
+func (r *ResourceQuotaRepo) Usage(ctx context.Context, userID uuid.UUID) (domain.ResourceUsage, error) {
+	var usage domain.ResourceUsage
+	if err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM domains WHERE user_id = $1`, userID).Scan(&usage.Domains); err != nil {
+		return usage, fmt.Errorf("failed to count domains: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT a.app_type, a.processes, a.instances
+		FROM applications a
+		INNER JOIN domains d ON a.domain_id = d.id
+		WHERE d.user_id = $1
+	`, userID)
+	if err != nil {
+		return usage, fmt.Errorf("failed to load application usage: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var app domain.Application
+		if err := rows.Scan(&app.AppType, &app.Processes, &app.Instances); err != nil {
+			return usage, fmt.Errorf("failed to scan application usage: %w", err)
+		}
+		usage.Applications++
+		usage.MemoryMB += app.ReservedMemoryMB()
+	}
+	return usage, rows.Err()
+}
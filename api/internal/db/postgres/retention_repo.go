@@ -0,0 +1,129 @@
+// api/internal/db/postgres/retention_repo.go
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+// retentionTables whitelists the tables the pruner may touch, with the SQL
+// type of each one's primary key.
+// 🛡️ Zero-Trust: Table names are interpolated into SQL, so they must never
+// come from configuration or user input directly.
+var retentionTables = map[string]string{
+	"tenant_logs":     "uuid",
+	"deployment_logs": "bigint", // SERIAL
+	"system_alerts":   "uuid",
+}
+
+type RetentionRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewRetentionRepo(pool *pgxpool.Pool) domain.RetentionRepository {
+	return &RetentionRepo{pool: pool}
+}
+
+// FetchExpired serializes expired rows with to_jsonb so archives survive schema changes.
+func (r *RetentionRepo) FetchExpired(ctx context.Context, table string, cutoff time.Time, limit int) ([]domain.ArchivedRow, error) {
+	if _, ok := retentionTables[table]; !ok {
+		return nil, fmt.Errorf("%w: table %q is not subject to retention", domain.ErrValidation, table)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT t.id::text, t.created_at, to_jsonb(t)
+		FROM %s t
+		WHERE t.created_at < $1
+		ORDER BY t.created_at ASC, t.id ASC
+		LIMIT $2
+	`, table)
+
+	rows, err := r.pool.Query(ctx, query, cutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch expired %s rows: %w", table, err)
+	}
+	defer rows.Close()
+
+	var out []domain.ArchivedRow
+	for rows.Next() {
+		var row domain.ArchivedRow
+		if err := rows.Scan(&row.ID, &row.CreatedAt, &row.Data); err != nil {
+			return nil, fmt.Errorf("failed to scan expired %s row: %w", table, err)
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
+
+// DeleteRows removes an archived batch in a single statement.
+func (r *RetentionRepo) DeleteRows(ctx context.Context, table string, ids []string) (int64, error) {
+	idType, ok := retentionTables[table]
+	if !ok {
+		return 0, fmt.Errorf("%w: table %q is not subject to retention", domain.ErrValidation, table)
+	}
+
+	// 🛡️ Performance: A typed array keeps the primary key index usable; casting
+	// id to text forced a sequential scan for every batch
+	var arg any
+	switch idType {
+	case "uuid":
+		parsed := make([]uuid.UUID, len(ids))
+		for i, id := range ids {
+			u, err := uuid.Parse(id)
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s id %q: %w", table, id, err)
+			}
+			parsed[i] = u
+		}
+		arg = parsed
+	default:
+		parsed := make([]int64, len(ids))
+		for i, id := range ids {
+			n, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s id %q: %w", table, id, err)
+			}
+			parsed[i] = n
+		}
+		arg = parsed
+	}
+
+	tag, err := r.pool.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE id = ANY($1::%s[])`, table, idType), arg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete archived %s rows: %w", table, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// TableSizes reads the Postgres catalog; row counts are planner estimates so the
+// query stays O(1) even on very large tables.
+func (r *RetentionRepo) TableSizes(ctx context.Context) ([]domain.TableSize, error) {
+	query := `
+		SELECT relname, n_live_tup,
+		       pg_total_relation_size(relid), pg_relation_size(relid), pg_indexes_size(relid)
+		FROM pg_stat_user_tables
+		WHERE schemaname = 'public'
+		ORDER BY pg_total_relation_size(relid) DESC
+	`
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table sizes: %w", err)
+	}
+	defer rows.Close()
+
+	sizes := []domain.TableSize{}
+	for rows.Next() {
+		var s domain.TableSize
+		if err := rows.Scan(&s.Table, &s.RowEstimate, &s.TotalBytes, &s.TableBytes, &s.IndexBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan table size: %w", err)
+		}
+		sizes = append(sizes, s)
+	}
+	return sizes, rows.Err()
+}
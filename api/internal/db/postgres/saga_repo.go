@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type SagaRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSagaRepo(pool *pgxpool.Pool) domain.SagaRepository {
+	return &SagaRepo{pool: pool}
+}
+
+const sagaColumns = `id, kind, owner_id, status, steps, data, created_at, updated_at`
+
+func (r *SagaRepo) Create(ctx context.Context, s *domain.Saga) error {
+	steps, data, err := encodeSaga(s)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO sagas (id, kind, owner_id, status, steps, data, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, s.ID, s.Kind, s.OwnerID, s.Status, steps, data, s.CreatedAt, s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create saga: %w", err)
+	}
+	return nil
+}
+
+func (r *SagaRepo) Update(ctx context.Context, s *domain.Saga) error {
+	steps, data, err := encodeSaga(s)
+	if err != nil {
+		return err
+	}
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE sagas SET status = $2, steps = $3, data = $4, updated_at = $5
+		WHERE id = $1
+	`, s.ID, s.Status, steps, data, s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update saga: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SagaRepo) GetByID(ctx context.Context, id uuid.UUID, ownerID uuid.UUID) (*domain.Saga, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+sagaColumns+` FROM sagas WHERE id = $1 AND owner_id = $2`, id, ownerID)
+	s, err := scanSaga(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load saga: %w", err)
+	}
+	return s, nil
+}
+
+func (r *SagaRepo) ListRunning(ctx context.Context) ([]domain.Saga, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+sagaColumns+` FROM sagas
+		WHERE status = 'running'
+		ORDER BY created_at ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list running sagas: %w", err)
+	}
+	defer rows.Close()
+
+	var sagas []domain.Saga
+	for rows.Next() {
+		s, err := scanSaga(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saga: %w", err)
+		}
+		sagas = append(sagas, *s)
+	}
+	return sagas, rows.Err()
+}
+
+func (r *SagaRepo) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM sagas WHERE status <> 'running' AND updated_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune sagas: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func encodeSaga(s *domain.Saga) (steps, data []byte, err error) {
+	if steps, err = json.Marshal(s.Steps); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode saga steps: %w", err)
+	}
+	if data, err = json.Marshal(s.Data); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode saga data: %w", err)
+	}
+	return steps, data, nil
+}
+
+func scanSaga(row pgx.Row) (*domain.Saga, error) {
+	var s domain.Saga
+	var steps, data []byte
+	if err := row.Scan(&s.ID, &s.Kind, &s.OwnerID, &s.Status, &steps, &data, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(steps, &s.Steps); err != nil {
+		return nil, fmt.Errorf("corrupt saga steps: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.Data); err != nil {
+		return nil, fmt.Errorf("corrupt saga data: %w", err)
+	}
+	if s.Data == nil {
+		s.Data = make(map[string]json.RawMessage) // Steps write into it on recovery
+	}
+	return &s, nil
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type SBOMRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSBOMRepo(pool *pgxpool.Pool) domain.SBOMRepository {
+	return &SBOMRepo{pool: pool}
+}
+
+const sbomColumns = `s.deployment_id, s.app_id, d.domain_name, s.generator, s.components, s.truncated, s.created_at`
+
+// currentDeployments is each app's latest successful deployment, resolved to
+// the deployment that actually built it (a redeploy's source).
+const currentDeployments = `
+	SELECT DISTINCT ON (d.app_id) d.app_id, d.domain_name, COALESCE(rd.source_deployment_id, d.id) AS built_by
+	FROM deployments d
+	LEFT JOIN deployment_redeploys rd ON rd.deployment_id = d.id
+	WHERE d.status = $1
+	ORDER BY d.app_id, d.created_at DESC
+`
+
+func (r *SBOMRepo) Record(ctx context.Context, s *domain.SBOM) error {
+	components, err := json.Marshal(s.Components)
+	if err != nil {
+		return fmt.Errorf("failed to encode sbom components: %w", err)
+	}
+	err = r.pool.QueryRow(ctx, `
+		INSERT INTO deployment_sboms (deployment_id, app_id, generator, components, component_count, truncated)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (deployment_id) DO UPDATE SET
+			generator = EXCLUDED.generator, components = EXCLUDED.components,
+			component_count = EXCLUDED.component_count, truncated = EXCLUDED.truncated
+		RETURNING created_at
+	`, s.DeploymentID, s.AppID, s.Generator, components, len(s.Components), s.Truncated).Scan(&s.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record sbom: %w", err)
+	}
+	return nil
+}
+
+func (r *SBOMRepo) Current(ctx context.Context, appID string) (*domain.SBOM, error) {
+	s, err := scanSBOM(r.pool.QueryRow(ctx, `
+		WITH current AS (`+currentDeployments+`)
+		SELECT `+sbomColumns+`
+		FROM current c
+		JOIN deployment_sboms s ON s.deployment_id = c.built_by
+		JOIN deployments d ON d.id = s.deployment_id
+		WHERE c.app_id = $2
+	`, domain.StatusSuccess, appID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return s, err
+}
+
+func (r *SBOMRepo) ListCurrent(ctx context.Context) ([]domain.SBOM, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH current AS (`+currentDeployments+`)
+		SELECT `+sbomColumns+`
+		FROM current c
+		JOIN deployment_sboms s ON s.deployment_id = c.built_by
+		JOIN deployments d ON d.id = s.deployment_id
+	`, domain.StatusSuccess)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list current sboms: %w", err)
+	}
+	defer rows.Close()
+
+	sboms := []domain.SBOM{}
+	for rows.Next() {
+		s, err := scanSBOM(rows)
+		if err != nil {
+			return nil, err
+		}
+		sboms = append(sboms, *s)
+	}
+	return sboms, rows.Err()
+}
+
+func scanSBOM(row pgx.Row) (*domain.SBOM, error) {
+	var s domain.SBOM
+	var components []byte
+	err := row.Scan(&s.DeploymentID, &s.AppID, &s.DomainName, &s.Generator, &components, &s.Truncated, &s.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan sbom: %w", err)
+	}
+	if err := json.Unmarshal(components, &s.Components); err != nil {
+		return nil, fmt.Errorf("failed to decode sbom components: %w", err)
+	}
+	return &s, nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+// schemaSentinels holds one table (or "table.column") per migration that the
+// running Brain depends on. Migrations run as Postgres init scripts and
+// leave no ledger, so their objects are the proof they ran.
+// 🛡️ SLA: Append the newest object here with every migration.
+var schemaSentinels = []string{
+	"deployment_logs",              // 0002
+	"system_alerts",                // 001, 002
+	"role_permissions",             // 003
+	"idempotency_keys",             // 004
+	"tenant_logs",                  // 005
+	"tenant_log_chain_head",        // 007
+	"audit_sink_config",            // 008
+	"state_outbox",                 // 009 (renamed by 037)
+	"app_uid_ledger",               // 010
+	"applications.settings",        // 011
+	"applications.runtime_version", // 012
+	"app_deploy_keys",              // 013
+	"git_connections",              // 014
+	"registry_credentials",         // 015
+	"app_buckets",                  // 016
+	"applications.processes",       // 017
+	"applications.instances",       // 018
+	"file_scans",                   // 019
+	"domain_security_headers",      // 020
+	"domain_access_cursors",        // 021
+	"app_transfer_quotas",          // 022
+	"usage_exports",                // 023
+	"user_resource_overrides",      // 024
+	"system_settings",              // 025
+	"crash_reports",                // 026
+	"redaction_rules",              // 027
+	"user_passkeys",                // 028
+	"users.must_change_password",   // 029
+	"users.deactivated_at",         // 030
+	"digest_deliveries",            // 031
+	"deployment_log_archives",      // 032
+	"deployment_logs.level",        // 033
+	"deployment_logs.seq",          // 034
+	"sagas",                        // 035
+	"drift_findings",               // 036
+	"state_outbox.event",           // 037
+	"webhook_deliveries",           // 038
+	"chatops_identities",           // 039
+	"deploy_approvals",             // 040
+	"feed_tokens",                  // 041
+	"maintenance_windows",          // 042
+	"deployments.scheduled_at",     // 043
+	"applications.build_env_vars",  // 044
+	"deployment_artifacts",         // 045
+	"vulnerability_findings",       // 046
+	// 047 only adds a trigger; without it workers fall back to polling
+	"server_enroll_tokens",      // 048
+	"applications.server_id",    // 049
+	"server_ssh_credentials",    // 050
+	"servers.host_capabilities", // 051
+	"system_profiles",           // 052
+	"system_profile_rollouts",   // 052
+	"stack_versions",            // 053
+	"users.timezone",            // 054
+	"users.passkey_reenroll",    // 055
+	// 056 only adds a trigger; without it domain quotas go unenforced
+	// 057 only adds triggers; without them identity caches rely on their TTL
+}
+
+type SchemaCheck struct {
+	pool *pgxpool.Pool
+}
+
+func NewSchemaCheck(pool *pgxpool.Pool) domain.SchemaChecker {
+	return &SchemaCheck{pool: pool}
+}
+
+// MissingRelations checks every sentinel in a single round trip.
+func (c *SchemaCheck) MissingRelations(ctx context.Context) ([]string, error) {
+	tables := make([]string, len(schemaSentinels))
+	columns := make([]string, len(schemaSentinels))
+	for i, s := range schemaSentinels {
+		tables[i], columns[i], _ = strings.Cut(s, ".")
+	}
+
+	rows, err := c.pool.Query(ctx, `
+		SELECT s.table_name, s.column_name
+		FROM unnest($1::text[], $2::text[]) AS s(table_name, column_name)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM information_schema.columns ic
+			WHERE ic.table_schema = current_schema()
+			  AND ic.table_name = s.table_name
+			  AND (s.column_name = '' OR ic.column_name = s.column_name)
+		)
+	`, tables, columns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect schema: %w", err)
+	}
+	defer rows.Close()
+
+	missing := []string{}
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return nil, fmt.Errorf("failed to scan schema check: %w", err)
+		}
+		if column != "" {
+			table += "." + column
+		}
+		missing = append(missing, table)
+	}
+	return missing, rows.Err()
+}
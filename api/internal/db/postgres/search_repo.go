@@ -0,0 +1,96 @@
+// api/internal/db/postgres/search_repo.go
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type SearchRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSearchRepo(pool *pgxpool.Pool) domain.SearchRepository {
+	return &SearchRepo{pool: pool}
+}
+
+// searchBranches holds one ranked sub-query per index. Every branch shares the
+// the q CTE (tsquery + admin flag) and parameters $2 = caller ID, $3 = limit.
+// 🛡️ Tenant Isolation: Ownership is always resolved through domains.user_id.
+var searchBranches = map[domain.SearchKind]string{
+	domain.SearchKindApplication: `
+		SELECT 'application' AS kind, a.id::text AS id, a.app_user AS title, a.repo_url AS snippet,
+		       a.domain_id::text AS resource_id, ts_rank(a.search_vector, q.query) AS rank, a.created_at
+		FROM applications a
+		JOIN domains d ON a.domain_id = d.id
+		CROSS JOIN q
+		WHERE a.search_vector @@ q.query AND d.user_id = $2
+		ORDER BY rank DESC LIMIT $3`,
+
+	domain.SearchKindDomain: `
+		SELECT 'domain' AS kind, d.id::text AS id, d.name AS title, d.status AS snippet,
+		       COALESCE(d.app_id::text, '') AS resource_id, ts_rank(d.search_vector, q.query) AS rank, d.created_at
+		FROM domains d
+		CROSS JOIN q
+		WHERE d.search_vector @@ q.query AND d.user_id = $2
+		ORDER BY rank DESC LIMIT $3`,
+
+	domain.SearchKindDeploymentLog: `
+		SELECT 'deployment_log' AS kind, l.id::text AS id, dep.domain_name AS title,
+		       ts_headline('simple', l.content, q.query, 'MaxWords=20, MinWords=5, MaxFragments=1') AS snippet,
+		       dep.id::text AS resource_id, ts_rank(l.search_vector, q.query) AS rank, l.created_at
+		FROM deployment_logs l
+		JOIN deployments dep ON l.deployment_id = dep.id
+		JOIN applications a ON dep.app_id = a.id
+		JOIN domains d ON a.domain_id = d.id
+		CROSS JOIN q
+		WHERE l.search_vector @@ q.query AND d.user_id = $2
+		ORDER BY rank DESC LIMIT $3`,
+
+	// Audit hits honor q.all_tenants: admins search every tenant's trail.
+	domain.SearchKindAudit: `
+		SELECT 'audit' AS kind, t.id::text AS id, t.action AS title, t.resource_type || ':' || t.resource_id AS snippet,
+		       t.resource_id, ts_rank(t.search_vector, q.query) AS rank, t.created_at
+		FROM tenant_logs t
+		CROSS JOIN q
+		WHERE t.search_vector @@ q.query AND (q.all_tenants OR t.tenant_id = $2)
+		ORDER BY rank DESC LIMIT $3`,
+}
+
+// Search runs a single UNION ALL over the permitted indexes and merges by rank.
+func (r *SearchRepo) Search(ctx context.Context, sq domain.SearchQuery) ([]domain.SearchResult, error) {
+	parts := make([]string, 0, len(sq.Kinds))
+	for _, kind := range sq.Kinds {
+		if branch, ok := searchBranches[kind]; ok {
+			parts = append(parts, "("+branch+")")
+		}
+	}
+	if len(parts) == 0 {
+		return []domain.SearchResult{}, nil
+	}
+
+	// websearch_to_tsquery never errors on user input (quotes, OR, -negation are all safe)
+	query := `WITH q AS (SELECT websearch_to_tsquery('simple', $1) AS query, $4::boolean AS all_tenants) ` +
+		strings.Join(parts, " UNION ALL ") +
+		` ORDER BY rank DESC, created_at DESC LIMIT $3`
+
+	rows, err := r.pool.Query(ctx, query, sq.Text, sq.UserID, sq.Limit, sq.AllTenants)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute search: %w", err)
+	}
+	defer rows.Close()
+
+	results := []domain.SearchResult{}
+	for rows.Next() {
+		var res domain.SearchResult
+		if err := rows.Scan(&res.Kind, &res.ID, &res.Title, &res.Snippet, &res.ResourceID, &res.Rank, &res.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, res)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type SecurityHeadersRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSecurityHeadersRepo(pool *pgxpool.Pool) domain.SecurityHeadersRepository {
+	return &SecurityHeadersRepo{pool: pool}
+}
+
+// Get joins through domains so the ownership check and the read are one query.
+func (r *SecurityHeadersRepo) Get(ctx context.Context, domainID, ownerID uuid.UUID) (*domain.DomainSecurityHeaders, error) {
+	var (
+		h        domain.DomainSecurityHeaders
+		settings *domain.SecurityHeaders
+	)
+	err := r.pool.QueryRow(ctx, `
+		SELECT d.id, d.name, s.settings, s.updated_at
+		FROM domains d
+		LEFT JOIN domain_security_headers s ON s.domain_id = d.id
+		WHERE d.id = $1 AND d.user_id = $2
+	`, domainID, ownerID).Scan(&h.DomainID, &h.DomainName, &settings, &h.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load security headers: %w", err)
+	}
+
+	h.Settings = domain.SecurityHeaders{Preset: domain.HeaderPresetNone}
+	if settings != nil {
+		h.Settings = *settings
+	}
+	return &h, nil
+}
+
+func (r *SecurityHeadersRepo) Upsert(ctx context.Context, domainID uuid.UUID, settings domain.SecurityHeaders) (time.Time, error) {
+	var updatedAt time.Time
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO domain_security_headers (domain_id, settings)
+		VALUES ($1, $2)
+		ON CONFLICT (domain_id) DO UPDATE SET settings = EXCLUDED.settings, updated_at = NOW()
+		RETURNING updated_at
+	`, domainID, settings).Scan(&updatedAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to store security headers: %w", err)
+	}
+	return updatedAt, nil
+}
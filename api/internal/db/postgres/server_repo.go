@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type ServerRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewServerRepo(pool *pgxpool.Pool) domain.ServerRepository {
+	return &ServerRepo{pool: pool}
+}
+
+// ==============================================================================
+// 1. Enrollment Tokens
+// ==============================================================================
+
+func (r *ServerRepo) CreateEnrollToken(ctx context.Context, tokenHash, serverName string, createdBy uuid.UUID, expiresAt time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO server_enroll_tokens (token_hash, server_name, created_by, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`, tokenHash, serverName, createdBy, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to store enrollment token: %w", err)
+	}
+	return nil
+}
+
+func (r *ServerRepo) PeekEnrollToken(ctx context.Context, tokenHash string) (*domain.EnrollTokenClaim, error) {
+	var c domain.EnrollTokenClaim
+	err := r.pool.QueryRow(ctx, `
+		SELECT server_name, created_by FROM server_enroll_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+	`, tokenHash).Scan(&c.ServerName, &c.CreatedBy)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve enrollment token: %w", err)
+	}
+	return &c, nil
+}
+
+// ==============================================================================
+// 2. Servers
+// ==============================================================================
+
+func (r *ServerRepo) EnrollServer(ctx context.Context, s *domain.Server, tokenHash string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin enrollment tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// 🛡️ Zero-Trust: The row lock makes two agents racing on one token
+	// serialize here; the loser sees used_at set and gets no rows.
+	var tokenID uuid.UUID
+	var createdBy *uuid.UUID
+	err = tx.QueryRow(ctx, `
+		UPDATE server_enroll_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING id, created_by
+	`, tokenHash).Scan(&tokenID, &createdBy)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to consume enrollment token: %w", err)
+	}
+
+	if err := insertServer(ctx, tx, s, createdBy); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE server_enroll_tokens SET server_id = $2 WHERE id = $1`, tokenID, s.ID); err != nil {
+		return fmt.Errorf("failed to link enrollment token: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit enrollment: %w", err)
+	}
+	return nil
+}
+
+func insertServer(ctx context.Context, tx pgx.Tx, s *domain.Server, enrolledBy *uuid.UUID) error {
+	err := tx.QueryRow(ctx, `
+		INSERT INTO servers (id, name, hostname, executor, capabilities, cert_serial, cert_fingerprint, cert_expires_at, enrolled_by)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9)
+		RETURNING status, enrolled_at
+	`, s.ID, s.Name, s.Hostname, s.Executor, capabilityStrings(s.Capabilities),
+		s.CertSerial, s.CertFingerprint, s.CertExpiresAt, enrolledBy,
+	).Scan(&s.Status, &s.EnrolledAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("%w: a server named %q already exists", domain.ErrConflict, s.Name)
+		}
+		return fmt.Errorf("failed to register server: %w", err)
+	}
+	return nil
+}
+
+func capabilityStrings(caps []domain.ServerCapability) []string {
+	out := make([]string, len(caps))
+	for i, c := range caps {
+		out[i] = string(c)
+	}
+	return out
+}
+
+const serverColumns = `
+	id, name, hostname, executor, capabilities, host_capabilities,
+	COALESCE(cert_serial, ''), COALESCE(cert_fingerprint, ''), cert_expires_at,
+	status, last_seen_at, agent_version, enrolled_at`
+
+func scanServer(row pgx.Row) (*domain.Server, error) {
+	var s domain.Server
+	var caps []string
+	if err := row.Scan(&s.ID, &s.Name, &s.Hostname, &s.Executor, &caps, &s.Host,
+		&s.CertSerial, &s.CertFingerprint, &s.CertExpiresAt,
+		&s.Status, &s.LastSeenAt, &s.AgentVersion, &s.EnrolledAt); err != nil {
+		return nil, err
+	}
+	for _, c := range caps {
+		s.Capabilities = append(s.Capabilities, domain.ServerCapability(c))
+	}
+	return &s, nil
+}
+
+func (r *ServerRepo) ListServers(ctx context.Context) ([]domain.Server, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+serverColumns+` FROM servers ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+	defer rows.Close()
+
+	servers := []domain.Server{}
+	for rows.Next() {
+		s, err := scanServer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan server: %w", err)
+		}
+		servers = append(servers, *s)
+	}
+	return servers, rows.Err()
+}
+
+func (r *ServerRepo) GetServer(ctx context.Context, id uuid.UUID) (*domain.Server, error) {
+	s, err := scanServer(r.pool.QueryRow(ctx, `SELECT `+serverColumns+` FROM servers WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server: %w", err)
+	}
+	return s, nil
+}
+
+// ==============================================================================
+// 3. Liveness
+// ==============================================================================
+
+func (r *ServerRepo) RecordHeartbeat(ctx context.Context, id uuid.UUID, agentVersion string, seenAt time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE servers SET last_seen_at = $2, agent_version = $3 WHERE id = $1
+	`, id, seenAt, agentVersion)
+	if err != nil {
+		return fmt.Errorf("failed to record server heartbeat: %w", err)
+	}
+	return nil
+}
+
+func (r *ServerRepo) SetServerStatus(ctx context.Context, id uuid.UUID, status domain.ServerStatus) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE servers SET status = $2 WHERE id = $1`, id, status)
+	if err != nil {
+		return fmt.Errorf("failed to update server status: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ServerRepo) RecordHostCapabilities(ctx context.Context, id uuid.UUID, host *domain.HostCapabilities, caps []domain.ServerCapability) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE servers SET host_capabilities = $2, capabilities = $3 WHERE id = $1
+	`, id, host, capabilityStrings(caps))
+	if err != nil {
+		return fmt.Errorf("failed to record server capabilities: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ==============================================================================
+// 4. SSH Servers
+// ==============================================================================
+
+func (r *ServerRepo) CreateSSHServer(ctx context.Context, s *domain.Server, cred *domain.SSHCredential) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin ssh server tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertServer(ctx, tx, s, nil); err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO server_ssh_credentials (server_id, username, port, host_key, public_key, private_key_ciphertext)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, s.ID, cred.Username, cred.Port, cred.HostKey, cred.PublicKey, cred.PrivateKeyCiphertext)
+	if err != nil {
+		return fmt.Errorf("failed to store ssh credential: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit ssh server: %w", err)
+	}
+	return nil
+}
+
+func (r *ServerRepo) GetSSHCredential(ctx context.Context, serverID uuid.UUID) (*domain.SSHCredential, error) {
+	c := domain.SSHCredential{ServerID: serverID}
+	err := r.pool.QueryRow(ctx, `
+		SELECT username, port, host_key, public_key, private_key_ciphertext
+		FROM server_ssh_credentials WHERE server_id = $1
+	`, serverID).Scan(&c.Username, &c.Port, &c.HostKey, &c.PublicKey, &c.PrivateKeyCiphertext)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ssh credential: %w", err)
+	}
+	return &c, nil
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type SettingsRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSettingsRepo(pool *pgxpool.Pool) domain.SettingsRepository {
+	return &SettingsRepo{pool: pool}
+}
+
+func (r *SettingsRepo) Get(ctx context.Context) (*domain.SystemSettings, error) {
+	var s domain.SystemSettings
+	err := r.pool.QueryRow(ctx, `
+		SELECT maintenance_enabled, maintenance_message, maintenance_since, maintenance_set_by,
+		       passkey_require_rank0, passkey_password_fallback,
+		       password_min_length, password_max_age_days, password_breach_check, timezone, updated_at
+		FROM system_settings WHERE id
+	`).Scan(&s.Maintenance.Enabled, &s.Maintenance.Message, &s.Maintenance.Since, &s.Maintenance.SetBy,
+		&s.Passkeys.RequireForRank0, &s.Passkeys.PasswordFallback,
+		&s.Passwords.MinLength, &s.Passwords.MaxAgeDays, &s.Passwords.BreachCheck, &s.Timezone, &s.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Seeded by the migration; defaults if it was removed
+			return &domain.SystemSettings{
+				Passkeys:  domain.DefaultPasskeyPolicy,
+				Passwords: domain.DefaultPasswordPolicy,
+				Timezone:  domain.DefaultTimezone,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to load system settings: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *SettingsRepo) SaveMaintenance(ctx context.Context, m domain.MaintenanceMode) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO system_settings (id, maintenance_enabled, maintenance_message, maintenance_since, maintenance_set_by, updated_at)
+		VALUES (true, $1, $2, $3, $4, NOW())
+		ON CONFLICT (id) DO UPDATE SET
+			maintenance_enabled = EXCLUDED.maintenance_enabled,
+			maintenance_message = EXCLUDED.maintenance_message,
+			maintenance_since = EXCLUDED.maintenance_since,
+			maintenance_set_by = EXCLUDED.maintenance_set_by,
+			updated_at = NOW()
+	`, m.Enabled, m.Message, m.Since, m.SetBy)
+	if err != nil {
+		return fmt.Errorf("failed to save maintenance mode: %w", err)
+	}
+	return nil
+}
+
+func (r *SettingsRepo) SavePasskeyPolicy(ctx context.Context, p domain.PasskeyPolicy) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO system_settings (id, passkey_require_rank0, passkey_password_fallback, updated_at)
+		VALUES (true, $1, $2, NOW())
+		ON CONFLICT (id) DO UPDATE SET
+			passkey_require_rank0 = EXCLUDED.passkey_require_rank0,
+			passkey_password_fallback = EXCLUDED.passkey_password_fallback,
+			updated_at = NOW()
+	`, p.RequireForRank0, p.PasswordFallback)
+	if err != nil {
+		return fmt.Errorf("failed to save passkey policy: %w", err)
+	}
+	return nil
+}
+
+func (r *SettingsRepo) SavePasswordPolicy(ctx context.Context, p domain.PasswordPolicy) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO system_settings (id, password_min_length, password_max_age_days, password_breach_check, updated_at)
+		VALUES (true, $1, $2, $3, NOW())
+		ON CONFLICT (id) DO UPDATE SET
+			password_min_length = EXCLUDED.password_min_length,
+			password_max_age_days = EXCLUDED.password_max_age_days,
+			password_breach_check = EXCLUDED.password_breach_check,
+			updated_at = NOW()
+	`, p.MinLength, p.MaxAgeDays, p.BreachCheck)
+	if err != nil {
+		return fmt.Errorf("failed to save password policy: %w", err)
+	}
+	return nil
+}
+
+func (r *SettingsRepo) SaveTimezone(ctx context.Context, tz string) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO system_settings (id, timezone, updated_at)
+		VALUES (true, $1, NOW())
+		ON CONFLICT (id) DO UPDATE SET
+			timezone = EXCLUDED.timezone,
+			updated_at = NOW()
+	`, tz)
+	if err != nil {
+		return fmt.Errorf("failed to save timezone: %w", err)
+	}
+	return nil
+}
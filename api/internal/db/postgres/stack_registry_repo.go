@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type StackRegistryRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewStackRegistryRepo(pool *pgxpool.Pool) domain.StackRegistryRepository {
+	return &StackRegistryRepo{pool: pool}
+}
+
+// stackDefault is the active profile's default for s.runtime, empty if none.
+const stackDefault = `COALESCE((SELECT default_stack_registry ->> s.runtime FROM system_profiles LIMIT 1), '')`
+
+// An app builds with a version if it pins it, or pins nothing while the
+// version is the default.
+const stackVersionColumns = `s.runtime, s.version, s.status, s.added_at, s.deprecated_at,
+	` + stackDefault + ` = s.version AS is_default,
+	(SELECT COUNT(*) FROM applications a
+	  WHERE a.app_type = s.runtime
+	    AND (a.runtime_version = s.version OR (a.runtime_version = '' AND ` + stackDefault + ` = s.version))) AS app_count`
+
+func scanStackVersion(row pgx.Row, v *domain.StackVersion) error {
+	return row.Scan(&v.Runtime, &v.Version, &v.Status, &v.AddedAt, &v.DeprecatedAt, &v.IsDefault, &v.AppCount)
+}
+
+func (r *StackRegistryRepo) ListStackVersions(ctx context.Context) (domain.StackCatalogue, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+stackVersionColumns+` FROM stack_versions s ORDER BY s.runtime, string_to_array(s.version, '.')::int[]`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stack versions: %w", err)
+	}
+	defer rows.Close()
+
+	catalogue := domain.StackCatalogue{}
+	for rows.Next() {
+		var v domain.StackVersion
+		if err := scanStackVersion(rows, &v); err != nil {
+			return nil, fmt.Errorf("failed to scan stack version: %w", err)
+		}
+		catalogue = append(catalogue, v)
+	}
+	return catalogue, rows.Err()
+}
+
+func (r *StackRegistryRepo) CreateStackVersion(ctx context.Context, runtime, version string) (*domain.StackVersion, error) {
+	if _, err := r.pool.Exec(ctx,
+		`INSERT INTO stack_versions (runtime, version) VALUES ($1, $2)`, runtime, version); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, fmt.Errorf("%w: %s %s is already in the stack registry", domain.ErrConflict, runtime, version)
+		}
+		return nil, fmt.Errorf("failed to add stack version: %w", err)
+	}
+	return r.get(ctx, runtime, version)
+}
+
+func (r *StackRegistryRepo) SetStackVersionStatus(ctx context.Context, runtime, version string, status domain.StackVersionStatus) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE stack_versions SET
+			status = $3,
+			deprecated_at = CASE WHEN $3 = 'deprecated' THEN COALESCE(deprecated_at, NOW()) END
+		WHERE runtime = $1 AND version = $2`, runtime, version, string(status))
+	if err != nil {
+		return fmt.Errorf("failed to update stack version: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *StackRegistryRepo) DeleteStackVersion(ctx context.Context, runtime, version string) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM stack_versions
+		WHERE runtime = $1 AND version = $2
+		  AND NOT EXISTS (SELECT 1 FROM applications WHERE app_type = $1 AND runtime_version = $2)`,
+		runtime, version)
+	if err != nil {
+		return fmt.Errorf("failed to remove stack version: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		// Either it was never listed, or an app still pins it
+		if _, err := r.get(ctx, runtime, version); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: apps are still pinned to %s %s; migrate them first", domain.ErrConflict, runtime, version)
+	}
+	return nil
+}
+
+func (r *StackRegistryRepo) ListStackVersionApps(ctx context.Context, runtime, version string) ([]domain.StackVersionApp, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT a.id, d.domain_name, d.user_id, a.runtime_version <> ''
+		FROM applications a
+		INNER JOIN domains d ON a.domain_id = d.id
+		WHERE a.app_type = $1
+		  AND (a.runtime_version = $2 OR (a.runtime_version = ''
+		       AND COALESCE((SELECT default_stack_registry ->> $1 FROM system_profiles LIMIT 1), '') = $2))
+		ORDER BY d.domain_name`, runtime, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list apps on stack version: %w", err)
+	}
+	defer rows.Close()
+
+	apps := []domain.StackVersionApp{}
+	for rows.Next() {
+		var a domain.StackVersionApp
+		if err := rows.Scan(&a.AppID, &a.DomainName, &a.OwnerID, &a.Pinned); err != nil {
+			return nil, fmt.Errorf("failed to scan app on stack version: %w", err)
+		}
+		apps = append(apps, a)
+	}
+	return apps, rows.Err()
+}
+
+func (r *StackRegistryRepo) RepinApps(ctx context.Context, runtime, from, to string, includeUnpinned bool) ([]domain.Application, error) {
+	rows, err := r.pool.Query(ctx, `
+		UPDATE applications a SET runtime_version = $3, updated_at = NOW()
+		FROM domains d
+		WHERE a.domain_id = d.id AND a.app_type = $1
+		  AND (a.runtime_version = $2 OR (a.runtime_version = '' AND $4))
+		RETURNING a.id, a.domain_id, d.domain_name, d.user_id, a.app_type, a.runtime_version,
+		          a.repo_url, a.branch, a.build_command, a.port`,
+		runtime, from, to, includeUnpinned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate apps between stack versions: %w", err)
+	}
+	defer rows.Close()
+
+	apps := []domain.Application{}
+	for rows.Next() {
+		var a domain.Application
+		if err := rows.Scan(&a.ID, &a.DomainID, &a.DomainName, &a.OwnerID, &a.AppType, &a.RuntimeVersion,
+			&a.RepoURL, &a.Branch, &a.BuildCommand, &a.Port); err != nil {
+			return nil, fmt.Errorf("failed to scan migrated app: %w", err)
+		}
+		apps = append(apps, a)
+	}
+	return apps, rows.Err()
+}
+
+func (r *StackRegistryRepo) get(ctx context.Context, runtime, version string) (*domain.StackVersion, error) {
+	var v domain.StackVersion
+	err := scanStackVersion(r.pool.QueryRow(ctx,
+		`SELECT `+stackVersionColumns+` FROM stack_versions s WHERE s.runtime = $1 AND s.version = $2`, runtime, version), &v)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stack version: %w", err)
+	}
+	return &v, nil
+}
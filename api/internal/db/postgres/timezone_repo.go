@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type TimezoneRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTimezoneRepo(pool *pgxpool.Pool) domain.UserTimezoneRepository {
+	return &TimezoneRepo{pool: pool}
+}
+
+func (r *TimezoneRepo) GetUserTimezone(ctx context.Context, userID uuid.UUID) (string, error) {
+	var tz string
+	err := r.pool.QueryRow(ctx, `SELECT COALESCE(timezone, '') FROM users WHERE id = $1`, userID).Scan(&tz)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", domain.ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load user timezone: %w", err)
+	}
+	return tz, nil
+}
+
+// SetUserTimezone stores NULL for "", so the user follows the install again.
+func (r *TimezoneRepo) SetUserTimezone(ctx context.Context, userID uuid.UUID, tz string) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE users SET timezone = NULLIF($2, ''), updated_at = NOW() WHERE id = $1`, userID, tz)
+	if err != nil {
+		return fmt.Errorf("failed to save user timezone: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
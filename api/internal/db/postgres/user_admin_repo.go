@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+// userSorts whitelists the public sort keys of GET /users.
+var userSorts = map[string]sortColumn{
+	"created_at": {expr: "u.created_at", cast: "timestamptz"},
+	"email":      {expr: "u.email", cast: "text"},
+}
+
+const managedUserColumns = `
+	u.id, u.email, u.role_id, r.name, r.rank, u.is_active, u.must_change_password,
+	u.password_changed_at, u.deactivated_at, u.created_at, u.updated_at
+`
+
+type UserAdminRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewUserAdminRepo(pool *pgxpool.Pool) domain.UserAdminRepository {
+	return &UserAdminRepo{pool: pool}
+}
+
+func (r *UserAdminRepo) List(ctx context.Context, filter domain.UserFilter, page domain.PageRequest) (domain.Page[domain.ManagedUser], error) {
+	page = page.Normalize()
+
+	q := &listQuery{}
+	if filter.Query != "" {
+		q.where("u.email ILIKE " + q.arg("%"+escapeLike(filter.Query)+"%"))
+	}
+	if filter.RoleID != uuid.Nil {
+		q.where("u.role_id = " + q.arg(filter.RoleID))
+	}
+	if filter.Active != nil {
+		q.where("u.is_active = " + q.arg(*filter.Active))
+	}
+
+	from := " FROM users u JOIN roles r ON u.role_id = r.id"
+
+	var total int
+	if err := r.pool.QueryRow(ctx, "SELECT COUNT(*)"+from+q.whereSQL(), q.args...).Scan(&total); err != nil {
+		return domain.Page[domain.ManagedUser]{}, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	tail, err := q.paginate(page, userSorts, "created_at", "u.id")
+	if err != nil {
+		return domain.Page[domain.ManagedUser]{}, err
+	}
+
+	rows, err := r.pool.Query(ctx, "SELECT "+managedUserColumns+from+q.whereSQL()+tail, q.args...)
+	if err != nil {
+		return domain.Page[domain.ManagedUser]{}, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []domain.ManagedUser
+	for rows.Next() {
+		u, err := scanManagedUser(rows)
+		if err != nil {
+			return domain.Page[domain.ManagedUser]{}, err
+		}
+		users = append(users, *u)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.Page[domain.ManagedUser]{}, err
+	}
+
+	return buildPage(users, page, total, func(u domain.ManagedUser) domain.Cursor {
+		if page.Sort == "email" {
+			return domain.Cursor{Value: u.Email, ID: u.ID}
+		}
+		return domain.Cursor{Value: u.CreatedAt.Format(time.RFC3339Nano), ID: u.ID}
+	}), nil
+}
+
+func (r *UserAdminRepo) Get(ctx context.Context, id uuid.UUID) (*domain.ManagedUser, error) {
+	row := r.pool.QueryRow(ctx, "SELECT "+managedUserColumns+`
+		FROM users u JOIN roles r ON u.role_id = r.id
+		WHERE u.id = $1
+	`, id)
+	u, err := scanManagedUser(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return u, err
+}
+
+func (r *UserAdminRepo) Create(ctx context.Context, email, passwordHash string, roleID uuid.UUID, mustChangePassword bool) (*domain.ManagedUser, error) {
+	var id uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO users (email, password_hash, role_id, must_change_password)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, email, passwordHash, roleID, mustChangePassword).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, domain.ErrConflict // Email already registered
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *UserAdminRepo) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE users SET email = $2, updated_at = NOW() WHERE id = $1`, id, email)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrConflict
+		}
+		return fmt.Errorf("failed to update user email: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserAdminRepo) SetMustChangePassword(ctx context.Context, id uuid.UUID, must bool) error {
+	tag, err := r.pool.Exec(ctx, `UPDATE users SET must_change_password = $2, updated_at = NOW() WHERE id = $1`, id, must)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserAdminRepo) SetActive(ctx context.Context, id uuid.UUID, active bool) error {
+	query := `
+		UPDATE users
+		SET is_active = true, deactivated_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+	if !active {
+		query = `
+			UPDATE users
+			SET is_active = false, deactivated_at = NOW(), refresh_token = NULL, updated_at = NOW()
+			WHERE id = $1
+		`
+	}
+	tag, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to update user status: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func scanManagedUser(row pgx.Row) (*domain.ManagedUser, error) {
+	var u domain.ManagedUser
+	err := row.Scan(&u.ID, &u.Email, &u.RoleID, &u.RoleName, &u.RoleRank, &u.IsActive, &u.MustChangePassword,
+		&u.PasswordChangedAt, &u.DeactivatedAt, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan user: %w", err)
+	}
+	return &u, nil
+}
+
+// escapeLike neutralizes LIKE wildcards in user-supplied search text.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
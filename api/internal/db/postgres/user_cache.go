@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+// identityChannel is notified by the identity triggers (migration 057) with
+// a user id, or "*" after a role, grant or multi-user change.
+const identityChannel = "kari_identity"
+
+// identityRetry paces reconnects of the identity listener.
+const identityRetry = 5 * time.Second
+
+// UserCache memoizes the per-request identity reads (the ghost-token check in
+// RequireAuthentication and RBAC permission lookups) for a short TTL.
+// 🛡️ Zero-Trust: Every mutation made through this process invalidates the
+// affected user at once, and Follow applies the ones made elsewhere (other
+// replicas, recover-admin, SQL); the TTL only bounds staleness while the
+// listener is down.
+type UserCache struct {
+	ttl   time.Duration
+	mu    sync.Mutex
+	users map[uuid.UUID]cachedUser
+	perms map[permKey]cachedPerm
+}
+
+type cachedUser struct {
+	user    domain.User
+	expires time.Time
+}
+
+type permKey struct {
+	userID   uuid.UUID
+	resource string
+	action   string
+}
+
+type cachedPerm struct {
+	allowed bool
+	expires time.Time
+}
+
+func NewUserCache(ttl time.Duration) *UserCache {
+	return &UserCache{
+		ttl:   ttl,
+		users: make(map[uuid.UUID]cachedUser),
+		perms: make(map[permKey]cachedPerm),
+	}
+}
+
+// Invalidate drops everything cached for one user.
+func (c *UserCache) Invalidate(userID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.users, userID)
+	for k := range c.perms {
+		if k.userID == userID {
+			delete(c.perms, k)
+		}
+	}
+}
+
+// InvalidateAll empties the cache; call it when a role's permission set changes.
+func (c *UserCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.users)
+	clear(c.perms)
+}
+
+// sweep removes expired entries so users who stop calling the API don't pin memory.
+func (c *UserCache) sweep(now time.Time) {
+	for id, e := range c.users {
+		if now.After(e.expires) {
+			delete(c.users, id)
+		}
+	}
+	for k, e := range c.perms {
+		if now.After(e.expires) {
+			delete(c.perms, k)
+		}
+	}
+}
+
+// Follow applies the identity triggers' notifications until ctx is
+// cancelled. Anything missed while disconnected is unknown, so every
+// (re)connect starts from an empty cache.
+func (c *UserCache) Follow(ctx context.Context, pool *pgxpool.Pool, logger *slog.Logger) {
+	for {
+		c.InvalidateAll()
+		err := listen(ctx, pool, identityChannel, func(payload string) {
+			if payload == "*" {
+				c.InvalidateAll()
+				return
+			}
+			if id, err := uuid.Parse(payload); err == nil {
+				c.Invalidate(id)
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn("⚠️ Identity cache listener stopped; reconnecting", slog.Any("error", err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(identityRetry):
+		}
+	}
+}
+
+// Start sweeps expired entries every TTL until ctx is cancelled.
+func (c *UserCache) Start(ctx context.Context) {
+	ticker := time.NewTicker(max(c.ttl, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			c.mu.Lock()
+			c.sweep(now)
+			c.mu.Unlock()
+		}
+	}
+}
+
+// ==============================================================================
+// Cached UserRepository
+// ==============================================================================
+
+type cachedUserRepo struct {
+	domain.UserRepository
+	cache *UserCache
+}
+
+// NewCachedUserRepo serves GetByID and HasPermission from cache and
+// invalidates on every write that changes identity or role.
+func NewCachedUserRepo(inner domain.UserRepository, cache *UserCache) domain.UserRepository {
+	return &cachedUserRepo{UserRepository: inner, cache: cache}
+}
+
+func (r *cachedUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	now := time.Now()
+	r.cache.mu.Lock()
+	e, ok := r.cache.users[id]
+	r.cache.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		user := e.user // Callers get their own copy
+		return &user, nil
+	}
+
+	user, err := r.UserRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err // Misses and errors are never cached
+	}
+	r.cache.mu.Lock()
+	r.cache.users[id] = cachedUser{user: *user, expires: now.Add(r.cache.ttl)}
+	r.cache.mu.Unlock()
+	return user, nil
+}
+
+func (r *cachedUserRepo) HasPermission(ctx context.Context, userID uuid.UUID, resource string, action string) (bool, error) {
+	key := permKey{userID: userID, resource: resource, action: action}
+	now := time.Now()
+	r.cache.mu.Lock()
+	e, ok := r.cache.perms[key]
+	r.cache.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.allowed, nil
+	}
+
+	allowed, err := r.UserRepository.HasPermission(ctx, userID, resource, action)
+	if err != nil {
+		return false, err
+	}
+	r.cache.mu.Lock()
+	r.cache.perms[key] = cachedPerm{allowed: allowed, expires: now.Add(r.cache.ttl)}
+	r.cache.mu.Unlock()
+	return allowed, nil
+}
+
+func (r *cachedUserRepo) UpdateUserRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) error {
+	defer r.cache.Invalidate(userID)
+	return r.UserRepository.UpdateUserRole(ctx, userID, roleID)
+}
+
+func (r *cachedUserRepo) UpdatePassword(ctx context.Context, id uuid.UUID, hash string) error {
+	defer r.cache.Invalidate(id)
+	return r.UserRepository.UpdatePassword(ctx, id, hash)
+}
+
+func (r *cachedUserRepo) UpdateRefreshToken(ctx context.Context, id uuid.UUID, token string) error {
+	defer r.cache.Invalidate(id)
+	return r.UserRepository.UpdateRefreshToken(ctx, id, token)
+}
+
+// ==============================================================================
+// Cached UserAdminRepository (write-through invalidation only)
+// ==============================================================================
+
+type cachedUserAdminRepo struct {
+	domain.UserAdminRepository
+	cache *UserCache
+}
+
+// NewCachedUserAdminRepo invalidates the shared cache on /users mutations, so
+// a deactivated account is locked out on its very next request.
+func NewCachedUserAdminRepo(inner domain.UserAdminRepository, cache *UserCache) domain.UserAdminRepository {
+	return &cachedUserAdminRepo{UserAdminRepository: inner, cache: cache}
+}
+
+func (r *cachedUserAdminRepo) UpdateEmail(ctx context.Context, id uuid.UUID, email string) error {
+	defer r.cache.Invalidate(id)
+	return r.UserAdminRepository.UpdateEmail(ctx, id, email)
+}
+
+func (r *cachedUserAdminRepo) SetMustChangePassword(ctx context.Context, id uuid.UUID, must bool) error {
+	defer r.cache.Invalidate(id)
+	return r.UserAdminRepository.SetMustChangePassword(ctx, id, must)
+}
+
+func (r *cachedUserAdminRepo) SetActive(ctx context.Context, id uuid.UUID, active bool) error {
+	defer r.cache.Invalidate(id)
+	return r.UserAdminRepository.SetActive(ctx, id, active)
+}
@@ -42,6 +42,7 @@ func (r *UserRepo) HasPermission(ctx context.Context, userID uuid.UUID, resource
 func (r *UserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	query := `
 		SELECT u.id, u.email, u.password_hash, u.is_active, u.created_at, u.updated_at,
+		       u.password_changed_at, u.must_change_password,
 		       r.id, r.name, r.rank
 		FROM users u
 		JOIN roles r ON u.role_id = r.id
@@ -52,6 +53,7 @@ func (r *UserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, err
 
 	err := r.pool.QueryRow(ctx, query, id).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
+		&user.PasswordChangedAt, &user.MustChangePassword,
 		&role.ID, &role.Name, &role.Rank,
 	)
 
@@ -78,6 +80,25 @@ func (r *UserRepo) UpdateRefreshToken(ctx context.Context, id uuid.UUID, token s
 	return nil
 }
 
+// 🛡️ UpdatePassword rotates the hash and revokes the stored refresh token, so
+// every other session must sign in again with the new password.
+func (r *UserRepo) UpdatePassword(ctx context.Context, id uuid.UUID, hash string) error {
+	query := `
+		UPDATE users
+		SET password_hash = $1, password_changed_at = NOW(), must_change_password = false,
+		    refresh_token = NULL, updated_at = NOW()
+		WHERE id = $2
+	`
+	tag, err := r.pool.Exec(ctx, query, hash, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
 // 🛡️ GetRoleByID allows RoleService to verify ranks before assignment.
 func (r *UserRepo) GetRoleByID(ctx context.Context, id uuid.UUID) (*domain.Role, error) {
 	query := `SELECT id, name, rank FROM roles WHERE id = $1`
@@ -89,6 +110,22 @@ func (r *UserRepo) GetRoleByID(ctx context.Context, id uuid.UUID) (*domain.Role,
 	return &role, err
 }
 
+// ListRolePermissions returns what a role grants as sorted "resource:action"
+// strings, so privilege changes can be audited as a before/after set.
+func (r *UserRepo) ListRolePermissions(ctx context.Context, roleID uuid.UUID) ([]string, error) {
+	query := `
+		SELECT p.resource || ':' || p.action
+		FROM role_permissions rp
+		JOIN permissions p ON rp.permission_id = p.id
+		WHERE rp.role_id = $1
+		ORDER BY 1`
+	rows, err := r.pool.Query(ctx, query, roleID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
 // 🛡️ CountAdmins provides a fail-fast check for the "Last Admin" protection logic.
 func (r *UserRepo) CountAdmins(ctx context.Context) (int, error) {
 	query := `SELECT COUNT(*) FROM users u JOIN roles r ON u.role_id = r.id WHERE r.rank = 0 AND u.is_active = true`
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type VulnerabilityRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewVulnerabilityRepo(pool *pgxpool.Pool) domain.VulnerabilityRepository {
+	return &VulnerabilityRepo{pool: pool}
+}
+
+const vulnColumns = `id, app_id, deployment_id, vuln_id, aliases, summary, severity, package_name, package_version, purl, first_seen_at, last_seen_at, resolved_at`
+
+func (r *VulnerabilityRepo) Sync(ctx context.Context, appID string, deploymentID string, findings []domain.VulnerabilityFinding) ([]domain.VulnerabilityFinding, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin vulnerability tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now().UTC()
+	var opened []domain.VulnerabilityFinding
+	for _, f := range findings {
+		var isNew bool
+		if f.Aliases == nil {
+			f.Aliases = []string{} // NOT NULL column
+		}
+		// Opened = freshly inserted (xmax = 0) or previously resolved; the CTE
+		// still sees the row as it was before this statement
+		err := tx.QueryRow(ctx, `
+			WITH prev AS (
+				SELECT resolved_at FROM vulnerability_findings
+				WHERE app_id = $1 AND vuln_id = $3 AND purl = $9
+			)
+			INSERT INTO vulnerability_findings
+				(app_id, deployment_id, vuln_id, aliases, summary, severity, package_name, package_version, purl, first_seen_at, last_seen_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+			ON CONFLICT (app_id, vuln_id, purl) DO UPDATE SET
+				deployment_id = EXCLUDED.deployment_id, aliases = EXCLUDED.aliases,
+				summary = EXCLUDED.summary, severity = EXCLUDED.severity,
+				last_seen_at = EXCLUDED.last_seen_at, resolved_at = NULL
+			RETURNING id, first_seen_at, (xmax = 0 OR EXISTS (SELECT 1 FROM prev WHERE resolved_at IS NOT NULL))
+		`, appID, deploymentID, f.VulnID, f.Aliases, f.Summary, f.Severity, f.PackageName, f.PackageVersion, f.PURL, now,
+		).Scan(&f.ID, &f.FirstSeenAt, &isNew)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record vulnerability finding: %w", err)
+		}
+		if isNew {
+			f.AppID, f.DeploymentID, f.LastSeenAt = appID, deploymentID, now
+			opened = append(opened, f)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE vulnerability_findings SET resolved_at = $2
+		WHERE app_id = $1 AND resolved_at IS NULL AND last_seen_at < $2
+	`, appID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve vulnerability findings: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit vulnerability sync: %w", err)
+	}
+	return opened, nil
+}
+
+func (r *VulnerabilityRepo) ListOpen(ctx context.Context, appID string) ([]domain.VulnerabilityFinding, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+vulnColumns+` FROM vulnerability_findings
+		WHERE app_id = $1 AND resolved_at IS NULL
+		ORDER BY CASE severity WHEN $2 THEN 0 WHEN $3 THEN 1 WHEN $4 THEN 2 WHEN $5 THEN 3 ELSE 4 END, package_name, vuln_id
+	`, appID, domain.VulnSeverityCritical, domain.VulnSeverityHigh, domain.VulnSeverityModerate, domain.VulnSeverityLow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vulnerability findings: %w", err)
+	}
+	defer rows.Close()
+
+	findings := []domain.VulnerabilityFinding{}
+	for rows.Next() {
+		f, err := scanVulnerabilityFinding(rows)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, *f)
+	}
+	return findings, rows.Err()
+}
+
+func scanVulnerabilityFinding(row pgx.Row) (*domain.VulnerabilityFinding, error) {
+	var f domain.VulnerabilityFinding
+	err := row.Scan(&f.ID, &f.AppID, &f.DeploymentID, &f.VulnID, &f.Aliases, &f.Summary, &f.Severity,
+		&f.PackageName, &f.PackageVersion, &f.PURL, &f.FirstSeenAt, &f.LastSeenAt, &f.ResolvedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan vulnerability finding: %w", err)
+	}
+	return &f, nil
+}
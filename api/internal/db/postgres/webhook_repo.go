@@ -0,0 +1,241 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"kari/api/internal/core/domain"
+)
+
+type WebhookRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepo(pool *pgxpool.Pool) domain.WebhookRepository {
+	return &WebhookRepo{pool: pool}
+}
+
+const webhookColumns = `id, user_id, url, events, enabled, created_at, updated_at`
+
+const deliveryColumns = `d.id, d.webhook_id, d.event_id, d.event, d.payload, d.status, d.attempts,
+	d.next_attempt_at, d.response_status, d.last_error, d.duration_ms, d.created_at, d.delivered_at`
+
+// ==============================================================================
+// 1. Webhooks
+// ==============================================================================
+
+func (r *WebhookRepo) Create(ctx context.Context, w *domain.OutgoingWebhook, secretCiphertext string) error {
+	return r.pool.QueryRow(ctx, `
+		INSERT INTO outgoing_webhooks (user_id, url, secret_ciphertext, events, enabled)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, w.UserID, w.URL, secretCiphertext, w.Events, w.Enabled).Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt)
+}
+
+func (r *WebhookRepo) List(ctx context.Context, userID uuid.UUID) ([]domain.OutgoingWebhook, error) {
+	return r.queryWebhooks(ctx, `SELECT `+webhookColumns+` FROM outgoing_webhooks WHERE user_id = $1 ORDER BY created_at`, userID)
+}
+
+func (r *WebhookRepo) Get(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*domain.OutgoingWebhook, error) {
+	var w domain.OutgoingWebhook
+	err := r.pool.QueryRow(ctx, `SELECT `+webhookColumns+` FROM outgoing_webhooks WHERE id = $1 AND user_id = $2`, id, userID).
+		Scan(&w.ID, &w.UserID, &w.URL, &w.Events, &w.Enabled, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load webhook: %w", err)
+	}
+	return &w, nil
+}
+
+func (r *WebhookRepo) Update(ctx context.Context, w *domain.OutgoingWebhook) error {
+	err := r.pool.QueryRow(ctx, `
+		UPDATE outgoing_webhooks SET url = $3, events = $4, enabled = $5, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING updated_at
+	`, w.ID, w.UserID, w.URL, w.Events, w.Enabled).Scan(&w.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	return err
+}
+
+func (r *WebhookRepo) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM outgoing_webhooks WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepo) Subscribers(ctx context.Context, userID uuid.UUID, event string) ([]domain.OutgoingWebhook, error) {
+	return r.queryWebhooks(ctx, `
+		SELECT `+webhookColumns+` FROM outgoing_webhooks
+		WHERE user_id = $1 AND enabled AND $2 = ANY(events)
+	`, userID, event)
+}
+
+func (r *WebhookRepo) queryWebhooks(ctx context.Context, query string, args ...any) ([]domain.OutgoingWebhook, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	hooks := []domain.OutgoingWebhook{}
+	for rows.Next() {
+		var w domain.OutgoingWebhook
+		if err := rows.Scan(&w.ID, &w.UserID, &w.URL, &w.Events, &w.Enabled, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		hooks = append(hooks, w)
+	}
+	return hooks, rows.Err()
+}
+
+// ==============================================================================
+// 2. Deliveries
+// ==============================================================================
+
+func (r *WebhookRepo) EnqueueDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	d.Status = domain.DeliveryPending
+	return r.pool.QueryRow(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event, payload)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, next_attempt_at, created_at
+	`, d.WebhookID, d.EventID, d.Event, d.Payload).Scan(&d.ID, &d.NextAttemptAt, &d.CreatedAt)
+}
+
+func (r *WebhookRepo) DueDeliveries(ctx context.Context, limit int) ([]domain.WebhookTarget, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+deliveryColumns+`, w.user_id, w.url, w.secret_ciphertext
+		FROM webhook_deliveries d
+		JOIN outgoing_webhooks w ON w.id = d.webhook_id
+		WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.enabled
+		ORDER BY d.next_attempt_at
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load due deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []domain.WebhookTarget
+	for rows.Next() {
+		t, err := scanTarget(rows)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, *t)
+	}
+	return targets, rows.Err()
+}
+
+func (r *WebhookRepo) Target(ctx context.Context, deliveryID uuid.UUID) (*domain.WebhookTarget, error) {
+	t, err := scanTarget(r.pool.QueryRow(ctx, `
+		SELECT `+deliveryColumns+`, w.user_id, w.url, w.secret_ciphertext
+		FROM webhook_deliveries d
+		JOIN outgoing_webhooks w ON w.id = d.webhook_id
+		WHERE d.id = $1
+	`, deliveryID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return t, err
+}
+
+func (r *WebhookRepo) RecordAttempt(ctx context.Context, id uuid.UUID, status domain.WebhookDeliveryStatus, attempt domain.WebhookAttempt, nextAttempt *time.Time) error {
+	var responseStatus *int
+	if attempt.ResponseStatus != 0 {
+		responseStatus = &attempt.ResponseStatus
+	}
+	var lastError *string
+	if attempt.Err != nil {
+		msg := attempt.Err.Error()
+		lastError = &msg
+	}
+	_, err := r.pool.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, next_attempt_at = $3,
+		    response_status = $4, last_error = $5, duration_ms = $6,
+		    delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() ELSE delivered_at END
+		WHERE id = $1
+	`, id, status, nextAttempt, responseStatus, lastError, int(attempt.Duration.Milliseconds()))
+	if err != nil {
+		return fmt.Errorf("failed to record delivery attempt: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, webhookID uuid.UUID, userID uuid.UUID, limit int) ([]domain.WebhookDelivery, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries d
+		JOIN outgoing_webhooks w ON w.id = d.webhook_id
+		WHERE d.webhook_id = $1 AND w.user_id = $2
+		ORDER BY d.created_at DESC
+		LIMIT $3
+	`, webhookID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		if err := scanDelivery(rows, &d); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *WebhookRepo) GetDelivery(ctx context.Context, id uuid.UUID, webhookID uuid.UUID, userID uuid.UUID) (*domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	err := scanDelivery(r.pool.QueryRow(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries d
+		JOIN outgoing_webhooks w ON w.id = d.webhook_id
+		WHERE d.id = $1 AND d.webhook_id = $2 AND w.user_id = $3
+	`, id, webhookID, userID), &d)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func scanDelivery(row pgx.Row, d *domain.WebhookDelivery, extra ...any) error {
+	dest := append([]any{
+		&d.ID, &d.WebhookID, &d.EventID, &d.Event, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.ResponseStatus, &d.LastError, &d.DurationMS, &d.CreatedAt, &d.DeliveredAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		return fmt.Errorf("failed to scan delivery: %w", err)
+	}
+	return nil
+}
+
+func scanTarget(row pgx.Row) (*domain.WebhookTarget, error) {
+	var t domain.WebhookTarget
+	if err := scanDelivery(row, &t.Delivery, &t.UserID, &t.URL, &t.SecretCiphertext); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
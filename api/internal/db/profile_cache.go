@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// CachedProfileRepository serves the singleton system profile from memory
+// for a short TTL: every app create, scale and deploy reads it.
+// 🛡️ Stability: UpdateProfile invalidates on any outcome, so a version
+// conflict is retried against the database, not the stale copy; the TTL
+// bounds staleness from other replicas.
+type CachedProfileRepository struct {
+	domain.SystemProfileRepository
+	ttl     time.Duration
+	mu      sync.Mutex
+	profile *domain.SystemProfile
+	expires time.Time
+}
+
+func NewCachedProfileRepository(inner domain.SystemProfileRepository, ttl time.Duration) *CachedProfileRepository {
+	return &CachedProfileRepository{SystemProfileRepository: inner, ttl: ttl}
+}
+
+func (r *CachedProfileRepository) GetActiveProfile(ctx context.Context) (*domain.SystemProfile, error) {
+	now := time.Now()
+	r.mu.Lock()
+	cached, expires := r.profile, r.expires
+	r.mu.Unlock()
+	if cached != nil && now.Before(expires) {
+		return cloneProfile(cached), nil
+	}
+
+	p, err := r.SystemProfileRepository.GetActiveProfile(ctx)
+	if err != nil {
+		return nil, err // Misses and errors are never cached
+	}
+	r.mu.Lock()
+	r.profile, r.expires = cloneProfile(p), now.Add(r.ttl)
+	r.mu.Unlock()
+	return p, nil
+}
+
+func (r *CachedProfileRepository) UpdateProfile(ctx context.Context, profile *domain.SystemProfile) error {
+	defer r.Invalidate()
+	return r.SystemProfileRepository.UpdateProfile(ctx, profile)
+}
+
+// Invalidate drops the cached profile.
+func (r *CachedProfileRepository) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.profile = nil
+}
+
+// cloneProfile gives each caller its own copy; patches edit the stack map in place.
+func cloneProfile(p *domain.SystemProfile) *domain.SystemProfile {
+	c := *p
+	c.DefaultStackRegistry = maps.Clone(p.DefaultStackRegistry)
+	return &c
+}
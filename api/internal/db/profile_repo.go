@@ -134,3 +134,47 @@ func (r *PostgresProfileRepository) UpdateProfile(ctx context.Context, profile *
 
 	return nil
 }
+
+// ListRollouts returns every agent's last known rollout state.
+func (r *PostgresProfileRepository) ListRollouts(ctx context.Context) ([]domain.ProfileRollout, error) {
+	const query = `
+		SELECT target, applied_version, applied_at, last_error, updated_at
+		FROM system_profile_rollouts
+		ORDER BY target;
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query profile rollouts: %w", err)
+	}
+	defer rows.Close()
+
+	var rollouts []domain.ProfileRollout
+	for rows.Next() {
+		var ro domain.ProfileRollout
+		if err := rows.Scan(&ro.Target, &ro.AppliedVersion, &ro.AppliedAt, &ro.LastError, &ro.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan profile rollout: %w", err)
+		}
+		rollouts = append(rollouts, ro)
+	}
+	return rollouts, rows.Err()
+}
+
+// RecordRollout upserts one agent's rollout state. A failed push (LastError
+// set) keeps the previously applied version and timestamp.
+func (r *PostgresProfileRepository) RecordRollout(ctx context.Context, ro domain.ProfileRollout) error {
+	const query = `
+		INSERT INTO system_profile_rollouts (target, applied_version, applied_at, last_error, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (target) DO UPDATE SET
+			applied_version = CASE WHEN $4 = '' THEN EXCLUDED.applied_version ELSE system_profile_rollouts.applied_version END,
+			applied_at      = CASE WHEN $4 = '' THEN EXCLUDED.applied_at ELSE system_profile_rollouts.applied_at END,
+			last_error      = EXCLUDED.last_error,
+			updated_at      = NOW();
+	`
+
+	if _, err := r.pool.Exec(ctx, query, ro.Target, ro.AppliedVersion, ro.AppliedAt, ro.LastError); err != nil {
+		return fmt.Errorf("failed to record profile rollout: %w", err)
+	}
+	return nil
+}
@@ -2,21 +2,57 @@ package http
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"kari/api/internal/core/domain"
 	"kari/api/proto/agent"
 )
 
 type HealthHandler struct {
 	agentClient agent.SystemAgentClient
+	maintenance domain.MaintenanceState
+	readiness   domain.ReadinessChecker
 }
 
-func NewHealthHandler(client agent.SystemAgentClient) *HealthHandler {
-	return &HealthHandler{agentClient: client}
+func NewHealthHandler(client agent.SystemAgentClient, maintenance domain.MaintenanceState, readiness domain.ReadinessChecker) *HealthHandler {
+	return &HealthHandler{agentClient: client, maintenance: maintenance, readiness: readiness}
+}
+
+// Live handles GET /health/live
+// 🛡️ SLA: Answers from the process alone; a failing dependency must never
+// get the Brain restarted, only taken out of rotation by /health/ready.
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	noCache(w)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, `{"status":"ok"}`)
+}
+
+// Ready handles GET /health/ready
+// 200 while ok or degraded, 503 once a critical component is down.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	noCache(w)
+	report := h.readiness.Ready(r.Context())
+
+	code := http.StatusOK
+	if report.Status == domain.HealthDown {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(report)
+}
+
+// 🛡️ Zero-Trust: Prevent upstream proxies from caching health status
+func noCache(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Expires", "0")
 }
 
 func (h *HealthHandler) Check(w http.ResponseWriter, r *http.Request) {
@@ -24,10 +60,13 @@ func (h *HealthHandler) Check(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
 	defer cancel()
 
-	// 🛡️ Zero-Trust: Prevent upstream proxies from caching health status
-	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
-	w.Header().Set("Pragma", "no-cache")
-	w.Header().Set("Expires", "0")
+	noCache(w)
+
+	// 🚧 Maintenance is reported, not failed: the panel still serves reads
+	maintenance := h.maintenance.Maintenance().Enabled
+	if maintenance {
+		w.Header().Set("X-Kari-Maintenance", "on")
+	}
 
 	// 📡 Perform the "Heartbeat" probe to the Muscle
 	_, err := h.agentClient.GetSystemStatus(ctx, &agent.Empty{})
@@ -57,5 +96,9 @@ func (h *HealthHandler) Check(w http.ResponseWriter, r *http.Request) {
 
 	// ✅ PASS: The Brain and Muscle are synchronized
 	w.WriteHeader(http.StatusOK)
+	if maintenance {
+		fmt.Fprint(w, "healthy: maintenance mode")
+		return
+	}
 	fmt.Fprint(w, "healthy")
 }
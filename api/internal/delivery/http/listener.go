@@ -0,0 +1,55 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+)
+
+// Listen opens the socket the API serves on: a TCP port, or a Unix socket
+// when a local reverse proxy is the only client. The Unix socket is created
+// under a restrictive umask and then given socketMode, so no connection is
+// accepted with looser permissions in between.
+func Listen(mode, port, socketPath, socketMode string) (net.Listener, error) {
+	switch mode {
+	case "", "tcp":
+		return net.Listen("tcp", ":"+port)
+	case "unix":
+	default:
+		return nil, fmt.Errorf("unknown listen mode %q (want tcp or unix)", mode)
+	}
+
+	perm, err := strconv.ParseUint(socketMode, 8, 32)
+	if err != nil || perm > 0o777 {
+		return nil, fmt.Errorf("invalid socket mode %q (want octal, e.g. 0660)", socketMode)
+	}
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+
+	// A socket left behind by a crash would make the bind fail; anything
+	// else at that path is not ours to delete
+	if fi, err := os.Lstat(socketPath); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", socketPath)
+		}
+		if err := os.Remove(socketPath); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	old := syscall.Umask(0o177)
+	ln, err := net.Listen("unix", socketPath)
+	syscall.Umask(old)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(socketPath, os.FileMode(perm)); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("failed to set socket mode: %w", err)
+	}
+	return ln, nil
+}
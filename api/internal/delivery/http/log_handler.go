@@ -1,6 +1,7 @@
 package http
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"kari/api/internal/telemetry"
@@ -44,7 +45,11 @@ func (h *LogHandler) StreamLogs(w http.ResponseWriter, r *http.Request) {
 			return
 		case logLine := <-logChan:
 			// 🛡️ Zero-Trust: Ensure no sensitive data is leaked in the log strings
-			fmt.Fprintf(w, "data: %s\n\n", logLine)
+			frame, err := json.Marshal(logLine)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", frame)
 			if err := rc.Flush(); err != nil {
 				return
 			}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"time"
 
+	"kari/api/internal/api/middleware"
+	"kari/api/internal/core/domain"
 	"kari/api/internal/core/services"
 )
 
@@ -34,7 +36,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	var req LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request payload", http.StatusBadRequest)
+		middleware.WriteError(w, r, http.StatusBadRequest, domain.CodeInvalidJSON, "invalid request payload")
 		return
 	}
 
@@ -42,7 +44,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	accessToken, refreshToken, err := h.authService.Login(r.Context(), req.Email, req.Password)
 	if err != nil {
 		// 🛡️ Information Obfuscation: Generic 401 prevents enumeration
-		http.Error(w, "invalid credentials", http.StatusUnauthorized)
+		middleware.WriteError(w, r, http.StatusUnauthorized, domain.CodeInvalidCredentials, "invalid credentials")
 		return
 	}
 
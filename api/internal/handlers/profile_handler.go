@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 
+	"kari/api/internal/api/middleware"
 	"kari/api/internal/core/domain"
 	"kari/api/internal/db"
 )
@@ -32,14 +33,14 @@ func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	profile, err := h.repo.GetActiveProfile(ctx)
 	if err != nil {
 		if errors.Is(err, db.ErrProfileNotFound) {
-			http.Error(w, "System profile not initialized", http.StatusNotFound)
+			middleware.WriteError(w, r, http.StatusNotFound, domain.CodeNotFound, "System profile not initialized")
 			return
 		}
 		
 		// 🛡️ Zero-Trust: Log the real error internally, but return a generic 500
 		// to prevent leaking database topology or SQL syntax to the caller.
 		log.Printf("ERROR: Failed to fetch profile: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		middleware.WriteError(w, r, http.StatusInternalServerError, domain.CodeInternal, "Internal server error")
 		return
 	}
 
@@ -58,7 +59,7 @@ func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	
 	// 2. SLA: Translate HTTP bytes to Domain Intent
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		middleware.WriteError(w, r, http.StatusBadRequest, domain.CodeInvalidJSON, "Invalid JSON payload")
 		return
 	}
 
@@ -71,16 +72,16 @@ func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		switch {
 		// The Optimistic Concurrency Control (OCC) Trap we built earlier
 		case errors.Is(err, db.ErrConcurrencyConflict):
-			http.Error(w, "Conflict: The profile was modified by another administrator. Please refresh and try again.", http.StatusConflict)
+			middleware.WriteError(w, r, http.StatusConflict, domain.CodeConflict, "Conflict: The profile was modified by another administrator. Please refresh and try again.")
 			
 		// Catch our strict Domain.Validate() errors (e.g., MaxMemory < 128)
 		case err.Error() != "" && contains(err.Error(), "domain validation failed"):
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			middleware.WriteError(w, r, http.StatusBadRequest, domain.CodeValidationFailed, err.Error())
 			
 		default:
 			// Generic fallback for actual database connection drops or panics
 			log.Printf("ERROR: Failed to update profile: %v", err)
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			middleware.WriteError(w, r, http.StatusInternalServerError, domain.CodeInternal, "Internal server error")
 		}
 		return
 	}
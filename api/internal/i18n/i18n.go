@@ -0,0 +1,140 @@
+// Package i18n localizes the error envelopes the Brain returns: messages,
+// field errors, and the title and message of classified agent errors.
+// Stored text (system alerts, deployment logs) is written once, in English.
+//
+// The catalogue is keyed by the English source text, so a handler keeps
+// writing plain English and never looks anything up itself; the error writer
+// translates on the way out. Text with no catalogue entry (dynamic messages,
+// new strings) falls back to English, which is always correct if not ideal.
+// Machine-readable codes never change, so the UI can still key off them.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Locale is a BCP 47 tag from Supported.
+type Locale string
+
+const (
+	English Locale = "en-US" // Source language; has no catalogue file
+	Turkish Locale = "tr-TR"
+)
+
+// Supported lists the locales the Brain can answer in, default first.
+var Supported = []Locale{English, Turkish}
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogues maps each non-source locale to English text → translation.
+var catalogues = loadCatalogues()
+
+func loadCatalogues() map[Locale]map[string]string {
+	out := make(map[Locale]map[string]string, len(Supported))
+	for _, loc := range Supported[1:] {
+		raw, err := localeFiles.ReadFile(path.Join("locales", string(loc)+".json"))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalogue for %s: %v", loc, err))
+		}
+		var entries map[string]string
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalogue for %s: %v", loc, err))
+		}
+		out[loc] = entries
+	}
+	return out
+}
+
+// Translate returns msg in loc, or msg itself when there is no translation.
+func Translate(loc Locale, msg string) string {
+	if t, ok := catalogues[loc][msg]; ok && t != "" {
+		return t
+	}
+	return msg
+}
+
+// Format translates a template and fills its {name} placeholders, e.g.
+// Format(loc, "{field} is required", "field", "email").
+func Format(loc Locale, template string, pairs ...string) string {
+	out := Translate(loc, template)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		out = strings.ReplaceAll(out, "{"+pairs[i]+"}", pairs[i+1])
+	}
+	return out
+}
+
+// ==============================================================================
+// Negotiation
+// ==============================================================================
+
+// Negotiate picks the best supported locale for an Accept-Language header.
+// A bare language ("tr") matches its regional tag; anything unsupported, or
+// an empty header, gets English.
+func Negotiate(acceptLanguage string) Locale {
+	best, bestQ := English, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, q := parseRange(part)
+		if tag == "" || q <= bestQ {
+			continue
+		}
+		if loc, ok := match(tag); ok {
+			best, bestQ = loc, q
+		}
+	}
+	return best
+}
+
+// parseRange splits "tr-TR;q=0.8" into its tag and weight (default 1).
+func parseRange(part string) (string, float64) {
+	tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+	q := 1.0
+	if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return "", 0
+		}
+		q = parsed
+	}
+	return strings.TrimSpace(tag), q
+}
+
+func match(tag string) (Locale, bool) {
+	lang, _, _ := strings.Cut(tag, "-")
+	for _, loc := range Supported {
+		if strings.EqualFold(tag, string(loc)) {
+			return loc, true
+		}
+	}
+	for _, loc := range Supported {
+		if l, _, _ := strings.Cut(string(loc), "-"); strings.EqualFold(lang, l) {
+			return loc, true
+		}
+	}
+	return "", false
+}
+
+// ==============================================================================
+// Request Context
+// ==============================================================================
+
+type localeKey struct{}
+
+// WithLocale returns ctx carrying the request's negotiated locale.
+func WithLocale(ctx context.Context, loc Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, loc)
+}
+
+// FromContext returns the request's locale, English outside a request.
+func FromContext(ctx context.Context) Locale {
+	if loc, ok := ctx.Value(localeKey{}).(Locale); ok {
+		return loc
+	}
+	return English
+}
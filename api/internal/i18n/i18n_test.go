@@ -0,0 +1,52 @@
+package i18n_test
+
+import (
+	"testing"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/i18n"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]i18n.Locale{
+		"":                           i18n.English,
+		"tr-TR":                      i18n.Turkish,
+		"tr":                         i18n.Turkish,
+		"TR-tr":                      i18n.Turkish,
+		"de-DE, tr;q=0.5":            i18n.Turkish,
+		"en-GB;q=0.9, tr-TR;q=0.8":   i18n.English,
+		"tr-TR;q=0.4, en;q=0.6":      i18n.English,
+		"fr-FR, de;q=0.9, *;q=0.1":   i18n.English,
+		"tr-TR;q=bogus, en-US;q=0.1": i18n.English,
+	}
+	for header, want := range cases {
+		if got := i18n.Negotiate(header); got != want {
+			t.Errorf("Negotiate(%q) = %s, want %s", header, got, want)
+		}
+	}
+}
+
+func TestFormatFillsTranslatedTemplate(t *testing.T) {
+	got := i18n.Format(i18n.Turkish, "{field} must be at most {param} characters", "field", "name", "param", "64")
+	if want := "name en fazla 64 karakter olmalıdır"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got := i18n.Translate(i18n.Turkish, "no such message"); got != "no such message" {
+		t.Fatalf("untranslated text should fall back to English, got %q", got)
+	}
+}
+
+// Every classified agent alert must read naturally in every locale.
+func TestCatalogueCoversAgentErrors(t *testing.T) {
+	raws := []string{"OOM", "exit code 1", "npm", "iptables", "tls", "EPERM", "useradd", "unreachable", "deadline", "???"}
+	for _, loc := range i18n.Supported[1:] {
+		for _, raw := range raws {
+			e := domain.ClassifyAgentError(raw)
+			for _, text := range []string{e.Title, e.Message} {
+				if i18n.Translate(loc, text) == text {
+					t.Errorf("%s: no translation for %s text %q", loc, e.Code, text)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package agentlink
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerCycle(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewCircuitBreaker(3, 10*time.Second)
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if b.Failure() {
+			t.Fatalf("breaker tripped after %d failures, threshold is 3", i+1)
+		}
+	}
+	if !b.Failure() {
+		t.Fatal("expected third failure to trip the breaker")
+	}
+	if b.Allow() {
+		t.Fatal("open breaker must reject calls during cooldown")
+	}
+
+	now = now.Add(10 * time.Second)
+	if !b.Allow() {
+		t.Fatal("expected a half-open probe after cooldown")
+	}
+	if b.Allow() {
+		t.Fatal("only one probe may be in flight while half-open")
+	}
+
+	// A failed probe re-opens immediately, regardless of threshold
+	if !b.Failure() {
+		t.Fatal("failed probe should re-open the breaker")
+	}
+	now = now.Add(10 * time.Second)
+	if !b.Allow() {
+		t.Fatal("expected another probe after the second cooldown")
+	}
+	b.Success()
+
+	if state, failures := b.State(); state != BreakerClosed || failures != 0 {
+		t.Fatalf("expected closed/0 after success, got %s/%d", state, failures)
+	}
+}
@@ -0,0 +1,252 @@
+// Package agentlink hardens the Brain's gRPC link to the Rust Muscle: it waits
+// out UDS reconnects with backoff, fails fast through a circuit breaker while
+// the agent is down, and tracks connection-state metrics.
+//
+// The link is installed as client interceptors on the shared *grpc.ClientConn,
+// so every SystemAgentClient built from that connection gets the same behavior.
+package agentlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+
+	"kari/api/internal/core/domain"
+)
+
+// ErrCircuitOpen is returned without dialing while the breaker is open.
+var ErrCircuitOpen = fmt.Errorf("%w: agent circuit breaker open", domain.ErrUnavailable)
+
+// Options tunes reconnection and breaker behavior.
+type Options struct {
+	FailureThreshold int           // Consecutive failures before the breaker opens
+	Cooldown         time.Duration // How long the breaker stays open before probing
+	ReconnectBudget  time.Duration // Max time a call waits for the UDS to come back
+	InitialBackoff   time.Duration
+	MaxBackoff       time.Duration
+}
+
+// DefaultOptions suits a local UDS: reconnects are fast, so waiting a few
+// seconds absorbs an agent restart without stalling request handlers.
+func DefaultOptions() Options {
+	return Options{
+		FailureThreshold: 5,
+		Cooldown:         30 * time.Second,
+		ReconnectBudget:  5 * time.Second,
+		InitialBackoff:   100 * time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+	}
+}
+
+// Link owns the breaker and metrics for one agent connection.
+type Link struct {
+	opts    Options
+	breaker *CircuitBreaker
+	logger  *slog.Logger
+
+	mu              sync.RWMutex
+	conn            *grpc.ClientConn
+	lastStateChange time.Time
+
+	calls      atomic.Uint64
+	failures   atomic.Uint64
+	rejected   atomic.Uint64
+	reconnects atomic.Uint64
+}
+
+func New(opts Options, logger *slog.Logger) *Link {
+	return &Link{
+		opts:    opts,
+		breaker: NewCircuitBreaker(opts.FailureThreshold, opts.Cooldown),
+		logger:  logger,
+	}
+}
+
+// Attach binds the dialed connection. Interceptors must be installed at dial
+// time, before the conn exists, hence the two-step setup.
+func (l *Link) Attach(conn *grpc.ClientConn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.conn = conn
+	l.lastStateChange = time.Now()
+}
+
+// ==============================================================================
+// 1. Interceptors
+// ==============================================================================
+
+// UnaryInterceptor guards every unary agent RPC.
+func (l *Link) UnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := l.before(ctx, method); err != nil {
+			return err
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		l.after(method, err)
+		return err
+	}
+}
+
+// StreamInterceptor guards stream establishment; mid-stream failures are the
+// caller's to handle (the deployment worker already reports them to the UI).
+func (l *Link) StreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if err := l.before(ctx, method); err != nil {
+			return nil, err
+		}
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		l.after(method, err)
+		return stream, err
+	}
+}
+
+func (l *Link) before(ctx context.Context, method string) error {
+	l.calls.Add(1)
+	if !l.breaker.Allow() {
+		l.rejected.Add(1)
+		return ErrCircuitOpen
+	}
+	// A not-yet-sent request is always safe to delay; wait out a UDS reconnect
+	// instead of surfacing a raw "connection refused".
+	if err := l.awaitReady(ctx); err != nil {
+		l.recordFailure(method, err)
+		return fmt.Errorf("%w: agent unreachable: %v", domain.ErrUnavailable, err)
+	}
+	return nil
+}
+
+func (l *Link) after(method string, err error) {
+	if isInfrastructureFailure(err) {
+		l.recordFailure(method, err)
+		return
+	}
+	// Application-level errors (InvalidArgument, PermissionDenied...) prove the
+	// agent is alive, so they close the breaker just like a success.
+	l.breaker.Success()
+}
+
+func (l *Link) recordFailure(method string, err error) {
+	l.failures.Add(1)
+	if l.breaker.Failure() {
+		l.logger.Error("🔌 Agent circuit breaker opened",
+			slog.String("method", method),
+			slog.Duration("cooldown", l.opts.Cooldown),
+			slog.Any("error", err))
+	}
+}
+
+// ==============================================================================
+// 2. Reconnection
+// ==============================================================================
+
+// awaitReady nudges an idle or failed connection and waits, with exponential
+// backoff between checks, until it is READY or the reconnect budget runs out.
+func (l *Link) awaitReady(ctx context.Context) error {
+	l.mu.RLock()
+	conn := l.conn
+	l.mu.RUnlock()
+	if conn == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, l.opts.ReconnectBudget)
+	defer cancel()
+
+	backoff := l.opts.InitialBackoff
+	for {
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Shutdown:
+			return errors.New("connection shut down")
+		case connectivity.Idle, connectivity.TransientFailure:
+			conn.Connect()
+		}
+
+		waitCtx, waitCancel := context.WithTimeout(ctx, backoff)
+		conn.WaitForStateChange(waitCtx, state)
+		waitCancel()
+		if ctx.Err() != nil {
+			return fmt.Errorf("not ready after %s (state %s)", l.opts.ReconnectBudget, conn.GetState())
+		}
+		backoff = min(backoff*2, l.opts.MaxBackoff)
+	}
+}
+
+// Watch logs connectivity transitions and counts reconnects until ctx ends.
+func (l *Link) Watch(ctx context.Context) {
+	l.mu.RLock()
+	conn := l.conn
+	l.mu.RUnlock()
+	if conn == nil {
+		return
+	}
+
+	state := conn.GetState()
+	wasReady := state == connectivity.Ready
+	for conn.WaitForStateChange(ctx, state) {
+		state = conn.GetState()
+
+		l.mu.Lock()
+		l.lastStateChange = time.Now()
+		l.mu.Unlock()
+
+		switch state {
+		case connectivity.Ready:
+			if wasReady {
+				l.reconnects.Add(1)
+				l.logger.Info("🔌 Agent link re-established")
+			}
+			wasReady = true
+		case connectivity.TransientFailure:
+			l.logger.Warn("🔌 Agent link lost; reconnecting")
+		}
+	}
+}
+
+// Stats returns the current link metrics.
+func (l *Link) Stats() domain.AgentLinkStats {
+	l.mu.RLock()
+	conn, changed := l.conn, l.lastStateChange
+	l.mu.RUnlock()
+
+	state, consecutive := l.breaker.State()
+	s := domain.AgentLinkStats{
+		ConnState:           "unattached",
+		Breaker:             string(state),
+		ConsecutiveFailures: consecutive,
+		Calls:               l.calls.Load(),
+		Failures:            l.failures.Load(),
+		Rejected:            l.rejected.Load(),
+		Reconnects:          l.reconnects.Load(),
+		LastStateChange:     changed,
+	}
+	if conn != nil {
+		s.ConnState = conn.GetState().String()
+	}
+	return s
+}
+
+// isInfrastructureFailure separates "the agent is unreachable or stuck" from
+// "the agent answered with an error". Only the former should trip the breaker.
+func isInfrastructureFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,184 @@
+package agentlink
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"kari/api/internal/core/domain"
+)
+
+// MethodPolicy is the default call behavior for one agent RPC.
+type MethodPolicy struct {
+	// Timeout applies only when the caller's context has no deadline of its own.
+	// Zero leaves the call unbounded (long-lived streams).
+	Timeout time.Duration
+	// Idempotent RPCs are retried on transient transport failures; everything
+	// else runs at most once so a half-applied mutation is never replayed.
+	Idempotent  bool
+	MaxAttempts int
+}
+
+const agentService = "/kari.agent.v1.SystemAgent/"
+
+// defaultPolicy covers RPCs added to the proto without a policy entry.
+var defaultPolicy = MethodPolicy{Timeout: 30 * time.Second, MaxAttempts: 1}
+
+// methodPolicies is the single source of truth for agent call budgets.
+var methodPolicies = map[string]MethodPolicy{
+	agentService + "GetSystemStatus":       {Timeout: 5 * time.Second, Idempotent: true, MaxAttempts: 3},
+	agentService + "GetCapabilities":       {Timeout: 10 * time.Second, Idempotent: true, MaxAttempts: 2},
+	agentService + "ExecutePackageCommand": {Timeout: 10 * time.Minute, MaxAttempts: 1},
+	agentService + "ProvisionAppJail":      {Timeout: 60 * time.Second, MaxAttempts: 1},
+	agentService + "ManageService":         {Timeout: 30 * time.Second, MaxAttempts: 1},
+	agentService + "GetProcessStatus":      {Timeout: 10 * time.Second, Idempotent: true, MaxAttempts: 3},
+	agentService + "RestartApp":            {Timeout: 20 * time.Minute, MaxAttempts: 1}, // Graceful: drain + health wait per instance
+	agentService + "StreamDeployment":      {Timeout: 0, MaxAttempts: 1},
+	agentService + "PullImage":             {Timeout: 15 * time.Minute, Idempotent: true, MaxAttempts: 2},
+	agentService + "ScanAppFiles":          {Timeout: 30 * time.Minute, MaxAttempts: 1},
+	agentService + "DeleteDeployment":      {Timeout: 60 * time.Second, Idempotent: true, MaxAttempts: 3},
+	agentService + "TeardownJail":          {Timeout: 60 * time.Second, Idempotent: true, MaxAttempts: 3},
+	agentService + "DeleteArtifacts":       {Timeout: 60 * time.Second, Idempotent: true, MaxAttempts: 3},
+	agentService + "ListManagedResources":  {Timeout: 30 * time.Second, Idempotent: true, MaxAttempts: 2},
+	agentService + "RemoveManagedResource": {Timeout: 30 * time.Second, Idempotent: true, MaxAttempts: 3},
+	agentService + "WriteSystemFile":       {Timeout: 15 * time.Second, Idempotent: true, MaxAttempts: 3},
+	agentService + "CollectAccessLogs":     {Timeout: 60 * time.Second, Idempotent: true, MaxAttempts: 2},
+	agentService + "SetBandwidthLimit":     {Timeout: 30 * time.Second, Idempotent: true, MaxAttempts: 3},
+	agentService + "ApplySecurityHeaders":  {Timeout: 30 * time.Second, Idempotent: true, MaxAttempts: 3},
+	agentService + "InstallCertificate":    {Timeout: 30 * time.Second, Idempotent: true, MaxAttempts: 3},
+	agentService + "ApplyFirewallPolicy":   {Timeout: 15 * time.Second, MaxAttempts: 1},
+	agentService + "ScheduleJob":           {Timeout: 15 * time.Second, Idempotent: true, MaxAttempts: 3},
+	agentService + "ApplyBrainUpdate":      {Timeout: 2 * time.Minute, MaxAttempts: 1},
+	agentService + "SealSecrets":           {Timeout: 30 * time.Second, Idempotent: true, MaxAttempts: 2},
+	agentService + "UnsealSecrets":         {Timeout: 30 * time.Second, Idempotent: true, MaxAttempts: 3},
+}
+
+// PolicyFor returns the policy for a full gRPC method name.
+func PolicyFor(method string) MethodPolicy {
+	if p, ok := methodPolicies[method]; ok {
+		return p
+	}
+	return defaultPolicy
+}
+
+// ==============================================================================
+// 1. Interceptors
+// ==============================================================================
+
+// PolicyUnaryInterceptor applies deadlines, idempotent retries and error
+// translation. Install it OUTSIDE the link interceptor so every retry attempt
+// is still subject to the circuit breaker.
+func PolicyUnaryInterceptor(opts Options) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		policy := PolicyFor(method)
+		ctx, cancel := withDefaultDeadline(ctx, policy.Timeout)
+		defer cancel()
+
+		backoff := opts.InitialBackoff
+		var err error
+		for attempt := 1; ; attempt++ {
+			err = invoker(ctx, method, req, reply, cc, callOpts...)
+			if err == nil || !policy.Idempotent || attempt >= policy.MaxAttempts || !isRetryable(err) {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return TranslateError(err)
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, opts.MaxBackoff)
+		}
+		return TranslateError(err)
+	}
+}
+
+// PolicyStreamInterceptor applies the default deadline and translates errors
+// from stream setup and every RecvMsg. Streams are never retried.
+func PolicyStreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		policy := PolicyFor(method)
+		ctx, cancel := withDefaultDeadline(ctx, policy.Timeout)
+
+		stream, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			cancel()
+			return nil, TranslateError(err)
+		}
+		return &translatingStream{ClientStream: stream, cancel: cancel}, nil
+	}
+}
+
+// translatingStream releases the deadline once the stream ends.
+type translatingStream struct {
+	grpc.ClientStream
+	cancel context.CancelFunc
+}
+
+func (s *translatingStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		return nil
+	}
+	s.cancel()
+	if errors.Is(err, io.EOF) {
+		return err // Normal end of stream; callers compare against io.EOF
+	}
+	return TranslateError(err)
+}
+
+// ==============================================================================
+// 2. Helpers
+// ==============================================================================
+
+// withDefaultDeadline respects a caller's request-scoped deadline and only
+// fills in the policy timeout when there is none.
+func withDefaultDeadline(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// isRetryable limits retries to "the agent never processed this": a refused
+// or reset transport. DeadlineExceeded is excluded because the call may have
+// run to completion on the Muscle side.
+func isRetryable(err error) bool {
+	if errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	return status.Code(err) == codes.Unavailable
+}
+
+// TranslateError converts gRPC failures into domain.AgentError so every
+// caller sees the same UI-safe codes. The raw error stays reachable via Unwrap.
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var agentErr domain.AgentError
+	if errors.As(err, &agentErr) || errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	// Transport-level codes map straight onto their catalogue entries
+	switch {
+	case errors.Is(err, domain.ErrUnavailable), status.Code(err) == codes.Unavailable:
+		agentErr = domain.ClassifyAgentError("unreachable")
+	case status.Code(err) == codes.DeadlineExceeded:
+		agentErr = domain.ClassifyAgentError("timeout")
+	default:
+		msg := err.Error()
+		if st, ok := status.FromError(err); ok {
+			msg = st.Message()
+		}
+		agentErr = domain.ClassifyAgentError(msg)
+	}
+	agentErr.Cause = err
+	return agentErr
+}
@@ -0,0 +1,110 @@
+package agentlink
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/infrastructure/agentpki"
+	"kari/api/internal/infrastructure/sshexec"
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+// RemotePool holds one mTLS connection per enrolled remote agent. Like the
+// local link, dials do not block: a down server fails its calls, not Client.
+// SSH servers get an in-process executor behind the same client interface.
+type RemotePool struct {
+	ca     *agentpki.CA
+	port   string
+	ssh    sshTargets // nil: SSH servers are unreachable
+	logger *slog.Logger
+
+	mu    sync.Mutex
+	conns map[uuid.UUID]*remoteConn
+}
+
+type remoteConn struct {
+	hostname    string
+	fingerprint string
+	conn        *grpc.ClientConn
+	close       func() error
+}
+
+// sshTargets is satisfied by sshexec.Credentials.
+type sshTargets interface {
+	Target(ctx context.Context, s domain.Server) (*sshexec.Target, error)
+}
+
+func NewRemotePool(ca *agentpki.CA, port string, logger *slog.Logger) *RemotePool {
+	return &RemotePool{ca: ca, port: port, logger: logger, conns: make(map[uuid.UUID]*remoteConn)}
+}
+
+// WithSSH lets the pool reach servers registered with the SSH executor.
+func (p *RemotePool) WithSSH(targets sshTargets) *RemotePool {
+	p.ssh = targets
+	return p
+}
+
+// Client returns a SystemAgentClient for s. A server that re-enrolled or
+// moved host gets a fresh connection.
+func (p *RemotePool) Client(s domain.Server) (agent.SystemAgentClient, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if rc, ok := p.conns[s.ID]; ok {
+		if rc.hostname == s.Hostname && rc.fingerprint == s.CertFingerprint {
+			return agent.NewSystemAgentClient(rc.conn), nil
+		}
+		rc.close()
+		delete(p.conns, s.ID)
+	}
+
+	if s.Executor == domain.ExecutorSSH {
+		return p.sshClient(s)
+	}
+
+	creds := credentials.NewTLS(p.ca.DialConfig(s.ID.String(), s.Hostname))
+	conn, err := grpc.Dial(net.JoinHostPort(s.Hostname, p.port), grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up link to server %s: %w", s.Name, err)
+	}
+	p.conns[s.ID] = &remoteConn{hostname: s.Hostname, fingerprint: s.CertFingerprint, conn: conn, close: conn.Close}
+	return agent.NewSystemAgentClient(conn), nil
+}
+
+// sshClient runs with p.mu held; loading the credential is a single query.
+func (p *RemotePool) sshClient(s domain.Server) (agent.SystemAgentClient, error) {
+	if p.ssh == nil {
+		return nil, fmt.Errorf("%w: SSH servers are not configured", domain.ErrUnavailable)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	target, err := p.ssh.Target(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	link, err := sshexec.Serve(sshexec.NewExecutor(*target, p.logger.With(slog.String("server", s.Name))))
+	if err != nil {
+		return nil, err
+	}
+	p.conns[s.ID] = &remoteConn{hostname: s.Hostname, conn: link.Conn(), close: link.Close}
+	return agent.NewSystemAgentClient(link.Conn()), nil
+}
+
+// Close tears down every connection; call on shutdown.
+func (p *RemotePool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, rc := range p.conns {
+		rc.close()
+		delete(p.conns, id)
+	}
+}
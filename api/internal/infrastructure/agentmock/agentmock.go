@@ -0,0 +1,127 @@
+// Package agentmock is a recording SystemAgentClient for tests. Client is
+// generated from agent.proto, so it always covers the whole contract; the
+// contract tests use its Recorder to pin the exact RPC sequence each service
+// emits, which is what the Muscle has to keep answering.
+package agentmock
+
+//go:generate go run ./gen -proto ../../../../proto/kari/agent/v1/agent.proto -out client_gen.go
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+// Call is one recorded RPC.
+type Call struct {
+	Method  string        // Full method name, e.g. agent.SystemAgent_StreamDeployment_FullMethodName
+	Request proto.Message // A copy, so later mutation by the caller does not rewrite history
+}
+
+// Recorder keeps calls in the order they were made. Safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *Recorder) record(method string, req proto.Message) {
+	r.mu.Lock()
+	r.calls = append(r.calls, Call{Method: method, Request: proto.Clone(req)})
+	r.mu.Unlock()
+}
+
+// Calls returns every recorded call.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Methods returns the short method names in call order ("StreamDeployment"),
+// the form contract expectations are written in.
+func (r *Recorder) Methods() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	methods := make([]string, len(r.calls))
+	for i, c := range r.calls {
+		methods[i] = path.Base(c.Method)
+	}
+	return methods
+}
+
+// Reset forgets every call.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.calls = nil
+	r.mu.Unlock()
+}
+
+// reply is the unscripted answer: empty, but a successful AgentResponse,
+// since services read Success=false as the Muscle refusing.
+func reply[T proto.Message](m T) T {
+	if resp, ok := any(m).(*agent.AgentResponse); ok {
+		resp.Success = true
+	}
+	return m
+}
+
+// convert copies src into dst through the wire format, which also bridges
+// the two import paths the generated agent package is reachable under.
+func convert(src, dst any) error {
+	in, ok := src.(proto.Message)
+	if !ok {
+		return fmt.Errorf("agentmock: %T is not a protobuf message", src)
+	}
+	out, ok := dst.(proto.Message)
+	if !ok {
+		return fmt.Errorf("agentmock: %T is not a protobuf message", dst)
+	}
+	raw, err := proto.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(raw, out)
+}
+
+// stream replays scripted messages, then io.EOF.
+type stream[T any] struct {
+	ctx  context.Context
+	msgs []*T
+}
+
+func newStream[T any](ctx context.Context, msgs []*T) *stream[T] {
+	return &stream[T]{ctx: ctx, msgs: msgs}
+}
+
+func (s *stream[T]) Recv() (*T, error) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(s.msgs) == 0 {
+		return nil, io.EOF
+	}
+	msg := s.msgs[0]
+	s.msgs = s.msgs[1:]
+	return msg, nil
+}
+
+func (s *stream[T]) Header() (metadata.MD, error) { return metadata.MD{}, nil }
+func (s *stream[T]) Trailer() metadata.MD         { return metadata.MD{} }
+func (s *stream[T]) CloseSend() error             { return nil }
+func (s *stream[T]) Context() context.Context     { return s.ctx }
+func (s *stream[T]) SendMsg(any) error            { return nil }
+
+func (s *stream[T]) RecvMsg(m any) error {
+	msg, err := s.Recv()
+	if err != nil {
+		return err
+	}
+	return convert(any(msg), m)
+}
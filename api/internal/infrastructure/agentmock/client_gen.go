@@ -0,0 +1,528 @@
+// Code generated by agentmock/gen from agent.proto. DO NOT EDIT.
+
+package agentmock
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+// Client is a SystemAgentClient that records every call. Set a method's
+// Func to script its answer; left nil, it answers with an empty response
+// (a successful one, for AgentResponse).
+type Client struct {
+	Recorder
+
+	GetSystemStatusFunc       func(ctx context.Context, in *agent.Empty) (*agent.SystemStatus, error)
+	GetCapabilitiesFunc       func(ctx context.Context, in *agent.Empty) (*agent.HostCapabilities, error)
+	ExecutePackageCommandFunc func(ctx context.Context, in *agent.PackageRequest) (*agent.AgentResponse, error)
+	ProvisionAppJailFunc      func(ctx context.Context, in *agent.ProvisionJailRequest) (*agent.AgentResponse, error)
+	ManageServiceFunc         func(ctx context.Context, in *agent.ServiceRequest) (*agent.AgentResponse, error)
+	GetProcessStatusFunc      func(ctx context.Context, in *agent.ProcessStatusRequest) (*agent.ProcessStatusResponse, error)
+	RestartAppFunc            func(ctx context.Context, in *agent.RestartRequest) (*agent.AgentResponse, error)
+	StreamDeploymentFunc      func(ctx context.Context, in *agent.DeployRequest) ([]*agent.LogChunk, error)
+	PullImageFunc             func(ctx context.Context, in *agent.ImagePullRequest) (*agent.AgentResponse, error)
+	ScanAppFilesFunc          func(ctx context.Context, in *agent.FileScanRequest) (*agent.FileScanResponse, error)
+	CollectAccessLogsFunc     func(ctx context.Context, in *agent.AccessLogRequest) (*agent.AccessLogResponse, error)
+	DeleteDeploymentFunc      func(ctx context.Context, in *agent.DeleteRequest) (*agent.AgentResponse, error)
+	TeardownJailFunc          func(ctx context.Context, in *agent.TeardownRequest) (*agent.AgentResponse, error)
+	DeleteArtifactsFunc       func(ctx context.Context, in *agent.DeleteArtifactsRequest) (*agent.AgentResponse, error)
+	ListManagedResourcesFunc  func(ctx context.Context, in *agent.Empty) (*agent.ResourceInventory, error)
+	RemoveManagedResourceFunc func(ctx context.Context, in *agent.RemoveResourceRequest) (*agent.AgentResponse, error)
+	WriteSystemFileFunc       func(ctx context.Context, in *agent.FileWriteRequest) (*agent.AgentResponse, error)
+	InstallCertificateFunc    func(ctx context.Context, in *agent.SslPayload) (*agent.AgentResponse, error)
+	ApplySecurityHeadersFunc  func(ctx context.Context, in *agent.SecurityHeadersRequest) (*agent.AgentResponse, error)
+	SetBandwidthLimitFunc     func(ctx context.Context, in *agent.BandwidthLimitRequest) (*agent.AgentResponse, error)
+	ApplyFirewallPolicyFunc   func(ctx context.Context, in *agent.FirewallPolicy) (*agent.AgentResponse, error)
+	ScheduleJobFunc           func(ctx context.Context, in *agent.JobIntent) (*agent.AgentResponse, error)
+	ApplySystemDefaultsFunc   func(ctx context.Context, in *agent.SystemDefaults) (*agent.AgentResponse, error)
+	SealSecretsFunc           func(ctx context.Context, in *agent.SecretBundle) (*agent.SealedSecrets, error)
+	UnsealSecretsFunc         func(ctx context.Context, in *agent.SealedSecrets) (*agent.SecretBundle, error)
+	ApplyBrainUpdateFunc      func(ctx context.Context, in *agent.BrainUpdateRequest) (*agent.AgentResponse, error)
+}
+
+var (
+	_ agent.SystemAgentClient  = (*Client)(nil)
+	_ grpc.ClientConnInterface = (*Client)(nil)
+)
+
+func (c *Client) GetSystemStatus(ctx context.Context, in *agent.Empty, _ ...grpc.CallOption) (*agent.SystemStatus, error) {
+	c.record(agent.SystemAgent_GetSystemStatus_FullMethodName, in)
+	if c.GetSystemStatusFunc != nil {
+		return c.GetSystemStatusFunc(ctx, in)
+	}
+	return reply(&agent.SystemStatus{}), nil
+}
+
+func (c *Client) GetCapabilities(ctx context.Context, in *agent.Empty, _ ...grpc.CallOption) (*agent.HostCapabilities, error) {
+	c.record(agent.SystemAgent_GetCapabilities_FullMethodName, in)
+	if c.GetCapabilitiesFunc != nil {
+		return c.GetCapabilitiesFunc(ctx, in)
+	}
+	return reply(&agent.HostCapabilities{}), nil
+}
+
+func (c *Client) ExecutePackageCommand(ctx context.Context, in *agent.PackageRequest, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_ExecutePackageCommand_FullMethodName, in)
+	if c.ExecutePackageCommandFunc != nil {
+		return c.ExecutePackageCommandFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) ProvisionAppJail(ctx context.Context, in *agent.ProvisionJailRequest, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_ProvisionAppJail_FullMethodName, in)
+	if c.ProvisionAppJailFunc != nil {
+		return c.ProvisionAppJailFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) ManageService(ctx context.Context, in *agent.ServiceRequest, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_ManageService_FullMethodName, in)
+	if c.ManageServiceFunc != nil {
+		return c.ManageServiceFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) GetProcessStatus(ctx context.Context, in *agent.ProcessStatusRequest, _ ...grpc.CallOption) (*agent.ProcessStatusResponse, error) {
+	c.record(agent.SystemAgent_GetProcessStatus_FullMethodName, in)
+	if c.GetProcessStatusFunc != nil {
+		return c.GetProcessStatusFunc(ctx, in)
+	}
+	return reply(&agent.ProcessStatusResponse{}), nil
+}
+
+func (c *Client) RestartApp(ctx context.Context, in *agent.RestartRequest, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_RestartApp_FullMethodName, in)
+	if c.RestartAppFunc != nil {
+		return c.RestartAppFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) StreamDeployment(ctx context.Context, in *agent.DeployRequest, _ ...grpc.CallOption) (agent.SystemAgent_StreamDeploymentClient, error) {
+	c.record(agent.SystemAgent_StreamDeployment_FullMethodName, in)
+	var msgs []*agent.LogChunk
+	if c.StreamDeploymentFunc != nil {
+		var err error
+		if msgs, err = c.StreamDeploymentFunc(ctx, in); err != nil {
+			return nil, err
+		}
+	}
+	return newStream(ctx, msgs), nil
+}
+
+func (c *Client) PullImage(ctx context.Context, in *agent.ImagePullRequest, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_PullImage_FullMethodName, in)
+	if c.PullImageFunc != nil {
+		return c.PullImageFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) ScanAppFiles(ctx context.Context, in *agent.FileScanRequest, _ ...grpc.CallOption) (*agent.FileScanResponse, error) {
+	c.record(agent.SystemAgent_ScanAppFiles_FullMethodName, in)
+	if c.ScanAppFilesFunc != nil {
+		return c.ScanAppFilesFunc(ctx, in)
+	}
+	return reply(&agent.FileScanResponse{}), nil
+}
+
+func (c *Client) CollectAccessLogs(ctx context.Context, in *agent.AccessLogRequest, _ ...grpc.CallOption) (*agent.AccessLogResponse, error) {
+	c.record(agent.SystemAgent_CollectAccessLogs_FullMethodName, in)
+	if c.CollectAccessLogsFunc != nil {
+		return c.CollectAccessLogsFunc(ctx, in)
+	}
+	return reply(&agent.AccessLogResponse{}), nil
+}
+
+func (c *Client) DeleteDeployment(ctx context.Context, in *agent.DeleteRequest, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_DeleteDeployment_FullMethodName, in)
+	if c.DeleteDeploymentFunc != nil {
+		return c.DeleteDeploymentFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) TeardownJail(ctx context.Context, in *agent.TeardownRequest, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_TeardownJail_FullMethodName, in)
+	if c.TeardownJailFunc != nil {
+		return c.TeardownJailFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) DeleteArtifacts(ctx context.Context, in *agent.DeleteArtifactsRequest, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_DeleteArtifacts_FullMethodName, in)
+	if c.DeleteArtifactsFunc != nil {
+		return c.DeleteArtifactsFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) ListManagedResources(ctx context.Context, in *agent.Empty, _ ...grpc.CallOption) (*agent.ResourceInventory, error) {
+	c.record(agent.SystemAgent_ListManagedResources_FullMethodName, in)
+	if c.ListManagedResourcesFunc != nil {
+		return c.ListManagedResourcesFunc(ctx, in)
+	}
+	return reply(&agent.ResourceInventory{}), nil
+}
+
+func (c *Client) RemoveManagedResource(ctx context.Context, in *agent.RemoveResourceRequest, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_RemoveManagedResource_FullMethodName, in)
+	if c.RemoveManagedResourceFunc != nil {
+		return c.RemoveManagedResourceFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) WriteSystemFile(ctx context.Context, in *agent.FileWriteRequest, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_WriteSystemFile_FullMethodName, in)
+	if c.WriteSystemFileFunc != nil {
+		return c.WriteSystemFileFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) InstallCertificate(ctx context.Context, in *agent.SslPayload, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_InstallCertificate_FullMethodName, in)
+	if c.InstallCertificateFunc != nil {
+		return c.InstallCertificateFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) ApplySecurityHeaders(ctx context.Context, in *agent.SecurityHeadersRequest, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_ApplySecurityHeaders_FullMethodName, in)
+	if c.ApplySecurityHeadersFunc != nil {
+		return c.ApplySecurityHeadersFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) SetBandwidthLimit(ctx context.Context, in *agent.BandwidthLimitRequest, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_SetBandwidthLimit_FullMethodName, in)
+	if c.SetBandwidthLimitFunc != nil {
+		return c.SetBandwidthLimitFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) ApplyFirewallPolicy(ctx context.Context, in *agent.FirewallPolicy, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_ApplyFirewallPolicy_FullMethodName, in)
+	if c.ApplyFirewallPolicyFunc != nil {
+		return c.ApplyFirewallPolicyFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) ScheduleJob(ctx context.Context, in *agent.JobIntent, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_ScheduleJob_FullMethodName, in)
+	if c.ScheduleJobFunc != nil {
+		return c.ScheduleJobFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) ApplySystemDefaults(ctx context.Context, in *agent.SystemDefaults, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_ApplySystemDefaults_FullMethodName, in)
+	if c.ApplySystemDefaultsFunc != nil {
+		return c.ApplySystemDefaultsFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+func (c *Client) SealSecrets(ctx context.Context, in *agent.SecretBundle, _ ...grpc.CallOption) (*agent.SealedSecrets, error) {
+	c.record(agent.SystemAgent_SealSecrets_FullMethodName, in)
+	if c.SealSecretsFunc != nil {
+		return c.SealSecretsFunc(ctx, in)
+	}
+	return reply(&agent.SealedSecrets{}), nil
+}
+
+func (c *Client) UnsealSecrets(ctx context.Context, in *agent.SealedSecrets, _ ...grpc.CallOption) (*agent.SecretBundle, error) {
+	c.record(agent.SystemAgent_UnsealSecrets_FullMethodName, in)
+	if c.UnsealSecretsFunc != nil {
+		return c.UnsealSecretsFunc(ctx, in)
+	}
+	return reply(&agent.SecretBundle{}), nil
+}
+
+func (c *Client) ApplyBrainUpdate(ctx context.Context, in *agent.BrainUpdateRequest, _ ...grpc.CallOption) (*agent.AgentResponse, error) {
+	c.record(agent.SystemAgent_ApplyBrainUpdate_FullMethodName, in)
+	if c.ApplyBrainUpdateFunc != nil {
+		return c.ApplyBrainUpdateFunc(ctx, in)
+	}
+	return reply(&agent.AgentResponse{}), nil
+}
+
+// Invoke serves unary RPCs by full method name, for callers that hold a
+// grpc.ClientConnInterface (the outbox). Messages cross as wire bytes, as
+// they would over the socket.
+func (c *Client) Invoke(ctx context.Context, method string, args, out any, _ ...grpc.CallOption) error {
+	switch method {
+	case agent.SystemAgent_GetSystemStatus_FullMethodName:
+		in := &agent.Empty{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.GetSystemStatus(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_GetCapabilities_FullMethodName:
+		in := &agent.Empty{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.GetCapabilities(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_ExecutePackageCommand_FullMethodName:
+		in := &agent.PackageRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.ExecutePackageCommand(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_ProvisionAppJail_FullMethodName:
+		in := &agent.ProvisionJailRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.ProvisionAppJail(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_ManageService_FullMethodName:
+		in := &agent.ServiceRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.ManageService(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_GetProcessStatus_FullMethodName:
+		in := &agent.ProcessStatusRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.GetProcessStatus(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_RestartApp_FullMethodName:
+		in := &agent.RestartRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.RestartApp(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_PullImage_FullMethodName:
+		in := &agent.ImagePullRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.PullImage(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_ScanAppFiles_FullMethodName:
+		in := &agent.FileScanRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.ScanAppFiles(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_CollectAccessLogs_FullMethodName:
+		in := &agent.AccessLogRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.CollectAccessLogs(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_DeleteDeployment_FullMethodName:
+		in := &agent.DeleteRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.DeleteDeployment(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_TeardownJail_FullMethodName:
+		in := &agent.TeardownRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.TeardownJail(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_DeleteArtifacts_FullMethodName:
+		in := &agent.DeleteArtifactsRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.DeleteArtifacts(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_ListManagedResources_FullMethodName:
+		in := &agent.Empty{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.ListManagedResources(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_RemoveManagedResource_FullMethodName:
+		in := &agent.RemoveResourceRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.RemoveManagedResource(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_WriteSystemFile_FullMethodName:
+		in := &agent.FileWriteRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.WriteSystemFile(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_InstallCertificate_FullMethodName:
+		in := &agent.SslPayload{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.InstallCertificate(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_ApplySecurityHeaders_FullMethodName:
+		in := &agent.SecurityHeadersRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.ApplySecurityHeaders(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_SetBandwidthLimit_FullMethodName:
+		in := &agent.BandwidthLimitRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.SetBandwidthLimit(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_ApplyFirewallPolicy_FullMethodName:
+		in := &agent.FirewallPolicy{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.ApplyFirewallPolicy(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_ScheduleJob_FullMethodName:
+		in := &agent.JobIntent{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.ScheduleJob(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_ApplySystemDefaults_FullMethodName:
+		in := &agent.SystemDefaults{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.ApplySystemDefaults(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_SealSecrets_FullMethodName:
+		in := &agent.SecretBundle{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.SealSecrets(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_UnsealSecrets_FullMethodName:
+		in := &agent.SealedSecrets{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.UnsealSecrets(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	case agent.SystemAgent_ApplyBrainUpdate_FullMethodName:
+		in := &agent.BrainUpdateRequest{}
+		if err := convert(args, in); err != nil {
+			return err
+		}
+		resp, err := c.ApplyBrainUpdate(ctx, in)
+		if err != nil {
+			return err
+		}
+		return convert(resp, out)
+	}
+	return status.Errorf(codes.Unimplemented, "agentmock: %s is not a unary SystemAgent RPC", method)
+}
+
+// NewStream is not served: call the streaming methods on Client directly.
+func (c *Client) NewStream(_ context.Context, _ *grpc.StreamDesc, method string, _ ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "agentmock: call %s on Client directly", method)
+}
@@ -0,0 +1,209 @@
+// Package agentpki is the private CA that issues remote Muscle agents their
+// long-term mTLS identity. It is separate from the panel's public ACME
+// certificate: only the Brain and enrolled agents ever trust it.
+package agentpki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	caValidity   = 10 * 365 * 24 * time.Hour
+	minRSABits   = 2048
+	clockSkewPad = 5 * time.Minute // Backdates NotBefore for agents with a slow clock
+)
+
+// Issued is a signed agent certificate.
+type Issued struct {
+	CertPEM     []byte
+	Serial      string // Hex
+	Fingerprint string // SHA-256 of the DER, hex
+	ExpiresAt   time.Time
+}
+
+// CA signs agent certificates with a key kept in dir (ca.pem and ca.key,
+// both 0600). The pair is generated on first start.
+type CA struct {
+	cert    *x509.Certificate
+	certPEM []byte
+	key     crypto.Signer
+
+	brainMu   sync.Mutex
+	brainCert *tls.Certificate // See DialConfig
+}
+
+func LoadOrCreate(dir string) (*CA, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create agent CA directory: %w", err)
+	}
+	certPath, keyPath := filepath.Join(dir, "ca.pem"), filepath.Join(dir, "ca.key")
+
+	certPEM, err := os.ReadFile(certPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return create(certPath, keyPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent CA: %w", err)
+	}
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent CA key: %w", err)
+	}
+
+	certBlock, _ := pem.Decode(certPEM)
+	keyBlock, _ := pem.Decode(keyPEM)
+	if certBlock == nil || keyBlock == nil {
+		return nil, errors.New("agent CA files are not PEM")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse agent CA: %w", err)
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse agent CA key: %w", err)
+	}
+	return &CA{cert: cert, certPEM: certPEM, key: key}, nil
+}
+
+func create(certPath, keyPath string) (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate agent CA key: %w", err)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "Kari Agent CA", Organization: []string{"Kari"}},
+		NotBefore:             now.Add(-clockSkewPad),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to self-sign agent CA: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Key first: a crash in between leaves no CA without its key
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := writeFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})); err != nil {
+		return nil, err
+	}
+	if err := writeFile(certPath, certPEM); err != nil {
+		return nil, err
+	}
+	return &CA{cert: cert, certPEM: certPEM, key: key}, nil
+}
+
+// CertPEM is the CA certificate agents verify the Brain against.
+func (c *CA) CertPEM() []byte { return c.certPEM }
+
+// CertHash is what an operator passes to the join command so the agent can
+// pin this CA before it trusts the enrollment response, as kubeadm does.
+func (c *CA) CertHash() string {
+	sum := sha256.Sum256(c.cert.Raw)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Sign issues a certificate for csr's key, usable for both ends of the
+// agent link. The identity comes from the arguments, never from the CSR's
+// subject or extensions.
+func (c *CA) Sign(csr *x509.CertificateRequest, commonName string, dnsNames []string, validity time.Duration) (*Issued, error) {
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("CSR signature is invalid: %w", err)
+	}
+	switch pub := csr.PublicKey.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA keys must be at least %d bits", minRSABits)
+		}
+	default:
+		return nil, errors.New("unsupported CSR key type")
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	notAfter := now.Add(validity)
+	if notAfter.After(c.cert.NotAfter) {
+		notAfter = c.cert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"Kari Agents"}},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-clockSkewPad),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, c.cert, csr.PublicKey, c.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign agent certificate: %w", err)
+	}
+
+	sum := sha256.Sum256(der)
+	return &Issued{
+		CertPEM:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Serial:      serial.Text(16),
+		Fingerprint: hex.EncodeToString(sum[:]),
+		ExpiresAt:   notAfter,
+	}, nil
+}
+
+// randomSerial returns a positive 128-bit serial (RFC 5280 allows 20 octets).
+func randomSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial: %w", err)
+	}
+	return serial.Add(serial, big.NewInt(1)), nil
+}
+
+// writeFile replaces path atomically with a 0600 file.
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
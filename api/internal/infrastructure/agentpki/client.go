@@ -0,0 +1,86 @@
+package agentpki
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BrainCommonName identifies the Brain's client certificate. Enrolled agents
+// hold client certificates from the same CA, so an agent must accept only
+// this CN from callers, or one agent could drive another.
+const BrainCommonName = "kari-brain"
+
+const (
+	brainCertValidity = 90 * 24 * time.Hour
+	brainCertRenew    = 30 * 24 * time.Hour
+)
+
+// DialConfig returns the TLS config for calling the agent enrolled as
+// serverID at hostname. Both ends authenticate: the Brain presents its
+// client certificate, and the agent's certificate must chain to this CA
+// and name serverID, so a re-pointed DNS record cannot substitute another
+// enrolled agent.
+func (c *CA) DialConfig(serverID, hostname string) *tls.Config {
+	roots := x509.NewCertPool()
+	roots.AddCert(c.cert)
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS13,
+		ServerName: hostname,
+		RootCAs:    roots,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return c.brainCertificate()
+		},
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 || cs.PeerCertificates[0].Subject.CommonName != serverID {
+				return errors.New("agent certificate does not belong to this server")
+			}
+			return nil
+		},
+	}
+}
+
+// brainCertificate is issued in memory and reissued well before it expires;
+// nothing but the CA needs to persist, since agents trust the CA and the CN.
+func (c *CA) brainCertificate() (*tls.Certificate, error) {
+	c.brainMu.Lock()
+	defer c.brainMu.Unlock()
+	if c.brainCert != nil && time.Until(c.brainCert.Leaf.NotAfter) > brainCertRenew {
+		return c.brainCert, nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate Brain client key: %w", err)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: BrainCommonName, Organization: []string{"Kari"}},
+		NotBefore:    now.Add(-clockSkewPad),
+		NotAfter:     now.Add(brainCertValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, c.cert, &key.PublicKey, c.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign Brain client certificate: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	c.brainCert = &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+	return c.brainCert, nil
+}
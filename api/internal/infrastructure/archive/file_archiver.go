@@ -0,0 +1,87 @@
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// FileArchiver writes expired rows as gzip-compressed JSON Lines files:
+//
+//	<baseDir>/<table>/<table>-<oldest row timestamp>-<unix nanos>.jsonl.gz
+//
+// One JSON object per line keeps archives greppable with zcat | jq.
+type FileArchiver struct {
+	baseDir string
+}
+
+// NewFileArchiver ensures the archive root exists with owner-only permissions.
+func NewFileArchiver(baseDir string) (*FileArchiver, error) {
+	// 🛡️ Privacy: Archived audit rows may contain IPs and user IDs
+	if err := os.MkdirAll(baseDir, 0o700); err != nil {
+		return nil, fmt.Errorf("archive: failed to create %s: %w", baseDir, err)
+	}
+	return &FileArchiver{baseDir: baseDir}, nil
+}
+
+// Archive writes the batch to a temp file, fsyncs, then atomically renames it.
+// 🛡️ Stability: A crash mid-write leaves only a *.tmp file, never a truncated
+// archive that the pruner believes is complete.
+func (a *FileArchiver) Archive(ctx context.Context, table string, rows []domain.ArchivedRow) (string, error) {
+	if len(rows) == 0 {
+		return "", nil
+	}
+
+	dir := filepath.Join(a.baseDir, table)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("archive: failed to create %s: %w", dir, err)
+	}
+
+	name := fmt.Sprintf("%s-%s-%d.jsonl.gz", table, rows[0].CreatedAt.UTC().Format("20060102T150405Z"), time.Now().UnixNano())
+	final := filepath.Join(dir, name)
+
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("archive: failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+
+	gz := gzip.NewWriter(tmp)
+	enc := json.NewEncoder(gz)
+	for _, row := range rows {
+		if err := ctx.Err(); err != nil {
+			tmp.Close()
+			return "", err
+		}
+		if err := enc.Encode(row); err != nil {
+			tmp.Close()
+			return "", fmt.Errorf("archive: failed to encode row %s: %w", row.ID, err)
+		}
+	}
+
+	if err := gz.Close(); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("archive: failed to flush gzip stream: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("archive: fsync failed: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("archive: close failed: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		return "", fmt.Errorf("archive: chmod failed: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), final); err != nil {
+		return "", fmt.Errorf("archive: rename failed: %w", err)
+	}
+
+	return final, nil
+}
@@ -0,0 +1,69 @@
+package breach
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultPwnedRangeURL is the Have I Been Pwned range API. Mirrors that
+// serve the same format can be configured instead.
+const DefaultPwnedRangeURL = "https://api.pwnedpasswords.com/range/"
+
+// PwnedChecker looks passwords up with k-anonymity: only the first five hex
+// characters of the SHA-1 hash leave the Brain, and the match happens locally.
+type PwnedChecker struct {
+	rangeURL string
+	client   *http.Client
+}
+
+func NewPwnedChecker(rangeURL string) *PwnedChecker {
+	if rangeURL == "" {
+		rangeURL = DefaultPwnedRangeURL
+	}
+	return &PwnedChecker{
+		rangeURL: strings.TrimSuffix(rangeURL, "/") + "/",
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Breached returns how often the password appears in the corpus (0 = never).
+func (c *PwnedChecker) Breached(ctx context.Context, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.rangeURL+prefix, nil)
+	if err != nil {
+		return 0, err
+	}
+	// Padding hides the real response size from a network observer
+	req.Header.Set("Add-Padding", "true")
+	req.Header.Set("User-Agent", "kari-brain/password-policy")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("breach corpus lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("breach corpus returned HTTP %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || candidate != suffix {
+			continue
+		}
+		var n int
+		fmt.Sscanf(count, "%d", &n)
+		return n, nil // Padded entries carry a count of 0
+	}
+	return 0, scanner.Err()
+}
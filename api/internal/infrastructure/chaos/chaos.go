@@ -0,0 +1,193 @@
+// Package chaos injects dependency failures so operators can watch alerts,
+// retries and the agent circuit breaker react before a real outage does it
+// for them. It is for development only: main refuses to boot with faults
+// configured unless KARI_ENV=development.
+//
+// Each fault hooks the same seam the real dependency uses (gRPC client
+// interceptors, the pgx pool, the ACME HTTP transport), so everything above
+// it sees exactly the errors an outage would produce.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxAgentHang bounds an injected agent timeout when the caller set no
+// deadline, so a fault never wedges a goroutine forever.
+const maxAgentHang = 30 * time.Second
+
+// ErrInjectedDBLoss is what a refused reconnect returns; queries on a
+// dropped connection fail with pgx's own network error instead.
+var ErrInjectedDBLoss = errors.New("chaos: injected database connection loss")
+
+// Faults says how often each dependency misbehaves. Percentages are per
+// call (agent RPC, pool acquire); zero disables that fault.
+type Faults struct {
+	AgentTimeoutPercent int           // Agent RPCs that hang until their deadline, then fail DeadlineExceeded
+	DBDropPercent       int           // Pool acquires whose connection is cut, and reconnects refused
+	ACMEDelay           time.Duration // Added before every request to the ACME directory
+}
+
+// Enabled reports whether any fault is configured.
+func (f Faults) Enabled() bool {
+	return f.AgentTimeoutPercent > 0 || f.DBDropPercent > 0 || f.ACMEDelay > 0
+}
+
+// Injector applies Faults and counts what it injected.
+type Injector struct {
+	faults Faults
+	logger *slog.Logger
+
+	agentTimeouts atomic.Uint64
+	dbDrops       atomic.Uint64
+	acmeDelays    atomic.Uint64
+
+	injectedDesc *prometheus.Desc
+}
+
+func New(faults Faults, logger *slog.Logger) *Injector {
+	return &Injector{
+		faults:       faults,
+		logger:       logger,
+		injectedDesc: prometheus.NewDesc("kari_chaos_injected_total", "Faults injected by the development chaos layer.", []string{"fault"}, nil),
+	}
+}
+
+func (i *Injector) roll(percent int) bool {
+	return percent > 0 && rand.IntN(100) < percent
+}
+
+// ==============================================================================
+// 1. Agent Timeouts
+// ==============================================================================
+
+// UnaryInterceptor must be the innermost agent interceptor, so the policy
+// retries and the agentlink breaker observe the injected timeouts.
+func (i *Injector) UnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if i.roll(i.faults.AgentTimeoutPercent) {
+			return i.hang(ctx, method)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamInterceptor fails stream establishment the same way.
+func (i *Injector) StreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if i.roll(i.faults.AgentTimeoutPercent) {
+			return nil, i.hang(ctx, method)
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// hang waits like a stuck agent would: until the caller's deadline.
+func (i *Injector) hang(ctx context.Context, method string) error {
+	i.agentTimeouts.Add(1)
+	i.logger.Debug("🧪 Chaos: Agent call timing out", slog.String("method", method))
+
+	timer := time.NewTimer(maxAgentHang)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+	return status.Error(codes.DeadlineExceeded, "chaos: injected agent timeout")
+}
+
+// ==============================================================================
+// 2. Database Connection Loss
+// ==============================================================================
+
+// ConfigurePool cuts the socket under some acquired connections, so the
+// caller's next query fails as if Postgres went away, and refuses some
+// redials, so the pool cannot simply replace them.
+func (i *Injector) ConfigurePool(config *pgxpool.Config) {
+	if i.faults.DBDropPercent <= 0 {
+		return
+	}
+
+	before := config.BeforeAcquire
+	config.BeforeAcquire = func(ctx context.Context, conn *pgx.Conn) bool {
+		if before != nil && !before(ctx, conn) {
+			return false
+		}
+		if i.roll(i.faults.DBDropPercent) {
+			i.dbDrops.Add(1)
+			i.logger.Debug("🧪 Chaos: Dropping database connection")
+			conn.PgConn().Conn().Close()
+		}
+		return true
+	}
+
+	dial := config.ConnConfig.DialFunc
+	if dial == nil {
+		dial = (&net.Dialer{KeepAlive: 5 * time.Minute}).DialContext
+	}
+	config.ConnConfig.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if i.roll(i.faults.DBDropPercent) {
+			i.dbDrops.Add(1)
+			return nil, ErrInjectedDBLoss
+		}
+		return dial(ctx, network, addr)
+	}
+}
+
+// ==============================================================================
+// 3. Slow ACME
+// ==============================================================================
+
+// WrapACMETransport delays every ACME request; a delay longer than the
+// client's timeout turns into the timeout error a stalled CA produces.
+func (i *Injector) WrapACMETransport(base http.RoundTripper) http.RoundTripper {
+	if i.faults.ACMEDelay <= 0 {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		i.acmeDelays.Add(1)
+		timer := time.NewTimer(i.faults.ACMEDelay)
+		defer timer.Stop()
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		return base.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// ==============================================================================
+// 4. Metrics
+// ==============================================================================
+
+func (i *Injector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- i.injectedDesc
+}
+
+func (i *Injector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(i.injectedDesc, prometheus.CounterValue, float64(i.agentTimeouts.Load()), "agent_timeout")
+	ch <- prometheus.MustNewConstMetric(i.injectedDesc, prometheus.CounterValue, float64(i.dbDrops.Load()), "db_drop")
+	ch <- prometheus.MustNewConstMetric(i.injectedDesc, prometheus.CounterValue, float64(i.acmeDelays.Load()), "acme_delay")
+}
@@ -0,0 +1,75 @@
+package chaos
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestAgentTimeoutWaitsForDeadline(t *testing.T) {
+	inj := New(Faults{AgentTimeoutPercent: 100}, discard)
+	invoked := false
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		invoked = true
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := inj.UnaryInterceptor()(ctx, "/kari.agent.v1.SystemAgent/GetSystemStatus", nil, nil, nil, invoker)
+
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("err = %v, want DeadlineExceeded", err)
+	}
+	if invoked {
+		t.Fatal("timed-out call reached the agent")
+	}
+	if waited := time.Since(start); waited < 50*time.Millisecond {
+		t.Fatalf("returned after %v, before the caller's deadline", waited)
+	}
+	if got := inj.agentTimeouts.Load(); got != 1 {
+		t.Fatalf("counted %d timeouts, want 1", got)
+	}
+}
+
+func TestZeroPercentPassesThrough(t *testing.T) {
+	inj := New(Faults{}, discard)
+	calls := 0
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		calls++
+		return nil
+	}
+	for range 100 {
+		if err := inj.UnaryInterceptor()(context.Background(), "/m", nil, nil, nil, invoker); err != nil {
+			t.Fatalf("err = %v", err)
+		}
+	}
+	if calls != 100 {
+		t.Fatalf("%d of 100 calls reached the agent", calls)
+	}
+}
+
+func TestACMEDelayHonorsCancellation(t *testing.T) {
+	inj := New(Faults{ACMEDelay: time.Hour}, discard)
+	rt := inj.WrapACMETransport(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		t.Fatal("request sent despite cancellation")
+		return nil, nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://acme.test/directory", nil)
+	if _, err := rt.RoundTrip(req); err != context.DeadlineExceeded {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,81 @@
+package chatops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// Discord component constants (https://discord.com/developers/docs/interactions/message-components)
+const (
+	discordActionRow     = 1
+	discordButton        = 2
+	discordStyleSuccess  = 3
+	discordStyleDanger   = 4
+	discordAPIBase       = "https://discord.com/api/v10"
+	discordMaxErrorBytes = 4 << 10
+)
+
+// DiscordBot posts approval requests as a bot user. Button clicks come back
+// through the application's interactions endpoint (/integrations/discord/interactions).
+type DiscordBot struct {
+	token  string
+	client *http.Client
+}
+
+func NewDiscordBot(token string) *DiscordBot {
+	return &DiscordBot{token: token, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (b *DiscordBot) RequestApproval(ctx context.Context, channelID string, a *domain.DeploymentApproval) error {
+	body, err := json.Marshal(map[string]any{
+		"content": fmt.Sprintf("🚦 Deployment of **%s** (`%s`) is waiting for approval.", a.DomainName, a.Branch),
+		"components": []any{map[string]any{
+			"type": discordActionRow,
+			"components": []any{
+				discordButtonOf("Approve", discordStyleSuccess, domain.ChatApproveAction+":"+a.ID.String()),
+				discordButtonOf("Reject", discordStyleDanger, domain.ChatRejectAction+":"+a.ID.String()),
+			},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint := discordAPIBase + "/channels/" + url.PathEscape(channelID) + "/messages"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bot "+b.token)
+	req.Header.Set("User-Agent", "DiscordBot (https://github.com/irgordon/Kari, 1) kari-brain")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("discord: create message failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, discordMaxErrorBytes))
+		return fmt.Errorf("discord: create message returned %s: %s", resp.Status, detail)
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return nil
+}
+
+func discordButtonOf(label string, style int, customID string) map[string]any {
+	return map[string]any{
+		"type":      discordButton,
+		"label":     label,
+		"style":     style,
+		"custom_id": customID,
+	}
+}
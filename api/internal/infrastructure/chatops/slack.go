@@ -0,0 +1,138 @@
+package chatops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// slackMessage is the JSON body of a response_url follow-up.
+type slackMessage struct {
+	ResponseType    string `json:"response_type"` // ephemeral | in_channel
+	Text            string `json:"text"`
+	ReplaceOriginal bool   `json:"replace_original,omitempty"`
+}
+
+// SlackResponder posts follow-ups to a slash command's response_url.
+type SlackResponder struct {
+	client *http.Client
+}
+
+func NewSlackResponder() *SlackResponder {
+	return &SlackResponder{client: &http.Client{
+		Timeout: 10 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+func (s *SlackResponder) Respond(ctx context.Context, responseURL string, reply domain.ChatReply) error {
+	// 🛡️ Zero-Trust: response_url arrives in the (signed) request body, but is
+	// still pinned to Slack's host so it can never point the Brain elsewhere
+	u, err := url.Parse(responseURL)
+	if err != nil || u.Scheme != "https" || u.Host != "hooks.slack.com" {
+		return fmt.Errorf("%w: unexpected Slack response_url", domain.ErrValidation)
+	}
+
+	msg := slackMessage{ResponseType: "in_channel", Text: reply.Text, ReplaceOriginal: reply.Replace}
+	if reply.Ephemeral {
+		msg.ResponseType = "ephemeral"
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack: follow-up failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("slack: follow-up returned %s", resp.Status)
+	}
+	return nil
+}
+
+// ==============================================================================
+// Bot: Approval Requests
+// ==============================================================================
+
+// SlackBot posts approval requests with chat.postMessage. Button clicks come
+// back through the app's interactivity URL (/integrations/slack/interactions).
+type SlackBot struct {
+	token  string
+	client *http.Client
+}
+
+func NewSlackBot(token string) *SlackBot {
+	return &SlackBot{token: token, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (b *SlackBot) RequestApproval(ctx context.Context, channelID string, a *domain.DeploymentApproval) error {
+	text := fmt.Sprintf("🚦 Deployment of *%s* (`%s`) is waiting for approval.", a.DomainName, a.Branch)
+	body, err := json.Marshal(map[string]any{
+		"channel": channelID,
+		"text":    text,
+		"blocks": []any{
+			map[string]any{"type": "section", "text": map[string]string{"type": "mrkdwn", "text": text}},
+			map[string]any{"type": "actions", "elements": []any{
+				slackButton("Approve", "primary", domain.ChatApproveAction, a.ID.String()),
+				slackButton("Reject", "danger", domain.ChatRejectAction, a.ID.String()),
+			}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://slack.com/api/chat.postMessage", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+b.token)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack: chat.postMessage failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Slack answers 200 with ok=false for API-level errors
+	var out struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&out); err != nil {
+		return fmt.Errorf("slack: unreadable response (%s)", resp.Status)
+	}
+	if !out.OK {
+		return fmt.Errorf("slack: chat.postMessage: %s", out.Error)
+	}
+	return nil
+}
+
+func slackButton(label, style, actionID, value string) map[string]any {
+	return map[string]any{
+		"type":      "button",
+		"text":      map[string]string{"type": "plain_text", "text": label},
+		"style":     style,
+		"action_id": actionID,
+		"value":     value,
+	}
+}
@@ -0,0 +1,526 @@
+// Package fakeagent is an in-memory stand-in for the Muscle, for demos and
+// end-to-end tests on a laptop: no Rust agent, no root, nothing written to
+// the host. It implements the agent's gRPC service and keeps just enough
+// state (jails, units, vhosts, certificates, artifacts) for the panel to
+// show what it asked for.
+package fakeagent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"kari/api/internal/version"
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+// Version is reported as the agent version of the demo agent.
+const Version = "demo"
+
+// KeyBinding is what demo "sealed" secrets are bound to: nothing. They are
+// only base64 encoded, which is fine for a laptop and nothing else.
+const KeyBinding = "demo"
+
+// How long each scripted build step takes, so the live log looks alive.
+const buildStepDelay = 400 * time.Millisecond
+
+const webProcess = "web"
+
+type unit struct {
+	active bool
+	since  time.Time
+	memory uint64
+}
+
+// Agent serves the agent API from memory. Safe for concurrent use.
+type Agent struct {
+	agent.UnimplementedSystemAgentServer
+
+	started time.Time
+
+	mu        sync.Mutex
+	jails     map[string]string // app ID -> domain
+	units     map[string]*unit  // kari-{domain}, kari-{domain}_{process}
+	vhosts    map[string]bool
+	certs     map[string]bool
+	files     map[string][]byte
+	baselines map[string]bool                           // app IDs with a file scan baseline
+	artifacts map[string]map[string]*agent.ArtifactInfo // app ID -> artifact ID
+	logBytes  map[string]uint64                         // domain -> synthetic access log size
+}
+
+func New() *Agent {
+	return &Agent{
+		started:   time.Now(),
+		jails:     map[string]string{},
+		units:     map[string]*unit{},
+		vhosts:    map[string]bool{},
+		certs:     map[string]bool{},
+		files:     map[string][]byte{},
+		baselines: map[string]bool{},
+		artifacts: map[string]map[string]*agent.ArtifactInfo{},
+		logBytes:  map[string]uint64{},
+	}
+}
+
+// ==============================================================================
+// 1. Health
+// ==============================================================================
+
+func (a *Agent) GetSystemStatus(_ context.Context, _ *agent.Empty) (*agent.SystemStatus, error) {
+	a.mu.Lock()
+	jails := len(a.jails)
+	var memory uint64
+	for _, u := range a.units {
+		if u.active {
+			memory += u.memory
+		}
+	}
+	a.mu.Unlock()
+
+	return &agent.SystemStatus{
+		Healthy:            true,
+		ActiveJails:        uint32(jails),
+		CpuUsagePercent:    float32(3 + rand.Intn(12)),
+		MemoryUsageMb:      float32(512 + memory>>20),
+		AgentVersion:       Version,
+		UptimeSeconds:      uint64(time.Since(a.started).Seconds()),
+		ProtocolVersion:    version.AgentProtocolMax, // Every feature, so every screen can be shown
+		MinProtocolVersion: version.AgentProtocolMin,
+	}, nil
+}
+
+func (a *Agent) GetCapabilities(_ context.Context, _ *agent.Empty) (*agent.HostCapabilities, error) {
+	return &agent.HostCapabilities{
+		ContainerRuntime: "podman",
+		PhpVersions:      []string{"8.2", "8.3"},
+		DiskQuotas:       true,
+		Firewall:         "nftables",
+	}, nil
+}
+
+// ==============================================================================
+// 2. Apps & Processes
+// ==============================================================================
+
+func (a *Agent) ExecutePackageCommand(_ context.Context, req *agent.PackageRequest) (*agent.AgentResponse, error) {
+	return ok("demo: skipped %s %s", req.Command, strings.Join(req.Args, " ")), nil
+}
+
+func (a *Agent) ProvisionAppJail(_ context.Context, req *agent.ProvisionJailRequest) (*agent.AgentResponse, error) {
+	if req.AppId == "" || req.DomainName == "" {
+		return nil, status.Error(codes.InvalidArgument, "app_id and domain_name are required")
+	}
+	a.mu.Lock()
+	a.jails[req.AppId] = req.DomainName
+	a.mu.Unlock()
+	return ok("demo: jail kari-app-%s ready", req.AppId), nil
+}
+
+func (a *Agent) ManageService(_ context.Context, req *agent.ServiceRequest) (*agent.AgentResponse, error) {
+	name := strings.TrimSuffix(req.ServiceName, ".service")
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	switch req.Action {
+	case agent.ServiceAction_START, agent.ServiceAction_RESTART, agent.ServiceAction_ENABLE:
+		a.startLocked(name)
+	case agent.ServiceAction_STOP, agent.ServiceAction_DISABLE:
+		if u, exists := a.units[name]; exists {
+			u.active = false
+		}
+	case agent.ServiceAction_RELOAD:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown service action %v", req.Action)
+	}
+	return ok("demo: %s %s", strings.ToLower(req.Action.String()), name), nil
+}
+
+func (a *Agent) GetProcessStatus(_ context.Context, req *agent.ProcessStatusRequest) (*agent.ProcessStatusResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	resp := &agent.ProcessStatusResponse{}
+	for _, name := range req.Names {
+		instances := uint32(1)
+		if name == webProcess {
+			instances = max(1, req.WebInstances)
+		}
+		for i := uint32(0); i < instances; i++ {
+			unitProcess := name
+			if i > 0 {
+				unitProcess = fmt.Sprintf("%s-%d", webProcess, i+1)
+			}
+			state := &agent.ProcessState{Name: name, ActiveState: "inactive", SubState: "dead"}
+			if u, exists := a.units[unitName(req.DomainName, unitProcess)]; exists && u.active {
+				state.ActiveState, state.SubState = "active", "running"
+				state.MemoryBytes = u.memory + uint64(rand.Intn(8<<20))
+				state.ActiveSinceUnix = u.since.Unix()
+				// Roughly 2% of a core since start
+				state.CpuUsageNsec = uint64(time.Since(u.since) / 50)
+			}
+			if name == webProcess && req.BasePort > 0 {
+				state.Instance = i + 1
+				state.Port = req.BasePort + i
+				state.Healthy = state.ActiveState == "active"
+			}
+			resp.Processes = append(resp.Processes, state)
+		}
+	}
+	return resp, nil
+}
+
+func (a *Agent) RestartApp(_ context.Context, req *agent.RestartRequest) (*agent.AgentResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, name := range req.Names {
+		instances := uint32(1)
+		if name == webProcess {
+			instances = max(1, req.WebInstances)
+		}
+		for i := uint32(0); i < instances; i++ {
+			unitProcess := name
+			if i > 0 {
+				unitProcess = fmt.Sprintf("%s-%d", webProcess, i+1)
+			}
+			a.startLocked(unitName(req.DomainName, unitProcess))
+		}
+	}
+	return ok("demo: restarted %s", strings.Join(req.Names, ", ")), nil
+}
+
+// ==============================================================================
+// 3. Builds & Artifacts
+// ==============================================================================
+
+// StreamDeployment plays a scripted build log, then "starts" the app's units.
+func (a *Agent) StreamDeployment(req *agent.DeployRequest, stream agent.SystemAgent_StreamDeploymentServer) error {
+	if req.AppId == "" || req.DomainName == "" {
+		return status.Error(codes.InvalidArgument, "app_id and domain_name are required")
+	}
+	ctx := stream.Context()
+	send := func(chunk *agent.LogChunk) error {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-time.After(buildStepDelay):
+		}
+		chunk.TraceId = req.TraceId
+		return stream.Send(chunk)
+	}
+	say := func(format string, args ...any) error {
+		return send(&agent.LogChunk{Content: fmt.Sprintf(format, args...) + "\n"})
+	}
+
+	runtime := req.Runtime
+	if runtime == "" {
+		runtime = "static"
+	}
+	if req.ReuseArtifact != nil {
+		if err := say("📦 [demo] Unpacking stored release %s", req.ReuseArtifact.Id); err != nil {
+			return err
+		}
+	} else {
+		branch := req.Branch
+		if branch == "" {
+			branch = "main"
+		}
+		steps := []string{
+			fmt.Sprintf("🔗 [demo] Cloning %s (%s)", req.RepoUrl, branch),
+			fmt.Sprintf("🧰 [demo] Preparing %s %s toolchain", runtime, req.RuntimeVersion),
+		}
+		if req.BuildCommand != "" {
+			steps = append(steps, "$ "+req.BuildCommand, "✔ [demo] Build finished")
+		}
+		for _, step := range steps {
+			if err := say("%s", step); err != nil {
+				return err
+			}
+		}
+		if err := send(&agent.LogChunk{Content: "🧾 [demo] Cataloged 2 dependencies\n", Sbom: demoSBOM(runtime)}); err != nil {
+			return err
+		}
+	}
+
+	if req.ArtifactId != "" {
+		sum := sha256.Sum256([]byte(req.AppId + "/" + req.ArtifactId))
+		info := &agent.ArtifactInfo{Id: req.ArtifactId, Sha256: hex.EncodeToString(sum[:]), SizeBytes: uint64(1<<20 + rand.Intn(4<<20))}
+		a.mu.Lock()
+		if a.artifacts[req.AppId] == nil {
+			a.artifacts[req.AppId] = map[string]*agent.ArtifactInfo{}
+		}
+		a.artifacts[req.AppId][req.ArtifactId] = info
+		a.mu.Unlock()
+		if err := send(&agent.LogChunk{Content: "📦 [demo] Release archived\n", Artifact: info}); err != nil {
+			return err
+		}
+	}
+
+	processes := []string{webProcess}
+	if len(req.Processes) > 0 {
+		processes = processes[:0]
+		for _, p := range req.Processes {
+			processes = append(processes, p.Name)
+		}
+	}
+	a.mu.Lock()
+	a.jails[req.AppId] = req.DomainName
+	a.vhosts[req.DomainName] = true
+	for _, p := range processes {
+		a.startLocked(unitName(req.DomainName, p))
+		if p == webProcess {
+			for i := uint32(2); i <= req.Instances; i++ {
+				a.startLocked(unitName(req.DomainName, fmt.Sprintf("%s-%d", webProcess, i)))
+			}
+		}
+	}
+	a.mu.Unlock()
+	return say("🚀 [demo] %s is live (%s)", req.DomainName, strings.Join(processes, ", "))
+}
+
+func (a *Agent) PullImage(_ context.Context, req *agent.ImagePullRequest) (*agent.AgentResponse, error) {
+	if req.Image == "" {
+		return nil, status.Error(codes.InvalidArgument, "image is required")
+	}
+	return ok("demo: pulled %s", req.Image), nil
+}
+
+func (a *Agent) DeleteArtifacts(_ context.Context, req *agent.DeleteArtifactsRequest) (*agent.AgentResponse, error) {
+	a.mu.Lock()
+	for _, id := range req.ArtifactIds {
+		delete(a.artifacts[req.AppId], id)
+	}
+	a.mu.Unlock()
+	return ok("demo: deleted %d artifacts", len(req.ArtifactIds)), nil
+}
+
+// ==============================================================================
+// 4. Scans & Access Logs
+// ==============================================================================
+
+// ScanAppFiles always finds a clean tree; the first scan creates the baseline.
+func (a *Agent) ScanAppFiles(_ context.Context, req *agent.FileScanRequest) (*agent.FileScanResponse, error) {
+	a.mu.Lock()
+	created := req.Rebaseline || !a.baselines[req.AppId]
+	a.baselines[req.AppId] = true
+	a.mu.Unlock()
+	return &agent.FileScanResponse{FilesScanned: uint64(120 + rand.Intn(400)), BaselineCreated: created}, nil
+}
+
+// CollectAccessLogs invents the traffic since the previous call, so the
+// analytics charts fill in while the demo runs.
+func (a *Agent) CollectAccessLogs(_ context.Context, req *agent.AccessLogRequest) (*agent.AccessLogResponse, error) {
+	a.mu.Lock()
+	size := a.logBytes[req.DomainName] + uint64(2000+rand.Intn(20000))
+	a.logBytes[req.DomainName] = size
+	a.mu.Unlock()
+
+	if req.Offset > size {
+		return &agent.AccessLogResponse{NextOffset: size, Rotated: true}, nil
+	}
+	requests := (size - req.Offset) / 200
+	if requests == 0 {
+		return &agent.AccessLogResponse{NextOffset: size}, nil
+	}
+	failed := requests / 50
+	return &agent.AccessLogResponse{
+		NextOffset: size,
+		Buckets: []*agent.AccessLogBucket{{
+			HourUnix:      time.Now().UTC().Truncate(time.Hour).Unix(),
+			Requests:      requests,
+			BytesSent:     requests * 4096,
+			BytesReceived: requests * 512,
+			StatusClasses: map[string]uint64{"2xx": requests - failed, "4xx": failed},
+			Paths:         map[string]uint64{"/": requests / 2, "/api/items": requests / 3, "/login": requests / 10},
+			Clients:       map[string]uint64{"203.0.113.0/24": requests / 2, "198.51.100.0/24": requests / 4},
+		}},
+	}, nil
+}
+
+// ==============================================================================
+// 5. Teardown & Drift
+// ==============================================================================
+
+func (a *Agent) DeleteDeployment(_ context.Context, req *agent.DeleteRequest) (*agent.AgentResponse, error) {
+	a.mu.Lock()
+	for name := range a.units {
+		if name == unitName(req.DomainName, webProcess) || strings.HasPrefix(name, unitName(req.DomainName, "")) {
+			delete(a.units, name)
+		}
+	}
+	delete(a.vhosts, req.DomainName)
+	delete(a.certs, req.DomainName)
+	a.mu.Unlock()
+	return ok("demo: removed %s", req.DomainName), nil
+}
+
+func (a *Agent) TeardownJail(_ context.Context, req *agent.TeardownRequest) (*agent.AgentResponse, error) {
+	a.mu.Lock()
+	delete(a.jails, req.AppId)
+	delete(a.baselines, req.AppId)
+	delete(a.artifacts, req.AppId)
+	a.mu.Unlock()
+	return ok("demo: jail kari-app-%s removed", req.AppId), nil
+}
+
+func (a *Agent) ListManagedResources(_ context.Context, _ *agent.Empty) (*agent.ResourceInventory, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	inv := &agent.ResourceInventory{}
+	for name := range a.units {
+		inv.Units = append(inv.Units, name)
+	}
+	for domain := range a.vhosts {
+		inv.Vhosts = append(inv.Vhosts, domain)
+	}
+	for appID := range a.jails {
+		inv.JailUsers = append(inv.JailUsers, "kari-app-"+appID)
+	}
+	for domain := range a.certs {
+		inv.Certificates = append(inv.Certificates, domain)
+	}
+	sort.Strings(inv.Units)
+	sort.Strings(inv.Vhosts)
+	sort.Strings(inv.JailUsers)
+	sort.Strings(inv.Certificates)
+	return inv, nil
+}
+
+func (a *Agent) RemoveManagedResource(_ context.Context, req *agent.RemoveResourceRequest) (*agent.AgentResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	switch req.Kind {
+	case agent.ResourceKind_UNIT:
+		delete(a.units, req.Name)
+	case agent.ResourceKind_VHOST:
+		delete(a.vhosts, req.Name)
+	case agent.ResourceKind_JAIL_USER:
+		delete(a.jails, strings.TrimPrefix(req.Name, "kari-app-"))
+	case agent.ResourceKind_CERTIFICATE:
+		delete(a.certs, req.Name)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown resource kind %v", req.Kind)
+	}
+	return ok("demo: removed %s %s", strings.ToLower(req.Kind.String()), req.Name), nil
+}
+
+// ==============================================================================
+// 6. Files, Certificates & Policy
+// ==============================================================================
+
+func (a *Agent) WriteSystemFile(_ context.Context, req *agent.FileWriteRequest) (*agent.AgentResponse, error) {
+	if !path.IsAbs(req.AbsolutePath) || strings.Contains(req.AbsolutePath, "..") {
+		return nil, status.Error(codes.InvalidArgument, "path traversal detected")
+	}
+	a.mu.Lock()
+	a.files[path.Clean(req.AbsolutePath)] = req.Content
+	a.mu.Unlock()
+	return ok("demo: wrote %d bytes to %s", len(req.Content), req.AbsolutePath), nil
+}
+
+func (a *Agent) InstallCertificate(_ context.Context, req *agent.SslPayload) (*agent.AgentResponse, error) {
+	a.mu.Lock()
+	a.certs[req.DomainName] = true
+	a.mu.Unlock()
+	return ok("demo: certificate installed for %s", req.DomainName), nil
+}
+
+func (a *Agent) ApplySecurityHeaders(_ context.Context, req *agent.SecurityHeadersRequest) (*agent.AgentResponse, error) {
+	return ok("demo: %d headers applied to %s", len(req.Headers), req.DomainName), nil
+}
+
+func (a *Agent) SetBandwidthLimit(_ context.Context, req *agent.BandwidthLimitRequest) (*agent.AgentResponse, error) {
+	return ok("demo: %s limited to %d KiB/s", req.DomainName, req.RateKbps), nil
+}
+
+func (a *Agent) ApplyFirewallPolicy(_ context.Context, req *agent.FirewallPolicy) (*agent.AgentResponse, error) {
+	return ok("demo: %s port %d/%s", req.Action, req.Port, req.Protocol), nil
+}
+
+func (a *Agent) ScheduleJob(_ context.Context, req *agent.JobIntent) (*agent.AgentResponse, error) {
+	return ok("demo: scheduled %s (%s)", req.JobName, req.ScheduleExpression), nil
+}
+
+func (a *Agent) ApplySystemDefaults(_ context.Context, req *agent.SystemDefaults) (*agent.AgentResponse, error) {
+	return ok("demo: system profile v%d applied", req.ProfileVersion), nil
+}
+
+// ApplyBrainUpdate is refused: there is no binary to swap on a laptop.
+func (a *Agent) ApplyBrainUpdate(_ context.Context, req *agent.BrainUpdateRequest) (*agent.AgentResponse, error) {
+	return &agent.AgentResponse{
+		ExitCode:     1,
+		ErrorMessage: fmt.Sprintf("demo agent cannot install Brain %s", req.Version),
+	}, nil
+}
+
+// ==============================================================================
+// 7. Secrets
+// ==============================================================================
+
+// SealSecrets only encodes: demo secrets are bound to nothing.
+func (a *Agent) SealSecrets(_ context.Context, req *agent.SecretBundle) (*agent.SealedSecrets, error) {
+	sealed := &agent.SealedSecrets{Values: make(map[string]string, len(req.Values)), KeyBinding: KeyBinding}
+	for key, value := range req.Values {
+		sealed.Values[key] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+	return sealed, nil
+}
+
+func (a *Agent) UnsealSecrets(_ context.Context, req *agent.SealedSecrets) (*agent.SecretBundle, error) {
+	plain := &agent.SecretBundle{Values: make(map[string]string, len(req.Values))}
+	for key, value := range req.Values {
+		raw, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s was not sealed by the demo agent", key)
+		}
+		plain.Values[key] = string(raw)
+	}
+	return plain, nil
+}
+
+// ==============================================================================
+// 8. Helpers
+// ==============================================================================
+
+// startLocked marks a unit running, as a fresh start would. Callers hold mu.
+func (a *Agent) startLocked(name string) {
+	u, exists := a.units[name]
+	if !exists {
+		u = &unit{memory: uint64(48+rand.Intn(160)) << 20}
+		a.units[name] = u
+	}
+	u.active = true
+	u.since = time.Now()
+}
+
+// unitName mirrors the Muscle: "web" keeps kari-{domain}, the rest get a suffix.
+func unitName(domain, process string) string {
+	if process == webProcess {
+		return "kari-" + domain
+	}
+	return "kari-" + domain + "_" + process
+}
+
+func demoSBOM(runtime string) *agent.SbomReport {
+	return &agent.SbomReport{
+		Generator: "demo",
+		Components: []*agent.SbomComponent{
+			{Name: "left-pad", Version: "1.3.0", Purl: "pkg:npm/left-pad@1.3.0", Licenses: []string{"WTFPL"}},
+			{Name: runtime + "-demo-runtime", Version: "1.0.0", Purl: "pkg:generic/" + runtime + "-demo-runtime@1.0.0", Licenses: []string{"MIT"}},
+		},
+	}
+}
+
+func ok(format string, args ...any) *agent.AgentResponse {
+	return &agent.AgentResponse{Success: true, Stdout: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,42 @@
+package fakeagent
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+// Link serves an Agent on an in-memory listener, so the Brain reaches it
+// through the same generated client (and interceptors) as a real Muscle.
+type Link struct {
+	server *grpc.Server
+	lis    *bufconn.Listener
+}
+
+func Serve(a *Agent) *Link {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	agent.RegisterSystemAgentServer(server, a)
+	go server.Serve(lis)
+	return &Link{server: server, lis: lis}
+}
+
+// Dialer replaces the Unix socket dialer in grpc.WithContextDialer.
+func (l *Link) Dialer() func(ctx context.Context, _ string) (net.Conn, error) {
+	return func(ctx context.Context, _ string) (net.Conn, error) {
+		conn, err := l.lis.DialContext(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("demo agent: %w", err)
+		}
+		return conn, nil
+	}
+}
+
+func (l *Link) Close() {
+	l.server.Stop()
+}
@@ -0,0 +1,153 @@
+package gitprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"kari/api/internal/core/domain"
+)
+
+const githubAPI = "https://api.github.com"
+
+// GitHub talks to github.com through an OAuth App.
+type GitHub struct {
+	clientID     string
+	clientSecret string
+	client       *http.Client
+}
+
+func NewGitHub(clientID, clientSecret string) *GitHub {
+	return &GitHub{clientID: clientID, clientSecret: clientSecret, client: newHTTPClient()}
+}
+
+func (g *GitHub) Provider() domain.GitProvider { return domain.GitProviderGitHub }
+
+func (g *GitHub) AuthorizeURL(state, redirectURI string) string {
+	q := url.Values{
+		"client_id":    {g.clientID},
+		"redirect_uri": {redirectURI},
+		// repo: list/clone private repos, add deploy keys; admin:repo_hook: push webhooks
+		"scope": {"repo admin:repo_hook"},
+		"state": {state},
+	}
+	return "https://github.com/login/oauth/authorize?" + q.Encode()
+}
+
+func (g *GitHub) Exchange(ctx context.Context, code, redirectURI string) (*domain.GitToken, error) {
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+	err := postForm(ctx, g.client, "https://github.com/login/oauth/access_token", url.Values{
+		"client_id":     {g.clientID},
+		"client_secret": {g.clientSecret},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	// GitHub reports OAuth failures as 200 with an error field
+	if resp.Error != "" || resp.AccessToken == "" {
+		return nil, fmt.Errorf("%w: github oauth exchange failed: %s", domain.ErrInvalidCredentials, resp.Error)
+	}
+	return &domain.GitToken{AccessToken: resp.AccessToken}, nil
+}
+
+func (g *GitHub) Refresh(context.Context, string) (*domain.GitToken, error) {
+	return nil, errors.New("gitprovider: github oauth app tokens do not expire")
+}
+
+func (g *GitHub) AccountLogin(ctx context.Context, token string) (string, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := doJSON(ctx, g.client, http.MethodGet, githubAPI+"/user", bearer(token), nil, &user); err != nil {
+		return "", err
+	}
+	return user.Login, nil
+}
+
+func (g *GitHub) ListRepos(ctx context.Context, token string) ([]domain.GitRepo, error) {
+	var repos []struct {
+		FullName      string `json:"full_name"`
+		SSHURL        string `json:"ssh_url"`
+		DefaultBranch string `json:"default_branch"`
+		Private       bool   `json:"private"`
+	}
+	endpoint := githubAPI + "/user/repos?per_page=100&sort=updated&affiliation=owner,collaborator,organization_member"
+	if err := doJSON(ctx, g.client, http.MethodGet, endpoint, bearer(token), nil, &repos); err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.GitRepo, 0, len(repos))
+	for _, r := range repos {
+		out = append(out, domain.GitRepo{FullName: r.FullName, SSHURL: r.SSHURL, DefaultBranch: r.DefaultBranch, Private: r.Private})
+	}
+	return out, nil
+}
+
+func (g *GitHub) ListBranches(ctx context.Context, token, repo string) ([]domain.GitBranch, error) {
+	path, err := githubRepoPath(repo)
+	if err != nil {
+		return nil, err
+	}
+	var branches []struct {
+		Name   string `json:"name"`
+		Commit struct {
+			SHA string `json:"sha"`
+		} `json:"commit"`
+	}
+	if err := doJSON(ctx, g.client, http.MethodGet, githubAPI+path+"/branches?per_page=100", bearer(token), nil, &branches); err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.GitBranch, 0, len(branches))
+	for _, b := range branches {
+		out = append(out, domain.GitBranch{Name: b.Name, CommitSHA: b.Commit.SHA})
+	}
+	return out, nil
+}
+
+func (g *GitHub) AddDeployKey(ctx context.Context, token, repo, title, publicKey string) error {
+	path, err := githubRepoPath(repo)
+	if err != nil {
+		return err
+	}
+	body := map[string]any{"title": title, "key": publicKey, "read_only": true}
+	return doJSON(ctx, g.client, http.MethodPost, githubAPI+path+"/keys", bearer(token), body, nil)
+}
+
+func (g *GitHub) AddPushWebhook(ctx context.Context, token, repo, hookURL, secret string) error {
+	path, err := githubRepoPath(repo)
+	if err != nil {
+		return err
+	}
+	body := map[string]any{
+		"name":   "web",
+		"active": true,
+		"events": []string{"push"},
+		"config": map[string]string{
+			"url":          hookURL,
+			"content_type": "json",
+			"secret":       secret, // Verified via X-Hub-Signature-256
+			"insecure_ssl": "0",
+		},
+	}
+	return doJSON(ctx, g.client, http.MethodPost, githubAPI+path+"/hooks", bearer(token), body, nil)
+}
+
+// githubRepoPath validates owner/name so user input never rewrites the API path.
+func githubRepoPath(repo string) (string, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") || strings.Contains(repo, "..") {
+		return "", fmt.Errorf("%w: repository must be owner/name", domain.ErrValidation)
+	}
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(name), nil
+}
+
+func bearer(token string) string { return "Bearer " + token }
@@ -0,0 +1,165 @@
+package gitprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"kari/api/internal/core/domain"
+)
+
+// GitLab talks to gitlab.com or a self-managed instance through an OAuth application.
+type GitLab struct {
+	baseURL      string
+	clientID     string
+	clientSecret string
+	client       *http.Client
+}
+
+func NewGitLab(baseURL, clientID, clientSecret string) *GitLab {
+	return &GitLab{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		client:       newHTTPClient(),
+	}
+}
+
+func (g *GitLab) Provider() domain.GitProvider { return domain.GitProviderGitLab }
+
+func (g *GitLab) AuthorizeURL(state, redirectURI string) string {
+	q := url.Values{
+		"client_id":     {g.clientID},
+		"redirect_uri":  {redirectURI},
+		"response_type": {"code"},
+		"scope":         {"api"}, // Needed for deploy keys and project hooks
+		"state":         {state},
+	}
+	return g.baseURL + "/oauth/authorize?" + q.Encode()
+}
+
+type gitlabTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+func (g *GitLab) Exchange(ctx context.Context, code, redirectURI string) (*domain.GitToken, error) {
+	return g.token(ctx, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {redirectURI},
+	})
+}
+
+// Refresh trades a refresh token for a new pair; GitLab access tokens last 2h.
+func (g *GitLab) Refresh(ctx context.Context, refreshToken string) (*domain.GitToken, error) {
+	return g.token(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+func (g *GitLab) token(ctx context.Context, form url.Values) (*domain.GitToken, error) {
+	form.Set("client_id", g.clientID)
+	form.Set("client_secret", g.clientSecret)
+
+	var resp gitlabTokenResponse
+	if err := postForm(ctx, g.client, g.baseURL+"/oauth/token", form, &resp); err != nil {
+		return nil, fmt.Errorf("%w: gitlab oauth token request failed: %v", domain.ErrInvalidCredentials, err)
+	}
+	return &domain.GitToken{
+		AccessToken:  resp.AccessToken,
+		RefreshToken: resp.RefreshToken,
+		ExpiresAt:    expiresAt(resp.ExpiresIn),
+	}, nil
+}
+
+func (g *GitLab) AccountLogin(ctx context.Context, token string) (string, error) {
+	var user struct {
+		Username string `json:"username"`
+	}
+	if err := doJSON(ctx, g.client, http.MethodGet, g.baseURL+"/api/v4/user", bearer(token), nil, &user); err != nil {
+		return "", err
+	}
+	return user.Username, nil
+}
+
+func (g *GitLab) ListRepos(ctx context.Context, token string) ([]domain.GitRepo, error) {
+	var projects []struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+		SSHURL            string `json:"ssh_url_to_repo"`
+		DefaultBranch     string `json:"default_branch"`
+		Visibility        string `json:"visibility"`
+	}
+	endpoint := g.baseURL + "/api/v4/projects?membership=true&per_page=100&order_by=last_activity_at&min_access_level=40"
+	if err := doJSON(ctx, g.client, http.MethodGet, endpoint, bearer(token), nil, &projects); err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.GitRepo, 0, len(projects))
+	for _, p := range projects {
+		out = append(out, domain.GitRepo{
+			FullName:      p.PathWithNamespace,
+			SSHURL:        p.SSHURL,
+			DefaultBranch: p.DefaultBranch,
+			Private:       p.Visibility != "public",
+		})
+	}
+	return out, nil
+}
+
+func (g *GitLab) ListBranches(ctx context.Context, token, repo string) ([]domain.GitBranch, error) {
+	project, err := g.projectPath(repo)
+	if err != nil {
+		return nil, err
+	}
+	var branches []struct {
+		Name   string `json:"name"`
+		Commit struct {
+			ID string `json:"id"`
+		} `json:"commit"`
+	}
+	if err := doJSON(ctx, g.client, http.MethodGet, project+"/repository/branches?per_page=100", bearer(token), nil, &branches); err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.GitBranch, 0, len(branches))
+	for _, b := range branches {
+		out = append(out, domain.GitBranch{Name: b.Name, CommitSHA: b.Commit.ID})
+	}
+	return out, nil
+}
+
+func (g *GitLab) AddDeployKey(ctx context.Context, token, repo, title, publicKey string) error {
+	project, err := g.projectPath(repo)
+	if err != nil {
+		return err
+	}
+	body := map[string]any{"title": title, "key": publicKey, "can_push": false}
+	return doJSON(ctx, g.client, http.MethodPost, project+"/deploy_keys", bearer(token), body, nil)
+}
+
+func (g *GitLab) AddPushWebhook(ctx context.Context, token, repo, hookURL, secret string) error {
+	project, err := g.projectPath(repo)
+	if err != nil {
+		return err
+	}
+	body := map[string]any{
+		"url":                     hookURL,
+		"push_events":             true,
+		"token":                   secret, // Echoed back as X-Gitlab-Token
+		"enable_ssl_verification": true,
+	}
+	return doJSON(ctx, g.client, http.MethodPost, project+"/hooks", bearer(token), body, nil)
+}
+
+// projectPath addresses a project by its URL-encoded namespace path.
+func (g *GitLab) projectPath(repo string) (string, error) {
+	if !strings.Contains(repo, "/") || strings.Contains(repo, "..") || strings.HasPrefix(repo, "/") {
+		return "", fmt.Errorf("%w: repository must be namespace/project", domain.ErrValidation)
+	}
+	return g.baseURL + "/api/v4/projects/" + url.PathEscape(repo), nil
+}
@@ -0,0 +1,93 @@
+// Package gitprovider implements domain.GitProviderClient for GitHub and GitLab.
+package gitprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const maxResponseBytes = 4 << 20
+
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: 15 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse // Never forward tokens across redirects
+		},
+	}
+}
+
+// doJSON sends body (if any) as JSON and decodes a 2xx response into out.
+func doJSON(ctx context.Context, client *http.Client, method, endpoint, authHeader string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("gitprovider: failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("gitprovider: invalid request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "kari-brain/git-integration")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+	return send(client, req, out)
+}
+
+// postForm is used for OAuth token endpoints, which expect form encoding.
+func postForm(ctx context.Context, client *http.Client, endpoint string, form url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("gitprovider: invalid request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return send(client, req, out)
+}
+
+func send(client *http.Client, req *http.Request, out any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("gitprovider: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body := io.LimitReader(resp.Body, maxResponseBytes)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		io.Copy(io.Discard, body)
+		return fmt.Errorf("gitprovider: %s %s returned HTTP %d", req.Method, req.URL.Path, resp.StatusCode)
+	}
+	if out == nil {
+		io.Copy(io.Discard, body)
+		return nil
+	}
+	if err := json.NewDecoder(body).Decode(out); err != nil {
+		return fmt.Errorf("gitprovider: invalid response: %w", err)
+	}
+	return nil
+}
+
+// expiresAt converts an OAuth expires_in into an absolute time (nil = never).
+func expiresAt(seconds int64) *time.Time {
+	if seconds <= 0 {
+		return nil
+	}
+	t := time.Now().Add(time.Duration(seconds) * time.Second)
+	return &t
+}
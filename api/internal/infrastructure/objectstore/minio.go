@@ -0,0 +1,187 @@
+// Package objectstore implements domain.ObjectStorageProvider on MinIO.
+package objectstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"kari/api/internal/core/domain"
+)
+
+type MinIO struct {
+	s3             *minio.Client
+	admin          *madmin.AdminClient
+	publicEndpoint string
+	region         string
+}
+
+// NewMinIO connects with the operator's root (or admin-policy) credentials.
+// publicEndpoint is what apps are told to use; it defaults to endpoint.
+func NewMinIO(endpoint, accessKey, secretKey string, useTLS bool, publicEndpoint, region string) (*MinIO, error) {
+	s3, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useTLS,
+		Region: region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("objectstore: invalid MinIO endpoint: %w", err)
+	}
+	admin, err := madmin.New(endpoint, accessKey, secretKey, useTLS)
+	if err != nil {
+		return nil, fmt.Errorf("objectstore: invalid MinIO admin endpoint: %w", err)
+	}
+
+	if publicEndpoint == "" {
+		scheme := "http"
+		if useTLS {
+			scheme = "https"
+		}
+		publicEndpoint = scheme + "://" + endpoint
+	}
+	return &MinIO{s3: s3, admin: admin, publicEndpoint: publicEndpoint, region: region}, nil
+}
+
+func (m *MinIO) Endpoint() string { return m.publicEndpoint }
+func (m *MinIO) Region() string   { return m.region }
+
+// CreateBucket provisions bucket -> policy -> user -> quota, undoing the
+// earlier steps if a later one fails so no half-scoped user is left behind.
+func (m *MinIO) CreateBucket(ctx context.Context, bucket, accessKey, secretKey string, quotaBytes int64) (err error) {
+	var undo []func()
+	defer func() {
+		if err != nil {
+			for i := len(undo) - 1; i >= 0; i-- {
+				undo[i]()
+			}
+		}
+	}()
+	cleanup := context.WithoutCancel(ctx)
+
+	if err := m.s3.MakeBucket(ctx, bucket, minio.MakeBucketOptions{Region: m.region}); err != nil {
+		return fmt.Errorf("objectstore: failed to create bucket: %w", err)
+	}
+	undo = append(undo, func() {
+		m.s3.RemoveBucketWithOptions(cleanup, bucket, minio.RemoveBucketOptions{ForceDelete: true})
+	})
+
+	// 🛡️ Tenant Isolation: The policy names exactly one bucket
+	policy, _ := json.Marshal(map[string]any{
+		"Version": "2012-10-17",
+		"Statement": []map[string]any{{
+			"Effect":   "Allow",
+			"Action":   []string{"s3:*"},
+			"Resource": []string{"arn:aws:s3:::" + bucket, "arn:aws:s3:::" + bucket + "/*"},
+		}},
+	})
+	if err := m.admin.AddCannedPolicy(ctx, policyName(bucket), policy); err != nil {
+		return fmt.Errorf("objectstore: failed to create bucket policy: %w", err)
+	}
+	undo = append(undo, func() { m.admin.RemoveCannedPolicy(cleanup, policyName(bucket)) })
+
+	if err := m.admin.AddUser(ctx, accessKey, secretKey); err != nil {
+		return fmt.Errorf("objectstore: failed to create bucket user: %w", err)
+	}
+	undo = append(undo, func() { m.admin.RemoveUser(cleanup, accessKey) })
+
+	if _, err := m.admin.AttachPolicy(ctx, madmin.PolicyAssociationReq{
+		Policies: []string{policyName(bucket)},
+		User:     accessKey,
+	}); err != nil {
+		return fmt.Errorf("objectstore: failed to scope bucket user: %w", err)
+	}
+
+	return m.SetQuota(ctx, bucket, quotaBytes)
+}
+
+func (m *MinIO) SetQuota(ctx context.Context, bucket string, quotaBytes int64) error {
+	if quotaBytes <= 0 {
+		return fmt.Errorf("%w: bucket quota must be positive", domain.ErrValidation)
+	}
+	err := m.admin.SetBucketQuota(ctx, bucket, &madmin.BucketQuota{
+		Size: uint64(quotaBytes),
+		Type: madmin.HardQuota,
+	})
+	if err != nil {
+		return fmt.Errorf("objectstore: failed to set bucket quota: %w", err)
+	}
+	return nil
+}
+
+// Usage reads MinIO's background data-usage scan, which lags writes by minutes.
+func (m *MinIO) Usage(ctx context.Context, bucket string) (uint64, uint64, bool, error) {
+	info, err := m.admin.DataUsageInfo(ctx)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("objectstore: failed to read usage: %w", err)
+	}
+	u, ok := info.BucketsUsage[bucket]
+	if !ok {
+		return 0, 0, false, nil
+	}
+	return u.Size, u.ObjectsCount, true, nil
+}
+
+func (m *MinIO) DeleteBucket(ctx context.Context, bucket, accessKey string) error {
+	var errs []error
+	if err := m.admin.RemoveUser(ctx, accessKey); err != nil {
+		errs = append(errs, fmt.Errorf("remove user: %w", err))
+	}
+	if err := m.admin.RemoveCannedPolicy(ctx, policyName(bucket)); err != nil {
+		errs = append(errs, fmt.Errorf("remove policy: %w", err))
+	}
+	if err := m.s3.RemoveBucketWithOptions(ctx, bucket, minio.RemoveBucketOptions{ForceDelete: true}); err != nil {
+		errs = append(errs, fmt.Errorf("remove bucket: %w", err))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("objectstore: failed to delete bucket %s: %w", bucket, errors.Join(errs...))
+	}
+	return nil
+}
+
+// ==============================================================================
+// Platform Blobs (domain.BlobStore)
+// ==============================================================================
+
+// PutObject uploads into a platform-owned bucket, creating it on first use.
+// These buckets get no tenant user or policy; only the Brain can reach them.
+func (m *MinIO) PutObject(ctx context.Context, bucket, key string, r io.Reader, size int64, contentType string) error {
+	exists, err := m.s3.BucketExists(ctx, bucket)
+	if err != nil {
+		return fmt.Errorf("objectstore: failed to check bucket: %w", err)
+	}
+	if !exists {
+		if err := m.s3.MakeBucket(ctx, bucket, minio.MakeBucketOptions{Region: m.region}); err != nil {
+			return fmt.Errorf("objectstore: failed to create bucket: %w", err)
+		}
+	}
+	if _, err := m.s3.PutObject(ctx, bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType}); err != nil {
+		return fmt.Errorf("objectstore: failed to upload %s: %w", key, err)
+	}
+	return nil
+}
+
+func (m *MinIO) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	obj, err := m.s3.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("objectstore: failed to open %s: %w", key, err)
+	}
+	// GetObject is lazy; Stat surfaces a missing key before any bytes are sent
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("objectstore: failed to open %s: %w", key, err)
+	}
+	return obj, nil
+}
+
+func policyName(bucket string) string {
+	return "kari-bucket-" + bucket
+}
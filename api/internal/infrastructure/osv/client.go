@@ -0,0 +1,155 @@
+package osv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// DefaultAPIURL is the public OSV.dev API. Self-hosted mirrors that serve
+// the same API can be configured instead.
+const DefaultAPIURL = "https://api.osv.dev"
+
+// queryBatchSize is OSV's limit on queries per /v1/querybatch call.
+const queryBatchSize = 1000
+
+// Client matches Package URLs against the OSV advisory database. Only the
+// purls leave the Brain; no app names, domains or source code.
+type Client struct {
+	apiURL string
+	client *http.Client
+}
+
+func NewClient(apiURL string) *Client {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return &Client{
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+var _ domain.VulnerabilityFeed = (*Client)(nil)
+
+type batchQuery struct {
+	Package struct {
+		PURL string `json:"purl"`
+	} `json:"package"`
+}
+
+type batchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+// Query returns the advisory IDs affecting each purl. Results come back in
+// query order, so they are matched up by index.
+func (c *Client) Query(ctx context.Context, purls []string) (map[string][]string, error) {
+	affected := make(map[string][]string)
+	for start := 0; start < len(purls); start += queryBatchSize {
+		batch := purls[start:min(start+queryBatchSize, len(purls))]
+
+		queries := make([]batchQuery, len(batch))
+		for i, p := range batch {
+			// OSV matches on type/namespace/name@version; qualifiers only get it rejected
+			p, _, _ = strings.Cut(p, "?")
+			p, _, _ = strings.Cut(p, "#")
+			queries[i].Package.PURL = p
+		}
+		var resp batchResponse
+		if err := c.do(ctx, http.MethodPost, "/v1/querybatch", map[string]any{"queries": queries}, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Results) != len(batch) {
+			return nil, fmt.Errorf("OSV returned %d results for %d queries", len(resp.Results), len(batch))
+		}
+		for i, res := range resp.Results {
+			for _, v := range res.Vulns {
+				affected[batch[i]] = append(affected[batch[i]], v.ID)
+			}
+		}
+	}
+	return affected, nil
+}
+
+type vulnResponse struct {
+	ID               string   `json:"id"`
+	Aliases          []string `json:"aliases"`
+	Summary          string   `json:"summary"`
+	Details          string   `json:"details"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+func (c *Client) Get(ctx context.Context, id string) (*domain.Vulnerability, error) {
+	var resp vulnResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/vulns/"+url.PathEscape(id), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	v := &domain.Vulnerability{
+		ID:       resp.ID,
+		Aliases:  resp.Aliases,
+		Summary:  resp.Summary,
+		Severity: severity(resp.DatabaseSpecific.Severity),
+	}
+	if v.Summary == "" {
+		v.Summary, _, _ = strings.Cut(resp.Details, "\n")
+	}
+	return v, nil
+}
+
+// severity normalizes the GHSA-style rating most ecosystems publish. Advisories
+// rated only by CVSS vector stay UNKNOWN rather than being guessed at.
+func severity(s string) string {
+	switch s = strings.ToUpper(strings.TrimSpace(s)); s {
+	case domain.VulnSeverityCritical, domain.VulnSeverityHigh, domain.VulnSeverityModerate, domain.VulnSeverityLow:
+		return s
+	case "MEDIUM":
+		return domain.VulnSeverityModerate
+	default:
+		return domain.VulnSeverityUnknown
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body any, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.apiURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "kari-brain/vulnerability-scan")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("OSV request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("OSV returned HTTP %d for %s", resp.StatusCode, path)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid OSV response: %w", err)
+	}
+	return nil
+}
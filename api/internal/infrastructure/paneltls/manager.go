@@ -0,0 +1,266 @@
+// Package paneltls lets the Brain serve the panel over HTTPS itself when no
+// reverse proxy fronts it. The certificate for APP_DOMAIN is obtained with the
+// same ACME client (lego) tenant sites use, but the HTTP-01 challenge is
+// answered in-process: the Brain owns port 80 in this mode.
+package paneltls
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/go-acme/lego/v4/registration"
+)
+
+const (
+	// renewBefore matches Let's Encrypt's advice for 90-day certificates
+	renewBefore   = 30 * 24 * time.Hour
+	checkInterval = 12 * time.Hour
+	retryInterval = time.Hour
+
+	challengePrefix = "/.well-known/acme-challenge/"
+)
+
+// Manager keeps a certificate for one domain on disk and in memory and
+// renews it before it expires. Handshakes pick up a renewal immediately.
+type Manager struct {
+	domain    string
+	email     string
+	directory string // ACME directory URL; empty for Let's Encrypt production
+	dir       string // account.key, cert.pem and key.pem, all 0600
+	logger    *slog.Logger
+	transport func(http.RoundTripper) http.RoundTripper // Optional ACME transport wrapper
+
+	cert   atomic.Pointer[tls.Certificate]
+	tokens sync.Map // HTTP-01 token -> key authorization
+}
+
+func NewManager(domain, email, directoryURL, stateDir string, logger *slog.Logger) (*Manager, error) {
+	if domain == "" {
+		return nil, errors.New("APP_DOMAIN is required to obtain a panel certificate")
+	}
+	if err := os.MkdirAll(stateDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create TLS state directory: %w", err)
+	}
+
+	m := &Manager{
+		domain:    domain,
+		email:     email,
+		directory: directoryURL,
+		dir:       stateDir,
+		logger:    logger,
+	}
+	// A certificate from a previous run serves right away, even while stale
+	if cert, err := tls.LoadX509KeyPair(m.path("cert.pem"), m.path("key.pem")); err == nil {
+		m.cert.Store(&cert)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		logger.Warn("⚠️ Ignoring unreadable panel certificate", slog.Any("error", err))
+	}
+	return m, nil
+}
+
+// WithTransport wraps the HTTP transport of every ACME client it creates.
+func (m *Manager) WithTransport(wrap func(http.RoundTripper) http.RoundTripper) *Manager {
+	m.transport = wrap
+	return m
+}
+
+// TLSConfig serves the current certificate; handshakes fail until the
+// first one is issued.
+func (m *Manager) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			if cert := m.cert.Load(); cert != nil {
+				return cert, nil
+			}
+			return nil, fmt.Errorf("no certificate for %s yet", m.domain)
+		},
+	}
+}
+
+// HTTPHandler answers ACME challenges and redirects everything else to
+// HTTPS on httpsPort.
+func (m *Manager) HTTPHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token, ok := strings.CutPrefix(r.URL.Path, challengePrefix); ok {
+			if keyAuth, found := m.tokens.Load(token); found {
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = w.Write([]byte(keyAuth.(string)))
+				return
+			}
+			http.NotFound(w, r)
+			return
+		}
+
+		host := m.domain
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		// 🛡️ The configured domain, never the Host header: no open redirect
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
+
+// Present and CleanUp make the Manager lego's HTTP-01 provider.
+func (m *Manager) Present(_, token, keyAuth string) error {
+	m.tokens.Store(token, keyAuth)
+	return nil
+}
+
+func (m *Manager) CleanUp(_, token, _ string) error {
+	m.tokens.Delete(token)
+	return nil
+}
+
+// Start obtains a certificate if there is none and renews it once less than
+// renewBefore remains. A failed attempt is retried hourly.
+func (m *Manager) Start(ctx context.Context) {
+	for {
+		wait := checkInterval
+		if m.needsRenewal() {
+			if err := m.obtain(); err != nil {
+				m.logger.Error("❌ Panel certificate renewal failed", slog.String("domain", m.domain), slog.Any("error", err))
+				wait = retryInterval
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (m *Manager) needsRenewal() bool {
+	cert := m.cert.Load()
+	if cert == nil || len(cert.Certificate) == 0 {
+		return true
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil || leaf.VerifyHostname(m.domain) != nil {
+		return true // APP_DOMAIN changed since it was issued
+	}
+	return time.Until(leaf.NotAfter) < renewBefore
+}
+
+func (m *Manager) obtain() error {
+	key, err := m.accountKey()
+	if err != nil {
+		return err
+	}
+	user := &acmeUser{email: m.email, key: key}
+
+	legoCfg := lego.NewConfig(user)
+	if m.directory != "" {
+		legoCfg.CADirURL = m.directory
+	}
+	if m.transport != nil {
+		legoCfg.HTTPClient.Transport = m.transport(legoCfg.HTTPClient.Transport)
+	}
+	client, err := lego.NewClient(legoCfg)
+	if err != nil {
+		return fmt.Errorf("failed to create ACME client: %w", err)
+	}
+	if err := client.Challenge.SetHTTP01Provider(m); err != nil {
+		return fmt.Errorf("failed to set HTTP-01 provider: %w", err)
+	}
+	// Registering an existing key returns the existing account
+	if user.registration, err = client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true}); err != nil {
+		return fmt.Errorf("failed to register ACME account: %w", err)
+	}
+
+	res, err := client.Certificate.Obtain(certificate.ObtainRequest{Domains: []string{m.domain}, Bundle: true})
+	if err != nil {
+		return fmt.Errorf("failed to obtain certificate: %w", err)
+	}
+	cert, err := tls.X509KeyPair(res.Certificate, res.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("issued certificate is unusable: %w", err)
+	}
+
+	// Key first: a crash in between leaves a pair that fails to load and is
+	// re-issued, never a new certificate beside an old key
+	if err := writeFile(m.path("key.pem"), res.PrivateKey); err != nil {
+		return err
+	}
+	if err := writeFile(m.path("cert.pem"), res.Certificate); err != nil {
+		return err
+	}
+	m.cert.Store(&cert)
+	m.logger.Info("🔐 Panel certificate issued", slog.String("domain", m.domain))
+	return nil
+}
+
+// accountKey loads the ACME account key, creating it on first use.
+func (m *Manager) accountKey() (crypto.PrivateKey, error) {
+	raw, err := os.ReadFile(m.path("account.key"))
+	if err == nil {
+		block, _ := pem.Decode(raw)
+		if block == nil {
+			return nil, errors.New("account.key is not PEM")
+		}
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read account key: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate account key: %w", err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFile(m.path("account.key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (m *Manager) path(name string) string {
+	return filepath.Join(m.dir, name)
+}
+
+// writeFile replaces path atomically with a 0600 file.
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+type acmeUser struct {
+	email        string
+	registration *registration.Resource
+	key          crypto.PrivateKey
+}
+
+func (u *acmeUser) GetEmail() string                        { return u.email }
+func (u *acmeUser) GetRegistration() *registration.Resource { return u.registration }
+func (u *acmeUser) GetPrivateKey() crypto.PrivateKey        { return u.key }
@@ -0,0 +1,200 @@
+// Package registry implements domain.RegistryVerifier against the
+// Docker Registry HTTP API v2 (Docker Hub, GHCR, Harbor, GitLab, ...).
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/infrastructure/webhook"
+)
+
+const maxResponseBytes = 1 << 20
+
+// dockerHubAPIHost serves the v2 API for the "docker.io" namespace.
+const dockerHubAPIHost = "registry-1.docker.io"
+
+// realmHosts are token services that live on another host than the registry
+// they sign for. Any other realm must be on the registry's own host.
+var realmHosts = map[string][]string{
+	dockerHubAPIHost:      {"auth.docker.io"},
+	"registry.gitlab.com": {"gitlab.com"},
+}
+
+type Verifier struct {
+	client *http.Client
+}
+
+// NewVerifier builds the handshake client. Unless allowPrivate is set it
+// shares the webhook sender's dial-time guard: a registry address is user
+// input, and must not reach the control plane's own network.
+func NewVerifier(allowPrivate bool) *Verifier {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = webhook.PublicOnly
+	}
+	return &Verifier{client: &http.Client{
+		Timeout:   15 * time.Second,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 5 * time.Second},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse // Never forward credentials across redirects
+		},
+	}}
+}
+
+// Verify performs the same handshake as `docker login`: ping /v2/, then answer
+// the advertised Basic or Bearer challenge with the credential.
+// Rejected credentials wrap domain.ErrInvalidCredentials; anything else means
+// the registry could not be asked.
+func (v *Verifier) Verify(ctx context.Context, registry, username, password string) error {
+	host := domain.NormalizeRegistryHost(registry)
+	if host == domain.DockerHubRegistry {
+		host = dockerHubAPIHost
+	}
+	ping := "https://" + host + "/v2/"
+
+	// 1. Anonymous ping discovers the auth scheme
+	resp, err := v.get(ctx, ping, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusOK {
+		// Open registry: nothing to prove, but the credential is not wrong either
+		return nil
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return fmt.Errorf("%w: registry ping returned HTTP %d", domain.ErrUnavailable, resp.StatusCode)
+	}
+
+	scheme, params := parseChallenge(resp.Header.Get("WWW-Authenticate"))
+	switch scheme {
+	case "basic":
+		// 2a. Basic: retry the ping with the credential
+		resp, err := v.get(ctx, ping, func(r *http.Request) { r.SetBasicAuth(username, password) })
+		if err != nil {
+			return err
+		}
+		return checkAuthStatus(resp.StatusCode)
+
+	case "bearer":
+		// 2b. Bearer: the token service validates the credential
+		realm, err := url.Parse(params["realm"])
+		if err != nil || realm.Scheme != "https" {
+			return fmt.Errorf("%w: registry advertised an insecure token realm", domain.ErrUnavailable)
+		}
+		// 🛡️ The credential only goes to the registry's own token service
+		if !trustedRealm(host, realm.Host) {
+			return fmt.Errorf("%w: registry advertised a token realm on another host (%s)", domain.ErrUnavailable, realm.Hostname())
+		}
+		q := realm.Query()
+		if svc := params["service"]; svc != "" {
+			q.Set("service", svc)
+		}
+		q.Set("account", username)
+		realm.RawQuery = q.Encode()
+
+		resp, err := v.get(ctx, realm.String(), func(r *http.Request) { r.SetBasicAuth(username, password) })
+		if err != nil {
+			return err
+		}
+		return checkAuthStatus(resp.StatusCode)
+
+	default:
+		return fmt.Errorf("%w: unsupported registry auth scheme %q", domain.ErrUnavailable, scheme)
+	}
+}
+
+// get issues a GET and drains the body; callers only need status and headers.
+func (v *Verifier) get(ctx context.Context, endpoint string, auth func(*http.Request)) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid registry address: %v", domain.ErrValidation, err)
+	}
+	req.Header.Set("User-Agent", "kari-brain/registry-verifier")
+	if auth != nil {
+		auth(req)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: registry unreachable: %v", domain.ErrUnavailable, err)
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+	resp.Body.Close()
+	return resp, nil
+}
+
+// trustedRealm reports whether a Bearer realm on realmHost may receive the
+// credential for registry host.
+func trustedRealm(host, realmHost string) bool {
+	realmHost = strings.ToLower(realmHost)
+	if realmHost == strings.ToLower(host) {
+		return true
+	}
+	for _, allowed := range realmHosts[host] {
+		if realmHost == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func checkAuthStatus(status int) error {
+	switch {
+	case status >= 200 && status <= 299:
+		return nil
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return fmt.Errorf("%w: registry rejected the credential", domain.ErrInvalidCredentials)
+	default:
+		return fmt.Errorf("%w: registry auth returned HTTP %d", domain.ErrUnavailable, status)
+	}
+}
+
+// parseChallenge splits `Bearer realm="...",service="..."` into a lowercased
+// scheme and its parameters.
+func parseChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := map[string]string{}
+	for _, part := range splitParams(rest) {
+		k, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		if unq, err := unquote(val); err == nil {
+			val = unq
+		}
+		params[strings.ToLower(k)] = val
+	}
+	return strings.ToLower(scheme), params
+}
+
+// splitParams splits on commas that are outside quoted strings.
+func splitParams(s string) []string {
+	var parts []string
+	inQuotes, start := false, 0
+	for i, c := range s {
+		switch c {
+		case '"':
+			inQuotes = !inQuotes
+		case ',':
+			if !inQuotes {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func unquote(s string) (string, error) {
+	var out string
+	err := json.Unmarshal([]byte(s), &out)
+	return out, err
+}
@@ -0,0 +1,83 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"kari/api/internal/core/domain"
+)
+
+// ==============================================================================
+// 1. Splunk HTTP Event Collector
+// ==============================================================================
+
+// SplunkHECSink posts newline-delimited HEC envelopes to /services/collector/event.
+type SplunkHECSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewSplunkHECSink(url, token string) *SplunkHECSink {
+	return &SplunkHECSink{url: url, token: token, client: newHTTPClient()}
+}
+
+type hecEnvelope struct {
+	Time       float64           `json:"time"`
+	Source     string            `json:"source"`
+	SourceType string            `json:"sourcetype"`
+	Event      domain.AuditEvent `json:"event"`
+}
+
+func (s *SplunkHECSink) Send(ctx context.Context, events []domain.AuditEvent) error {
+	// HEC accepts multiple concatenated JSON objects in a single request
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, ev := range events {
+		err := enc.Encode(hecEnvelope{
+			Time:       float64(ev.Timestamp.UnixNano()) / 1e9,
+			Source:     "kari-brain",
+			SourceType: "kari:audit",
+			Event:      ev,
+		})
+		if err != nil {
+			return fmt.Errorf("siem: failed to encode HEC event: %w", err)
+		}
+	}
+	return postBody(ctx, s.client, s.url, "application/json", "Splunk "+s.token, buf.Bytes())
+}
+
+func (s *SplunkHECSink) Close() error { return nil }
+
+// ==============================================================================
+// 2. Generic HTTPS Webhook
+// ==============================================================================
+
+// WebhookSink posts a JSON array of events. If a token is configured it is sent
+// as a Bearer credential.
+type WebhookSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewWebhookSink(url, token string) *WebhookSink {
+	return &WebhookSink{url: url, token: token, client: newHTTPClient()}
+}
+
+func (s *WebhookSink) Send(ctx context.Context, events []domain.AuditEvent) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("siem: failed to encode webhook batch: %w", err)
+	}
+	auth := ""
+	if s.token != "" {
+		auth = "Bearer " + s.token
+	}
+	return postBody(ctx, s.client, s.url, "application/json", auth, body)
+}
+
+func (s *WebhookSink) Close() error { return nil }
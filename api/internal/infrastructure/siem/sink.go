@@ -0,0 +1,63 @@
+// Package siem forwards audit events to external security tooling.
+package siem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// NewSink builds the transport selected by the admin configuration.
+func NewSink(cfg domain.AuditSinkConfig) (domain.AuditSink, error) {
+	switch cfg.Kind {
+	case domain.AuditSinkSyslog:
+		return NewSyslogSink(cfg.Endpoint, cfg.Network)
+	case domain.AuditSinkSplunkHEC:
+		return NewSplunkHECSink(cfg.Endpoint, cfg.Token), nil
+	case domain.AuditSinkHTTPS:
+		return NewWebhookSink(cfg.Endpoint, cfg.Token), nil
+	default:
+		return nil, fmt.Errorf("siem: unsupported sink kind %q", cfg.Kind)
+	}
+}
+
+// newHTTPClient is shared by the HTTP-based sinks.
+// 🛡️ SLA: A slow SIEM must never hold a forwarder goroutine indefinitely.
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse // Never forward tokens across redirects
+		},
+	}
+}
+
+// postBody sends a payload and treats any non-2xx status as a retryable failure.
+func postBody(ctx context.Context, client *http.Client, url, contentType, authHeader string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("siem: invalid request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "kari-brain/audit-forwarder")
+	if authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("siem: delivery failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("siem: endpoint returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
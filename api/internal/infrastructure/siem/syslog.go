@@ -0,0 +1,124 @@
+package siem
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// syslogPriority is facility 13 (log audit) * 8 + severity 6 (informational).
+const syslogPriority = 13*8 + 6
+
+// SyslogSink emits RFC 5424 messages with the JSON event as the MSG part.
+// TCP and TLS use RFC 6587 octet-counting framing; UDP sends one datagram per event.
+type SyslogSink struct {
+	addr     string
+	network  string
+	hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func NewSyslogSink(addr, network string) (*SyslogSink, error) {
+	switch network {
+	case "":
+		network = "tls" // 🛡️ Zero-Trust: Encrypted by default
+	case "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("siem: unsupported syslog network %q", network)
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, fmt.Errorf("siem: syslog endpoint must be host:port: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+	return &SyslogSink{addr: addr, network: network, hostname: hostname}, nil
+}
+
+func (s *SyslogSink) Send(ctx context.Context, events []domain.AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetWriteDeadline(deadline)
+	} else {
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	}
+
+	for _, ev := range events {
+		msg, err := s.format(ev)
+		if err != nil {
+			return err
+		}
+		if s.network != "udp" {
+			msg = append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+		}
+		if _, err := conn.Write(msg); err != nil {
+			// Drop the broken connection; the forwarder's retry will redial.
+			conn.Close()
+			s.conn = nil
+			return fmt.Errorf("siem: syslog write failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// connect lazily dials and reuses a single connection.
+func (s *SyslogSink) connect(ctx context.Context) (net.Conn, error) {
+	if s.conn != nil {
+		return s.conn, nil
+	}
+
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	var conn net.Conn
+	var err error
+	if s.network == "tls" {
+		host, _, _ := net.SplitHostPort(s.addr)
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", s.addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, s.network, s.addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("siem: syslog dial %s/%s failed: %w", s.network, s.addr, err)
+	}
+	s.conn = conn
+	return conn, nil
+}
+
+// format renders: <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG
+func (s *SyslogSink) format(ev domain.AuditEvent) ([]byte, error) {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return nil, fmt.Errorf("siem: failed to encode syslog event: %w", err)
+	}
+	header := fmt.Sprintf("<%d>1 %s %s kari-brain - AUDIT - ",
+		syslogPriority, ev.Timestamp.UTC().Format(time.RFC3339Nano), s.hostname)
+	return append([]byte(header), payload...), nil
+}
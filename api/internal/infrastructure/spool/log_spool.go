@@ -0,0 +1,165 @@
+package spool
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"kari/api/internal/core/domain"
+)
+
+// LogSpool buffers build log messages on local disk while Postgres rejects
+// writes, so the stored copy of a deployment log is never lossy:
+//
+//	<dir>/<deployment id>.jsonl
+//
+// Once a deployment has spilled, later messages for it queue behind the
+// backlog until Replay drains it, keeping deployment_logs in write order.
+type LogSpool struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewLogSpool ensures the spool directory exists with owner-only permissions.
+func NewLogSpool(dir string) (*LogSpool, error) {
+	// 🛡️ Privacy: Build output may contain scrubbed-but-sensitive context
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("spool: failed to create %s: %w", dir, err)
+	}
+	return &LogSpool{dir: dir}, nil
+}
+
+// Holds reports whether the deployment has messages waiting for replay.
+func (s *LogSpool) Holds(deploymentID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := os.Stat(s.path(deploymentID))
+	return err == nil
+}
+
+// Append writes one message and fsyncs; it returns only once the line is durable.
+func (s *LogSpool) Append(deploymentID string, msg domain.LogMessage) error {
+	if _, err := uuid.Parse(deploymentID); err != nil {
+		return fmt.Errorf("spool: invalid deployment id %q", deploymentID) // Never a path component otherwise
+	}
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path(deploymentID), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("spool: failed to open: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("spool: failed to write: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("spool: failed to sync: %w", err)
+	}
+	return f.Close()
+}
+
+// Replay feeds every spooled message to insert in write order. A deployment's
+// file is removed once fully inserted; on the first failure the remaining
+// lines are kept for the next pass. It returns the number of messages replayed.
+func (s *LogSpool) Replay(ctx context.Context, insert func(ctx context.Context, deploymentID string, msg domain.LogMessage) error) (int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return 0, fmt.Errorf("spool: failed to list: %w", err)
+	}
+
+	total := 0
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".jsonl")
+		if !ok || e.IsDir() {
+			continue
+		}
+		n, err := s.replayFile(ctx, id, insert)
+		total += n
+		if err != nil {
+			return total, err // The database is likely still down; try again later
+		}
+	}
+	return total, nil
+}
+
+// replayFile holds the lock throughout, so Append cannot slip a message in
+// between the last insert and the removal.
+func (s *LogSpool) replayFile(ctx context.Context, deploymentID string, insert func(ctx context.Context, deploymentID string, msg domain.LogMessage) error) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.path(deploymentID)
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("spool: failed to open: %w", err)
+	}
+	var lines [][]byte
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16<<20)
+	for sc.Scan() {
+		lines = append(lines, append([]byte(nil), sc.Bytes()...))
+	}
+	f.Close()
+	if err := sc.Err(); err != nil {
+		return 0, fmt.Errorf("spool: failed to read: %w", err)
+	}
+
+	for i, line := range lines {
+		var msg domain.LogMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue // A torn final line from a crash mid-append
+		}
+		if err := insert(ctx, deploymentID, msg); err != nil {
+			return i, s.rewrite(path, lines[i:], err)
+		}
+	}
+	if err := os.Remove(path); err != nil {
+		return len(lines), fmt.Errorf("spool: failed to remove replayed file: %w", err)
+	}
+	return len(lines), nil
+}
+
+// rewrite atomically replaces the file with the lines not yet inserted.
+func (s *LogSpool) rewrite(path string, rest [][]byte, cause error) error {
+	tmp, err := os.CreateTemp(s.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("spool: replay failed (%v) and rewrite failed: %w", cause, err)
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+
+	w := bufio.NewWriter(tmp)
+	for _, line := range rest {
+		w.Write(line)
+		w.WriteByte('\n')
+	}
+	err = w.Flush()
+	if err == nil {
+		err = tmp.Sync()
+	}
+	tmp.Close()
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		// The original is untouched; the next pass repeats lines already inserted
+		return fmt.Errorf("spool: replay failed (%v) and rewrite failed: %w", cause, err)
+	}
+	return fmt.Errorf("spool: replay stopped: %w", cause)
+}
+
+func (s *LogSpool) path(deploymentID string) string {
+	return filepath.Join(s.dir, deploymentID+".jsonl")
+}
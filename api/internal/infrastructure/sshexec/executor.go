@@ -0,0 +1,397 @@
+// Package sshexec drives a server that has no Muscle agent by running shell
+// commands over SSH. It implements the agent's gRPC service, so callers get
+// an ordinary SystemAgentClient; RPCs it cannot honor answer Unimplemented,
+// and the server's declared capabilities keep services from sending them.
+package sshexec
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+// Version is reported as the agent version of SSH-driven servers.
+const Version = "ssh"
+
+// Mirrors the Muscle's defaults, so files land where the rest of the Brain
+// expects them on either kind of server.
+const webRoot = "/var/www/kari"
+
+var allowedPrefixes = []string{webRoot, "/etc/kari/ssl", "/etc/nginx/sites-available", "/etc/systemd/system"}
+
+var (
+	identifierPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`) // Same set as the Muscle's validate_identifier
+	unitPattern       = regexp.MustCompile(`^[A-Za-z0-9@._-]+$`)
+	envKeyPattern     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	modePattern       = regexp.MustCompile(`^[0-7]{3,4}$`)
+)
+
+// Target is one SSH login, with the host key pinned at registration.
+type Target struct {
+	Addr     string // host:port
+	Username string // Needs passwordless sudo
+	HostKey  ssh.PublicKey
+	Signer   ssh.Signer
+}
+
+// Executor serves the agent API for one Target. Each RPC opens its own SSH
+// connection: calls are rare enough that holding one open buys nothing.
+type Executor struct {
+	agent.UnimplementedSystemAgentServer
+
+	target Target
+	logger *slog.Logger
+}
+
+func NewExecutor(target Target, logger *slog.Logger) *Executor {
+	return &Executor{target: target, logger: logger}
+}
+
+// ==============================================================================
+// 1. Health
+// ==============================================================================
+
+func (e *Executor) GetSystemStatus(ctx context.Context, _ *agent.Empty) (*agent.SystemStatus, error) {
+	res, err := e.run(ctx, "cat /proc/uptime", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.exitCode != 0 {
+		return nil, status.Errorf(codes.Unavailable, "health check failed: %s", res.stderr)
+	}
+	var uptime float64
+	if fields := strings.Fields(res.stdout); len(fields) > 0 {
+		uptime, _ = strconv.ParseFloat(fields[0], 64)
+	}
+	return &agent.SystemStatus{Healthy: true, AgentVersion: Version, UptimeSeconds: uint64(uptime)}, nil
+}
+
+// capabilityScript prints one "key value" line per finding, mirroring the
+// Muscle's host probe.
+const capabilityScript = `for b in podman docker; do if command -v $b >/dev/null 2>&1; then echo runtime $b; break; fi; done
+for d in /etc/php/*/fpm; do [ -d "$d" ] && echo php "$(basename "$(dirname "$d")")"; done
+awk '$4 ~ /(^|,)(quota|usrquota|grpquota|prjquota)(,|$)/ { found = 1 } END { if (found) print "quotas yes" }' /proc/mounts
+if command -v nft >/dev/null 2>&1; then echo firewall nftables; elif command -v iptables >/dev/null 2>&1; then echo firewall iptables; fi
+true`
+
+func (e *Executor) GetCapabilities(ctx context.Context, _ *agent.Empty) (*agent.HostCapabilities, error) {
+	res, err := e.run(ctx, capabilityScript, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	caps := &agent.HostCapabilities{}
+	for _, line := range strings.Split(res.stdout, "\n") {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch key {
+		case "runtime":
+			caps.ContainerRuntime = value
+		case "php":
+			caps.PhpVersions = append(caps.PhpVersions, value)
+		case "quotas":
+			caps.DiskQuotas = true
+		case "firewall":
+			caps.Firewall = value
+		}
+	}
+	return caps, nil
+}
+
+// ==============================================================================
+// 2. App Users
+// ==============================================================================
+
+// ProvisionAppJail creates the app user and directory the Muscle would. It
+// writes no unit: without the Muscle there is no cgroup-limited jail, which
+// is why SSH servers do not declare CapabilityAppUnits.
+func (e *Executor) ProvisionAppJail(ctx context.Context, req *agent.ProvisionJailRequest) (*agent.AgentResponse, error) {
+	if err := validateIdentifier(req.AppId, "app_id"); err != nil {
+		return nil, err
+	}
+	if err := validateIdentifier(req.DomainName, "domain_name"); err != nil {
+		return nil, err
+	}
+	// 🛡️ Zero-Trust: Never create a jail user in the system/root UID space
+	if req.AppUid < 1000 {
+		return nil, status.Errorf(codes.InvalidArgument, "app_uid %d is outside the unprivileged range", req.AppUid)
+	}
+
+	user := shellQuote("kari-app-" + req.AppId)
+	dir := shellQuote(path.Join(webRoot, req.DomainName))
+	cmd := fmt.Sprintf(
+		"id -u %[1]s >/dev/null 2>&1 || sudo -n useradd --system --no-create-home --shell /bin/false -u %[2]d %[1]s; "+
+			"sudo -n install -d -m 0750 -o %[1]s -g %[1]s %[3]s",
+		user, req.AppUid, dir)
+	return e.respond(ctx, cmd, nil)
+}
+
+// ==============================================================================
+// 3. System Files & Services
+// ==============================================================================
+
+func (e *Executor) WriteSystemFile(ctx context.Context, req *agent.FileWriteRequest) (*agent.AgentResponse, error) {
+	// 🛡️ Zero-Trust: Same boundaries as the Muscle, checked on the cleaned path
+	if strings.Contains(req.AbsolutePath, "..") || !path.IsAbs(req.AbsolutePath) {
+		return nil, status.Error(codes.InvalidArgument, "path traversal detected")
+	}
+	target := path.Clean(req.AbsolutePath)
+	allowed := false
+	for _, prefix := range allowedPrefixes {
+		if strings.HasPrefix(target, prefix+"/") {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, status.Errorf(codes.PermissionDenied, "path %q is outside all allowed boundaries", req.AbsolutePath)
+	}
+
+	q := shellQuote(target)
+	cmd := fmt.Sprintf("sudo -n mkdir -p %s && sudo -n tee %s >/dev/null", shellQuote(path.Dir(target)), q)
+	if req.FileMode != "" {
+		if !modePattern.MatchString(req.FileMode) {
+			return nil, status.Error(codes.InvalidArgument, "invalid octal file mode")
+		}
+		cmd += " && sudo -n chmod " + req.FileMode + " " + q
+	}
+	if req.Owner != "" {
+		owner := req.Owner
+		if req.Group != "" {
+			owner += ":" + req.Group
+		}
+		if err := validateIdentifier(strings.Replace(owner, ":", "", 1), "owner"); err != nil {
+			return nil, err
+		}
+		cmd += " && sudo -n chown " + shellQuote(owner) + " " + q
+	}
+	return e.respond(ctx, cmd, bytes.NewReader(req.Content))
+}
+
+func (e *Executor) ManageService(ctx context.Context, req *agent.ServiceRequest) (*agent.AgentResponse, error) {
+	if !unitPattern.MatchString(req.ServiceName) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid service name %q", req.ServiceName)
+	}
+	var verb string
+	switch req.Action {
+	case agent.ServiceAction_START:
+		verb = "start"
+	case agent.ServiceAction_STOP:
+		verb = "stop"
+	case agent.ServiceAction_RESTART:
+		verb = "restart"
+	case agent.ServiceAction_RELOAD:
+		verb = "reload"
+	case agent.ServiceAction_ENABLE:
+		verb = "enable"
+	case agent.ServiceAction_DISABLE:
+		verb = "disable"
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown service action %v", req.Action)
+	}
+	cmd := "sudo -n systemctl " + verb + " " + shellQuote(req.ServiceName)
+	if verb == "start" || verb == "restart" || verb == "enable" {
+		cmd = "sudo -n systemctl daemon-reload && " + cmd // Picks up units written by WriteSystemFile
+	}
+	return e.respond(ctx, cmd, nil)
+}
+
+// ==============================================================================
+// 4. Builds
+// ==============================================================================
+
+// StreamDeployment clones and builds the app as its jail user, streaming the
+// output. It stops there: installing and starting the release is the
+// Muscle's job, so SSH servers do not declare CapabilityReleases.
+func (e *Executor) StreamDeployment(req *agent.DeployRequest, stream agent.SystemAgent_StreamDeploymentServer) error {
+	if err := validateIdentifier(req.AppId, "app_id"); err != nil {
+		return err
+	}
+	if err := validateIdentifier(req.DomainName, "domain_name"); err != nil {
+		return err
+	}
+	if req.SshKey != nil && *req.SshKey != "" {
+		return status.Error(codes.FailedPrecondition, "private repositories need the Muscle agent")
+	}
+	if !strings.HasPrefix(req.RepoUrl, "https://") {
+		return status.Error(codes.InvalidArgument, "repo_url must be an https URL")
+	}
+	if req.Branch != "" && (strings.HasPrefix(req.Branch, "-") || !identifierPattern.MatchString(strings.ReplaceAll(req.Branch, "/", ""))) {
+		return status.Errorf(codes.InvalidArgument, "invalid branch %q", req.Branch)
+	}
+
+	// Build env first, so an app's runtime env cannot mask a build setting
+	env := make(map[string]string, len(req.EnvVars)+len(req.BuildEnv))
+	for k, v := range req.EnvVars {
+		env[k] = v
+	}
+	for k, v := range req.BuildEnv {
+		env[k] = v
+	}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		if !envKeyPattern.MatchString(k) {
+			return status.Errorf(codes.InvalidArgument, "invalid environment variable name %q", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var exports strings.Builder
+	for _, k := range keys {
+		exports.WriteString("export " + k + "=" + shellQuote(env[k]) + "; ")
+	}
+
+	clone := "git clone --depth 1 "
+	if req.Branch != "" {
+		clone += "--branch " + shellQuote(req.Branch) + " "
+	}
+	clone += shellQuote(req.RepoUrl) + " source.new"
+
+	script := "set -e; cd " + shellQuote(path.Join(webRoot, req.DomainName)) + "; " +
+		"rm -rf source.new; " + clone + "; cd source.new; " + exports.String()
+	if req.BuildCommand != "" {
+		script += req.BuildCommand + "; " // Runs as the jail user, like on the Muscle
+	}
+	script += "cd ..; rm -rf source; mv source.new source"
+	cmd := "sudo -n -u " + shellQuote("kari-app-"+req.AppId) + " sh -c " + shellQuote(script) + " 2>&1"
+
+	ctx := stream.Context()
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		res, err := e.run(ctx, cmd, nil, pw)
+		if err == nil && res.exitCode != 0 {
+			err = status.Errorf(codes.Aborted, "build exited with status %d", res.exitCode)
+		}
+		pw.Close()
+		done <- err
+	}()
+
+	scanner := bufio.NewScanner(pr)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if err := stream.Send(&agent.LogChunk{TraceId: req.TraceId, Content: scanner.Text() + "\n"}); err != nil {
+			pr.CloseWithError(err)
+			<-done
+			return err
+		}
+	}
+	pr.Close()
+	return <-done
+}
+
+// ==============================================================================
+// 5. SSH Plumbing
+// ==============================================================================
+
+type result struct {
+	stdout, stderr string
+	exitCode       int
+}
+
+func (e *Executor) respond(ctx context.Context, cmd string, stdin io.Reader) (*agent.AgentResponse, error) {
+	res, err := e.run(ctx, cmd, stdin, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp := &agent.AgentResponse{
+		Success:  res.exitCode == 0,
+		ExitCode: int32(res.exitCode),
+		Stdout:   res.stdout,
+		Stderr:   res.stderr,
+	}
+	if !resp.Success {
+		resp.ErrorMessage = strings.TrimSpace(res.stderr)
+	}
+	return resp, nil
+}
+
+// run executes cmd in a fresh session. A non-zero exit is a result, not an
+// error; errors mean the command could not be run at all. When out is set,
+// stdout goes there instead of into the result.
+func (e *Executor) run(ctx context.Context, cmd string, stdin io.Reader, out io.Writer) (*result, error) {
+	client, err := e.dial(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "ssh %s: %v", e.target.Addr, err)
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "ssh session: %v", err)
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdin = stdin
+	session.Stdout = &stdout
+	if out != nil {
+		session.Stdout = out
+	}
+	session.Stderr = &stderr
+
+	// Closing the client is the only way to interrupt a running command
+	stop := context.AfterFunc(ctx, func() { client.Close() })
+	defer stop()
+
+	err = session.Run(cmd)
+	if ctx.Err() != nil {
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	res := &result{stdout: stdout.String(), stderr: stderr.String()}
+	var exitErr *ssh.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		res.exitCode = exitErr.ExitStatus()
+	case err != nil:
+		return nil, status.Errorf(codes.Unavailable, "ssh command failed: %v", err)
+	}
+	return res, nil
+}
+
+func (e *Executor) dial(ctx context.Context) (*ssh.Client, error) {
+	config := &ssh.ClientConfig{
+		User:            e.target.Username,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(e.target.Signer)},
+		HostKeyCallback: ssh.FixedHostKey(e.target.HostKey), // 🛡️ Zero-Trust: Pinned at registration
+		Timeout:         10 * time.Second,
+	}
+	d := net.Dialer{Timeout: config.Timeout}
+	conn, err := d.DialContext(ctx, "tcp", e.target.Addr)
+	if err != nil {
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, e.target.Addr, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+func validateIdentifier(value, field string) error {
+	if !identifierPattern.MatchString(value) || strings.HasPrefix(value, "-") || value == "." || value == ".." {
+		return status.Errorf(codes.InvalidArgument, "invalid %s format: %q", field, value)
+	}
+	return nil
+}
+
+// shellQuote wraps s in single quotes for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,84 @@
+package sshexec
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"kari/api/internal/core/domain"
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+// Link serves an Executor on an in-memory listener, so it is reached through
+// the same generated client as a real agent. Nothing leaves the process
+// except the SSH traffic itself.
+type Link struct {
+	server *grpc.Server
+	conn   *grpc.ClientConn
+}
+
+func Serve(e *Executor) (*Link, error) {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	agent.RegisterSystemAgentServer(server, e)
+	go server.Serve(lis)
+
+	conn, err := grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		server.Stop()
+		return nil, fmt.Errorf("failed to link SSH executor: %w", err)
+	}
+	return &Link{server: server, conn: conn}, nil
+}
+
+func (l *Link) Conn() *grpc.ClientConn { return l.conn }
+
+func (l *Link) Close() error {
+	err := l.conn.Close()
+	l.server.Stop()
+	return err
+}
+
+// Credentials turns stored SSH logins into Targets.
+type Credentials struct {
+	repo   domain.ServerRepository
+	crypto domain.CryptoService
+}
+
+func NewCredentials(repo domain.ServerRepository, crypto domain.CryptoService) *Credentials {
+	return &Credentials{repo: repo, crypto: crypto}
+}
+
+func (c *Credentials) Target(ctx context.Context, s domain.Server) (*Target, error) {
+	cred, err := c.repo.GetSSHCredential(ctx, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SSH credential for %s: %w", s.Name, err)
+	}
+	// 🛡️ Privacy: AAD = ServerID, as RegisterSSHServer sealed it
+	keyPEM, err := c.crypto.Decrypt(ctx, cred.PrivateKeyCiphertext, []byte(s.ID.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt SSH key for %s: %w", s.Name, err)
+	}
+	signer, err := ssh.ParsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SSH key for %s: %w", s.Name, err)
+	}
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(cred.HostKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pinned host key for %s: %w", s.Name, err)
+	}
+	return &Target{
+		Addr:     net.JoinHostPort(s.Hostname, strconv.Itoa(cred.Port)),
+		Username: cred.Username,
+		HostKey:  hostKey,
+		Signer:   signer,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/utils"
+)
+
+// ErrPrivateAddress is returned when a host resolves to an address the Brain
+// must not reach on a user's behalf.
+var ErrPrivateAddress = errors.New("destination resolves to a non-public address")
+
+// Sender POSTs signed event payloads to user endpoints.
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender builds the delivery client. Unless allowPrivate is set, the dialer
+// refuses loopback, private and link-local addresses, so a user-supplied URL
+// cannot be used to probe the control plane's own network.
+func NewSender(allowPrivate bool) *Sender {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = PublicOnly
+	}
+
+	return &Sender{client: &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 5 * time.Second},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse // A redirect is a non-2xx answer, never followed
+		},
+	}}
+}
+
+// Send performs one attempt. Any non-2xx status counts as a failure.
+func (s *Sender) Send(ctx context.Context, url string, secret []byte, headers map[string]string, body []byte) domain.WebhookAttempt {
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return domain.WebhookAttempt{Err: fmt.Errorf("webhook: invalid request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "kari-brain/webhooks")
+	req.Header.Set("X-Kari-Signature-256", utils.SignPayload(body, secret))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return domain.WebhookAttempt{Duration: time.Since(start), Err: err}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt := domain.WebhookAttempt{ResponseStatus: resp.StatusCode, Duration: time.Since(start)}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Err = fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return attempt
+}
+
+// PublicOnly is a net.Dialer Control hook that refuses loopback, private and
+// link-local addresses. Every client dialing a user-supplied host uses it.
+//
+// 🛡️ Zero-Trust: Checked on the resolved address at connect time, so DNS
+// rebinding between validation and delivery doesn't help
+func PublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublic(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+func isPublic(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast())
+}
@@ -0,0 +1,190 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/stdlib"
+
+	"kari/api/internal/api/handlers"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/db/postgres"
+)
+
+// ==============================================================================
+// 1. Auth
+// ==============================================================================
+
+func TestLogin(t *testing.T) {
+	s := newStack(t)
+
+	resp := s.do(t, http.MethodPost, "/api/v1/auth/login", "", handlers.LoginRequest{
+		Email:    adminEmail,
+		Password: "not-the-password",
+	})
+	expect(t, resp, http.StatusUnauthorized, nil)
+
+	var body struct {
+		User struct {
+			ID    uuid.UUID `json:"id"`
+			Email string    `json:"email"`
+		} `json:"user"`
+	}
+	resp = s.do(t, http.MethodPost, "/api/v1/auth/login", "", handlers.LoginRequest{
+		Email:    adminEmail,
+		Password: adminPassword,
+	})
+	expect(t, resp, http.StatusOK, &body)
+	if body.User.ID != s.AdminID || body.User.Email != adminEmail {
+		t.Fatalf("logged in as %s (%s), want the seeded admin %s", body.User.Email, body.User.ID, s.AdminID)
+	}
+}
+
+func TestProtectedRoutesRequireToken(t *testing.T) {
+	s := newStack(t)
+
+	expect(t, s.do(t, http.MethodGet, "/api/v1/applications", "", nil), http.StatusUnauthorized, nil)
+	expect(t, s.do(t, http.MethodGet, "/api/v1/applications", "forged.token.value", nil), http.StatusUnauthorized, nil)
+}
+
+func TestSuspendedUserIsRefused(t *testing.T) {
+	s := newStack(t)
+	token := s.login(t)
+
+	// The token is still valid; the per-request DB check must catch this
+	if _, err := s.DB.Exec(context.Background(), `UPDATE users SET is_active = false WHERE id = $1`, s.AdminID); err != nil {
+		t.Fatalf("suspend: %v", err)
+	}
+	expect(t, s.do(t, http.MethodGet, "/api/v1/applications", token, nil), http.StatusForbidden, nil)
+}
+
+// ==============================================================================
+// 2. Applications
+// ==============================================================================
+
+func TestApplicationLifecycle(t *testing.T) {
+	s := newStack(t)
+	token := s.login(t)
+	domainID := s.seedDomain(t, "shop.kari.test")
+
+	var created domain.Application
+	resp := s.do(t, http.MethodPost, "/api/v1/applications", token, handlers.CreateAppRequest{
+		DomainID:     domainID,
+		AppType:      "nodejs",
+		RepoURL:      "https://github.com/example/shop.git",
+		Branch:       "main",
+		BuildCommand: "npm ci && npm run build",
+		StartCommand: "node server.js",
+		EnvVars:      map[string]string{"NODE_ENV": "production"},
+	})
+	expect(t, resp, http.StatusCreated, &created)
+	if created.ID == uuid.Nil || created.OwnerID != s.AdminID || created.DomainID != domainID {
+		t.Fatalf("created %+v, want an app owned by the admin on the seeded domain", created)
+	}
+	if created.AppUID == nil {
+		t.Fatal("created app has no UID from the ledger")
+	}
+
+	var listed []domain.Application
+	expect(t, s.do(t, http.MethodGet, "/api/v1/applications", token, nil), http.StatusOK, &listed)
+	if len(listed) != 1 || listed[0].ID != created.ID {
+		t.Fatalf("list returned %d apps, want only %s", len(listed), created.ID)
+	}
+
+	var fetched domain.Application
+	expect(t, s.do(t, http.MethodGet, "/api/v1/applications/"+created.ID.String(), token, nil), http.StatusOK, &fetched)
+	if fetched.RepoURL != created.RepoURL || fetched.EnvVars["NODE_ENV"] != "production" {
+		t.Fatalf("fetched %+v, want the app as created", fetched)
+	}
+}
+
+func TestApplicationValidation(t *testing.T) {
+	s := newStack(t)
+	token := s.login(t)
+
+	resp := s.do(t, http.MethodPost, "/api/v1/applications", token, handlers.CreateAppRequest{
+		DomainID: s.seedDomain(t, "bad.kari.test"),
+		AppType:  "cobol",
+	})
+	expect(t, resp, http.StatusBadRequest, nil)
+
+	var count int
+	if err := s.DB.QueryRow(context.Background(), `SELECT count(*) FROM applications`).Scan(&count); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("%d applications stored after a rejected create", count)
+	}
+}
+
+func TestUnknownApplicationIsNotFound(t *testing.T) {
+	s := newStack(t)
+	token := s.login(t)
+
+	expect(t, s.do(t, http.MethodGet, "/api/v1/applications/"+uuid.NewString(), token, nil), http.StatusNotFound, nil)
+	expect(t, s.do(t, http.MethodGet, "/api/v1/applications/not-a-uuid", token, nil), http.StatusBadRequest, nil)
+}
+
+// ==============================================================================
+// 3. Deployments
+// ==============================================================================
+
+func TestTriggerDeployQueuesForWorker(t *testing.T) {
+	s := newStack(t)
+	token := s.login(t)
+
+	var app domain.Application
+	expect(t, s.do(t, http.MethodPost, "/api/v1/applications", token, handlers.CreateAppRequest{
+		DomainID:     s.seedDomain(t, "deploy.kari.test"),
+		AppType:      "go",
+		RepoURL:      "https://github.com/example/api.git",
+		Branch:       "release",
+		BuildCommand: "go build -o app .",
+		StartCommand: "./app",
+	}), http.StatusCreated, &app)
+
+	var queued struct {
+		ID string `json:"id"`
+	}
+	expect(t, s.do(t, http.MethodPost, "/api/v1/applications/"+app.ID.String()+"/deploy", token, nil), http.StatusAccepted, &queued)
+	if _, err := uuid.Parse(queued.ID); err != nil {
+		t.Fatalf("deploy returned id %q: %v", queued.ID, err)
+	}
+
+	// Queueing must not touch the Muscle; the worker does that once it claims the row
+	if calls := s.Agent.Methods(); len(calls) != 0 {
+		t.Fatalf("trigger called the agent: %v", calls)
+	}
+
+	// The worker's claim sees exactly what the API queued
+	repo := postgres.NewPostgresDeploymentRepository(stdlib.OpenDBFromPool(s.DB))
+	claimed, err := repo.ClaimNextPending(context.Background())
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if claimed == nil || claimed.ID != queued.ID || claimed.AppID != app.ID.String() || claimed.Branch != "release" {
+		t.Fatalf("claimed %+v, want deployment %s of app %s on release", claimed, queued.ID, app.ID)
+	}
+	if again, err := repo.ClaimNextPending(context.Background()); err != nil || again != nil {
+		t.Fatalf("second claim = %+v, %v; want nothing left", again, err)
+	}
+
+	var logs struct {
+		Lines []any `json:"lines"`
+	}
+	expect(t, s.do(t, http.MethodGet, "/api/v1/deployments/"+queued.ID+"/logs", token, nil), http.StatusOK, &logs)
+	if len(logs.Lines) != 0 {
+		t.Fatalf("unbuilt deployment has %d log lines", len(logs.Lines))
+	}
+}
+
+func TestDeployUnknownApplicationIsNotFound(t *testing.T) {
+	s := newStack(t)
+	token := s.login(t)
+
+	expect(t, s.do(t, http.MethodPost, "/api/v1/applications/"+uuid.NewString()+"/deploy", token, nil), http.StatusNotFound, nil)
+}
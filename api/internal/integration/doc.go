@@ -0,0 +1,10 @@
+// Package integration drives the Brain end to end: HTTP through the real
+// middleware, services and Postgres repositories, against a throwaway
+// Postgres started with dockertest. Only the Muscle is faked (agentmock).
+//
+// The tests need Docker and are behind the integration build tag:
+//
+//	go test -tags integration ./internal/integration/
+//
+// Without a reachable Docker daemon they skip rather than fail.
+package integration
@@ -0,0 +1,339 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"golang.org/x/crypto/bcrypt"
+
+	"kari/api/internal/api/handlers"
+	"kari/api/internal/api/middleware"
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/saga"
+	"kari/api/internal/core/services"
+	kdb "kari/api/internal/db"
+	"kari/api/internal/db/migrations"
+	"kari/api/internal/db/postgres"
+	"kari/api/internal/infrastructure/agentmock"
+	"kari/api/internal/infrastructure/crypto"
+)
+
+const (
+	adminEmail    = "admin@kari.test"
+	adminPassword = "integration-pass-1"
+	jwtSecret     = "integration-jwt-secret-0123456789abcdef"
+	masterKeyHex  = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+)
+
+var (
+	// serverDSN points at the container's maintenance database; each test
+	// gets a database of its own next to it. Empty when Docker is missing.
+	serverDSN   string
+	dockerError error
+	databases   atomic.Int64
+
+	discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+)
+
+func TestMain(m *testing.M) {
+	dpool, err := dockertest.NewPool("")
+	if err == nil {
+		err = dpool.Client.Ping()
+	}
+	if err != nil {
+		dockerError = err
+		os.Exit(m.Run())
+	}
+
+	resource, err := dpool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "postgres",
+		Tag:        "16-alpine", // Same image as docker-compose
+		Env: []string{
+			"POSTGRES_USER=kari_admin",
+			"POSTGRES_PASSWORD=integration",
+			"POSTGRES_DB=postgres",
+		},
+	}, func(hc *docker.HostConfig) {
+		hc.AutoRemove = true
+		hc.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "integration: could not start postgres: %v\n", err)
+		os.Exit(1)
+	}
+	_ = resource.Expire(600) // Reaped even if this process is killed
+
+	dsn := fmt.Sprintf("postgres://kari_admin:integration@%s/postgres?sslmode=disable", resource.GetHostPort("5432/tcp"))
+	dpool.MaxWait = 2 * time.Minute
+	err = dpool.Retry(func() error {
+		conn, err := pgx.Connect(context.Background(), dsn)
+		if err != nil {
+			return err
+		}
+		defer conn.Close(context.Background())
+		return conn.Ping(context.Background())
+	})
+	if err != nil {
+		_ = dpool.Purge(resource)
+		fmt.Fprintf(os.Stderr, "integration: postgres never became ready: %v\n", err)
+		os.Exit(1)
+	}
+	serverDSN = dsn
+
+	code := m.Run()
+	_ = dpool.Purge(resource)
+	os.Exit(code)
+}
+
+// ==============================================================================
+// 1. The Stack Under Test
+// ==============================================================================
+
+// stack is one Brain wired to a freshly migrated database. Everything is
+// real except the Muscle, which Agent records and answers for.
+type stack struct {
+	URL     string
+	DB      *pgxpool.Pool
+	Agent   *agentmock.Client
+	AdminID uuid.UUID
+}
+
+// newStack creates and migrates a private database, seeds the Super Admin,
+// and serves the auth, application and deployment routes over HTTP.
+func newStack(t *testing.T) *stack {
+	t.Helper()
+	if serverDSN == "" {
+		t.Skipf("integration: docker unavailable: %v", dockerError)
+	}
+	ctx := context.Background()
+
+	name := fmt.Sprintf("kari_it_%d", databases.Add(1))
+	admin, err := pgx.Connect(ctx, serverDSN)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer admin.Close(ctx)
+	if _, err := admin.Exec(ctx, "CREATE DATABASE "+name); err != nil {
+		t.Fatalf("create database: %v", err)
+	}
+
+	cfg, err := pgx.ParseConfig(serverDSN)
+	if err != nil {
+		t.Fatalf("parse dsn: %v", err)
+	}
+	cfg.Database = name
+	db, err := postgres.NewPool(ctx, cfg.ConnString(), postgres.PoolOptions{MaxConns: 4, MinConns: 1})
+	if err != nil {
+		t.Fatalf("pool: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		if conn, err := pgx.Connect(ctx, serverDSN); err == nil {
+			_, _ = conn.Exec(ctx, "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)")
+			conn.Close(ctx)
+		}
+	})
+
+	if _, err := postgres.ApplyMigrations(ctx, db, migrations.Files); err != nil {
+		t.Fatalf("migrations: %v", err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(adminPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	adminID, _, err := postgres.BootstrapAdmin(ctx, db, adminEmail, string(hash))
+	if err != nil {
+		t.Fatalf("bootstrap admin: %v", err)
+	}
+
+	s := &stack{DB: db, Agent: &agentmock.Client{}, AdminID: adminID}
+	srv := httptest.NewServer(s.router(t))
+	t.Cleanup(srv.Close)
+	s.URL = srv.URL
+	return s
+}
+
+// router mounts the handlers at the paths and behind the guards that
+// router.NewRouter uses for them. The full router needs every handler in
+// the panel; these are the ones this suite covers.
+func (s *stack) router(t *testing.T) http.Handler {
+	t.Helper()
+
+	cryptoService, err := crypto.NewAESCryptoService(masterKeyHex)
+	if err != nil {
+		t.Fatalf("crypto: %v", err)
+	}
+
+	userRepo := postgres.NewUserRepo(s.DB)
+	authService := services.NewAuthService(userRepo, services.NewTokenService(jwtSecret))
+	authMiddleware := middleware.NewAuthMiddleware(authService, services.NewRoleService(userRepo, discard), userRepo, discard)
+
+	appRepo := postgres.NewApplicationRepo(s.DB)
+	auditRepo := postgres.NewAuditRepository(s.DB)
+	appService := services.NewApplicationService(
+		appRepo,
+		auditRepo,
+		kdb.NewPostgresProfileRepository(s.DB),
+		s.Agent,
+		newestAgent{},
+		services.NewDeployKeyService(appRepo, postgres.NewDeployKeyRepo(s.DB), cryptoService, discard),
+		noBuckets{},
+		services.NewResourceQuotaService(postgres.NewResourceQuotaRepo(s.DB), auditRepo, discard),
+		saga.NewOrchestrator(postgres.NewSagaRepo(s.DB), discard),
+		discard,
+	).WithStacks(postgres.NewStackRegistryRepo(s.DB))
+	logService := services.NewDeploymentLogService(postgres.NewDeploymentLogRepo(s.DB), appRepo, nil, "", 0, discard)
+
+	authHandler := handlers.NewAuthHandler(authService)
+	appHandler := handlers.NewAppHandler(appService, nil) // No git_provider in these tests
+	logHandler := handlers.NewDeploymentLogHandler(logService)
+
+	r := chi.NewRouter()
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Post("/auth/login", authHandler.Login)
+
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuthentication)
+			r.Use(func(next http.Handler) http.Handler {
+				guard := authMiddleware.RequireScope(
+					"domains:write", "domains:delete",
+					"applications:write", "applications:deploy", "applications:delete",
+					"server:manage", "users:manage", "quotas:manage",
+				)
+				return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					if req.Method == http.MethodPost || req.Method == http.MethodPut ||
+						req.Method == http.MethodDelete || req.Method == http.MethodPatch {
+						guard(next).ServeHTTP(w, req)
+						return
+					}
+					next.ServeHTTP(w, req)
+				})
+			})
+
+			r.Route("/applications", func(r chi.Router) {
+				r.With(authMiddleware.RequirePermission("applications", "read")).Get("/", appHandler.List)
+				r.With(authMiddleware.RequirePermission("applications", "write")).Post("/", appHandler.Create)
+				r.With(authMiddleware.RequirePermission("applications", "read")).Get("/{id}", appHandler.GetByID)
+				r.With(authMiddleware.RequirePermission("applications", "deploy")).Post("/{id}/deploy", appHandler.TriggerDeploy)
+			})
+			r.With(authMiddleware.RequirePermission("applications", "read")).
+				Get("/deployments/{id}/logs", logHandler.List)
+		})
+	})
+	return r
+}
+
+// ==============================================================================
+// 2. Fixtures
+// ==============================================================================
+
+// seedDomain inserts a domain owned by the admin for apps to attach to.
+func (s *stack) seedDomain(t *testing.T, name string) uuid.UUID {
+	t.Helper()
+	id := uuid.New()
+	_, err := s.DB.Exec(context.Background(), `
+		INSERT INTO domains (id, user_id, name, status, target_port, created_at, updated_at)
+		VALUES ($1, $2, $3, 'active', 3000, NOW(), NOW())`, id, s.AdminID, name)
+	if err != nil {
+		t.Fatalf("seed domain: %v", err)
+	}
+	return id
+}
+
+// newestAgent supports every feature, so no flow is cut short by a gate.
+type newestAgent struct{}
+
+func (newestAgent) Supports(domain.AgentFeature) bool { return true }
+
+// noBuckets stands in for object storage, which has no container here.
+type noBuckets struct{}
+
+func (noBuckets) BucketEnvFor(context.Context, uuid.UUID) (map[string]string, error) { return nil, nil }
+func (noBuckets) ReleaseBucket(context.Context, uuid.UUID) error                     { return nil }
+
+// ==============================================================================
+// 3. HTTP Helpers
+// ==============================================================================
+
+// login signs in as the seeded admin and returns the access token. The
+// session cookies are Secure, so a plain-HTTP client would drop them; the
+// token is replayed as a bearer header instead.
+func (s *stack) login(t *testing.T) string {
+	t.Helper()
+	resp := s.do(t, http.MethodPost, "/api/v1/auth/login", "", handlers.LoginRequest{
+		Email:    adminEmail,
+		Password: adminPassword,
+	})
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("login: status %d", resp.StatusCode)
+	}
+	for _, c := range resp.Cookies() {
+		if c.Name == "kari_access_token" {
+			return c.Value
+		}
+	}
+	t.Fatal("login: no access token cookie")
+	return ""
+}
+
+func (s *stack) do(t *testing.T, method, path, token string, body any) *http.Response {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequest(method, s.URL+path, reader)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	return resp
+}
+
+// expect asserts the status and decodes the JSON body into out (if non-nil).
+func expect(t *testing.T, resp *http.Response, status int, out any) {
+	t.Helper()
+	defer resp.Body.Close()
+	if resp.StatusCode != status {
+		raw, _ := io.ReadAll(resp.Body)
+		t.Fatalf("%s %s: status %d, want %d: %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, status, raw)
+	}
+	if out == nil {
+		return
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatalf("%s %s: decode: %v", resp.Request.Method, resp.Request.URL.Path, err)
+	}
+}
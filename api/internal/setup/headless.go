@@ -0,0 +1,225 @@
+package setup
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+
+	"kari/api/internal/db/migrations"
+	"kari/api/internal/db/postgres"
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+// Spec is everything the wizard would ask, for provisioning without a
+// browser (Ansible, cloud-init). Secrets may come from files so they stay
+// out of the spec and the process list.
+type Spec struct {
+	AdminEmail        string `yaml:"admin_email"`
+	AdminPassword     string `yaml:"admin_password"`
+	AdminPasswordFile string `yaml:"admin_password_file"`
+	DatabaseURL       string `yaml:"database_url"`
+	AppDomain         string `yaml:"app_domain"`
+
+	// MasterKeyHex is generated when empty and written to MasterKeyFile,
+	// the only place it is ever shown.
+	MasterKeyHex  string `yaml:"master_key_hex"`
+	MasterKeyFile string `yaml:"master_key_file"`
+
+	SecretsMode string `yaml:"secrets_mode"` // sealed, plaintext; empty seals when possible
+	Migrate     *bool  `yaml:"migrate"`      // Defaults to true
+
+	EnvFile     string `yaml:"env_file"`
+	LockPath    string `yaml:"lock_path"`
+	AgentSocket string `yaml:"agent_socket"`
+}
+
+// LoadSpec reads the spec file (optional) and lets KARI_SETUP_* variables
+// override it, so secrets can be injected from the environment.
+func LoadSpec(path string) (*Spec, error) {
+	spec := &Spec{}
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		dec := yaml.NewDecoder(strings.NewReader(string(raw)))
+		dec.KnownFields(true) // A misspelled key must not silently fall back to a default
+		if err := dec.Decode(spec); err != nil {
+			return nil, fmt.Errorf("invalid setup spec %s: %w", path, err)
+		}
+	}
+
+	for env, field := range map[string]*string{
+		"KARI_SETUP_ADMIN_EMAIL":         &spec.AdminEmail,
+		"KARI_SETUP_ADMIN_PASSWORD":      &spec.AdminPassword,
+		"KARI_SETUP_ADMIN_PASSWORD_FILE": &spec.AdminPasswordFile,
+		"KARI_SETUP_DATABASE_URL":        &spec.DatabaseURL,
+		"KARI_SETUP_APP_DOMAIN":          &spec.AppDomain,
+		"KARI_SETUP_MASTER_KEY_HEX":      &spec.MasterKeyHex,
+		"KARI_SETUP_MASTER_KEY_FILE":     &spec.MasterKeyFile,
+		"KARI_SETUP_SECRETS_MODE":        &spec.SecretsMode,
+		"KARI_SETUP_ENV_FILE":            &spec.EnvFile,
+		"KARI_SETUP_LOCK_PATH":           &spec.LockPath,
+		"KARI_SETUP_AGENT_SOCKET":        &spec.AgentSocket,
+	} {
+		if v, ok := os.LookupEnv(env); ok {
+			*field = v
+		}
+	}
+	if v, ok := os.LookupEnv("KARI_SETUP_MIGRATE"); ok {
+		migrate := v != "false" && v != "0"
+		spec.Migrate = &migrate
+	}
+
+	if spec.EnvFile == "" {
+		spec.EnvFile = DefaultEnvFile
+	}
+	if spec.LockPath == "" {
+		spec.LockPath = DefaultLockPath
+	}
+	if spec.AgentSocket == "" {
+		spec.AgentSocket = "/var/run/kari/agent.sock"
+	}
+	if spec.AdminPasswordFile != "" {
+		raw, err := os.ReadFile(spec.AdminPasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("admin_password_file: %w", err)
+		}
+		spec.AdminPassword = strings.TrimRight(string(raw), "\r\n")
+	}
+	return spec, spec.validate()
+}
+
+// validate applies the wizard's rules (SetupRequest) to the spec.
+func (s *Spec) validate() error {
+	var problems []string
+	if addr, err := mail.ParseAddress(s.AdminEmail); err != nil || addr.Address != s.AdminEmail {
+		problems = append(problems, "admin_email must be an email address")
+	}
+	if n := len(s.AdminPassword); n < 12 || n > 72 {
+		problems = append(problems, "admin password must be 12-72 characters")
+	}
+	if u, err := url.Parse(s.DatabaseURL); err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		problems = append(problems, "database_url must be a postgres:// URL")
+	}
+	if !strings.Contains(s.AppDomain, ".") || strings.ContainsAny(s.AppDomain, "/: ") {
+		problems = append(problems, "app_domain must be a fully qualified domain name")
+	}
+	if s.MasterKeyHex != "" {
+		if raw, err := hex.DecodeString(s.MasterKeyHex); err != nil || len(raw) != 32 {
+			problems = append(problems, "master_key_hex must be 64 hex characters")
+		}
+	} else if s.MasterKeyFile == "" {
+		problems = append(problems, "master_key_file is required when master_key_hex is empty: a generated key is written only there")
+	}
+	switch s.SecretsMode {
+	case "", SecretsModeSealed, SecretsModePlaintext:
+	default:
+		problems = append(problems, "secrets_mode must be sealed or plaintext")
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Run performs every Finalize step without the wizard: migrations, the
+// first Super Admin, the master key, the env file and setup.lock. Each
+// step converges when re-run, until the lock is written.
+func Run(ctx context.Context, spec *Spec, client agent.SystemAgentClient, logger *slog.Logger) error {
+	if Locked(spec.LockPath) {
+		return fmt.Errorf("%s exists: this panel is already set up", spec.LockPath)
+	}
+
+	// 1. Database: schema and the first admin
+	pool, err := postgres.NewPool(ctx, spec.DatabaseURL, postgres.PoolOptions{MaxConns: 2, MinConns: 1})
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	waitCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	err = postgres.WaitForDatabase(waitCtx, pool, logger)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("database unreachable: %w", err)
+	}
+
+	if spec.Migrate == nil || *spec.Migrate {
+		applied, err := postgres.ApplyMigrations(ctx, pool, migrations.Files)
+		if err != nil {
+			return err
+		}
+		logger.Info("🗄️ Setup: Schema ready", "migrations_applied", applied)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(spec.AdminPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash admin password: %w", err)
+	}
+	adminID, created, err := postgres.BootstrapAdmin(ctx, pool, strings.ToLower(spec.AdminEmail), string(hash))
+	if err != nil {
+		return err
+	}
+	logger.Info("👤 Setup: Super Admin ready", "admin_id", adminID, "created", created)
+
+	// 2. Master key: generated keys go to a root-only file, never to logs
+	settings := Settings{
+		DatabaseURL:  spec.DatabaseURL,
+		JWTSecret:    randomHex(32),
+		MasterKeyHex: spec.MasterKeyHex,
+		AppDomain:    spec.AppDomain,
+		AdminEmail:   spec.AdminEmail,
+	}
+	if settings.MasterKeyHex == "" {
+		settings.MasterKeyHex = randomHex(32)
+		if err := os.WriteFile(spec.MasterKeyFile, []byte(settings.MasterKeyHex+"\n"), 0400); err != nil {
+			return fmt.Errorf("failed to store the generated master key: %w", err)
+		}
+		logger.Info("🔑 Setup: Master key generated; move it offline", "file", spec.MasterKeyFile)
+	}
+
+	// 3. Secrets: sealed by the Muscle unless plaintext was asked for
+	if spec.SecretsMode != SecretsModePlaintext {
+		sealCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		keyBinding, err := Seal(sealCtx, client, &settings)
+		cancel()
+		switch {
+		case err == nil:
+			logger.Info("🔐 Setup: Secrets sealed", "key_binding", keyBinding)
+		case spec.SecretsMode == SecretsModeSealed:
+			return fmt.Errorf("this host cannot seal secrets: %w", err)
+		default:
+			logger.Warn("Setup: Sealing unavailable, writing secrets in plaintext", "error", err)
+		}
+	}
+
+	// 4. Env file, then the lock that makes it final
+	if err := WriteEnvFile(spec.EnvFile, settings); err != nil {
+		return fmt.Errorf("failed to write %s: %w", spec.EnvFile, err)
+	}
+	if err := WriteLock(spec.LockPath, spec.AdminEmail, spec.AppDomain); err != nil {
+		return fmt.Errorf("failed to write %s: %w", spec.LockPath, err)
+	}
+	logger.Info("🔒 Setup: Complete. Start (or restart) kari-api to boot in Production Mode.",
+		"domain", spec.AppDomain, "admin", spec.AdminEmail)
+	return nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand never fails on Linux
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,90 @@
+// Package setup writes the one-time production configuration: the env file
+// the Brain boots from and the setup.lock that closes the wizard. The web
+// wizard (handlers.SetupHandler) and the headless "kari-api setup" command
+// both finalize through here.
+package setup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"kari/api/internal/config"
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+const (
+	DefaultEnvFile  = "/opt/kari/.env.production"
+	DefaultLockPath = "/opt/kari/setup.lock"
+)
+
+// Secrets modes for the production env file. Empty seals when the Muscle
+// can, and falls back to plaintext (0600) when it cannot.
+const (
+	SecretsModeSealed    = "sealed"
+	SecretsModePlaintext = "plaintext"
+)
+
+// Settings are the values Finalize writes. The first three are secrets.
+type Settings struct {
+	DatabaseURL  string
+	JWTSecret    string
+	MasterKeyHex string
+	AppDomain    string
+	AdminEmail   string
+}
+
+// Seal replaces the secrets with their sealed form, returning the key they
+// are bound to ("tpm2" or "host"). On error the settings are untouched.
+func Seal(ctx context.Context, client agent.SystemAgentClient, s *Settings) (string, error) {
+	sealed, err := client.SealSecrets(ctx, &agent.SecretBundle{Values: map[string]string{
+		"DATABASE_URL":   s.DatabaseURL,
+		"JWT_SECRET":     s.JWTSecret,
+		"ENCRYPTION_KEY": s.MasterKeyHex,
+	}})
+	if err != nil {
+		return "", err
+	}
+	for key, field := range map[string]*string{
+		"DATABASE_URL":   &s.DatabaseURL,
+		"JWT_SECRET":     &s.JWTSecret,
+		"ENCRYPTION_KEY": &s.MasterKeyHex,
+	} {
+		ciphertext, ok := sealed.Values[key]
+		if !ok || ciphertext == "" {
+			return "", fmt.Errorf("the Muscle did not seal %s", key)
+		}
+		*field = config.SealedPrefix + ciphertext
+	}
+	return sealed.KeyBinding, nil
+}
+
+// WriteEnvFile writes the production env file (0600).
+func WriteEnvFile(path string, s Settings) error {
+	content := fmt.Sprintf(
+		"DATABASE_URL=%s\nJWT_SECRET=%s\nENCRYPTION_KEY=%s\nAPP_DOMAIN=%s\nADMIN_EMAIL=%s\n",
+		s.DatabaseURL,
+		s.JWTSecret,
+		s.MasterKeyHex,
+		s.AppDomain,
+		s.AdminEmail,
+	)
+	return os.WriteFile(path, []byte(content), 0600)
+}
+
+// WriteLock permanently closes the setup wizard.
+func WriteLock(path, adminEmail, appDomain string) error {
+	content := fmt.Sprintf(`{"locked_at":"%s","admin_email":"%s","domain":"%s"}`,
+		time.Now().UTC().Format(time.RFC3339),
+		adminEmail,
+		appDomain,
+	)
+	return os.WriteFile(path, []byte(content), 0444)
+}
+
+// Locked reports whether setup already ran.
+func Locked(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,302 @@
+package telemetry
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// LogFormat selects how a log stream treats terminal escape sequences.
+type LogFormat string
+
+const (
+	LogFormatANSI  LogFormat = "ansi"  // Raw bytes for xterm.js (default)
+	LogFormatHTML  LogFormat = "html"  // SGR colours become inline-styled <span>s
+	LogFormatPlain LogFormat = "plain" // Every escape sequence is stripped
+)
+
+const (
+	// MaxRenderedLineRunes caps a line in html and plain output; the rest of
+	// the line is replaced by a single ellipsis.
+	MaxRenderedLineRunes = 2000
+	// maxPendingEscape bounds an unterminated escape carried between chunks.
+	maxPendingEscape = 256
+)
+
+// ParseLogFormat reads the ?format= query value; empty means ansi.
+func ParseLogFormat(s string) (LogFormat, bool) {
+	switch LogFormat(s) {
+	case "", LogFormatANSI:
+		return LogFormatANSI, true
+	case LogFormatHTML, LogFormatPlain:
+		return LogFormat(s), true
+	}
+	return "", false
+}
+
+// LogRenderer converts one log stream chunk by chunk. It is stateful: an
+// escape sequence or UTF-8 rune split across chunks is carried over, and the
+// current colour survives into the next chunk. Use one renderer per stream.
+type LogRenderer struct {
+	format    LogFormat
+	pending   string
+	style     sgrStyle
+	spanOpen  bool
+	col       int
+	truncated bool
+}
+
+func NewLogRenderer(format LogFormat) *LogRenderer {
+	return &LogRenderer{format: format}
+}
+
+// Render returns the chunk in the renderer's format. In html mode every
+// returned fragment is balanced, so it can be appended to a page on its own.
+func (r *LogRenderer) Render(chunk string) string {
+	if r.format == LogFormatANSI || r.format == "" {
+		return chunk
+	}
+
+	s := r.pending + chunk
+	r.pending = ""
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == 0x1b:
+			end, params, final, ok := scanEscape(s, i)
+			if !ok {
+				if len(s)-i <= maxPendingEscape {
+					r.pending = s[i:]
+					i = len(s)
+					continue
+				}
+				// Too long to be a real sequence: drop the ESC and render
+				// what follows as text rather than swallow the chunk
+				i++
+				continue
+			}
+			if final == 'm' && r.format == LogFormatHTML {
+				next := r.style.apply(params)
+				if next != r.style && r.spanOpen {
+					b.WriteString("</span>")
+					r.spanOpen = false
+				}
+				r.style = next
+			}
+			i = end
+		case c == '\n':
+			b.WriteByte('\n')
+			r.col, r.truncated = 0, false
+			i++
+		case c == '\t':
+			r.writeVisible(&b, "\t")
+			i++
+		case c < 0x20 || c == 0x7f:
+			// \r (progress bars), bells and backspaces have no meaning outside a terminal
+			i++
+		default:
+			if !utf8.FullRuneInString(s[i:]) {
+				r.pending = s[i:]
+				i = len(s)
+				continue
+			}
+			_, size := utf8.DecodeRuneInString(s[i:])
+			r.writeVisible(&b, s[i:i+size])
+			i += size
+		}
+	}
+
+	if r.spanOpen {
+		b.WriteString("</span>")
+		r.spanOpen = false
+	}
+	return b.String()
+}
+
+// writeVisible emits one character, enforcing the line cap and opening a
+// styled span on demand.
+func (r *LogRenderer) writeVisible(b *strings.Builder, ch string) {
+	if r.col >= MaxRenderedLineRunes {
+		if !r.truncated {
+			b.WriteString("…")
+			r.truncated = true
+		}
+		return
+	}
+	r.col++
+
+	if r.format != LogFormatHTML {
+		b.WriteString(ch)
+		return
+	}
+	if !r.spanOpen {
+		if css := r.style.css(); css != "" {
+			b.WriteString(`<span style="` + css + `">`)
+			r.spanOpen = true
+		}
+	}
+	b.WriteString(html.EscapeString(ch))
+}
+
+// scanEscape parses the escape sequence starting at s[i]. ok is false when
+// the sequence continues past the end of s.
+func scanEscape(s string, i int) (end int, params string, final byte, ok bool) {
+	if i+1 >= len(s) {
+		return 0, "", 0, false
+	}
+	switch s[i+1] {
+	case '[': // CSI: parameter and intermediate bytes, then one final byte
+		j := i + 2
+		for j < len(s) && s[j] >= 0x20 && s[j] <= 0x3f {
+			j++
+		}
+		if j == len(s) {
+			return 0, "", 0, false
+		}
+		if s[j] < 0x40 || s[j] > 0x7e {
+			return j, "", 0, true // Malformed: drop what was read
+		}
+		return j + 1, s[i+2 : j], s[j], true
+	case ']': // OSC (titles, hyperlinks): ends with BEL or ESC \
+		for j := i + 2; j < len(s); j++ {
+			if s[j] == 0x07 {
+				return j + 1, "", 0, true
+			}
+			if s[j] == 0x1b && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 2, "", 0, true
+			}
+		}
+		return 0, "", 0, false
+	case '(', ')', '*', '+': // Character set designation takes one more byte
+		if i+2 >= len(s) {
+			return 0, "", 0, false
+		}
+		return i + 3, "", 0, true
+	}
+	return i + 2, "", 0, true
+}
+
+// ==============================================================================
+// SGR (colours and text attributes)
+// ==============================================================================
+
+type sgrStyle struct {
+	bold, dim, italic, underline bool
+	fg, bg                       string // CSS colour; empty = default
+}
+
+// ansiPalette is the 16-colour xterm palette as rendered by xterm.js.
+var ansiPalette = [16]string{
+	"#000000", "#cd3131", "#0dbc79", "#e5e510", "#2472c8", "#bc3fbc", "#11a8cd", "#e5e5e5",
+	"#666666", "#f14c4c", "#23d18b", "#f5f543", "#3b8eea", "#d670d6", "#29b8db", "#ffffff",
+}
+
+func (st sgrStyle) apply(params string) sgrStyle {
+	codes := strings.Split(params, ";")
+	for k := 0; k < len(codes); k++ {
+		n, err := strconv.Atoi(codes[k])
+		if err != nil {
+			n = 0 // An empty parameter means reset
+		}
+		switch {
+		case n == 0:
+			st = sgrStyle{}
+		case n == 1:
+			st.bold = true
+		case n == 2:
+			st.dim = true
+		case n == 3:
+			st.italic = true
+		case n == 4:
+			st.underline = true
+		case n == 22:
+			st.bold, st.dim = false, false
+		case n == 23:
+			st.italic = false
+		case n == 24:
+			st.underline = false
+		case n >= 30 && n <= 37:
+			st.fg = ansiPalette[n-30]
+		case n == 39:
+			st.fg = ""
+		case n >= 40 && n <= 47:
+			st.bg = ansiPalette[n-40]
+		case n == 49:
+			st.bg = ""
+		case n >= 90 && n <= 97:
+			st.fg = ansiPalette[n-90+8]
+		case n >= 100 && n <= 107:
+			st.bg = ansiPalette[n-100+8]
+		case n == 38 || n == 48:
+			colour, used := extendedColour(codes[k+1:])
+			k += used
+			if n == 38 {
+				st.fg = colour
+			} else {
+				st.bg = colour
+			}
+		}
+	}
+	return st
+}
+
+// extendedColour reads "5;n" (256 colours) or "2;r;g;b" (truecolour) and
+// returns the colour with the number of parameters consumed.
+func extendedColour(args []string) (string, int) {
+	if len(args) == 0 {
+		return "", 0
+	}
+	num := func(k int) int {
+		if k >= len(args) {
+			return 0
+		}
+		v, _ := strconv.Atoi(args[k])
+		return min(max(v, 0), 255)
+	}
+	switch args[0] {
+	case "5":
+		n := num(1)
+		switch {
+		case n < 16:
+			return ansiPalette[n], 2
+		case n < 232:
+			levels := [6]int{0, 95, 135, 175, 215, 255}
+			n -= 16
+			return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[n/6%6], levels[n%6]), 2
+		default:
+			g := 8 + 10*(n-232)
+			return fmt.Sprintf("#%02x%02x%02x", g, g, g), 2
+		}
+	case "2":
+		return fmt.Sprintf("#%02x%02x%02x", num(1), num(2), num(3)), min(4, len(args))
+	}
+	return "", 1
+}
+
+func (st sgrStyle) css() string {
+	var parts []string
+	if st.fg != "" {
+		parts = append(parts, "color:"+st.fg)
+	}
+	if st.bg != "" {
+		parts = append(parts, "background-color:"+st.bg)
+	}
+	if st.bold {
+		parts = append(parts, "font-weight:bold")
+	}
+	if st.dim {
+		parts = append(parts, "opacity:0.7")
+	}
+	if st.italic {
+		parts = append(parts, "font-style:italic")
+	}
+	if st.underline {
+		parts = append(parts, "text-decoration:underline")
+	}
+	return strings.Join(parts, ";")
+}
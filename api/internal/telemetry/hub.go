@@ -2,88 +2,268 @@ package telemetry
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// hubShards splits the subscriber map so broadcasts for different deployments
+// rarely contend on a lock. A power of two keeps shard selection a mask.
+const hubShards = 64
+
+// relayBuffer bounds broadcasts waiting to be relayed; past it they are
+// dropped rather than slowing a build down. relayRetry paces reconnects.
+const (
+	relayBuffer = 4096
+	relayRetry  = 5 * time.Second
 )
 
 // Hub manages active log streams for the Kari Panel.
 // 🛡️ SLA: Implements backpressure (drop-on-full) and hanging-stream cancellation.
+// 🛡️ Performance: State is sharded by deployment ID; a burst of output from one
+// build only locks its own shard, never every other build's stream.
 type Hub struct {
+	shards []hubShard
+
+	// Optional multi-replica fan-out (WithRelay)
+	relay        domain.LogRelay
+	origin       string
+	relayQueue   chan domain.LogRelayEnvelope
+	relayDropped atomic.Uint64
+}
+
+type hubShard struct {
 	mu          sync.RWMutex
-	subscribers map[string][]chan string            // deploymentID -> list of client channels
-	cancels     map[string]context.CancelFunc       // deploymentID -> cancel func for gRPC stream
+	subscribers map[string][]*subscriber      // deploymentID -> list of client channels
+	cancels     map[string]context.CancelFunc // deploymentID -> cancel func for gRPC stream
+	dropped     atomic.Uint64                 // Per shard, so drop accounting is not a global hotspot
+	_           [64]byte                      // Keeps neighbouring shards off one cache line
+}
+
+// subscriber is one UI client. dropped counts messages skipped since the
+// last successful send; the next send that fits is preceded by a marker.
+type subscriber struct {
+	ch      chan domain.LogMessage
+	dropped atomic.Int64
 }
 
 func NewHub() *Hub {
-	return &Hub{
-		subscribers: make(map[string][]chan string),
-		cancels:     make(map[string]context.CancelFunc),
+	return newHub(hubShards)
+}
+
+// newHub lets benchmarks compare shard counts (1 = the old single lock).
+func newHub(shards int) *Hub {
+	h := &Hub{shards: make([]hubShard, shards)}
+	for i := range h.shards {
+		h.shards[i].subscribers = make(map[string][]*subscriber)
+		h.shards[i].cancels = make(map[string]context.CancelFunc)
+	}
+	return h
+}
+
+// WithRelay also fans broadcasts out to other Brain replicas and delivers
+// theirs to local subscribers. Start RunRelay for it to take effect.
+func (h *Hub) WithRelay(relay domain.LogRelay) *Hub {
+	id := make([]byte, 8)
+	rand.Read(id)
+	h.relay = relay
+	h.origin = hex.EncodeToString(id)
+	h.relayQueue = make(chan domain.LogRelayEnvelope, relayBuffer)
+	return h
+}
+
+// RunRelay publishes this replica's broadcasts and delivers other replicas'
+// until ctx is done. A lost listen connection is re-established; messages
+// missed meanwhile are only in the stored log.
+func (h *Hub) RunRelay(ctx context.Context, logger *slog.Logger) {
+	pubCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	go h.publish(pubCtx, logger)
+
+	logger.Info("📡 Kari Brain: Log relay started", slog.String("origin", h.origin))
+	for {
+		err := h.relay.Listen(ctx, func(env domain.LogRelayEnvelope) {
+			if env.Origin != h.origin {
+				h.deliver(env.DeploymentID, env.Message)
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn("⚠️ Log relay listener stopped; reconnecting", slog.Any("error", err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(relayRetry):
+		}
+	}
+}
+
+// publish drains the relay queue in order. Failures are logged once per
+// outage, not once per line.
+func (h *Hub) publish(ctx context.Context, logger *slog.Logger) {
+	failing := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case env := <-h.relayQueue:
+			err := h.relay.Publish(ctx, env)
+			switch {
+			case err != nil && !failing:
+				logger.Warn("⚠️ Log relay publish failing; other replicas miss live output", slog.Any("error", err))
+			case err == nil && failing:
+				logger.Info("📡 Log relay publishing again")
+			}
+			if err != nil {
+				h.relayDropped.Add(1)
+			}
+			failing = err != nil
+		}
+	}
+}
+
+// shard picks the deployment's shard with an inline FNV-1a hash.
+func (h *Hub) shard(deploymentID string) *hubShard {
+	hash := uint32(2166136261)
+	for i := 0; i < len(deploymentID); i++ {
+		hash ^= uint32(deploymentID[i])
+		hash *= 16777619
 	}
+	return &h.shards[hash%uint32(len(h.shards))]
 }
 
 // RegisterCancel stores a cancellation function for a deployment's gRPC stream.
 // The DeploymentWorker calls this before starting the stream, enabling the Hub
 // to signal teardown when the last SSE consumer disconnects.
 func (h *Hub) RegisterCancel(deploymentID string, cancel context.CancelFunc) {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-	h.cancels[deploymentID] = cancel
+	sh := h.shard(deploymentID)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	sh.cancels[deploymentID] = cancel
 }
 
 // Subscribe adds a new UI client to a deployment log stream.
-func (h *Hub) Subscribe(deploymentID string) chan string {
-	h.mu.Lock()
-	defer h.mu.Unlock()
+func (h *Hub) Subscribe(deploymentID string) chan domain.LogMessage {
+	sh := h.shard(deploymentID)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
 
-	ch := make(chan string, 100) // Buffer to prevent slow clients from blocking the worker
-	h.subscribers[deploymentID] = append(h.subscribers[deploymentID], ch)
-	return ch
+	sub := &subscriber{ch: make(chan domain.LogMessage, 100)} // Buffer to prevent slow clients from blocking the worker
+	sh.subscribers[deploymentID] = append(sh.subscribers[deploymentID], sub)
+	return sub.ch
 }
 
 // Unsubscribe removes a client channel.
 // 🛡️ Hanging-Stream Prevention: If this was the LAST subscriber, fire the gRPC cancel
 // so the Muscle stops streaming logs to a ghost consumer.
-func (h *Hub) Unsubscribe(deploymentID string, ch chan string) {
-	h.mu.Lock()
-	defer h.mu.Unlock()
+func (h *Hub) Unsubscribe(deploymentID string, ch chan domain.LogMessage) {
+	sh := h.shard(deploymentID)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
 
-	subs := h.subscribers[deploymentID]
+	subs := sh.subscribers[deploymentID]
 	for i, sub := range subs {
-		if sub == ch {
-			h.subscribers[deploymentID] = append(subs[:i], subs[i+1:]...)
+		if sub.ch == ch {
+			sh.subscribers[deploymentID] = append(subs[:i], subs[i+1:]...)
 			close(ch)
 			break
 		}
 	}
 
 	// 🛡️ If no subscribers remain, cancel the gRPC stream to free Muscle CPU
-	if len(h.subscribers[deploymentID]) == 0 {
-		if cancel, ok := h.cancels[deploymentID]; ok {
+	if len(sh.subscribers[deploymentID]) == 0 {
+		if cancel, ok := sh.cancels[deploymentID]; ok {
 			cancel()
-			delete(h.cancels, deploymentID)
+			delete(sh.cancels, deploymentID)
 		}
-		delete(h.subscribers, deploymentID)
+		delete(sh.subscribers, deploymentID)
 	}
 }
 
 // HasSubscribers returns true if at least one UI client is listening.
 func (h *Hub) HasSubscribers(deploymentID string) bool {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-	return len(h.subscribers[deploymentID]) > 0
+	sh := h.shard(deploymentID)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	return len(sh.subscribers[deploymentID]) > 0
 }
 
-// Broadcast sends a log chunk to all listeners of a deployment.
+// Dropped returns how many messages slow subscribers have missed since start,
+// plus broadcasts that could not be relayed to other replicas.
+func (h *Hub) Dropped() uint64 {
+	total := h.relayDropped.Load()
+	for i := range h.shards {
+		total += h.shards[i].dropped.Load()
+	}
+	return total
+}
+
+// Broadcast sends a typed log message to all listeners of a deployment.
 // 🛡️ SLA: Uses select+default to drop messages for slow clients (backpressure).
-func (h *Hub) Broadcast(deploymentID string, message string) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-
-	if subs, ok := h.subscribers[deploymentID]; ok {
-		for _, ch := range subs {
-			select {
-			case ch <- message:
-			default: // Drop message if buffer is full to preserve SLA stability
+// A client that missed messages first receives a "N lines skipped" marker, so
+// a gap in its terminal is visible instead of silent.
+func (h *Hub) Broadcast(deploymentID string, message domain.LogMessage) {
+	h.deliver(deploymentID, message)
+
+	if h.relay != nil {
+		select {
+		case h.relayQueue <- domain.LogRelayEnvelope{Origin: h.origin, DeploymentID: deploymentID, Message: message}:
+		default:
+			h.relayDropped.Add(1)
+		}
+	}
+}
+
+// deliver fans a message out to this replica's subscribers only.
+func (h *Hub) deliver(deploymentID string, message domain.LogMessage) {
+	sh := h.shard(deploymentID)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+
+	for _, sub := range sh.subscribers[deploymentID] {
+		if len(sub.ch) == cap(sub.ch) {
+			// Fast path for a stalled client: no marker is built only to be dropped
+			sub.dropped.Add(1)
+			sh.dropped.Add(1)
+			continue
+		}
+		if n := sub.dropped.Swap(0); n > 0 {
+			if !sub.offer(skippedMarker(message, n)) {
+				sub.dropped.Add(n + 1) // Still full: this message is lost too
+				sh.dropped.Add(1)
+				continue
 			}
 		}
+		if !sub.offer(message) {
+			sub.dropped.Add(1)
+			sh.dropped.Add(1)
+		}
+	}
+}
+
+// offer sends without blocking the worker.
+func (s *subscriber) offer(msg domain.LogMessage) bool {
+	select {
+	case s.ch <- msg:
+		return true
+	default: // Drop message if buffer is full to preserve SLA stability
+		return false
+	}
+}
+
+func skippedMarker(next domain.LogMessage, n int64) domain.LogMessage {
+	return domain.LogMessage{
+		Level:     domain.LogLevelWarn,
+		Stage:     next.Stage,
+		Timestamp: next.Timestamp,
+		Content:   fmt.Sprintf("\r\n⚠️ Kari Panel: %d lines skipped (connection too slow; download the full log)\r\n", n),
+		Skipped:   n,
 	}
 }
@@ -0,0 +1,192 @@
+package telemetry
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"kari/api/internal/core/domain"
+)
+
+// Load profile the Hub is sized for: 100 concurrent builds, 50 browser tabs each.
+const (
+	benchBuilds      = 100
+	benchSubscribers = 50
+)
+
+// attach subscribes benchSubscribers draining clients to every build. The
+// returned detach unsubscribes them, waits for the drains and reports how
+// many messages they received.
+func attach(h *Hub, ids []string) (detach func() uint64) {
+	var wg sync.WaitGroup
+	var received atomic.Uint64
+	type sub struct {
+		id string
+		ch chan domain.LogMessage
+	}
+	var subs []sub
+	for _, id := range ids {
+		for i := 0; i < benchSubscribers; i++ {
+			ch := h.Subscribe(id)
+			subs = append(subs, sub{id, ch})
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range ch {
+					received.Add(1)
+				}
+			}()
+		}
+	}
+	return func() uint64 {
+		for _, s := range subs {
+			h.Unsubscribe(s.id, s.ch)
+		}
+		wg.Wait()
+		return received.Load()
+	}
+}
+
+// benchmarkBroadcast streams from every build at once and reports messages
+// actually received by subscribers, plus the share the Hub had to drop.
+func benchmarkBroadcast(b *testing.B, shards int, churn bool) {
+	h := newHub(shards)
+	ids := make([]string, benchBuilds)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("00000000-0000-0000-0000-%012d", i)
+	}
+	detach := attach(h, ids)
+
+	// Browser tabs opening and closing take the write lock throughout the run
+	stop := make(chan struct{})
+	if churn {
+		go func() {
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				id := ids[i%benchBuilds]
+				h.Unsubscribe(id, h.Subscribe(id))
+			}
+		}()
+	}
+
+	msg := domain.NewLogMessage(domain.LogStageBuild, domain.LogLevelInfo, "Compiling kari v1.0.0\n")
+	var next atomic.Uint64
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	// Each goroutine plays one build worker streaming its own deployment
+	b.RunParallel(func(pb *testing.PB) {
+		id := ids[next.Add(1)%benchBuilds]
+		for pb.Next() {
+			h.Broadcast(id, msg)
+		}
+	})
+	b.StopTimer()
+	close(stop)
+
+	received := detach()
+	b.ReportMetric(float64(received)/b.Elapsed().Seconds(), "received/s")
+	b.ReportMetric(100*float64(h.Dropped())/float64(b.N*benchSubscribers), "%dropped")
+}
+
+// Compare with: go test -run=^$ -bench=HubBroadcast -cpu=1,4,16 ./internal/telemetry/
+func BenchmarkHubBroadcastSingleLock(b *testing.B)      { benchmarkBroadcast(b, 1, false) }
+func BenchmarkHubBroadcastSharded(b *testing.B)         { benchmarkBroadcast(b, hubShards, false) }
+func BenchmarkHubBroadcastChurnSingleLock(b *testing.B) { benchmarkBroadcast(b, 1, true) }
+func BenchmarkHubBroadcastChurnSharded(b *testing.B)    { benchmarkBroadcast(b, hubShards, true) }
+
+// Every subscriber stalled: isolates the Hub's own locking and accounting
+// from channel delivery, which dominates the benchmarks above.
+func benchmarkStalled(b *testing.B, shards int) {
+	h := newHub(shards)
+	ids := make([]string, benchBuilds)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("00000000-0000-0000-0000-%012d", i)
+		for j := 0; j < benchSubscribers; j++ {
+			ch := h.Subscribe(ids[i])
+			for len(ch) < cap(ch) {
+				ch <- domain.LogMessage{}
+			}
+		}
+	}
+	var next atomic.Uint64
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		id := ids[next.Add(1)%benchBuilds]
+		for pb.Next() {
+			h.Broadcast(id, domain.LogMessage{Content: "x"})
+		}
+	})
+}
+
+func BenchmarkHubStalledSingleLock(b *testing.B) { benchmarkStalled(b, 1) }
+func BenchmarkHubStalledSharded(b *testing.B)    { benchmarkStalled(b, hubShards) }
+
+// Subscribing and leaving must not stall other builds' broadcasts.
+func BenchmarkHubSubscribeChurn(b *testing.B) {
+	h := NewHub()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			id := fmt.Sprintf("churn-%d", i%benchBuilds)
+			ch := h.Subscribe(id)
+			h.Broadcast(id, domain.LogMessage{Content: "x"})
+			h.Unsubscribe(id, ch)
+			i++
+		}
+	})
+}
+
+func TestHubSkippedMarker(t *testing.T) {
+	h := NewHub()
+	ch := h.Subscribe("d1")
+	for i := 0; i < 150; i++ {
+		h.Broadcast("d1", domain.LogMessage{Content: "line\n"})
+	}
+	for i := 0; i < 100; i++ {
+		<-ch
+	}
+
+	h.Broadcast("d1", domain.LogMessage{Content: "after\n"})
+	if marker := <-ch; marker.Skipped != 50 || marker.Level != domain.LogLevelWarn {
+		t.Fatalf("expected a warn marker for 50 skipped lines, got %+v", marker)
+	}
+	if got := <-ch; got.Content != "after\n" {
+		t.Fatalf("expected the message after the marker, got %q", got.Content)
+	}
+	if h.Dropped() != 50 {
+		t.Fatalf("expected 50 dropped messages in total, got %d", h.Dropped())
+	}
+}
+
+func TestHubShardsIsolateDeployments(t *testing.T) {
+	h := NewHub()
+	a, b := h.Subscribe("a"), h.Subscribe("b")
+	h.Broadcast("a", domain.LogMessage{Content: "for a"})
+
+	if got := <-a; got.Content != "for a" {
+		t.Fatalf("unexpected message %q", got.Content)
+	}
+	select {
+	case got := <-b:
+		t.Fatalf("deployment b received a's message %q", got.Content)
+	default:
+	}
+
+	cancelled := false
+	h.RegisterCancel("a", func() { cancelled = true })
+	h.Unsubscribe("a", a)
+	if !cancelled || h.HasSubscribers("a") {
+		t.Fatal("last unsubscribe must cancel the stream and forget the deployment")
+	}
+	if !h.HasSubscribers("b") {
+		t.Fatal("unsubscribing a must not affect b")
+	}
+}
@@ -0,0 +1,130 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"kari/api/internal/core/domain"
+)
+
+// LogRing keeps the last N log records in memory so a crash report can show
+// what the Brain was doing right before a panic.
+// 🛡️ SLA: Fixed size; recording is a copy under a mutex, never I/O.
+type LogRing struct {
+	mu      sync.Mutex
+	entries []domain.CrashLogLine
+	next    int
+	full    bool
+}
+
+func NewLogRing(size int) *LogRing {
+	if size <= 0 {
+		size = 1
+	}
+	return &LogRing{entries: make([]domain.CrashLogLine, size)}
+}
+
+// Wrap returns a slog.Handler that records into the ring, then delegates.
+func (r *LogRing) Wrap(next slog.Handler) slog.Handler {
+	return &ringHandler{ring: r, next: next}
+}
+
+// Recent returns the buffered records, oldest first.
+func (r *LogRing) Recent() []domain.CrashLogLine {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]domain.CrashLogLine(nil), r.entries[:r.next]...)
+	}
+	out := make([]domain.CrashLogLine, 0, len(r.entries))
+	out = append(out, r.entries[r.next:]...)
+	return append(out, r.entries[:r.next]...)
+}
+
+func (r *LogRing) push(e domain.CrashLogLine) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = e
+	r.next++
+	if r.next == len(r.entries) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// ==============================================================================
+// slog.Handler
+// ==============================================================================
+
+type ringHandler struct {
+	ring   *LogRing
+	next   slog.Handler
+	attrs  []slog.Attr // From WithAttrs, keys already group-prefixed
+	prefix string      // From WithGroup, e.g. "request."
+}
+
+func (h *ringHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *ringHandler) Handle(ctx context.Context, rec slog.Record) error {
+	entry := domain.CrashLogLine{
+		Time:    rec.Time,
+		Level:   rec.Level.String(),
+		Message: rec.Message,
+	}
+	if len(h.attrs) > 0 || rec.NumAttrs() > 0 {
+		entry.Attrs = make(map[string]any, len(h.attrs)+rec.NumAttrs())
+		for _, a := range h.attrs {
+			entry.Attrs[a.Key] = attrValue(a.Value)
+		}
+		rec.Attrs(func(a slog.Attr) bool {
+			entry.Attrs[h.prefix+a.Key] = attrValue(a.Value)
+			return true
+		})
+	}
+	h.ring.push(entry)
+
+	return h.next.Handle(ctx, rec)
+}
+
+func (h *ringHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := *h
+	next.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		next.attrs = append(next.attrs, slog.Attr{Key: h.prefix + a.Key, Value: a.Value})
+	}
+	next.next = h.next.WithAttrs(attrs)
+	return &next
+}
+
+func (h *ringHandler) WithGroup(name string) slog.Handler {
+	next := *h
+	next.prefix = h.prefix + name + "."
+	next.next = h.next.WithGroup(name)
+	return &next
+}
+
+// attrValue renders a value so it survives JSON encoding; errors would
+// otherwise marshal as {}.
+func attrValue(v slog.Value) any {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := make(map[string]any, len(v.Group()))
+		for _, a := range v.Group() {
+			group[a.Key] = attrValue(a.Value)
+		}
+		return group
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+		return v.Any()
+	default:
+		return v.Any()
+	}
+}
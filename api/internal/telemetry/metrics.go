@@ -0,0 +1,26 @@
+package telemetry
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsHandler serves the default Prometheus registry to scrapers that
+// present the bearer token (Prometheus: authorization.credentials).
+// 🛡️ Zero-Trust: Metrics reveal traffic and capacity; they are never public.
+func MetricsHandler(token string) http.Handler {
+	metrics := promhttp.Handler()
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(strings.TrimSpace(r.Header.Get("Authorization")))
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="kari-metrics"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		metrics.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,36 @@
+// Package version exposes build metadata injected at link time:
+//
+//	go build -ldflags "-X kari/api/internal/version.Version=1.4.0"
+package version
+
+// Version is the semantic version of this kari-api build ("dev" for local builds).
+var Version = "dev"
+
+// Brain<->Muscle protocol revisions this Brain can speak. Negotiation settles on
+// the highest revision both sides support; see services.AgentCompatService.
+//
+//	1: baseline RPC surface
+//	2: ApplyBrainUpdate (self-update)
+//	3: PHP-FPM runtime (DeployRequest.runtime / php_max_children)
+//	4: Runtime version pinning (DeployRequest.runtime_version toolchains)
+//	5: PullImage with registry credentials
+//	6: Multi-process apps (DeployRequest.processes, GetProcessStatus)
+//	7: Horizontal web instances (DeployRequest.instances, per-instance health)
+//	8: RestartApp (hard or graceful, connection-draining restarts)
+//	9: ScanAppFiles (integrity baselines, ClamAV)
+//	10: ApplySecurityHeaders (per-domain response headers)
+//	11: CollectAccessLogs (per-domain access analytics)
+//	12: SetBandwidthLimit, AccessLogBucket.bytes_received (transfer quotas)
+//	13: ProcessState.cpu_usage_nsec (usage metering)
+//	14: ListManagedResources, RemoveManagedResource (drift reconciliation)
+//	15: x-kari-change-id metadata (outbox redeliveries acknowledged, not re-applied)
+//	16: DeployRequest.build_env (build-only environment, never in the units)
+//	17: Build artifacts (DeployRequest.artifact_id / reuse_artifact, DeleteArtifacts)
+//	18: LogChunk.sbom (dependency catalog of each built release)
+//	19: GetCapabilities (container runtime, PHP versions, quotas, firewall)
+//	20: ApplySystemDefaults (SystemProfile ceilings, default firewall policy)
+//	21: SealSecrets, UnsealSecrets (machine-bound env file secrets)
+const (
+	AgentProtocolMin uint32 = 1
+	AgentProtocolMax uint32 = 21
+)
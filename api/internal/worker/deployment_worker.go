@@ -2,11 +2,14 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"kari/api/internal/core/domain"
 	"kari/api/internal/core/domain"
 	"kari/api/proto/agent" // Generated gRPC client
@@ -14,7 +17,23 @@ import (
 
 // Broadcaster abstracts the telemetry hub for dependency inversion
 type Broadcaster interface {
-	Broadcast(deploymentID string, message string)
+	Broadcast(deploymentID string, message domain.LogMessage)
+}
+
+// QueueNotifier signals newly queued deployments (Postgres LISTEN/NOTIFY).
+type QueueNotifier interface {
+	Listen(ctx context.Context, wake func()) error
+}
+
+// LogScrubber removes secrets and PII from build output before it is stored or streamed
+type LogScrubber interface {
+	RedactAppLog(ctx context.Context, appID string, text string) string
+}
+
+// LogSpool holds log messages on disk while the database rejects writes
+type LogSpool interface {
+	Holds(deploymentID string) bool
+	Append(deploymentID string, msg domain.LogMessage) error
 }
 
 // DeploymentWorker orchestrates the lifecycle of an application deployment.
@@ -26,6 +45,21 @@ type DeploymentWorker struct {
 	hub          Broadcaster
 	logger       *slog.Logger
 	pollInterval time.Duration
+	heartbeats   domain.HeartbeatRecorder
+	scrubber     LogScrubber
+	spool        LogSpool
+	webhooks     domain.WebhookEmitter
+	maintenance  domain.MaintenanceSchedule
+	artifacts    domain.ArtifactRepository
+	sboms        domain.SBOMRepository
+	notifier     QueueNotifier
+	wake         chan struct{}
+
+	// Shutdown drain: claims stop once draining is set; Drain waits on inflight
+	mu       sync.Mutex
+	draining bool
+	inflight sync.WaitGroup
+	abort    context.CancelFunc // Cancels the in-flight deployment's context
 }
 
 // NewDeploymentWorker initializes the background processor with necessary dependencies.
@@ -43,28 +77,169 @@ func NewDeploymentWorker(
 		hub:          hub,
 		logger:       logger,
 		pollInterval: 5 * time.Second,
+		wake:         make(chan struct{}, 1),
 	}
 }
 
+// WithHeartbeats reports each completed poll to /health/ready.
+func (w *DeploymentWorker) WithHeartbeats(rec domain.HeartbeatRecorder) *DeploymentWorker {
+	rec.Register("deployment_worker", w.pollInterval)
+	w.heartbeats = rec
+	return w
+}
+
+// WithScrubber redacts the Muscle's output, a whole line at a time, before persistence and broadcast.
+func (w *DeploymentWorker) WithScrubber(s LogScrubber) *DeploymentWorker {
+	w.scrubber = s
+	return w
+}
+
+// WithSpool makes log persistence lossless: writes the database rejects are
+// spooled to disk and replayed later instead of being dropped.
+func (w *DeploymentWorker) WithSpool(s LogSpool) *DeploymentWorker {
+	w.spool = s
+	return w
+}
+
+// WithWebhooks emits deployment.succeeded / deployment.failed to the app owner's webhooks.
+func (w *DeploymentWorker) WithWebhooks(e domain.WebhookEmitter) *DeploymentWorker {
+	w.webhooks = e
+	return w
+}
+
+// WithMaintenance leaves PENDING deployments queued while a maintenance
+// window with pause_deploys is active; they are claimed once it ends.
+func (w *DeploymentWorker) WithMaintenance(s domain.MaintenanceSchedule) *DeploymentWorker {
+	w.maintenance = s
+	return w
+}
+
+// WithArtifacts has the Muscle archive every build for rebuild-free
+// redeploys, and unpacks the stored release for deployments queued as one.
+func (w *DeploymentWorker) WithArtifacts(repo domain.ArtifactRepository) *DeploymentWorker {
+	w.artifacts = repo
+	return w
+}
+
+// WithSBOMs records the dependency catalog the Muscle produces for each build.
+func (w *DeploymentWorker) WithSBOMs(repo domain.SBOMRepository) *DeploymentWorker {
+	w.sboms = repo
+	return w
+}
+
+// WithWakeups claims a deployment as soon as it is queued instead of at the
+// next poll. Polling continues as the fallback for missed notifications.
+func (w *DeploymentWorker) WithWakeups(n QueueNotifier) *DeploymentWorker {
+	w.notifier = n
+	return w
+}
+
 // Start initiates the non-blocking polling loop.
 func (w *DeploymentWorker) Start(ctx context.Context) {
 	w.logger.Info("🚀 Kari Brain: Deployment Worker started.")
 	ticker := time.NewTicker(w.pollInterval)
 	defer ticker.Stop()
 
+	// 🛡️ A deployment in flight outlives ctx: shutdown goes through Drain,
+	// which only aborts it once its own deadline has passed
+	taskCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	defer abort()
+	w.mu.Lock()
+	w.abort = abort
+	w.mu.Unlock()
+
+	if w.notifier != nil {
+		listenCtx, stop := context.WithCancel(ctx)
+		defer stop()
+		go w.listen(listenCtx)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			w.logger.Info("🛑 Kari Brain: Deployment Worker shutting down...")
 			return
 		case <-ticker.C:
-			w.processNextTask(ctx)
+		case <-w.wake:
+		}
+		w.processNextTask(taskCtx)
+		if w.heartbeats != nil {
+			w.heartbeats.Beat("deployment_worker")
+		}
+	}
+}
+
+// Drain stops claiming deployments and waits for the one in flight to finish.
+// When ctx ends first, that deployment is aborted and recorded as failed.
+func (w *DeploymentWorker) Drain(ctx context.Context) error {
+	w.mu.Lock()
+	w.draining = true
+	abort := w.abort
+	w.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	w.logger.Warn("⚠️ Kari Brain: Drain deadline passed; aborting the in-flight deployment")
+	if abort != nil {
+		abort()
+	}
+	// Long enough to record the failure, short enough not to hold up the exit
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+	}
+	return ctx.Err()
+}
+
+// signal wakes the loop without blocking; pending wakeups coalesce.
+func (w *DeploymentWorker) signal() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// listen turns queue notifications into wakeups, reconnecting after a poll
+// interval when the listen session drops.
+func (w *DeploymentWorker) listen(ctx context.Context) {
+	for {
+		err := w.notifier.Listen(ctx, w.signal)
+		if ctx.Err() != nil {
+			return
+		}
+		w.logger.Warn("⚠️ Kari Brain: Deployment queue listener stopped; polling until it reconnects", slog.Any("error", err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.pollInterval):
 		}
 	}
 }
 
 // processNextTask handles the transition from PENDING to SUCCESS/FAILED.
 func (w *DeploymentWorker) processNextTask(ctx context.Context) {
+	if w.maintenance != nil && w.maintenance.DeploysPaused(time.Now()) {
+		return
+	}
+	// Registered before claiming, so Drain never misses a deployment it must wait for
+	w.mu.Lock()
+	if w.draining {
+		w.mu.Unlock()
+		return
+	}
+	w.inflight.Add(1)
+	w.mu.Unlock()
+	defer w.inflight.Done()
+
 	// 1. 🛡️ Claim Task: Atomic 'FOR UPDATE SKIP LOCKED' via repository
 	deployment, err := w.repo.ClaimNextPending(ctx)
 	if err != nil {
@@ -74,8 +249,14 @@ func (w *DeploymentWorker) processNextTask(ctx context.Context) {
 	if deployment == nil {
 		return // No tasks available
 	}
+	// More may be queued behind this one (one wakeup covers a burst): look again right after
+	w.signal()
+
+	// Lines are persisted in batches; every exit path below flushes them
+	logs := w.newLogBatcher(ctx, deployment.ID)
+	defer logs.Close()
 
-	w.hub.Broadcast(deployment.ID, "🚀 Kari Panel: Initializing deployment engine...\n")
+	w.hub.Broadcast(deployment.ID, domain.NewLogMessage(domain.LogStageInit, domain.LogLevelInfo, "🚀 Kari Panel: Initializing deployment engine...\n"))
 
 	// 2. 🛡️ Zero-Trust: Decrypt SSH Key (Transient Memory Only)
 	var sshKey string
@@ -83,7 +264,7 @@ func (w *DeploymentWorker) processNextTask(ctx context.Context) {
 		// AssociatedData binds this key to the specific AppID for tamper protection
 		decrypted, err := w.crypto.Decrypt(ctx, deployment.EncryptedSSHKey, []byte(deployment.AppID))
 		if err != nil {
-			w.failDeployment(ctx, deployment, fmt.Errorf("security: failed to decrypt deploy key: %w", err))
+			w.failDeployment(ctx, deployment, logs, fmt.Errorf("security: failed to decrypt deploy key: %w", err))
 			return
 		}
 		sshKey = string(decrypted)
@@ -103,7 +284,7 @@ func (w *DeploymentWorker) processNextTask(ctx context.Context) {
 	w.hub.RegisterCancel(deployment.ID, streamCancel)
 
 	port := int32(deployment.TargetPort)
-	stream, err := w.agent.StreamDeployment(streamCtx, &agent.DeployRequest{
+	req := &agent.DeployRequest{
 		AppId:        deployment.AppID,
 		DomainName:   deployment.DomainName,
 		RepoUrl:      deployment.RepoURL,
@@ -112,30 +293,74 @@ func (w *DeploymentWorker) processNextTask(ctx context.Context) {
 		Port:         &port,
 		SshKey:       &sshKey,
 		TraceId:      deployment.ID,
-	})
+	}
+	if w.artifacts != nil {
+		// 📦 Redeploys unpack their source's release; everything else is archived after the build
+		src, err := w.artifacts.RedeploySource(ctx, deployment.ID)
+		switch {
+		case err == nil && src.PrunedAt != nil:
+			w.failDeployment(ctx, deployment, logs, fmt.Errorf("%w: artifact %s was pruned", domain.ErrConflict, src.DeploymentID))
+			return
+		case err == nil:
+			req.ReuseArtifact = &agent.ArtifactRef{Id: src.DeploymentID, Sha256: src.SHA256}
+		case errors.Is(err, domain.ErrNotFound):
+			req.ArtifactId = deployment.ID
+		default:
+			w.failDeployment(ctx, deployment, logs, fmt.Errorf("database: failed to resolve redeploy source: %w", err))
+			return
+		}
+	}
+	stream, err := w.agent.StreamDeployment(streamCtx, req)
 
 	if err != nil {
-		w.failDeployment(ctx, deployment, fmt.Errorf("network: agent unreachable: %w", err))
+		w.failDeployment(ctx, deployment, logs, fmt.Errorf("network: agent unreachable: %w", err))
 		return
 	}
 
 	// 4. 🚰 Telemetry Loop: Pipe logs from Agent -> DB & Hub
+	// 🛡️ Chunks are cut into whole lines (whole key blocks) before scrubbing,
+	// so a secret split across two chunks is still caught
+	redactor := newLogRedactor(nil)
+	if w.scrubber != nil {
+		redactor.scrub = func(text string) string {
+			return w.scrubber.RedactAppLog(ctx, deployment.AppID, text)
+		}
+	}
+	// 🛡️ SLA Visibility: Concurrent persistence and real-time broadcast
+	// Logging never fails the deployment; rejected writes are spooled when a spool is attached.
+	emitLog := func(content string) {
+		if content == "" {
+			return
+		}
+		msg := domain.NewLogMessage(domain.LogStageBuild, "", content)
+		logs.Add(msg)
+		w.hub.Broadcast(deployment.ID, msg)
+	}
 	for {
 		chunk, err := stream.Recv()
 		if err == io.EOF {
+			emitLog(redactor.Flush())
 			break // Deployment finished successfully
 		}
 		if err != nil {
-			w.failDeployment(ctx, deployment, fmt.Errorf("execution: stream interrupted: %w", err))
+			emitLog(redactor.Flush())
+			w.failDeployment(ctx, deployment, logs, fmt.Errorf("execution: stream interrupted: %w", err))
 			return
 		}
 
-		// 🛡️ SLA Visibility: Concurrent persistence and real-time broadcast
-		// We ignore errors on logging to ensure the deployment continues even if DB is under load.
-		_ = w.repo.AppendLog(ctx, deployment.ID, chunk.Content)
-		w.hub.Broadcast(deployment.ID, chunk.Content)
+		emitLog(redactor.Write(chunk.Content))
+
+		if chunk.Artifact != nil && w.artifacts != nil {
+			w.recordArtifact(ctx, deployment, chunk.Artifact)
+		}
+		if chunk.Sbom != nil && w.sboms != nil {
+			w.recordSBOM(ctx, deployment, chunk.Sbom)
+		}
 	}
 
+	// Every build line is written before the status flips to SUCCESS
+	logs.Close()
+
 	// 5. ✅ Finalize: Update state to Success
 	if err := w.repo.UpdateStatus(ctx, deployment.ID, domain.StatusSuccess); err != nil {
 		w.logger.Error("❌ Kari Panel: Failed to update success status",
@@ -144,14 +369,60 @@ func (w *DeploymentWorker) processNextTask(ctx context.Context) {
 		return
 	}
 
-	w.hub.Broadcast(deployment.ID, "✅ Kari Panel: Deployment successful. Service is live.\n")
+	w.hub.Broadcast(deployment.ID, domain.NewLogMessage(domain.LogStageComplete, domain.LogLevelInfo, "✅ Kari Panel: Deployment successful. Service is live.\n"))
+	w.emit(ctx, deployment, domain.WebhookDeploymentSucceeded, nil)
+}
+
+// recordArtifact stores the Muscle's checksum of the archived release. A
+// failure only costs the redeploy option, never the deployment.
+func (w *DeploymentWorker) recordArtifact(ctx context.Context, d *domain.Deployment, info *agent.ArtifactInfo) {
+	err := w.artifacts.Record(ctx, &domain.DeploymentArtifact{
+		DeploymentID: d.ID,
+		AppID:        d.AppID,
+		SHA256:       info.Sha256,
+		SizeBytes:    int64(info.SizeBytes),
+	})
+	if err != nil {
+		w.logger.Warn("⚠️ Failed to record build artifact",
+			slog.String("deployment_id", d.ID),
+			slog.Any("error", err))
+	}
+}
+
+// recordSBOM stores the build's dependency catalog. A failure only leaves the
+// app out of vulnerability scans until its next build.
+func (w *DeploymentWorker) recordSBOM(ctx context.Context, d *domain.Deployment, report *agent.SbomReport) {
+	components := make([]domain.SBOMComponent, 0, len(report.Components))
+	for _, c := range report.Components {
+		components = append(components, domain.SBOMComponent{
+			Name:     c.Name,
+			Version:  c.Version,
+			PURL:     c.Purl,
+			Licenses: c.Licenses,
+		})
+	}
+	err := w.sboms.Record(ctx, &domain.SBOM{
+		DeploymentID: d.ID,
+		AppID:        d.AppID,
+		Generator:    report.Generator,
+		Components:   components,
+		Truncated:    report.Truncated,
+	})
+	if err != nil {
+		w.logger.Warn("⚠️ Failed to record SBOM",
+			slog.String("deployment_id", d.ID),
+			slog.Any("error", err))
+	}
 }
 
 // failDeployment handles cleanup and telemetry updates for failed builds.
 // 🛡️ Zero-Trust: Raw Muscle errors are classified into UI-safe codes before broadcast.
-func (w *DeploymentWorker) failDeployment(ctx context.Context, d *domain.Deployment, err error) {
+func (w *DeploymentWorker) failDeployment(ctx context.Context, d *domain.Deployment, logs *logBatcher, err error) {
+	// An aborted drain cancels ctx; the failure must still be recorded
+	ctx = context.WithoutCancel(ctx)
+
 	// 1. Classify the raw error into a human-readable, UI-safe structure
-	agentErr := domain.ClassifyAgentError(err.Error())
+	agentErr := domain.AsAgentError(err)
 
 	// 2. Log the RAW error server-side for forensic analysis (never sent to browser)
 	w.logger.Error("❌ Deployment failed",
@@ -168,7 +439,51 @@ func (w *DeploymentWorker) failDeployment(ctx context.Context, d *domain.Deploym
 		terminalMsg = fmt.Sprintf("\r\n\x1b[33m[%s] %s\x1b[0m\r\n\x1b[33m  → %s\x1b[0m\r\n", agentErr.Code, agentErr.Title, agentErr.Message)
 	}
 
-	_ = w.repo.AppendLog(ctx, d.ID, terminalMsg)
-	w.hub.Broadcast(d.ID, terminalMsg)
+	msg := domain.NewLogMessage(domain.LogStageFailed, domain.LogLevelError, terminalMsg)
+	logs.Add(msg)
+	logs.Close()
+	w.hub.Broadcast(d.ID, msg)
 	_ = w.repo.UpdateStatus(ctx, d.ID, domain.StatusFailed)
+	w.emit(ctx, d, domain.WebhookDeploymentFailed, &agentErr)
+}
+
+// emit raises a deployment webhook event. Only the UI-safe classified error
+// is included, never the raw Muscle output.
+func (w *DeploymentWorker) emit(ctx context.Context, d *domain.Deployment, event string, agentErr *domain.AgentError) {
+	if w.webhooks == nil {
+		return
+	}
+	appID, err := uuid.Parse(d.AppID)
+	if err != nil {
+		return
+	}
+	data := map[string]any{
+		"deployment_id": d.ID,
+		"app_id":        d.AppID,
+		"domain_name":   d.DomainName,
+	}
+	if agentErr != nil {
+		data["error_code"] = agentErr.Code
+		data["error_title"] = agentErr.Title
+	}
+	w.webhooks.EmitForApp(ctx, appID, event, data)
+}
+
+// persistLogs stores a batch of log messages without ever failing the
+// deployment on the database. With a spool, a deployment that has spilled
+// keeps spooling until the backlog is replayed, so rows land in the order
+// they were produced.
+func (w *DeploymentWorker) persistLogs(ctx context.Context, deploymentID string, msgs []domain.LogMessage) {
+	if w.spool == nil || !w.spool.Holds(deploymentID) {
+		err := w.repo.AppendLogs(ctx, deploymentID, msgs)
+		if err == nil || w.spool == nil {
+			return // Without a spool, logging errors are ignored so the deployment continues
+		}
+	}
+
+	for _, msg := range msgs {
+		if err := w.spool.Append(deploymentID, msg); err != nil {
+			w.logger.Error("❌ Build log line lost", slog.String("deployment_id", deploymentID), slog.Any("error", err))
+		}
+	}
 }
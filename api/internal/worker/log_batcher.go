@@ -0,0 +1,93 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+const (
+	// logBatchSize caps the lines per INSERT (5 params each, far below Postgres' 65535).
+	logBatchSize = 200
+	// logFlushInterval bounds how long a quiet build's lines wait in memory,
+	// and so how much log a crash of the Brain can lose.
+	logFlushInterval = 500 * time.Millisecond
+)
+
+// logBatcher buffers one deployment's log lines and persists them in
+// multi-row INSERTs. It flushes when the buffer fills, on a timer so quiet
+// builds still reach the database, and when the stream ends.
+// 🛡️ Ordering: Flushes are serialized under mu, so batches land in the order
+// their lines were produced, whether they go to Postgres or the spool.
+type logBatcher struct {
+	w            *DeploymentWorker
+	ctx          context.Context
+	deploymentID string
+
+	mu  sync.Mutex
+	buf []domain.LogMessage
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func (w *DeploymentWorker) newLogBatcher(ctx context.Context, deploymentID string) *logBatcher {
+	b := &logBatcher{
+		w:            w,
+		ctx:          context.WithoutCancel(ctx), // An aborted drain must not lose the final lines
+		deploymentID: deploymentID,
+		buf:          make([]domain.LogMessage, 0, logBatchSize),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (b *logBatcher) run() {
+	defer close(b.done)
+	ticker := time.NewTicker(logFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			b.flushLocked()
+			b.mu.Unlock()
+		}
+	}
+}
+
+// Add queues a message, flushing synchronously once the batch is full.
+func (b *logBatcher) Add(msg domain.LogMessage) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, msg)
+	if len(b.buf) >= logBatchSize {
+		b.flushLocked()
+	}
+}
+
+// Close stops the timer and writes whatever is still buffered. It is safe to
+// call more than once; later calls only flush lines added since.
+func (b *logBatcher) Close() {
+	b.stopOnce.Do(func() { close(b.stop) })
+	<-b.done
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.flushLocked()
+}
+
+func (b *logBatcher) flushLocked() {
+	if len(b.buf) == 0 {
+		return
+	}
+	b.w.persistLogs(b.ctx, b.deploymentID, b.buf)
+	b.buf = b.buf[:0]
+}
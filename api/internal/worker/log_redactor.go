@@ -0,0 +1,118 @@
+package worker
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxPendingLine flushes a line that never ends (a progress bar without
+	// newlines) instead of holding the rest of the build back.
+	maxPendingLine = 16 << 10
+	// maxHeldBlock caps an open PRIVATE KEY block; past it the block is
+	// replaced, not released, and dropped until its END line.
+	maxHeldBlock = 64 << 10
+)
+
+var (
+	keyBlockBegin = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)
+	keyBlockEnd   = regexp.MustCompile(`-----END [A-Z ]*PRIVATE KEY-----`)
+)
+
+// redactedKeyBlock stands in for a block the scrubber never saw whole.
+const redactedKeyBlock = "[REDACTED PRIVATE KEY]\n"
+
+// logRedactor cuts one deployment's output stream into whole lines before it
+// is scrubbed. The Muscle's chunks split anywhere, so a secret can straddle
+// two of them; a PRIVATE KEY block spans many lines and is held until its
+// END line, so the scrubber always matches it in one piece.
+// Not safe for concurrent use: one per deployment, on its Recv loop.
+type logRedactor struct {
+	scrub   func(string) string
+	partial strings.Builder // Text after the last newline
+	block   strings.Builder // Lines of an open PRIVATE KEY block
+	inBlock bool
+	dropped bool // The open block overflowed and is being discarded
+}
+
+func newLogRedactor(scrub func(string) string) *logRedactor {
+	return &logRedactor{scrub: scrub}
+}
+
+// Write takes the next chunk and returns the scrubbed text now safe to
+// persist and broadcast; "" while everything is still held.
+func (r *logRedactor) Write(chunk string) string {
+	r.partial.WriteString(chunk)
+	pending := r.partial.String()
+	cut := strings.LastIndexByte(pending, '\n') + 1
+	if cut == 0 && len(pending) < maxPendingLine {
+		return ""
+	}
+	if cut == 0 {
+		cut = len(pending)
+	}
+	r.partial.Reset()
+	r.partial.WriteString(pending[cut:])
+
+	var out strings.Builder
+	for _, line := range strings.SplitAfter(pending[:cut], "\n") {
+		if line != "" {
+			r.line(&out, line)
+		}
+	}
+	return r.release(out.String())
+}
+
+// Flush returns whatever is left when the stream ends. A block that never
+// closed is replaced whole: fail closed rather than print half a key.
+func (r *logRedactor) Flush() string {
+	var out strings.Builder
+	if rest := r.partial.String(); rest != "" {
+		r.partial.Reset()
+		r.line(&out, rest)
+	}
+	if r.inBlock {
+		if !r.dropped {
+			out.WriteString(redactedKeyBlock)
+		}
+		r.block.Reset()
+		r.inBlock, r.dropped = false, false
+	}
+	return r.release(out.String())
+}
+
+// line routes one complete line: into the open block, or to out.
+func (r *logRedactor) line(out *strings.Builder, line string) {
+	if !r.inBlock {
+		begin := keyBlockBegin.FindStringIndex(line)
+		if begin == nil || keyBlockEnd.MatchString(line[begin[1]:]) {
+			out.WriteString(line)
+			return
+		}
+		r.inBlock = true
+	}
+
+	if !r.dropped {
+		r.block.WriteString(line)
+		if r.block.Len() > maxHeldBlock {
+			out.WriteString(redactedKeyBlock)
+			r.block.Reset()
+			r.dropped = true
+		}
+	}
+	if keyBlockEnd.MatchString(line) {
+		if !r.dropped {
+			// Whole now: the scrubber's block pattern replaces it
+			out.WriteString(r.block.String())
+		}
+		r.block.Reset()
+		r.inBlock, r.dropped = false, false
+	}
+}
+
+func (r *logRedactor) release(text string) string {
+	if text == "" || r.scrub == nil {
+		return text
+	}
+	return r.scrub(text)
+}
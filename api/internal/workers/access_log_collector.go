@@ -0,0 +1,109 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/services"
+)
+
+// AccessLogCollector periodically pulls new access log entries for every
+// domain from the Muscle, enforces transfer quotas against the fresh totals,
+// then prunes rollups past their retention.
+type AccessLogCollector struct {
+	repo       domain.AccessAnalyticsRepository
+	service    *services.AccessAnalyticsService
+	bandwidth  *services.BandwidthService
+	logger     *slog.Logger
+	interval   time.Duration
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewAccessLogCollector(
+	repo domain.AccessAnalyticsRepository,
+	service *services.AccessAnalyticsService,
+	bandwidth *services.BandwidthService,
+	logger *slog.Logger,
+	interval time.Duration,
+) *AccessLogCollector {
+	return &AccessLogCollector{
+		repo:      repo,
+		service:   service,
+		bandwidth: bandwidth,
+		logger:    logger,
+		interval:  interval,
+	}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (w *AccessLogCollector) WithHeartbeats(rec domain.HeartbeatRecorder) *AccessLogCollector {
+	rec.Register("access_log_collector", w.interval)
+	w.heartbeats = rec
+	return w
+}
+
+// Start begins the non-blocking collection loop.
+func (w *AccessLogCollector) Start(ctx context.Context) {
+	w.logger.Info("📊 Kari Brain: Access log collector started", slog.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("🛑 Kari Brain: Access log collector shutting down...")
+			return
+		case <-ticker.C:
+			w.sweep(ctx)
+			beat(w.heartbeats, "access_log_collector")
+		}
+	}
+}
+
+func (w *AccessLogCollector) sweep(ctx context.Context) {
+	if !w.service.Available() {
+		w.logger.Warn("Access log sweep skipped: the Muscle agent cannot read access logs")
+		return
+	}
+
+	targets, err := w.repo.ListTargets(ctx)
+	if err != nil {
+		w.logger.Error("Access log sweep could not list domains", slog.Any("error", err))
+		return
+	}
+
+	var requests int64
+	failed := 0
+	for _, t := range targets {
+		if ctx.Err() != nil {
+			return
+		}
+		n, err := w.service.Collect(ctx, t)
+		if err != nil {
+			w.logger.Error("Access log collection failed",
+				slog.String("domain", t.DomainName),
+				slog.Any("error", err))
+			failed++
+			continue
+		}
+		requests += n
+	}
+
+	if err := w.bandwidth.EnforceQuotas(ctx); err != nil {
+		w.logger.Error("Transfer quota enforcement failed", slog.Any("error", err))
+	}
+
+	pruned, err := w.service.Prune(ctx)
+	if err != nil {
+		w.logger.Error("Access analytics pruning failed", slog.Any("error", err))
+	}
+
+	w.logger.Debug("Access log sweep completed",
+		slog.Int("domains", len(targets)),
+		slog.Int64("requests", requests),
+		slog.Int("failed", failed),
+		slog.Int64("pruned", pruned))
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"kari/api/internal/core/domain"
 	"math/rand"
 )
@@ -19,6 +20,14 @@ type AppMonitor struct {
 	logger     *slog.Logger
 	interval   time.Duration
 	concurrency int // 🛡️ SLA: Limit concurrent checks
+	heartbeats domain.HeartbeatRecorder
+	webhooks   domain.WebhookEmitter
+	maintenance domain.MaintenanceSchedule
+	servers    domain.ServerAvailability
+
+	// Results of the last completed sweep, swapped in whole when it ends
+	healthMu sync.RWMutex
+	health   map[uuid.UUID]domain.AppHealth
 }
 
 func NewAppMonitor(
@@ -43,6 +52,32 @@ func NewAppMonitor(
 	}
 }
 
+// WithHeartbeats reports each completed tick to /health/ready.
+func (m *AppMonitor) WithHeartbeats(rec domain.HeartbeatRecorder) *AppMonitor {
+	rec.Register("app_monitor", m.interval)
+	m.heartbeats = rec
+	return m
+}
+
+// WithWebhooks emits app.crashed to the owner's webhooks when a running app stops answering.
+func (m *AppMonitor) WithWebhooks(e domain.WebhookEmitter) *AppMonitor {
+	m.webhooks = e
+	return m
+}
+
+// WithMaintenance silences failure alerts while a maintenance window is active.
+func (m *AppMonitor) WithMaintenance(s domain.MaintenanceSchedule) *AppMonitor {
+	m.maintenance = s
+	return m
+}
+
+// WithServers silences failure alerts for apps on a remote server that is
+// offline; the ServerMonitor has already raised one alert for all of them.
+func (m *AppMonitor) WithServers(s domain.ServerAvailability) *AppMonitor {
+	m.servers = s
+	return m
+}
+
 func (m *AppMonitor) Start(ctx context.Context) {
 	ticker := time.NewTicker(m.interval)
 	defer ticker.Stop()
@@ -53,6 +88,7 @@ func (m *AppMonitor) Start(ctx context.Context) {
 			return
 		case <-ticker.C:
 			m.performHealthChecks(ctx)
+			beat(m.heartbeats, "app_monitor")
 		}
 	}
 }
@@ -67,6 +103,8 @@ func (m *AppMonitor) performHealthChecks(ctx context.Context) {
 	// 🛡️ SLA: Concurrency control via semaphore
 	sem := make(chan struct{}, m.concurrency)
 	var wg sync.WaitGroup
+	var resultsMu sync.Mutex
+	results := make(map[uuid.UUID]domain.AppHealth, len(apps))
 
 	for _, app := range apps {
 		wg.Add(1)
@@ -83,13 +121,32 @@ func (m *AppMonitor) performHealthChecks(ctx context.Context) {
 			checkCtx, cancel := context.WithTimeout(ctx, 6*time.Second)
 			defer cancel()
 			
-			m.checkAppHealth(checkCtx, a)
+			h := m.checkAppHealth(checkCtx, a)
+			resultsMu.Lock()
+			results[a.ID] = h
+			resultsMu.Unlock()
 		}(app)
 	}
 	wg.Wait()
+
+	// Apps that stopped or were deleted drop out with the old map
+	m.healthMu.Lock()
+	m.health = results
+	m.healthMu.Unlock()
 }
 
-func (m *AppMonitor) checkAppHealth(ctx context.Context, app domain.Application) {
+// LastHealth serves ?include=status on the application list.
+func (m *AppMonitor) LastHealth(appID uuid.UUID) *domain.AppHealth {
+	m.healthMu.RLock()
+	defer m.healthMu.RUnlock()
+	h, ok := m.health[appID]
+	if !ok {
+		return nil
+	}
+	return &h
+}
+
+func (m *AppMonitor) checkAppHealth(ctx context.Context, app domain.Application) domain.AppHealth {
 	// 🛡️ Platform Agnostic: Allow apps to define custom health paths
 	healthPath := app.EnvVars["KARI_HEALTH_PATH"]
 	if healthPath == "" {
@@ -99,20 +156,45 @@ func (m *AppMonitor) checkAppHealth(ctx context.Context, app domain.Application)
 	url := fmt.Sprintf("http://127.0.0.1:%d%s", app.Port, healthPath)
 	
 	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+	started := time.Now()
 	resp, err := m.httpClient.Do(req)
+	health := domain.AppHealth{LatencyMS: time.Since(started).Milliseconds(), CheckedAt: time.Now().UTC()}
 
 	// A 401/403 might still mean the app is "Running" but the monitor is unauth'd
 	// Here we define "Up" as any responsive HTTP listener.
 	isUp := err == nil && resp != nil && resp.StatusCode < 500
 	if resp != nil {
+		health.StatusCode = resp.StatusCode
 		resp.Body.Close()
 	}
+	health.Up = isUp
+
+	// 🚧 Planned downtime: health is still recorded, but nobody gets paged.
+	// An app that is still down once the window ends alerts on the next sweep.
+	if !isUp && app.Status == "running" && m.inMaintenance() {
+		return health
+	}
+	if !isUp && app.ServerID != nil && m.servers != nil && m.servers.ServerOffline(*app.ServerID) {
+		return health
+	}
 
 	if !isUp && app.Status == "running" {
 		m.handleAppFailure(ctx, app, err)
+		if m.webhooks != nil {
+			m.webhooks.Emit(ctx, app.OwnerID, domain.WebhookAppCrashed, map[string]any{
+				"app_id":      app.ID,
+				"domain_name": app.DomainName,
+				"status_code": health.StatusCode,
+			})
+		}
 	} else if isUp && app.Status == "failed" {
 		m.handleAppRecovery(ctx, app)
 	}
+	return health
+}
+
+func (m *AppMonitor) inMaintenance() bool {
+	return m.maintenance != nil && m.maintenance.ActiveWindow(time.Now()) != nil
 }
 
 // ... handleAppFailure and handleAppRecovery remain similar but use structured logging ...
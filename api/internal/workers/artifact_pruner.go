@@ -0,0 +1,55 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/services"
+)
+
+// ArtifactPruner deletes stored build artifacts that fell out of the
+// retention policy, on the Muscle first and then in the database.
+type ArtifactPruner struct {
+	artifacts  *services.ArtifactService
+	logger     *slog.Logger
+	interval   time.Duration
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewArtifactPruner(artifacts *services.ArtifactService, logger *slog.Logger, interval time.Duration) *ArtifactPruner {
+	return &ArtifactPruner{
+		artifacts: artifacts,
+		logger:    logger,
+		interval:  interval,
+	}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (p *ArtifactPruner) WithHeartbeats(rec domain.HeartbeatRecorder) *ArtifactPruner {
+	rec.Register("artifact_pruner", p.interval)
+	p.heartbeats = rec
+	return p
+}
+
+// Start begins the non-blocking retention loop.
+func (p *ArtifactPruner) Start(ctx context.Context) {
+	p.logger.Info("📦 Kari Brain: Artifact pruner started", slog.Duration("interval", p.interval))
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.logger.Info("🛑 Kari Brain: Artifact pruner shutting down...")
+			return
+		case <-ticker.C:
+			if err := p.artifacts.Prune(ctx); err != nil {
+				p.logger.Warn("⚠️ Failed to prune artifacts", slog.Any("error", err))
+			}
+			beat(p.heartbeats, "artifact_pruner")
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+const (
+	forwarderBufferSize    = 10000
+	forwarderBatchSize     = 100
+	forwarderFlushInterval = 2 * time.Second
+	forwarderMaxAttempts   = 6
+)
+
+// AuditForwarder buffers audit events in memory and ships them to the active
+// SIEM sink in batches, retrying with exponential backoff.
+// 🛡️ SLA: Postgres remains the system of record. Forwarding is best-effort and
+// can never slow down or fail the API request that produced the event.
+type AuditForwarder struct {
+	events chan domain.AuditEvent
+	logger *slog.Logger
+
+	mu   sync.RWMutex
+	sink domain.AuditSink // nil = forwarding disabled
+
+	dropped atomic.Int64
+}
+
+func NewAuditForwarder(logger *slog.Logger) *AuditForwarder {
+	return &AuditForwarder{
+		events: make(chan domain.AuditEvent, forwarderBufferSize),
+		logger: logger,
+	}
+}
+
+// Publish enqueues an event without blocking. When the buffer is full the
+// event is dropped and counted (it is still safe in Postgres).
+func (f *AuditForwarder) Publish(ev domain.AuditEvent) {
+	f.mu.RLock()
+	enabled := f.sink != nil
+	f.mu.RUnlock()
+	if !enabled {
+		return
+	}
+
+	select {
+	case f.events <- ev:
+	default:
+		if n := f.dropped.Add(1); n%1000 == 1 {
+			f.logger.Warn("Audit forwarder buffer full; dropping events", slog.Int64("dropped_total", n))
+		}
+	}
+}
+
+// SetSink swaps the active sink (nil disables forwarding) and closes the old one.
+func (f *AuditForwarder) SetSink(sink domain.AuditSink) {
+	f.mu.Lock()
+	old := f.sink
+	f.sink = sink
+	f.mu.Unlock()
+
+	if old != nil {
+		old.Close()
+	}
+}
+
+// Dropped reports how many events were discarded since boot.
+func (f *AuditForwarder) Dropped() int64 {
+	return f.dropped.Load()
+}
+
+// Start begins the batching loop.
+func (f *AuditForwarder) Start(ctx context.Context) {
+	f.logger.Info("📡 Kari Brain: Audit forwarder started")
+
+	ticker := time.NewTicker(forwarderFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]domain.AuditEvent, 0, forwarderBatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			// Best-effort final flush with a short, independent deadline
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			f.flush(flushCtx, f.drain(batch))
+			cancel()
+			f.SetSink(nil)
+			f.logger.Info("🛑 Kari Brain: Audit forwarder shutting down...")
+			return
+		case ev := <-f.events:
+			batch = append(batch, ev)
+			if len(batch) >= forwarderBatchSize {
+				f.flush(ctx, batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				f.flush(ctx, batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// drain moves any queued events into the batch without blocking.
+func (f *AuditForwarder) drain(batch []domain.AuditEvent) []domain.AuditEvent {
+	for {
+		select {
+		case ev := <-f.events:
+			batch = append(batch, ev)
+		default:
+			return batch
+		}
+	}
+}
+
+// flush delivers a batch with exponential backoff (1s, 2s, 4s ... capped at 30s).
+func (f *AuditForwarder) flush(ctx context.Context, batch []domain.AuditEvent) {
+	if len(batch) == 0 {
+		return
+	}
+
+	backoff := time.Second
+	for attempt := 1; attempt <= forwarderMaxAttempts; attempt++ {
+		f.mu.RLock()
+		sink := f.sink
+		f.mu.RUnlock()
+		if sink == nil {
+			return // Disabled mid-flight
+		}
+
+		sendCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		err := sink.Send(sendCtx, batch)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		f.logger.Warn("Audit forward attempt failed",
+			slog.Int("attempt", attempt),
+			slog.Int("batch_size", len(batch)),
+			slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+
+	n := f.dropped.Add(int64(len(batch)))
+	f.logger.Error("Audit forward batch abandoned after retries",
+		slog.Int("batch_size", len(batch)),
+		slog.Int64("dropped_total", n))
+}
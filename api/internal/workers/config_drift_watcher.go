@@ -0,0 +1,101 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// ConfigDriftWatcher notices when the env file is edited after boot. The
+// Brain never reloads its configuration, so the edit does nothing until a
+// restart; the watcher says so once instead of letting it look applied.
+//
+// Not a singleton: every replica compares the file with its own process.
+type ConfigDriftWatcher struct {
+	inspector  domain.ConfigInspector
+	auditRepo  domain.AuditRepository
+	logger     *slog.Logger
+	interval   time.Duration
+	heartbeats domain.HeartbeatRecorder
+
+	reported string // Drift signature last alerted on
+}
+
+func NewConfigDriftWatcher(inspector domain.ConfigInspector, audit domain.AuditRepository, logger *slog.Logger, interval time.Duration) *ConfigDriftWatcher {
+	return &ConfigDriftWatcher{
+		inspector: inspector,
+		auditRepo: audit,
+		logger:    logger,
+		interval:  interval,
+	}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (w *ConfigDriftWatcher) WithHeartbeats(rec domain.HeartbeatRecorder) *ConfigDriftWatcher {
+	rec.Register("config_drift_watcher", w.interval)
+	w.heartbeats = rec
+	return w
+}
+
+// Start begins the non-blocking watch loop.
+func (w *ConfigDriftWatcher) Start(ctx context.Context) {
+	w.logger.Info("⚙️ Kari Brain: Config drift watcher started", slog.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("🛑 Kari Brain: Config drift watcher shutting down...")
+			return
+		case <-ticker.C:
+			w.check(ctx)
+			beat(w.heartbeats, "config_drift_watcher")
+		}
+	}
+}
+
+func (w *ConfigDriftWatcher) check(ctx context.Context) {
+	report, err := w.inspector.ConfigReport()
+	if err != nil {
+		w.logger.Warn("Failed to read env file for drift check", slog.Any("error", err))
+		return
+	}
+
+	keys := make([]string, 0, len(report.Drift))
+	changes := make(map[string]any, len(report.Drift))
+	for _, d := range report.Drift {
+		keys = append(keys, d.Key)
+		changes[d.Key] = string(d.Kind)
+	}
+	signature := strings.Join(keys, ",")
+	if signature == w.reported {
+		return
+	}
+	w.reported = signature
+
+	if len(keys) == 0 {
+		w.logger.Info("✅ Env file matches the running configuration again")
+		return
+	}
+
+	host, _ := os.Hostname()
+	w.logger.Warn("⚠️ Env file changed since boot; restart to apply",
+		slog.String("env_file", report.EnvFile), slog.Any("keys", keys))
+	// Keys and kinds only: the values may be secrets
+	if err := w.auditRepo.CreateAlert(ctx, &domain.SystemAlert{
+		Severity: "warning",
+		Category: "system",
+		Message: fmt.Sprintf("%s has changed since the Brain on %s started (%s); restart it to apply",
+			report.EnvFile, host, signature),
+		Metadata: map[string]any{"env_file": report.EnvFile, "host": host, "changes": changes},
+	}); err != nil {
+		w.logger.Warn("Failed to raise config drift alert", slog.Any("error", err))
+	}
+}
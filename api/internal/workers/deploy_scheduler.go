@@ -0,0 +1,55 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/services"
+)
+
+// DeployScheduler promotes SCHEDULED deployments into the PENDING queue once
+// their time has come. The deployment worker does the rest as usual.
+type DeployScheduler struct {
+	schedules  *services.DeployScheduleService
+	logger     *slog.Logger
+	interval   time.Duration
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewDeployScheduler(schedules *services.DeployScheduleService, logger *slog.Logger, interval time.Duration) *DeployScheduler {
+	return &DeployScheduler{
+		schedules: schedules,
+		logger:    logger,
+		interval:  interval,
+	}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (s *DeployScheduler) WithHeartbeats(rec domain.HeartbeatRecorder) *DeployScheduler {
+	rec.Register("deploy_scheduler", s.interval)
+	s.heartbeats = rec
+	return s
+}
+
+// Start begins the non-blocking promotion loop.
+func (s *DeployScheduler) Start(ctx context.Context) {
+	s.logger.Info("⏰ Kari Brain: Deploy scheduler started", slog.Duration("interval", s.interval))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("🛑 Kari Brain: Deploy scheduler shutting down...")
+			return
+		case <-ticker.C:
+			if err := s.schedules.PromoteDue(ctx); err != nil {
+				s.logger.Warn("⚠️ Failed to promote scheduled deployments", slog.Any("error", err))
+			}
+			beat(s.heartbeats, "deploy_scheduler")
+		}
+	}
+}
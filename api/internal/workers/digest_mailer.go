@@ -0,0 +1,54 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/services"
+)
+
+// digestCheckInterval is how often the mailer looks for due digests. The
+// delivery ledger makes every extra pass a no-op.
+const digestCheckInterval = time.Hour
+
+// DigestMailer sends the daily and weekly Action Center digests.
+type DigestMailer struct {
+	service    *services.DigestService
+	logger     *slog.Logger
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewDigestMailer(service *services.DigestService, logger *slog.Logger) *DigestMailer {
+	return &DigestMailer{service: service, logger: logger}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (w *DigestMailer) WithHeartbeats(rec domain.HeartbeatRecorder) *DigestMailer {
+	rec.Register("digest_mailer", digestCheckInterval)
+	w.heartbeats = rec
+	return w
+}
+
+// Start begins the non-blocking digest loop.
+func (w *DigestMailer) Start(ctx context.Context) {
+	w.logger.Info("📬 Kari Brain: Digest mailer started")
+
+	ticker := time.NewTicker(digestCheckInterval)
+	defer ticker.Stop()
+
+	w.service.SendDue(ctx, time.Now())
+	beat(w.heartbeats, "digest_mailer")
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("🛑 Kari Brain: Digest mailer shutting down...")
+			return
+		case <-ticker.C:
+			w.service.SendDue(ctx, time.Now())
+			beat(w.heartbeats, "digest_mailer")
+		}
+	}
+}
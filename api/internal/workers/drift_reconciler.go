@@ -0,0 +1,70 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/services"
+)
+
+// DriftReconciler periodically compares the Muscle's resource inventory with
+// the database. It only reports: adoption and cleanup stay admin decisions.
+type DriftReconciler struct {
+	service    *services.DriftService
+	logger     *slog.Logger
+	interval   time.Duration
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewDriftReconciler(service *services.DriftService, logger *slog.Logger, interval time.Duration) *DriftReconciler {
+	return &DriftReconciler{
+		service:  service,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (w *DriftReconciler) WithHeartbeats(rec domain.HeartbeatRecorder) *DriftReconciler {
+	rec.Register("drift_reconciler", w.interval)
+	w.heartbeats = rec
+	return w
+}
+
+// Start begins the non-blocking sweep loop. The first sweep waits one
+// interval: right after a restart, deployments may still be converging.
+func (w *DriftReconciler) Start(ctx context.Context) {
+	w.logger.Info("🧭 Kari Brain: Drift reconciler started", slog.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("🛑 Kari Brain: Drift reconciler shutting down...")
+			return
+		case <-ticker.C:
+			w.sweep(ctx)
+			beat(w.heartbeats, "drift_reconciler")
+		}
+	}
+}
+
+func (w *DriftReconciler) sweep(ctx context.Context) {
+	if !w.service.Available() {
+		w.logger.Warn("Drift sweep skipped: the Muscle agent cannot list its resources")
+		return
+	}
+
+	opened, resolved, err := w.service.Sweep(ctx)
+	if err != nil {
+		w.logger.Error("Drift sweep failed", slog.Any("error", err))
+		return
+	}
+	w.logger.Info("✅ Drift sweep completed",
+		slog.Int("opened", opened),
+		slog.Int("resolved", resolved))
+}
@@ -0,0 +1,103 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/services"
+)
+
+// FileScanner sweeps every active app with an integrity/malware scan. Scans
+// run one at a time: they are disk-bound and share the host with the apps.
+type FileScanner struct {
+	repo       domain.ApplicationRepository
+	service    *services.FileScanService
+	logger     *slog.Logger
+	interval   time.Duration
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewFileScanner(
+	repo domain.ApplicationRepository,
+	service *services.FileScanService,
+	logger *slog.Logger,
+	interval time.Duration,
+) *FileScanner {
+	return &FileScanner{
+		repo:     repo,
+		service:  service,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (w *FileScanner) WithHeartbeats(rec domain.HeartbeatRecorder) *FileScanner {
+	rec.Register("file_scanner", w.interval)
+	w.heartbeats = rec
+	return w
+}
+
+// Start begins the non-blocking sweep loop. The first sweep waits one
+// interval so a Brain restart does not trigger a full-disk scan.
+func (w *FileScanner) Start(ctx context.Context) {
+	w.logger.Info("🧬 Kari Brain: File scanner started", slog.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("🛑 Kari Brain: File scanner shutting down...")
+			return
+		case <-ticker.C:
+			w.sweep(ctx)
+			beat(w.heartbeats, "file_scanner")
+		}
+	}
+}
+
+func (w *FileScanner) sweep(ctx context.Context) {
+	// An old Muscle would fail every app the same way
+	if !w.service.Available() {
+		w.logger.Warn("File scan sweep skipped: the Muscle agent cannot scan app files")
+		return
+	}
+
+	apps, err := w.repo.ListAllActive(ctx)
+	if err != nil {
+		w.logger.Error("File scan sweep could not list apps", slog.Any("error", err))
+		return
+	}
+
+	scanned, flagged, failed := 0, 0, 0
+	for i := range apps {
+		if ctx.Err() != nil {
+			return
+		}
+		if apps[i].AppType == "image" {
+			continue
+		}
+
+		scan, err := w.service.Scan(ctx, &apps[i], false)
+		if err != nil {
+			w.logger.Error("File scan failed",
+				slog.String("app_id", apps[i].ID.String()),
+				slog.Any("error", err))
+			failed++
+			continue
+		}
+		scanned++
+		if scan.Status == domain.FileScanFindings {
+			flagged++
+		}
+	}
+
+	w.logger.Info("✅ File scan sweep completed",
+		slog.Int("scanned", scanned),
+		slog.Int("flagged", flagged),
+		slog.Int("failed", failed))
+}
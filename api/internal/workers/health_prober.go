@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"kari/api/internal/core/domain"
 	"kari/api/proto/agent"
 )
 
@@ -18,14 +19,22 @@ type HealthCache struct {
 	lastPing time.Time
 }
 
+// AgentObserver is notified of every successful heartbeat so protocol
+// negotiation can follow Muscle upgrades without a Brain restart.
+type AgentObserver interface {
+	ObserveAgent(ctx context.Context, agentVersion string, minProtocol, maxProtocol uint32)
+}
+
 // HealthProber periodically polls the Rust Muscle's GetSystemStatus RPC
 // and updates a global health cache. The Brain reports itself as Unhealthy
 // if the Muscle link is severed — enforcing the Fail-Closed design mandate.
 type HealthProber struct {
-	agent    agent.SystemAgentClient
-	cache    *HealthCache
-	logger   *slog.Logger
-	interval time.Duration
+	agent      agent.SystemAgentClient
+	cache      *HealthCache
+	logger     *slog.Logger
+	interval   time.Duration
+	observers  []AgentObserver
+	heartbeats domain.HeartbeatRecorder
 }
 
 // NewHealthProber creates a new background health checker.
@@ -39,6 +48,20 @@ func NewHealthProber(agentClient agent.SystemAgentClient, logger *slog.Logger) *
 	}
 }
 
+// WithObserver registers a heartbeat observer (protocol negotiation, offline
+// queue replay); call before Start.
+func (p *HealthProber) WithObserver(observer AgentObserver) *HealthProber {
+	p.observers = append(p.observers, observer)
+	return p
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (p *HealthProber) WithHeartbeats(rec domain.HeartbeatRecorder) *HealthProber {
+	rec.Register("health_prober", p.interval)
+	p.heartbeats = rec
+	return p
+}
+
 // Start begins the non-blocking polling loop.
 func (p *HealthProber) Start(ctx context.Context) {
 	p.logger.Info("🩺 Kari Brain: Health Prober started (interval: 15s)")
@@ -56,6 +79,7 @@ func (p *HealthProber) Start(ctx context.Context) {
 			return
 		case <-ticker.C:
 			p.probe(ctx)
+			beat(p.heartbeats, "health_prober")
 		}
 	}
 }
@@ -85,6 +109,10 @@ func (p *HealthProber) probe(ctx context.Context) {
 	p.cache.lastPing = time.Now()
 	p.cache.mu.Unlock()
 
+	for _, observer := range p.observers {
+		observer.ObserveAgent(ctx, status.AgentVersion, status.MinProtocolVersion, status.ProtocolVersion)
+	}
+
 	p.logger.Debug("🩺 Muscle heartbeat received",
 		slog.Float64("cpu_percent", float64(status.CpuUsagePercent)),
 		slog.Float64("memory_mb", float64(status.MemoryUsageMb)),
@@ -107,6 +135,11 @@ func (p *HealthProber) GetStatus() *agent.SystemStatus {
 	return p.cache.status
 }
 
+// LastSuccess returns the time of the last successful probe (zero if none).
+func (p *HealthProber) LastSuccess() time.Time {
+	return p.cache.LastPing()
+}
+
 // LastPing returns the time of the last successful probe.
 func (c *HealthCache) LastPing() time.Time {
 	c.mu.RLock()
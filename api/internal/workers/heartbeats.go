@@ -0,0 +1,60 @@
+package workers
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// Heartbeats records when each background loop last completed a tick, so
+// /health/ready can tell a stuck worker from an idle one.
+// 🛡️ SLA: A nil *Heartbeats is valid and records nothing.
+type Heartbeats struct {
+	mu    sync.RWMutex
+	beats map[string]*domain.WorkerHeartbeat
+}
+
+func NewHeartbeats() *Heartbeats {
+	return &Heartbeats{beats: map[string]*domain.WorkerHeartbeat{}}
+}
+
+func (h *Heartbeats) Register(name string, interval time.Duration) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.beats[name] = &domain.WorkerHeartbeat{Name: name, Interval: interval, RegisteredAt: time.Now()}
+}
+
+func (h *Heartbeats) Beat(name string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if hb, ok := h.beats[name]; ok {
+		hb.LastBeat = time.Now()
+	}
+}
+
+// Snapshot returns copies sorted by name.
+func (h *Heartbeats) Snapshot() []domain.WorkerHeartbeat {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	out := make([]domain.WorkerHeartbeat, 0, len(h.beats))
+	for _, hb := range h.beats {
+		out = append(out, *hb)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// beat is the nil-safe call site used by the worker loops.
+func beat(rec domain.HeartbeatRecorder, name string) {
+	if rec != nil {
+		rec.Beat(name)
+	}
+}
@@ -0,0 +1,54 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/services"
+)
+
+// logArchiveInterval is how often finished deployments are checked for
+// archival. Each pass is bounded, so a backlog drains over several days.
+const logArchiveInterval = 24 * time.Hour
+
+// LogArchiver compresses old build logs into object storage.
+type LogArchiver struct {
+	service    *services.DeploymentLogService
+	logger     *slog.Logger
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewLogArchiver(service *services.DeploymentLogService, logger *slog.Logger) *LogArchiver {
+	return &LogArchiver{service: service, logger: logger}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (w *LogArchiver) WithHeartbeats(rec domain.HeartbeatRecorder) *LogArchiver {
+	rec.Register("log_archiver", logArchiveInterval)
+	w.heartbeats = rec
+	return w
+}
+
+// Start begins the non-blocking archival loop.
+func (w *LogArchiver) Start(ctx context.Context) {
+	w.logger.Info("🗄️ Kari Brain: Deployment log archiver started")
+
+	ticker := time.NewTicker(logArchiveInterval)
+	defer ticker.Stop()
+
+	w.service.ArchiveDue(ctx)
+	beat(w.heartbeats, "log_archiver")
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("🛑 Kari Brain: Deployment log archiver shutting down...")
+			return
+		case <-ticker.C:
+			w.service.ArchiveDue(ctx)
+			beat(w.heartbeats, "log_archiver")
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// logSpoolInterval is how often spooled build logs are retried against Postgres.
+const logSpoolInterval = 30 * time.Second
+
+type logInsertFunc = func(ctx context.Context, deploymentID string, msg domain.LogMessage) error
+
+// replayableSpool is the disk buffer the DeploymentWorker spills into.
+type replayableSpool interface {
+	Replay(ctx context.Context, insert logInsertFunc) (int, error)
+}
+
+// LogSpoolReplayer drains spooled build logs back into deployment_logs once
+// the database accepts writes again.
+type LogSpoolReplayer struct {
+	spool      replayableSpool
+	insert     logInsertFunc
+	logger     *slog.Logger
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewLogSpoolReplayer(spool replayableSpool, insert logInsertFunc, logger *slog.Logger) *LogSpoolReplayer {
+	return &LogSpoolReplayer{spool: spool, insert: insert, logger: logger}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (w *LogSpoolReplayer) WithHeartbeats(rec domain.HeartbeatRecorder) *LogSpoolReplayer {
+	rec.Register("log_spool", logSpoolInterval)
+	w.heartbeats = rec
+	return w
+}
+
+// Start begins the non-blocking replay loop.
+func (w *LogSpoolReplayer) Start(ctx context.Context) {
+	w.logger.Info("💾 Kari Brain: Build log spool replayer started")
+
+	ticker := time.NewTicker(logSpoolInterval)
+	defer ticker.Stop()
+
+	w.replay(ctx)
+	beat(w.heartbeats, "log_spool")
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("🛑 Kari Brain: Build log spool replayer shutting down...")
+			return
+		case <-ticker.C:
+			w.replay(ctx)
+			beat(w.heartbeats, "log_spool")
+		}
+	}
+}
+
+func (w *LogSpoolReplayer) replay(ctx context.Context) {
+	n, err := w.spool.Replay(ctx, w.insert)
+	if n > 0 {
+		w.logger.Info("💾 Spooled build log lines replayed", slog.Int("count", n))
+	}
+	if err != nil {
+		w.logger.Warn("Build log spool replay incomplete", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,62 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/services"
+)
+
+// MaintenanceScheduler keeps the in-memory maintenance windows fresh, so
+// windows scheduled on another Brain take effect here, and announces their
+// start and end.
+type MaintenanceScheduler struct {
+	windows    *services.MaintenanceWindowService
+	logger     *slog.Logger
+	interval   time.Duration
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewMaintenanceScheduler(windows *services.MaintenanceWindowService, logger *slog.Logger, interval time.Duration) *MaintenanceScheduler {
+	return &MaintenanceScheduler{
+		windows:  windows,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (s *MaintenanceScheduler) WithHeartbeats(rec domain.HeartbeatRecorder) *MaintenanceScheduler {
+	rec.Register("maintenance_scheduler", s.interval)
+	s.heartbeats = rec
+	return s
+}
+
+// Start syncs once immediately, then on every tick.
+func (s *MaintenanceScheduler) Start(ctx context.Context) {
+	s.logger.Info("🚧 Kari Brain: Maintenance scheduler started", slog.Duration("interval", s.interval))
+	s.sync(ctx)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("🛑 Kari Brain: Maintenance scheduler shutting down...")
+			return
+		case <-ticker.C:
+			s.sync(ctx)
+			beat(s.heartbeats, "maintenance_scheduler")
+		}
+	}
+}
+
+func (s *MaintenanceScheduler) sync(ctx context.Context) {
+	if err := s.windows.Sync(ctx); err != nil {
+		// Keep the last known windows rather than dropping them
+		s.logger.Warn("⚠️ Failed to reload maintenance windows", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,54 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/services"
+)
+
+// OutboxDispatcher retries pending state changes on a fixed cadence. Agent
+// heartbeats already drain the outbox on reconnect; this covers a change that
+// failed to mark applied, or a Brain restarted with changes still pending.
+type OutboxDispatcher struct {
+	outbox     *services.Outbox
+	logger     *slog.Logger
+	interval   time.Duration
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewOutboxDispatcher(outbox *services.Outbox, logger *slog.Logger, interval time.Duration) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		outbox:   outbox,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (w *OutboxDispatcher) WithHeartbeats(rec domain.HeartbeatRecorder) *OutboxDispatcher {
+	rec.Register("outbox_dispatcher", w.interval)
+	w.heartbeats = rec
+	return w
+}
+
+// Start begins the non-blocking dispatch loop.
+func (w *OutboxDispatcher) Start(ctx context.Context) {
+	w.logger.Info("📬 Kari Brain: Outbox dispatcher started", slog.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("🛑 Kari Brain: Outbox dispatcher shutting down...")
+			return
+		case <-ticker.C:
+			w.outbox.Dispatch(ctx)
+			beat(w.heartbeats, "outbox_dispatcher")
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/services"
+)
+
+// ProfileReconciler rolls the SystemProfile out to the agents. Saving a new
+// version wakes it straight away; the ticker retries agents that were
+// offline or failed.
+type ProfileReconciler struct {
+	profiles   *services.SystemProfileService
+	logger     *slog.Logger
+	interval   time.Duration
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewProfileReconciler(profiles *services.SystemProfileService, logger *slog.Logger, interval time.Duration) *ProfileReconciler {
+	return &ProfileReconciler{
+		profiles: profiles,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (w *ProfileReconciler) WithHeartbeats(rec domain.HeartbeatRecorder) *ProfileReconciler {
+	rec.Register("profile_reconciler", w.interval)
+	w.heartbeats = rec
+	return w
+}
+
+// Start begins the non-blocking rollout loop.
+func (w *ProfileReconciler) Start(ctx context.Context) {
+	w.logger.Info("📐 Kari Brain: Profile reconciler started", slog.Duration("interval", w.interval))
+
+	w.profiles.Reconcile(ctx)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("🛑 Kari Brain: Profile reconciler shutting down...")
+			return
+		case <-w.profiles.Wake():
+			w.profiles.Reconcile(ctx)
+		case <-ticker.C:
+			w.profiles.Reconcile(ctx)
+			beat(w.heartbeats, "profile_reconciler")
+		}
+	}
+}
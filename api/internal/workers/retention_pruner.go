@@ -0,0 +1,158 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// expiryPurger drops rows past their own TTL; nothing is archived first.
+// Satisfied by postgres.IdempotencyRepo.
+type expiryPurger interface {
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// RetentionPruner moves expired log rows to cold storage and then deletes them,
+// keeping tenant_logs and deployment_logs bounded. On the same tick it
+// purges short-lived tables whose rows carry their own expiry.
+type RetentionPruner struct {
+	repo       domain.RetentionRepository
+	sink       domain.ArchiveSink // nil = archiving unavailable; log tables are left alone
+	policies   []domain.RetentionPolicy
+	purgers    map[string]expiryPurger
+	logger     *slog.Logger
+	interval   time.Duration
+	batchSize  int
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewRetentionPruner(
+	repo domain.RetentionRepository,
+	sink domain.ArchiveSink,
+	policies []domain.RetentionPolicy,
+	logger *slog.Logger,
+) *RetentionPruner {
+	return &RetentionPruner{
+		repo:      repo,
+		sink:      sink,
+		policies:  policies,
+		purgers:   map[string]expiryPurger{},
+		logger:    logger,
+		interval:  6 * time.Hour,
+		batchSize: 5000, // 🛡️ Performance: Bounded batches keep DELETE locks short
+	}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (p *RetentionPruner) WithHeartbeats(rec domain.HeartbeatRecorder) *RetentionPruner {
+	rec.Register("retention_pruner", p.interval)
+	p.heartbeats = rec
+	return p
+}
+
+// WithExpiry purges table's expired rows every tick (idempotency keys).
+func (p *RetentionPruner) WithExpiry(table string, purger expiryPurger) *RetentionPruner {
+	p.purgers[table] = purger
+	return p
+}
+
+// Start begins the non-blocking pruning loop.
+func (p *RetentionPruner) Start(ctx context.Context) {
+	p.logger.Info("🗄️ Kari Brain: Retention pruner started", slog.Duration("interval", p.interval))
+
+	p.runOnce(ctx)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.logger.Info("🛑 Kari Brain: Retention pruner shutting down...")
+			return
+		case <-ticker.C:
+			p.runOnce(ctx)
+			beat(p.heartbeats, "retention_pruner")
+		}
+	}
+}
+
+func (p *RetentionPruner) runOnce(ctx context.Context) {
+	for table, purger := range p.purgers {
+		purged, err := purger.DeleteExpired(ctx)
+		if err != nil {
+			p.logger.Error("Expiry purge failed", slog.String("table", table), slog.Any("error", err))
+			continue
+		}
+		if purged > 0 {
+			p.logger.Info("Expiry purge complete", slog.String("table", table), slog.Int64("rows_deleted", purged))
+		}
+	}
+
+	for _, policy := range p.policies {
+		if policy.RetentionDays <= 0 || p.sink == nil {
+			continue
+		}
+		cutoff := time.Now().AddDate(0, 0, -policy.RetentionDays)
+		archived, err := p.pruneTable(ctx, policy.Table, cutoff)
+		if err != nil {
+			p.logger.Error("Retention pruning failed",
+				slog.String("table", policy.Table),
+				slog.Int64("archived_before_failure", archived),
+				slog.Any("error", err))
+			continue
+		}
+		if archived > 0 {
+			p.logger.Info("Retention pruning complete",
+				slog.String("table", policy.Table),
+				slog.Int64("rows_archived", archived),
+				slog.Time("cutoff", cutoff))
+		}
+	}
+}
+
+// pruneTable drains all expired rows batch by batch: archive first, delete second.
+// 🛡️ Stability: If archiving fails the batch stays in Postgres; rows are never
+// deleted without a durable copy.
+func (p *RetentionPruner) pruneTable(ctx context.Context, table string, cutoff time.Time) (int64, error) {
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		rows, err := p.repo.FetchExpired(ctx, table, cutoff, p.batchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(rows) == 0 {
+			return total, nil
+		}
+
+		location, err := p.sink.Archive(ctx, table, rows)
+		if err != nil {
+			return total, err
+		}
+
+		ids := make([]string, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ID
+		}
+		deleted, err := p.repo.DeleteRows(ctx, table, ids)
+		if err != nil {
+			return total, err
+		}
+		total += deleted
+
+		p.logger.Debug("Archived retention batch",
+			slog.String("table", table),
+			slog.String("location", location),
+			slog.Int64("rows", deleted))
+
+		if len(rows) < p.batchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"kari/api/internal/core/domain"
+	agent "kari/api/proto/kari/agent/v1"
+)
+
+// remoteAgents is satisfied by agentlink.RemotePool.
+type remoteAgents interface {
+	Client(s domain.Server) (agent.SystemAgentClient, error)
+}
+
+// ServerMonitor is the HealthProber for enrolled remote servers: it probes
+// every agent, records when each last answered, and flips a server offline
+// once it has been silent past the threshold. The Brain's own agent stays
+// with the HealthProber, which also gates readiness.
+//
+// It also asks each server what its host can run whenever the agent version
+// changes, so services can gate features per server (see Server.Supports).
+//
+// 🛡️ SLA: One transition alert per outage. While a server is offline, the
+// AppMonitor stays quiet about the apps on it (see ServerOffline).
+type ServerMonitor struct {
+	repo       domain.ServerRepository
+	agents     remoteAgents
+	auditRepo  domain.AuditRepository
+	logger     *slog.Logger
+	interval   time.Duration
+	threshold  time.Duration
+	heartbeats domain.HeartbeatRecorder
+
+	offlineMu sync.RWMutex
+	offline   map[uuid.UUID]bool
+}
+
+func NewServerMonitor(
+	repo domain.ServerRepository,
+	agents remoteAgents,
+	audit domain.AuditRepository,
+	logger *slog.Logger,
+	threshold time.Duration,
+) *ServerMonitor {
+	return &ServerMonitor{
+		repo:      repo,
+		agents:    agents,
+		auditRepo: audit,
+		logger:    logger,
+		interval:  15 * time.Second,
+		threshold: threshold,
+		offline:   map[uuid.UUID]bool{},
+	}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (m *ServerMonitor) WithHeartbeats(rec domain.HeartbeatRecorder) *ServerMonitor {
+	rec.Register("server_monitor", m.interval)
+	m.heartbeats = rec
+	return m
+}
+
+func (m *ServerMonitor) Start(ctx context.Context) {
+	m.logger.Info("🖥️ Kari Brain: Server monitor started",
+		slog.Duration("interval", m.interval), slog.Duration("offline_after", m.threshold))
+
+	m.sweep(ctx)
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.sweep(ctx)
+			beat(m.heartbeats, "server_monitor")
+		}
+	}
+}
+
+// ServerOffline implements domain.ServerAvailability from the last sweep.
+func (m *ServerMonitor) ServerOffline(id uuid.UUID) bool {
+	m.offlineMu.RLock()
+	defer m.offlineMu.RUnlock()
+	return m.offline[id]
+}
+
+func (m *ServerMonitor) sweep(ctx context.Context) {
+	servers, err := m.repo.ListServers(ctx)
+	if err != nil {
+		m.logger.Error("SLA Breach: Failed to list servers", slog.Any("error", err))
+		return
+	}
+
+	// 🛡️ SLA: Concurrency control via semaphore
+	sem := make(chan struct{}, 10)
+	var wg sync.WaitGroup
+	for i := range servers {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(s *domain.Server) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			m.probe(ctx, s)
+		}(&servers[i])
+	}
+	wg.Wait()
+
+	now := time.Now()
+	offline := make(map[uuid.UUID]bool, len(servers))
+	for i := range servers {
+		s := &servers[i]
+		lastSeen := s.EnrolledAt // A server that never answered counts from enrollment
+		if s.LastSeenAt != nil {
+			lastSeen = *s.LastSeenAt
+		}
+		silent := now.Sub(lastSeen) > m.threshold
+
+		switch {
+		case silent && s.Status != domain.ServerOffline:
+			m.transition(ctx, s, domain.ServerOffline, lastSeen)
+		case !silent && s.LastSeenAt != nil && s.Status != domain.ServerOnline:
+			m.transition(ctx, s, domain.ServerOnline, lastSeen)
+		}
+		offline[s.ID] = s.Status == domain.ServerOffline
+	}
+
+	m.offlineMu.Lock()
+	m.offline = offline
+	m.offlineMu.Unlock()
+}
+
+// probe updates s.LastSeenAt in place when the agent answers.
+func (m *ServerMonitor) probe(ctx context.Context, s *domain.Server) {
+	client, err := m.agents.Client(*s)
+	if err != nil {
+		m.logger.Warn("Server link unavailable", slog.String("server", s.Name), slog.Any("error", err))
+		return
+	}
+
+	// 🛡️ SLA: Per-probe timeout so one black-holed host cannot stall the sweep
+	probeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	sys, err := client.GetSystemStatus(probeCtx, &agent.Empty{})
+	if err != nil {
+		m.logger.Debug("Server probe failed", slog.String("server", s.Name), slog.Any("error", err))
+		return
+	}
+
+	seen := time.Now().UTC()
+	if err := m.repo.RecordHeartbeat(ctx, s.ID, sys.AgentVersion, seen); err != nil {
+		m.logger.Error("Failed to record server heartbeat", slog.String("server", s.Name), slog.Any("error", err))
+		return
+	}
+	s.LastSeenAt, s.AgentVersion = &seen, sys.AgentVersion
+
+	if s.Host == nil || s.Host.AgentVersion != sys.AgentVersion {
+		m.discover(probeCtx, client, s)
+	}
+}
+
+// discover records the host's capabilities. An agent older than protocol
+// rev 19 answers Unimplemented and is recorded with no host features, so it
+// is not asked again until it is upgraded.
+func (m *ServerMonitor) discover(ctx context.Context, client agent.SystemAgentClient, s *domain.Server) {
+	host := &domain.HostCapabilities{AgentVersion: s.AgentVersion, DiscoveredAt: time.Now().UTC()}
+	report, err := client.GetCapabilities(ctx, &agent.Empty{})
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+	case err != nil:
+		m.logger.Warn("Server capability discovery failed", slog.String("server", s.Name), slog.Any("error", err))
+		return
+	default:
+		host.ContainerRuntime = report.ContainerRuntime
+		host.PHPVersions = report.PhpVersions
+		host.DiskQuotas = report.DiskQuotas
+		host.Firewall = report.Firewall
+	}
+
+	caps := append(append([]domain.ServerCapability{}, domain.ExecutorCapabilities(s.Executor)...), host.Capabilities()...)
+	if err := m.repo.RecordHostCapabilities(ctx, s.ID, host, caps); err != nil {
+		m.logger.Error("Failed to record server capabilities", slog.String("server", s.Name), slog.Any("error", err))
+		return
+	}
+	s.Host, s.Capabilities = host, caps
+	m.logger.Info("🔍 Server capabilities discovered", slog.String("server", s.Name), slog.Any("capabilities", caps))
+}
+
+// transition persists the new status first, so a failover leader does not
+// alert again for the same outage.
+func (m *ServerMonitor) transition(ctx context.Context, s *domain.Server, to domain.ServerStatus, lastSeen time.Time) {
+	if err := m.repo.SetServerStatus(ctx, s.ID, to); err != nil {
+		m.logger.Error("Failed to update server status", slog.String("server", s.Name), slog.Any("error", err))
+		return
+	}
+	from := s.Status
+	s.Status = to
+
+	resourceID := s.ID.String()
+	alert := &domain.SystemAlert{
+		Category:   "server",
+		ResourceID: &resourceID,
+		Metadata:   map[string]any{"server": s.Name, "hostname": s.Hostname, "last_seen_at": lastSeen},
+	}
+	switch {
+	case to == domain.ServerOffline:
+		m.logger.Warn("🚨 Server offline", slog.String("server", s.Name), slog.Time("last_seen_at", lastSeen))
+		alert.Severity = "critical"
+		alert.Message = fmt.Sprintf("Server %s (%s) has not answered since %s; alerts for its apps are paused",
+			s.Name, s.Hostname, lastSeen.UTC().Format(time.RFC3339))
+	case from == domain.ServerOffline:
+		m.logger.Info("✅ Server back online", slog.String("server", s.Name))
+		alert.Severity = "info"
+		alert.Message = fmt.Sprintf("Server %s (%s) is back online", s.Name, s.Hostname)
+	default:
+		return // First contact after enrollment is not news
+	}
+	if err := m.auditRepo.CreateAlert(ctx, alert); err != nil {
+		m.logger.Warn("Failed to raise server alert", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,57 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// standbyBeat keeps a standby replica's heartbeat for a singleton fresh:
+// waiting for leadership is healthy, not stuck.
+const standbyBeat = 30 * time.Second
+
+// Singleton runs a worker loop only while this replica is the leader, and
+// starts it afresh on every new term. A nil election runs it unconditionally.
+//
+//	go workers.Supervise(ctx, "app_monitor", crashes, logger,
+//		workers.Singleton(election, "app_monitor", heartbeats, logger, appMonitor.Start))
+func Singleton(election domain.LeaderElection, name string, heartbeats domain.HeartbeatRecorder, logger *slog.Logger, run func(context.Context)) func(context.Context) {
+	if election == nil {
+		return run
+	}
+	return func(ctx context.Context) {
+		for {
+			leading, release, err := awaitLeadership(ctx, election, name, heartbeats)
+			if err != nil {
+				return // Shutdown
+			}
+			logger.Info("👑 Singleton worker running on this replica", slog.String("worker", name))
+			run(leading)
+			release()
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Info("⏸️ Singleton worker stood down", slog.String("worker", name))
+		}
+	}
+}
+
+func awaitLeadership(ctx context.Context, election domain.LeaderElection, name string, heartbeats domain.HeartbeatRecorder) (context.Context, context.CancelFunc, error) {
+	waiting, stop := context.WithCancel(ctx)
+	defer stop()
+	go func() {
+		ticker := time.NewTicker(standbyBeat)
+		defer ticker.Stop()
+		for {
+			beat(heartbeats, name)
+			select {
+			case <-waiting.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return election.Lead(ctx)
+}
@@ -22,9 +22,15 @@ type SSLRenewer struct {
 	DB           domain.DomainRepository
 	SSLService   *services.SSLService
 	AuditService domain.AuditService
+	Webhooks     domain.WebhookEmitter // Optional: cert.renewed to the domain owner
+	Zone         domain.TimezoneSource // Optional: sweep on the install's clock, not UTC
 	Logger       *slog.Logger
 }
 
+// renewalSweepHour is the local hour of the daily sweep: quiet for most
+// installs, and early enough that a failed renewal is seen the same morning.
+const renewalSweepHour = 3
+
 func NewSSLRenewer(
 	cfg *config.Config,
 	db domain.DomainRepository,
@@ -48,22 +54,29 @@ func NewSSLRenewer(
 func (w *SSLRenewer) Start(ctx context.Context) {
 	w.Logger.Info("🛡️ SSL Auto-Renewal Worker started")
 
-	ticker := time.NewTicker(24 * time.Hour)
-	defer ticker.Stop()
-
 	w.checkAndRenew(ctx)
 
+	// Re-armed each day, so a time zone change or DST shift moves the sweep
 	for {
+		timer := time.NewTimer(time.Until(domain.NextLocalHour(time.Now(), w.location(), renewalSweepHour)))
 		select {
 		case <-ctx.Done():
+			timer.Stop()
 			w.Logger.Info("🛑 Shutting down SSL Auto-Renewal Worker gracefully")
 			return
-		case <-ticker.C:
+		case <-timer.C:
 			w.checkAndRenew(ctx)
 		}
 	}
 }
 
+func (w *SSLRenewer) location() *time.Location {
+	if w.Zone != nil {
+		return w.Zone.Location()
+	}
+	return time.UTC
+}
+
 // ==============================================================================
 // 3. Core Worker Logic
 // ==============================================================================
@@ -122,6 +135,12 @@ func (w *SSLRenewer) checkAndRenew(ctx context.Context) {
 			}
 			
 			renewCount++
+			if w.Webhooks != nil {
+				w.Webhooks.Emit(ctx, dom.UserID, domain.WebhookCertRenewed, map[string]any{
+					"domain_id":   dom.ID,
+					"domain_name": dom.DomainName,
+				})
+			}
 		}
 	}
 
@@ -0,0 +1,47 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"kari/api/internal/core/domain"
+)
+
+// supervisorRestartDelay keeps a worker that panics on every tick from
+// spinning; one report per delay is plenty.
+const supervisorRestartDelay = 30 * time.Second
+
+// Supervise runs a worker loop until ctx is done. A panic is reported and
+// the loop restarted instead of taking the whole Brain down with it.
+//
+//	go workers.Supervise(ctx, "app_monitor", crashes, logger, appMonitor.Start)
+func Supervise(ctx context.Context, name string, reporter domain.CrashReporter, logger *slog.Logger, run func(context.Context)) {
+	for {
+		if !runGuarded(ctx, name, reporter, run) {
+			return // Returned normally: shutdown
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(supervisorRestartDelay):
+			logger.Warn("♻️ Restarting worker after panic", slog.String("worker", name))
+		}
+	}
+}
+
+func runGuarded(ctx context.Context, name string, reporter domain.CrashReporter, run func(context.Context)) (panicked bool) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			panicked = true
+			if reporter != nil {
+				reporter.Report(domain.CrashSourceWorker, name, rec, debug.Stack(), nil)
+			}
+		}
+	}()
+
+	run(ctx)
+	return false
+}
@@ -0,0 +1,54 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/services"
+)
+
+// UsageMeter samples per-app resource usage into the monthly meters and,
+// once a month has closed, pushes its report to the billing webhook.
+type UsageMeter struct {
+	service    *services.MeteringService
+	logger     *slog.Logger
+	interval   time.Duration
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewUsageMeter(service *services.MeteringService, logger *slog.Logger, interval time.Duration) *UsageMeter {
+	return &UsageMeter{
+		service:  service,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (w *UsageMeter) WithHeartbeats(rec domain.HeartbeatRecorder) *UsageMeter {
+	rec.Register("usage_meter", w.interval)
+	w.heartbeats = rec
+	return w
+}
+
+// Start begins the non-blocking sampling loop.
+func (w *UsageMeter) Start(ctx context.Context) {
+	w.logger.Info("🧾 Kari Brain: Usage meter started", slog.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("🛑 Kari Brain: Usage meter shutting down...")
+			return
+		case <-ticker.C:
+			w.service.SampleAll(ctx)
+			w.service.ExportDue(ctx)
+			beat(w.heartbeats, "usage_meter")
+		}
+	}
+}
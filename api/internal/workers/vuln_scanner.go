@@ -0,0 +1,55 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/services"
+)
+
+// VulnScanner matches the SBOM of every deployed app against the
+// vulnerability feed, alerting on new critical findings.
+type VulnScanner struct {
+	sboms      *services.SBOMService
+	logger     *slog.Logger
+	interval   time.Duration
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewVulnScanner(sboms *services.SBOMService, logger *slog.Logger, interval time.Duration) *VulnScanner {
+	return &VulnScanner{
+		sboms:    sboms,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (v *VulnScanner) WithHeartbeats(rec domain.HeartbeatRecorder) *VulnScanner {
+	rec.Register("vuln_scanner", v.interval)
+	v.heartbeats = rec
+	return v
+}
+
+// Start begins the non-blocking scan loop.
+func (v *VulnScanner) Start(ctx context.Context) {
+	v.logger.Info("🧾 Kari Brain: Vulnerability scanner started", slog.Duration("interval", v.interval))
+
+	ticker := time.NewTicker(v.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			v.logger.Info("🛑 Kari Brain: Vulnerability scanner shutting down...")
+			return
+		case <-ticker.C:
+			if err := v.sboms.Scan(ctx); err != nil {
+				v.logger.Warn("⚠️ Vulnerability scan failed", slog.Any("error", err))
+			}
+			beat(v.heartbeats, "vuln_scanner")
+		}
+	}
+}
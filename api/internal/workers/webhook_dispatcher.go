@@ -0,0 +1,55 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"kari/api/internal/core/domain"
+	"kari/api/internal/core/services"
+)
+
+// WebhookDispatcher sends queued outgoing webhook deliveries. Emitting wakes
+// it straight away; the ticker picks up retries as their backoff expires.
+type WebhookDispatcher struct {
+	webhooks   *services.WebhookService
+	logger     *slog.Logger
+	interval   time.Duration
+	heartbeats domain.HeartbeatRecorder
+}
+
+func NewWebhookDispatcher(webhooks *services.WebhookService, logger *slog.Logger, interval time.Duration) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhooks: webhooks,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// WithHeartbeats reports each completed tick to /health/ready.
+func (w *WebhookDispatcher) WithHeartbeats(rec domain.HeartbeatRecorder) *WebhookDispatcher {
+	rec.Register("webhook_dispatcher", w.interval)
+	w.heartbeats = rec
+	return w
+}
+
+// Start begins the non-blocking dispatch loop.
+func (w *WebhookDispatcher) Start(ctx context.Context) {
+	w.logger.Info("🪝 Kari Brain: Webhook dispatcher started", slog.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("🛑 Kari Brain: Webhook dispatcher shutting down...")
+			return
+		case <-w.webhooks.Wake():
+			w.webhooks.Dispatch(ctx)
+		case <-ticker.C:
+			w.webhooks.Dispatch(ctx)
+			beat(w.heartbeats, "webhook_dispatcher")
+		}
+	}
+}
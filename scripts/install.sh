@@ -52,6 +52,8 @@ After=postgresql.service kari-agent.service
 [Service]
 ExecStart=/opt/kari/bin/kari-api
 Restart=always
+# Covers SHUTDOWN_DRAIN_SECONDS (default 300), so an in-flight deploy can finish
+TimeoutStopSec=330
 User=kari-api
 Group=kari-api
 EnvironmentFile=-/etc/kari/api.env